@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AlertRuleSpec maps the states of the dashboard tabs to the targets
+// notified when a tab enters one of them.
+type AlertRuleSpec struct {
+	// Dashboards are the TestGrid dashboards of the rule, e.g.
+	// sig-release-master-blocking, every dashboard of the namespace when
+	// empty.
+	// +optional
+	Dashboards []string `json:"dashboards,omitempty"`
+
+	// Tabs are the tab names of the rule, every tab when empty.
+	// +optional
+	Tabs []string `json:"tabs,omitempty"`
+
+	// States are the tab states notified, FAILING and FLAKY when empty.
+	// +optional
+	States []ErrorState `json:"states,omitempty"`
+
+	// Targets are notified of every tab entering one of the states.
+	// +kubebuilder:validation:MinItems=1
+	Targets []AlertTarget `json:"targets"`
+}
+
+// AlertTargetType is the kind of a notification target.
+// +kubebuilder:validation:Enum=slack;webhook;email
+type AlertTargetType string
+
+const (
+	SlackTarget   AlertTargetType = "slack"
+	WebhookTarget AlertTargetType = "webhook"
+	EmailTarget   AlertTargetType = "email"
+)
+
+// AlertTarget is a Slack incoming webhook, a generic HTTP webhook receiving
+// the alerts as JSON, or email recipients.
+type AlertTarget struct {
+	Type AlertTargetType `json:"type"`
+
+	// URL is the URL of the Slack incoming webhook or of the HTTP webhook.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// URLSecretRef reads the URL from a key of a Secret of the namespace of
+	// the rule, instead of URL.
+	// +optional
+	URLSecretRef *corev1.SecretKeySelector `json:"urlSecretRef,omitempty"`
+
+	// To are the email recipients, sent through the SMTP server of the
+	// controller.
+	// +optional
+	To []string `json:"to,omitempty"`
+}
+
+// AlertRuleStatus is the last notification of the rule.
+type AlertRuleStatus struct {
+	// LastNotified is the last time a target was notified.
+	LastNotified metav1.Time `json:"lastNotified,omitempty"`
+
+	// LastError is the error of the last failed notification, cleared by
+	// the next successful one.
+	LastError string `json:"lastError,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// AlertRule is the Schema for the alertrules API.
+type AlertRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AlertRuleSpec   `json:"spec,omitempty"`
+	Status AlertRuleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AlertRuleList contains a list of AlertRule.
+type AlertRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AlertRule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AlertRule{}, &AlertRuleList{})
+}
@@ -28,6 +28,13 @@ const (
 
 var ERROR_STATUSES = []string{FAILING_STATUS, FLAKY_STATUS}
 
+// Classifications of a test by its runs in the grid, see
+// TestResult.Classification.
+const (
+	CONSISTENT_FAILURE = "CONSISTENT_FAILURE"
+	INTERMITTENT_FLAKE = "INTERMITTENT_FLAKE"
+)
+
 // DashboardSpec defines the desired state of Dashboard.
 type DashboardSpec struct {
 	// DashboardTab is the name of the tab be scrapped from this board
@@ -42,6 +49,18 @@ type DashboardSpec struct {
 	// +kubebuilder:default=3
 	// MinFlake is the minimum number of flakes to consider a test group as flaky
 	MinFlakes int `json:"minFlakes,omitempty"`
+
+	// RefreshInterval is the minimum time between two updates of the status,
+	// the dashboard is also fetched again after it. Defaults to 1m.
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+
+	// Schedule is a cron expression in UTC, e.g. "*/15 * * * *", the status
+	// is only updated at the scheduled times instead of every RefreshInterval.
+	// The summary watcher leaves the scheduled dashboards alone, only an
+	// explicit trigger updates them outside of the schedule.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
 }
 
 // DashboardStatus defines the observed state of a testgrid Dashboard.
@@ -51,8 +70,47 @@ type DashboardStatus struct {
 
 	// DashboardSummary represents the list of Tabs summarized from a dashboard set in the spec.DashboardTab
 	DashboardSummary []DashboardSummary `json:"summary,omitempty"`
+
+	// OverallState is the worst state of the tabs, FAILING, FLAKY or PASSING.
+	// +optional
+	OverallState string `json:"overallState,omitempty"`
+
+	// FailingTabs is the number of failing tabs of the dashboard.
+	// +optional
+	FailingTabs int `json:"failingTabs,omitempty"`
+
+	// FetchFailures is the number of consecutive failed fetches of the
+	// dashboard, reset by a successful one. The retries back off with it.
+	FetchFailures int `json:"fetchFailures,omitempty"`
+
+	// Conditions are the Ready, FetchFailed and Stale conditions of the
+	// dashboard.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// Condition types of a Dashboard.
+const (
+	// ReadyCondition is True when the last fetch from testgrid succeeded.
+	ReadyCondition = "Ready"
+	// FetchFailedCondition is True with the error of the last failed fetch.
+	FetchFailedCondition = "FetchFailed"
+	// StaleCondition is True when TestGrid didn't update the failing and
+	// flaky tabs for a day, e.g. their jobs stopped running.
+	StaleCondition = "Stale"
+)
+
+// Reasons of the Dashboard conditions.
+const (
+	FetchSucceededReason     = "FetchSucceeded"
+	SummaryFetchFailedReason = "SummaryFetchFailed"
+	TabFetchFailedReason     = "TabFetchFailed"
+	TabsUpdatedReason        = "TabsUpdated"
+	TabsNotUpdatedReason     = "TabsNotUpdated"
+)
+
 // DashboardSummary represents summary information from a TestGrid dashboard
 type DashboardSummary struct {
 	LastRunTime    int64         `json:"last_run_timestamp,omitempty"`
@@ -73,6 +131,9 @@ type DashboardTab struct {
 	StateIcon string       `json:"icon"`
 	TabState  string       `json:"state"`
 	TestRuns  []TestResult `json:"tab_tests,omitempty"`
+
+	// LatestBuildURL is the Prow URL of the most recent run of the tab job.
+	LatestBuildURL string `json:"latest_build_url,omitempty"`
 }
 
 // TestResult contains details about an individual test run
@@ -83,10 +144,105 @@ type TestResult struct {
 	TriageURL       string `json:"triage_url"`
 	ProwJobURL      string `json:"prow_url"`
 	ErrorMessage    string `json:"error_message"`
+
+	// LastPassTimestamp is the last green run before the current failure streak.
+	LastPassTimestamp int64 `json:"last_pass_timestamp,omitempty"`
+
+	// FirstFailTimestamp is the first red run of the current failure streak.
+	FirstFailTimestamp int64 `json:"first_fail_timestamp,omitempty"`
+
+	// LastFailureTimestamp is the most recent run with a failure.
+	LastFailureTimestamp int64 `json:"last_failure_timestamp,omitempty"`
+
+	// FailureCount is the number of failed runs in the grid.
+	FailureCount int `json:"failure_count,omitempty"`
+
+	// RunCount is the number of runs in the grid.
+	RunCount int `json:"run_count,omitempty"`
+
+	// ConsecutiveFailures is the length of the current failure streak.
+	ConsecutiveFailures int `json:"consecutive_failures,omitempty"`
+
+	// Classification is CONSISTENT_FAILURE for a test red in its latest runs,
+	// or INTERMITTENT_FLAKE for a test with red runs between green ones,
+	// whatever the state of its tab.
+	Classification string `json:"classification,omitempty"`
+
+	// FailingSince is the commit of the first red run of a consistently
+	// failing test, empty when the streak is older than the grid or the tab
+	// has no commit column.
+	FailingSince string `json:"failing_since,omitempty"`
+
+	// Fingerprint identifies the failure across branches, see fingerprint.New.
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// MissingRuns is the number of the latest runs without a result for the
+	// test, e.g. after the test was skipped or removed.
+	MissingRuns int `json:"missing_runs,omitempty"`
+
+	// Skipped is true when the latest job config skips or doesn't focus the
+	// test, so its missing results are not a recovery.
+	Skipped bool `json:"skipped,omitempty"`
+
+	// RecoveredTimestamp is the first green run after the latest failure
+	// streak, zero while the test is failing.
+	RecoveredTimestamp int64 `json:"recovered_timestamp,omitempty"`
+
+	// RecoveredFromTimestamp is the first red run of the failure streak ended
+	// at RecoveredTimestamp.
+	RecoveredFromTimestamp int64 `json:"recovered_from_timestamp,omitempty"`
+
+	// Sig is the SIG owning the test, e.g. node, see sig.Resolver.
+	Sig string `json:"sig,omitempty"`
+
+	// FailedRunURLs are the Prow URLs of the latest red runs, the most recent
+	// first, used to correlate the failures with the run infrastructure.
+	FailedRunURLs []string `json:"failed_run_urls,omitempty"`
+
+	// PassedRunURLs are the Prow URLs of the latest green runs, the baseline
+	// of the infrastructure correlation.
+	PassedRunURLs []string `json:"passed_run_urls,omitempty"`
+
+	// LastPassRunURL and FirstFailRunURL are the Prow URLs of the runs of
+	// LastPassTimestamp and FirstFailTimestamp, whose revisions bound the
+	// commits to bisect.
+	LastPassRunURL  string `json:"last_pass_run_url,omitempty"`
+	FirstFailRunURL string `json:"first_fail_run_url,omitempty"`
+
+	// LastPassCommit and FirstFailCommit are the commits of the same runs
+	// read from the Commit column of the tab, empty when the tab has none.
+	LastPassCommit  string `json:"last_pass_commit,omitempty"`
+	FirstFailCommit string `json:"first_fail_commit,omitempty"`
+
+	// FlakeScore is the percentage of red runs of the test, flaky runs
+	// included, across the boards recorded in the history store over the
+	// score window, or over the grid without a history.
+	FlakeScore int `json:"flake_score,omitempty"`
+
+	// ScoreRuns is the number of runs the flake score is computed on.
+	ScoreRuns int `json:"score_runs,omitempty"`
+
+	// Runs are the results of the test in every run of the grid, kept in
+	// memory for the history store and left out of the Dashboard status.
+	Runs []RunResult `json:"-"`
+}
+
+// RunResult is the result of a test in a run of the grid.
+type RunResult struct {
+	// Timestamp is the start of the run in milliseconds.
+	Timestamp int64 `json:"timestamp"`
+
+	// Failed is true for a red or flaky run.
+	Failed bool `json:"failed"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Dashboard",type=string,JSONPath=`.spec.dashboardTab`
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.overallState`
+// +kubebuilder:printcolumn:name="Failing",type=integer,JSONPath=`.status.failingTabs`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Last Update",type=date,JSONPath=`.status.lastFetched`
 
 // Dashboard is the Schema for the dashboards API.
 type Dashboard struct {
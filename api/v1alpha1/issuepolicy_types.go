@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IssuePolicySpec declares the tests the controller opens GitHub issues for.
+type IssuePolicySpec struct {
+	// Boards are the TestGrid dashboards watched by the policy, e.g.
+	// sig-release-master-blocking.
+	// +kubebuilder:validation:MinItems=1
+	Boards []string `json:"boards"`
+
+	// States are the tab states filing issues, FAILING and FLAKY when empty.
+	// +optional
+	States []ErrorState `json:"states,omitempty"`
+
+	// MinConsecutiveFailures is the failure streak a test of a failing tab
+	// needs before its issue is opened, 0 opens it at the first failure. A
+	// pointer so an explicit 0 is not replaced by the default.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=2
+	// +optional
+	MinConsecutiveFailures *int32 `json:"minConsecutiveFailures,omitempty"`
+
+	// Labels are added to the labels of the issue template, e.g.
+	// release-blocker.
+	// +optional
+	Labels []string `json:"labels,omitempty"`
+
+	// AssigneeSIG is the SIG labeled on the issues, e.g. node for sig/node,
+	// the SIG owning the test when empty.
+	// +optional
+	AssigneeSIG string `json:"assigneeSIG,omitempty"`
+
+	// Repository is the owner/repo the issues are opened in, the repository
+	// of the test when empty, e.g. kubernetes/test-infra for the job failures.
+	// +optional
+	Repository string `json:"repository,omitempty"`
+
+	// RefreshInterval is the time between two evaluations of the policy.
+	// Defaults to 15m.
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+}
+
+// ErrorState is a failing or flaky tab state, see ERROR_STATUSES.
+// +kubebuilder:validation:Enum=FAILING;FLAKY
+type ErrorState string
+
+// IssuePolicyStatus is the issues opened or updated by the policy.
+type IssuePolicyStatus struct {
+	// LastEvaluated is the last time the boards were checked.
+	LastEvaluated metav1.Time `json:"lastEvaluated,omitempty"`
+
+	// Issues are the issues of the tests matched by the policy, one per test
+	// and board.
+	Issues []PolicyIssue `json:"issues,omitempty"`
+}
+
+// PolicyIssue is the GitHub issue of a test matched by a policy.
+type PolicyIssue struct {
+	TestName  string `json:"testName"`
+	BoardHash string `json:"boardHash"`
+	Number    int    `json:"number"`
+	URL       string `json:"url,omitempty"`
+
+	// State is the tab state the issue was last updated for.
+	State string `json:"state"`
+
+	// UpdatedAt is the time the issue was opened or last commented.
+	UpdatedAt metav1.Time `json:"updatedAt,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// IssuePolicy is the Schema for the issuepolicies API.
+type IssuePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IssuePolicySpec   `json:"spec,omitempty"`
+	Status IssuePolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// IssuePolicyList contains a list of IssuePolicy.
+type IssuePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IssuePolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IssuePolicy{}, &IssuePolicyList{})
+}
@@ -3,15 +3,25 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"os"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/signalhound/api/v1alpha1"
-	"sigs.k8s.io/signalhound/internal/testgrid"
+	"sigs.k8s.io/signalhound/internal/config"
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/links"
+	"sigs.k8s.io/signalhound/internal/monitoring"
+	"sigs.k8s.io/signalhound/internal/release"
+	"sigs.k8s.io/signalhound/internal/store"
 	"sigs.k8s.io/signalhound/internal/tui"
+	"sigs.k8s.io/signalhound/pkg/pipeline"
 )
 
 // abstractCmd represents the abstract command
@@ -24,11 +34,21 @@ var abstractCmd = &cobra.Command{
 var defaultDashboards = []string{"sig-release-master-blocking", "sig-release-master-informing"}
 
 var (
-	tg                   = testgrid.NewTestGrid(testgrid.URL)
+	testgridURL          = pipeline.TestGridURL
 	minFailure, minFlake int
 	refreshInterval      int
-	token                string
 	dashboards           []string
+	releaseBranch        string
+	releaseSchedule      string
+	releasePhase         *release.Phase
+	reopenWindow         time.Duration
+	createReleaseOption  bool
+	repositoryIssues     bool
+	plain                bool
+	slackChannel         string
+	checkLinks           bool
+	includeSynthetic     bool
+	crashLog             string
 )
 
 func init() {
@@ -41,43 +61,142 @@ func init() {
 	abstractCmd.PersistentFlags().IntVarP(&refreshInterval, "refresh-interval", "r", 0,
 		"refresh interval in seconds (0 to disable auto-refresh)")
 	abstractCmd.PersistentFlags().StringSliceVarP(&dashboards, "dashboards", "d", defaultDashboards,
-		"comma-separated list of TestGrid dashboards to monitor (e.g. sig-release-1.35-blocking,sig-release-1.35-informing)")
-
-	token = os.Getenv("SIGNALHOUND_GITHUB_TOKEN")
-	if token == "" {
-		token = os.Getenv("GITHUB_TOKEN")
-	}
+		"comma-separated list of TestGrid dashboards or dashboard groups to monitor (e.g. sig-release-1.35-blocking,sig-release-1.35-informing or sig-release)")
+	abstractCmd.PersistentFlags().StringVar(&releaseBranch, "release", "",
+		"release branch to monitor, e.g. 1.32 expands to the sig-release-1.32-blocking and sig-release-1.32-informing dashboards unless --dashboards is set.")
+	abstractCmd.PersistentFlags().StringVar(&releaseSchedule, "release-schedule", release.ScheduleURL,
+		"URL or path of the SIG Release schedule.yaml used for code freeze awareness, empty to disable.")
+	abstractCmd.PersistentFlags().DurationVar(&reopenWindow, "reopen-window", 14*24*time.Hour,
+		"reopen the issue of a regressed test closed within this window instead of drafting a new one, 0 to disable.")
+	abstractCmd.PersistentFlags().BoolVar(&createReleaseOption, "create-release-option", false,
+		"create the option of the release in progress in the K8s Release field of the board when it is missing.")
+	abstractCmd.PersistentFlags().BoolVar(&repositoryIssues, "repository-issues", false,
+		"start with Ctrl-B creating the issues in the repository of the test instead of drafts, toggled with Ctrl-T.")
+	abstractCmd.PersistentFlags().BoolVar(&plain, "plain", false,
+		"print labeled text blocks and numbered menus instead of the grid UI, for screen readers, constrained terminals and CI shells, alias --no-tui.")
+	// --no-tui is an alias of --plain, left out of the help.
+	abstractCmd.PersistentFlags().BoolVar(&plain, "no-tui", false, "alias of --plain.")
+	_ = abstractCmd.PersistentFlags().MarkHidden("no-tui")
+	abstractCmd.PersistentFlags().StringVar(&slackChannel, "slack-channel", "",
+		"Slack channel Ctrl-B posts the messages to with the slack.botToken, e.g. #release-ci-signal, overrides slack.channel of the configuration file.")
+	abstractCmd.PersistentFlags().BoolVar(&checkLinks, "check-links", true,
+		"check the TestGrid, Prow and Triage links of the issues and Slack messages, replacing the dead ones by working alternates.")
+	abstractCmd.PersistentFlags().BoolVar(&includeSynthetic, "include-synthetic-rows", false,
+		"keep the Overall and aggregate rows TestGrid adds to the tests of a job, e.g. ci-kubernetes-e2e.Overall.")
+	abstractCmd.PersistentFlags().StringVar(&crashLog, "crash-log", tui.DefaultCrashLog(),
+		"file the stack trace of a crash of the UI is appended to, empty prints it on the standard error.")
 }
 
-// FetchTabSummary fetches all dashboard tabs from TestGrid.
+// FetchTabSummary fetches all dashboard tabs from TestGrid and records the
+// test results in the history.
 func FetchTabSummary() ([]*v1alpha1.DashboardTab, error) {
-	var dashboardTabs []*v1alpha1.DashboardTab
-	for _, dashboard := range dashboards {
-		dashSummaries, err := tg.FetchTabSummary(dashboard, v1alpha1.ERROR_STATUSES)
+	return fetchTabSummary(os.Stdout)
+}
+
+// fetchTabSummary is FetchTabSummary printing the errors not stopping the
+// fetch to w.
+func fetchTabSummary(w io.Writer) ([]*v1alpha1.DashboardTab, error) {
+	fetcher := pipeline.NewFetcher(testgridURL, releasePhase.StrictThreshold(minFailure), releasePhase.StrictThreshold(minFlake))
+	fetcher.IncludeSynthetic = includeSynthetic
+	if sigResolver != nil {
+		fetcher.SIGs = sigResolver
+	}
+	fetcher.OnTabError = func(tabName string, err error) {
+		fmt.Fprintln(w, fmt.Errorf("error fetching table : %s", err)) // nolint
+	}
+	start := time.Now()
+	tabs, err := fetcher.Fetch(dashboards)
+	if err != nil {
+		return nil, withExitCode(ExitFetchError, err)
+	}
+	tui.SetFetchLatency(time.Since(start))
+	if history := historyStore(); history != nil {
+		now := time.Now()
+		if err := history.Record(tabs, now); err != nil {
+			// the history only misses this fetch, the boards are still shown.
+			fmt.Fprintln(w, fmt.Errorf("error recording the history: %s", err)) // nolint
+		}
+		// the tests keep the flake score of their grid on errors.
+		if err := history.Score(tabs, now.Add(-store.ScoreWindow)); err != nil {
+			fmt.Fprintln(w, fmt.Errorf("error scoring the tests: %s", err)) // nolint
+		}
+		transitions, err := history.RecordBoards(fetcher.Dashboards, tabs, now)
 		if err != nil {
-			return nil, err
+			fmt.Fprintln(w, fmt.Errorf("error recording the board states: %s", err)) // nolint
 		}
-		for _, dashSummary := range dashSummaries {
-			dashTab, err := tg.FetchTabTests(&dashSummary, minFailure, minFlake)
-			if err != nil {
-				fmt.Println(fmt.Errorf("error fetching table : %s", err))
-				continue
-			}
-			if len(dashTab.TestRuns) > 0 {
-				dashboardTabs = append(dashboardTabs, dashTab)
+		for _, transition := range transitions {
+			if transition.Degraded() {
+				fmt.Fprintf(w, "board alert: %s\n", transition) // nolint
 			}
 		}
+		compactHistory(w, history, now)
 	}
-	return dashboardTabs, nil
+	return tabs, nil
 }
 
 // RunAbstract starts the main command to scrape TestGrid.
 func RunAbstract(cmd *cobra.Command, args []string) error {
+	if err := expandRelease(cmd); err != nil {
+		return err
+	}
+	releasePhase = fetchReleasePhase()
+	tui.SetReleasePhase(releasePhase)
+	if releasePhase != nil {
+		github.Release = releasePhase.Release
+	}
+	github.CreateReleaseOption = createReleaseOption
+	tui.SetReopenWindow(reopenWindow)
+	tui.SetRepositoryIssues(repositoryIssues)
+	tui.SetReadOnly(cfg.ReadOnly)
+	tui.SetCrashLog(crashLog)
+	tui.SetSlackMentions(&cfg.Slack.SlackMentions)
+	if slackChannel == "" {
+		slackChannel = cfg.Slack.Channel
+	}
+	tui.SetSlackChannel(slackToken(), slackChannel)
+	tui.SetSlackRouting(cfg.Slack.SIGChannels, cfg.Slack.RateLimits)
+	tui.SetClipboardTool(cfg.Clipboard)
+	tui.SetMCPURL(cfg.MCPURL)
+	if checkLinks {
+		tui.SetLinkChecker(links.NewChecker())
+	}
+	if cfg.Telemetry.Actions {
+		if err := serveActionMetrics(cfg.Telemetry.MetricsAddress); err != nil {
+			return err
+		}
+		tui.SetActionTelemetry(true)
+	}
+	store, err := stateStore()
+	if err != nil {
+		return err
+	}
+	tui.SetStateStore(store)
+	tui.SetHistoryStore(historyStore())
+
 	dashboardTabs, err := FetchTabSummary()
 	if err != nil {
 		return err
 	}
 
+	if plain {
+		// the plain mode refreshes on demand, there is no screen to update in place.
+		refresh := func() ([]*v1alpha1.DashboardTab, error) {
+			tabs, err := FetchTabSummary()
+			if err == nil {
+				dashboardTabs = tabs
+			}
+			return tabs, err
+		}
+		// the actions run in the foreground, only the read of the next choice is
+		// stopped on SIGINT or SIGTERM.
+		stop := context.AfterFunc(cmd.Context(), func() { _ = os.Stdin.Close() })
+		defer stop()
+		if err := tui.RenderPlain(os.Stdin, os.Stdout, dashboardTabs, githubToken(), refresh); err != nil {
+			return err
+		}
+		return failingTestsError(dashboardTabs)
+	}
+
 	var refreshFunc func() ([]*v1alpha1.DashboardTab, error)
 	if refreshInterval > 0 {
 		refreshFunc = func() ([]*v1alpha1.DashboardTab, error) {
@@ -85,5 +204,67 @@ func RunAbstract(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	return tui.RenderVisual(dashboardTabs, token, time.Duration(refreshInterval)*time.Second, refreshFunc)
+	return tui.RenderVisual(cmd.Context(), dashboardTabs, githubToken(), time.Duration(refreshInterval)*time.Second, refreshFunc)
+}
+
+// expandRelease replaces the dashboards by the ones of the --release branch
+// when --dashboards is not set.
+func expandRelease(cmd *cobra.Command) error {
+	if releaseBranch == "" || cmd.Flags().Changed("dashboards") {
+		return nil
+	}
+	releaseDashboards, err := release.Dashboards(releaseBranch)
+	if err != nil {
+		return err
+	}
+	dashboards = releaseDashboards
+	return nil
+}
+
+// serveActionMetrics serves the counts of the UI actions on the /metrics
+// endpoint of the address in background, DefaultMetricsAddress when empty.
+func serveActionMetrics(address string) error {
+	if address == "" {
+		address = config.DefaultMetricsAddress
+	}
+	if err := monitoring.Setup(); err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("error serving the telemetry metrics: %v", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", monitoring.Handler())
+	go http.Serve(listener, mux) // nolint
+	return nil
+}
+
+// failingTestsError returns an error ending the plain mode with
+// ExitFailingTests when the latest fetched boards have failing tests.
+func failingTestsError(tabs []*v1alpha1.DashboardTab) error {
+	var failing int
+	for _, tab := range tabs {
+		if tab.TabState == v1alpha1.FAILING_STATUS {
+			failing += len(tab.TestRuns)
+		}
+	}
+	if failing == 0 {
+		return nil
+	}
+	return withExitCode(ExitFailingTests, fmt.Errorf("%d failing tests found", failing))
+}
+
+// fetchReleasePhase returns the current release cycle phase, or nil when the
+// schedule is disabled or cannot be fetched.
+func fetchReleasePhase() *release.Phase {
+	if releaseSchedule == "" {
+		return nil
+	}
+	schedule, err := release.FetchSchedule(releaseSchedule)
+	if err != nil {
+		fmt.Println(fmt.Errorf("error fetching release schedule, freeze awareness disabled: %s", err))
+		return nil
+	}
+	return schedule.PhaseAt(time.Now())
 }
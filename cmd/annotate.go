@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/signalhound/internal/state"
+)
+
+// annotateCmd represents the annotate command
+var annotateCmd = &cobra.Command{
+	Use:   "annotate TEST [NOTE...]",
+	Short: "Attach a note to a test, or list the notes of a test without NOTE",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  RunAnnotate,
+}
+
+var clearAnnotations bool
+
+func init() {
+	rootCmd.AddCommand(annotateCmd)
+
+	annotateCmd.PersistentFlags().BoolVar(&clearAnnotations, "clear", false,
+		"remove every note of the test.")
+}
+
+// RunAnnotate adds, lists or clears the notes of a test in the state store.
+func RunAnnotate(cmd *cobra.Command, args []string) error {
+	testName, note := args[0], strings.TrimSpace(strings.Join(args[1:], " "))
+	store, err := stateStore()
+	if err != nil {
+		return err
+	}
+	current, err := store.Load()
+	if clearAnnotations || note != "" {
+		current, err = state.Update(store, func(current *state.State) {
+			if clearAnnotations {
+				current.ClearAnnotations(testName)
+			}
+			if note != "" {
+				author := os.Getenv("USER")
+				if u, err := user.Current(); err == nil {
+					author = u.Username
+				}
+				current.Annotate(testName, note, author)
+			}
+		})
+	}
+	if err != nil {
+		return err
+	}
+	for _, note := range current.Notes(testName) {
+		fmt.Printf("* %s\n", note)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/signalhound/pkg/pipeline"
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "List the failing and flaky tests of the recorded history that never got an issue",
+	RunE:  RunAudit,
+}
+
+var auditSince = days(30 * 24 * time.Hour)
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+
+	auditCmd.PersistentFlags().Var(&auditSince, "since", "how far back the audit goes, in days like 30d or a duration like 36h.")
+}
+
+// RunAudit looks up the issue of every test failing or flaky on a board in
+// the history recorded by the abstract command, and prints the misses in
+// Markdown. The lookup errors go to the standard error.
+func RunAudit(cmd *cobra.Command, args []string) error {
+	history := historyStore()
+	if history == nil {
+		return errors.New("the history is disabled, set --history")
+	}
+	token := githubToken()
+	if token == "" {
+		return withExitCode(ExitAuthError, errors.New("no GitHub token, export SIGNALHOUND_GITHUB_TOKEN or set githubToken in the configuration file"))
+	}
+	now := time.Now()
+	since := now.Add(-time.Duration(auditSince))
+	failures, err := history.Failures(since)
+	if err != nil {
+		return err
+	}
+
+	store, err := stateStore()
+	if err != nil {
+		return err
+	}
+	auditor := pipeline.NewProjectAuditor(cmd.Context(), token)
+	if auditor.State, err = store.Load(); err != nil {
+		return err
+	}
+	var misses strings.Builder
+	missed, audited := 0, 0
+	checked := map[string]bool{}
+	for _, failure := range failures {
+		// a test failing on several boards needs a single issue.
+		if checked[failure.TestName] {
+			continue
+		}
+		filed, err := auditor.Filed(failure.TestName, failure.BoardHash, since)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, fmt.Errorf("error looking up the issue of %s: %v", failure.TestName, err)) // nolint
+			continue
+		}
+		checked[failure.TestName] = true
+		audited++
+		if filed != nil {
+			continue
+		}
+		missed++
+		fmt.Fprintf(&misses, "* `%s` %s on %s, %s to %s\n", failure.TestName, strings.ToLower(failure.TabState), failure.BoardHash,
+			failure.FirstSeen.Format("2006-01-02 15:04"), failure.LastSeen.Format("2006-01-02 15:04"))
+	}
+
+	fmt.Printf("# Issue audit, %s to %s\n\n", since.Format(time.DateOnly), now.Format(time.DateOnly))
+	fmt.Printf("%d of %d failing or flaky tests got no issue.\n", missed, audited)
+	if missed > 0 {
+		fmt.Printf("\n## Misses\n\n%s", misses.String())
+	}
+	return nil
+}
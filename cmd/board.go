@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/pkg/pipeline"
+)
+
+// boardCmd groups the commands acting on the CI Signal project board
+var boardCmd = &cobra.Command{
+	Use:   "board",
+	Short: "Manage the CI Signal GitHub project board",
+}
+
+// boardBootstrapCmd represents the board bootstrap command
+var boardBootstrapCmd = &cobra.Command{
+	Use:   "bootstrap",
+	Short: "Create or rename the board fields and options of a new release cycle",
+	RunE:  RunBoardBootstrap,
+}
+
+// boardLintCmd represents the board lint command
+var boardLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check the board issues against the issue templates and list the ones to fix",
+	RunE:  RunBoardLint,
+}
+
+var (
+	bootstrapRelease string
+	lintStale        = days(7 * 24 * time.Hour)
+)
+
+func init() {
+	rootCmd.AddCommand(boardCmd)
+	boardCmd.AddCommand(boardBootstrapCmd)
+	boardCmd.AddCommand(boardLintCmd)
+
+	boardBootstrapCmd.PersistentFlags().StringVar(&bootstrapRelease, "release", "",
+		"release cycle to set the board up for, e.g. 1.35.")
+	_ = boardBootstrapCmd.MarkPersistentFlagRequired("release")
+
+	boardLintCmd.PersistentFlags().Var(&lintStale, "stale", "flag the issues without comment for longer, in days like 7d.")
+}
+
+// RunBoardBootstrap sets up the K8s Release, View, Status and Testgrid Board
+// fields of the project board for the release cycle and prints the changes,
+// only printed with the global --dry-run.
+func RunBoardBootstrap(cmd *cobra.Command, args []string) error {
+	if !dryRun {
+		if err := checkWritable(); err != nil {
+			return err
+		}
+	}
+	token := githubToken()
+	if token == "" {
+		return withExitCode(ExitAuthError, errors.New("no GitHub token, export SIGNALHOUND_GITHUB_TOKEN or set githubToken in the configuration file"))
+	}
+	changes, err := github.NewProjectManager(cmd.Context(), token).BootstrapBoard(bootstrapRelease, dryRun)
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(changes) == 0:
+		fmt.Printf("the board is already set up for %s\n", bootstrapRelease)
+	case dryRun:
+		fmt.Printf("\n%d changes not applied, run again without --dry-run\n", len(changes))
+	}
+	return nil
+}
+
+// RunBoardLint checks the draft and open issues of the project board for the
+// links, SIG and error excerpt of the issue templates, their last comment
+// and their labels, and prints the fix-list in Markdown, the worst first.
+func RunBoardLint(cmd *cobra.Command, args []string) error {
+	token := githubToken()
+	if token == "" {
+		return withExitCode(ExitAuthError, errors.New("no GitHub token, export SIGNALHOUND_GITHUB_TOKEN or set githubToken in the configuration file"))
+	}
+	issues, err := github.NewProjectManager(cmd.Context(), token).ListBoardIssues()
+	if err != nil {
+		return err
+	}
+	fmt.Print(pipeline.LintBoard(issues, time.Now(), time.Duration(lintStale)).Markdown())
+	return nil
+}
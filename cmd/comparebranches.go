@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/signalhound/internal/fingerprint"
+	"sigs.k8s.io/signalhound/internal/release"
+	"sigs.k8s.io/signalhound/pkg/pipeline"
+)
+
+// compareBranchesCmd represents the compare-branches command
+var compareBranchesCmd = &cobra.Command{
+	Use:   "compare-branches",
+	Short: "Diff the failing and flaky tests of the master boards with the boards of a release branch",
+	RunE:  RunCompareBranches,
+}
+
+var compareBoard string
+
+func init() {
+	rootCmd.AddCommand(compareBranchesCmd)
+
+	compareBranchesCmd.PersistentFlags().StringVar(&releaseBranch, "release", "",
+		"release branch to compare with master, e.g. 1.35.")
+	compareBranchesCmd.PersistentFlags().StringVar(&compareBoard, "board", "blocking",
+		"boards to compare, blocking or informing.")
+	compareBranchesCmd.PersistentFlags().IntVarP(&minFailure, "min-failure", "f", 0,
+		"minimum threshold for test failures, to disable use 0. Defaults to 0.")
+	compareBranchesCmd.PersistentFlags().IntVarP(&minFlake, "min-flake", "m", 0,
+		"minimum threshold for test flakeness, to disable use 0. Defaults to 0.")
+	_ = compareBranchesCmd.MarkPersistentFlagRequired("release")
+}
+
+// RunCompareBranches fetches the master and release branch boards and prints
+// the tests red only on the branch, only on master and on both in Markdown.
+func RunCompareBranches(cmd *cobra.Command, args []string) error {
+	if compareBoard != "blocking" && compareBoard != "informing" {
+		return fmt.Errorf("unknown board %q, use blocking or informing", compareBoard)
+	}
+	releaseDashboards, err := release.Dashboards(releaseBranch)
+	if err != nil {
+		return err
+	}
+	branch := fingerprint.Branch(releaseDashboards[0])
+	if branch == fingerprint.Master {
+		return errors.New("the release branch to compare with master must be a minor version, e.g. 1.35")
+	}
+	dashboards = []string{
+		fmt.Sprintf("sig-release-%s-%s", fingerprint.Master, compareBoard),
+		fmt.Sprintf("sig-release-%s-%s", branch, compareBoard),
+	}
+	tabs, err := fetchTabSummary(os.Stderr)
+	if err != nil {
+		return err
+	}
+	fmt.Print(pipeline.CompareBranches(branch, tabs).Markdown())
+	return nil
+}
@@ -4,9 +4,11 @@ package cmd
 
 import (
 	"crypto/tls"
+	"errors"
 	"flag"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/cobra"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -24,6 +26,7 @@ import (
 
 	testgridv1alpha1 "sigs.k8s.io/signalhound/api/v1alpha1"
 	"sigs.k8s.io/signalhound/internal/controller"
+	"sigs.k8s.io/signalhound/internal/github"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -37,6 +40,10 @@ var (
 	probeAddr                                        string
 	secureMetrics                                    bool
 	enableHTTP2                                      bool
+	resolveIssues                                    bool
+	issuePolicies                                    bool
+	alertRules                                       bool
+	watchInterval                                    time.Duration
 )
 
 // controllerCmd represents the controller command
@@ -77,6 +84,18 @@ func init() {
 		"The name of the metrics server key file.")
 	controllerCmd.PersistentFlags().BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	controllerCmd.PersistentFlags().BoolVar(&resolveIssues, "resolve-issues", false,
+		"Comment on or close the GitHub issues of the tests green again for --green-runs runs, see the resolve command.")
+	addResolveFlags(controllerCmd)
+	controllerCmd.PersistentFlags().BoolVar(&issuePolicies, "issue-policies", false,
+		"Open the GitHub issues of the tests matched by the IssuePolicy resources.")
+	controllerCmd.PersistentFlags().DurationVar(&reopenWindow, "reopen-window", 14*24*time.Hour,
+		"reopen the issue of a test matched by an IssuePolicy closed within this window instead of opening a new one, 0 disables it.")
+	controllerCmd.PersistentFlags().BoolVar(&alertRules, "alert-rules", false,
+		"Notify the targets of the AlertRule resources when a tab enters one of their states.")
+	addSMTPFlags(controllerCmd)
+	controllerCmd.PersistentFlags().DurationVar(&watchInterval, "watch-interval", 0,
+		"Poll the summary of the dashboards at this interval and refresh the ones with new runs right away, 0 disables it.")
 }
 
 // nolint:gocyclo
@@ -171,13 +190,38 @@ func RunController(cmd *cobra.Command, args []string) error {
 		os.Exit(1)
 	}
 
-	if err = (&controller.DashboardReconciler{
+	reconciler := &controller.DashboardReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	}
+	if alertRules {
+		smtpServer.Password = os.Getenv("SIGNALHOUND_SMTP_PASSWORD")
+		reconciler.Alerts = &controller.AlertNotifier{
+			Client: mgr.GetClient(), Secrets: mgr.GetAPIReader(), SMTP: &smtpServer,
+		}
+	}
+	if resolveIssues {
+		if reconciler.Resolver, err = newResolver(cmd); err != nil {
+			setupLog.Error(err, "unable to create the issue resolver")
+			os.Exit(1)
+		}
+	}
+	if err = reconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Dashboard")
 		os.Exit(1)
 	}
+	if watchInterval > 0 {
+		if err := mgr.Add(&controller.SummaryWatcher{Client: mgr.GetClient(), Interval: watchInterval}); err != nil {
+			setupLog.Error(err, "unable to add the summary watcher to manager")
+			os.Exit(1)
+		}
+	}
+	if issuePolicies {
+		if err := setupIssuePolicies(cmd, mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "IssuePolicy")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -206,5 +250,25 @@ func RunController(cmd *cobra.Command, args []string) error {
 	}
 
 	setupLog.Info("starting manager")
-	return mgr.Start(ctrl.SetupSignalHandler())
+	// the root context is done on SIGINT or SIGTERM, the manager then stops
+	// the reconcilers and the summary watcher gracefully.
+	return mgr.Start(cmd.Context())
+}
+
+// setupIssuePolicies adds the IssuePolicy reconciler to the manager, it needs
+// a GitHub token and a writable configuration.
+func setupIssuePolicies(cmd *cobra.Command, mgr ctrl.Manager) error {
+	if err := checkWritable(); err != nil {
+		return err
+	}
+	token := githubToken()
+	if token == "" {
+		return errors.New("no GitHub token, export SIGNALHOUND_GITHUB_TOKEN or set githubToken in the configuration file")
+	}
+	return (&controller.IssuePolicyReconciler{
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		Manager:      github.NewProjectManager(cmd.Context(), token),
+		ReopenWindow: reopenWindow,
+	}).SetupWithManager(mgr)
 }
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/signalhound/internal/fingerprint"
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/release"
+	"sigs.k8s.io/signalhound/internal/state"
+	"sigs.k8s.io/signalhound/internal/store"
+)
+
+// digestCmd represents the digest command
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Comment the new failures, resolved tests and board states of each release on its umbrella tracking issue",
+	RunE:  RunDigest,
+}
+
+var (
+	digestReleases []string
+	digestSince    = days(24 * time.Hour)
+	digestInterval time.Duration
+	digestPost     bool
+)
+
+func init() {
+	rootCmd.AddCommand(digestCmd)
+
+	digestCmd.PersistentFlags().StringSliceVar(&digestReleases, "release", nil,
+		"comma-separated list of releases to digest, e.g. 1.35,master, every release of umbrellaIssues when empty.")
+	digestCmd.PersistentFlags().Var(&digestSince, "since", "period of the digest, in days like 1d.")
+	digestCmd.PersistentFlags().DurationVar(&digestInterval, "interval", 0,
+		"digest again at this interval, e.g. 24h for a nightly digest, 0 digests once.")
+	digestCmd.PersistentFlags().BoolVar(&digestPost, "post", false,
+		"comment the digests on the umbrella issues of umbrellaIssues in the configuration file instead of printing them.")
+}
+
+// RunDigest digests the history recorded by the abstract command for each
+// release, once or at every interval until interrupted.
+func RunDigest(cmd *cobra.Command, args []string) error {
+	history := historyStore()
+	if history == nil {
+		return errors.New("the history is disabled, set --history")
+	}
+	if time.Duration(digestSince) < 24*time.Hour {
+		return fmt.Errorf("the digest needs at least a day, got %s", digestSince.String())
+	}
+	releases := digestReleases
+	if len(releases) == 0 {
+		for version := range cfg.UmbrellaIssues {
+			releases = append(releases, version)
+		}
+		sort.Strings(releases)
+	}
+	if len(releases) == 0 {
+		return errors.New("no release to digest, set --release or umbrellaIssues")
+	}
+	var manager github.ProjectManagerInterface
+	if digestPost {
+		if err := checkWritable(); err != nil {
+			return err
+		}
+		token := githubToken()
+		if token == "" {
+			return errors.New("the GitHub token is required to post the digests, set SIGNALHOUND_GITHUB_TOKEN")
+		}
+		manager = github.NewProjectManager(cmd.Context(), token)
+	}
+
+	if err := postDigests(history, manager, releases); err != nil || digestInterval <= 0 {
+		return err
+	}
+
+	// the root context is done on SIGINT or SIGTERM.
+	ctx := cmd.Context()
+	ticker := time.NewTicker(digestInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			// a failed comment is retried at the next interval.
+			if err := postDigests(history, manager, releases); err != nil {
+				fmt.Println(fmt.Errorf("error posting digests: %s", err))
+			}
+		}
+	}
+}
+
+// postDigests prints the digest of every release, or comments it on the
+// umbrella issue of the release when the manager is set.
+func postDigests(history *store.Store, manager github.ProjectManagerInterface, releases []string) error {
+	now := time.Now()
+	summary, err := history.Summary(now.Add(-time.Duration(digestSince)))
+	if err != nil {
+		return err
+	}
+	trends, err := history.BoardTrends(now, int(time.Duration(digestSince)/(24*time.Hour)))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, version := range releases {
+		releaseDashboards, err := release.Dashboards(version)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		branch := fingerprint.Branch(releaseDashboards[0])
+		digest := summary.Digest(branch, trends)
+		if manager == nil {
+			fmt.Println(digest)
+			continue
+		}
+		url := cfg.UmbrellaIssues[version]
+		if url == "" {
+			url = cfg.UmbrellaIssues[branch]
+		}
+		if url == "" {
+			fmt.Printf("%s has no umbrella issue in umbrellaIssues, skipping\n", branch)
+			continue
+		}
+		if err := commentUmbrellaIssue(manager, url, digest); err != nil {
+			errs = append(errs, fmt.Errorf("error posting the %s digest to %s: %v", branch, url, err))
+			continue
+		}
+		fmt.Printf("posted the %s digest to %s\n", branch, url)
+	}
+	return errors.Join(errs...)
+}
+
+// commentUmbrellaIssue comments the digest on the issue of the URL.
+func commentUmbrellaIssue(manager github.ProjectManagerInterface, url, digest string) error {
+	owner, repo, number, err := state.ParseIssueURL(url)
+	if err != nil {
+		return err
+	}
+	issue, err := manager.GetIssue(owner, repo, number)
+	if err != nil {
+		return err
+	}
+	return manager.CommentIssue(issue, digest)
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/signalhound/internal/doctor"
+	"sigs.k8s.io/signalhound/internal/testgrid"
+	"sigs.k8s.io/signalhound/internal/tui"
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the GitHub token, TestGrid, MCP server, clipboard and templates, with fix hints",
+	// a broken configuration is reported as a check instead of aborting.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		doctorConfigErr = loadConfig(cmd, args)
+		return nil
+	},
+	RunE: RunDoctor,
+}
+
+var (
+	doctorDashboards []string
+	doctorMCPURL     string
+	doctorConfigErr  error
+)
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.PersistentFlags().StringSliceVarP(&doctorDashboards, "dashboards", "d", defaultDashboards,
+		"comma-separated list of TestGrid dashboards to check.")
+	doctorCmd.PersistentFlags().StringVar(&doctorMCPURL, "mcp-url", "",
+		"URL of a signalhound mcp --http server to check, e.g. http://localhost:8080/mcp, mcpURL of the configuration file when empty.")
+}
+
+// RunDoctor runs the environment checks and fails when any of them failed.
+func RunDoctor(cmd *cobra.Command, args []string) error {
+	tui.SetClipboardTool(cfg.Clipboard)
+	if doctorMCPURL == "" {
+		doctorMCPURL = cfg.MCPURL
+	}
+	results := doctor.Run(cmd.Context(), doctor.Options{
+		ConfigErr:     doctorConfigErr,
+		GitHubToken:   githubToken(),
+		TestGridURL:   testgrid.URL,
+		Dashboards:    doctorDashboards,
+		MCPURL:        doctorMCPURL,
+		AnthropicKey:  os.Getenv("ANTHROPIC_API_KEY"),
+		ClipboardTool: tui.ClipboardTool,
+	})
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "STATUS\tCHECK\tRESULT") // nolint
+	for _, result := range results {
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", result.Status, result.Name, result.Message) // nolint
+		if result.Hint != "" && result.Status != doctor.OK {
+			fmt.Fprintf(writer, "\t\t↳ %s\n", result.Hint) // nolint
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	if failures := doctor.Failures(results); failures > 0 {
+		return withExitCode(doctorExitCode(results), fmt.Errorf("%d checks failed", failures))
+	}
+	return nil
+}
+
+// doctorExitCode returns ExitAuthError when a credential check failed,
+// ExitFetchError when a TestGrid check failed and ExitError otherwise.
+func doctorExitCode(results []doctor.Result) int {
+	code := ExitError
+	for _, result := range results {
+		if result.Status != doctor.Failed {
+			continue
+		}
+		switch {
+		case result.Name == "GitHub token" || result.Name == "Anthropic key":
+			return ExitAuthError
+		case strings.HasPrefix(result.Name, "TestGrid "):
+			code = ExitFetchError
+		}
+	}
+	return code
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of the CLI, pipelines embedding signalhound branch on them.
+const (
+	ExitOK           = 0
+	ExitError        = 1
+	ExitFailingTests = 2
+	ExitFetchError   = 3
+	ExitAuthError    = 4
+)
+
+// Formats of the error printed before exiting, set with --error-format.
+const (
+	errorFormatText = "text"
+	errorFormatJSON = "json"
+)
+
+var errorFormat string
+
+// exitError is an error ending the CLI with a documented exit code.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode returns the error ending the CLI with the exit code, nil for
+// a nil error.
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{code: code, err: err}
+}
+
+// exitCode returns the exit code of the error, ExitError unless set with
+// withExitCode.
+func exitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return ExitError
+}
+
+// errorKind names the exit code in the JSON errors.
+func errorKind(code int) string {
+	switch code {
+	case ExitFailingTests:
+		return "failing_tests"
+	case ExitFetchError:
+		return "fetch_error"
+	case ExitAuthError:
+		return "auth_error"
+	}
+	return "error"
+}
+
+// validateErrorFormat checks --error-format, a JSON error format silences
+// the usage printed on errors so the error is the only output on stderr.
+func validateErrorFormat(cmd *cobra.Command) error {
+	switch format := errorFormat; format {
+	case errorFormatText:
+	case errorFormatJSON:
+		cmd.SilenceUsage = true
+	default:
+		errorFormat = errorFormatText
+		return fmt.Errorf("unknown error format %q, expected text or json", format)
+	}
+	return nil
+}
+
+// printError writes the error in the error format, e.g.
+// {"code":3,"kind":"fetch_error","error":"..."} in JSON.
+func printError(w io.Writer, err error) {
+	code := exitCode(err)
+	if errorFormat != errorFormatJSON {
+		fmt.Fprintf(w, "Error: %v\n", err) // nolint
+		return
+	}
+	data, _ := json.Marshal(struct {
+		Code  int    `json:"code"`
+		Kind  string `json:"kind"`
+		Error string `json:"error"`
+	}{Code: code, Kind: errorKind(code), Error: err.Error()})
+	fmt.Fprintln(w, string(data)) // nolint
+}
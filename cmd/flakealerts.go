@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/signalhound/internal/testgrid"
+	"sigs.k8s.io/signalhound/pkg/pipeline"
+)
+
+// flakeAlertsCmd represents the flake-alerts command
+var flakeAlertsCmd = &cobra.Command{
+	Use:   "flake-alerts",
+	Short: "Alert on the tests whose rolling flake rate crosses a threshold, even on passing tabs",
+	RunE:  RunFlakeAlerts,
+}
+
+var (
+	flakeAlertDashboards []string
+	flakeAlertThresholds []string
+	flakeAlertInterval   time.Duration
+	flakeAlertSynthetic  bool
+)
+
+func init() {
+	rootCmd.AddCommand(flakeAlertsCmd)
+
+	flakeAlertsCmd.PersistentFlags().StringSliceVarP(&flakeAlertDashboards, "dashboards", "d", defaultDashboards,
+		"comma-separated list of TestGrid dashboards to check the flake rates of.")
+	flakeAlertsCmd.PersistentFlags().StringSliceVarP(&flakeAlertThresholds, "threshold", "t", nil,
+		"flake rate and window to alert on, e.g. 30%/24h, overrides flakeRateAlerts of the configuration file.")
+	flakeAlertsCmd.PersistentFlags().DurationVar(&flakeAlertInterval, "interval", 0,
+		"check again at this interval and print only the new crossings, 0 checks once.")
+	flakeAlertsCmd.PersistentFlags().BoolVar(&flakeAlertSynthetic, "include-synthetic-rows", false,
+		"check the Overall and aggregate rows TestGrid adds to the tests of a job, e.g. ci-kubernetes-e2e.Overall.")
+}
+
+// RunFlakeAlerts prints a Slack message for every test crossing a flake rate
+// threshold, once or at every interval until interrupted.
+func RunFlakeAlerts(cmd *cobra.Command, args []string) error {
+	thresholds := cfg.FlakeRateAlerts
+	if len(flakeAlertThresholds) > 0 {
+		thresholds = nil
+		for _, value := range flakeAlertThresholds {
+			threshold, err := testgrid.ParseFlakeRateThreshold(value)
+			if err != nil {
+				return err
+			}
+			thresholds = append(thresholds, threshold)
+		}
+	}
+	if len(thresholds) == 0 {
+		return fmt.Errorf("no flake rate threshold, pass --threshold or set flakeRateAlerts in the configuration file")
+	}
+
+	monitor := pipeline.NewFlakeRateMonitor(testgridURL, thresholds)
+	monitor.IncludeSynthetic = flakeAlertSynthetic
+	monitor.OnTabError = func(tabName string, err error) {
+		fmt.Println(fmt.Errorf("error fetching table %s: %s", tabName, err))
+	}
+	check := func() (int, error) {
+		alerts, err := monitor.Check(flakeAlertDashboards)
+		if err != nil {
+			return 0, withExitCode(ExitFetchError, err)
+		}
+		for _, alert := range alerts {
+			fmt.Println(alert.Message())
+		}
+		return len(alerts), nil
+	}
+	alerts, err := check()
+	if err != nil {
+		return err
+	}
+	if flakeAlertInterval <= 0 {
+		if alerts > 0 {
+			return withExitCode(ExitFailingTests, fmt.Errorf("%d tests over a flake rate threshold", alerts))
+		}
+		return nil
+	}
+
+	// the root context is done on SIGINT or SIGTERM.
+	ctx := cmd.Context()
+	ticker := time.NewTicker(flakeAlertInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			// a TestGrid outage is retried at the next interval.
+			if _, err := check(); err != nil {
+				fmt.Println(fmt.Errorf("error checking flake rates: %s", err))
+			}
+		}
+	}
+}
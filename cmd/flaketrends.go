@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/signalhound/internal/notify"
+	"sigs.k8s.io/signalhound/internal/slack"
+	"sigs.k8s.io/signalhound/internal/store"
+)
+
+// flakeTrendsCmd represents the flake-trends command
+var flakeTrendsCmd = &cobra.Command{
+	Use:   "flake-trends",
+	Short: "Report the new, worsening, improved and resolved flakes of each SIG to its Slack channel and leads",
+	RunE:  RunFlakeTrends,
+}
+
+var (
+	flakeTrendsPeriod   = days(7 * 24 * time.Hour)
+	flakeTrendsInterval time.Duration
+	flakeTrendsSend     bool
+	flakeTrendsSIGs     []string
+
+	// smtpServer is the mail server of the flake trends and of the email
+	// alerts of the controller.
+	smtpServer notify.SMTP
+)
+
+func init() {
+	rootCmd.AddCommand(flakeTrendsCmd)
+
+	flakeTrendsCmd.PersistentFlags().Var(&flakeTrendsPeriod, "period", "period compared with the previous one, in days like 7d or a duration like 36h.")
+	flakeTrendsCmd.PersistentFlags().DurationVar(&flakeTrendsInterval, "interval", 0,
+		"report again at this interval, e.g. 168h for a weekly report, 0 reports once.")
+	flakeTrendsCmd.PersistentFlags().BoolVar(&flakeTrendsSend, "send", false,
+		"post the reports to slack.sigChannels and email them to sigEmails of the configuration file instead of printing them.")
+	flakeTrendsCmd.PersistentFlags().StringSliceVar(&flakeTrendsSIGs, "sig", nil,
+		"comma-separated list of SIGs to report, e.g. node,network, every SIG with a trend when empty.")
+	addSMTPFlags(flakeTrendsCmd)
+}
+
+// addSMTPFlags adds the flags of the mail server, shared with the controller.
+func addSMTPFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&smtpServer.Addr, "smtp-addr", "",
+		"host:port of the SMTP server sending the emails.")
+	cmd.PersistentFlags().StringVar(&smtpServer.From, "smtp-from", "signalhound@localhost",
+		"sender address of the emails.")
+	cmd.PersistentFlags().StringVar(&smtpServer.Username, "smtp-username", "",
+		"username of the SMTP server, the password is read from SIGNALHOUND_SMTP_PASSWORD.")
+}
+
+// RunFlakeTrends reports the flake trends of the SIGs recorded by the
+// abstract command, once or at every interval until interrupted.
+func RunFlakeTrends(cmd *cobra.Command, args []string) error {
+	history := historyStore()
+	if history == nil {
+		return errors.New("the history is disabled, set --history")
+	}
+	if flakeTrendsSend {
+		if err := checkWritable(); err != nil {
+			return err
+		}
+	}
+	smtpServer.Password = os.Getenv("SIGNALHOUND_SMTP_PASSWORD")
+
+	if err := reportFlakeTrends(history); err != nil || flakeTrendsInterval <= 0 {
+		return err
+	}
+
+	// the root context is done on SIGINT or SIGTERM.
+	ctx := cmd.Context()
+	ticker := time.NewTicker(flakeTrendsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			// a failed delivery is retried at the next interval.
+			if err := reportFlakeTrends(history); err != nil {
+				fmt.Println(fmt.Errorf("error reporting flake trends: %s", err))
+			}
+		}
+	}
+}
+
+// reportFlakeTrends prints the report of every SIG, or sends it to the Slack
+// channel and the emails of the SIG with --send.
+func reportFlakeTrends(history *store.Store) error {
+	trends, err := history.FlakeTrends(time.Now(), time.Duration(flakeTrendsPeriod))
+	if err != nil {
+		return err
+	}
+	sigs := flakeTrendsSIGs
+	if len(sigs) == 0 {
+		sigs = trends.SIGs()
+	}
+
+	var errs []error
+	for _, sigName := range sigs {
+		report := trends.Report(sigName)
+		if !flakeTrendsSend {
+			fmt.Println(report)
+			continue
+		}
+		channel, emails := cfg.Slack.SIGChannels[sigName], cfg.SIGEmails[sigName]
+		if channel == "" && len(emails) == 0 {
+			fmt.Printf("sig-%s has no channel in slack.sigChannels nor emails in sigEmails, skipping\n", sigName)
+			continue
+		}
+		if channel != "" {
+			if _, err := slack.NewClient(slackToken()).PostMessage(channel, report, ""); err != nil {
+				errs = append(errs, fmt.Errorf("error posting the sig-%s report to %s: %v", sigName, channel, err))
+			}
+		}
+		if len(emails) > 0 {
+			subject := fmt.Sprintf("sig-%s flake trends, %s to %s", sigName, trends.Since.Format(time.DateOnly), trends.Until.Format(time.DateOnly))
+			if err := (&notify.Email{Server: &smtpServer, To: emails}).Send(subject, report); err != nil {
+				errs = append(errs, fmt.Errorf("error emailing the sig-%s report: %v", sigName, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/signalhound/internal/store"
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history TEST",
+	Short: "Show the flake rate and the recorded results of a test over days or weeks",
+	Args:  cobra.ExactArgs(1),
+	RunE:  RunHistory,
+}
+
+// historyCompactCmd represents the history compact command
+var historyCompactCmd = &cobra.Command{
+	Use:   "compact",
+	Short: "Compact the history older than the retention of the configuration into daily runs",
+	Args:  cobra.NoArgs,
+	RunE:  RunHistoryCompact,
+}
+
+// compactInterval is how often the fetches compact the history, a
+// signalhound refreshing for weeks keeps a bounded history file.
+const compactInterval = 24 * time.Hour
+
+var (
+	historySince time.Duration
+
+	compactMu      sync.Mutex
+	lastCompaction time.Time // Time the history was last compacted by the fetches
+)
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.AddCommand(historyCompactCmd)
+
+	historyCmd.PersistentFlags().DurationVar(&historySince, "since", store.ScoreWindow,
+		"how far back the history goes, 0 for the whole history.")
+}
+
+// RunHistory prints the history recorded by the abstract command for a test.
+func RunHistory(cmd *cobra.Command, args []string) error {
+	history := historyStore()
+	if history == nil {
+		return errors.New("the history is disabled, set --history")
+	}
+	var since time.Time
+	if historySince > 0 {
+		since = time.Now().Add(-historySince)
+	}
+	result, err := history.History(args[0], since)
+	if err != nil {
+		return err
+	}
+	fmt.Print(result)
+	return nil
+}
+
+// RunHistoryCompact applies the history retention of the configuration and
+// prints the counts of the compaction.
+func RunHistoryCompact(cmd *cobra.Command, args []string) error {
+	history := historyStore()
+	if history == nil {
+		return errors.New("the history is disabled, set --history")
+	}
+	compaction, err := history.Compact(time.Now(), cfg.HistoryRetention)
+	if err != nil {
+		return err
+	}
+	fmt.Println(compaction)
+	return nil
+}
+
+// compactHistory compacts the history in background at most once per
+// compactInterval, printing the errors to w.
+func compactHistory(w io.Writer, history *store.Store, now time.Time) {
+	compactMu.Lock()
+	defer compactMu.Unlock()
+	if now.Sub(lastCompaction) < compactInterval {
+		return
+	}
+	lastCompaction = now
+	go func() {
+		if _, err := history.Compact(now, cfg.HistoryRetention); err != nil {
+			fmt.Fprintln(w, fmt.Errorf("error compacting the history: %s", err)) // nolint
+		}
+	}()
+}
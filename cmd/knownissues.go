@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/pkg/pipeline"
+)
+
+// knownIssuesCmd represents the known-issues command
+var knownIssuesCmd = &cobra.Command{
+	Use:   "known-issues",
+	Short: "Draft the Known Issues (CI) section of the release notes from the failing tests and their board issues",
+	RunE:  RunKnownIssues,
+}
+
+func init() {
+	rootCmd.AddCommand(knownIssuesCmd)
+
+	knownIssuesCmd.PersistentFlags().StringSliceVarP(&dashboards, "dashboards", "d", defaultDashboards,
+		"comma-separated list of TestGrid dashboards or dashboard groups to read the failing tests of.")
+	knownIssuesCmd.PersistentFlags().StringVar(&releaseBranch, "release", "",
+		"release branch of the notes, e.g. 1.35 expands to the sig-release-1.35-blocking and sig-release-1.35-informing dashboards unless --dashboards is set.")
+	knownIssuesCmd.PersistentFlags().BoolVar(&includeSynthetic, "include-synthetic-rows", false,
+		"keep the Overall and aggregate rows TestGrid adds to the tests of a job, e.g. ci-kubernetes-e2e.Overall.")
+}
+
+// RunKnownIssues fetches the boards like abstract, matches their failing
+// tests with the open and draft issues of the project board and prints the
+// draft of the release notes section in Markdown.
+func RunKnownIssues(cmd *cobra.Command, args []string) error {
+	token := githubToken()
+	if token == "" {
+		return withExitCode(ExitAuthError, errors.New("no GitHub token, export SIGNALHOUND_GITHUB_TOKEN or set githubToken in the configuration file"))
+	}
+	if err := expandRelease(cmd); err != nil {
+		return err
+	}
+	tabs, err := fetchTabSummary(os.Stderr)
+	if err != nil {
+		return err
+	}
+	issues, err := github.NewProjectManager(cmd.Context(), token).ListBoardIssues()
+	if err != nil {
+		return err
+	}
+	fmt.Print(pipeline.KnownIssuesMarkdown(releaseBranch, pipeline.KnownIssues(tabs, issues)))
+	return nil
+}
@@ -0,0 +1,80 @@
+/* Copyright 2025 Amim Knabben */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/signalhound/internal/prow"
+	"sigs.k8s.io/signalhound/internal/testgrid"
+)
+
+// lintCmd represents the lint-jobs command
+var lintCmd = &cobra.Command{
+	Use:   "lint-jobs",
+	Short: "Check the Prow job configs of the monitored boards for signal-quality issues",
+	RunE:  RunLint,
+}
+
+var lintDashboards []string
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+	lintCmd.PersistentFlags().StringSliceVarP(&lintDashboards, "dashboards", "d", defaultDashboards,
+		"comma-separated list of TestGrid dashboards to lint the jobs of")
+}
+
+// RunLint reads the job config of the latest run of every tab and reports
+// missing alerting annotations and short result histories.
+func RunLint(cmd *cobra.Command, args []string) error {
+	tg := testgrid.NewTestGrid(testgrid.URL)
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "BOARD#TAB\tJOB\tANNOTATION\tISSUE") // nolint
+
+	var jobsWithIssues int
+	for _, dashboard := range lintDashboards {
+		// every tab is linted, a passing job with broken alerting is a future miss.
+		summaries, err := tg.FetchTabSummary(dashboard, nil)
+		if err != nil {
+			return withExitCode(ExitFetchError, err)
+		}
+		sort.Slice(summaries, func(i, j int) bool {
+			return summaries[i].DashboardTab.TabName < summaries[j].DashboardTab.TabName
+		})
+		for _, summary := range summaries {
+			boardHash := fmt.Sprintf("%s#%s", dashboard, summary.DashboardTab.TabName)
+			testGroup, err := tg.FetchTestGroup(&summary)
+			if err != nil {
+				fmt.Println(fmt.Errorf("error fetching table %s: %s", boardHash, err))
+				continue
+			}
+			buildURL := testGroup.LatestBuildURL()
+			if buildURL == "" {
+				continue
+			}
+			job, err := prow.NewProw(buildURL).GetProwJob()
+			if err != nil {
+				fmt.Println(fmt.Errorf("error fetching job config %s: %s", boardHash, err))
+				continue
+			}
+			issues := job.Lint()
+			if len(issues) > 0 {
+				jobsWithIssues++
+			}
+			for _, issue := range issues {
+				fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", boardHash, job.Spec.Job, issue.Annotation, issue.Message) // nolint
+			}
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\n%d jobs with signal-quality issues\n", jobsWithIssues)
+	return nil
+}
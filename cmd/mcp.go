@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/signalhound/internal/mcp"
+	"sigs.k8s.io/signalhound/internal/monitoring"
+)
+
+// mcpCmd represents the mcp command
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Serve the signalhound tools over the Model Context Protocol",
+	RunE:  RunMCP,
+}
+
+var (
+	mcpAddress             string
+	mcpRequireSessionToken bool
+)
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+
+	mcpCmd.PersistentFlags().StringVar(&mcpAddress, "http", "",
+		"address to serve the Streamable HTTP transport on (e.g. :8080), stdio is used when empty.")
+	mcpCmd.PersistentFlags().BoolVar(&mcpRequireSessionToken, "require-session-token", false,
+		"reject sessions without their own GitHub token instead of using the server token.")
+	mcpCmd.PersistentFlags().StringSliceVarP(&dashboards, "dashboards", "d", defaultDashboards,
+		"comma-separated list of TestGrid dashboards get_failing_tests checks when the call names none.")
+}
+
+// RunMCP starts the MCP server on stdio or HTTP until SIGINT or SIGTERM.
+func RunMCP(cmd *cobra.Command, args []string) error {
+	store, err := stateStore()
+	if err != nil {
+		return err
+	}
+	server := mcp.NewServer("signalhound", "dev", githubToken())
+	server.RequireSessionToken = mcpRequireSessionToken
+	mcp.Dashboards = dashboards
+	mcp.History = historyStore()
+	mcp.State = store
+	mcp.RegisterTools(server)
+	mcp.RegisterPrompts(server)
+	mcp.RegisterResources(server)
+
+	if mcpAddress == "" {
+		return server.ServeStdio(cmd.Context(), os.Stdin, os.Stdout)
+	}
+
+	if err := monitoring.Setup(); err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", server.HTTPHandler())
+	mux.Handle("/metrics", monitoring.Handler())
+	fmt.Fprintf(os.Stderr, "serving MCP on %s/mcp and metrics on %s/metrics\n", mcpAddress, mcpAddress)
+	httpServer := &http.Server{Addr: mcpAddress, Handler: mux}
+	// the tool calls in flight are answered before the listener is closed.
+	stop := context.AfterFunc(cmd.Context(), func() {
+		ctx, cancel := context.WithTimeout(context.Background(), requestsGrace)
+		defer cancel()
+		_ = httpServer.Shutdown(ctx)
+	})
+	defer stop()
+	if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
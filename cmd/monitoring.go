@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/signalhound/internal/monitoring"
+)
+
+// monitoringCmd represents the generate-monitoring command
+var monitoringCmd = &cobra.Command{
+	Use:   "generate-monitoring",
+	Short: "Generate the ServiceMonitor and Grafana dashboard of the controller metrics",
+	RunE:  RunGenerateMonitoring,
+}
+
+var (
+	monitoringOutputDir string
+	monitoringOpts      = monitoring.DefaultOptions()
+)
+
+func init() {
+	rootCmd.AddCommand(monitoringCmd)
+
+	monitoringCmd.PersistentFlags().StringVarP(&monitoringOutputDir, "output-dir", "o", "config/monitoring",
+		"directory to write the servicemonitor.yaml and grafana-dashboard.json files to.")
+	monitoringCmd.PersistentFlags().StringVar(&monitoringOpts.Namespace, "namespace", monitoringOpts.Namespace,
+		"namespace of the controller metrics service.")
+	monitoringCmd.PersistentFlags().StringVar(&monitoringOpts.Port, "port", monitoringOpts.Port,
+		"name of the metrics service port.")
+	monitoringCmd.PersistentFlags().BoolVar(&monitoringOpts.Secure, "metrics-secure", monitoringOpts.Secure,
+		"scrape the metrics endpoint over HTTPS with the service account token.")
+}
+
+// RunGenerateMonitoring writes the monitoring manifests built from the
+// controller metric definitions.
+func RunGenerateMonitoring(cmd *cobra.Command, args []string) error {
+	if err := monitoring.Write(monitoringOutputDir, monitoringOpts); err != nil {
+		return err
+	}
+	fmt.Printf("generated %s and %s in %s\n", monitoring.ServiceMonitorFile, monitoring.DashboardFile, monitoringOutputDir)
+	return nil
+}
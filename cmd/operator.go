@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"sigs.k8s.io/signalhound/internal/controller"
+)
+
+// operatorCmd groups the commands acting on the Dashboard objects of the controller
+var operatorCmd = &cobra.Command{
+	Use:   "operator",
+	Short: "Manage the Dashboard objects reconciled by the controller",
+}
+
+// operatorTriggerCmd represents the operator trigger command
+var operatorTriggerCmd = &cobra.Command{
+	Use:   "trigger [DASHBOARD...]",
+	Short: "Force the immediate reconciliation of Dashboard objects, e.g. after fixing credentials",
+	RunE:  RunOperatorTrigger,
+}
+
+var (
+	triggerAll       bool
+	triggerNamespace string
+)
+
+func init() {
+	rootCmd.AddCommand(operatorCmd)
+	operatorCmd.AddCommand(operatorTriggerCmd)
+
+	operatorTriggerCmd.PersistentFlags().BoolVar(&triggerAll, "all", false,
+		"trigger every Dashboard of the namespace, or of all namespaces without --namespace.")
+	operatorTriggerCmd.PersistentFlags().StringVarP(&triggerNamespace, "namespace", "n", "",
+		"namespace of the Dashboard objects, required with names.")
+}
+
+// RunOperatorTrigger touches the reconcile annotation of the Dashboard
+// objects of the current kubeconfig context.
+func RunOperatorTrigger(cmd *cobra.Command, args []string) error {
+	switch {
+	case triggerAll && len(args) > 0:
+		return errors.New("pass either --all or dashboard names")
+	case !triggerAll && len(args) == 0:
+		return errors.New("pass --all or dashboard names")
+	case len(args) > 0 && triggerNamespace == "":
+		return errors.New("--namespace is required with dashboard names")
+	}
+
+	config, err := ctrl.GetConfig()
+	if err != nil {
+		return fmt.Errorf("error loading the kubeconfig: %v", err)
+	}
+	c, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("error creating the client: %v", err)
+	}
+	triggered, err := controller.Trigger(cmd.Context(), c, triggerNamespace, args, time.Now())
+	for _, dashboard := range triggered {
+		fmt.Printf("dashboard %s triggered\n", dashboard)
+	}
+	return err
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/pkg/pipeline"
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Print the failing and flaky tests of the boards as JSON, YAML or Markdown, without the UI",
+	RunE:  RunReport,
+}
+
+var reportOutput string
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+
+	// the fetch flags are shared with abstract, so are the config file defaults.
+	reportCmd.PersistentFlags().IntVarP(&minFailure, "min-failure", "f", 0,
+		"minimum threshold for test failures, to disable use 0. Defaults to 0.")
+	reportCmd.PersistentFlags().IntVarP(&minFlake, "min-flake", "m", 0,
+		"minimum threshold for test flakeness, to disable use 0. Defaults to 0.")
+	reportCmd.PersistentFlags().StringSliceVarP(&dashboards, "dashboards", "d", defaultDashboards,
+		"comma-separated list of TestGrid dashboards or dashboard groups to report (e.g. sig-release-1.35-blocking,sig-release-1.35-informing or sig-release)")
+	reportCmd.PersistentFlags().StringVar(&releaseBranch, "release", "",
+		"release branch to report, e.g. 1.32 expands to the sig-release-1.32-blocking and sig-release-1.32-informing dashboards unless --dashboards is set.")
+	reportCmd.PersistentFlags().BoolVar(&includeSynthetic, "include-synthetic-rows", false,
+		"keep the Overall and aggregate rows TestGrid adds to the tests of a job, e.g. ci-kubernetes-e2e.Overall.")
+	reportCmd.PersistentFlags().StringVarP(&reportOutput, "output", "o", pipeline.FormatJSON,
+		"output format, one of "+strings.Join(pipeline.Formats, ", ")+".")
+}
+
+// RunReport fetches the boards like abstract and prints the report to the
+// standard output, the fetch errors go to the standard error. It ends with
+// ExitFailingTests when a board has failing tests.
+func RunReport(cmd *cobra.Command, args []string) error {
+	if !slices.Contains(pipeline.Formats, reportOutput) {
+		return fmt.Errorf("unknown output format %q, use one of %s", reportOutput, strings.Join(pipeline.Formats, ", "))
+	}
+	if err := expandRelease(cmd); err != nil {
+		return err
+	}
+	tabs, err := fetchTabSummary(os.Stderr)
+	if err != nil {
+		return err
+	}
+	snapshot := pipeline.NewSnapshot(dashboards, tabs, time.Now())
+	// the health scores count the open critical issues when GitHub is reachable
+	if token := githubToken(); token != "" {
+		if err := snapshot.CountCriticalIssues(github.NewProjectManager(cmd.Context(), token)); err != nil {
+			fmt.Fprintln(os.Stderr, fmt.Errorf("error counting the critical issues: %v", err)) // nolint
+		}
+	}
+	if err := snapshot.Write(os.Stdout, reportOutput); err != nil {
+		return err
+	}
+	return failingTestsError(tabs)
+}
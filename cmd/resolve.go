@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/signalhound/pkg/pipeline"
+)
+
+// resolveCmd represents the resolve command
+var resolveCmd = &cobra.Command{
+	Use:   "resolve",
+	Short: "Comment on or close the GitHub issues of the tests green again for a number of runs",
+	RunE:  RunResolve,
+}
+
+// defaultGreenRuns is the number of consecutive green runs of a recovered
+// test before its issue is resolved.
+const defaultGreenRuns = 3
+
+var (
+	resolveGreenRuns int
+	resolveClose     bool
+)
+
+func init() {
+	rootCmd.AddCommand(resolveCmd)
+
+	// the fetch flags are shared with abstract, so are the config file defaults.
+	resolveCmd.PersistentFlags().StringSliceVarP(&dashboards, "dashboards", "d", defaultDashboards,
+		"comma-separated list of TestGrid dashboards or dashboard groups to check (e.g. sig-release-1.35-blocking,sig-release-1.35-informing or sig-release)")
+	resolveCmd.PersistentFlags().StringVar(&releaseBranch, "release", "",
+		"release branch to check, e.g. 1.32 expands to the sig-release-1.32-blocking and sig-release-1.32-informing dashboards unless --dashboards is set.")
+	resolveCmd.PersistentFlags().BoolVar(&includeSynthetic, "include-synthetic-rows", false,
+		"keep the Overall and aggregate rows TestGrid adds to the tests of a job, e.g. ci-kubernetes-e2e.Overall.")
+	addResolveFlags(resolveCmd)
+}
+
+// addResolveFlags adds the flags of the issue resolution, shared with the
+// controller.
+func addResolveFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().IntVar(&resolveGreenRuns, "green-runs", defaultGreenRuns,
+		"consecutive green runs of a recovered test before its issue is resolved.")
+	cmd.PersistentFlags().BoolVar(&resolveClose, "close", false,
+		"close the issues of the recovered tests instead of commenting they can be closed.")
+}
+
+// newResolver returns the resolver of the issue resolution flags, or an auth
+// error without GitHub token.
+func newResolver(cmd *cobra.Command) (*pipeline.ProjectResolver, error) {
+	if err := checkWritable(); err != nil {
+		return nil, err
+	}
+	if resolveGreenRuns < 0 {
+		return nil, fmt.Errorf("invalid --green-runs %d, expected a positive number of runs", resolveGreenRuns)
+	}
+	token := githubToken()
+	if token == "" {
+		return nil, withExitCode(ExitAuthError, errors.New("no GitHub token, export SIGNALHOUND_GITHUB_TOKEN or set githubToken in the configuration file"))
+	}
+	resolver := pipeline.NewProjectResolver(cmd.Context(), token)
+	resolver.GreenRuns = resolveGreenRuns
+	resolver.CommentOnly = !resolveClose
+	return resolver, nil
+}
+
+// RunResolve fetches the boards like abstract and resolves the open issue of
+// every failing test green again for --green-runs runs, commenting that it can
+// be closed, or closing it with --close.
+func RunResolve(cmd *cobra.Command, args []string) error {
+	if err := expandRelease(cmd); err != nil {
+		return err
+	}
+	resolver, err := newResolver(cmd)
+	if err != nil {
+		return err
+	}
+	tabs, err := fetchTabSummary(os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	action := "commented on"
+	if resolveClose {
+		action = "closed"
+	}
+	resolved := 0
+	for _, finding := range pipeline.NewAnalyzer().Analyze(tabs) {
+		open, err := resolver.Resolve(finding)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, fmt.Errorf("error resolving %s: %v", finding.Test.TestName, err)) // nolint
+			continue
+		}
+		if open != nil {
+			resolved++
+			fmt.Printf("%s #%d %s, %s recovered on %s\n", action, open.Number, open.URL, finding.Test.TestName, finding.Tab.BoardHash)
+		}
+	}
+	if resolved == 0 {
+		fmt.Printf("no open issue of a test green for %d runs\n", resolveGreenRuns)
+	}
+	return nil
+}
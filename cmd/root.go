@@ -1,22 +1,181 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/signalhound/internal/config"
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/issue"
+	"sigs.k8s.io/signalhound/internal/network"
+	"sigs.k8s.io/signalhound/internal/sig"
+	"sigs.k8s.io/signalhound/internal/state"
+	"sigs.k8s.io/signalhound/internal/store"
 )
 
 var (
 	rootCmd = &cobra.Command{
-		Use:   "signalhound",
-		Short: "signalhound search for issues and flaky tests on Kubernetes",
-		Long:  "signalhound search for issues and flaky tests on Kubernetes",
+		Use:               "signalhound",
+		Short:             "signalhound search for issues and flaky tests on Kubernetes",
+		Long:              "signalhound search for issues and flaky tests on Kubernetes",
+		PersistentPreRunE: loadConfig,
+		// the error is printed by Execute in the --error-format.
+		SilenceErrors: true,
 	}
+
+	configFile  string
+	profile     string
+	cfg         = &config.Config{}
+	statePath   string
+	historyPath string
+	templates   string
+	dryRun      bool
+
+	// sigResolver infers the SIG of the tests from the sources of the
+	// configuration, shared by the refreshes to keep its cache.
+	sigResolver *sig.Resolver
 )
 
+func init() {
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", config.DefaultPath(),
+		"path of the configuration file, secrets can reference ${ENV} variables, file:<path> or ENC[age:...] values.")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "",
+		"profile of the configuration file overriding its defaults, e.g. lead or shadow, the profile of the file when empty.")
+	rootCmd.PersistentFlags().StringVar(&statePath, "state", state.DefaultPath(),
+		"location of the state keeping the test annotations, a local file path or configmap:<namespace>/<name> to share it in a cluster.")
+	rootCmd.PersistentFlags().StringVar(&historyPath, "history", store.DefaultPath(),
+		"path of the local database recording the fetched test results for the history command, empty to disable.")
+	rootCmd.PersistentFlags().StringVar(&errorFormat, "error-format", errorFormatText,
+		"format of the error printed on failures, text or json, see the README for the exit codes.")
+	rootCmd.PersistentFlags().StringVar(&templates, "templates", "",
+		"directory overriding the embedded issue templates, e.g. failure.tmpl and flake.tmpl.")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false,
+		"log the GitHub mutations, e.g. the issue creations, comments and board changes, instead of running them.")
+}
+
+// stateStore returns the store of the signalhound state.
+func stateStore() (state.Store, error) {
+	return state.Open(statePath)
+}
+
+// historyStore returns the store of the test results history, nil when
+// disabled.
+func historyStore() *store.Store {
+	if historyPath == "" {
+		return nil
+	}
+	return store.NewStore(historyPath)
+}
+
+// loadConfig reads the configuration file, sets up the HTTP clients and reads
+// the issue templates before running any command.
+func loadConfig(cmd *cobra.Command, args []string) (err error) {
+	if err = validateErrorFormat(cmd); err != nil {
+		return err
+	}
+	if cfg, err = config.Load(configFile); err != nil {
+		return err
+	}
+	if err = cfg.UseProfile(profile); err != nil {
+		return err
+	}
+	if err = network.Setup(requestsContext(cmd.Context()), cfg.Network); err != nil {
+		return err
+	}
+	if err = applyConfigFlags(cmd); err != nil {
+		return err
+	}
+	github.DryRun = dryRun
+	if cfg.ProjectFieldsTTL != nil {
+		github.FieldsTTL = cfg.ProjectFieldsTTL.Duration
+	}
+	if sigResolver, err = cfg.SIG.Resolver(); err != nil {
+		return err
+	}
+	return issue.SetTemplateDir(templates)
+}
+
+// applyConfigFlags sets the flags of the command not set on the command line
+// to the values of the configuration file, e.g. dashboards to --dashboards.
+func applyConfigFlags(cmd *cobra.Command) error {
+	values := map[string]string{}
+	if len(cfg.Dashboards) > 0 {
+		values["dashboards"] = strings.Join(cfg.Dashboards, ",")
+	}
+	if cfg.MinFailure > 0 {
+		values["min-failure"] = strconv.Itoa(cfg.MinFailure)
+	}
+	if cfg.MinFlake > 0 {
+		values["min-flake"] = strconv.Itoa(cfg.MinFlake)
+	}
+	if cfg.GreenRuns > 0 {
+		values["green-runs"] = strconv.Itoa(cfg.GreenRuns)
+	}
+	if cfg.RefreshInterval.Duration > 0 {
+		values["refresh-interval"] = strconv.Itoa(int(cfg.RefreshInterval.Seconds()))
+	}
+	if cfg.Templates != "" {
+		values["templates"] = cfg.Templates
+	}
+	if cfg.IncludeSyntheticRows {
+		values["include-synthetic-rows"] = "true"
+	}
+	for name, value := range values {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		if err := flag.Value.Set(value); err != nil {
+			return fmt.Errorf("error setting --%s from the config file: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// checkWritable returns an error when the configuration is read-only, before
+// a command writes to GitHub or Slack.
+func checkWritable() error {
+	if !cfg.ReadOnly {
+		return nil
+	}
+	if cfg.Profile != "" {
+		return fmt.Errorf("the %s profile is read-only, use a profile writing to GitHub and Slack", cfg.Profile)
+	}
+	return errors.New("the configuration is read-only, unset readOnly to write to GitHub and Slack")
+}
+
+// githubToken returns the GitHub token from the environment, falling back to
+// the configuration file.
+func githubToken() string {
+	if token := os.Getenv("SIGNALHOUND_GITHUB_TOKEN"); token != "" {
+		return token
+	}
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		return token
+	}
+	return cfg.GitHubToken
+}
+
+// slackToken returns the Slack bot token from the environment, falling back
+// to the configuration file.
+func slackToken() string {
+	if token := os.Getenv("SIGNALHOUND_SLACK_TOKEN"); token != "" {
+		return token
+	}
+	return cfg.Slack.BotToken
+}
+
 func Execute() {
-	err := rootCmd.Execute()
+	ctx, stop := signalContext()
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
 	if err != nil {
-		os.Exit(1)
+		printError(os.Stderr, err)
+		os.Exit(exitCode(err))
 	}
 }
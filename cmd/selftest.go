@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	sigtesting "sigs.k8s.io/signalhound/internal/testing"
+)
+
+// selftestCmd represents the selftest command
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Run the whole pipeline against fake TestGrid, GitHub and MCP servers",
+	RunE:  RunSelfTest,
+}
+
+var selftestServe bool
+
+func init() {
+	rootCmd.AddCommand(selftestCmd)
+
+	selftestCmd.PersistentFlags().BoolVar(&selftestServe, "serve", false,
+		"keep the fake servers running after the test for local demos, until interrupted.")
+}
+
+// RunSelfTest starts the fake servers, runs the pipeline stages against them
+// and fails when any stage failed.
+func RunSelfTest(cmd *cobra.Command, args []string) error {
+	env, err := sigtesting.NewEnvironment()
+	if err != nil {
+		return err
+	}
+	defer env.Close()
+
+	steps := sigtesting.SelfTest(cmd.Context(), env)
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "STATUS\tSTEP\tRESULT") // nolint
+	for _, step := range steps {
+		status, result := "ok", step.Detail
+		if step.Err != nil {
+			status, result = "fail", step.Err.Error()
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", status, step.Name, result) // nolint
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	if last := steps[len(steps)-1]; last.Err != nil {
+		return fmt.Errorf("self test failed at %s", last.Name)
+	}
+
+	if selftestServe {
+		fmt.Printf("\nTestGrid: %s (dashboard %s)\nGitHub:   %s/graphql\nMCP:      %s (token %s)\n",
+			env.TestGrid.URL, sigtesting.Dashboard, env.GitHub.URL, env.MCP.URL, sigtesting.Token)
+		<-cmd.Context().Done()
+	}
+	return nil
+}
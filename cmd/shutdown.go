@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const (
+	// requestsGrace is the time left to the HTTP requests in flight after
+	// SIGINT or SIGTERM, so the issues and Slack messages being sent are not
+	// lost, before they are canceled.
+	requestsGrace = 10 * time.Second
+
+	// shutdownTimeout bounds the graceful shutdown, the command is killed
+	// once it runs out. It outlives the 30s shutdown of the controller
+	// manager.
+	shutdownTimeout = 40 * time.Second
+)
+
+// signalContext returns the context of the commands, done on SIGINT or
+// SIGTERM. A second signal exits right away, and so does the end of
+// shutdownTimeout after the first one.
+func signalContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		// the default handlers are back for the second signal.
+		stop()
+		time.Sleep(shutdownTimeout)
+		fmt.Fprintln(os.Stderr, "graceful shutdown timed out") // nolint
+		os.Exit(ExitError)
+	}()
+	return ctx, stop
+}
+
+// requestsContext returns a context done requestsGrace after ctx, canceling
+// the HTTP requests still in flight, see network.Setup.
+func requestsContext(ctx context.Context) context.Context {
+	requests, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	context.AfterFunc(ctx, func() {
+		time.AfterFunc(requestsGrace, cancel)
+	})
+	return requests
+}
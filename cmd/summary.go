@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// summaryCmd represents the summary command
+var summaryCmd = &cobra.Command{
+	Use:   "summary",
+	Short: "Summarize the recorded history in the weekly CI Signal report format",
+	RunE:  RunSummary,
+}
+
+var summarySince = days(7 * 24 * time.Hour)
+
+func init() {
+	rootCmd.AddCommand(summaryCmd)
+
+	summaryCmd.PersistentFlags().Var(&summarySince, "since", "how far back the summary goes, in days like 7d or a duration like 36h.")
+}
+
+// RunSummary prints the new failures, the resolved failures, the top flakes
+// and the board health trend recorded by the abstract command.
+func RunSummary(cmd *cobra.Command, args []string) error {
+	history := historyStore()
+	if history == nil {
+		return errors.New("the history is disabled, set --history")
+	}
+	summary, err := history.Summary(time.Now().Add(-time.Duration(summarySince)))
+	if err != nil {
+		return err
+	}
+	fmt.Print(summary)
+	return nil
+}
+
+// days is a duration flag also accepting a number of days, e.g. 7d.
+type days time.Duration
+
+func (d *days) String() string {
+	duration := time.Duration(*d)
+	if duration%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", duration/(24*time.Hour))
+	}
+	return duration.String()
+}
+
+func (d *days) Set(value string) error {
+	if count, found := strings.CutSuffix(value, "d"); found {
+		number, err := strconv.Atoi(count)
+		if err != nil || number <= 0 {
+			return fmt.Errorf("invalid number of days %q", value)
+		}
+		*d = days(time.Duration(number) * 24 * time.Hour)
+		return nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return fmt.Errorf("invalid duration %q, expected days like 7d or a duration like 36h", value)
+	}
+	*d = days(duration)
+	return nil
+}
+
+func (d *days) Type() string {
+	return "duration"
+}
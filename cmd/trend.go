@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// trendCmd represents the trend command
+var trendCmd = &cobra.Command{
+	Use:   "trend",
+	Short: "Show the daily health of the recorded boards and their state changes",
+	RunE:  RunTrend,
+}
+
+var trendSince = days(7 * 24 * time.Hour)
+
+func init() {
+	rootCmd.AddCommand(trendCmd)
+
+	trendCmd.PersistentFlags().Var(&trendSince, "since", "how far back the trend goes, in days like 7d.")
+}
+
+// RunTrend prints a sparkline of the worst state of every board recorded by
+// the fetches on each day, with the transitions between the states.
+func RunTrend(cmd *cobra.Command, args []string) error {
+	history := historyStore()
+	if history == nil {
+		return errors.New("the history is disabled, set --history")
+	}
+	count := int(time.Duration(trendSince) / (24 * time.Hour))
+	if count == 0 {
+		return fmt.Errorf("the trend needs at least a day, got %s", trendSince.String())
+	}
+	trends, err := history.BoardTrends(time.Now(), count)
+	if err != nil {
+		return err
+	}
+	fmt.Print(trends)
+	return nil
+}
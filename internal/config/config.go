@@ -0,0 +1,418 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/signalhound/internal/issue"
+	"sigs.k8s.io/signalhound/internal/network"
+	"sigs.k8s.io/signalhound/internal/sig"
+	"sigs.k8s.io/signalhound/internal/slack"
+	"sigs.k8s.io/signalhound/internal/store"
+	"sigs.k8s.io/signalhound/internal/testgrid"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// filePrefix references a secret stored in a local file.
+	filePrefix = "file:"
+
+	// agePrefix and ageSuffix wrap an age encrypted value, ENC[age:<armored ciphertext>].
+	agePrefix, ageSuffix = "ENC[age:", "]"
+
+	// AgeIdentityEnv points to the age identity used to decrypt the values,
+	// defaults to the SOPS age keys file.
+	AgeIdentityEnv = "SIGNALHOUND_AGE_IDENTITY"
+)
+
+var envRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// execCommand runs the external decryption tools, replaced in tests.
+var execCommand = func(stdin []byte, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
+
+// Config serializes the signalhound configuration file. Secret values can
+// reference environment variables, local files or age encrypted values, see
+// ResolveSecret, so the file can be shared and committed by a team.
+type Config struct {
+	// GitHubToken is the token used for the GitHub API calls.
+	GitHubToken string `json:"githubToken,omitempty"`
+
+	// Slack sets the @-mentions of the Slack messages of failing tests and
+	// the channel they are posted to.
+	Slack Slack `json:"slack,omitempty"`
+
+	// FlakeRateAlerts are the rolling flake rates alerted by the flake-alerts
+	// command, e.g. {rate: 0.3, window: 24h}.
+	FlakeRateAlerts []testgrid.FlakeRateThreshold `json:"flakeRateAlerts,omitempty"`
+
+	// SIGEmails are the addresses of the SIG leads the flake-trends command
+	// emails the weekly trends of their SIG to, e.g. node:
+	// [sig-node-leads@kubernetes.io].
+	SIGEmails map[string][]string `json:"sigEmails,omitempty"`
+
+	// UmbrellaIssues are the tracking issues the digest command comments the
+	// nightly digest of each release on, e.g. 1.35:
+	// https://github.com/kubernetes/sig-release/issues/2800.
+	UmbrellaIssues map[string]string `json:"umbrellaIssues,omitempty"`
+
+	// SIG selects the sources inferring the SIG of the tests without [sig-*]
+	// tag and their priority, and the local overrides, e.g. sources:
+	// [overrides, tag, owners, triage].
+	SIG sig.Config `json:"sig,omitempty"`
+
+	// HistoryRetention keeps the raw history of the tests rawDays, then their
+	// runs per day dailyMonths, see store.DefaultRetention, e.g. rawDays: 30.
+	HistoryRetention store.Retention `json:"historyRetention,omitempty"`
+
+	// Dashboards are the TestGrid dashboards of the commands, e.g.
+	// sig-release-1.35-blocking, instead of the master ones.
+	Dashboards []string `json:"dashboards,omitempty"`
+
+	// MinFailure and MinFlake are the test thresholds of the abstract command.
+	MinFailure int `json:"minFailure,omitempty"`
+	MinFlake   int `json:"minFlake,omitempty"`
+
+	// RefreshInterval is the auto-refresh interval of the abstract command in
+	// whole seconds, e.g. 5m.
+	RefreshInterval metav1.Duration `json:"refreshInterval,omitempty"`
+
+	// IncludeSyntheticRows keeps the Overall and aggregate rows TestGrid adds
+	// to the tests of a job, left out of the counts and the issues by default.
+	IncludeSyntheticRows bool `json:"includeSyntheticRows,omitempty"`
+
+	// GreenRuns is the number of consecutive green runs after which the issue
+	// of a recovered test is resolved by the resolve command and the
+	// controller.
+	GreenRuns int `json:"greenRuns,omitempty"`
+
+	// Templates is the directory overriding the embedded issue templates, ~
+	// expands to the home directory.
+	Templates string `json:"templates,omitempty"`
+
+	// Telemetry opts in to the counts of the actions taken in the abstract UI.
+	Telemetry Telemetry `json:"telemetry,omitempty"`
+
+	// Clipboard is the command the yy copies of the UI go to, e.g. osc52 or
+	// xsel, detected when empty. SIGNALHOUND_CLIPBOARD overrides it.
+	Clipboard string `json:"clipboard,omitempty"`
+
+	// MCPURL is the signalhound mcp --http server the status bar of the UI
+	// and the doctor command check, e.g. http://localhost:8080/mcp.
+	MCPURL string `json:"mcpURL,omitempty"`
+
+	// ProjectFieldsTTL is the time the fields of the project board are cached
+	// by the GitHub client, 10m when unset, 0s disables the cache.
+	ProjectFieldsTTL *metav1.Duration `json:"projectFieldsTTL,omitempty"`
+
+	// Network sets the proxy, the CA bundle and the client certificate of the
+	// HTTP clients, ~ expands to the home directory in the paths.
+	Network network.Config `json:"network,omitempty"`
+
+	// ReadOnly disables the commands and the UI actions writing to GitHub or
+	// Slack, e.g. for a SIG member watching the boards.
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// Profile is the profile applied when --profile is not set.
+	Profile string `json:"profile,omitempty"`
+
+	// Profiles are the named overrides of the defaults of the file by role,
+	// e.g. lead, shadow or sig-node, selected with --profile.
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+}
+
+// Profile overrides the defaults of the configuration file for a role. The
+// fields left empty keep the values of the file.
+type Profile struct {
+	// Dashboards, MinFailure, MinFlake, GreenRuns and RefreshInterval
+	// override the flag defaults of the file.
+	Dashboards      []string        `json:"dashboards,omitempty"`
+	MinFailure      int             `json:"minFailure,omitempty"`
+	MinFlake        int             `json:"minFlake,omitempty"`
+	GreenRuns       int             `json:"greenRuns,omitempty"`
+	RefreshInterval metav1.Duration `json:"refreshInterval,omitempty"`
+
+	// SlackChannel overrides slack.channel, the channel the messages are
+	// posted to.
+	SlackChannel string `json:"slackChannel,omitempty"`
+
+	// Mentions set to false leaves the @-mentions out of the Slack messages,
+	// e.g. for a shadow who doesn't page the SIGs.
+	Mentions *bool `json:"mentions,omitempty"`
+
+	// ReadOnly disables the writes to GitHub and Slack, see Config.ReadOnly.
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
+
+// UseProfile applies the named profile over the defaults of the file, the
+// profile of the file when name is empty. An empty name without profile in
+// the file keeps the configuration as is.
+func (c *Config) UseProfile(name string) error {
+	if name == "" {
+		name = c.Profile
+	}
+	if name == "" {
+		return nil
+	}
+	profile, found := c.Profiles[name]
+	if !found && len(c.Profiles) == 0 {
+		return fmt.Errorf("unknown profile %q, the configuration file has no profiles", name)
+	} else if !found {
+		names := make([]string, 0, len(c.Profiles))
+		for known := range c.Profiles {
+			names = append(names, known)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown profile %q, expected one of %s", name, strings.Join(names, ", "))
+	}
+	c.Profile = name
+	if len(profile.Dashboards) > 0 {
+		c.Dashboards = profile.Dashboards
+	}
+	if profile.MinFailure != 0 {
+		c.MinFailure = profile.MinFailure
+	}
+	if profile.MinFlake != 0 {
+		c.MinFlake = profile.MinFlake
+	}
+	if profile.GreenRuns != 0 {
+		c.GreenRuns = profile.GreenRuns
+	}
+	if profile.RefreshInterval.Duration != 0 {
+		c.RefreshInterval = profile.RefreshInterval
+	}
+	if profile.SlackChannel != "" {
+		c.Slack.Channel = profile.SlackChannel
+	}
+	if profile.Mentions != nil && !*profile.Mentions {
+		c.Slack.SlackMentions = issue.SlackMentions{}
+	}
+	c.ReadOnly = c.ReadOnly || profile.ReadOnly
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("error in profile %s: %v", name, err)
+	}
+	return nil
+}
+
+// DefaultMetricsAddress serves the telemetry metrics of the abstract UI.
+const DefaultMetricsAddress = "localhost:9464"
+
+// Telemetry configures the opt-in counts of the UI actions, e.g. the issues
+// created or the tests snoozed, served on a Prometheus metrics endpoint. The
+// test names and the users are never recorded.
+type Telemetry struct {
+	// Actions enables the counts, disabled by default.
+	Actions bool `json:"actions,omitempty"`
+
+	// MetricsAddress is the address of the /metrics endpoint, defaults to
+	// DefaultMetricsAddress.
+	MetricsAddress string `json:"metricsAddress,omitempty"`
+}
+
+// Validate checks the thresholds, the green runs, the refresh interval and
+// the project fields TTL.
+func (c *Config) Validate() error {
+	if c.MinFailure < 0 || c.MinFlake < 0 {
+		return fmt.Errorf("invalid minFailure %d or minFlake %d, expected positive thresholds", c.MinFailure, c.MinFlake)
+	}
+	if c.GreenRuns < 0 {
+		return fmt.Errorf("invalid greenRuns %d, expected a positive number of runs", c.GreenRuns)
+	}
+	if interval := c.RefreshInterval.Duration; interval < 0 || interval%time.Second != 0 {
+		return fmt.Errorf("invalid refreshInterval %s, expected whole seconds", interval)
+	}
+	if c.ProjectFieldsTTL != nil && c.ProjectFieldsTTL.Duration < 0 {
+		return fmt.Errorf("invalid projectFieldsTTL %s, expected a positive duration", c.ProjectFieldsTTL.Duration)
+	}
+	return nil
+}
+
+// Slack configures the Slack messages, the mentions are set at the top of the
+// slack section.
+type Slack struct {
+	issue.SlackMentions
+
+	// BotToken is the bot token posting the messages, e.g. xoxb-..., a secret
+	// reference like githubToken.
+	BotToken string `json:"botToken,omitempty"`
+
+	// Channel is the channel the messages are posted to, e.g.
+	// #release-ci-signal, without it the messages are only copied.
+	Channel string `json:"channel,omitempty"`
+
+	// SIGChannels routes the messages of the flaky tests to the channel of
+	// the SIG owning the test too, e.g. node: "#sig-node-ci".
+	SIGChannels issue.SlackRoutes `json:"sigChannels,omitempty"`
+
+	// RateLimits caps the messages posted by channel, e.g. 5 per hour, "*"
+	// applies to the channels without their own limit.
+	RateLimits map[string]slack.RateLimit `json:"rateLimits,omitempty"`
+}
+
+// DefaultPath returns the configuration file location under the user
+// configuration directory, or ~/.signalhound.yaml when only this one exists.
+func DefaultPath() string {
+	path := ""
+	if dir, err := os.UserConfigDir(); err == nil {
+		path = filepath.Join(dir, "signalhound", "config.yaml")
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		if _, err := os.Stat(filepath.Join(home, ".signalhound.yaml")); err == nil {
+			return filepath.Join(home, ".signalhound.yaml")
+		}
+	}
+	return path
+}
+
+// Load reads the configuration file and resolves its secrets. A missing file
+// returns an empty configuration. Files encrypted with SOPS, carrying the
+// top-level sops key, are decrypted with the sops binary.
+func Load(path string) (*Config, error) {
+	config := &Config{}
+	if path == "" {
+		return config, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading config file: %v", err)
+	}
+
+	if isSOPSEncrypted(data) {
+		if data, err = execCommand(nil, "sops", "--decrypt", path); err != nil {
+			return nil, fmt.Errorf("error decrypting config file: %v", err)
+		}
+	}
+	if err = yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("error unmarshaling config file: %v", err)
+	}
+
+	if config.GitHubToken, err = ResolveSecret(config.GitHubToken); err != nil {
+		return nil, fmt.Errorf("error resolving githubToken: %v", err)
+	}
+	if config.Slack.BotToken, err = ResolveSecret(config.Slack.BotToken); err != nil {
+		return nil, fmt.Errorf("error resolving slack.botToken: %v", err)
+	}
+	if err = config.Slack.QuietHours.Validate(); err != nil {
+		return nil, err
+	}
+	for channel, limit := range config.Slack.RateLimits {
+		if err = limit.Validate(); err != nil {
+			return nil, fmt.Errorf("error in slack.rateLimits of %s: %v", channel, err)
+		}
+	}
+	for _, threshold := range config.FlakeRateAlerts {
+		if err = threshold.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if err = config.SIG.Validate(); err != nil {
+		return nil, fmt.Errorf("error in sig: %v", err)
+	}
+	if err = config.HistoryRetention.Validate(); err != nil {
+		return nil, fmt.Errorf("error in historyRetention: %v", err)
+	}
+	if err = config.Validate(); err != nil {
+		return nil, err
+	}
+	config.Templates = expandHome(config.Templates)
+	config.Network.CABundle = expandHome(config.Network.CABundle)
+	config.Network.ClientCert = expandHome(config.Network.ClientCert)
+	config.Network.ClientKey = expandHome(config.Network.ClientKey)
+	return config, nil
+}
+
+// ResolveSecret returns the plain value of a secret reference:
+//
+//   - ${NAME} is replaced by the environment variable NAME;
+//   - file:<path> is replaced by the trimmed content of the file, ~ expands to
+//     the home directory;
+//   - ENC[age:<armored ciphertext>] is decrypted with the age binary and the
+//     identity in SIGNALHOUND_AGE_IDENTITY.
+//
+// Any other value is returned as is.
+func ResolveSecret(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, filePrefix):
+		path := expandHome(strings.TrimPrefix(value, filePrefix))
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("error reading secret file: %v", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	case strings.HasPrefix(value, agePrefix) && strings.HasSuffix(value, ageSuffix):
+		return decryptAge(strings.TrimSuffix(strings.TrimPrefix(value, agePrefix), ageSuffix))
+	}
+
+	var missing []string
+	resolved := envRegex.ReplaceAllStringFunc(value, func(match string) string {
+		name := envRegex.FindStringSubmatch(match)[1]
+		env, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+		}
+		return env
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variables not set: %s", strings.Join(missing, ", "))
+	}
+	return resolved, nil
+}
+
+// decryptAge decrypts an armored age ciphertext.
+func decryptAge(ciphertext string) (string, error) {
+	identity := os.Getenv(AgeIdentityEnv)
+	if identity == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return "", fmt.Errorf("%s not set: %v", AgeIdentityEnv, err)
+		}
+		identity = filepath.Join(dir, "sops", "age", "keys.txt")
+	}
+	// armored values are usually folded in a single line in YAML.
+	armored := strings.ReplaceAll(strings.TrimSpace(ciphertext), `\n`, "\n")
+	output, err := execCommand([]byte(armored+"\n"), "age", "--decrypt", "--identity", expandHome(identity))
+	if err != nil {
+		return "", fmt.Errorf("error decrypting age value: %v", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// isSOPSEncrypted returns true when the YAML document has the sops metadata.
+func isSOPSEncrypted(data []byte) bool {
+	var document map[string]interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return false
+	}
+	_, ok := document["sops"]
+	return ok
+}
+
+func expandHome(path string) string {
+	if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(path, "~/") {
+		return filepath.Join(home, path[2:])
+	}
+	return path
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveSecret(t *testing.T) {
+	t.Setenv("SIGNALHOUND_TEST_TOKEN", "env-token")
+	secretFile := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(secretFile, []byte("file-token\n"), 0o600))
+
+	stubExecCommand(t, func(stdin []byte, name string, args ...string) ([]byte, error) {
+		assert.Equal(t, "age", name)
+		assert.Equal(t, "-----BEGIN AGE ENCRYPTED FILE-----\nYWdl\n-----END AGE ENCRYPTED FILE-----\n", string(stdin))
+		return []byte("age-token\n"), nil
+	})
+	t.Setenv(AgeIdentityEnv, "/keys.txt")
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain value", value: "plain-token", want: "plain-token"},
+		{name: "environment variable", value: "${SIGNALHOUND_TEST_TOKEN}", want: "env-token"},
+		{name: "missing environment variable", value: "${SIGNALHOUND_MISSING_TOKEN}", wantErr: true},
+		{name: "file reference", value: "file:" + secretFile, want: "file-token"},
+		{name: "missing file", value: "file:/nonexistent/token", wantErr: true},
+		{
+			name:  "age encrypted value",
+			value: `ENC[age:-----BEGIN AGE ENCRYPTED FILE-----\nYWdl\n-----END AGE ENCRYPTED FILE-----]`,
+			want:  "age-token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveSecret(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("SIGNALHOUND_TEST_TOKEN", "env-token")
+
+	config, err := Load(filepath.Join(dir, "missing.yaml"))
+	assert.NoError(t, err)
+	assert.Empty(t, config.GitHubToken)
+
+	plain := filepath.Join(dir, "config.yaml")
+	assert.NoError(t, os.WriteFile(plain, []byte("githubToken: ${SIGNALHOUND_TEST_TOKEN}\n"), 0o600))
+	config, err = Load(plain)
+	assert.NoError(t, err)
+	assert.Equal(t, "env-token", config.GitHubToken)
+
+	slack := filepath.Join(dir, "slack.yaml")
+	assert.NoError(t, os.WriteFile(slack, []byte("slack:\n  mentions:\n    sig-release-master-blocking: ['@release-ci-signal']\n"+
+		"  sigMention: '@sig-{sig}-test-failures'\n  quietHours: {start: '22:00', end: '07:00', timeZone: UTC}\n"+
+		"  botToken: ${SIGNALHOUND_TEST_TOKEN}\n  channel: '#release-ci-signal'\n  sigChannels: {node: '#sig-node-ci'}\n"+
+		"  rateLimits: {'*': {messages: 5, window: 1h}}\n"), 0o600))
+	config, err = Load(slack)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"@release-ci-signal"}, config.Slack.Boards["sig-release-master-blocking"])
+	assert.Equal(t, "22:00", config.Slack.QuietHours.Start)
+	assert.Equal(t, "env-token", config.Slack.BotToken)
+	assert.Equal(t, "#release-ci-signal", config.Slack.Channel)
+	assert.Equal(t, "#sig-node-ci", config.Slack.SIGChannels["node"])
+	assert.Equal(t, time.Hour, config.Slack.RateLimits["*"].Window.Duration)
+	assert.NoError(t, os.WriteFile(slack, []byte("slack:\n  quietHours: {start: '10pm', end: '07:00'}\n"), 0o600))
+	_, err = Load(slack)
+	assert.Error(t, err)
+	assert.NoError(t, os.WriteFile(slack, []byte("slack:\n  rateLimits: {'#sig-node-ci': {messages: 0, window: 1h}}\n"), 0o600))
+	_, err = Load(slack)
+	assert.Error(t, err)
+
+	alerts := filepath.Join(dir, "alerts.yaml")
+	assert.NoError(t, os.WriteFile(alerts, []byte("flakeRateAlerts:\n- {rate: 0.3, window: 24h}\n"), 0o600))
+	config, err = Load(alerts)
+	assert.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, config.FlakeRateAlerts[0].Window.Duration)
+	assert.NoError(t, os.WriteFile(alerts, []byte("flakeRateAlerts:\n- {rate: 30, window: 24h}\n"), 0o600))
+	_, err = Load(alerts)
+	assert.Error(t, err)
+
+	defaults := filepath.Join(dir, "defaults.yaml")
+	assert.NoError(t, os.WriteFile(defaults, []byte("dashboards: [sig-release-1.35-blocking]\nminFailure: 2\nminFlake: 3\n"+
+		"refreshInterval: 5m\ntemplates: ~/templates\nincludeSyntheticRows: true\ngreenRuns: 3\n"+
+		"network: {proxy: 'http://proxy.example.com:3128', caBundle: ~/ca.pem}\n"), 0o600))
+	config, err = Load(defaults)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sig-release-1.35-blocking"}, config.Dashboards)
+	assert.Equal(t, 2, config.MinFailure)
+	assert.Equal(t, 3, config.MinFlake)
+	assert.Equal(t, 5*time.Minute, config.RefreshInterval.Duration)
+	home, _ := os.UserHomeDir()
+	assert.Equal(t, filepath.Join(home, "templates"), config.Templates)
+	assert.True(t, config.IncludeSyntheticRows)
+	assert.Equal(t, 3, config.GreenRuns)
+	assert.Equal(t, "http://proxy.example.com:3128", config.Network.Proxy)
+	assert.Equal(t, filepath.Join(home, "ca.pem"), config.Network.CABundle)
+	sigs := filepath.Join(dir, "sigs.yaml")
+	assert.NoError(t, os.WriteFile(sigs, []byte("sig:\n  sources: [overrides, tag, triage]\n  overrides: {'^k8s.io/kubernetes/pkg/volume': storage}\nhistoryRetention: {rawDays: 60}\nprojectFieldsTTL: 0s\n"), 0o600))
+	config, err = Load(sigs)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"overrides", "tag", "triage"}, config.SIG.Sources)
+	assert.Equal(t, "storage", config.SIG.Overrides["^k8s.io/kubernetes/pkg/volume"])
+	assert.Equal(t, 60, config.HistoryRetention.RawDays)
+	assert.Equal(t, time.Duration(0), config.ProjectFieldsTTL.Duration)
+
+	for _, invalid := range []string{"minFailure: -1\n", "refreshInterval: 1500ms\n", "greenRuns: -1\n",
+		"sig: {sources: [owners, slack]}\n", "sig: {sources: [tag, tag]}\n", "sig: {overrides: {'[': node}}\n",
+		"historyRetention: {rawDays: 7}\n", "projectFieldsTTL: -1m\n"} {
+		assert.NoError(t, os.WriteFile(defaults, []byte(invalid), 0o600))
+		_, err = Load(defaults)
+		assert.Error(t, err, invalid)
+	}
+
+	encrypted := filepath.Join(dir, "encrypted.yaml")
+	assert.NoError(t, os.WriteFile(encrypted, []byte("githubToken: ENC[AES256_GCM,data:abc]\nsops:\n  version: 3.9.0\n"), 0o600))
+	stubExecCommand(t, func(stdin []byte, name string, args ...string) ([]byte, error) {
+		assert.Equal(t, "sops", name)
+		assert.Equal(t, encrypted, args[len(args)-1])
+		return []byte("githubToken: sops-token\n"), nil
+	})
+	config, err = Load(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, "sops-token", config.GitHubToken)
+}
+
+func TestUseProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("dashboards: [sig-release-master-blocking]\nminFailure: 2\nprofile: lead\n"+
+		"slack:\n  mentions: {'*': ['@release-ci-signal']}\n  channel: '#release-ci-signal'\n"+
+		"profiles:\n  lead: {greenRuns: 5}\n"+
+		"  shadow: {dashboards: [sig-release-master-informing], slackChannel: '#ci-signal-shadows', mentions: false, readOnly: true}\n"+
+		"  invalid: {minFlake: -1}\n"), 0o600))
+
+	config, err := Load(path)
+	assert.NoError(t, err)
+	assert.NoError(t, config.UseProfile(""))
+	assert.Equal(t, "lead", config.Profile)
+	assert.Equal(t, 5, config.GreenRuns)
+	assert.Equal(t, []string{"sig-release-master-blocking"}, config.Dashboards)
+	assert.False(t, config.ReadOnly)
+
+	config, err = Load(path)
+	assert.NoError(t, err)
+	assert.NoError(t, config.UseProfile("shadow"))
+	assert.Equal(t, []string{"sig-release-master-informing"}, config.Dashboards)
+	assert.Equal(t, 2, config.MinFailure)
+	assert.Equal(t, "#ci-signal-shadows", config.Slack.Channel)
+	assert.Empty(t, config.Slack.Boards)
+	assert.True(t, config.ReadOnly)
+
+	config, err = Load(path)
+	assert.NoError(t, err)
+	assert.ErrorContains(t, config.UseProfile("sig-node"), `unknown profile "sig-node", expected one of invalid, lead, shadow`)
+	assert.Error(t, config.UseProfile("invalid"))
+	assert.ErrorContains(t, (&Config{}).UseProfile("lead"), "no profiles")
+	assert.NoError(t, (&Config{}).UseProfile(""))
+}
+
+func stubExecCommand(t *testing.T, stub func(stdin []byte, name string, args ...string) ([]byte, error)) {
+	previous := execCommand
+	execCommand = stub
+	t.Cleanup(func() { execCommand = previous })
+}
+
+func TestDefaultPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	assert.True(t, strings.HasSuffix(DefaultPath(), filepath.Join("signalhound", "config.yaml")))
+
+	// the home file is only read when the configuration directory has none
+	assert.NoError(t, os.WriteFile(filepath.Join(home, ".signalhound.yaml"), nil, 0o600))
+	assert.Equal(t, filepath.Join(home, ".signalhound.yaml"), DefaultPath())
+	dir, err := os.UserConfigDir()
+	assert.NoError(t, err)
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "signalhound"), 0o700))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "signalhound", "config.yaml"), nil, 0o600))
+	assert.Equal(t, filepath.Join(dir, "signalhound", "config.yaml"), DefaultPath())
+}
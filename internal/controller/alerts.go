@@ -0,0 +1,174 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	testgridv1alpha1 "sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/notify"
+)
+
+// AlertNotifier notifies the targets of the AlertRule resources when a tab
+// enters one of the states of a rule, once per state change.
+type AlertNotifier struct {
+	client.Client
+
+	// Secrets reads the URLs of the targets, the uncached API reader of the
+	// manager needing only get on the secrets. The Client when nil.
+	Secrets client.Reader
+
+	// SMTP is the mail server of the email targets, nil fails them.
+	SMTP *notify.SMTP
+
+	// states are the last notified state of every rule, board hash and
+	// target, see targetKey. A tab is notified again only after leaving the
+	// state, the targets failing to notify it are retried at the next call.
+	mu     sync.Mutex
+	states map[string]string
+}
+
+// +kubebuilder:rbac:groups=testgrid.holdmybeer.io,resources=alertrules,verbs=get;list;watch
+// +kubebuilder:rbac:groups=testgrid.holdmybeer.io,resources=alertrules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+
+// Notify sends the alerts of the tab to the targets of the rules of the
+// namespace matching it, and records the outcome on the rule status.
+func (a *AlertNotifier) Notify(ctx context.Context, namespace string, dashSummary *testgridv1alpha1.DashboardSummary, tab *testgridv1alpha1.DashboardTab) error {
+	var rules testgridv1alpha1.AlertRuleList
+	if err := a.List(ctx, &rules, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("error listing the alert rules: %v", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.states == nil {
+		a.states = map[string]string{}
+	}
+
+	var errs []error
+	for i := range rules.Items {
+		rule := &rules.Items[i]
+		key := rule.Namespace + "/" + rule.Name + "/" + tab.BoardHash
+		if !matchesRule(&rule.Spec, dashSummary.DashboardName, tab) {
+			for i := range rule.Spec.Targets {
+				delete(a.states, targetKey(key, i))
+			}
+			continue
+		}
+		var pending []int
+		for i := range rule.Spec.Targets {
+			if a.states[targetKey(key, i)] != tab.TabState {
+				pending = append(pending, i)
+			}
+		}
+		if len(pending) == 0 {
+			continue
+		}
+
+		alert := &notify.Alert{
+			Rule: rule.Namespace + "/" + rule.Name, Dashboard: dashSummary.DashboardName, Tab: tab.TabName,
+			BoardHash: tab.BoardHash, TabURL: tab.TabURL, State: tab.TabState,
+		}
+		for _, test := range tab.TestRuns {
+			alert.Tests = append(alert.Tests, test.TestName)
+		}
+		var ruleErrs []error
+		for _, i := range pending {
+			target := &rule.Spec.Targets[i]
+			notifier, err := a.notifier(ctx, rule.Namespace, target)
+			if err == nil {
+				err = notifier.Notify(ctx, alert)
+			}
+			if err != nil {
+				ruleErrs = append(ruleErrs, fmt.Errorf("%s target: %v", target.Type, err))
+				continue
+			}
+			a.states[targetKey(key, i)] = tab.TabState
+		}
+
+		rule.Status.LastError = ""
+		if err := errors.Join(ruleErrs...); err != nil {
+			rule.Status.LastError = err.Error()
+			errs = append(errs, fmt.Errorf("error notifying the rule %s: %v", alert.Rule, err))
+		}
+		if len(ruleErrs) < len(pending) {
+			rule.Status.LastNotified = metav1.Now()
+		}
+		if err := a.Status().Update(ctx, rule); err != nil {
+			errs = append(errs, fmt.Errorf("error updating the rule %s status: %v", alert.Rule, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// targetKey returns the key of the notified state of the target at index i
+// of the rule and board hash key.
+func targetKey(key string, i int) string {
+	return fmt.Sprintf("%s/%d", key, i)
+}
+
+// notifier returns the notifier of the target, reading its URL from the
+// Secret of the rule namespace when set.
+func (a *AlertNotifier) notifier(ctx context.Context, namespace string, target *testgridv1alpha1.AlertTarget) (notify.Notifier, error) {
+	if target.Type == testgridv1alpha1.EmailTarget {
+		return &notify.Email{Server: a.SMTP, To: target.To}, nil
+	}
+
+	url := target.URL
+	if ref := target.URLSecretRef; ref != nil {
+		reader := a.Secrets
+		if reader == nil {
+			reader = a.Client
+		}
+		var secret corev1.Secret
+		if err := reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, &secret); err != nil {
+			return nil, fmt.Errorf("error reading the secret %s: %v", ref.Name, err)
+		}
+		url = string(secret.Data[ref.Key])
+	}
+	if url == "" {
+		return nil, errors.New("no URL, set url or urlSecretRef")
+	}
+	if target.Type == testgridv1alpha1.SlackTarget {
+		return &notify.SlackWebhook{URL: url}, nil
+	}
+	return &notify.Webhook{URL: url}, nil
+}
+
+// matchesRule returns if the tab of the dashboard is in a state of the rule,
+// FAILING or FLAKY when the rule has no states.
+func matchesRule(spec *testgridv1alpha1.AlertRuleSpec, dashboardName string, tab *testgridv1alpha1.DashboardTab) bool {
+	if len(spec.Dashboards) > 0 && !slices.Contains(spec.Dashboards, dashboardName) {
+		return false
+	}
+	if len(spec.Tabs) > 0 && !slices.Contains(spec.Tabs, tab.TabName) {
+		return false
+	}
+	if len(spec.States) == 0 {
+		return slices.Contains(testgridv1alpha1.ERROR_STATUSES, tab.TabState)
+	}
+	return slices.Contains(spec.States, testgridv1alpha1.ErrorState(tab.TabState))
+}
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	testgridv1alpha1 "sigs.k8s.io/signalhound/api/v1alpha1"
+)
+
+var _ = Describe("Alert Notifier", func() {
+	var (
+		ctx     = context.Background()
+		summary = &testgridv1alpha1.DashboardSummary{DashboardName: "sig-release-master-blocking"}
+	)
+
+	newClient := func(objects ...client.Object) client.Client {
+		scheme := runtime.NewScheme()
+		Expect(testgridv1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithStatusSubresource(&testgridv1alpha1.AlertRule{}).Build()
+	}
+	ruleStatus := func(c client.Client, name string) testgridv1alpha1.AlertRuleStatus {
+		var updated testgridv1alpha1.AlertRule
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: "default", Name: name}, &updated)).To(Succeed())
+		return updated.Status
+	}
+
+	It("should notify the targets once per state change", func() {
+		var alerts []map[string]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var alert map[string]interface{}
+			Expect(json.NewDecoder(r.Body).Decode(&alert)).To(Succeed())
+			alerts = append(alerts, alert)
+		}))
+		defer server.Close()
+
+		rule := &testgridv1alpha1.AlertRule{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "blocking"},
+			Spec: testgridv1alpha1.AlertRuleSpec{
+				Dashboards: []string{"sig-release-master-blocking"},
+				States:     []testgridv1alpha1.ErrorState{testgridv1alpha1.FAILING_STATUS},
+				Targets: []testgridv1alpha1.AlertTarget{{
+					Type:         testgridv1alpha1.WebhookTarget,
+					URLSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "alerts"}, Key: "url"},
+				}},
+			},
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "alerts"},
+			Data:       map[string][]byte{"url": []byte(server.URL)},
+		}
+		c := newClient(rule, secret)
+		notifier := &AlertNotifier{Client: c}
+
+		tab := &testgridv1alpha1.DashboardTab{
+			TabName: "gce", BoardHash: "sig-release-master-blocking#gce", TabState: testgridv1alpha1.FAILING_STATUS,
+			TestRuns: []testgridv1alpha1.TestResult{{TestName: "[sig-node] Pods should run"}},
+		}
+		Expect(notifier.Notify(ctx, "default", summary, tab)).To(Succeed())
+		By("leaving the tab still failing unnotified")
+		Expect(notifier.Notify(ctx, "default", summary, tab)).To(Succeed())
+		Expect(alerts).To(HaveLen(1))
+		Expect(alerts[0]["rule"]).To(Equal("default/blocking"))
+		Expect(alerts[0]["tests"]).To(Equal([]interface{}{"[sig-node] Pods should run"}))
+
+		status := ruleStatus(c, "blocking")
+		Expect(status.LastNotified.IsZero()).To(BeFalse())
+		Expect(status.LastError).To(BeEmpty())
+
+		By("notifying the tab failing again after leaving the rule")
+		tab.TabState = testgridv1alpha1.FLAKY_STATUS
+		Expect(notifier.Notify(ctx, "default", summary, tab)).To(Succeed())
+		tab.TabState = testgridv1alpha1.FAILING_STATUS
+		Expect(notifier.Notify(ctx, "default", summary, tab)).To(Succeed())
+		Expect(alerts).To(HaveLen(2))
+
+		By("ignoring the tabs of the other dashboards")
+		other := &testgridv1alpha1.DashboardSummary{DashboardName: "sig-release-master-informing"}
+		otherTab := &testgridv1alpha1.DashboardTab{TabName: "kind", BoardHash: "sig-release-master-informing#kind", TabState: testgridv1alpha1.FAILING_STATUS}
+		Expect(notifier.Notify(ctx, "default", other, otherTab)).To(Succeed())
+		Expect(alerts).To(HaveLen(2))
+	})
+
+	It("should retry the targets failing to notify at the next call", func() {
+		var delivered, failed int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			delivered++
+		}))
+		defer server.Close()
+		flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if failed == 0 {
+				failed++
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			delivered++
+		}))
+		defer flaky.Close()
+
+		rule := &testgridv1alpha1.AlertRule{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "webhooks"},
+			Spec: testgridv1alpha1.AlertRuleSpec{Targets: []testgridv1alpha1.AlertTarget{
+				{Type: testgridv1alpha1.WebhookTarget, URL: server.URL},
+				{Type: testgridv1alpha1.WebhookTarget, URL: flaky.URL},
+			}},
+		}
+		c := newClient(rule)
+		notifier := &AlertNotifier{Client: c}
+
+		tab := &testgridv1alpha1.DashboardTab{TabName: "gce", BoardHash: "sig-release-master-blocking#gce", TabState: testgridv1alpha1.FAILING_STATUS}
+		Expect(notifier.Notify(ctx, "default", summary, tab)).To(MatchError(ContainSubstring("webhook target")))
+		Expect(delivered).To(Equal(1))
+		Expect(ruleStatus(c, "webhooks").LastError).NotTo(BeEmpty())
+
+		By("notifying only the failed target again")
+		Expect(notifier.Notify(ctx, "default", summary, tab)).To(Succeed())
+		Expect(delivered).To(Equal(2))
+		Expect(ruleStatus(c, "webhooks").LastError).To(BeEmpty())
+
+		By("leaving the tab notified to every target alone")
+		Expect(notifier.Notify(ctx, "default", summary, tab)).To(Succeed())
+		Expect(delivered).To(Equal(2))
+	})
+
+	It("should record the target errors on the rule status", func() {
+		rule := &testgridv1alpha1.AlertRule{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "email"},
+			Spec: testgridv1alpha1.AlertRuleSpec{Targets: []testgridv1alpha1.AlertTarget{
+				{Type: testgridv1alpha1.EmailTarget, To: []string{"release-team@example.com"}},
+				{Type: testgridv1alpha1.SlackTarget},
+			}},
+		}
+		c := newClient(rule)
+
+		tab := &testgridv1alpha1.DashboardTab{TabName: "gce", BoardHash: "sig-release-master-blocking#gce", TabState: testgridv1alpha1.FLAKY_STATUS}
+		err := (&AlertNotifier{Client: c}).Notify(ctx, "default", summary, tab)
+		Expect(err).To(MatchError(ContainSubstring("email target: error sending the alert: no SMTP server")))
+		Expect(err).To(MatchError(ContainSubstring("slack target: no URL")))
+
+		status := ruleStatus(c, "email")
+		Expect(status.LastNotified.IsZero()).To(BeTrue())
+		Expect(status.LastError).To(ContainSubstring("no SMTP server"))
+	})
+})
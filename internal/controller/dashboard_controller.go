@@ -18,29 +18,30 @@ package controller
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	testgridv1alpha1 "sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/monitoring"
 	"sigs.k8s.io/signalhound/internal/testgrid"
+	"sigs.k8s.io/signalhound/pkg/pipeline"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
-
-	"go.opentelemetry.io/otel/exporters/prometheus"
-	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
-	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
-const meterName = "signalhound"
-
 // Metrics holds OpenTelemetry metric instruments
 type Metrics struct {
 	dashboardStateGauge metric.Int64Gauge
@@ -50,87 +51,81 @@ type Metrics struct {
 	totalTestFailures   metric.Int64Gauge
 	totalTestFlakes     metric.Int64Gauge
 	testFailuresCounter metric.Int64Counter
+	healthScoreGauge    metric.Int64Gauge
 }
 
 // globalMetrics holds the initialized metrics
 var globalMetrics *Metrics
 
-func init() {
-	exporter, err := prometheus.New(
-		prometheus.WithRegisterer(metrics.Registry),
-	)
-	if err != nil {
-		panic(err)
-	}
+// defaultRefreshInterval is the minimum time between two updates of the
+// status of a Dashboard without refreshInterval nor schedule.
+const defaultRefreshInterval = time.Minute
 
-	provider := sdkmetric.NewMeterProvider(
-		sdkmetric.WithReader(exporter),
-	)
-	otel.SetMeterProvider(provider)
-}
+// staleAfter is the time without TestGrid update of the failing and flaky
+// tabs after which a dashboard is Stale.
+const staleAfter = 24 * time.Hour
 
-// initMetrics initializes OpenTelemetry metrics
+// minFetchBackoff is the delay before the first retry of a failed fetch.
+const minFetchBackoff = 5 * time.Second
+
+// requeueJitter is the maximum jitter factor added to the requeue delays,
+// spreading the fetches of the dashboards created together.
+const requeueJitter = 0.1
+
+// initMetrics initializes OpenTelemetry metrics from the monitoring
+// definitions, which also generate the ServiceMonitor and Grafana dashboard.
 func initMetrics() error {
-	meter := otel.Meter(meterName)
+	if err := monitoring.Setup(); err != nil {
+		return err
+	}
+	meter := otel.Meter(monitoring.MeterName)
 
-	dashboardStateGauge, err := meter.Int64Gauge(
-		"testgrid_dashboard_state",
-		metric.WithDescription("Current state of testgrid dashboard (1 = active state)"),
-		metric.WithUnit("1"),
-	)
+	gauge := func(definition monitoring.Definition) (metric.Int64Gauge, error) {
+		return meter.Int64Gauge(definition.Name,
+			metric.WithDescription(definition.Description),
+			metric.WithUnit(definition.Unit),
+		)
+	}
+
+	dashboardStateGauge, err := gauge(monitoring.DashboardState)
 	if err != nil {
 		return err
 	}
 
-	tabStateGauge, err := meter.Int64Gauge(
-		"testgrid_tab_state",
-		metric.WithDescription("State of testgrid dashboard tab"),
-		metric.WithUnit("1"),
-	)
+	tabStateGauge, err := gauge(monitoring.TabState)
 	if err != nil {
 		return err
 	}
 
-	lastRunTimestamp, err := meter.Int64Gauge(
-		"testgrid_dashboard_last_run_timestamp",
-		metric.WithDescription("Unix timestamp of the last test run for a dashboard tab"),
-		metric.WithUnit("s"),
-	)
+	lastRunTimestamp, err := gauge(monitoring.LastRunTimestamp)
 	if err != nil {
 		return err
 	}
 
-	lastUpdateTimestamp, err := meter.Int64Gauge(
-		"testgrid_dashboard_last_update_timestamp",
-		metric.WithDescription("Unix timestamp of the last update for a dashboard tab"),
-		metric.WithUnit("s"),
-	)
+	lastUpdateTimestamp, err := gauge(monitoring.LastUpdateTimestamp)
 	if err != nil {
 		return err
 	}
 
-	totalTestFailures, err := meter.Int64Gauge(
-		"testgrid_test_failures_total",
-		metric.WithDescription("Total number of failing tests in a dashboard tab"),
-		metric.WithUnit("1"),
-	)
+	totalTestFailures, err := gauge(monitoring.TotalTestFailures)
 	if err != nil {
 		return err
 	}
 
-	totalTestFlakes, err := meter.Int64Gauge(
-		"testgrid_test_flakes_total",
-		metric.WithDescription("Total number of flaky tests in a dashboard tab"),
-		metric.WithUnit("1"),
-	)
+	totalTestFlakes, err := gauge(monitoring.TotalTestFlakes)
+	if err != nil {
+		return err
+	}
+
+	healthScoreGauge, err := gauge(monitoring.ReleaseHealthScore)
 	if err != nil {
 		return err
 	}
 
 	testFailuresCounter, err := meter.Int64Counter(
-		"testgrid_individual_test_failures_total",
-		metric.WithDescription("Counter of failures for individual tests"),
-		metric.WithUnit("1"),
+		monitoring.TestFailures.Name,
+		metric.WithDescription(monitoring.TestFailures.Description),
+		metric.WithUnit(monitoring.TestFailures.Unit),
 	)
 	if err != nil {
 		return err
@@ -144,6 +139,7 @@ func initMetrics() error {
 		totalTestFailures:   totalTestFailures,
 		totalTestFlakes:     totalTestFlakes,
 		testFailuresCounter: testFailuresCounter,
+		healthScoreGauge:    healthScoreGauge,
 	}
 
 	return nil
@@ -154,6 +150,22 @@ type DashboardReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
 	log    logr.Logger
+
+	// Resolver resolves the GitHub issues of the recovered tests of the
+	// fetched tabs, nil leaves the issues as they are.
+	Resolver *pipeline.ProjectResolver
+
+	// TestGrid fetches the dashboards, the client of testgrid.URL when nil.
+	TestGrid testgrid.TestGridInterface
+
+	// Alerts notifies the targets of the AlertRule resources of the fetched
+	// tabs, nil disables the alerts.
+	Alerts *AlertNotifier
+
+	// healthTabs are the last fetched tabs of every dashboard, the health
+	// score of a release is computed across its dashboards.
+	healthMu   sync.Mutex
+	healthTabs map[string][]*testgridv1alpha1.DashboardTab
 }
 
 // +kubebuilder:rbac:groups=testgrid.holdmybeer.io,resources=dashboards,verbs=get;list;watch;create;update;patch;delete
@@ -165,7 +177,7 @@ func (r *DashboardReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	r.log = logf.FromContext(ctx).WithValues("resource", req.NamespacedName)
 
 	// Create a span for tracing
-	tracer := otel.Tracer(meterName)
+	tracer := otel.Tracer(monitoring.MeterName)
 	ctx, span := tracer.Start(ctx, "DashboardReconcile")
 	defer span.End()
 
@@ -181,51 +193,178 @@ func (r *DashboardReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	grid := testgrid.NewTestGrid(testgrid.URL)
+	var schedule *cronSchedule
+	if dashboard.Spec.Schedule != "" {
+		var err error
+		if schedule, err = parseSchedule(dashboard.Spec.Schedule); err != nil {
+			// the refresh interval is used until the schedule is fixed
+			r.log.Error(err, "ignoring the dashboard schedule")
+			span.RecordError(err)
+		}
+	}
+
+	grid := r.TestGrid
+	if grid == nil {
+		grid = testgrid.NewTestGrid(testgrid.URL)
+	}
 	dashboardSummaries, err := grid.FetchTabSummary(dashboard.Spec.DashboardTab, testgridv1alpha1.ERROR_STATUSES)
 	if err != nil {
 		r.log.Error(err, "error fetching summary from endpoint.")
 		span.RecordError(err)
-		return ctrl.Result{}, err
+
+		// the failure is recorded on the status and retried with a backoff
+		// instead of the rate limiter of the queue
+		dashboard.Status.FetchFailures++
+		setConditions(&dashboard, testgridv1alpha1.SummaryFetchFailedReason, err, time.Now())
+		if err := r.Status().Update(ctx, &dashboard); err != nil {
+			r.log.Error(err, "unable to update dashboard status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: fetchBackoff(&dashboard.Spec, dashboard.Status.FetchFailures)}, nil
 	}
 
 	span.SetAttributes(attribute.Int("summaries.count", len(dashboardSummaries)))
 
-	// set the dashboard summary on status if an update happened
-	if r.shouldRefresh(dashboard.Status, dashboardSummaries) {
+	// set the dashboard summary on status if an update happened or was requested
+	now := time.Now()
+	if r.shouldRefresh(&dashboard.Spec, schedule, dashboard.Status, dashboardSummaries, now) || reconcileRequested(&dashboard) {
 		dashboard.Status.DashboardSummary = dashboardSummaries
 		dashboard.Status.LastUpdate = metav1.Now()
+		dashboard.Status.OverallState, dashboard.Status.FailingTabs = overallState(dashboardSummaries)
 
-		r.log.Info("updating dashboard object status.")
-		if err := r.Status().Update(ctx, &dashboard); err != nil {
-			r.log.Error(err, "unable to update dashboard status")
-			span.RecordError(err)
-			return ctrl.Result{}, err
-		}
-
+		var tabs []*testgridv1alpha1.DashboardTab
+		var tabErr error
 		for _, dashSummary := range dashboardSummaries {
 			tabName := dashSummary.DashboardTab.TabName
 
 			var tab *testgridv1alpha1.DashboardTab
-			if tab, err = grid.FetchTabTests(&dashSummary, dashboard.Spec.MinFlakes, dashboard.Spec.MinFailures); err != nil {
+			if tab, err = grid.FetchTabTests(&dashSummary, dashboard.Spec.MinFailures, dashboard.Spec.MinFlakes); err != nil {
 				r.log.Error(err, "error fetching table", "tab", tabName)
 				span.RecordError(err)
+				tabErr = fmt.Errorf("error fetching the tab %s: %v", tabName, err)
 				continue
 			}
 
 			// record metrics for this tab summary
-			r.recordMetrics(ctx, &dashSummary, tab)
+			r.recordMetrics(ctx, req.Namespace, &dashSummary, tab)
+			r.resolveIssues(tab)
+			tabs = append(tabs, tab)
+		}
+		r.recordHealthScore(ctx, dashboard.Spec.DashboardTab, tabs)
+
+		dashboard.Status.FetchFailures = 0
+		if tabErr != nil {
+			setConditions(&dashboard, testgridv1alpha1.TabFetchFailedReason, tabErr, now)
+		} else {
+			setConditions(&dashboard, testgridv1alpha1.FetchSucceededReason, nil, now)
+		}
+		r.log.Info("updating dashboard object status.")
+		if err := r.Status().Update(ctx, &dashboard); err != nil {
+			r.log.Error(err, "unable to update dashboard status")
+			span.RecordError(err)
+			return ctrl.Result{}, err
+		}
+	} else if changed := setConditions(&dashboard, testgridv1alpha1.FetchSucceededReason, nil, now); changed || dashboard.Status.FetchFailures > 0 {
+		// the fetch recovered or the tabs became stale without a new summary
+		// to record
+		dashboard.Status.FetchFailures = 0
+		if err := r.Status().Update(ctx, &dashboard); err != nil {
+			r.log.Error(err, "unable to update dashboard status")
+			span.RecordError(err)
+			return ctrl.Result{}, err
 		}
 	}
 
 	r.log.V(1).Info("reconciliation completed successfully")
 	span.SetAttributes(attribute.Bool("reconcile.success", true))
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: wait.Jitter(requeueAfter(&dashboard.Spec, schedule, now), requeueJitter)}, nil
 }
 
-// recordMetrics records OpenTelemetry metrics for testgrid dashboard failures and flakes
-func (r *DashboardReconciler) recordMetrics(ctx context.Context, dashSummary *testgridv1alpha1.DashboardSummary, tab *testgridv1alpha1.DashboardTab) {
+// setConditions sets the Ready and FetchFailed conditions of the dashboard
+// from the error of the fetch, and the Stale condition from the summary of
+// the status. It returns if a condition changed.
+func setConditions(dashboard *testgridv1alpha1.Dashboard, reason string, err error, now time.Time) bool {
+	ready := metav1.Condition{
+		Type:    testgridv1alpha1.ReadyCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: "the dashboard was fetched from testgrid",
+	}
+	fetchFailed := metav1.Condition{
+		Type:    testgridv1alpha1.FetchFailedCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: ready.Message,
+	}
+	if err != nil {
+		ready.Status, ready.Message = metav1.ConditionFalse, err.Error()
+		fetchFailed.Status, fetchFailed.Message = metav1.ConditionTrue, err.Error()
+	}
+
+	stale := metav1.Condition{
+		Type:    testgridv1alpha1.StaleCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  testgridv1alpha1.TabsUpdatedReason,
+		Message: fmt.Sprintf("the failing and flaky tabs were updated by testgrid within %s", staleAfter),
+	}
+	var lastUpdate int64
+	for _, summary := range dashboard.Status.DashboardSummary {
+		lastUpdate = max(lastUpdate, summary.LastUpdateTime)
+	}
+	if updated := time.Unix(lastUpdate, 0); lastUpdate > 0 && now.Sub(updated) > staleAfter {
+		stale.Status, stale.Reason = metav1.ConditionTrue, testgridv1alpha1.TabsNotUpdatedReason
+		stale.Message = fmt.Sprintf("the failing and flaky tabs were last updated by testgrid on %s", updated.UTC().Format(time.RFC3339))
+	}
+
+	var changed bool
+	for _, condition := range []metav1.Condition{ready, fetchFailed, stale} {
+		condition.ObservedGeneration = dashboard.Generation
+		if meta.SetStatusCondition(&dashboard.Status.Conditions, condition) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// overallState returns the worst state of the summaries and the number of
+// failing tabs.
+func overallState(summaries []testgridv1alpha1.DashboardSummary) (state string, failing int) {
+	state = testgridv1alpha1.PASSING_STATUS
+	for _, summary := range summaries {
+		switch summary.OverallState {
+		case testgridv1alpha1.FAILING_STATUS:
+			state = testgridv1alpha1.FAILING_STATUS
+			failing++
+		case testgridv1alpha1.FLAKY_STATUS:
+			if state == testgridv1alpha1.PASSING_STATUS {
+				state = testgridv1alpha1.FLAKY_STATUS
+			}
+		}
+	}
+	return state, failing
+}
+
+// fetchBackoff returns the delay before retrying a failed fetch, doubling
+// from minFetchBackoff with the consecutive failures up to the refresh
+// interval of the dashboard.
+func fetchBackoff(spec *testgridv1alpha1.DashboardSpec, failures int) time.Duration {
+	backoff, limit := minFetchBackoff, refreshInterval(spec)
+	for i := 1; i < failures && backoff < limit; i++ {
+		backoff *= 2
+	}
+	return wait.Jitter(min(backoff, limit), requeueJitter)
+}
+
+// recordMetrics records OpenTelemetry metrics for testgrid dashboard failures and flakes,
+// and sends the alerts of the tab to the AlertRule targets of the namespace.
+func (r *DashboardReconciler) recordMetrics(ctx context.Context, namespace string, dashSummary *testgridv1alpha1.DashboardSummary, tab *testgridv1alpha1.DashboardTab) {
+	if r.Alerts != nil {
+		if err := r.Alerts.Notify(ctx, namespace, dashSummary, tab); err != nil {
+			r.log.Error(err, "unable to send the alerts", "tab", tab.TabName)
+		}
+	}
+
 	if globalMetrics == nil {
 		r.log.Error(nil, "metrics not initialized")
 		return
@@ -287,16 +426,94 @@ func (r *DashboardReconciler) recordMetrics(ctx context.Context, dashSummary *te
 		"tests", len(tab.TestRuns))
 }
 
-// shouldRefresh determines if it's time to refresh the dashboard data
-func (r *DashboardReconciler) shouldRefresh(dashboardStatus testgridv1alpha1.DashboardStatus, summary []testgridv1alpha1.DashboardSummary) bool {
+// recordHealthScore keeps the fetched tabs of the dashboard and records the
+// health score of its release from the tabs of every dashboard of the
+// release, without the critical issues.
+func (r *DashboardReconciler) recordHealthScore(ctx context.Context, dashboardName string, tabs []*testgridv1alpha1.DashboardTab) {
+	if globalMetrics == nil {
+		return
+	}
+	r.healthMu.Lock()
+	defer r.healthMu.Unlock()
+	if r.healthTabs == nil {
+		r.healthTabs = map[string][]*testgridv1alpha1.DashboardTab{}
+	}
+	r.healthTabs[dashboardName] = tabs
+
+	releases := pipeline.Releases([]string{dashboardName})
+	if len(releases) == 0 {
+		return
+	}
+	var dashboards []string
+	var releaseTabs []*testgridv1alpha1.DashboardTab
+	for name, tabs := range r.healthTabs {
+		if slices.Equal(pipeline.Releases([]string{name}), releases) {
+			dashboards = append(dashboards, name)
+			releaseTabs = append(releaseTabs, tabs...)
+		}
+	}
+	for _, health := range pipeline.HealthScores(dashboards, releaseTabs, nil) {
+		globalMetrics.healthScoreGauge.Record(ctx, int64(health.Score),
+			metric.WithAttributes(attribute.String("release", health.Release)))
+		r.log.V(1).Info("recorded health score", "release", health.Release, "score", health.Score)
+	}
+}
+
+// resolveIssues resolves the issues of the recovered tests of the tab, the
+// errors are logged and retried on the next refresh.
+func (r *DashboardReconciler) resolveIssues(tab *testgridv1alpha1.DashboardTab) {
+	if r.Resolver == nil {
+		return
+	}
+	for _, finding := range pipeline.NewAnalyzer().Analyze([]*testgridv1alpha1.DashboardTab{tab}) {
+		resolved, err := r.Resolver.Resolve(finding)
+		if err != nil {
+			r.log.Error(err, "unable to resolve the issue", "test", finding.Test.TestName)
+			continue
+		}
+		if resolved != nil {
+			r.log.Info("resolved the issue of a recovered test", "issue", resolved.Number, "test", finding.Test.TestName, "board", tab.BoardHash)
+		}
+	}
+}
+
+// shouldRefresh determines if it's time to refresh the dashboard data: the
+// summary changed and a scheduled time passed since the last update, or the
+// refresh interval without schedule.
+func (r *DashboardReconciler) shouldRefresh(spec *testgridv1alpha1.DashboardSpec, schedule *cronSchedule,
+	dashboardStatus testgridv1alpha1.DashboardStatus, summary []testgridv1alpha1.DashboardSummary, now time.Time) bool {
 	if reflect.DeepEqual(dashboardStatus.DashboardSummary, summary) {
 		return false
 	}
 	if dashboardStatus.LastUpdate.IsZero() {
 		return true
 	}
-	refreshInterval := time.Duration(1) * time.Minute // should at least wait for 1 minute
-	return time.Since(dashboardStatus.LastUpdate.Time) >= refreshInterval
+	if schedule != nil {
+		next := schedule.Next(dashboardStatus.LastUpdate.Time)
+		return !next.IsZero() && !next.After(now)
+	}
+	return now.Sub(dashboardStatus.LastUpdate.Time) >= refreshInterval(spec)
+}
+
+// refreshInterval returns the refresh interval of the dashboard, at least
+// defaultRefreshInterval when unset.
+func refreshInterval(spec *testgridv1alpha1.DashboardSpec) time.Duration {
+	if spec.RefreshInterval == nil || spec.RefreshInterval.Duration <= 0 {
+		return defaultRefreshInterval
+	}
+	return spec.RefreshInterval.Duration
+}
+
+// requeueAfter returns the delay of the next reconciliation of the dashboard,
+// the next scheduled time or its refresh interval, so the dashboard is polled
+// again without changes.
+func requeueAfter(spec *testgridv1alpha1.DashboardSpec, schedule *cronSchedule, now time.Time) time.Duration {
+	if schedule != nil {
+		if next := schedule.Next(now); !next.IsZero() {
+			return next.Sub(now)
+		}
+	}
+	return refreshInterval(spec)
 }
 
 // SetupWithManager sets up the controller with the Manager.
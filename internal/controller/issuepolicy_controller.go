@@ -0,0 +1,263 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	testgridv1alpha1 "sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/issue"
+	"sigs.k8s.io/signalhound/internal/testgrid"
+	"sigs.k8s.io/signalhound/pkg/pipeline"
+)
+
+// defaultPolicyInterval is the time between two evaluations of an
+// IssuePolicy without refreshInterval.
+const defaultPolicyInterval = 15 * time.Minute
+
+// defaultMinConsecutiveFailures is the failure streak of a policy without
+// minConsecutiveFailures, the CRD default when the API server doesn't set it.
+const defaultMinConsecutiveFailures = 2
+
+// IssuePolicyReconciler opens the GitHub issues of the tests matched by the
+// IssuePolicy resources, and comments on them when the state of their tab
+// changes.
+type IssuePolicyReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Manager opens and comments on the issues.
+	Manager github.ProjectManagerInterface
+
+	// ReopenWindow reopens the issue of a test closed within the window
+	// instead of opening a new one, zero disables it.
+	ReopenWindow time.Duration
+
+	// TestGrid fetches the boards, the client of testgrid.URL when nil.
+	TestGrid testgrid.TestGridInterface
+}
+
+// +kubebuilder:rbac:groups=testgrid.holdmybeer.io,resources=issuepolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=testgrid.holdmybeer.io,resources=issuepolicies/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=testgrid.holdmybeer.io,resources=issuepolicies/finalizers,verbs=update
+
+// Reconcile evaluates the policy against its boards and records the issues
+// of the matched tests on its status.
+func (r *IssuePolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx).WithValues("resource", req.NamespacedName)
+
+	var policy testgridv1alpha1.IssuePolicy
+	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	tabs, err := r.fetchTabs(&policy.Spec)
+	if err != nil {
+		log.Error(err, "error fetching the policy boards")
+		return ctrl.Result{}, err
+	}
+	now := time.Now()
+	policy.Status.Issues = r.applyPolicy(log, &policy, tabs, now)
+	policy.Status.LastEvaluated = metav1.NewTime(now)
+	if err := r.Status().Update(ctx, &policy); err != nil {
+		log.Error(err, "unable to update issue policy status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: policyInterval(&policy.Spec)}, nil
+}
+
+// fetchTabs returns the tabs of the policy boards in one of its states.
+func (r *IssuePolicyReconciler) fetchTabs(spec *testgridv1alpha1.IssuePolicySpec) ([]*testgridv1alpha1.DashboardTab, error) {
+	grid := r.TestGrid
+	if grid == nil {
+		grid = testgrid.NewTestGrid(testgrid.URL)
+	}
+	var tabs []*testgridv1alpha1.DashboardTab
+	for _, board := range spec.Boards {
+		summaries, err := grid.FetchTabSummary(board, policyStates(spec))
+		if err != nil {
+			return nil, err
+		}
+		for i := range summaries {
+			tab, err := grid.FetchTabTests(&summaries[i], 0, 0)
+			if err != nil {
+				return nil, err
+			}
+			tabs = append(tabs, tab)
+		}
+	}
+	return tabs, nil
+}
+
+// applyPolicy opens the issue of every matched test without one and
+// comments on the issue of a test whose tab changed state. It returns the
+// issues of the matched tests, the tests matched no more are dropped so a
+// later regression is handled again. The GitHub errors are logged and
+// retried on the next evaluation.
+func (r *IssuePolicyReconciler) applyPolicy(log logr.Logger, policy *testgridv1alpha1.IssuePolicy,
+	tabs []*testgridv1alpha1.DashboardTab, now time.Time) []testgridv1alpha1.PolicyIssue {
+	var issues []testgridv1alpha1.PolicyIssue
+	for _, finding := range pipeline.NewAnalyzer().Analyze(tabs) {
+		if !matchesPolicy(&policy.Spec, finding) {
+			continue
+		}
+		previous := findPolicyIssue(policy.Status.Issues, finding)
+		if previous != nil && previous.State == finding.Tab.TabState {
+			issues = append(issues, *previous)
+			continue
+		}
+		filed, err := r.fileIssue(&policy.Spec, finding, previous)
+		if err != nil {
+			log.Error(err, "unable to file the issue", "test", finding.Test.TestName, "board", finding.Tab.BoardHash)
+			if previous != nil {
+				issues = append(issues, *previous)
+			}
+			continue
+		}
+		log.Info("filed the issue of a test matched by the policy", "issue", filed.Number,
+			"test", finding.Test.TestName, "board", finding.Tab.BoardHash)
+		issues = append(issues, testgridv1alpha1.PolicyIssue{
+			TestName:  finding.Test.TestName,
+			BoardHash: finding.Tab.BoardHash,
+			Number:    filed.Number,
+			URL:       filed.URL,
+			State:     finding.Tab.TabState,
+			UpdatedAt: metav1.NewTime(now),
+		})
+	}
+	return issues
+}
+
+// fileIssue comments on the open issue of the test when its tab changed
+// state since the previous issue, adopts an open issue filed by someone
+// else, or opens the issue, reopening one closed within ReopenWindow.
+func (r *IssuePolicyReconciler) fileIssue(spec *testgridv1alpha1.IssuePolicySpec, finding *pipeline.Finding,
+	previous *testgridv1alpha1.PolicyIssue) (*github.Issue, error) {
+	report, err := pipeline.NewIssueReporter().Report(finding)
+	if err != nil {
+		return nil, err
+	}
+	options := policyFrontMatter(spec, finding, report.FrontMatter).Options()
+
+	open, err := r.Manager.FindOpenIssue(options.Owner, options.Repo, finding.Test.TestName)
+	if err != nil {
+		return nil, err
+	}
+	if open != nil {
+		if previous != nil {
+			comment := fmt.Sprintf("The test is now %s on [%s](%s).\n\n%s",
+				finding.Tab.TabState, finding.Tab.BoardHash, finding.Tab.TabURL, report.Body)
+			return open, r.Manager.CommentIssue(open, comment)
+		}
+		return open, nil
+	}
+	return github.ReopenOrCreateDraft(r.Manager, options.Owner, options.Repo, finding.Test.TestName,
+		report.Title, report.Body, finding.Tab.BoardHash, options, r.ReopenWindow)
+}
+
+// policyFrontMatter returns the front matter of the template with the
+// repository, the labels and the SIG of the policy.
+func policyFrontMatter(spec *testgridv1alpha1.IssuePolicySpec, finding *pipeline.Finding,
+	frontMatter *issue.FrontMatter) *issue.FrontMatter {
+	frontMatter = frontMatter.WithRepository(issue.Repository(finding.Tab.BoardHash, finding.Test.TestName))
+	if spec.Repository != "" {
+		frontMatter.Repo = spec.Repository
+	}
+	frontMatter.Labels = slices.Clone(frontMatter.Labels)
+	for _, label := range spec.Labels {
+		if !slices.Contains(frontMatter.Labels, label) {
+			frontMatter.Labels = append(frontMatter.Labels, label)
+		}
+	}
+	sig := spec.AssigneeSIG
+	if sig == "" {
+		sig = finding.Test.Sig
+	}
+	return frontMatter.WithSIGLabel(sig)
+}
+
+// matchesPolicy returns true when the test of the finding is in one of the
+// policy states, with the minimum failure streak for the failing tabs.
+// Recovered tests are left to the issue resolver.
+func matchesPolicy(spec *testgridv1alpha1.IssuePolicySpec, finding *pipeline.Finding) bool {
+	if !slices.Contains(policyStates(spec), finding.Tab.TabState) || finding.Recovered() {
+		return false
+	}
+	return !finding.Failing() || finding.Test.ConsecutiveFailures >= minConsecutiveFailures(spec)
+}
+
+// minConsecutiveFailures returns the failure streak of the policy, 0 when
+// set to open the issues at the first failure.
+func minConsecutiveFailures(spec *testgridv1alpha1.IssuePolicySpec) int {
+	if spec.MinConsecutiveFailures == nil {
+		return defaultMinConsecutiveFailures
+	}
+	return int(*spec.MinConsecutiveFailures)
+}
+
+// findPolicyIssue returns the issue recorded for the test and the board of
+// the finding, nil when there is none.
+func findPolicyIssue(issues []testgridv1alpha1.PolicyIssue, finding *pipeline.Finding) *testgridv1alpha1.PolicyIssue {
+	for i := range issues {
+		if issues[i].TestName == finding.Test.TestName && issues[i].BoardHash == finding.Tab.BoardHash {
+			return &issues[i]
+		}
+	}
+	return nil
+}
+
+// policyStates returns the tab states of the policy, failing and flaky by
+// default.
+func policyStates(spec *testgridv1alpha1.IssuePolicySpec) []string {
+	if len(spec.States) == 0 {
+		return testgridv1alpha1.ERROR_STATUSES
+	}
+	var states []string
+	for _, state := range spec.States {
+		states = append(states, string(state))
+	}
+	return states
+}
+
+// policyInterval returns the time between two evaluations of the policy.
+func policyInterval(spec *testgridv1alpha1.IssuePolicySpec) time.Duration {
+	if spec.RefreshInterval != nil && spec.RefreshInterval.Duration > 0 {
+		return spec.RefreshInterval.Duration
+	}
+	return defaultPolicyInterval
+}
+
+// SetupWithManager sets up the controller with the Manager, the status
+// updates don't trigger a new evaluation.
+func (r *IssuePolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&testgridv1alpha1.IssuePolicy{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("issuepolicy").
+		Complete(r)
+}
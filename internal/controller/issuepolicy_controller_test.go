@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	testgridv1alpha1 "sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/github"
+	signalfake "sigs.k8s.io/signalhound/internal/testing/fake"
+	"sigs.k8s.io/signalhound/pkg/pipeline"
+)
+
+func int32Ptr(value int32) *int32 {
+	return &value
+}
+
+var _ = Describe("IssuePolicy Controller", func() {
+	const board = "sig-release-master-blocking"
+	ctx := context.Background()
+
+	It("should open and update the issues of the matched tests", func() {
+		scheme := runtime.NewScheme()
+		Expect(testgridv1alpha1.AddToScheme(scheme)).To(Succeed())
+		policy := &testgridv1alpha1.IssuePolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "blocking"},
+			Spec: testgridv1alpha1.IssuePolicySpec{
+				Boards: []string{board}, MinConsecutiveFailures: int32Ptr(2), Labels: []string{"release-blocker"},
+			},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy).WithStatusSubresource(policy).Build()
+
+		summary := func(tabName, state string) testgridv1alpha1.DashboardSummary {
+			return testgridv1alpha1.DashboardSummary{OverallState: state, DashboardTab: &testgridv1alpha1.DashboardTab{
+				TabName: tabName, BoardHash: board + "#" + tabName, TabState: state,
+			}}
+		}
+		grid := &signalfake.TestGrid{
+			Summaries: map[string][]testgridv1alpha1.DashboardSummary{
+				board: {summary("gce", testgridv1alpha1.FAILING_STATUS), summary("kind", testgridv1alpha1.FLAKY_STATUS)},
+			},
+			Tabs: map[string]*testgridv1alpha1.DashboardTab{
+				"gce": {TabName: "gce", BoardHash: board + "#gce", TabState: testgridv1alpha1.FAILING_STATUS, TestRuns: []testgridv1alpha1.TestResult{
+					{TestName: "[sig-node] Pods should run", Sig: "node", ConsecutiveFailures: 3},
+					{TestName: "[sig-apps] Deployment", ConsecutiveFailures: 1},
+				}},
+				"kind": {TabName: "kind", BoardHash: board + "#kind", TabState: testgridv1alpha1.FLAKY_STATUS, TestRuns: []testgridv1alpha1.TestResult{
+					{TestName: "[sig-network] DNS"},
+				}},
+			},
+		}
+		manager := &signalfake.ProjectManager{Open: map[string]*github.Issue{}}
+		reconciler := &IssuePolicyReconciler{Client: c, Scheme: scheme, Manager: manager, TestGrid: grid}
+		request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "blocking"}}
+		status := func() testgridv1alpha1.IssuePolicyStatus {
+			var updated testgridv1alpha1.IssuePolicy
+			Expect(c.Get(ctx, request.NamespacedName, &updated)).To(Succeed())
+			return updated.Status
+		}
+
+		result, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(defaultPolicyInterval))
+		By("leaving out the deployment test below the failure streak")
+		Expect(manager.Issues).To(HaveLen(2))
+		Expect(manager.Issues[0].Title).To(Equal("[Failing Test] [sig-node] Pods should run"))
+		Expect(manager.Issues[0].Options.Repo).To(Equal("kubernetes"))
+		Expect(manager.Issues[0].Options.Labels).To(ContainElements("release-blocker", "sig/node"))
+		Expect(manager.Issues[1].Title).To(Equal("[Flaking Test] [sig-network] DNS"))
+
+		issues := status().Issues
+		Expect(issues).To(HaveLen(2))
+		Expect(issues[0].TestName).To(Equal("[sig-node] Pods should run"))
+		Expect(issues[0].Number).To(Equal(1))
+		Expect(issues[0].URL).To(Equal("https://github.com/kubernetes/kubernetes/issues/1"))
+		Expect(issues[1].State).To(Equal(testgridv1alpha1.FLAKY_STATUS))
+		lastEvaluated := status().LastEvaluated
+		Expect(lastEvaluated.IsZero()).To(BeFalse())
+
+		By("leaving the recorded issues alone while their tab state is the same")
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manager.Issues).To(HaveLen(2))
+		Expect(manager.Comments).To(BeEmpty())
+
+		By("commenting the issue of the flaky test failing now, the failing one recovered")
+		manager.Open["[sig-network] DNS"] = &manager.Issues[1].Issue
+		grid.Summaries[board] = []testgridv1alpha1.DashboardSummary{summary("kind", testgridv1alpha1.FAILING_STATUS)}
+		grid.Tabs["kind"] = &testgridv1alpha1.DashboardTab{TabName: "kind", BoardHash: board + "#kind", TabState: testgridv1alpha1.FAILING_STATUS,
+			TestRuns: []testgridv1alpha1.TestResult{{TestName: "[sig-network] DNS", ConsecutiveFailures: 2}}}
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manager.Issues).To(HaveLen(2))
+		Expect(manager.Comments).To(HaveLen(1))
+		Expect(manager.Comments[0].Issue.Number).To(Equal(2))
+		Expect(manager.Comments[0].Comment).To(ContainSubstring("The test is now FAILING on [" + board + "#kind]"))
+
+		issues = status().Issues
+		Expect(issues).To(HaveLen(1))
+		Expect(issues[0].TestName).To(Equal("[sig-network] DNS"))
+		Expect(issues[0].State).To(Equal(testgridv1alpha1.FAILING_STATUS))
+	})
+
+	It("should match the findings of the policy states and failure streak", func() {
+		failing := &testgridv1alpha1.DashboardTab{TabState: testgridv1alpha1.FAILING_STATUS}
+		flaky := &testgridv1alpha1.DashboardTab{TabState: testgridv1alpha1.FLAKY_STATUS}
+		spec := &testgridv1alpha1.IssuePolicySpec{}
+		Expect(matchesPolicy(spec, &pipeline.Finding{Tab: failing, Test: &testgridv1alpha1.TestResult{ConsecutiveFailures: 2}})).To(BeTrue())
+		Expect(matchesPolicy(spec, &pipeline.Finding{Tab: failing, Test: &testgridv1alpha1.TestResult{ConsecutiveFailures: 1}})).To(BeFalse())
+		Expect(matchesPolicy(spec, &pipeline.Finding{Tab: failing, Test: &testgridv1alpha1.TestResult{ConsecutiveFailures: 3, RecoveredTimestamp: 1}})).To(BeFalse())
+		Expect(matchesPolicy(spec, &pipeline.Finding{Tab: flaky, Test: &testgridv1alpha1.TestResult{}})).To(BeTrue())
+
+		By("opening the issues at the first failure with an explicit 0")
+		spec.MinConsecutiveFailures = int32Ptr(0)
+		Expect(matchesPolicy(spec, &pipeline.Finding{Tab: failing, Test: &testgridv1alpha1.TestResult{ConsecutiveFailures: 1}})).To(BeTrue())
+
+		spec.States = []testgridv1alpha1.ErrorState{testgridv1alpha1.FAILING_STATUS}
+		Expect(matchesPolicy(spec, &pipeline.Finding{Tab: flaky, Test: &testgridv1alpha1.TestResult{}})).To(BeFalse())
+	})
+})
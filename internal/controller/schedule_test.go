@@ -0,0 +1,195 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	testgridv1alpha1 "sigs.k8s.io/signalhound/api/v1alpha1"
+	signalfake "sigs.k8s.io/signalhound/internal/testing/fake"
+)
+
+var _ = Describe("Dashboard schedule", func() {
+	// a Wednesday
+	now := time.Date(2025, 10, 1, 12, 7, 30, 0, time.UTC)
+
+	DescribeTable("should find the next time of the schedule",
+		func(spec string, next time.Time) {
+			schedule, err := parseSchedule(spec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schedule.Next(now)).To(Equal(next))
+		},
+		Entry("every minute", "* * * * *", time.Date(2025, 10, 1, 12, 8, 0, 0, time.UTC)),
+		Entry("a value", "20 * * * *", time.Date(2025, 10, 1, 12, 20, 0, 0, time.UTC)),
+		Entry("a list", "0,45 * * * *", time.Date(2025, 10, 1, 12, 45, 0, 0, time.UTC)),
+		Entry("a range", "0 8-18 * * 1-5", time.Date(2025, 10, 1, 13, 0, 0, 0, time.UTC)),
+		Entry("a step of *", "*/15 * * * *", time.Date(2025, 10, 1, 12, 15, 0, 0, time.UTC)),
+		Entry("a step of a range", "10-30/10 * * * *", time.Date(2025, 10, 1, 12, 10, 0, 0, time.UTC)),
+		Entry("a step from a value", "5/20 * * * *", time.Date(2025, 10, 1, 12, 25, 0, 0, time.UTC)),
+		Entry("a list of ranges and values", "0 1-3,22 * * *", time.Date(2025, 10, 1, 22, 0, 0, 0, time.UTC)),
+		Entry("a month", "0 0 1 3 *", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)),
+		Entry("a new year", "0 0 1 1 *", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)),
+		Entry("a leap day", "0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)),
+		Entry("no day in five years", "0 0 30 2 *", time.Time{}),
+		Entry("a day of month", "0 0 15 * *", time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)),
+		Entry("a day of week", "0 0 * * 5", time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC)),
+		Entry("Sunday as 0", "0 0 * * 0", time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC)),
+		Entry("Sunday as 7", "0 0 * * 7", time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC)),
+		Entry("a list of days of week", "30 6 * * 6,7", time.Date(2025, 10, 4, 6, 30, 0, 0, time.UTC)),
+		Entry("the day of month or the day of week", "0 0 15 * 5", time.Date(2025, 10, 3, 0, 0, 0, 0, time.UTC)),
+		Entry("the day of month before the day of week", "0 0 2 * 1", time.Date(2025, 10, 2, 0, 0, 0, 0, time.UTC)),
+		Entry("a step of * and the day of week, like cron", "0 0 */10 * 1", time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)),
+		Entry("@hourly", "@hourly", time.Date(2025, 10, 1, 13, 0, 0, 0, time.UTC)),
+		Entry("@daily", "@daily", time.Date(2025, 10, 2, 0, 0, 0, 0, time.UTC)),
+		Entry("@weekly", "@weekly", time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC)),
+	)
+
+	DescribeTable("should reject the invalid schedules",
+		func(spec string) {
+			_, err := parseSchedule(spec)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("an empty schedule", ""),
+		Entry("too few fields", "* * * *"),
+		Entry("too many fields", "* * * * * *"),
+		Entry("an unknown macro", "@yearly"),
+		Entry("a minute out of range", "60 * * * *"),
+		Entry("an hour out of range", "0 24 * * *"),
+		Entry("a day of month out of range", "0 0 0 * *"),
+		Entry("a month out of range", "0 0 1 13 *"),
+		Entry("a day of week out of range", "0 0 * * 8"),
+		Entry("a negative value", "-1 * * * *"),
+		Entry("a name", "0 0 * * mon"),
+		Entry("a value", "a * * * *"),
+		Entry("a reversed range", "5-1 * * * *"),
+		Entry("a range out of range", "50-60 * * * *"),
+		Entry("an open range", "5- * * * *"),
+		Entry("a range of three values", "1-2-3 * * * *"),
+		Entry("a zero step", "*/0 * * * *"),
+		Entry("a negative step", "*/-5 * * * *"),
+		Entry("a step without number", "*/ * * * *"),
+		Entry("an empty list item", "1,,2 * * * *"),
+	)
+})
+
+var _ = Describe("Dashboard refresh", func() {
+	It("should refresh after the interval or at the scheduled times", func() {
+		r := &DashboardReconciler{}
+		now := time.Date(2025, 10, 1, 12, 7, 0, 0, time.UTC)
+		summary := []testgridv1alpha1.DashboardSummary{{DashboardName: "sig-release-master-blocking", OverallState: testgridv1alpha1.FAILING_STATUS}}
+		status := func(lastUpdate time.Time) testgridv1alpha1.DashboardStatus {
+			return testgridv1alpha1.DashboardStatus{LastUpdate: metav1.NewTime(lastUpdate)}
+		}
+		spec := &testgridv1alpha1.DashboardSpec{}
+
+		Expect(r.shouldRefresh(spec, nil, status(time.Time{}), summary, now)).To(BeTrue())
+		Expect(r.shouldRefresh(spec, nil, testgridv1alpha1.DashboardStatus{DashboardSummary: summary}, summary, now)).To(BeFalse())
+		Expect(r.shouldRefresh(spec, nil, status(now.Add(-time.Minute)), summary, now)).To(BeTrue())
+		Expect(r.shouldRefresh(spec, nil, status(now.Add(-30*time.Second)), summary, now)).To(BeFalse())
+		Expect(requeueAfter(spec, nil, now)).To(Equal(defaultRefreshInterval))
+
+		spec.RefreshInterval = &metav1.Duration{Duration: 10 * time.Minute}
+		Expect(r.shouldRefresh(spec, nil, status(now.Add(-5*time.Minute)), summary, now)).To(BeFalse())
+		Expect(r.shouldRefresh(spec, nil, status(now.Add(-10*time.Minute)), summary, now)).To(BeTrue())
+		Expect(requeueAfter(spec, nil, now)).To(Equal(10 * time.Minute))
+
+		By("updating the status only at the scheduled times")
+		schedule, err := parseSchedule("0 * * * *")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.shouldRefresh(spec, schedule, status(now.Add(-5*time.Minute)), summary, now)).To(BeFalse())
+		Expect(r.shouldRefresh(spec, schedule, status(now.Add(-10*time.Minute)), summary, now)).To(BeTrue())
+		Expect(requeueAfter(spec, schedule, now)).To(Equal(53 * time.Minute))
+	})
+
+	It("should back off the failed fetches up to the refresh interval", func() {
+		spec := &testgridv1alpha1.DashboardSpec{RefreshInterval: &metav1.Duration{Duration: 10 * time.Minute}}
+		within := func(expected, backoff time.Duration) {
+			Expect(backoff).To(BeNumerically(">=", expected))
+			Expect(backoff).To(BeNumerically("<=", expected+expected/10))
+		}
+		within(minFetchBackoff, fetchBackoff(spec, 1))
+		within(4*minFetchBackoff, fetchBackoff(spec, 3))
+		within(10*time.Minute, fetchBackoff(spec, 20))
+		within(defaultRefreshInterval, fetchBackoff(&testgridv1alpha1.DashboardSpec{}, 20))
+	})
+
+	It("should record the fetch errors on the conditions", func() {
+		const board = "sig-release-master-blocking"
+		ctx := context.Background()
+		scheme := runtime.NewScheme()
+		Expect(testgridv1alpha1.AddToScheme(scheme)).To(Succeed())
+		dashboard := &testgridv1alpha1.Dashboard{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "blocking"},
+			Spec:       testgridv1alpha1.DashboardSpec{DashboardTab: board},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(dashboard).WithStatusSubresource(dashboard).Build()
+		grid := &signalfake.TestGrid{Err: errors.New("503 Service Unavailable")}
+		reconciler := &DashboardReconciler{Client: c, Scheme: scheme, TestGrid: grid}
+		request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "blocking"}}
+		status := func() testgridv1alpha1.DashboardStatus {
+			var updated testgridv1alpha1.Dashboard
+			Expect(c.Get(ctx, request.NamespacedName, &updated)).To(Succeed())
+			return updated.Status
+		}
+
+		By("backing off the failed fetches")
+		for failures := 1; failures <= 2; failures++ {
+			result, err := reconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("<=", 2*minFetchBackoff+minFetchBackoff/5))
+			Expect(status().FetchFailures).To(Equal(failures))
+		}
+		conditions := status().Conditions
+		Expect(meta.IsStatusConditionFalse(conditions, testgridv1alpha1.ReadyCondition)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(conditions, testgridv1alpha1.FetchFailedCondition)).To(BeTrue())
+		Expect(meta.FindStatusCondition(conditions, testgridv1alpha1.FetchFailedCondition).Reason).To(Equal(testgridv1alpha1.SummaryFetchFailedReason))
+
+		By("resetting the failures and the condition on the next fetch")
+		grid.Err = nil
+		grid.Summaries = map[string][]testgridv1alpha1.DashboardSummary{board: {{
+			DashboardName: board, OverallState: testgridv1alpha1.FAILING_STATUS,
+			DashboardTab: &testgridv1alpha1.DashboardTab{TabName: "gce", BoardHash: board + "#gce"},
+		}}}
+		result, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">=", defaultRefreshInterval))
+		current := status()
+		Expect(current.FetchFailures).To(BeZero())
+		Expect(current.DashboardSummary).To(HaveLen(1))
+		Expect(meta.IsStatusConditionTrue(current.Conditions, testgridv1alpha1.ReadyCondition)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(current.Conditions, testgridv1alpha1.FetchFailedCondition)).To(BeTrue())
+		Expect(current.OverallState).To(Equal(testgridv1alpha1.FAILING_STATUS))
+		Expect(current.FailingTabs).To(Equal(1))
+	})
+
+	It("should set the Ready and Stale conditions", func() {
+		now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+		dashboard := &testgridv1alpha1.Dashboard{Status: testgridv1alpha1.DashboardStatus{DashboardSummary: []testgridv1alpha1.DashboardSummary{
+			{OverallState: testgridv1alpha1.FLAKY_STATUS, LastUpdateTime: now.Add(-2 * time.Hour).Unix()},
+		}}}
+		Expect(setConditions(dashboard, testgridv1alpha1.FetchSucceededReason, nil, now)).To(BeTrue())
+		Expect(setConditions(dashboard, testgridv1alpha1.FetchSucceededReason, nil, now)).To(BeFalse())
+		Expect(meta.IsStatusConditionFalse(dashboard.Status.Conditions, testgridv1alpha1.StaleCondition)).To(BeTrue())
+
+		By("marking stale the tabs not updated by TestGrid for a day")
+		Expect(setConditions(dashboard, testgridv1alpha1.FetchSucceededReason, nil, now.Add(23*time.Hour))).To(BeTrue())
+		stale := meta.FindStatusCondition(dashboard.Status.Conditions, testgridv1alpha1.StaleCondition)
+		Expect(stale.Status).To(Equal(metav1.ConditionTrue))
+		Expect(stale.Reason).To(Equal(testgridv1alpha1.TabsNotUpdatedReason))
+		Expect(stale.Message).To(ContainSubstring("2025-10-01T10:00:00Z"))
+
+		state, failing := overallState(dashboard.Status.DashboardSummary)
+		Expect(state).To(Equal(testgridv1alpha1.FLAKY_STATUS))
+		Expect(failing).To(BeZero())
+		state, _ = overallState(nil)
+		Expect(state).To(Equal(testgridv1alpha1.PASSING_STATUS))
+	})
+})
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	testgridv1alpha1 "sigs.k8s.io/signalhound/api/v1alpha1"
+)
+
+// ReconcileRequestedAnnotation forces the refresh of a Dashboard at its next
+// reconciliation when its RFC 3339 time is after the last update of the
+// status. Touching it triggers the reconciliation right away.
+const ReconcileRequestedAnnotation = "testgrid.holdmybeer.io/reconcile-requested-at"
+
+// Trigger touches the ReconcileRequestedAnnotation of the named dashboards of
+// the namespace, or of all its dashboards without names. An empty namespace
+// lists the dashboards of every namespace. It returns the triggered
+// dashboards as namespace/name.
+func Trigger(ctx context.Context, c client.Client, namespace string, names []string, now time.Time) ([]string, error) {
+	var dashboards testgridv1alpha1.DashboardList
+	var opts []client.ListOption
+	if namespace != "" {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+	if err := c.List(ctx, &dashboards, opts...); err != nil {
+		return nil, fmt.Errorf("error listing dashboards: %v", err)
+	}
+
+	var triggered []string
+	found := map[string]bool{}
+	for i := range dashboards.Items {
+		dashboard := &dashboards.Items[i]
+		if len(names) > 0 && !slices.Contains(names, dashboard.Name) {
+			continue
+		}
+		found[dashboard.Name] = true
+		patch := client.MergeFrom(dashboard.DeepCopy())
+		if dashboard.Annotations == nil {
+			dashboard.Annotations = map[string]string{}
+		}
+		dashboard.Annotations[ReconcileRequestedAnnotation] = now.UTC().Format(time.RFC3339)
+		if err := c.Patch(ctx, dashboard, patch); err != nil {
+			return triggered, fmt.Errorf("error triggering dashboard %s/%s: %v", dashboard.Namespace, dashboard.Name, err)
+		}
+		triggered = append(triggered, dashboard.Namespace+"/"+dashboard.Name)
+	}
+	for _, name := range names {
+		if !found[name] {
+			return triggered, fmt.Errorf("error triggering dashboard %s: not found", name)
+		}
+	}
+	return triggered, nil
+}
+
+// reconcileRequested returns true when the dashboard was triggered after the
+// last update of its status.
+func reconcileRequested(dashboard *testgridv1alpha1.Dashboard) bool {
+	value, ok := dashboard.Annotations[ReconcileRequestedAnnotation]
+	if !ok {
+		return false
+	}
+	requestedAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return false
+	}
+	return requestedAt.After(dashboard.Status.LastUpdate.Time)
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	testgridv1alpha1 "sigs.k8s.io/signalhound/api/v1alpha1"
+)
+
+var _ = Describe("Dashboard trigger", func() {
+	now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	It("should annotate the dashboards of the namespace and names", func() {
+		scheme := runtime.NewScheme()
+		Expect(testgridv1alpha1.AddToScheme(scheme)).To(Succeed())
+		dashboard := func(namespace, name string) client.Object {
+			return &testgridv1alpha1.Dashboard{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			dashboard("default", "blocking"), dashboard("default", "informing"), dashboard("ci", "blocking"),
+		).Build()
+		ctx := context.Background()
+
+		triggered, err := Trigger(ctx, c, "", nil, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(triggered).To(ConsistOf("default/blocking", "default/informing", "ci/blocking"))
+
+		var updated testgridv1alpha1.Dashboard
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "ci", Name: "blocking"}, &updated)).To(Succeed())
+		Expect(updated.Annotations).To(HaveKeyWithValue(ReconcileRequestedAnnotation, "2025-10-01T12:00:00Z"))
+
+		triggered, err = Trigger(ctx, c, "default", []string{"informing"}, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(triggered).To(Equal([]string{"default/informing"}))
+
+		_, err = Trigger(ctx, c, "ci", []string{"informing"}, now)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should request the reconciliation until the next refresh", func() {
+		dashboard := &testgridv1alpha1.Dashboard{}
+		Expect(reconcileRequested(dashboard)).To(BeFalse())
+
+		dashboard.Annotations = map[string]string{ReconcileRequestedAnnotation: now.Format(time.RFC3339)}
+		Expect(reconcileRequested(dashboard)).To(BeTrue())
+
+		By("clearing the request on the following refresh")
+		dashboard.Status.LastUpdate = metav1.NewTime(now.Add(time.Second))
+		Expect(reconcileRequested(dashboard)).To(BeFalse())
+
+		dashboard.Annotations[ReconcileRequestedAnnotation] = "now"
+		dashboard.Status.LastUpdate = metav1.Time{}
+		Expect(reconcileRequested(dashboard)).To(BeFalse())
+	})
+})
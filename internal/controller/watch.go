@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	testgridv1alpha1 "sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/testgrid"
+)
+
+// minWatchInterval bounds the polls of the summaries, TestGrid updates them
+// every few minutes at best.
+const minWatchInterval = 10 * time.Second
+
+// SummaryWatcher reacts to the new runs of the dashboards within seconds
+// instead of their refresh interval. TestGrid has no push nor long-poll
+// endpoint, so the watcher polls the summary of every Dashboard, a single
+// small request, and triggers the dashboards with a new run or a tab state
+// change, see Trigger. The tab tests are only fetched by the reconciliation.
+// The dashboards with a schedule are left to it, a trigger would update their
+// status outside of the scheduled times.
+type SummaryWatcher struct {
+	client.Client
+
+	// TestGrid fetches the summaries, the client of testgrid.URL when nil.
+	TestGrid testgrid.TestGridInterface
+
+	// Interval is the delay between two polls, at least minWatchInterval.
+	Interval time.Duration
+}
+
+// NeedLeaderElection polls on the leader only, the triggers would be
+// repeated by every replica otherwise.
+func (w *SummaryWatcher) NeedLeaderElection() bool {
+	return true
+}
+
+// Start polls the summaries until the context is done.
+func (w *SummaryWatcher) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("summary-watcher")
+	ticker := time.NewTicker(max(w.Interval, minWatchInterval))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			triggered, err := w.Poll(ctx, now)
+			if err != nil {
+				log.Error(err, "error polling the dashboard summaries")
+			}
+			if len(triggered) > 0 {
+				log.Info("triggered the dashboards with new runs", "dashboards", triggered)
+			}
+		}
+	}
+}
+
+// Poll fetches the summary of every Dashboard and triggers the ones with new
+// runs since their status, except the ones already triggered or with a
+// schedule. It returns the
+// triggered dashboards as namespace/name, the fetch errors are joined.
+func (w *SummaryWatcher) Poll(ctx context.Context, now time.Time) ([]string, error) {
+	grid := w.TestGrid
+	if grid == nil {
+		grid = testgrid.NewTestGrid(testgrid.URL)
+	}
+	var dashboards testgridv1alpha1.DashboardList
+	if err := w.List(ctx, &dashboards); err != nil {
+		return nil, err
+	}
+
+	var triggered []string
+	var errs []error
+	for i := range dashboards.Items {
+		dashboard := &dashboards.Items[i]
+		if dashboard.Status.LastUpdate.IsZero() || reconcileRequested(dashboard) {
+			// the reconciliation is on its way
+			continue
+		}
+		if dashboard.Spec.Schedule != "" {
+			continue
+		}
+		summaries, err := grid.FetchTabSummary(dashboard.Spec.DashboardTab, testgridv1alpha1.ERROR_STATUSES)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !newRuns(dashboard.Status.DashboardSummary, summaries) {
+			continue
+		}
+		names, err := Trigger(ctx, w.Client, dashboard.Namespace, []string{dashboard.Name}, now)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		triggered = append(triggered, names...)
+	}
+	return triggered, errors.Join(errs...)
+}
+
+// newRuns returns true when a tab of the fetched summaries has a run after
+// the one of the stored summaries, a different state, or when a tab entered
+// or left the summaries.
+func newRuns(stored, fetched []testgridv1alpha1.DashboardSummary) bool {
+	if len(stored) != len(fetched) {
+		return true
+	}
+	previous := map[string]testgridv1alpha1.DashboardSummary{}
+	for _, summary := range stored {
+		previous[tabName(summary)] = summary
+	}
+	for _, summary := range fetched {
+		before, ok := previous[tabName(summary)]
+		if !ok || summary.LastRunTime > before.LastRunTime || summary.OverallState != before.OverallState {
+			return true
+		}
+	}
+	return false
+}
+
+// tabName returns the tab of the summary, empty without tab.
+func tabName(summary testgridv1alpha1.DashboardSummary) string {
+	if summary.DashboardTab == nil {
+		return ""
+	}
+	return summary.DashboardTab.TabName
+}
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	testgridv1alpha1 "sigs.k8s.io/signalhound/api/v1alpha1"
+	signalfake "sigs.k8s.io/signalhound/internal/testing/fake"
+)
+
+var _ = Describe("Summary Watcher", func() {
+	const board = "sig-release-master-blocking"
+	summary := func(tab, state string, lastRun int64) testgridv1alpha1.DashboardSummary {
+		return testgridv1alpha1.DashboardSummary{DashboardName: board, OverallState: state, LastRunTime: lastRun,
+			DashboardTab: &testgridv1alpha1.DashboardTab{TabName: tab}}
+	}
+
+	It("should trigger the dashboards with new runs", func() {
+		scheme := runtime.NewScheme()
+		Expect(testgridv1alpha1.AddToScheme(scheme)).To(Succeed())
+		now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+		stored := []testgridv1alpha1.DashboardSummary{summary("gce", testgridv1alpha1.FAILING_STATUS, 100)}
+		dashboard := func(name string, lastUpdate time.Time) client.Object {
+			return &testgridv1alpha1.Dashboard{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+				Spec:       testgridv1alpha1.DashboardSpec{DashboardTab: board},
+				Status:     testgridv1alpha1.DashboardStatus{LastUpdate: metav1.NewTime(lastUpdate), DashboardSummary: stored},
+			}
+		}
+		scheduled := dashboard("scheduled", now.Add(-time.Hour)).(*testgridv1alpha1.Dashboard)
+		scheduled.Spec.Schedule = "0 8 * * *"
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			dashboard("blocking", now.Add(-time.Hour)), dashboard("new", time.Time{}), scheduled,
+		).Build()
+		grid := &signalfake.TestGrid{Summaries: map[string][]testgridv1alpha1.DashboardSummary{board: stored}}
+		watcher := &SummaryWatcher{Client: c, TestGrid: grid}
+		ctx := context.Background()
+
+		By("leaving the unchanged summary and the dashboard never fetched to their reconciliation")
+		triggered, err := watcher.Poll(ctx, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(triggered).To(BeEmpty())
+
+		By("leaving the scheduled dashboard to its schedule")
+		grid.Summaries[board] = []testgridv1alpha1.DashboardSummary{summary("gce", testgridv1alpha1.FAILING_STATUS, 200)}
+		triggered, err = watcher.Poll(ctx, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(triggered).To(Equal([]string{"default/blocking"}))
+
+		By("not triggering the dashboard again before its refresh")
+		triggered, err = watcher.Poll(ctx, now.Add(time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(triggered).To(BeEmpty())
+
+		grid.Err = errors.New("503 Service Unavailable")
+		var updated testgridv1alpha1.Dashboard
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "blocking"}, &updated)).To(Succeed())
+		updated.Annotations = nil
+		Expect(c.Update(ctx, &updated)).To(Succeed())
+		_, err = watcher.Poll(ctx, now)
+		Expect(err).To(MatchError(ContainSubstring("503 Service Unavailable")))
+	})
+
+	It("should detect the new runs and state changes of the tabs", func() {
+		stored := []testgridv1alpha1.DashboardSummary{summary("gce", testgridv1alpha1.FAILING_STATUS, 100), summary("kind", testgridv1alpha1.FLAKY_STATUS, 100)}
+		Expect(newRuns(stored, []testgridv1alpha1.DashboardSummary{stored[1], stored[0]})).To(BeFalse())
+		Expect(newRuns(stored, []testgridv1alpha1.DashboardSummary{stored[0], summary("kind", testgridv1alpha1.FLAKY_STATUS, 150)})).To(BeTrue())
+		Expect(newRuns(stored, []testgridv1alpha1.DashboardSummary{stored[0], summary("kind", testgridv1alpha1.FAILING_STATUS, 100)})).To(BeTrue())
+		Expect(newRuns(stored, stored[:1])).To(BeTrue())
+		Expect(newRuns(stored, []testgridv1alpha1.DashboardSummary{stored[0], summary("ec2", testgridv1alpha1.FLAKY_STATUS, 100)})).To(BeTrue())
+	})
+})
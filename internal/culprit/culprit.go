@@ -0,0 +1,129 @@
+package culprit
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/sig"
+)
+
+const (
+	// DefaultOwner and DefaultRepository are the repository the pull requests
+	// are searched in, where most of the tests on the release boards live.
+	DefaultOwner      = "kubernetes"
+	DefaultRepository = "kubernetes"
+
+	// sigWeight scores a pull request labeled or touching the paths of the
+	// failing test SIG above a single keyword match.
+	sigWeight = 3
+)
+
+var (
+	wordRegex = regexp.MustCompile(`[a-z0-9]+`)
+
+	// stopWords are frequent in test names and meaningless as path keywords.
+	stopWords = map[string]bool{
+		"should": true, "with": true, "when": true, "that": true, "from": true,
+		"test": true, "tests": true, "e2e": true, "feature": true, "conformance": true,
+		"serial": true, "slow": true, "disruptive": true, "able": true, "have": true,
+		"kubernetes": true, "suite": true, "the": true, "and": true, "for": true,
+	}
+)
+
+// CompareURL returns the GitHub page comparing the commit of the last green
+// run with the commit of the first red run, listing the suspected commits.
+func CompareURL(lastPass, firstFail string) string {
+	return fmt.Sprintf("https://github.com/%s/%s/compare/%s...%s", DefaultOwner, DefaultRepository, lastPass, firstFail)
+}
+
+// Candidate is a merged pull request ranked as a possible culprit.
+type Candidate struct {
+	github.PullRequest
+	Score int
+}
+
+// Rank scores the pull requests by overlap with the failing test area, the
+// test SIG label and the keywords of the test name found in the changed
+// paths, and returns up to limit candidates with a positive score.
+func Rank(pulls []github.PullRequest, testName string, limit int) []Candidate {
+	testSig := sig.FromTestName(testName)
+	keywords := testKeywords(testName, testSig)
+
+	var candidates []Candidate
+	for _, pull := range pulls {
+		score := 0
+		if testSig != "" && (hasLabel(pull.Labels, "sig/"+testSig) || touchesArea(pull.Files, testSig)) {
+			score += sigWeight
+		}
+		score += matchedKeywords(pull.Files, keywords)
+		if score > 0 {
+			candidates = append(candidates, Candidate{PullRequest: pull, Score: score})
+		}
+	}
+
+	// highest score first, most recent merge first on ties.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].MergedAt.After(candidates[j].MergedAt)
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates
+}
+
+// testKeywords returns the distinct words of the test name usable as path
+// keywords, the SIG name is excluded since it is scored on its own.
+func testKeywords(testName, sig string) []string {
+	seen := map[string]bool{}
+	var keywords []string
+	for _, word := range wordRegex.FindAllString(strings.ToLower(testName), -1) {
+		if len(word) < 4 || stopWords[word] || word == sig || seen[word] {
+			continue
+		}
+		seen[word] = true
+		keywords = append(keywords, word)
+	}
+	return keywords
+}
+
+// matchedKeywords counts the keywords found in the path segments.
+func matchedKeywords(files []string, keywords []string) int {
+	segments := map[string]bool{}
+	for _, file := range files {
+		for _, segment := range wordRegex.FindAllString(strings.ToLower(file), -1) {
+			segments[segment] = true
+		}
+	}
+	matches := 0
+	for _, keyword := range keywords {
+		if segments[keyword] {
+			matches++
+		}
+	}
+	return matches
+}
+
+// touchesArea returns true when a file lives in the e2e tests of the SIG.
+func touchesArea(files []string, sig string) bool {
+	for _, file := range files {
+		if strings.HasPrefix(file, "test/e2e/"+sig+"/") || strings.HasPrefix(file, "test/e2e_"+sig+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func hasLabel(labels []string, name string) bool {
+	for _, label := range labels {
+		if label == name {
+			return true
+		}
+	}
+	return false
+}
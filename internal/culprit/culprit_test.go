@@ -0,0 +1,30 @@
+package culprit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/signalhound/internal/github"
+)
+
+func TestRank(t *testing.T) {
+	merged := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	pulls := []github.PullRequest{
+		{Number: 1, Title: "docs: fix typo", Files: []string{"README.md"}, MergedAt: merged},
+		{Number: 2, Title: "kubelet: rework probes", Labels: []string{"sig/node"}, Files: []string{"pkg/kubelet/prober/prober.go"}, MergedAt: merged},
+		{Number: 3, Title: "e2e: pods lifecycle", Files: []string{"test/e2e/node/pods.go", "pkg/kubelet/lifecycle/handlers.go"}, MergedAt: merged.Add(time.Hour)},
+		{Number: 4, Title: "apiserver: lifecycle hooks", Files: []string{"staging/src/k8s.io/apiserver/pkg/lifecycle.go"}, MergedAt: merged},
+	}
+
+	candidates := Rank(pulls, "[sig-node] Pods Lifecycle should invoke the prestop hook", 2)
+	assert.Len(t, candidates, 2)
+	assert.Equal(t, 3, candidates[0].Number)
+	assert.Equal(t, 2, candidates[1].Number)
+
+	candidates = Rank(pulls, "[sig-node] Pods Lifecycle should invoke the prestop hook", 10)
+	assert.Len(t, candidates, 3)
+	assert.Equal(t, 4, candidates[2].Number)
+
+	assert.Empty(t, Rank(pulls, "[sig-storage] CSI volumes", 5))
+}
@@ -0,0 +1,352 @@
+// Package doctor checks the signalhound environment end to end and hints how
+// to fix what is broken.
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/issue"
+	"sigs.k8s.io/signalhound/internal/mcp"
+	"sigs.k8s.io/signalhound/internal/testgrid"
+)
+
+var (
+	// GitHubAPIURL is the GitHub REST endpoint used to validate the token.
+	GitHubAPIURL = "https://api.github.com"
+
+	// AnthropicURL is the Anthropic API endpoint used to validate the key.
+	AnthropicURL = "https://api.anthropic.com"
+)
+
+// client bounds every network check.
+var client = &http.Client{Timeout: 15 * time.Second}
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	OK      Status = "ok"
+	Warning Status = "warn"
+	Failed  Status = "fail"
+	Skipped Status = "skip"
+)
+
+// Result is the outcome of a check, with a hint to fix it when it is not OK.
+type Result struct {
+	Name    string
+	Status  Status
+	Message string
+	Hint    string
+}
+
+// Options are the settings checked by Run.
+type Options struct {
+	// ConfigErr is the error loading the configuration file and templates.
+	ConfigErr error
+
+	GitHubToken  string
+	TestGridURL  string
+	Dashboards   []string
+	MCPURL       string
+	AnthropicKey string
+
+	// ClipboardTool returns the clipboard command used by the TUI.
+	ClipboardTool func() (string, error)
+}
+
+// Run runs every check and returns their results in order.
+func Run(ctx context.Context, opts Options) []Result {
+	results := []Result{CheckConfig(opts.ConfigErr), CheckGitHubToken(ctx, opts.GitHubToken)}
+	for _, dashboard := range opts.Dashboards {
+		results = append(results, CheckTestGrid(opts.TestGridURL, dashboard))
+	}
+	results = append(results,
+		CheckMCP(ctx, opts.MCPURL, opts.GitHubToken),
+		CheckAnthropicKey(ctx, opts.AnthropicKey),
+		CheckClipboard(opts.ClipboardTool),
+	)
+	return append(results, CheckTemplates()...)
+}
+
+// Failures returns the number of failed results.
+func Failures(results []Result) (failures int) {
+	for _, result := range results {
+		if result.Status == Failed {
+			failures++
+		}
+	}
+	return failures
+}
+
+// CheckConfig reports the error loading the configuration file.
+func CheckConfig(err error) Result {
+	result := Result{Name: "configuration", Status: OK, Message: "loaded"}
+	if err != nil {
+		result.Status, result.Message = Failed, err.Error()
+		result.Hint = "fix the file given with --config, or the --templates directory"
+	}
+	return result
+}
+
+// requiredScopes are the classic token scopes needed to draft issues in the
+// project board and to reopen and create repository issues.
+var requiredScopes = [][]string{{"project"}, {"repo", "public_repo"}}
+
+// CheckGitHubToken validates the token and its classic scopes.
+func CheckGitHubToken(ctx context.Context, token string) Result {
+	result := Result{Name: "GitHub token"}
+	if token == "" {
+		result.Status, result.Message = Failed, "not set"
+		result.Hint = "export SIGNALHOUND_GITHUB_TOKEN or set githubToken in the configuration file"
+		return result
+	}
+
+	response, err := get(ctx, GitHubAPIURL+"/user", map[string]string{"Authorization": "Bearer " + token})
+	if err != nil {
+		result.Status, result.Message = Failed, fmt.Sprintf("error reaching GitHub: %v", err)
+		result.Hint = networkHint("api.github.com", err)
+		return result
+	}
+	defer response.Body.Close() // nolint
+	if response.StatusCode == http.StatusUnauthorized {
+		result.Status, result.Message = Failed, "invalid or expired token"
+		result.Hint = "create a new token, see the Prerequisites section of the README"
+		return result
+	}
+	if response.StatusCode != http.StatusOK {
+		result.Status, result.Message = Failed, fmt.Sprintf("unexpected GitHub response: %s", response.Status)
+		return result
+	}
+	var user struct {
+		Login string `json:"login"`
+	}
+	_ = json.NewDecoder(response.Body).Decode(&user)
+
+	header, found := response.Header[http.CanonicalHeaderKey("X-OAuth-Scopes")]
+	if !found {
+		result.Status, result.Message = Warning, fmt.Sprintf("valid for %s, scopes of fine-grained tokens can't be checked", user.Login)
+		result.Hint = "make sure the token can read and write organization projects and repository issues"
+		return result
+	}
+	scopes := map[string]bool{}
+	for _, scope := range strings.Split(strings.Join(header, ","), ",") {
+		scopes[strings.TrimSpace(scope)] = true
+	}
+	var missing []string
+	for _, alternatives := range requiredScopes {
+		granted := false
+		for _, scope := range alternatives {
+			granted = granted || scopes[scope]
+		}
+		if !granted {
+			missing = append(missing, strings.Join(alternatives, " or "))
+		}
+	}
+	if len(missing) > 0 {
+		result.Status, result.Message = Failed, fmt.Sprintf("valid for %s, missing scopes: %s", user.Login, strings.Join(missing, ", "))
+		result.Hint = "add the missing scopes to the token in the GitHub developer settings"
+		return result
+	}
+	result.Status, result.Message = OK, fmt.Sprintf("valid for %s", user.Login)
+	return result
+}
+
+// CheckTestGrid fetches the summary of the dashboard.
+func CheckTestGrid(url, dashboard string) Result {
+	result := Result{Name: "TestGrid " + dashboard}
+	summaries, err := testgrid.NewTestGrid(url).FetchTabSummary(dashboard, nil)
+	if err != nil {
+		result.Status, result.Message = Failed, err.Error()
+		result.Hint = "check the network access to " + url + " and the dashboard name in --dashboards"
+		if untrusted(err) {
+			result.Hint = networkHint(url, err)
+		}
+		return result
+	}
+	if len(summaries) == 0 {
+		result.Status, result.Message = Warning, "reachable, but the dashboard has no tabs"
+		result.Hint = "check the dashboard name in --dashboards"
+		return result
+	}
+	result.Status, result.Message = OK, fmt.Sprintf("reachable, %d tabs", len(summaries))
+	return result
+}
+
+// CheckMCP initializes a session on the MCP server at url and closes it.
+func CheckMCP(ctx context.Context, url, token string) Result {
+	result := Result{Name: "MCP server"}
+	if url == "" {
+		result.Status, result.Message = Skipped, "no server URL given"
+		result.Hint = "pass --mcp-url to check a server started with signalhound mcp --http"
+		return result
+	}
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"signalhound-doctor","version":"dev"}}}`
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBufferString(body))
+	if err != nil {
+		result.Status, result.Message = Failed, err.Error()
+		return result
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json, text/event-stream")
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		result.Status, result.Message = Failed, fmt.Sprintf("error reaching the server: %v", err)
+		result.Hint = "start it with signalhound mcp --http and check the URL ends with /mcp"
+		return result
+	}
+	defer response.Body.Close() // nolint
+
+	var initialize mcp.Response
+	if err := json.NewDecoder(response.Body).Decode(&initialize); err != nil || response.StatusCode != http.StatusOK {
+		result.Status, result.Message = Failed, fmt.Sprintf("unexpected response: %s", response.Status)
+		result.Hint = "check the URL points to the MCP endpoint, e.g. http://localhost:8080/mcp"
+		return result
+	}
+	if initialize.Error != nil {
+		result.Status, result.Message = Failed, initialize.Error.Message
+		result.Hint = "the server may require a session token, set the GitHub token"
+		return result
+	}
+	if session := response.Header.Get(mcp.SessionHeader); session != "" {
+		if closing, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil); err == nil {
+			closing.Header.Set(mcp.SessionHeader, session)
+			if response, err := client.Do(closing); err == nil {
+				response.Body.Close() // nolint
+			}
+		}
+	}
+	result.Status, result.Message = OK, "session initialized"
+	return result
+}
+
+// CheckAnthropicKey validates the Anthropic API key when it is set, it is
+// only needed by the language model features.
+func CheckAnthropicKey(ctx context.Context, key string) Result {
+	result := Result{Name: "Anthropic key"}
+	if key == "" {
+		result.Status, result.Message = Skipped, "not set"
+		result.Hint = "export ANTHROPIC_API_KEY to enable the language model features"
+		return result
+	}
+	response, err := get(ctx, AnthropicURL+"/v1/models", map[string]string{
+		"x-api-key":         key,
+		"anthropic-version": "2023-06-01",
+	})
+	if err != nil {
+		result.Status, result.Message = Failed, fmt.Sprintf("error reaching Anthropic: %v", err)
+		result.Hint = networkHint("api.anthropic.com", err)
+		return result
+	}
+	defer response.Body.Close() // nolint
+	switch response.StatusCode {
+	case http.StatusOK:
+		result.Status, result.Message = OK, "valid"
+	case http.StatusUnauthorized, http.StatusForbidden:
+		result.Status, result.Message = Failed, "invalid key"
+		result.Hint = "create a new key in the Anthropic console"
+	default:
+		result.Status, result.Message = Warning, fmt.Sprintf("unexpected Anthropic response: %s", response.Status)
+	}
+	return result
+}
+
+// CheckClipboard looks up the clipboard command used by the yy shortcut.
+func CheckClipboard(tool func() (string, error)) Result {
+	result := Result{Name: "clipboard"}
+	if tool == nil {
+		result.Status, result.Message = Skipped, "not checked"
+		return result
+	}
+	name, err := tool()
+	if err != nil {
+		result.Status, result.Message = Warning, err.Error()
+		return result
+	}
+	if name == "osc52" {
+		result.Status, result.Message = OK, "osc52 terminal escape sequence"
+		result.Hint = "the terminal must allow OSC 52, e.g. set-clipboard on in tmux"
+		return result
+	}
+	if _, err := exec.LookPath(name); err != nil {
+		result.Status, result.Message = Warning, fmt.Sprintf("%s not found", name)
+		result.Hint = fmt.Sprintf("install %s to copy panels with yy", name)
+		return result
+	}
+	result.Status, result.Message = OK, name
+	return result
+}
+
+// CheckTemplates renders the failure and flake issue templates with a sample
+// test and parses their front matter, and renders the resolution comment.
+func CheckTemplates() (results []Result) {
+	tab := &v1alpha1.DashboardTab{BoardHash: "sig-release-master-blocking#gce-cos-master-default", TabURL: "https://testgrid.k8s.io"}
+	test := &v1alpha1.TestResult{TestName: "[sig-node] Pods should run", ErrorMessage: "timeout"}
+	for _, state := range []string{v1alpha1.FAILING_STATUS, v1alpha1.FLAKY_STATUS} {
+		templateFile, _ := issue.PickTemplate(state)
+		result := Result{Name: "template " + templateFile, Status: OK, Message: "valid"}
+		issueTemplate := issue.NewTemplate(tab, test)
+		issueTemplate.Sig = "node"
+		rendered, err := issue.RenderTemplate(issueTemplate, templateFile)
+		if err == nil {
+			_, _, err = issue.ParseFrontMatter(rendered.String())
+		}
+		if err != nil {
+			result.Status, result.Message = Failed, err.Error()
+			result.Hint = "fix the template in the --templates directory, or remove it to use the embedded one"
+		}
+		results = append(results, result)
+	}
+
+	result := Result{Name: "template " + issue.JobTemplate, Status: OK, Message: "valid"}
+	if _, err := issue.RenderJob(issue.NewJob(tab, []v1alpha1.TestResult{*test})); err != nil {
+		result.Status, result.Message = Failed, err.Error()
+		result.Hint = "fix the template in the --templates directory, or remove it to use the embedded one"
+	}
+	results = append(results, result)
+
+	result = Result{Name: "template " + issue.ResolutionTemplate, Status: OK, Message: "valid"}
+	if _, err := issue.RenderResolution(issue.NewResolution(tab, test)); err != nil {
+		result.Status, result.Message = Failed, err.Error()
+		result.Hint = "fix the template in the --templates directory, or remove it to use the embedded one"
+	}
+	return append(results, result)
+}
+
+// networkHint hints how to reach the host through a proxy, or how to trust
+// the certificate of a TLS inspecting proxy.
+func networkHint(host string, err error) string {
+	if untrusted(err) {
+		return "the certificate of " + host + " is not trusted, set network.caBundle in the configuration file to the CA of your proxy"
+	}
+	return "check the network access to " + host + ", behind a proxy export HTTPS_PROXY or set network.proxy in the configuration file"
+}
+
+// untrusted returns true for the certificate verification errors, the
+// clients may have flattened the error chain.
+func untrusted(err error) bool {
+	return strings.Contains(err.Error(), "x509: ")
+}
+
+// get sends a GET request with the headers.
+func get(ctx context.Context, url string, headers map[string]string) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	return client.Do(request)
+}
@@ -0,0 +1,121 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/signalhound/internal/mcp"
+)
+
+func TestCheckGitHubToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("Authorization") {
+		case "Bearer classic":
+			w.Header().Set("X-OAuth-Scopes", "project, repo, read:org")
+		case "Bearer no-project":
+			w.Header().Set("X-OAuth-Scopes", "public_repo")
+		case "Bearer fine-grained":
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"login": "octocat"}`) // nolint
+	}))
+	defer server.Close()
+	previous := GitHubAPIURL
+	GitHubAPIURL = server.URL
+	t.Cleanup(func() { GitHubAPIURL = previous })
+
+	tests := []struct {
+		token   string
+		status  Status
+		message string
+	}{
+		{token: "", status: Failed, message: "not set"},
+		{token: "classic", status: OK, message: "valid for octocat"},
+		{token: "no-project", status: Failed, message: "valid for octocat, missing scopes: project"},
+		{token: "fine-grained", status: Warning, message: "valid for octocat, scopes of fine-grained tokens can't be checked"},
+		{token: "expired", status: Failed, message: "invalid or expired token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			result := CheckGitHubToken(context.Background(), tt.token)
+			assert.Equal(t, tt.status, result.Status)
+			assert.Equal(t, tt.message, result.Message)
+		})
+	}
+}
+
+func TestCheckTestGrid(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sig-release-master-blocking/summary" {
+			fmt.Fprint(w, `not json`) // nolint
+			return
+		}
+		fmt.Fprint(w, `{"kind-master": {"overall_status": "PASSING"}}`) // nolint
+	}))
+	defer server.Close()
+
+	assert.Equal(t, OK, CheckTestGrid(server.URL, "sig-release-master-blocking").Status)
+	result := CheckTestGrid(server.URL, "sig-release-typo")
+	assert.Equal(t, Failed, result.Status)
+	assert.Contains(t, result.Hint, "--dashboards")
+
+	// a TLS inspecting proxy is not trusted without its CA
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	defer tlsServer.Close()
+	result = CheckTestGrid(tlsServer.URL, "sig-release-master-blocking")
+	assert.Equal(t, Failed, result.Status)
+	assert.Contains(t, result.Hint, "network.caBundle")
+}
+
+func TestCheckMCP(t *testing.T) {
+	assert.Equal(t, Skipped, CheckMCP(context.Background(), "", "").Status)
+
+	server := mcp.NewServer("signalhound", "dev", "")
+	server.RequireSessionToken = true
+	ts := httptest.NewServer(server.HTTPHandler())
+	defer ts.Close()
+
+	assert.Equal(t, OK, CheckMCP(context.Background(), ts.URL, "token").Status)
+	assert.Equal(t, Failed, CheckMCP(context.Background(), ts.URL, "").Status)
+	assert.Equal(t, Failed, CheckMCP(context.Background(), "http://127.0.0.1:1/mcp", "token").Status)
+}
+
+func TestCheckAnthropicKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/models", r.URL.Path)
+		if r.Header.Get("x-api-key") != "valid" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+	previous := AnthropicURL
+	AnthropicURL = server.URL
+	t.Cleanup(func() { AnthropicURL = previous })
+
+	assert.Equal(t, Skipped, CheckAnthropicKey(context.Background(), "").Status)
+	assert.Equal(t, OK, CheckAnthropicKey(context.Background(), "valid").Status)
+	assert.Equal(t, Failed, CheckAnthropicKey(context.Background(), "revoked").Status)
+}
+
+func TestCheckClipboard(t *testing.T) {
+	assert.Equal(t, OK, CheckClipboard(func() (string, error) { return "go", nil }).Status)
+	assert.Equal(t, OK, CheckClipboard(func() (string, error) { return "osc52", nil }).Status)
+	result := CheckClipboard(func() (string, error) { return "signalhound-missing-clipboard", nil })
+	assert.Equal(t, Warning, result.Status)
+	assert.Contains(t, result.Hint, "install signalhound-missing-clipboard")
+	assert.Equal(t, Warning, CheckClipboard(func() (string, error) { return "", errors.New("unsupported") }).Status)
+}
+
+func TestCheckTemplates(t *testing.T) {
+	results := CheckTemplates()
+	assert.Len(t, results, 4)
+	assert.Equal(t, 0, Failures(results))
+}
@@ -0,0 +1,125 @@
+package fingerprint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+)
+
+// Master is the branch name of the development dashboards.
+const Master = "master"
+
+var (
+	// boardRegex extracts the branch of the SIG Release dashboards, e.g.
+	// sig-release-1.34-blocking or sig-release-master-informing.
+	boardRegex = regexp.MustCompile(`^sig-release-(master|\d+\.\d+)-`)
+
+	hexRegex    = regexp.MustCompile(`\b(0x)?[0-9a-f]{8,}\b`)
+	uuidRegex   = regexp.MustCompile(`\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+	numberRegex = regexp.MustCompile(`\d+`)
+	spaceRegex  = regexp.MustCompile(`\s+`)
+)
+
+// New returns the fingerprint of a test failure, a short hash of the test
+// name and the first line of the failure message with the run specific
+// values, like numbers, hashes and UUIDs, masked. The same failure gets the
+// same fingerprint on every branch.
+func New(testName, message string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	line = strings.ToLower(line)
+	line = uuidRegex.ReplaceAllString(line, "<uuid>")
+	line = hexRegex.ReplaceAllString(line, "<hex>")
+	line = numberRegex.ReplaceAllString(line, "<n>")
+	line = spaceRegex.ReplaceAllString(line, " ")
+
+	sum := sha256.Sum256([]byte(testName + "\x00" + line))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Branch returns the release branch of a board hash, master or the minor
+// version, or an empty string for the non SIG Release dashboards.
+func Branch(boardHash string) string {
+	if match := boardRegex.FindStringSubmatch(boardHash); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// Occurrence is a failure with the same fingerprint on another branch.
+type Occurrence struct {
+	Branch    string
+	BoardHash string
+	TabURL    string
+}
+
+// Index maps the fingerprints of the failing tests to their boards.
+type Index map[string][]Occurrence
+
+// NewIndex indexes the fingerprinted tests of the tabs on SIG Release boards.
+func NewIndex(tabs []*v1alpha1.DashboardTab) Index {
+	index := Index{}
+	for _, tab := range tabs {
+		branch := Branch(tab.BoardHash)
+		if branch == "" {
+			continue
+		}
+		for _, test := range tab.TestRuns {
+			if test.Fingerprint == "" {
+				continue
+			}
+			index[test.Fingerprint] = append(index[test.Fingerprint], Occurrence{
+				Branch:    branch,
+				BoardHash: tab.BoardHash,
+				TabURL:    tab.TabURL,
+			})
+		}
+	}
+	return index
+}
+
+// CrossBranch returns the occurrences of the test fingerprint on branches
+// other than the tab branch, one per board.
+func (i Index) CrossBranch(tab *v1alpha1.DashboardTab, test *v1alpha1.TestResult) (occurrences []Occurrence) {
+	branch := Branch(tab.BoardHash)
+	if branch == "" || test.Fingerprint == "" {
+		return nil
+	}
+	seen := map[string]bool{}
+	for _, occurrence := range i[test.Fingerprint] {
+		if occurrence.Branch == branch || seen[occurrence.BoardHash] {
+			continue
+		}
+		seen[occurrence.BoardHash] = true
+		occurrences = append(occurrences, occurrence)
+	}
+	return occurrences
+}
+
+// Branches returns the branches of the occurrences.
+func Branches(occurrences []Occurrence) (branches []string) {
+	for _, occurrence := range occurrences {
+		branches = append(branches, occurrence.Branch)
+	}
+	return branches
+}
+
+// Hint explains what the cross-branch correlation means for the fix, a
+// failure reaching master needs the fix cherry-picked to the release
+// branches, while a failure only on release branches is a branch regression.
+func Hint(branch string, otherBranches []string) string {
+	if len(otherBranches) == 0 {
+		return ""
+	}
+	if branch == Master {
+		return "the failure also affects release branches, the fix will need cherry-picks"
+	}
+	for _, other := range otherBranches {
+		if other == Master {
+			return "the failure also happens on master, fix it there and cherry-pick it"
+		}
+	}
+	return "the failure happens on several release branches but not on master"
+}
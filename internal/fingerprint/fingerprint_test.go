@@ -0,0 +1,51 @@
+package fingerprint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+)
+
+func TestNew(t *testing.T) {
+	master := New("[sig-node] Pods", "timed out after 300s waiting for pod 0a1b2c3d4e5f-x\nstack trace")
+	release := New("[sig-node] Pods", "Timed out after 120s  waiting for pod 9f8e7d6c5b4a-x")
+	assert.Equal(t, master, release)
+	assert.Len(t, master, 16)
+	assert.NotEqual(t, master, New("[sig-node] Pods", "connection refused"))
+	assert.NotEqual(t, master, New("[sig-network] DNS", "timed out after 300s waiting for pod 0a1b2c3d4e5f-x"))
+}
+
+func TestBranch(t *testing.T) {
+	assert.Equal(t, "master", Branch("sig-release-master-blocking#gce-cos-master-default"))
+	assert.Equal(t, "1.34", Branch("sig-release-1.34-informing#kind-1.34"))
+	assert.Equal(t, "", Branch("sig-node-kubelet#node-e2e"))
+}
+
+func TestCrossBranch(t *testing.T) {
+	failure := v1alpha1.TestResult{TestName: "[sig-node] Pods", Fingerprint: "abc"}
+	tabs := []*v1alpha1.DashboardTab{
+		{BoardHash: "sig-release-master-blocking#gce", TestRuns: []v1alpha1.TestResult{failure}},
+		{BoardHash: "sig-release-master-informing#kind", TestRuns: []v1alpha1.TestResult{failure}},
+		{BoardHash: "sig-release-1.34-blocking#gce", TestRuns: []v1alpha1.TestResult{failure, {TestName: "other", Fingerprint: "def"}}},
+		{BoardHash: "sig-release-1.33-blocking#gce", TestRuns: []v1alpha1.TestResult{{TestName: "other", Fingerprint: "def"}}},
+	}
+	index := NewIndex(tabs)
+
+	occurrences := index.CrossBranch(tabs[2], &failure)
+	assert.Equal(t, []Occurrence{
+		{Branch: "master", BoardHash: "sig-release-master-blocking#gce"},
+		{Branch: "master", BoardHash: "sig-release-master-informing#kind"},
+	}, occurrences)
+	assert.Equal(t, "the failure also happens on master, fix it there and cherry-pick it", Hint("1.34", Branches(occurrences)))
+
+	occurrences = index.CrossBranch(tabs[0], &failure)
+	assert.Equal(t, []Occurrence{{Branch: "1.34", BoardHash: "sig-release-1.34-blocking#gce"}}, occurrences)
+	assert.Equal(t, "the failure also affects release branches, the fix will need cherry-picks", Hint("master", Branches(occurrences)))
+
+	other := tabs[3].TestRuns[0]
+	occurrences = index.CrossBranch(tabs[3], &other)
+	assert.Equal(t, "the failure happens on several release branches but not on master", Hint("1.33", Branches(occurrences)))
+	assert.Empty(t, Hint("master", nil))
+}
@@ -0,0 +1,188 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	g4 "github.com/shurcooL/githubv4"
+)
+
+// BoardField is a single select field of the CI Signal board with the
+// options set by SignalHound on the project items.
+type BoardField struct {
+	Name    string
+	Options []string
+
+	// keyword matches the field under another name, the way the project
+	// items are filled, see itemFieldUpdates.
+	keyword string
+}
+
+// BoardFields returns the standard single select fields of the board for the
+// release cycle, e.g. 1.35.
+func BoardFields(release string) []BoardField {
+	return []BoardField{
+		{Name: "K8s Release", Options: []string{"v" + release}, keyword: "k8s release"},
+		{Name: "View", Options: []string{"issue-tracking"}, keyword: "view"},
+		{Name: "Status", Options: []string{"Drafting", "New", "Observing", "Resolved"}, keyword: "status"},
+		{Name: "Testgrid Board", Options: []string{
+			"master-blocking", "master-informing", release + "-blocking", release + "-informing",
+		}, keyword: "board"},
+	}
+}
+
+// BoardChange is a change of the board applied by BootstrapBoard.
+type BoardChange struct {
+	Field  string
+	Action string
+}
+
+func (c BoardChange) String() string {
+	return fmt.Sprintf("%s: %s", c.Field, c.Action)
+}
+
+// BootstrapBoard sets up the board for a new release cycle: the missing
+// standard fields are created, the fields and options named differently are
+// renamed and the missing options added, e.g. the K8s Release option of the
+// cycle. The existing options and their item values are kept. With dryRun
+// the changes are returned without being applied.
+func (g *ProjectManager) BootstrapBoard(release string, dryRun bool) ([]BoardChange, error) {
+	version := extractVersion(release)
+	if version == "" {
+		return nil, fmt.Errorf("invalid release %q, expected a version like 1.35", release)
+	}
+	// the changes are computed from the current fields, not the cached ones.
+	projectFields.invalidate(g.fieldsKey())
+	fields, err := g.GetProjectFields()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project fields: %w", err)
+	}
+
+	var changes []BoardChange
+	for _, standard := range BoardFields(version) {
+		field, found := findBoardField(fields, standard)
+		if !found {
+			changes = append(changes, BoardChange{standard.Name, "create with options " + strings.Join(standard.Options, ", ")})
+			if !dryRun {
+				if err := g.createField(standard); err != nil {
+					return changes, err
+				}
+			}
+			continue
+		}
+
+		options, err := g.fieldOptions(field.ID)
+		if err != nil {
+			return changes, err
+		}
+		input := UpdateProjectV2FieldInput{FieldID: field.ID, SingleSelectOptions: options}
+		var fieldChanges []BoardChange
+		if string(field.Name) != standard.Name {
+			name := g4.String(standard.Name)
+			input.Name = &name
+			fieldChanges = append(fieldChanges, BoardChange{string(field.Name), "rename to " + standard.Name})
+		}
+		for _, option := range standard.Options {
+			fieldChanges = append(fieldChanges, setOption(&input, standard.Name, option)...)
+		}
+		changes = append(changes, fieldChanges...)
+		if len(fieldChanges) > 0 && !dryRun {
+			if _, err := g.updateFieldOptions(input); err != nil {
+				return changes, err
+			}
+		}
+	}
+	return changes, nil
+}
+
+// findBoardField returns the field named like the standard one, or else the
+// first field matching its keyword.
+func findBoardField(fields []ProjectFieldInfo, standard BoardField) (ProjectFieldInfo, bool) {
+	for _, field := range fields {
+		if normalizeName(string(field.Name)) == normalizeName(standard.Name) {
+			return field, true
+		}
+	}
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(string(field.Name)), standard.keyword) {
+			return field, true
+		}
+	}
+	return ProjectFieldInfo{}, false
+}
+
+// setOption renames the option spelled differently, e.g. "Master Blocking"
+// or "1.35", or appends it when missing.
+func setOption(input *UpdateProjectV2FieldInput, fieldName, name string) []BoardChange {
+	for _, option := range input.SingleSelectOptions {
+		if string(option.Name) == name {
+			return nil
+		}
+	}
+	for i, option := range input.SingleSelectOptions {
+		if sameName(string(option.Name), name) {
+			input.SingleSelectOptions[i].Name = g4.String(name)
+			return []BoardChange{{fieldName, fmt.Sprintf("rename option %s to %s", option.Name, name)}}
+		}
+	}
+	input.SingleSelectOptions = append(input.SingleSelectOptions, projectV2SingleSelectFieldOptions{
+		Name: g4.String(name), Color: g4.ProjectV2SingleSelectFieldOptionColorGray, Description: g4.String(""),
+	})
+	return []BoardChange{{fieldName, "add option " + name}}
+}
+
+// sameName returns true for the names differing by case, spaces,
+// punctuation or the v prefix of a version, e.g. 1.35 and v1.35.
+func sameName(a, b string) bool {
+	a, b = normalizeName(a), normalizeName(b)
+	if a == b {
+		return true
+	}
+	isVersion := func(name string) bool {
+		return strings.HasPrefix(name, "v") && strings.TrimLeftFunc(name[1:], unicode.IsDigit) == ""
+	}
+	switch {
+	case isVersion(a) && !isVersion(b):
+		return a[1:] == b
+	case isVersion(b) && !isVersion(a):
+		return b[1:] == a
+	}
+	return false
+}
+
+// normalizeName lowercases the name and drops its spaces and punctuation.
+func normalizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// createField creates the single select field with its options.
+func (g *ProjectManager) createField(field BoardField) error {
+	options := make([]g4.ProjectV2SingleSelectFieldOptionInput, 0, len(field.Options))
+	for _, option := range field.Options {
+		options = append(options, g4.ProjectV2SingleSelectFieldOptionInput{
+			Name: g4.String(option), Color: g4.ProjectV2SingleSelectFieldOptionColorGray, Description: g4.String(""),
+		})
+	}
+	var mutation struct {
+		CreateProjectV2Field struct {
+			ClientMutationID string
+		} `graphql:"createProjectV2Field(input: $input)"`
+	}
+	input := g4.CreateProjectV2FieldInput{
+		ProjectID:           g4.ID(g.projectID),
+		DataType:            g4.ProjectV2CustomFieldTypeSingleSelect,
+		Name:                g4.String(field.Name),
+		SingleSelectOptions: &options,
+	}
+	if err := g.mutate("createProjectV2Field", &mutation, input, nil); err != nil {
+		return fmt.Errorf("failed to create the %s field: %w", field.Name, err)
+	}
+	projectFields.invalidate(g.fieldsKey())
+	return nil
+}
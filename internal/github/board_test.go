@@ -0,0 +1,50 @@
+package github
+
+import (
+	"testing"
+
+	g4 "github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSameName(t *testing.T) {
+	assert.True(t, sameName("Master Blocking", "master-blocking"))
+	assert.True(t, sameName("1.35", "v1.35"))
+	assert.True(t, sameName("v1.35", "V1.35"))
+	assert.False(t, sameName("1.35-blocking", "1.35-informing"))
+	assert.False(t, sameName("v1.34", "v1.35"))
+	assert.False(t, sameName("View", "iew"))
+}
+
+func TestSetOption(t *testing.T) {
+	id := g4.ID("OPT_blocking")
+	input := &UpdateProjectV2FieldInput{SingleSelectOptions: []projectV2SingleSelectFieldOptions{
+		{ID: &id, Name: "Master Blocking", Color: g4.ProjectV2SingleSelectFieldOptionColorRed},
+	}}
+
+	assert.Equal(t, []BoardChange{{"Testgrid Board", "rename option Master Blocking to master-blocking"}},
+		setOption(input, "Testgrid Board", "master-blocking"))
+	assert.Empty(t, setOption(input, "Testgrid Board", "master-blocking"))
+	assert.Equal(t, []BoardChange{{"Testgrid Board", "add option master-informing"}},
+		setOption(input, "Testgrid Board", "master-informing"))
+
+	if assert.Len(t, input.SingleSelectOptions, 2) {
+		assert.Equal(t, &id, input.SingleSelectOptions[0].ID, "the renamed option keeps its item values")
+		assert.Equal(t, g4.ProjectV2SingleSelectFieldOptionColorRed, input.SingleSelectOptions[0].Color)
+		assert.Nil(t, input.SingleSelectOptions[1].ID)
+	}
+}
+
+func TestFindBoardField(t *testing.T) {
+	fields := []ProjectFieldInfo{{ID: "PVTSSF_status", Name: "Issue Status"}, {ID: "PVTSSF_release", Name: "k8s-release"}}
+	standard := BoardFields("1.35")
+
+	field, found := findBoardField(fields, standard[0])
+	assert.True(t, found)
+	assert.Equal(t, g4.ID("PVTSSF_release"), field.ID)
+	field, found = findBoardField(fields, standard[2])
+	assert.True(t, found)
+	assert.Equal(t, g4.ID("PVTSSF_status"), field.ID)
+	_, found = findBoardField(fields, standard[1])
+	assert.False(t, found)
+}
@@ -0,0 +1,203 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	g4 "github.com/shurcooL/githubv4"
+)
+
+const (
+	// minSimilarity is the share of the words of the test description an
+	// issue title or body must contain to be reported as existing.
+	minSimilarity = 0.75
+
+	// maxBoardItems bounds the project board items scanned for existing
+	// issues, the most recently added ones.
+	maxBoardItems = 100
+
+	// IssuesRepository is the repository searched for the existing issues of
+	// a test, with the project board.
+	IssuesRepository = ORGANIZATION + "/kubernetes"
+)
+
+var (
+	// suiteRegex matches the suite prefix of the e2e test names, left out of
+	// the issue titles, e.g. Kubernetes e2e suite.
+	suiteRegex = regexp.MustCompile(`^[^\[]*\bsuite\.`)
+	tagRegex   = regexp.MustCompile(`\[[^\]]*\]`)
+	wordRegex  = regexp.MustCompile(`[a-z0-9]+`)
+
+	// issueTestRegex and issueBoardRegex read the test and the board of the
+	// first link of the test and job sections of the issue templates.
+	issueTestRegex  = regexp.MustCompile(`(?m)^### Which tests are (?:failing|flaking)\?\s*\n\s*\* \[(.+)\]\(`)
+	issueBoardRegex = regexp.MustCompile(`(?m)^### Which jobs are (?:failing|flaking)\?\s*\n\s*\* \[([^\]#]+)#`)
+)
+
+// FindExistingIssues returns the open issues of IssuesRepository and the
+// draft and open issues of the project board similar to the test, the most
+// similar first. The match is fuzzy, on the words of the test name without
+// its tags and suite, so an issue titled with a shortened or differently
+// tagged test name is still found. The issues filed with the issue templates
+// are matched on the test of their body instead of the whole body, see
+// issueMetadata.
+func (g *ProjectManager) FindExistingIssues(testName string) ([]Issue, error) {
+	if g.githubClient == nil {
+		return nil, errors.New("github GraphQL client is nil")
+	}
+	description := testDescription(testName)
+	if description == "" {
+		return nil, nil
+	}
+
+	var search struct {
+		Search struct {
+			Nodes []struct {
+				Issue struct {
+					ID     g4.ID
+					Number int
+					Title  string
+					URL    string
+					Body   string
+				} `graphql:"... on Issue"`
+			}
+		} `graphql:"search(query: $query, type: ISSUE, first: 20)"`
+	}
+	query := fmt.Sprintf(`repo:%s is:issue is:open "%s" in:title,body sort:updated-desc`, IssuesRepository, searchTerm(description))
+	if err := g.query("searchExistingIssues", &search, map[string]interface{}{"query": g4.String(query)}); err != nil {
+		return nil, fmt.Errorf("failed to search existing issues: %w", err)
+	}
+
+	var board struct {
+		Node struct {
+			ProjectV2 struct {
+				Items struct {
+					Nodes []struct {
+						ID      g4.ID
+						Content struct {
+							Typename string `graphql:"__typename"`
+							Draft    struct {
+								Title string
+								Body  string
+							} `graphql:"... on DraftIssue"`
+							Issue struct {
+								ID     g4.ID
+								Number int
+								Title  string
+								URL    string
+								State  string
+								Body   string
+							} `graphql:"... on Issue"`
+						}
+						FieldValueByName struct {
+							SingleSelect struct {
+								Name string
+							} `graphql:"... on ProjectV2ItemFieldSingleSelectValue"`
+						} `graphql:"fieldValueByName(name: \"Status\")"`
+					}
+				} `graphql:"items(last: $items)"`
+			} `graphql:"... on ProjectV2"`
+		} `graphql:"node(id: $projectID)"`
+	}
+	if err := g.query("listBoardItems", &board, map[string]interface{}{
+		"projectID": g4.ID(g.projectID),
+		"items":     g4.Int(maxBoardItems),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list project board items: %w", err)
+	}
+
+	type scored struct {
+		issue Issue
+		score float64
+	}
+	var found []scored
+	seen := map[string]int{}
+	add := func(issue Issue, body string) {
+		if i, ok := seen[issue.URL]; ok && issue.URL != "" {
+			// the issue found by the search is also on the board
+			if found[i].issue.Status == "" {
+				found[i].issue.Status = issue.Status
+			}
+			return
+		}
+		issue.TestName, issue.Board = issueMetadata(body)
+		if issue.TestName != "" {
+			body = issue.TestName
+		}
+		score := max(similarity(testName, description, issue.Title), similarity(testName, description, body))
+		if score < minSimilarity {
+			return
+		}
+		seen[issue.URL] = len(found)
+		found = append(found, scored{issue: issue, score: score})
+	}
+	for _, node := range search.Search.Nodes {
+		add(Issue{ID: fmt.Sprintf("%v", node.Issue.ID), Number: node.Issue.Number, Title: node.Issue.Title, URL: node.Issue.URL}, node.Issue.Body)
+	}
+	for _, node := range board.Node.ProjectV2.Items.Nodes {
+		content := node.Content
+		status := node.FieldValueByName.SingleSelect.Name
+		switch {
+		case content.Typename == "DraftIssue":
+			add(Issue{ID: fmt.Sprintf("%v", node.ID), Title: content.Draft.Title, Draft: true, Status: status}, content.Draft.Body)
+		case content.Typename == "Issue" && content.Issue.State == "OPEN":
+			add(Issue{ID: fmt.Sprintf("%v", content.Issue.ID), Number: content.Issue.Number, Title: content.Issue.Title, URL: content.Issue.URL,
+				Status: status}, content.Issue.Body)
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool { return found[i].score > found[j].score })
+	issues := make([]Issue, 0, len(found))
+	for _, candidate := range found {
+		issues = append(issues, candidate.issue)
+	}
+	return issues, nil
+}
+
+// issueMetadata returns the test and the board of an issue body rendered
+// from the issue templates, empty strings for the other bodies.
+func issueMetadata(body string) (testName, board string) {
+	if match := issueTestRegex.FindStringSubmatch(body); match != nil {
+		testName = match[1]
+	}
+	if match := issueBoardRegex.FindStringSubmatch(body); match != nil {
+		board = match[1]
+	}
+	return testName, board
+}
+
+// testDescription returns the test name without its tags and suite prefix,
+// e.g. Pods should run for Kubernetes e2e suite.[It] [sig-node] Pods should run.
+func testDescription(testName string) string {
+	description := tagRegex.ReplaceAllString(suiteRegex.ReplaceAllString(testName, ""), " ")
+	return strings.Join(strings.Fields(description), " ")
+}
+
+// similarity returns 1 when the text contains the test name, otherwise the
+// share of the words of the test description found in the text.
+func similarity(testName, description, text string) float64 {
+	if text == "" {
+		return 0
+	}
+	if strings.Contains(text, testName) {
+		return 1
+	}
+	words := wordRegex.FindAllString(strings.ToLower(description), -1)
+	if len(words) == 0 {
+		return 0
+	}
+	textWords := map[string]bool{}
+	for _, word := range wordRegex.FindAllString(strings.ToLower(text), -1) {
+		textWords[word] = true
+	}
+	matched := 0
+	for _, word := range words {
+		if textWords[word] {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(words))
+}
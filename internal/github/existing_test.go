@@ -0,0 +1,22 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueMetadata(t *testing.T) {
+	body := "### Which jobs are flaking?\n\n" +
+		"* [sig-release-master-blocking#gce-cos-master-default](https://testgrid.k8s.io/sig-release-master-blocking#gce-cos-master-default)\n\n" +
+		"### Which tests are flaking?\n\n" +
+		"* [Kubernetes e2e suite.[It] [sig-node] Pods should run](https://prow.k8s.io/view/gs/bucket/logs/job/1)\n\n" +
+		"### Since when has it been flaking?\n"
+	testName, board := issueMetadata(body)
+	assert.Equal(t, "Kubernetes e2e suite.[It] [sig-node] Pods should run", testName)
+	assert.Equal(t, "sig-release-master-blocking", board)
+
+	testName, board = issueMetadata("The [sig-node] Pods test is failing since yesterday.")
+	assert.Empty(t, testName)
+	assert.Empty(t, board)
+}
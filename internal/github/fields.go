@@ -0,0 +1,116 @@
+package github
+
+import (
+	"maps"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/signalhound/internal/monitoring"
+)
+
+// DefaultFieldsTTL is the time the project fields are cached, they only
+// change when the board is set up for a release cycle.
+const DefaultFieldsTTL = 10 * time.Minute
+
+// FieldsTTL is the time GetProjectFields caches the fields of a project
+// between the project managers, 0 disables the cache.
+var FieldsTTL = DefaultFieldsTTL
+
+// projectFields caches the fields per endpoint and project, see
+// ProjectManager.fieldsKey, shared by the project
+// managers as each action creates its own.
+var projectFields = &fieldsCache{entries: map[string]cachedFields{}, queries: map[string]*fieldsQuery{}}
+
+// fieldsCache is a concurrency-safe cache of the project fields. The lock is
+// released during a query, the concurrent lookups of a missing project wait
+// for the query in flight instead of each running one, and the lookups of
+// the other projects don't wait.
+type fieldsCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedFields
+	queries map[string]*fieldsQuery
+}
+
+type cachedFields struct {
+	fields []ProjectFieldInfo
+	at     time.Time
+}
+
+// fieldsQuery is a query in flight, done is closed once its result is set.
+type fieldsQuery struct {
+	done   chan struct{}
+	fields []ProjectFieldInfo
+	err    error
+}
+
+// get returns a copy of the fields of the key cached within the TTL, or of
+// the fields of the query, cached on success.
+func (c *fieldsCache) get(key string, ttl time.Duration, query func() ([]ProjectFieldInfo, error)) ([]ProjectFieldInfo, error) {
+	if ttl <= 0 {
+		return query()
+	}
+	c.mu.Lock()
+	cached, found := c.entries[key]
+	found = found && time.Since(cached.at) < ttl
+	monitoring.RecordCacheLookup("project_fields", found)
+	if found {
+		c.mu.Unlock()
+		return copyFields(cached.fields), nil
+	}
+	running, ok := c.queries[key]
+	if ok {
+		c.mu.Unlock()
+		<-running.done
+		if running.err != nil {
+			return nil, running.err
+		}
+		return copyFields(running.fields), nil
+	}
+	running = &fieldsQuery{done: make(chan struct{})}
+	c.queries[key] = running
+	c.mu.Unlock()
+
+	running.fields, running.err = query()
+	c.mu.Lock()
+	// an invalidation during the query drops it, its fields may be stale
+	if c.queries[key] == running {
+		delete(c.queries, key)
+		if running.err == nil {
+			c.entries[key] = cachedFields{fields: running.fields, at: time.Now()}
+		}
+	}
+	c.mu.Unlock()
+	close(running.done)
+	if running.err != nil {
+		return nil, running.err
+	}
+	return copyFields(running.fields), nil
+}
+
+// copyFields returns a deep copy of the fields, the callers can change the
+// Options of their fields without changing the cached ones.
+func copyFields(fields []ProjectFieldInfo) []ProjectFieldInfo {
+	copied := make([]ProjectFieldInfo, len(fields))
+	for i, field := range fields {
+		copied[i] = field
+		copied[i].Options = maps.Clone(field.Options)
+	}
+	return copied
+}
+
+// invalidate drops the cached fields of the key.
+func (c *fieldsCache) invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+	delete(c.queries, key)
+}
+
+// InvalidateProjectFields drops the cached fields of every project, e.g.
+// after the board fields are changed by hand.
+func InvalidateProjectFields() {
+	projectFields.mu.Lock()
+	defer projectFields.mu.Unlock()
+	projectFields.entries = map[string]cachedFields{}
+	projectFields.queries = map[string]*fieldsQuery{}
+}
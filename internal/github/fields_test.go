@@ -0,0 +1,92 @@
+package github
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldsCache(t *testing.T) {
+	cache := &fieldsCache{entries: map[string]cachedFields{}, queries: map[string]*fieldsQuery{}}
+	queries := 0
+	var queryErr error
+	query := func() ([]ProjectFieldInfo, error) {
+		queries++
+		return []ProjectFieldInfo{{ID: "PVTSSF_1", Name: "Status", Options: map[string]interface{}{"Failing": "1"}}}, queryErr
+	}
+
+	fields, err := cache.get("project", time.Minute, query)
+	assert.NoError(t, err)
+	assert.Equal(t, []ProjectFieldInfo{{ID: "PVTSSF_1", Name: "Status", Options: map[string]interface{}{"Failing": "1"}}}, fields)
+
+	// the callers get their own copy of the options
+	fields[0].Options["Flaking"] = "2"
+	fields, _ = cache.get("project", time.Minute, query)
+	assert.Equal(t, map[string]interface{}{"Failing": "1"}, fields[0].Options)
+	assert.Equal(t, 1, queries)
+
+	// another project, the invalidation and a disabled cache query again
+	_, _ = cache.get("other", time.Minute, query)
+	cache.invalidate("project")
+	_, _ = cache.get("project", time.Minute, query)
+	_, _ = cache.get("project", 0, query)
+	assert.Equal(t, 4, queries)
+
+	// the errors are not cached, nor are the fields after the TTL
+	cache.invalidate("project")
+	queryErr = errors.New("rate limited")
+	_, err = cache.get("project", time.Minute, query)
+	assert.Error(t, err)
+	queryErr = nil
+	_, _ = cache.get("project", time.Minute, query)
+	_, _ = cache.get("project", time.Nanosecond, query)
+	assert.Equal(t, 7, queries)
+}
+
+func TestFieldsCacheQueries(t *testing.T) {
+	cache := &fieldsCache{entries: map[string]cachedFields{}, queries: map[string]*fieldsQuery{}}
+	var queries atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+	slow := func() ([]ProjectFieldInfo, error) {
+		queries.Add(1)
+		close(started)
+		<-release
+		return []ProjectFieldInfo{{ID: "PVTSSF_1", Name: "Status"}}, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([][]ProjectFieldInfo, 3)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], _ = cache.get("project", time.Minute, slow)
+	}()
+	<-started
+
+	// the other projects don't wait for the query in flight
+	fields, err := cache.get("other", time.Minute, func() ([]ProjectFieldInfo, error) {
+		return []ProjectFieldInfo{{ID: "PVTSSF_2", Name: "Priority"}}, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Priority", string(fields[0].Name))
+
+	// the lookups of the same project share it
+	for i := 1; i < len(results); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = cache.get("project", time.Minute, slow)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), queries.Load())
+	for _, result := range results {
+		assert.Equal(t, []ProjectFieldInfo{{ID: "PVTSSF_1", Name: "Status"}}, result)
+	}
+}
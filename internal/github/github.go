@@ -2,14 +2,21 @@ package github
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	g4 "github.com/shurcooL/githubv4"
 	"golang.org/x/oauth2"
+
+	"sigs.k8s.io/signalhound/internal/monitoring"
+	"sigs.k8s.io/signalhound/internal/release"
 )
 
 const (
@@ -17,9 +24,45 @@ const (
 	ORGANIZATION = "kubernetes"
 )
 
+// GraphQLURL is the GitHub GraphQL endpoint, replaced to run against a fake
+// server.
+var GraphQLURL = "https://api.github.com/graphql"
+
+var (
+	// Release is the K8s Release option set on new project items, e.g. 1.35,
+	// the latest release option of the board is used when empty or missing.
+	Release string
+
+	// CreateReleaseOption adds the Release option to the K8s Release field of
+	// the board when it is missing, e.g. for the first issue of a new cycle.
+	// Opt-in, the option is shared by every user of the board.
+	CreateReleaseOption bool
+
+	// DryRun logs the mutations of the project managers created afterwards
+	// to DryRunLog instead of running them, the queries still run. The
+	// created issues are synthetic, see Issue.DryRun.
+	DryRun bool
+
+	// DryRunLog receives a line per mutation skipped by DryRun.
+	DryRunLog io.Writer = os.Stderr
+)
+
 type ProjectManagerInterface interface {
 	GetProjectFields() ([]ProjectFieldInfo, error)
 	CreateDraftIssue(title, body, board string) error
+	CreateIssue(title, body, board string, options *IssueOptions) (*Issue, error)
+	ListMergedPullRequests(owner, repo string, from, to time.Time) ([]PullRequest, error)
+	FindClosedIssue(owner, repo, testName string, closedSince time.Time) (*Issue, error)
+	ReopenIssue(issue *Issue, comment string) error
+	FindOpenIssue(owner, repo, testName string) (*Issue, error)
+	CountOpenIssues(owner, repo string, labels []string, milestone string) (int, error)
+	GetIssue(owner, repo string, number int) (*Issue, error)
+	CommentIssue(issue *Issue, comment string) error
+	CloseIssue(issue *Issue, comment string) error
+	FindExistingIssues(testName string) ([]Issue, error)
+	ListBoardIssues() ([]BoardIssue, error)
+	BootstrapBoard(release string, dryRun bool) ([]BoardChange, error)
+	RateLimit() (*RateLimit, error)
 }
 
 // ProjectManager represents a GitHub organization with a global workflow file and reference
@@ -35,6 +78,13 @@ type ProjectManager struct {
 
 	// githubClient is the official GitHub API v4 (GraphQL) client
 	githubClient *g4.Client
+
+	// graphQLURL is the endpoint of githubClient, the fields are cached per
+	// endpoint and project.
+	graphQLURL string
+
+	// dryRun logs the mutations instead of running them, see DryRun.
+	dryRun bool
 }
 
 // ProjectFieldInfo represents a project field with its options
@@ -50,18 +100,56 @@ func NewProjectManager(ctx context.Context, token string) ProjectManagerInterfac
 		organization: ORGANIZATION,
 		projectID:    PROJECT_ID,
 		fields:       map[string]ProjectFieldInfo{},
-		githubClient: g4.NewClient(oauth2.NewClient(
+		githubClient: g4.NewEnterpriseClient(GraphQLURL, oauth2.NewClient(
 			ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}),
 		)),
+		graphQLURL: GraphQLURL,
+		dryRun:     DryRun,
+	}
+}
+
+// query runs the GraphQL query, recording the operation latency and result.
+func (g *ProjectManager) query(operation string, q interface{}, variables map[string]interface{}) error {
+	start := time.Now()
+	err := g.githubClient.Query(context.Background(), q, variables)
+	monitoring.RecordGitHubRequest(operation, start, err)
+	return err
+}
+
+// mutate runs the GraphQL mutation, recording the operation latency and result.
+// In a dry run the mutation and its input are logged to DryRunLog instead, m
+// is left with its zero values.
+func (g *ProjectManager) mutate(operation string, m interface{}, input g4.Input, variables map[string]interface{}) error {
+	if g.dryRun {
+		encoded, err := json.Marshal(input)
+		if err != nil {
+			return fmt.Errorf("error encoding the %s input: %v", operation, err)
+		}
+		fmt.Fprintf(DryRunLog, "dry run: skipped %s %s\n", operation, encoded) // nolint
+		return nil
 	}
+	start := time.Now()
+	err := g.githubClient.Mutate(context.Background(), m, input, variables)
+	monitoring.RecordGitHubRequest(operation, start, err)
+	return err
 }
 
-// GetProjectFields queries the project fields and their options
+// GetProjectFields returns the project fields and their options, cached for
+// FieldsTTL. The field changes of the project manager invalidate the cache.
 func (g *ProjectManager) GetProjectFields() ([]ProjectFieldInfo, error) {
 	if g.githubClient == nil {
 		return nil, errors.New("github GraphQL client is nil")
 	}
+	return projectFields.get(g.fieldsKey(), FieldsTTL, g.queryProjectFields)
+}
+
+// fieldsKey is the key of the project fields in the cache.
+func (g *ProjectManager) fieldsKey() string {
+	return g.graphQLURL + "#" + g.projectID
+}
 
+// queryProjectFields queries the project fields and their options.
+func (g *ProjectManager) queryProjectFields() ([]ProjectFieldInfo, error) {
 	var query struct {
 		Node struct {
 			ProjectV2 struct {
@@ -92,7 +180,7 @@ func (g *ProjectManager) GetProjectFields() ([]ProjectFieldInfo, error) {
 		"projectID": g4.ID(g.projectID),
 	}
 
-	if err := g.githubClient.Query(context.Background(), &query, variables); err != nil {
+	if err := g.query("projectFields", &query, variables); err != nil {
 		return nil, fmt.Errorf("failed to query project fields: %w", err)
 	}
 
@@ -136,9 +224,48 @@ func (g *ProjectManager) CreateDraftIssue(title, body, board string) error {
 	}
 
 	// first, get the project fields to find the correct field IDs and option IDs
+	updates, err := g.itemFieldUpdates(board)
+	if err != nil {
+		return err
+	}
+
+	// create the draft issue
+	var mutationDraft struct {
+		AddProjectV2DraftIssue struct {
+			ProjectItem struct {
+				ID g4.ID
+			}
+		} `graphql:"addProjectV2DraftIssue(input: $input)"`
+	}
+	bodyInput := g4.String(body)
+	inputDraft := g4.AddProjectV2DraftIssueInput{
+		ProjectID: g4.ID(g.projectID),
+		Title:     g4.String(title),
+		Body:      &bodyInput,
+	}
+
+	if err := g.mutate("addProjectV2DraftIssue", &mutationDraft, inputDraft, nil); err != nil {
+		return fmt.Errorf("failed to create draft issue: %w", err)
+	}
+
+	g.updateItemFields(mutationDraft.AddProjectV2DraftIssue.ProjectItem.ID, updates)
+	return nil
+}
+
+// itemFieldUpdate is a single select field value set on new project items.
+type itemFieldUpdate struct {
+	fieldID   g4.ID
+	optionID  g4.ID
+	fieldName string
+}
+
+// itemFieldUpdates returns the field values of a new project item of the
+// board: the K8s release, see releaseOption, the issue-tracking view, the drafting status
+// and the testgrid board.
+func (g *ProjectManager) itemFieldUpdates(board string) ([]itemFieldUpdate, error) {
 	fields, err := g.GetProjectFields()
 	if err != nil {
-		return fmt.Errorf("failed to get project fields: %w", err)
+		return nil, fmt.Errorf("failed to get project fields: %w", err)
 	}
 
 	// find the fields we need
@@ -151,20 +278,8 @@ func (g *ProjectManager) CreateDraftIssue(title, body, board string) error {
 		// find K8s Release field - look for fields containing "k8s", "release", or "version"
 		if strings.Contains(fieldNameLower, "k8s release") {
 			k8sReleaseFieldID = field.ID
-			// find the latest version option (highest version number)
-			latestVersion := ""
-			latestVersionID := g4.ID("")
-			for optName, optID := range field.Options {
-				// extract version number from option name (e.g., "v1.32" -> "1.32")
-				if version := extractVersion(optName); version != "" {
-					if latestVersion == "" || compareVersions(version, latestVersion) > 0 {
-						latestVersion = version
-						latestVersionID = optID
-					}
-				}
-			}
-			if latestVersionID != g4.ID("") {
-				k8sReleaseValueID = latestVersionID
+			if k8sReleaseValueID, err = g.releaseOption(field, board); err != nil {
+				return nil, err
 			}
 		}
 
@@ -205,47 +320,173 @@ func (g *ProjectManager) CreateDraftIssue(title, body, board string) error {
 		}
 	}
 
-	// create the draft issue
-	var mutationDraft struct {
-		AddProjectV2DraftIssue struct {
-			ProjectItem struct {
-				ID g4.ID
+	return []itemFieldUpdate{
+		{k8sReleaseFieldID, k8sReleaseValueID, "K8s Release"},
+		{viewFieldID, viewValueID, "View"},
+		{statusFieldID, statusValueID, "Status"},
+		{boardFieldID, boardValueID, "Testgrid Board"},
+	}, nil
+}
+
+// releaseOption returns the option of the K8s Release field matching the
+// release branch of the board, e.g. v1.32 for sig-release-1.32-blocking, or
+// Release on master, created when missing and CreateReleaseOption is set,
+// falling back to the latest version option (highest version number). It
+// returns the error of the option creation, the item is not created then.
+func (g *ProjectManager) releaseOption(field ProjectFieldInfo, board string) (g4.ID, error) {
+	latestVersion := ""
+	latestVersionID := g4.ID("")
+	for optName, optID := range field.Options {
+		// extract version number from option name (e.g., "v1.32" -> "1.32")
+		if version := extractVersion(optName); version != "" {
+			if latestVersion == "" || compareVersions(version, latestVersion) > 0 {
+				latestVersion = version
+				latestVersionID = optID
 			}
-		} `graphql:"addProjectV2DraftIssue(input: $input)"`
+		}
 	}
-	bodyInput := g4.String(body)
-	inputDraft := g4.AddProjectV2DraftIssueInput{
-		ProjectID: g4.ID(g.projectID),
-		Title:     g4.String(title),
-		Body:      &bodyInput,
+
+	version := extractVersion(Release)
+	if milestone := release.Milestone(board); milestone != "" {
+		version = extractVersion(milestone)
 	}
+	if version == "" {
+		return latestVersionID, nil
+	}
+	for optName, optID := range field.Options {
+		if extractVersion(optName) == version {
+			return optID, nil
+		}
+	}
+	// options of past releases are never created, the schedule is stale.
+	if !CreateReleaseOption || (latestVersion != "" && compareVersions(version, latestVersion) < 0) {
+		return latestVersionID, nil
+	}
+	optionID, err := g.createFieldOption(field.ID, "v"+version, latestVersionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the v%s option of the K8s Release field: %w", version, err)
+	}
+	return optionID, nil
+}
 
-	if err := g.githubClient.Mutate(context.Background(), &mutationDraft, inputDraft, nil); err != nil {
-		return fmt.Errorf("failed to create draft issue: %w", err)
+// UpdateProjectV2FieldInput is the input of the updateProjectV2Field
+// mutation, missing in githubv4.
+type UpdateProjectV2FieldInput struct {
+	FieldID             g4.ID                               `json:"fieldId"`
+	Name                *g4.String                          `json:"name,omitempty"`
+	SingleSelectOptions []projectV2SingleSelectFieldOptions `json:"singleSelectOptions"`
+}
+
+// projectV2SingleSelectFieldOptions is a single select option, the ID keeps
+// the existing options and their item values when the options are replaced.
+type projectV2SingleSelectFieldOptions struct {
+	ID          *g4.ID                                   `json:"id,omitempty"`
+	Name        g4.String                                `json:"name"`
+	Color       g4.ProjectV2SingleSelectFieldOptionColor `json:"color"`
+	Description g4.String                                `json:"description"`
+}
+
+// createFieldOption appends the option to the single select field, with the
+// color of the like option, and returns its ID. The mutation replaces the
+// options, so the existing ones are sent back first.
+func (g *ProjectManager) createFieldOption(fieldID g4.ID, name string, likeID g4.ID) (g4.ID, error) {
+	options, err := g.fieldOptions(fieldID)
+	if err != nil {
+		return nil, err
+	}
+	input := UpdateProjectV2FieldInput{FieldID: fieldID, SingleSelectOptions: options}
+	color := g4.ProjectV2SingleSelectFieldOptionColorGray
+	for _, option := range options {
+		if *option.ID == likeID && option.Color != "" {
+			color = option.Color
+		}
 	}
+	input.SingleSelectOptions = append(input.SingleSelectOptions, projectV2SingleSelectFieldOptions{
+		Name: g4.String(name), Color: color, Description: g4.String(""),
+	})
 
-	itemID := mutationDraft.AddProjectV2DraftIssue.ProjectItem.ID
+	updated, err := g.updateFieldOptions(input)
+	if err != nil {
+		return nil, err
+	}
+	// the option isn't created in a dry run, the items keep the like option.
+	if g.dryRun {
+		return likeID, nil
+	}
+	for _, option := range updated {
+		if string(option.Name) == name {
+			return option.ID, nil
+		}
+	}
+	return nil, fmt.Errorf("option %s missing from the updated field", name)
+}
+
+// fieldOptions returns the options of the single select field, with their
+// IDs to keep them and their item values in an updateProjectV2Field input.
+func (g *ProjectManager) fieldOptions(fieldID g4.ID) ([]projectV2SingleSelectFieldOptions, error) {
+	var query struct {
+		Node struct {
+			ProjectV2SingleSelectField struct {
+				Options []struct {
+					ID          g4.ID
+					Name        g4.String
+					Color       g4.ProjectV2SingleSelectFieldOptionColor
+					Description g4.String
+				}
+			} `graphql:"... on ProjectV2SingleSelectField"`
+		} `graphql:"node(id: $fieldID)"`
+	}
+	if err := g.query("projectFieldOptions", &query, map[string]interface{}{"fieldID": fieldID}); err != nil {
+		return nil, fmt.Errorf("failed to query field options: %w", err)
+	}
+
+	var options []projectV2SingleSelectFieldOptions
+	for _, option := range query.Node.ProjectV2SingleSelectField.Options {
+		optionID := option.ID
+		options = append(options, projectV2SingleSelectFieldOptions{
+			ID: &optionID, Name: option.Name, Color: option.Color, Description: option.Description,
+		})
+	}
+	return options, nil
+}
+
+// fieldOption is an option of a single select field.
+type fieldOption struct {
+	ID   g4.ID
+	Name g4.String
+}
+
+// updateFieldOptions replaces the field options, and its name when set, and
+// returns the updated options.
+func (g *ProjectManager) updateFieldOptions(input UpdateProjectV2FieldInput) ([]fieldOption, error) {
+	var mutation struct {
+		UpdateProjectV2Field struct {
+			ProjectV2Field struct {
+				ProjectV2SingleSelectField struct {
+					Options []fieldOption
+				} `graphql:"... on ProjectV2SingleSelectField"`
+			}
+		} `graphql:"updateProjectV2Field(input: $input)"`
+	}
+	if err := g.mutate("updateProjectV2Field", &mutation, input, nil); err != nil {
+		return nil, fmt.Errorf("failed to update field options: %w", err)
+	}
+	projectFields.invalidate(g.fieldsKey())
+	return mutation.UpdateProjectV2Field.ProjectV2Field.ProjectV2SingleSelectField.Options, nil
+}
+
+// updateItemFields sets the field values of a project item, a field failing
+// to update is reported without failing the item creation.
+func (g *ProjectManager) updateItemFields(itemID g4.ID, updates []itemFieldUpdate) {
 	var mutationUpdate struct {
 		UpdateProjectV2ItemFieldValue struct {
 			ClientMutationID string
 		} `graphql:"updateProjectV2ItemFieldValue(input: $input)"`
 	}
-
-	fieldUpdates := []struct {
-		fieldID   g4.ID
-		optionID  g4.ID
-		fieldName string
-	}{
-		{k8sReleaseFieldID, k8sReleaseValueID, "K8s Release"},
-		{viewFieldID, viewValueID, "View"},
-		{statusFieldID, statusValueID, "Status"},
-		{boardFieldID, boardValueID, "Testgrid Board"},
-	}
-
-	for _, update := range fieldUpdates {
+	for _, update := range updates {
 		if update.fieldID != "" && update.optionID != "" {
 			optionIDStr := fmt.Sprintf("%s", update.optionID)
-			if err := g.githubClient.Mutate(context.Background(), &mutationUpdate, g4.UpdateProjectV2ItemFieldValueInput{
+			if err := g.mutate("updateProjectV2ItemFieldValue", &mutationUpdate, g4.UpdateProjectV2ItemFieldValueInput{
 				ProjectID: g4.ID(g.projectID),
 				ItemID:    itemID,
 				FieldID:   update.fieldID,
@@ -255,7 +496,6 @@ func (g *ProjectManager) CreateDraftIssue(title, body, board string) error {
 			}
 		}
 	}
-	return nil
 }
 
 // extractVersion extracts a version string from text (e.g., "v1.32" -> "1.32", "1.30" -> "1.30")
@@ -0,0 +1,56 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRun(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	var log bytes.Buffer
+	previousURL, previousLog := GraphQLURL, DryRunLog
+	GraphQLURL, DryRun, DryRunLog = server.URL, true, &log
+	t.Cleanup(func() { GraphQLURL, DryRun, DryRunLog = previousURL, false, previousLog })
+
+	manager := NewProjectManager(context.Background(), "token")
+	assert.NoError(t, manager.CloseIssue(&Issue{ID: "I_1", Number: 1}, "fixed"))
+	assert.Zero(t, requests)
+	assert.Equal(t, "dry run: skipped addComment {\"subjectId\":\"I_1\",\"body\":\"fixed\"}\n"+
+		"dry run: skipped closeIssue {\"issueId\":\"I_1\",\"stateReason\":\"COMPLETED\"}\n", log.String())
+
+	// the managers created without DryRun run the mutations
+	DryRun = false
+	assert.Error(t, NewProjectManager(context.Background(), "token").CommentIssue(&Issue{ID: "I_1", Number: 1}, "fixed"))
+	assert.Equal(t, 1, requests)
+}
+
+func TestReleaseOptionError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	previousURL, previousRelease := GraphQLURL, Release
+	GraphQLURL, Release, CreateReleaseOption = server.URL, "v1.35", true
+	t.Cleanup(func() { GraphQLURL, Release, CreateReleaseOption = previousURL, previousRelease, false })
+
+	manager := NewProjectManager(context.Background(), "token").(*ProjectManager)
+	field := ProjectFieldInfo{ID: "PVTSSF_1", Name: "K8s Release", Options: map[string]interface{}{"v1.34": "1"}}
+	_, err := manager.releaseOption(field, "sig-release-master-blocking")
+	assert.ErrorContains(t, err, "failed to create the v1.35 option of the K8s Release field")
+
+	// the existing option is used without creating it
+	field.Options["v1.35"] = "2"
+	id, err := manager.releaseOption(field, "sig-release-master-blocking")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", id)
+}
@@ -0,0 +1,426 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	g4 "github.com/shurcooL/githubv4"
+)
+
+// maxSearchTermLength keeps the issue search query under the GitHub limit of
+// 256 characters, long test names are matched on their prefix first.
+const maxSearchTermLength = 200
+
+// Issue is a GitHub issue filed for a test.
+type Issue struct {
+	ID       string
+	Number   int
+	Title    string
+	URL      string
+	ClosedAt time.Time
+
+	// Draft is set for a draft issue of the project board, it has no number
+	// nor URL and its ID is the project item ID.
+	Draft bool
+
+	// DryRun is set for the synthetic issue returned by CreateIssue in a dry
+	// run, it has no ID, number nor URL.
+	DryRun bool
+
+	// TestName and Board are read from the body of the issues filed with the
+	// issue templates, empty for the others. Status is the Status field of
+	// the project board item, empty for the issues not on the board.
+	TestName string
+	Board    string
+	Status   string
+}
+
+// IssueOptions are the repository and metadata of an issue filed in a
+// repository instead of as a draft of the project board.
+type IssueOptions struct {
+	Owner     string
+	Repo      string
+	Labels    []string
+	Assignees []string
+	Milestone string
+}
+
+// FindClosedIssue returns the most recently closed issue of owner/repo with a
+// title ending with the test name and closed after closedSince, or nil when
+// there is none.
+func (g *ProjectManager) FindClosedIssue(owner, repo, testName string, closedSince time.Time) (*Issue, error) {
+	issues, err := g.searchIssues("searchClosedIssues", fmt.Sprintf(`repo:%s/%s is:issue is:closed in:title "%s" closed:>=%s sort:updated-desc`,
+		owner, repo, searchTerm(testName), closedSince.UTC().Format("2006-01-02")), testName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search closed issues: %w", err)
+	}
+
+	var found *Issue
+	for _, issue := range issues {
+		if issue.ClosedAt.Before(closedSince) {
+			continue
+		}
+		if found == nil || issue.ClosedAt.After(found.ClosedAt) {
+			found = &issue
+		}
+	}
+	return found, nil
+}
+
+// FindOpenIssue returns the most recently updated open issue of owner/repo
+// with a title ending with the test name, or nil when there is none.
+func (g *ProjectManager) FindOpenIssue(owner, repo, testName string) (*Issue, error) {
+	issues, err := g.searchIssues("searchOpenIssues", fmt.Sprintf(`repo:%s/%s is:issue is:open in:title "%s" sort:updated-desc`,
+		owner, repo, searchTerm(testName)), testName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search open issues: %w", err)
+	}
+	if len(issues) == 0 {
+		return nil, nil
+	}
+	return &issues[0], nil
+}
+
+// CountOpenIssues returns the number of open issues of owner/repo with all
+// the labels, in the milestone when not empty.
+func (g *ProjectManager) CountOpenIssues(owner, repo string, labels []string, milestone string) (int, error) {
+	if g.githubClient == nil {
+		return 0, errors.New("github GraphQL client is nil")
+	}
+	search := fmt.Sprintf("repo:%s/%s is:issue is:open", owner, repo)
+	for _, label := range labels {
+		search += fmt.Sprintf(` label:"%s"`, label)
+	}
+	if milestone != "" {
+		search += fmt.Sprintf(` milestone:"%s"`, milestone)
+	}
+
+	var query struct {
+		Search struct {
+			IssueCount int
+		} `graphql:"search(query: $query, type: ISSUE)"`
+	}
+	if err := g.query("countOpenIssues", &query, map[string]interface{}{"query": g4.String(search)}); err != nil {
+		return 0, fmt.Errorf("failed to count open issues: %w", err)
+	}
+	return query.Search.IssueCount, nil
+}
+
+// GetIssue returns the issue of owner/repo with the number, e.g. the umbrella
+// tracking issue of a release, open or closed.
+func (g *ProjectManager) GetIssue(owner, repo string, number int) (*Issue, error) {
+	if g.githubClient == nil {
+		return nil, errors.New("github GraphQL client is nil")
+	}
+
+	var query struct {
+		Repository struct {
+			Issue struct {
+				ID       g4.ID
+				Number   int
+				Title    string
+				URL      string
+				ClosedAt *g4.DateTime
+			} `graphql:"issue(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $repo)"`
+	}
+	if err := g.query("getIssue", &query, map[string]interface{}{
+		"owner":  g4.String(owner),
+		"repo":   g4.String(repo),
+		"number": g4.Int(number),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to get issue %s/%s#%d: %w", owner, repo, number, err)
+	}
+	node := query.Repository.Issue
+	issue := &Issue{ID: fmt.Sprintf("%v", node.ID), Number: node.Number, Title: node.Title, URL: node.URL}
+	if node.ClosedAt != nil {
+		issue.ClosedAt = node.ClosedAt.Time
+	}
+	return issue, nil
+}
+
+// searchIssues returns the issues found by the search query with a title
+// ending with the test name, in the search order.
+func (g *ProjectManager) searchIssues(operation, search, testName string) ([]Issue, error) {
+	if g.githubClient == nil {
+		return nil, errors.New("github GraphQL client is nil")
+	}
+
+	var query struct {
+		Search struct {
+			Nodes []struct {
+				Issue struct {
+					ID       g4.ID
+					Number   int
+					Title    string
+					URL      string
+					ClosedAt *g4.DateTime
+				} `graphql:"... on Issue"`
+			}
+		} `graphql:"search(query: $query, type: ISSUE, first: 20)"`
+	}
+	if err := g.query(operation, &query, map[string]interface{}{"query": g4.String(search)}); err != nil {
+		return nil, err
+	}
+
+	var issues []Issue
+	for _, node := range query.Search.Nodes {
+		if !strings.HasSuffix(strings.TrimSpace(node.Issue.Title), testName) {
+			continue
+		}
+		issue := Issue{
+			ID:     fmt.Sprintf("%v", node.Issue.ID),
+			Number: node.Issue.Number,
+			Title:  node.Issue.Title,
+			URL:    node.Issue.URL,
+		}
+		if node.Issue.ClosedAt != nil {
+			issue.ClosedAt = node.Issue.ClosedAt.Time
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// searchTerm quotes the test name for the issue search query, long test
+// names are matched on their prefix first.
+func searchTerm(testName string) string {
+	term := strings.ReplaceAll(testName, `"`, "")
+	if len(term) > maxSearchTermLength {
+		term = term[:maxSearchTermLength]
+	}
+	return term
+}
+
+// ReopenIssue reopens the issue and adds the comment to it, keeping the
+// investigation history in a single thread.
+func (g *ProjectManager) ReopenIssue(issue *Issue, comment string) error {
+	if g.githubClient == nil {
+		return errors.New("github GraphQL client is nil")
+	}
+
+	var mutationReopen struct {
+		ReopenIssue struct {
+			ClientMutationID string
+		} `graphql:"reopenIssue(input: $input)"`
+	}
+	if err := g.mutate("reopenIssue", &mutationReopen, g4.ReopenIssueInput{
+		IssueID: g4.ID(issue.ID),
+	}, nil); err != nil {
+		return fmt.Errorf("failed to reopen issue #%d: %w", issue.Number, err)
+	}
+	return g.CommentIssue(issue, comment)
+}
+
+// CommentIssue adds the comment to the issue, leaving its state as it is.
+func (g *ProjectManager) CommentIssue(issue *Issue, comment string) error {
+	if g.githubClient == nil {
+		return errors.New("github GraphQL client is nil")
+	}
+
+	var mutationComment struct {
+		AddComment struct {
+			ClientMutationID string
+		} `graphql:"addComment(input: $input)"`
+	}
+	if err := g.mutate("addComment", &mutationComment, g4.AddCommentInput{
+		SubjectID: g4.ID(issue.ID),
+		Body:      g4.String(comment),
+	}, nil); err != nil {
+		return fmt.Errorf("failed to comment on issue #%d: %w", issue.Number, err)
+	}
+	return nil
+}
+
+// CloseIssue adds the comment to the issue and closes it as completed, so
+// the thread documents the resolution.
+func (g *ProjectManager) CloseIssue(issue *Issue, comment string) error {
+	if err := g.CommentIssue(issue, comment); err != nil {
+		return err
+	}
+
+	var mutationClose struct {
+		CloseIssue struct {
+			ClientMutationID string
+		} `graphql:"closeIssue(input: $input)"`
+	}
+	reason := g4.IssueClosedStateReasonCompleted
+	if err := g.mutate("closeIssue", &mutationClose, g4.CloseIssueInput{
+		IssueID:     g4.ID(issue.ID),
+		StateReason: &reason,
+	}, nil); err != nil {
+		return fmt.Errorf("failed to close issue #%d: %w", issue.Number, err)
+	}
+	return nil
+}
+
+// CreateIssue creates the issue in the options repository with its labels,
+// assignees and milestone, and adds it to the project board like a draft.
+func (g *ProjectManager) CreateIssue(title, body, board string, options *IssueOptions) (*Issue, error) {
+	if g.githubClient == nil {
+		return nil, errors.New("github GraphQL client is nil")
+	}
+
+	updates, err := g.itemFieldUpdates(board)
+	if err != nil {
+		return nil, err
+	}
+	input, err := g.createIssueInput(title, body, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var mutationIssue struct {
+		CreateIssue struct {
+			Issue struct {
+				ID     g4.ID
+				Number int
+				URL    string
+			}
+		} `graphql:"createIssue(input: $input)"`
+	}
+	if err := g.mutate("createIssue", &mutationIssue, input, nil); err != nil {
+		return nil, fmt.Errorf("failed to create issue in %s/%s: %w", options.Owner, options.Repo, err)
+	}
+	created := &Issue{
+		ID:     fmt.Sprintf("%v", mutationIssue.CreateIssue.Issue.ID),
+		Number: mutationIssue.CreateIssue.Issue.Number,
+		Title:  title,
+		URL:    mutationIssue.CreateIssue.Issue.URL,
+		DryRun: g.dryRun,
+	}
+
+	var mutationItem struct {
+		AddProjectV2ItemById struct {
+			Item struct {
+				ID g4.ID
+			}
+		} `graphql:"addProjectV2ItemById(input: $input)"`
+	}
+	if err := g.mutate("addProjectV2ItemById", &mutationItem, g4.AddProjectV2ItemByIdInput{
+		ProjectID: g4.ID(g.projectID),
+		ContentID: mutationIssue.CreateIssue.Issue.ID,
+	}, nil); err != nil {
+		return created, fmt.Errorf("failed to add issue #%d to the project: %w", created.Number, err)
+	}
+	g.updateItemFields(mutationItem.AddProjectV2ItemById.Item.ID, updates)
+	return created, nil
+}
+
+// createIssueInput resolves the repository, labels, assignees and milestone
+// names of the options to their node IDs.
+func (g *ProjectManager) createIssueInput(title, body string, options *IssueOptions) (*g4.CreateIssueInput, error) {
+	var query struct {
+		Repository struct {
+			ID         g4.ID
+			Milestones struct {
+				Nodes []struct {
+					ID    g4.ID
+					Title string
+				}
+			} `graphql:"milestones(first: 20, states: OPEN, query: $milestone)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	variables := map[string]interface{}{
+		"owner":     g4.String(options.Owner),
+		"name":      g4.String(options.Repo),
+		"milestone": g4.String(options.Milestone),
+	}
+	if err := g.query("repository", &query, variables); err != nil {
+		return nil, fmt.Errorf("failed to get repository %s/%s: %w", options.Owner, options.Repo, err)
+	}
+
+	bodyInput := g4.String(body)
+	input := &g4.CreateIssueInput{
+		RepositoryID: query.Repository.ID,
+		Title:        g4.String(title),
+		Body:         &bodyInput,
+	}
+	if options.Milestone != "" {
+		for _, milestone := range query.Repository.Milestones.Nodes {
+			if milestone.Title == options.Milestone {
+				input.MilestoneID = &milestone.ID
+				break
+			}
+		}
+		if input.MilestoneID == nil {
+			return nil, fmt.Errorf("milestone %q not found in %s/%s", options.Milestone, options.Owner, options.Repo)
+		}
+	}
+
+	var labelIDs []g4.ID
+	for _, name := range options.Labels {
+		var labelQuery struct {
+			Repository struct {
+				Label *struct {
+					ID g4.ID
+				} `graphql:"label(name: $label)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+		if err := g.query("label", &labelQuery, map[string]interface{}{
+			"owner": g4.String(options.Owner),
+			"name":  g4.String(options.Repo),
+			"label": g4.String(name),
+		}); err != nil {
+			return nil, fmt.Errorf("failed to get label %q: %w", name, err)
+		}
+		if labelQuery.Repository.Label == nil {
+			return nil, fmt.Errorf("label %q not found in %s/%s", name, options.Owner, options.Repo)
+		}
+		labelIDs = append(labelIDs, labelQuery.Repository.Label.ID)
+	}
+	if len(labelIDs) > 0 {
+		input.LabelIDs = &labelIDs
+	}
+
+	var assigneeIDs []g4.ID
+	for _, login := range options.Assignees {
+		var userQuery struct {
+			User *struct {
+				ID g4.ID
+			} `graphql:"user(login: $login)"`
+		}
+		if err := g.query("user", &userQuery, map[string]interface{}{
+			"login": g4.String(login),
+		}); err != nil {
+			return nil, fmt.Errorf("failed to get user %q: %w", login, err)
+		}
+		if userQuery.User == nil {
+			return nil, fmt.Errorf("user %q not found", login)
+		}
+		assigneeIDs = append(assigneeIDs, userQuery.User.ID)
+	}
+	if len(assigneeIDs) > 0 {
+		input.AssigneeIDs = &assigneeIDs
+	}
+	return input, nil
+}
+
+// ReopenOrCreateDraft reopens the issue of the test closed within the window
+// with the body as a comment, instead of filing an unrelated duplicate. When
+// there is no such issue, or the window is zero, a draft issue is created and
+// the returned issue is nil. Options with a repository search and create the
+// issue there instead, with its labels, assignees and milestone, the created
+// issue is returned with a zero ClosedAt.
+func ReopenOrCreateDraft(manager ProjectManagerInterface, owner, repo, testName, title, body, board string,
+	options *IssueOptions, window time.Duration) (*Issue, error) {
+	if options != nil && options.Repo != "" {
+		owner, repo = options.Owner, options.Repo
+	}
+	if window > 0 {
+		closed, err := manager.FindClosedIssue(owner, repo, testName, time.Now().Add(-window))
+		if err != nil {
+			return nil, err
+		}
+		if closed != nil {
+			comment := fmt.Sprintf("The test is failing again, reopening.\n\n%s", body)
+			return closed, manager.ReopenIssue(closed, comment)
+		}
+	}
+	if options != nil && options.Repo != "" {
+		return manager.CreateIssue(title, body, board, options)
+	}
+	return nil, manager.CreateDraftIssue(title, body, board)
+}
@@ -0,0 +1,139 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	g4 "github.com/shurcooL/githubv4"
+)
+
+// maxIssueLabels bounds the labels read for each board issue.
+const maxIssueLabels = 20
+
+// issueTitleRegex reads the test of the issue titles of the templates, e.g.
+// [Failing Test] [sig-node] Pods should run.
+var issueTitleRegex = regexp.MustCompile(`^\[(?:Failing|Flaking) Test\] (.+)$`)
+
+// BoardIssue is an open or draft issue of the project board with the content
+// linted by the board lint command.
+type BoardIssue struct {
+	Issue
+
+	Body   string
+	Labels []string
+
+	// LastActivity is the last comment of an issue, its creation without
+	// comment, or the last edit of a draft.
+	LastActivity time.Time
+}
+
+// ListBoardIssues returns the draft and open issues of the project board,
+// the maxBoardItems most recently added ones, with their Status field and
+// the test and board of the issues filed with the issue templates.
+func (g *ProjectManager) ListBoardIssues() ([]BoardIssue, error) {
+	if g.githubClient == nil {
+		return nil, errors.New("github GraphQL client is nil")
+	}
+
+	var board struct {
+		Node struct {
+			ProjectV2 struct {
+				Items struct {
+					Nodes []struct {
+						ID      g4.ID
+						Content struct {
+							Typename string `graphql:"__typename"`
+							Draft    struct {
+								Title     string
+								Body      string
+								UpdatedAt g4.DateTime
+							} `graphql:"... on DraftIssue"`
+							Issue struct {
+								ID        g4.ID
+								Number    int
+								Title     string
+								URL       string
+								State     string
+								Body      string
+								CreatedAt g4.DateTime
+								Labels    struct {
+									Nodes []struct {
+										Name string
+									}
+								} `graphql:"labels(first: $labels)"`
+								Comments struct {
+									Nodes []struct {
+										CreatedAt g4.DateTime
+									}
+								} `graphql:"comments(last: 1)"`
+							} `graphql:"... on Issue"`
+						}
+						FieldValueByName struct {
+							SingleSelect struct {
+								Name string
+							} `graphql:"... on ProjectV2ItemFieldSingleSelectValue"`
+						} `graphql:"fieldValueByName(name: \"Status\")"`
+					}
+				} `graphql:"items(last: $items)"`
+			} `graphql:"... on ProjectV2"`
+		} `graphql:"node(id: $projectID)"`
+	}
+	if err := g.query("listBoardIssues", &board, map[string]interface{}{
+		"projectID": g4.ID(g.projectID),
+		"items":     g4.Int(maxBoardItems),
+		"labels":    g4.Int(maxIssueLabels),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list project board items: %w", err)
+	}
+
+	var issues []BoardIssue
+	for _, node := range board.Node.ProjectV2.Items.Nodes {
+		content := node.Content
+		status := node.FieldValueByName.SingleSelect.Name
+		switch {
+		case content.Typename == "DraftIssue":
+			issues = append(issues, BoardIssue{
+				Issue:        Issue{ID: fmt.Sprintf("%v", node.ID), Title: content.Draft.Title, Draft: true, Status: status},
+				Body:         content.Draft.Body,
+				LastActivity: content.Draft.UpdatedAt.Time,
+			})
+		case content.Typename == "Issue" && content.Issue.State == "OPEN":
+			boardIssue := BoardIssue{
+				Issue: Issue{ID: fmt.Sprintf("%v", content.Issue.ID), Number: content.Issue.Number, Title: content.Issue.Title,
+					URL: content.Issue.URL, Status: status},
+				Body:         content.Issue.Body,
+				LastActivity: content.Issue.CreatedAt.Time,
+			}
+			for _, label := range content.Issue.Labels.Nodes {
+				boardIssue.Labels = append(boardIssue.Labels, label.Name)
+			}
+			if comments := content.Issue.Comments.Nodes; len(comments) > 0 {
+				boardIssue.LastActivity = comments[0].CreatedAt.Time
+			}
+			issues = append(issues, boardIssue)
+		}
+	}
+	for i := range issues {
+		issues[i].TestName, issues[i].Board = issueMetadata(issues[i].Body)
+	}
+	return issues, nil
+}
+
+// IssuesByTest indexes the board issues by the test of their body, filed with
+// the issue templates, or else of their title. The first issue of a test
+// wins.
+func IssuesByTest(issues []BoardIssue) map[string]Issue {
+	byTest := map[string]Issue{}
+	for _, boardIssue := range issues {
+		testName := boardIssue.TestName
+		if match := issueTitleRegex.FindStringSubmatch(boardIssue.Title); testName == "" && match != nil {
+			testName = match[1]
+		}
+		if _, found := byTest[testName]; testName != "" && !found {
+			byTest[testName] = boardIssue.Issue
+		}
+	}
+	return byTest
+}
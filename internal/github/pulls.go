@@ -0,0 +1,90 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	g4 "github.com/shurcooL/githubv4"
+)
+
+// maxPullRequestPages limits the search pages fetched for a merge window.
+const maxPullRequestPages = 4
+
+// PullRequest is a merged pull request with the paths it changed.
+type PullRequest struct {
+	Number   int
+	Title    string
+	URL      string
+	MergedAt time.Time
+	Labels   []string
+	Files    []string
+}
+
+// ListMergedPullRequests returns the pull requests merged into owner/repo
+// between from and to.
+func (g *ProjectManager) ListMergedPullRequests(owner, repo string, from, to time.Time) ([]PullRequest, error) {
+	if g.githubClient == nil {
+		return nil, errors.New("github GraphQL client is nil")
+	}
+
+	var query struct {
+		Search struct {
+			PageInfo struct {
+				EndCursor   g4.String
+				HasNextPage bool
+			}
+			Nodes []struct {
+				PullRequest struct {
+					Number   int
+					Title    string
+					URL      string
+					MergedAt g4.DateTime
+					Labels   struct {
+						Nodes []struct {
+							Name string
+						}
+					} `graphql:"labels(first: 20)"`
+					Files struct {
+						Nodes []struct {
+							Path string
+						}
+					} `graphql:"files(first: 100)"`
+				} `graphql:"... on PullRequest"`
+			}
+		} `graphql:"search(query: $query, type: ISSUE, first: 50, after: $cursor)"`
+	}
+
+	variables := map[string]interface{}{
+		"query": g4.String(fmt.Sprintf("repo:%s/%s is:pr is:merged merged:%s..%s", owner, repo,
+			from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339))),
+		"cursor": (*g4.String)(nil),
+	}
+
+	var pulls []PullRequest
+	for page := 0; page < maxPullRequestPages; page++ {
+		if err := g.query("searchPullRequests", &query, variables); err != nil {
+			return nil, fmt.Errorf("failed to search merged pull requests: %w", err)
+		}
+		for _, node := range query.Search.Nodes {
+			pull := PullRequest{
+				Number:   node.PullRequest.Number,
+				Title:    node.PullRequest.Title,
+				URL:      node.PullRequest.URL,
+				MergedAt: node.PullRequest.MergedAt.Time,
+			}
+			for _, label := range node.PullRequest.Labels.Nodes {
+				pull.Labels = append(pull.Labels, label.Name)
+			}
+			for _, file := range node.PullRequest.Files.Nodes {
+				pull.Files = append(pull.Files, file.Path)
+			}
+			pulls = append(pulls, pull)
+		}
+		if !query.Search.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = g4.NewString(query.Search.PageInfo.EndCursor)
+	}
+	return pulls, nil
+}
@@ -0,0 +1,35 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	g4 "github.com/shurcooL/githubv4"
+)
+
+// RateLimit is the GraphQL rate limit of the token, in points per hour.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	ResetAt   time.Time
+}
+
+// RateLimit returns the rate limit left to the token, the query itself costs
+// no point.
+func (g *ProjectManager) RateLimit() (*RateLimit, error) {
+	if g.githubClient == nil {
+		return nil, errors.New("github GraphQL client is nil")
+	}
+	var query struct {
+		RateLimit struct {
+			Limit     int
+			Remaining int
+			ResetAt   g4.DateTime
+		}
+	}
+	if err := g.query("rateLimit", &query, nil); err != nil {
+		return nil, fmt.Errorf("failed to query the rate limit: %w", err)
+	}
+	return &RateLimit{Limit: query.RateLimit.Limit, Remaining: query.RateLimit.Remaining, ResetAt: query.RateLimit.ResetAt.Time}, nil
+}
@@ -0,0 +1,60 @@
+package handbook
+
+import (
+	"embed"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+// sectionsFS holds the CI Signal handbook excerpts, one markdown file per
+// section named <order>-<name>.md and starting with its # title.
+//
+//go:embed sections/*.md
+var sectionsFS embed.FS
+
+// Section is an excerpt of the CI Signal handbook.
+type Section struct {
+	// Name identifies the section, e.g. issues.
+	Name  string
+	Title string
+	Text  string
+}
+
+// Sections returns the handbook sections in the handbook order.
+func Sections() []Section {
+	entries, err := fs.ReadDir(sectionsFS, "sections")
+	if err != nil {
+		panic(err) // the directory is embedded
+	}
+	sections := make([]Section, 0, len(entries))
+	for _, entry := range entries {
+		content, err := fs.ReadFile(sectionsFS, path.Join("sections", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+		_, name, _ := strings.Cut(strings.TrimSuffix(entry.Name(), ".md"), "-")
+		heading, text, _ := strings.Cut(string(content), "\n")
+		sections = append(sections, Section{
+			Name:  name,
+			Title: strings.TrimPrefix(heading, "# "),
+			Text:  strings.TrimSpace(text),
+		})
+	}
+	return sections
+}
+
+// Excerpts renders the named sections in the handbook order as markdown, to
+// ground a language model prompt in the team conventions. Unknown names are
+// ignored, no name renders every section.
+func Excerpts(names ...string) string {
+	var excerpts []string
+	for _, section := range Sections() {
+		if len(names) > 0 && !slices.Contains(names, section.Name) {
+			continue
+		}
+		excerpts = append(excerpts, "## "+section.Title+"\n\n"+section.Text)
+	}
+	return strings.Join(excerpts, "\n\n")
+}
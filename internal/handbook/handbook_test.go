@@ -0,0 +1,26 @@
+package handbook
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSections(t *testing.T) {
+	var names []string
+	for _, section := range Sections() {
+		names = append(names, section.Name)
+		assert.NotEmpty(t, section.Title, section.Name)
+		assert.False(t, strings.HasPrefix(section.Text, "#"), section.Name)
+	}
+	assert.Equal(t, []string{"triage", "issues", "priority", "communication"}, names)
+}
+
+func TestExcerpts(t *testing.T) {
+	excerpts := Excerpts("priority", "issues", "unknown")
+	assert.True(t, strings.HasPrefix(excerpts, "## Reporting an issue\n\n- Title the issue `[Failing Test] <test name>`"))
+	assert.Contains(t, excerpts, "\n\n## Priority and milestones\n\n")
+	assert.NotContains(t, excerpts, "Triaging")
+	assert.Contains(t, Excerpts(), "## Communicating")
+}
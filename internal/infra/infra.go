@@ -0,0 +1,136 @@
+// Package infra correlates the failures of a test with the infrastructure of
+// its runs, e.g. a GCE zone out of quota or a node image being rolled out.
+package infra
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"sigs.k8s.io/signalhound/internal/prow"
+)
+
+const (
+	// minFailures is the number of red runs on a value before it is flagged.
+	minFailures = 2
+
+	// minShare is the share of the red runs a value must cover.
+	minShare = 0.75
+
+	// minGap is the share of the green runs on the value must be lower than
+	// the share of the red runs by at least minGap, so the single zone or
+	// image of a job is not flagged.
+	minGap = 0.5
+
+	// maxFetches limits the run metadata fetched concurrently.
+	maxFetches = 4
+)
+
+// Attributes of the infrastructure compared across the runs.
+const (
+	Zone         = "zone"
+	Image        = "image"
+	InstanceType = "instance type"
+)
+
+var attributes = []struct {
+	name  string
+	value func(*prow.Infrastructure) string
+}{
+	{Zone, func(i *prow.Infrastructure) string { return i.Zone }},
+	{Image, func(i *prow.Infrastructure) string { return i.Image }},
+	{InstanceType, func(i *prow.Infrastructure) string { return i.InstanceType }},
+}
+
+// Cluster is an infrastructure value the red runs concentrate on.
+type Cluster struct {
+	Attribute string
+	Value     string
+
+	// Failures of FailedRuns red runs and Passes of PassedRuns green runs ran
+	// on the value, the runs without the attribute are not counted.
+	Failures, FailedRuns int
+	Passes, PassedRuns   int
+}
+
+func (c Cluster) String() string {
+	return fmt.Sprintf("%s %s: %d of %d red runs, %d of %d green runs", c.Attribute, c.Value, c.Failures, c.FailedRuns, c.Passes, c.PassedRuns)
+}
+
+// Fetch returns the infrastructure of the runs, in the order of the URLs. The
+// runs whose metadata can't be fetched are skipped and their first error is
+// returned with the others.
+func Fetch(urls []string) ([]*prow.Infrastructure, error) {
+	var (
+		wg        sync.WaitGroup
+		semaphore = make(chan struct{}, maxFetches)
+		infras    = make([]*prow.Infrastructure, len(urls))
+		errs      = make([]error, len(urls))
+	)
+	for i, url := range urls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			infras[i], errs[i] = prow.NewProw(url).GetInfrastructure()
+		}()
+	}
+	wg.Wait()
+
+	var (
+		fetched  []*prow.Infrastructure
+		firstErr error
+	)
+	for i, infra := range infras {
+		if errs[i] != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("error fetching the metadata of %s: %v", urls[i], errs[i])
+			}
+			continue
+		}
+		fetched = append(fetched, infra)
+	}
+	return fetched, firstErr
+}
+
+// Correlate returns the values the failed runs cluster on compared to the
+// passed runs, the most concentrated first. Without a passed run carrying
+// an attribute the attribute isn't compared, a job always running in the
+// same zone would be flagged otherwise.
+func Correlate(failed, passed []*prow.Infrastructure) []Cluster {
+	var clusters []Cluster
+	for _, attribute := range attributes {
+		failures, failedRuns := count(failed, attribute.value)
+		passes, passedRuns := count(passed, attribute.value)
+		if passedRuns == 0 {
+			continue
+		}
+		for value, n := range failures {
+			failShare := float64(n) / float64(failedRuns)
+			passShare := float64(passes[value]) / float64(passedRuns)
+			if n >= minFailures && failShare >= minShare && failShare-passShare >= minGap {
+				clusters = append(clusters, Cluster{
+					Attribute: attribute.name, Value: value,
+					Failures: n, FailedRuns: failedRuns, Passes: passes[value], PassedRuns: passedRuns,
+				})
+			}
+		}
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return float64(clusters[i].Failures)/float64(clusters[i].FailedRuns) > float64(clusters[j].Failures)/float64(clusters[j].FailedRuns)
+	})
+	return clusters
+}
+
+// count returns the runs per value of the attribute and the runs carrying it.
+func count(infras []*prow.Infrastructure, value func(*prow.Infrastructure) string) (map[string]int, int) {
+	counts, total := map[string]int{}, 0
+	for _, infra := range infras {
+		if v := value(infra); v != "" {
+			counts[v]++
+			total++
+		}
+	}
+	return counts, total
+}
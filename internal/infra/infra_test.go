@@ -0,0 +1,59 @@
+package infra
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/signalhound/internal/prow"
+)
+
+func TestCorrelate(t *testing.T) {
+	run := func(zone, image string) *prow.Infrastructure {
+		return &prow.Infrastructure{Zone: zone, Image: image, InstanceType: "e2-standard-4"}
+	}
+	failed := []*prow.Infrastructure{run("us-central1-b", "cos-117"), run("us-central1-b", "cos-117"), run("us-central1-b", "cos-117"), run("us-east1-c", "")}
+	passed := []*prow.Infrastructure{run("us-central1-b", "cos-113"), run("us-east1-c", "cos-113"), run("us-west1-a", "cos-113"), run("us-east1-c", "cos-113")}
+
+	// the single instance type of the job is not flagged, the runs without
+	// image are not counted
+	clusters := Correlate(failed, passed)
+	assert.Equal(t, []Cluster{
+		{Attribute: Image, Value: "cos-117", Failures: 3, FailedRuns: 3, Passes: 0, PassedRuns: 4},
+		{Attribute: Zone, Value: "us-central1-b", Failures: 3, FailedRuns: 4, Passes: 1, PassedRuns: 4},
+	}, clusters)
+	assert.Equal(t, "zone us-central1-b: 3 of 4 red runs, 1 of 4 green runs", clusters[1].String())
+
+	// without green runs there is no baseline
+	assert.Empty(t, Correlate(failed, nil))
+}
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/2/finished.json"):
+			w.WriteHeader(http.StatusForbidden)
+		case strings.HasSuffix(r.URL.Path, "/finished.json"):
+			fmt.Fprintf(w, `{"metadata": {"zone": %q}}`, strings.Split(r.URL.Path, "/")[4]) // nolint
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	previous := prow.GCSURL
+	prow.GCSURL = server.URL
+	t.Cleanup(func() { prow.GCSURL = previous })
+
+	urls := []string{
+		"https://prow.k8s.io/view/gs/bucket/logs/job/1",
+		"https://prow.k8s.io/view/gs/bucket/logs/job/2",
+		"https://prow.k8s.io/view/gs/bucket/logs/job/3",
+	}
+	infras, err := Fetch(urls)
+	assert.ErrorContains(t, err, "error fetching the metadata of "+urls[1])
+	assert.Equal(t, []*prow.Infrastructure{{URL: urls[0], Zone: "1"}, {URL: urls[2], Zone: "3"}}, infras)
+}
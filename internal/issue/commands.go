@@ -0,0 +1,104 @@
+package issue
+
+import (
+	"regexp"
+	"strings"
+)
+
+// commandRegex matches the /sig, /priority and /milestone command lines of
+// the issue templates, with or without argument.
+var commandRegex = regexp.MustCompile(`(?m)^/(sig|priority|milestone)(?:[ \t]+(\S*))?[ \t]*$`)
+
+// Commands are the Prow commands of the issue body labeling the issue once
+// created, picked in the issue form of the UI.
+type Commands struct {
+	Sig       string
+	Priority  string
+	Milestone string
+}
+
+// ParseCommands returns the first /sig, /priority and /milestone arguments
+// of the body.
+func ParseCommands(body string) Commands {
+	var commands Commands
+	for _, match := range commandRegex.FindAllStringSubmatch(body, -1) {
+		if field := commands.field(match[1]); *field == "" {
+			*field = match[2]
+		}
+	}
+	return commands
+}
+
+// field returns the field of the command.
+func (c *Commands) field(command string) *string {
+	switch command {
+	case "sig":
+		return &c.Sig
+	case "priority":
+		return &c.Priority
+	}
+	return &c.Milestone
+}
+
+// commandOrder is the order of the commands in the issue templates.
+var commandOrder = []string{"sig", "priority", "milestone"}
+
+// Apply returns the body with its commands set to c: the command lines are
+// replaced, the ones of an empty argument removed, and the missing ones
+// inserted in the order of the templates among the command lines, or at the
+// end of the body.
+func (c Commands) Apply(body string) string {
+	var kept []string
+	at := map[string]int{} // line of each kept command
+	for _, line := range strings.Split(body, "\n") {
+		match := commandRegex.FindStringSubmatch(line)
+		if match == nil {
+			kept = append(kept, line)
+			continue
+		}
+		// the duplicates of a command are dropped with it.
+		if _, found := at[match[1]]; !found {
+			at[match[1]] = -1
+			if value := *c.field(match[1]); value != "" {
+				at[match[1]] = len(kept)
+				kept = append(kept, "/"+match[1]+" "+value)
+			}
+		}
+	}
+	for i, command := range commandOrder {
+		value := *c.field(command)
+		if _, found := at[command]; found || value == "" {
+			continue
+		}
+		index := insertionLine(kept, at, commandOrder[:i], commandOrder[i+1:])
+		kept = append(kept[:index], append([]string{"/" + command + " " + value}, kept[index:]...)...)
+		for name, line := range at {
+			if line >= index {
+				at[name] = line + 1
+			}
+		}
+		at[command] = index
+	}
+	return strings.Join(kept, "\n")
+}
+
+// insertionLine returns the line a missing command is inserted at: before
+// the first kept command of after, or after the last kept command of before,
+// or else after the last non-empty line.
+func insertionLine(lines []string, at map[string]int, before, after []string) int {
+	for _, command := range after {
+		if line, found := at[command]; found && line >= 0 {
+			return line
+		}
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		if line, found := at[before[i]]; found && line >= 0 {
+			return line + 1
+		}
+	}
+	index := len(lines)
+	for index > 0 && lines[index-1] == "" {
+		index--
+	}
+	return index
+}
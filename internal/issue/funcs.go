@@ -0,0 +1,87 @@
+package issue
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"sigs.k8s.io/signalhound/internal/links"
+)
+
+// funcMap holds the functions of the issue templates, the embedded ones and
+// the ones of the --templates directory.
+var funcMap = template.FuncMap{
+	"fence":        fence,
+	"codefence":    codefence,
+	"truncate":     truncate,
+	"relativeTime": relativeTime,
+	"sigLabel":     sigLabel,
+	"prowLink":     links.ProwLink,
+}
+
+// now is the reference time of relativeTime, replaced in tests.
+var now = time.Now
+
+// codefence wraps the content in a markdown code block of the language, e.g.
+// {{ codefence "go" .ErrMessage }}, the fence is longer than any backtick run
+// of the content.
+func codefence(language, content string) string {
+	fenced := fence(content)
+	return fenced + language + "\n" + strings.TrimRight(content, "\n") + "\n" + fenced
+}
+
+// truncate cuts the text to its first n characters, the last one replaced by
+// an ellipsis, e.g. {{ .ErrMessage | truncate 200 }}.
+func truncate(n int, text string) string {
+	if utf8.RuneCountInString(text) <= n {
+		return text
+	}
+	if n <= 0 {
+		return ""
+	}
+	return string([]rune(text)[:n-1]) + "…"
+}
+
+// relativeTime returns the time elapsed since a timestamp in milliseconds, a
+// time or a date rendered by TimeClean, e.g. "2d 3h ago". An empty date
+// returns an empty string.
+func relativeTime(value any) (string, error) {
+	var at time.Time
+	switch value := value.(type) {
+	case int64:
+		at = time.UnixMilli(value)
+	case int:
+		at = time.UnixMilli(int64(value))
+	case time.Time:
+		at = value
+	case string:
+		if value == "" {
+			return "", nil
+		}
+		parsed, err := time.Parse(time.RFC1123, value)
+		if err != nil {
+			return "", fmt.Errorf("invalid time %q, expected a date like %s", value, time.RFC1123)
+		}
+		at = parsed
+	default:
+		return "", fmt.Errorf("invalid time of type %T", value)
+	}
+	elapsed := now().Sub(at)
+	if elapsed < time.Minute {
+		return "just now", nil
+	}
+	return FormatDuration(elapsed) + " ago", nil
+}
+
+// sigLabel returns the label of the SIG, e.g. sig/node for node, sig-node or
+// sig/node, or an empty string without SIG.
+func sigLabel(sig string) string {
+	sig = strings.ToLower(strings.TrimSpace(sig))
+	sig = strings.TrimPrefix(strings.TrimPrefix(sig, "sig/"), "sig-")
+	if sig == "" {
+		return ""
+	}
+	return "sig/" + sig
+}
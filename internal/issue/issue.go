@@ -0,0 +1,453 @@
+package issue
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/culprit"
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/release"
+	"sigs.k8s.io/yaml"
+)
+
+//go:embed template/*
+var tmplFolder embed.FS
+
+// templates holds the issue templates, the embedded ones unless a template
+// directory is set with SetTemplateDir.
+var templates fs.FS = tmplFolder
+
+const (
+	FailureTemplate  = "template/failure.tmpl"
+	FlakeTemplate    = "template/flake.tmpl"
+	LogsTemplate     = "template/logs.tmpl"
+	CulpritsTemplate = "template/culprits.tmpl"
+	NotesTemplate    = "template/notes.tmpl"
+	BranchesTemplate = "template/branches.tmpl"
+	VariantsTemplate = "template/variants.tmpl"
+	DetailsTemplate  = "template/details.tmpl"
+)
+
+// blockTemplates are the blocks shared by the issue templates, each one can
+// be rendered alone to refresh an already rendered issue body.
+var blockTemplates = []string{LogsTemplate, CulpritsTemplate, NotesTemplate, BranchesTemplate, VariantsTemplate, DetailsTemplate}
+
+const (
+	// maxLogBytes limits the tail of each log stream included in the issue body.
+	maxLogBytes = 8 * 1024
+
+	// maxLogsBytes limits all the log streams included in the issue body, kept
+	// well below the 65536 characters accepted by GitHub for the whole body.
+	maxLogsBytes = 40 * 1024
+)
+
+// Template holds the fields rendered in the issue templates.
+type Template struct {
+	BoardName    string
+	TabName      string
+	TestName     string
+	FirstFailure string
+	LastFailure  string
+	FailingSince string
+	TestGridURL  string
+	TriageURL    string
+	ProwURL      string
+	ErrMessage   string
+	Sig          string
+	Priority     string
+	FlakeScore   string
+	Logs         []Log
+	Culprits     []Culprit
+	Notes        []string
+
+	// Milestone is the milestone of a release branch board, e.g. v1.32,
+	// empty on master.
+	Milestone string
+
+	// LastPassCommit and FirstFailCommit bound the suspected commits of a
+	// failure, listed by CompareURL.
+	LastPassCommit  string
+	FirstFailCommit string
+	CompareURL      string
+
+	// Details is the JUnit result of the test in its failed run.
+	Details *TestDetails
+
+	// CrossBranch lists the boards of other branches failing with the same
+	// fingerprint, CrossBranchHint explains the cherry-pick needs.
+	CrossBranch     []CrossBranch
+	CrossBranchHint string
+
+	// Variants lists the other platform variants of the suite of the test,
+	// e.g. arm64, VariantsHint names the variants the test fails on.
+	Variants     []Variant
+	VariantsHint string
+}
+
+// CrossBranch is a board of another branch failing with the same fingerprint.
+type CrossBranch struct {
+	Branch    string
+	BoardHash string
+	URL       string
+}
+
+// Variant is a tab running the suite of the test on another platform, e.g.
+// arm64 or windows, Failing when the test fails on it too.
+type Variant struct {
+	Variant   string
+	BoardHash string
+	URL       string
+	Failing   bool
+}
+
+// Culprit is a pull request merged between the last green and the first red
+// run of a failing test.
+type Culprit struct {
+	Number int
+	Title  string
+	URL    string
+}
+
+// TestDetails is the result of the test read from the JUnit file of a run.
+type TestDetails struct {
+	File     string
+	URL      string
+	Duration time.Duration
+	Message  string
+	Failure  string
+	Output   string
+}
+
+// Log is a log stream included in the issue body.
+type Log struct {
+	Name    string
+	URL     string
+	Content string
+}
+
+// NewTemplate creates the filled-out issue template object of a test.
+func NewTemplate(tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestResult) *Template {
+	boardName, tabName, _ := strings.Cut(tab.BoardHash, "#")
+	issueTemplate := &Template{
+		BoardName:    boardName,
+		TabName:      tabName,
+		TestName:     currentTest.TestName,
+		TestGridURL:  tab.TabURL,
+		TriageURL:    currentTest.TriageURL,
+		ProwURL:      currentTest.ProwJobURL,
+		ErrMessage:   currentTest.ErrorMessage,
+		FirstFailure: TimeClean(currentTest.FirstTimestamp),
+		LastFailure:  TimeClean(currentTest.LatestTimestamp),
+		FailingSince: currentTest.FailingSince,
+		Sig:          testSIG(currentTest),
+		FlakeScore:   FlakeScore(currentTest),
+		Milestone:    release.Milestone(tab.BoardHash),
+	}
+	if currentTest.LastPassCommit != "" && currentTest.FirstFailCommit != "" {
+		issueTemplate.LastPassCommit, issueTemplate.FirstFailCommit = currentTest.LastPassCommit, currentTest.FirstFailCommit
+		issueTemplate.CompareURL = culprit.CompareURL(currentTest.LastPassCommit, currentTest.FirstFailCommit)
+	}
+	return issueTemplate
+}
+
+// FlakeScore returns the flake score of the test with the runs it covers,
+// e.g. "35% of 140 runs", or an empty string for an unscored test.
+func FlakeScore(currentTest *v1alpha1.TestResult) string {
+	if currentTest.ScoreRuns == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d%% of %d runs", currentTest.FlakeScore, currentTest.ScoreRuns)
+}
+
+// TestState returns the state the issue of the test is filed for, FAILING
+// for a consistently failing test and FLAKY for an intermittent flake, see
+// TestResult.Classification, the tab state for an unclassified test.
+func TestState(tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestResult) string {
+	switch currentTest.Classification {
+	case v1alpha1.CONSISTENT_FAILURE:
+		return v1alpha1.FAILING_STATUS
+	case v1alpha1.INTERMITTENT_FLAKE:
+		return v1alpha1.FLAKY_STATUS
+	}
+	return tab.TabState
+}
+
+// PickTemplate returns the template file and the issue title prefix by
+// the failure status, see TestState.
+func PickTemplate(state string) (templateFile, prefixTitle string) {
+	if state == v1alpha1.FAILING_STATUS {
+		return FailureTemplate, "Failing Test"
+	}
+	return FlakeTemplate, "Flaking Test"
+}
+
+// Title returns the issue title of a test.
+func Title(prefixTitle, testName string) string {
+	return fmt.Sprintf("[%v] %v", prefixTitle, testName)
+}
+
+// RenderTemplate executes the template file with the issue fields.
+func RenderTemplate(issue *Template, templateFile string) (output bytes.Buffer, err error) {
+	var tmpl *template.Template
+	tmpl, err = template.New(path.Base(templateFile)).Funcs(funcMap).ParseFS(templates, append([]string{templateFile}, blockTemplates...)...)
+	if err != nil {
+		return output, err
+	}
+	return execute(tmpl, issue)
+}
+
+// RenderSource executes a template source, e.g. a customized copy of an issue
+// template, with the issue fields. The source can use and redefine the
+// blocks of the issue templates.
+func RenderSource(issue *Template, source string) (output bytes.Buffer, err error) {
+	var tmpl *template.Template
+	if tmpl, err = template.New("source").Funcs(funcMap).ParseFS(templates, blockTemplates...); err != nil {
+		return output, err
+	}
+	if tmpl, err = tmpl.Parse(source); err != nil {
+		return output, err
+	}
+	return execute(tmpl, issue)
+}
+
+// TemplateFiles returns the issue templates, their blocks, the job template
+// and the resolution template.
+func TemplateFiles() []string {
+	return append([]string{FailureTemplate, FlakeTemplate, JobTemplate, ResolutionTemplate}, blockTemplates...)
+}
+
+// TemplateSource returns the source of the template file, the one of the
+// template directory when set, see SetTemplateDir.
+func TemplateSource(templateFile string) (string, error) {
+	data, err := fs.ReadFile(templates, templateFile)
+	if err != nil {
+		return "", fmt.Errorf("error reading template %s: %v", path.Base(templateFile), err)
+	}
+	return string(data), nil
+}
+
+// execute renders the issue with the logs and details capped.
+func execute(tmpl *template.Template, issue *Template) (output bytes.Buffer, err error) {
+	rendered := *issue
+	rendered.Logs = CapLogs(issue.Logs)
+	rendered.Details = capDetails(issue.Details)
+	if err = tmpl.Execute(&output, &rendered); err != nil {
+		return output, err
+	}
+	return
+}
+
+// RenderLogs executes only the logs block of the issue templates, used to
+// refresh the logs of an already rendered issue body.
+func RenderLogs(logs []Log) (string, error) {
+	return renderBlock("logs", &Template{Logs: CapLogs(logs)})
+}
+
+// RenderCulprits executes only the possible culprits block of the failure
+// template, used to refresh the culprits of an already rendered issue body.
+func RenderCulprits(culprits []Culprit) (string, error) {
+	return renderBlock("culprits", &Template{Culprits: culprits})
+}
+
+// RenderNotes executes only the notes block of the issue templates, used to
+// refresh the human annotations of an already rendered issue body.
+func RenderNotes(notes []string) (string, error) {
+	return renderBlock("notes", &Template{Notes: notes})
+}
+
+// RenderDetails executes only the test details block of the issue
+// templates, used to refresh the JUnit result of an already rendered issue
+// body.
+func RenderDetails(details *TestDetails) (string, error) {
+	return renderBlock("details", &Template{Details: capDetails(details)})
+}
+
+func renderBlock(name string, issue *Template) (string, error) {
+	tmpl, err := template.New(name).Funcs(funcMap).ParseFS(templates, blockTemplates...)
+	if err != nil {
+		return "", err
+	}
+	var output bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&output, name, issue); err != nil {
+		return "", err
+	}
+	return output.String(), nil
+}
+
+// SetTemplateDir reads the issue templates from dir, e.g. dir/failure.tmpl,
+// falling back to the embedded template of the files missing in dir. An
+// empty dir restores the embedded templates.
+func SetTemplateDir(dir string) error {
+	if dir == "" {
+		templates = tmplFolder
+		return nil
+	}
+	if info, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("error reading the template directory: %v", err)
+	} else if !info.IsDir() {
+		return fmt.Errorf("error reading the template directory: %s is not a directory", dir)
+	}
+	templates = overlayFS{dir: os.DirFS(dir)}
+	return nil
+}
+
+// overlayFS serves the templates of dir over the embedded ones.
+type overlayFS struct {
+	dir fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	file, err := o.dir.Open(path.Base(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return tmplFolder.Open(name)
+	}
+	return file, err
+}
+
+// FrontMatter is the issue configuration set in the YAML front matter of a
+// template, between two "---" lines at the top of the file. It is rendered
+// like the rest of the template, so it can use the issue fields.
+type FrontMatter struct {
+	// Repo is the owner/name repository the issue is created in, without it
+	// a draft issue is added to the project board and the rest is ignored.
+	Repo      string   `json:"repo,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+	Milestone string   `json:"milestone,omitempty"`
+}
+
+// ParseFrontMatter splits a rendered issue into its front matter and body,
+// the front matter is nil when the issue has none.
+func ParseFrontMatter(content string) (*FrontMatter, string, error) {
+	rest, found := strings.CutPrefix(content, "---\n")
+	if !found {
+		return nil, content, nil
+	}
+	header, body, found := strings.Cut("\n"+rest, "\n---\n")
+	if !found {
+		return nil, content, errors.New("error parsing the issue front matter: missing closing ---")
+	}
+	var frontMatter FrontMatter
+	if err := yaml.UnmarshalStrict([]byte(header), &frontMatter); err != nil {
+		return nil, content, fmt.Errorf("error parsing the issue front matter: %v", err)
+	}
+	if frontMatter.Repo != "" {
+		if owner, repo, ok := strings.Cut(frontMatter.Repo, "/"); !ok || owner == "" || repo == "" {
+			return nil, content, fmt.Errorf("error parsing the issue front matter: repo %q is not owner/name", frontMatter.Repo)
+		}
+	}
+	return &frontMatter, strings.TrimLeft(body, "\r\n"), nil
+}
+
+// Options returns the GitHub issue options of the front matter, nil when it
+// doesn't set a repository. Empty labels and assignees, e.g. rendered from
+// an empty field, are dropped.
+func (f *FrontMatter) Options() *github.IssueOptions {
+	if f == nil || f.Repo == "" {
+		return nil
+	}
+	owner, repo, _ := strings.Cut(f.Repo, "/")
+	options := &github.IssueOptions{Owner: owner, Repo: repo, Milestone: f.Milestone}
+	for _, label := range f.Labels {
+		if label = strings.TrimSpace(label); label != "" && !strings.HasSuffix(label, "/") {
+			options.Labels = append(options.Labels, label)
+		}
+	}
+	for _, assignee := range f.Assignees {
+		if assignee = strings.TrimPrefix(strings.TrimSpace(assignee), "@"); assignee != "" {
+			options.Assignees = append(options.Assignees, assignee)
+		}
+	}
+	return options
+}
+
+// CapLogs keeps the tail of each log stream within maxLogBytes and drops the
+// streams not fitting in the maxLogsBytes budget.
+func CapLogs(logs []Log) []Log {
+	var (
+		capped []Log
+		budget = maxLogsBytes
+	)
+	for _, log := range logs {
+		size := min(maxLogBytes, budget)
+		if size <= 0 {
+			break
+		}
+		log.Content = capContent(log.Content, size)
+		budget -= len(log.Content)
+		capped = append(capped, log)
+	}
+	return capped
+}
+
+// capDetails keeps the tail of the failure and output of the test details
+// within maxLogBytes each.
+func capDetails(details *TestDetails) *TestDetails {
+	if details == nil {
+		return nil
+	}
+	capped := *details
+	capped.Failure = capContent(details.Failure, maxLogBytes)
+	capped.Output = capContent(details.Output, maxLogBytes)
+	return &capped
+}
+
+// capContent keeps the last size bytes of the content, cut on a rune start.
+func capContent(content string, size int) string {
+	if len(content) <= size {
+		return content
+	}
+	cut := len(content) - size
+	for cut < len(content) && !utf8.RuneStart(content[cut]) {
+		cut++
+	}
+	return "[...truncated, see the full log in the link]\n" + content[cut:]
+}
+
+// fence returns a markdown code fence longer than any backtick run of the
+// content, so the content can't close the code block.
+func fence(content string) string {
+	longest, run := 0, 0
+	for _, r := range content {
+		if r != '`' {
+			run = 0
+			continue
+		}
+		run++
+		longest = max(longest, run)
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// SlackMessage returns the Slack message reporting a test of the tab.
+func SlackMessage(tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestResult) string {
+	item := fmt.Sprintf("%s %s on [%s](%s): `%s` [Prow](%s), [Triage](%s), last failure on %s\n",
+		tab.StateIcon, cases.Title(language.English).String(tab.TabState), tab.BoardHash, tab.TabURL,
+		currentTest.TestName, currentTest.ProwJobURL, currentTest.TriageURL, TimeClean(currentTest.LatestTimestamp),
+	)
+	item = strings.TrimRight(item, "\r\n")
+	if score := FlakeScore(currentTest); score != "" {
+		item += ", flake score " + score
+	}
+	if milestone := release.Milestone(tab.BoardHash); milestone != "" {
+		item += ", milestone " + milestone
+	}
+	return item
+}
+
+// TimeClean returns the string representation of the timestamp.
+func TimeClean(ts int64) string {
+	return time.Unix(ts/1000, 0).UTC().Format(time.RFC1123)
+}
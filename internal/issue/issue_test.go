@@ -0,0 +1,478 @@
+package issue
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+)
+
+func TestCapLogs(t *testing.T) {
+	logs := CapLogs([]Log{
+		{Name: "short", Content: "short log"},
+		{Name: "long", Content: strings.Repeat("a", maxLogBytes) + "tail"},
+	})
+	assert.Len(t, logs, 2)
+	assert.Equal(t, "short log", logs[0].Content)
+	assert.True(t, strings.HasSuffix(logs[1].Content, "tail"))
+	assert.Contains(t, logs[1].Content, "truncated")
+
+	var many []Log
+	for range 10 {
+		many = append(many, Log{Content: strings.Repeat("b", maxLogBytes)})
+	}
+	total := 0
+	for _, log := range CapLogs(many) {
+		total += len(log.Content)
+	}
+	assert.LessOrEqual(t, total, maxLogsBytes)
+}
+
+func TestFence(t *testing.T) {
+	assert.Equal(t, "```", fence("plain output"))
+	assert.Equal(t, "````", fence("a ``` code block"))
+	assert.Equal(t, "``````", fence("`````"))
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	assert.Equal(t, "```go\nfmt.Println()\n```", codefence("go", "fmt.Println()\n"))
+	assert.Equal(t, "````\na ``` block\n````", codefence("", "a ``` block"))
+
+	assert.Equal(t, "short", truncate(10, "short"))
+	assert.Equal(t, "timed o…", truncate(8, "timed out waiting"))
+	assert.Equal(t, "é…", truncate(2, "été"))
+	assert.Equal(t, "", truncate(0, "text"))
+
+	assert.Equal(t, "sig/node", sigLabel("node"))
+	assert.Equal(t, "sig/node", sigLabel("sig-node"))
+	assert.Equal(t, "sig/node", sigLabel("sig/Node"))
+	assert.Equal(t, "", sigLabel(""))
+
+	previous := now
+	now = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { now = previous })
+	at := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	for _, value := range []any{at, at.UnixMilli(), TimeClean(at.UnixMilli())} {
+		relative, err := relativeTime(value)
+		assert.NoError(t, err)
+		assert.Equal(t, "2d 3h ago", relative)
+	}
+	relative, err := relativeTime("")
+	assert.NoError(t, err)
+	assert.Empty(t, relative)
+	_, err = relativeTime("yesterday")
+	assert.Error(t, err)
+
+	tmpl := template.Must(template.New("custom").Funcs(funcMap).Parse(
+		`{{ sigLabel .Sig }} {{ .ErrMessage | truncate 9 }} {{ prowLink "history" .ProwURL }}`))
+	var output strings.Builder
+	assert.NoError(t, tmpl.Execute(&output, &Template{
+		Sig: "node", ErrMessage: "timed out waiting", ProwURL: "https://prow.k8s.io/view/gs/kubernetes-ci-logs/logs/ci-kind/1",
+	}))
+	assert.Equal(t, "sig/node timed ou… https://prow.k8s.io/job-history/gs/kubernetes-ci-logs/logs/ci-kind", output.String())
+}
+
+func TestRenderTemplateLogs(t *testing.T) {
+	tab := &v1alpha1.DashboardTab{BoardHash: "sig-release-master-blocking#gce-cos-master-default", TabState: v1alpha1.FAILING_STATUS}
+	test := &v1alpha1.TestResult{TestName: "[sig-node] Pods should run"}
+
+	issue := NewTemplate(tab, test)
+	body, err := RenderTemplate(issue, FailureTemplate)
+	assert.NoError(t, err)
+	assert.Contains(t, body.String(), "know?\n\n_No response_")
+
+	issue.Logs = []Log{{Name: "test-stdout.txt", URL: "https://storage.googleapis.com/stdout", Content: "```\nbroken"}}
+	body, err = RenderTemplate(issue, FailureTemplate)
+	assert.NoError(t, err)
+	assert.Contains(t, body.String(), "````\n```\nbroken\n````")
+	assert.NotContains(t, body.String(), "know?\n\n_No response_")
+
+	logs, err := RenderLogs(issue.Logs)
+	assert.NoError(t, err)
+	assert.Contains(t, body.String(), logs)
+}
+
+func TestRenderTemplateCulprits(t *testing.T) {
+	tab := &v1alpha1.DashboardTab{BoardHash: "sig-release-master-blocking#gce-cos-master-default", TabState: v1alpha1.FAILING_STATUS}
+	issue := NewTemplate(tab, &v1alpha1.TestResult{TestName: "[sig-node] Pods should run"})
+	issue.Culprits = []Culprit{{Number: 12345, Title: "kubelet: rework probes", URL: "https://github.com/kubernetes/kubernetes/pull/12345"}}
+
+	body, err := RenderTemplate(issue, FailureTemplate)
+	assert.NoError(t, err)
+	assert.Contains(t, body.String(), "### Possible culprits\n")
+	assert.Contains(t, body.String(), "* [#12345](https://github.com/kubernetes/kubernetes/pull/12345) kubelet: rework probes\n")
+
+	culprits, err := RenderCulprits(issue.Culprits)
+	assert.NoError(t, err)
+	assert.Contains(t, body.String(), "### Possible culprits\n"+culprits)
+
+	body, err = RenderTemplate(issue, FlakeTemplate)
+	assert.NoError(t, err)
+	assert.NotContains(t, body.String(), "Possible culprits")
+}
+
+func TestRenderTemplateDetails(t *testing.T) {
+	tab := &v1alpha1.DashboardTab{BoardHash: "sig-release-master-blocking#gce-cos-master-default", TabState: v1alpha1.FAILING_STATUS}
+	issue := NewTemplate(tab, &v1alpha1.TestResult{TestName: "[sig-node] Pods should run"})
+
+	body, err := RenderTemplate(issue, FailureTemplate)
+	assert.NoError(t, err)
+	assert.Contains(t, body.String(), "### Test details\n\n_No response_\n")
+
+	issue.Details = &TestDetails{
+		File:     "junit_01.xml",
+		URL:      "https://storage.googleapis.com/kubernetes-ci-logs/logs/ci-kubernetes-e2e/1/artifacts/junit_01.xml",
+		Duration: 90 * time.Second,
+		Message:  "timed out waiting for the pod",
+		Failure:  "pods.go:42: timed out",
+		Output:   "STEP: creating the pod",
+	}
+	body, err = RenderTemplate(issue, FailureTemplate)
+	assert.NoError(t, err)
+	assert.Contains(t, body.String(), "### Test details\n\nFrom [junit_01.xml]("+issue.Details.URL+"), the test ran for 1m30s and failed with: timed out waiting for the pod\n")
+	assert.Contains(t, body.String(), "```\npods.go:42: timed out\n```\n")
+	assert.Contains(t, body.String(), "<details><summary>system-out</summary>\n\n```\nSTEP: creating the pod\n```\n")
+
+	details, err := RenderDetails(issue.Details)
+	assert.NoError(t, err)
+	assert.Contains(t, body.String(), "### Test details\n"+details)
+
+	body, err = RenderTemplate(issue, FlakeTemplate)
+	assert.NoError(t, err)
+	assert.Contains(t, body.String(), "### Test details\n"+details)
+}
+
+func TestRenderTemplateNotes(t *testing.T) {
+	tab := &v1alpha1.DashboardTab{BoardHash: "sig-release-master-informing#kind-master", TabState: v1alpha1.FLAKY_STATUS}
+	issue := NewTemplate(tab, &v1alpha1.TestResult{TestName: "[sig-network] DNS"})
+
+	body, err := RenderTemplate(issue, FlakeTemplate)
+	assert.NoError(t, err)
+	assert.NotContains(t, body.String(), "Notes from the CI Signal team")
+
+	issue.Notes = []string{"waiting on #12345 (alice, 2026-10-16)"}
+	body, err = RenderTemplate(issue, FlakeTemplate)
+	assert.NoError(t, err)
+	assert.Contains(t, body.String(), "know?\n\n_No response_\n\nNotes from the CI Signal team:\n\n* waiting on #12345 (alice, 2026-10-16)\n")
+}
+
+func TestRenderTemplateCrossBranch(t *testing.T) {
+	tab := &v1alpha1.DashboardTab{BoardHash: "sig-release-1.34-blocking#gce-cos-1.34", TabState: v1alpha1.FAILING_STATUS}
+	issue := NewTemplate(tab, &v1alpha1.TestResult{TestName: "[sig-node] Pods should run", ErrorMessage: "timeout"})
+
+	body, err := RenderTemplate(issue, FailureTemplate)
+	assert.NoError(t, err)
+	assert.Contains(t, body.String(), "timeout\n```\n\n### Possible culprits")
+
+	issue.CrossBranch = []CrossBranch{{Branch: "master", BoardHash: "sig-release-master-blocking#gce-cos-master-default", URL: "https://testgrid.k8s.io/master"}}
+	issue.CrossBranchHint = "fix it on master"
+	body, err = RenderTemplate(issue, FailureTemplate)
+	assert.NoError(t, err)
+	assert.Contains(t, body.String(), "timeout\n```\n\nThe same failure fingerprint is seen on other branches, fix it on master:\n\n"+
+		"* [sig-release-master-blocking#gce-cos-master-default](https://testgrid.k8s.io/master) (master)\n\n### Possible culprits")
+
+	issue.CrossBranch = nil
+	issue.Variants = []Variant{
+		{Variant: "ubuntu", BoardHash: "sig-release-1.34-blocking#gce-ubuntu-1.34", URL: "https://testgrid.k8s.io/ubuntu"},
+	}
+	issue.VariantsHint = "fails only on cos, green on ubuntu"
+	body, err = RenderTemplate(issue, FailureTemplate)
+	assert.NoError(t, err)
+	assert.Contains(t, body.String(), "timeout\n```\n\nThe suite also runs on other platform variants, the test fails only on cos, green on ubuntu:\n\n"+
+		"* [sig-release-1.34-blocking#gce-ubuntu-1.34](https://testgrid.k8s.io/ubuntu) (ubuntu, green)\n\n### Possible culprits")
+}
+
+func TestFlakeScore(t *testing.T) {
+	tab := &v1alpha1.DashboardTab{BoardHash: "sig-release-master-informing#kind-master", TabState: v1alpha1.FLAKY_STATUS, StateIcon: ":large_purple_square:"}
+	test := &v1alpha1.TestResult{TestName: "[sig-network] DNS"}
+
+	body, err := RenderTemplate(NewTemplate(tab, test), FlakeTemplate)
+	assert.NoError(t, err)
+	assert.NotContains(t, body.String(), "Flake score")
+	assert.True(t, strings.HasSuffix(SlackMessage(tab, test), "last failure on "+TimeClean(0)))
+
+	test.FlakeScore, test.ScoreRuns = 35, 140
+	body, err = RenderTemplate(NewTemplate(tab, test), FlakeTemplate)
+	assert.NoError(t, err)
+	assert.Contains(t, body.String(), "* Latest flaky: "+TimeClean(0)+"\n* Flake score: 35% of 140 runs\n\n")
+	assert.True(t, strings.HasSuffix(SlackMessage(tab, test), ", flake score 35% of 140 runs"))
+}
+
+func TestTestState(t *testing.T) {
+	tab := &v1alpha1.DashboardTab{BoardHash: "sig-release-master-blocking#gce-cos-master-default", TabState: v1alpha1.FLAKY_STATUS}
+	test := &v1alpha1.TestResult{TestName: "[sig-node] Pods should run"}
+	assert.Equal(t, v1alpha1.FLAKY_STATUS, TestState(tab, test))
+
+	// a test red in its latest runs of a flaky tab is filed as failing
+	test.Classification, test.FailingSince = v1alpha1.CONSISTENT_FAILURE, "v1.35.0-alpha.1.12+0123456789ab"
+	assert.Equal(t, v1alpha1.FAILING_STATUS, TestState(tab, test))
+	body, err := RenderTemplate(NewTemplate(tab, test), FailureTemplate)
+	assert.NoError(t, err)
+	assert.Contains(t, body.String(), "\n* Failing since commit: v1.35.0-alpha.1.12+0123456789ab\n")
+
+	assert.NotContains(t, body.String(), "Suspected commits")
+
+	test.LastPassCommit, test.FirstFailCommit = "6f3c1b8e2a9d04", "a1b2c3d4e5f607"
+	body, err = RenderTemplate(NewTemplate(tab, test), FailureTemplate)
+	assert.NoError(t, err)
+	assert.Contains(t, body.String(), "\n* Suspected commits: [6f3c1b8e2a9d04...a1b2c3d4e5f607]"+
+		"(https://github.com/kubernetes/kubernetes/compare/6f3c1b8e2a9d04...a1b2c3d4e5f607)\n")
+
+	tab.TabState = v1alpha1.FAILING_STATUS
+	test.Classification = v1alpha1.INTERMITTENT_FLAKE
+	assert.Equal(t, v1alpha1.FLAKY_STATUS, TestState(tab, test))
+}
+
+func TestMilestone(t *testing.T) {
+	tab := &v1alpha1.DashboardTab{BoardHash: "sig-release-master-blocking#gce-cos-master-default", TabState: v1alpha1.FAILING_STATUS}
+	test := &v1alpha1.TestResult{TestName: "[sig-node] Pods should run"}
+
+	body, err := RenderTemplate(NewTemplate(tab, test), FailureTemplate)
+	assert.NoError(t, err)
+	assert.NotContains(t, body.String(), "/milestone")
+	assert.NotContains(t, SlackMessage(tab, test), "milestone")
+
+	tab.BoardHash = "sig-release-1.32-blocking#gce-cos-k8sbeta-default"
+	body, err = RenderTemplate(NewTemplate(tab, test), FailureTemplate)
+	assert.NoError(t, err)
+	assert.Contains(t, body.String(), "/kind failing-test\n/milestone v1.32\ncc @kubernetes/release-team-release-signal")
+	assert.True(t, strings.HasSuffix(SlackMessage(tab, test), ", milestone v1.32"))
+}
+
+func TestRenderResolution(t *testing.T) {
+	tab := &v1alpha1.DashboardTab{BoardHash: "sig-release-master-blocking#gce-cos-master-default", TabURL: "https://testgrid.k8s.io/master"}
+	test := &v1alpha1.TestResult{
+		TestName:               "[sig-node] Pods should run",
+		FailureCount:           7,
+		RunCount:               40,
+		RecoveredFromTimestamp: time.Date(2026, 10, 12, 8, 0, 0, 0, time.UTC).UnixMilli(),
+		RecoveredTimestamp:     time.Date(2026, 10, 14, 11, 20, 0, 0, time.UTC).UnixMilli(),
+	}
+
+	resolution := NewResolution(tab, test)
+	assert.Equal(t, "2d 3h", resolution.Duration)
+	body, err := RenderResolution(resolution)
+	assert.NoError(t, err)
+	assert.Contains(t, body, "`[sig-node] Pods should run` is passing again on [sig-release-master-blocking#gce-cos-master-default](https://testgrid.k8s.io/master)")
+	assert.Contains(t, body, "* Recovered on: Wed, 14 Oct 2026 11:20:00 UTC\n* Failing for: 2d 3h\n* Runs affected: 7 of the last 40\n")
+	assert.Contains(t, body, "### Likely fix\n\n_Not detected_\n")
+
+	resolution.Fixes = []Culprit{{Number: 12345, Title: "kubelet: fix probes", URL: "https://github.com/kubernetes/kubernetes/pull/12345"}}
+	body, err = RenderResolution(resolution)
+	assert.NoError(t, err)
+	assert.Contains(t, body, "* [#12345](https://github.com/kubernetes/kubernetes/pull/12345) kubelet: fix probes\n")
+	assert.NotContains(t, body, "_Not detected_")
+
+	resolution.GreenRuns, resolution.KeepOpen = 5, true
+	body, err = RenderResolution(resolution)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(body, "### Recovered\n"))
+	assert.Contains(t, body, "(https://testgrid.k8s.io/master), green for the last 5 runs, consider closing the issue.\n")
+}
+
+func TestRenderJob(t *testing.T) {
+	tab := &v1alpha1.DashboardTab{BoardHash: "sig-release-1.35-blocking#gce-cos-default", TabURL: "https://testgrid.k8s.io/1.35",
+		LatestBuildURL: "https://prow.k8s.io/view/gs/1"}
+	tests := []v1alpha1.TestResult{
+		{TestName: "[sig-node] Pods should run", ErrorMessage: "timeout"},
+		{TestName: "[sig-network] DNS should resolve", ErrorMessage: "no such host"},
+		{TestName: "[sig-node] Probes should restart", ErrorMessage: "timeout"},
+	}
+
+	job := NewJob(tab, tests)
+	assert.Equal(t, []string{"network", "node"}, job.Sigs)
+	assert.Equal(t, "[Failing Job] sig-release-1.35-blocking#gce-cos-default", JobTitle(tab))
+	body, err := RenderJob(job)
+	assert.NoError(t, err)
+	assert.Contains(t, body, "* [sig-release-1.35-blocking#gce-cos-default](https://testgrid.k8s.io/1.35)\n* Latest run: [https://prow.k8s.io/view/gs/1]")
+	assert.Contains(t, body, "* [[sig-network] DNS should resolve](), first failure: ")
+	assert.Contains(t, body, "`[sig-network] DNS should resolve`:\n\n```\nno such host\n```\n")
+	assert.Contains(t, body, "/sig network\n/sig node\n/kind failing-test\n/milestone v1.35\n")
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "42m", FormatDuration(42*time.Minute))
+	assert.Equal(t, "5h", FormatDuration(5*time.Hour+10*time.Minute))
+	assert.Equal(t, "1d 0h", FormatDuration(24*time.Hour))
+}
+
+func TestParseFrontMatter(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		frontMatter *FrontMatter
+		body        string
+		wantErr     bool
+	}{
+		{name: "no front matter", content: "### Which jobs are failing?\n", body: "### Which jobs are failing?\n"},
+		{name: "empty front matter", content: "---\n---\nbody", frontMatter: &FrontMatter{}, body: "body"},
+		{
+			name:        "front matter",
+			content:     "---\nrepo: kubernetes/kubernetes\nlabels: [kind/flake, sig/node]\nassignees: [octocat]\nmilestone: v1.35\n---\n\nbody\n",
+			frontMatter: &FrontMatter{Repo: "kubernetes/kubernetes", Labels: []string{"kind/flake", "sig/node"}, Assignees: []string{"octocat"}, Milestone: "v1.35"},
+			body:        "body\n",
+		},
+		{name: "unterminated", content: "---\nlabels: [kind/flake]\nbody", wantErr: true},
+		{name: "unknown field", content: "---\nlabel: kind/flake\n---\nbody", wantErr: true},
+		{name: "invalid repo", content: "---\nrepo: kubernetes\n---\nbody", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frontMatter, body, err := ParseFrontMatter(tt.content)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.frontMatter, frontMatter)
+			assert.Equal(t, tt.body, body)
+		})
+	}
+}
+
+func TestSetTemplateDir(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "flake.tmpl"),
+		[]byte("---\nrepo: kubernetes/kubernetes\nlabels:\n- kind/flake\n- sig/{{.Sig}}\n---\n{{.TestName}} is flaking\n{{ template \"notes\" . }}"), 0o644))
+	assert.NoError(t, SetTemplateDir(dir))
+	t.Cleanup(func() { _ = SetTemplateDir("") })
+
+	tab := &v1alpha1.DashboardTab{BoardHash: "sig-release-master-informing#kind-master", TabState: v1alpha1.FLAKY_STATUS}
+	issue := NewTemplate(tab, &v1alpha1.TestResult{TestName: "[sig-network] DNS"})
+	issue.Sig = "network"
+	rendered, err := RenderTemplate(issue, FlakeTemplate)
+	assert.NoError(t, err)
+	frontMatter, body, err := ParseFrontMatter(rendered.String())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"kind/flake", "sig/network"}, frontMatter.Labels)
+	assert.True(t, strings.HasPrefix(body, "[sig-network] DNS is flaking\n"))
+
+	// templates missing in the directory fall back to the embedded ones
+	rendered, err = RenderTemplate(issue, FailureTemplate)
+	assert.NoError(t, err)
+	assert.Contains(t, rendered.String(), "### Which jobs are failing?")
+
+	// the sources are read from the directory too
+	source, err := TemplateSource(FlakeTemplate)
+	assert.NoError(t, err)
+	assert.Contains(t, source, "is flaking")
+	source, err = TemplateSource(FailureTemplate)
+	assert.NoError(t, err)
+	assert.Contains(t, source, "### Which jobs are failing?")
+	_, err = TemplateSource("template/missing.tmpl")
+	assert.Error(t, err)
+
+	assert.Error(t, SetTemplateDir(filepath.Join(dir, "missing")))
+}
+
+func TestRenderSource(t *testing.T) {
+	issue := &Template{TestName: "[sig-node] Pods", Notes: []string{"known flake"}}
+	rendered, err := RenderSource(issue, "{{ .TestName }} {{ template \"notes\" . }}")
+	assert.NoError(t, err)
+	assert.Contains(t, rendered.String(), "[sig-node] Pods")
+	assert.Contains(t, rendered.String(), "known flake")
+
+	// the source can redefine the blocks
+	rendered, err = RenderSource(issue, "{{ define \"notes\" }}no notes{{ end }}{{ template \"notes\" . }}")
+	assert.NoError(t, err)
+	assert.Equal(t, "no notes", rendered.String())
+
+	_, err = RenderSource(issue, "{{ .TestName ")
+	assert.Error(t, err)
+	assert.Len(t, TemplateFiles(), 10)
+}
+
+func TestSlackMentions(t *testing.T) {
+	mentions := &SlackMentions{
+		Boards: map[string][]string{
+			"*":                           {"@release-ci-signal"},
+			"sig-release-master-blocking": {"@release-ci-signal", "@release-leads"},
+		},
+		SIG:        "@sig-{sig}-test-failures",
+		QuietHours: &QuietHours{Start: "22:00", End: "07:00", TimeZone: "UTC"},
+	}
+	failing := &v1alpha1.DashboardTab{BoardHash: "sig-release-master-blocking#gce-cos-master-default", TabState: v1alpha1.FAILING_STATUS}
+	flaky := &v1alpha1.DashboardTab{BoardHash: "sig-release-master-blocking#kind-master", TabState: v1alpha1.FLAKY_STATUS}
+	test := &v1alpha1.TestResult{TestName: "Kubernetes e2e suite.[It] [sig-node] Pods should run"}
+	day := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, []string{"@release-ci-signal", "@release-leads", "@sig-node-test-failures"}, mentions.For(failing, test, day))
+	assert.Empty(t, mentions.For(flaky, test, day))
+	assert.Empty(t, mentions.For(failing, test, day.Add(11*time.Hour)))
+	assert.Empty(t, mentions.For(failing, test, day.Add(-6*time.Hour)))
+	assert.Nil(t, (*SlackMentions)(nil).For(failing, test, day))
+
+	assert.Equal(t, "message @a @b", WithMentions("message", []string{"@a", "@b"}))
+	assert.Equal(t, "message", WithMentions("message", nil))
+
+	assert.Error(t, (&QuietHours{Start: "10pm", End: "07:00"}).Validate())
+	assert.Error(t, (&QuietHours{Start: "22:00", End: "07:00", TimeZone: "Mars/Olympus"}).Validate())
+}
+
+func TestSlackRoutes(t *testing.T) {
+	routes := SlackRoutes{"node": "#sig-node-ci"}
+	failing := &v1alpha1.DashboardTab{BoardHash: "sig-release-master-blocking#gce-cos-master-default", TabState: v1alpha1.FAILING_STATUS}
+	flaky := &v1alpha1.DashboardTab{BoardHash: "sig-release-master-blocking#kind-master", TabState: v1alpha1.FLAKY_STATUS}
+
+	assert.Equal(t, "#sig-node-ci", routes.For(flaky, &v1alpha1.TestResult{TestName: "[sig-node] Pods should run"}))
+	assert.Equal(t, "#sig-node-ci", routes.For(flaky, &v1alpha1.TestResult{TestName: "k8s.io/kubernetes/pkg/kubelet.TestRun", Sig: "node"}))
+	assert.Empty(t, routes.For(failing, &v1alpha1.TestResult{TestName: "[sig-node] Pods should run"}))
+	assert.Empty(t, routes.For(flaky, &v1alpha1.TestResult{TestName: "[sig-network] DNS"}))
+	assert.Empty(t, SlackRoutes(nil).For(flaky, &v1alpha1.TestResult{TestName: "[sig-node] Pods should run"}))
+}
+
+func TestRepository(t *testing.T) {
+	tests := []struct {
+		boardHash, testName, repo string
+	}{
+		{"sig-release-master-blocking#gce-cos-master-default", "[sig-node] Pods should run", "kubernetes/kubernetes"},
+		{"sig-release-master-blocking#gce-cos-master-default", "ci-kubernetes-e2e-gci-gce.Up", "kubernetes/test-infra"},
+		{"sig-release-master-informing#kind-master", "kubetest2.Test", "kubernetes/test-infra"},
+		{"sig-testing-infra#prow-monitoring", "Overall", "kubernetes/test-infra"},
+		{"sig-k8s-infra-gcb#image-promotion", "promote images", "kubernetes/k8s.io"},
+		{"sig-release-master-blocking#kind-master", "[sig-release] images should be pulled from registry.k8s.io", "kubernetes/k8s.io"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			assert.Equal(t, tt.repo, Repository(tt.boardHash, tt.testName))
+		})
+	}
+	assert.Equal(t, []string{"kubernetes/kubernetes", "kubernetes/k8s.io", "kubernetes/test-infra"}, Repositories())
+
+	var empty *FrontMatter
+	assert.Equal(t, &FrontMatter{Repo: "kubernetes/test-infra"}, empty.WithRepository("kubernetes/test-infra"))
+	frontMatter := &FrontMatter{Repo: "kubernetes/kubernetes", Labels: []string{"kind/flake"}}
+	assert.Equal(t, frontMatter, frontMatter.WithRepository("kubernetes/test-infra"))
+	frontMatter = &FrontMatter{Labels: []string{"kind/flake"}}
+	assert.Equal(t, "kubernetes/k8s.io", frontMatter.WithRepository("kubernetes/k8s.io").Repo)
+	assert.Empty(t, frontMatter.Repo)
+
+	// the SIG label is added once to repository issues only
+	assert.Equal(t, frontMatter, frontMatter.WithSIGLabel("node"))
+	frontMatter = &FrontMatter{Repo: "kubernetes/kubernetes", Labels: []string{"kind/flake"}}
+	assert.Equal(t, []string{"kind/flake", "sig/node"}, frontMatter.WithSIGLabel("node").Labels)
+	assert.Equal(t, []string{"kind/flake"}, frontMatter.Labels)
+	frontMatter.Labels = append(frontMatter.Labels, "sig/node")
+	assert.Equal(t, frontMatter, frontMatter.WithSIGLabel("node"))
+}
+
+func TestCommands(t *testing.T) {
+	body := "### Relevant SIG(s)\n\n/sig \n/kind failing-test\n/priority important-soon\n/milestone v1.35\n"
+	commands := ParseCommands(body)
+	assert.Equal(t, Commands{Priority: "important-soon", Milestone: "v1.35"}, commands)
+
+	applied := Commands{Sig: "node", Milestone: "v1.36"}.Apply(body)
+	assert.Equal(t, "### Relevant SIG(s)\n\n/sig node\n/kind failing-test\n/milestone v1.36\n", applied)
+	assert.Equal(t, Commands{Sig: "node", Milestone: "v1.36"}, ParseCommands(applied))
+
+	// the missing commands follow the last command, or end the body
+	assert.Equal(t, "/sig node\n/priority critical-urgent\n/kind flake\n",
+		Commands{Sig: "node", Priority: "critical-urgent"}.Apply("/sig node\n/kind flake\n"))
+	assert.Equal(t, "Failing.\n/sig node\n\n", Commands{Sig: "node"}.Apply("Failing.\n\n"))
+	assert.Equal(t, "Failing.", Commands{}.Apply("Failing."))
+}
@@ -0,0 +1,70 @@
+package issue
+
+import (
+	"bytes"
+	"path"
+	"slices"
+	"strings"
+	"text/template"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/release"
+)
+
+// JobTemplate is the single issue filed for a broken job, listing its
+// failing tests instead of an issue per test.
+const JobTemplate = "template/job.tmpl"
+
+// Job holds the fields rendered in the job template.
+type Job struct {
+	BoardName   string
+	TabName     string
+	TestGridURL string
+	ProwURL     string
+	Milestone   string
+
+	// Sigs are the SIGs of the tests, sorted without duplicates.
+	Sigs []string
+
+	// Tests are the failing tests of the job, in the tab order.
+	Tests []*Template
+}
+
+// NewJob creates the job issue of the failing tests of the tab.
+func NewJob(tab *v1alpha1.DashboardTab, tests []v1alpha1.TestResult) *Job {
+	boardName, tabName, _ := strings.Cut(tab.BoardHash, "#")
+	job := &Job{
+		BoardName:   boardName,
+		TabName:     tabName,
+		TestGridURL: tab.TabURL,
+		ProwURL:     tab.LatestBuildURL,
+		Milestone:   release.Milestone(tab.BoardHash),
+	}
+	for i := range tests {
+		test := NewTemplate(tab, &tests[i])
+		job.Tests = append(job.Tests, test)
+		if test.Sig != "" && !slices.Contains(job.Sigs, test.Sig) {
+			job.Sigs = append(job.Sigs, test.Sig)
+		}
+	}
+	slices.Sort(job.Sigs)
+	return job
+}
+
+// JobTitle returns the issue title of a broken job.
+func JobTitle(tab *v1alpha1.DashboardTab) string {
+	return Title("Failing Job", tab.BoardHash)
+}
+
+// RenderJob executes the job template.
+func RenderJob(job *Job) (string, error) {
+	tmpl, err := template.New(path.Base(JobTemplate)).Funcs(funcMap).ParseFS(templates, JobTemplate)
+	if err != nil {
+		return "", err
+	}
+	var output bytes.Buffer
+	if err := tmpl.Execute(&output, job); err != nil {
+		return "", err
+	}
+	return output.String(), nil
+}
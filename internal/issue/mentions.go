@@ -0,0 +1,119 @@
+package issue
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/sig"
+)
+
+// sigPlaceholder is replaced by the SIG of the test in SlackMentions.SIG.
+const sigPlaceholder = "{sig}"
+
+// SlackMentions configures the @-mentions appended to the Slack messages of
+// failing tests, flaky tests never mention anyone.
+type SlackMentions struct {
+	// Boards are the mentions by board name, "*" applies to every board.
+	Boards map[string][]string `json:"mentions,omitempty"`
+
+	// SIG is the mention of the group owning the test, {sig} is replaced by
+	// the SIG of the test name, e.g. @sig-{sig}-test-failures.
+	SIG string `json:"sigMention,omitempty"`
+
+	// QuietHours leaves the mentions out, e.g. outside the working hours.
+	QuietHours *QuietHours `json:"quietHours,omitempty"`
+}
+
+// QuietHours is a daily time range, wrapping past midnight when End is
+// before Start.
+type QuietHours struct {
+	// Start and End are 24-hour clock times, e.g. 22:00.
+	Start string `json:"start"`
+	End   string `json:"end"`
+
+	// TimeZone is the IANA time zone of the range, the local one when empty.
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// Validate checks the times and the time zone of the quiet hours.
+func (q *QuietHours) Validate() error {
+	if q == nil {
+		return nil
+	}
+	for _, clock := range []string{q.Start, q.End} {
+		if _, err := time.Parse("15:04", clock); err != nil {
+			return fmt.Errorf("error parsing quiet hours %q, expected HH:MM", clock)
+		}
+	}
+	if _, err := time.LoadLocation(q.TimeZone); err != nil {
+		return fmt.Errorf("error loading quiet hours time zone: %v", err)
+	}
+	return nil
+}
+
+// Contains returns true when now falls in the quiet hours.
+func (q *QuietHours) Contains(now time.Time) bool {
+	if q == nil || q.Validate() != nil {
+		return false
+	}
+	location, _ := time.LoadLocation(q.TimeZone)
+	clock := now.In(location).Format("15:04")
+	if q.Start <= q.End {
+		return clock >= q.Start && clock < q.End
+	}
+	return clock >= q.Start || clock < q.End
+}
+
+// For returns the mentions of a test of the tab at now, without duplicates.
+func (m *SlackMentions) For(tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestResult, now time.Time) []string {
+	if m == nil || tab.TabState != v1alpha1.FAILING_STATUS || m.QuietHours.Contains(now) {
+		return nil
+	}
+	board, _, _ := strings.Cut(tab.BoardHash, "#")
+	candidates := append(append([]string{}, m.Boards["*"]...), m.Boards[board]...)
+	if testSig := testSIG(currentTest); testSig != "" && m.SIG != "" {
+		candidates = append(candidates, strings.ReplaceAll(m.SIG, sigPlaceholder, testSig))
+	}
+
+	var mentions []string
+	seen := map[string]bool{}
+	for _, mention := range candidates {
+		if mention = strings.TrimSpace(mention); mention != "" && !seen[mention] {
+			seen[mention] = true
+			mentions = append(mentions, mention)
+		}
+	}
+	return mentions
+}
+
+// testSIG returns the SIG of the test, read from its name when it wasn't
+// inferred by the fetcher.
+func testSIG(currentTest *v1alpha1.TestResult) string {
+	if currentTest.Sig != "" {
+		return currentTest.Sig
+	}
+	return sig.FromTestName(currentTest.TestName)
+}
+
+// WithMentions appends the mentions to the Slack message.
+func WithMentions(message string, mentions []string) string {
+	if len(mentions) == 0 {
+		return message
+	}
+	return message + " " + strings.Join(mentions, " ")
+}
+
+// SlackRoutes maps a SIG to its Slack channel, e.g. node: "#sig-node-ci",
+// the messages of the flaky tests are posted there too.
+type SlackRoutes map[string]string
+
+// For returns the channel of the SIG owning the flaky test, empty for the
+// failing tests and the SIGs without channel.
+func (r SlackRoutes) For(tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestResult) string {
+	if tab.TabState != v1alpha1.FLAKY_STATUS {
+		return ""
+	}
+	return strings.TrimSpace(r[testSIG(currentTest)])
+}
@@ -0,0 +1,74 @@
+package issue
+
+import (
+	"regexp"
+	"slices"
+)
+
+// DefaultRepository receives the issues of the tests matching no repository
+// rule.
+const DefaultRepository = "kubernetes/kubernetes"
+
+// repositoryRules route the issues filed in a repository by board hash and
+// test name, the first matching rule wins: the community infrastructure
+// (registry.k8s.io, k8s-infra boards) goes to kubernetes/k8s.io, the CI
+// infrastructure and the job steps of kubetest, e.g. ci-kubernetes-e2e.Up,
+// go to kubernetes/test-infra.
+var repositoryRules = []struct {
+	pattern *regexp.Regexp
+	repo    string
+}{
+	{regexp.MustCompile(`(?i)k8s\.io|k8s-infra`), "kubernetes/k8s.io"},
+	{regexp.MustCompile(`(?i)infra|^kubetest2?\.|\.(Overall|Up|Down|Build|Extract|DumpClusterLogs|TearDown)$`), "kubernetes/test-infra"},
+}
+
+// Repository returns the owner/name repository the issue of the test is
+// filed in when issues are not drafted in the project board.
+func Repository(boardHash, testName string) string {
+	for _, rule := range repositoryRules {
+		if rule.pattern.MatchString(boardHash) || rule.pattern.MatchString(testName) {
+			return rule.repo
+		}
+	}
+	return DefaultRepository
+}
+
+// Repositories returns the repositories the issues are filed in by the
+// repository rules, the default one first.
+func Repositories() []string {
+	repos := []string{DefaultRepository}
+	for _, rule := range repositoryRules {
+		if !slices.Contains(repos, rule.repo) {
+			repos = append(repos, rule.repo)
+		}
+	}
+	return repos
+}
+
+// WithRepository returns a copy of the front matter creating the issue in
+// the repository, the repository of the template front matter wins.
+func (f *FrontMatter) WithRepository(repo string) *FrontMatter {
+	frontMatter := FrontMatter{}
+	if f != nil {
+		frontMatter = *f
+	}
+	if frontMatter.Repo == "" {
+		frontMatter.Repo = repo
+	}
+	return &frontMatter
+}
+
+// WithSIGLabel returns a copy of the front matter labeling the repository
+// issue with the sig/ label of the SIG, drafts have no labels.
+func (f *FrontMatter) WithSIGLabel(sig string) *FrontMatter {
+	if f == nil || f.Repo == "" || sig == "" {
+		return f
+	}
+	label := "sig/" + sig
+	if slices.Contains(f.Labels, label) {
+		return f
+	}
+	frontMatter := *f
+	frontMatter.Labels = append(slices.Clone(f.Labels), label)
+	return &frontMatter
+}
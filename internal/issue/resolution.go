@@ -0,0 +1,78 @@
+package issue
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"text/template"
+	"time"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+)
+
+// ResolutionTemplate is the comment posted when the issue of a recovered test
+// is closed, or left open for the maintainers to close.
+const ResolutionTemplate = "template/resolution.tmpl"
+
+// Resolution holds the fields rendered in the resolution template.
+type Resolution struct {
+	BoardHash    string
+	TestGridURL  string
+	TestName     string
+	FailingSince string
+	RecoveredAt  string
+	Duration     string
+	FailedRuns   int
+	Runs         int
+
+	// Fixes are the pull requests merged between the last red and the first
+	// green run, ranked by overlap with the test area.
+	Fixes []Culprit
+
+	// GreenRuns is the number of consecutive green runs since the recovery,
+	// left out of the comment when zero.
+	GreenRuns int
+
+	// KeepOpen suggests closing the issue instead of closing it.
+	KeepOpen bool
+}
+
+// NewResolution creates the resolution of a recovered test of the tab.
+func NewResolution(tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestResult) *Resolution {
+	return &Resolution{
+		BoardHash:    tab.BoardHash,
+		TestGridURL:  tab.TabURL,
+		TestName:     currentTest.TestName,
+		FailingSince: TimeClean(currentTest.RecoveredFromTimestamp),
+		RecoveredAt:  TimeClean(currentTest.RecoveredTimestamp),
+		Duration:     FormatDuration(time.Duration(currentTest.RecoveredTimestamp-currentTest.RecoveredFromTimestamp) * time.Millisecond),
+		FailedRuns:   currentTest.FailureCount,
+		Runs:         currentTest.RunCount,
+	}
+}
+
+// RenderResolution executes the resolution template.
+func RenderResolution(resolution *Resolution) (string, error) {
+	tmpl, err := template.New(path.Base(ResolutionTemplate)).Funcs(funcMap).ParseFS(templates, ResolutionTemplate)
+	if err != nil {
+		return "", err
+	}
+	var output bytes.Buffer
+	if err := tmpl.Execute(&output, resolution); err != nil {
+		return "", err
+	}
+	return output.String(), nil
+}
+
+// FormatDuration renders the duration in days and hours, or in minutes when
+// it is shorter than an hour.
+func FormatDuration(d time.Duration) string {
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Round(time.Minute).Minutes()))
+	}
+	hours := int(d.Round(time.Hour).Hours())
+	if hours < 24 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dd %dh", hours/24, hours%24)
+}
@@ -0,0 +1,262 @@
+package links
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/monitoring"
+)
+
+const (
+	// DefaultTTL is the time a link check is cached, the links of a test are
+	// checked again on each render otherwise.
+	DefaultTTL = 30 * time.Minute
+
+	checkTimeout = 10 * time.Second
+)
+
+// GCSWebURL browses the job artifacts, the alternate of a dead Prow link.
+var GCSWebURL = "https://gcsweb.k8s.io/gcs"
+
+// Links are the generated links of a test included in the issues and the
+// Slack messages.
+type Links struct {
+	TestGrid string
+	Prow     string
+	Triage   string
+}
+
+// For returns the links of the test of the tab.
+func For(tab *v1alpha1.DashboardTab, test *v1alpha1.TestResult) Links {
+	return Links{TestGrid: tab.TabURL, Prow: test.ProwJobURL, Triage: test.TriageURL}
+}
+
+// Apply returns copies of the tab and the test with the links set.
+func (l Links) Apply(tab *v1alpha1.DashboardTab, test *v1alpha1.TestResult) (*v1alpha1.DashboardTab, *v1alpha1.TestResult) {
+	tabCopy, testCopy := *tab, *test
+	tabCopy.TabURL, testCopy.ProwJobURL, testCopy.TriageURL = l.TestGrid, l.Prow, l.Triage
+	return &tabCopy, &testCopy
+}
+
+// DeadLink is a link found dead, with the alternate it was replaced by, empty
+// when none works.
+type DeadLink struct {
+	Name      string
+	URL       string
+	Alternate string
+}
+
+func (d DeadLink) String() string {
+	if d.Alternate == "" {
+		return fmt.Sprintf("%s link %s is dead", d.Name, d.URL)
+	}
+	return fmt.Sprintf("%s link %s is dead, replaced by %s", d.Name, d.URL, d.Alternate)
+}
+
+// Checker checks the links with HEAD requests, the results are cached for
+// the TTL.
+type Checker struct {
+	TTL time.Duration
+
+	client *http.Client
+	mu     sync.Mutex
+	checks map[string]check
+}
+
+type check struct {
+	status int
+	at     time.Time
+}
+
+// NewChecker returns a checker caching the results for DefaultTTL.
+func NewChecker() *Checker {
+	return &Checker{TTL: DefaultTTL, client: &http.Client{Timeout: checkTimeout}, checks: map[string]check{}}
+}
+
+// Dead returns true when the link answers 404 or 410. Unreachable hosts and
+// server errors are not flagged, the link may work again.
+func (c *Checker) Dead(link string) bool {
+	status := c.check(link)
+	return status == http.StatusNotFound || status == http.StatusGone
+}
+
+// alive returns true when the link answers a success or a redirect.
+func (c *Checker) alive(link string) bool {
+	status := c.check(link)
+	return status >= http.StatusOK && status < http.StatusBadRequest
+}
+
+// check returns the cached status code of the link, 0 when the link is
+// unreachable. Unreachable hosts and server errors are not cached.
+func (c *Checker) check(link string) int {
+	c.mu.Lock()
+	cached, found := c.checks[link]
+	c.mu.Unlock()
+	found = found && time.Since(cached.at) < c.TTL
+	monitoring.RecordCacheLookup("links", found)
+	if found {
+		return cached.status
+	}
+
+	status, err := c.status(link)
+	if err != nil {
+		return 0
+	}
+	if status < http.StatusInternalServerError {
+		c.mu.Lock()
+		c.checks[link] = check{status: status, at: time.Now()}
+		c.mu.Unlock()
+	}
+	return status
+}
+
+// status returns the status code of a HEAD request, or of a GET request for
+// the servers not allowing HEAD.
+func (c *Checker) status(link string) (int, error) {
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		request, err := http.NewRequest(method, link, nil)
+		if err != nil {
+			return 0, err
+		}
+		response, err := c.client.Do(request)
+		if err != nil {
+			return 0, err
+		}
+		response.Body.Close() // nolint
+		if response.StatusCode != http.StatusMethodNotAllowed && response.StatusCode != http.StatusNotImplemented {
+			return response.StatusCode, nil
+		}
+	}
+	return http.StatusMethodNotAllowed, nil
+}
+
+// Resolve checks the links and replaces the dead ones by the first working
+// alternate: the dashboard of a tab missing from TestGrid and the artifacts
+// of a run missing from Prow. A dead Prow link usually comes from a renamed
+// job, the job filter of the Triage link is then dropped too. A dead link
+// without working alternate is kept and flagged.
+func (c *Checker) Resolve(links Links) (Links, []DeadLink) {
+	var dead []DeadLink
+	replace := func(name, link, probe string, alternates ...string) string {
+		if link == "" || !c.Dead(probe) {
+			return link
+		}
+		found := DeadLink{Name: name, URL: link}
+		for _, alternate := range alternates {
+			if alternate != "" && alternate != link && c.alive(alternate) {
+				found.Alternate = alternate
+				break
+			}
+		}
+		dead = append(dead, found)
+		if found.Alternate != "" {
+			return found.Alternate
+		}
+		return link
+	}
+
+	resolved := Links{
+		TestGrid: replace("TestGrid", links.TestGrid, testGridTable(links.TestGrid), testGridDashboard(links.TestGrid)),
+		Prow:     replace("Prow", links.Prow, links.Prow, gcsWeb(links.Prow)),
+		Triage:   links.Triage,
+	}
+	if links.Prow != "" && c.Dead(links.Prow) {
+		if triage := withoutJob(links.Triage); triage != links.Triage {
+			dead = append(dead, DeadLink{Name: "Triage", URL: links.Triage, Alternate: triage})
+			resolved.Triage = triage
+			return resolved, dead
+		}
+	}
+	resolved.Triage = replace("Triage", links.Triage, links.Triage)
+	return resolved, dead
+}
+
+// testGridTable returns the table of the tab in a TestGrid link, e.g.
+// https://testgrid.k8s.io/sig-release-master-blocking#gce-cos-master-default,
+// the fragment naming the tab isn't sent to the server.
+func testGridTable(link string) string {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	board := strings.Trim(parsed.Path, "/")
+	tab, _, _ := strings.Cut(parsed.Fragment, "&")
+	if board == "" || tab == "" {
+		return link
+	}
+	return fmt.Sprintf("%s://%s/%s/table?tab=%s", parsed.Scheme, parsed.Host, board, url.QueryEscape(tab))
+}
+
+// testGridDashboard returns the dashboard of the tab in a TestGrid link.
+func testGridDashboard(link string) string {
+	parsed, err := url.Parse(link)
+	if err != nil || strings.Trim(parsed.Path, "/") == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s://%s/%s", parsed.Scheme, parsed.Host, strings.Trim(parsed.Path, "/"))
+}
+
+// gcsWeb returns the artifacts browser of the run in a Prow link, e.g.
+// https://prow.k8s.io/view/gs/<bucket>/logs/<job>/<build>.
+func gcsWeb(link string) string {
+	_, object, found := strings.Cut(link, "/view/gs/")
+	if !found || object == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/", GCSWebURL, strings.Trim(object, "/"))
+}
+
+// Prow link kinds built by ProwLink.
+const (
+	ProwView      = "view"
+	ProwHistory   = "history"
+	ProwArtifacts = "artifacts"
+	ProwBuildLog  = "build-log"
+)
+
+var prowLinkKinds = []string{ProwView, ProwHistory, ProwArtifacts, ProwBuildLog}
+
+// ProwLink returns the link of the kind built from the Prow link of a run,
+// e.g. the job history of https://prow.k8s.io/view/gs/<bucket>/logs/<job>/<build>.
+// A link not pointing to a run returns an empty string.
+func ProwLink(kind, link string) (string, error) {
+	if !slices.Contains(prowLinkKinds, kind) {
+		return "", fmt.Errorf("unknown prow link %q, use one of %s", kind, strings.Join(prowLinkKinds, ", "))
+	}
+	prow, object, found := strings.Cut(link, "/view/gs/")
+	object = strings.Trim(object, "/")
+	if !found || !strings.Contains(object, "/") {
+		return "", nil
+	}
+	switch kind {
+	case ProwHistory:
+		return fmt.Sprintf("%s/job-history/gs/%s", prow, path.Dir(object)), nil
+	case ProwArtifacts:
+		return gcsWeb(link), nil
+	case ProwBuildLog:
+		return fmt.Sprintf("%s/%s/build-log.txt", GCSWebURL, object), nil
+	}
+	return link, nil
+}
+
+// withoutJob returns the Triage link without its job filter.
+func withoutJob(link string) string {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	query := parsed.Query()
+	if !query.Has("job") {
+		return link
+	}
+	query.Del("job")
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
@@ -0,0 +1,92 @@
+package links
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch {
+		case r.Method != http.MethodHead && strings.HasPrefix(r.URL.Path, "/gcs/"):
+			w.WriteHeader(http.StatusOK)
+		case strings.HasPrefix(r.URL.Path, "/gcs/"):
+			// the artifacts browser doesn't allow HEAD
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case r.URL.Path == "/sig-release-master-blocking/table" && r.URL.Query().Get("tab") == "gce-cos-renamed",
+			strings.Contains(r.URL.Path, "/ci-kubernetes-renamed/"):
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/unavailable":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	t.Cleanup(server.Close)
+	gcsWebURL := GCSWebURL
+	GCSWebURL = server.URL + "/gcs"
+	t.Cleanup(func() { GCSWebURL = gcsWebURL })
+
+	checker := NewChecker()
+	alive := Links{
+		TestGrid: server.URL + "/sig-release-master-blocking#gce-cos-master-default&exclude-non-failed-tests=",
+		Prow:     server.URL + "/view/gs/kubernetes-ci-logs/logs/ci-kubernetes-e2e/1",
+		Triage:   server.URL + "/k8s-triage/index.html?job=ci-kubernetes-e2e$&test=Pods",
+	}
+	resolved, dead := checker.Resolve(alive)
+	assert.Equal(t, alive, resolved)
+	assert.Empty(t, dead)
+
+	// the results are cached
+	count := requests.Load()
+	_, _ = checker.Resolve(alive)
+	assert.Equal(t, count, requests.Load())
+
+	renamed := Links{
+		TestGrid: server.URL + "/sig-release-master-blocking#gce-cos-renamed&exclude-non-failed-tests=",
+		Prow:     server.URL + "/view/gs/kubernetes-ci-logs/logs/ci-kubernetes-renamed/1",
+		Triage:   server.URL + "/k8s-triage/index.html?job=ci-kubernetes-renamed$&test=Pods",
+	}
+	resolved, dead = checker.Resolve(renamed)
+	assert.Equal(t, Links{
+		TestGrid: server.URL + "/sig-release-master-blocking",
+		Prow:     server.URL + "/gcs/kubernetes-ci-logs/logs/ci-kubernetes-renamed/1/",
+		Triage:   server.URL + "/k8s-triage/index.html?test=Pods",
+	}, resolved)
+	assert.Len(t, dead, 3)
+	assert.Equal(t, "TestGrid link "+renamed.TestGrid+" is dead, replaced by "+server.URL+"/sig-release-master-blocking", dead[0].String())
+
+	// server errors are not flagged
+	resolved, dead = checker.Resolve(Links{Triage: server.URL + "/unavailable"})
+	assert.Equal(t, server.URL+"/unavailable", resolved.Triage)
+	assert.Empty(t, dead)
+}
+
+func TestProwLink(t *testing.T) {
+	run := "https://prow.k8s.io/view/gs/kubernetes-ci-logs/logs/ci-kubernetes-e2e-kind/1978"
+	tests := []struct {
+		kind, link, want string
+	}{
+		{ProwView, run, run},
+		{ProwHistory, run, "https://prow.k8s.io/job-history/gs/kubernetes-ci-logs/logs/ci-kubernetes-e2e-kind"},
+		{ProwArtifacts, run, GCSWebURL + "/kubernetes-ci-logs/logs/ci-kubernetes-e2e-kind/1978/"},
+		{ProwBuildLog, run, GCSWebURL + "/kubernetes-ci-logs/logs/ci-kubernetes-e2e-kind/1978/build-log.txt"},
+		{ProwHistory, "", ""},
+		{ProwHistory, "https://prow.k8s.io/view/gs/kubernetes-ci-logs/", ""},
+	}
+	for _, tt := range tests {
+		link, err := ProwLink(tt.kind, tt.link)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, link, tt.kind+" "+tt.link)
+	}
+
+	_, err := ProwLink("junit", "")
+	assert.EqualError(t, err, `unknown prow link "junit", use one of view, history, artifacts, build-log`)
+}
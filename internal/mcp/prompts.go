@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/signalhound/internal/handbook"
+)
+
+// testArguments are the arguments describing the test of a prompt.
+var testArguments = []PromptArgument{
+	{Name: "board", Description: "TestGrid board#tab of the test, e.g. sig-release-master-blocking#gce-cos-master-default", Required: true},
+	{Name: "test", Description: "full test name as shown on TestGrid", Required: true},
+	{Name: "state", Description: "TestGrid state of the tab, FAILING or FLAKY"},
+	{Name: "error", Description: "error message of the latest failure"},
+}
+
+// boardArguments are the arguments describing the board of a batch analysis.
+var boardArguments = []PromptArgument{
+	{Name: "board", Description: "TestGrid board analyzed, e.g. sig-release-master-blocking", Required: true},
+	{Name: "tests", Description: "failing and flaky tests of the board, one per line as <tab>: <test> (<state>)", Required: true},
+}
+
+// RegisterPrompts adds the signalhound prompts to the server, grounded in
+// the CI Signal handbook so the output follows the team conventions.
+func RegisterPrompts(s *Server) {
+	s.AddPrompt(&Prompt{
+		Name:        "analyze_failure",
+		Description: "Analyze a failing or flaky test following the CI Signal handbook triage conventions.",
+		Arguments:   testArguments,
+		Handler: func(arguments map[string]string) string {
+			return groundedPrompt(arguments, []string{"triage", "priority"},
+				"Analyze the test below: say whether it is failing or flaking, the most likely cause and "+
+					"whether it is an infrastructure problem, the SIG owning it, and the next step for the CI "+
+					"Signal team. Mark guesses as such.")
+		},
+	})
+	s.AddPrompt(&Prompt{
+		Name:        "draft_issue",
+		Description: "Write the GitHub issue of a failing or flaky test following the CI Signal handbook, ready for create_draft_issue.",
+		Arguments:   testArguments,
+		Handler: func(arguments map[string]string) string {
+			return groundedPrompt(arguments, []string{"issues", "priority", "communication"},
+				"Write the title and the markdown body of the GitHub issue reporting the test below, "+
+					"then create it with the create_draft_issue tool, passing the board.")
+		},
+	})
+	// a day with many red boards doesn't fit in a single analysis, clients
+	// analyze each board apart, showing the results as they come, and then
+	// combine them.
+	s.AddPrompt(&Prompt{
+		Name:        "analyze_board",
+		Description: "Analyze the failing and flaky tests of a single board, to split the analysis of all the boards.",
+		Arguments:   boardArguments,
+		Handler: func(arguments map[string]string) string {
+			var prompt strings.Builder
+			fmt.Fprintf(&prompt, "%s\n\nBoard: %s\nTests:\n%s\n", handbookIntro("triage", "priority"),
+				arguments["board"], strings.TrimSpace(arguments["tests"]))
+			prompt.WriteString("\nAnalyze the tests of this board only: group the tests failing for the same cause, " +
+				"flag the infrastructure problems, and list the tests missing an issue with their priority. " +
+				"End with a single paragraph summarizing the board. Mark guesses as such.\n")
+			return prompt.String()
+		},
+	})
+	s.AddPrompt(&Prompt{
+		Name:        "summarize_boards",
+		Description: "Combine the analyze_board results of every board into the CI Signal summary.",
+		Arguments: []PromptArgument{
+			{Name: "analyses", Description: "analyze_board results, each one starting with its board name", Required: true},
+		},
+		Handler: func(arguments map[string]string) string {
+			return fmt.Sprintf("%s\n\nThe boards were analyzed one by one:\n\n%s\n\n"+
+				"Combine the analyses into a single CI Signal summary: the causes shared across boards first, "+
+				"then the tests missing an issue by priority, then the state of each board in one line. "+
+				"Don't repeat the details of the analyses.\n",
+				handbookIntro("triage", "priority", "communication"), strings.TrimSpace(arguments["analyses"]))
+		},
+	})
+}
+
+// handbookIntro renders the handbook sections introducing every prompt.
+func handbookIntro(sections ...string) string {
+	return fmt.Sprintf("You are helping the Kubernetes release CI Signal team. Follow these excerpts of the CI Signal handbook:\n\n%s",
+		handbook.Excerpts(sections...))
+}
+
+// groundedPrompt renders the task on the test after the handbook sections.
+func groundedPrompt(arguments map[string]string, sections []string, task string) string {
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "%s\n\n", handbookIntro(sections...))
+	fmt.Fprintf(&prompt, "%s\n\nBoard: %s\nTest: %s\n", task, arguments["board"], arguments["test"])
+	if state := arguments["state"]; state != "" {
+		fmt.Fprintf(&prompt, "State: %s\n", state)
+	}
+	if message := arguments["error"]; message != "" {
+		fmt.Fprintf(&prompt, "Error:\n```\n%s\n```\n", message)
+	}
+	return prompt.String()
+}
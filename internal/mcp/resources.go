@@ -0,0 +1,42 @@
+package mcp
+
+import (
+	"fmt"
+	"path"
+
+	"sigs.k8s.io/signalhound/internal/issue"
+)
+
+// templateURI is the URI of the issue template resources, e.g.
+// signalhound://templates/failure.tmpl.
+const templateURI = "signalhound://templates/%s"
+
+// templateDescriptions describe the template resources, the blocks are
+// shared by the issue templates.
+var templateDescriptions = map[string]string{
+	issue.FailureTemplate:    "Issue template of a failing test, with its YAML front matter.",
+	issue.FlakeTemplate:      "Issue template of a flaky test, with its YAML front matter.",
+	issue.JobTemplate:        "Issue template of a broken job, listing its failing tests.",
+	issue.ResolutionTemplate: "Comment posted on the issue of a recovered test.",
+}
+
+// RegisterResources adds the issue templates to the server as resources, the
+// ones of the --templates directory when set, so the clients render the same
+// issues as the UI, see the render_issue tool.
+func RegisterResources(s *Server) {
+	for _, templateFile := range issue.TemplateFiles() {
+		description, found := templateDescriptions[templateFile]
+		if !found {
+			description = fmt.Sprintf("The %s block of the issue templates.", path.Base(templateFile))
+		}
+		s.AddResource(&Resource{
+			URI:         fmt.Sprintf(templateURI, path.Base(templateFile)),
+			Name:        path.Base(templateFile),
+			Description: description + " A Go text/template of the test fields, e.g. {{ .TestName }} or {{ .ErrMessage }}.",
+			MimeType:    "text/plain",
+			Handler: func() (string, error) {
+				return issue.TemplateSource(templateFile)
+			},
+		})
+	}
+}
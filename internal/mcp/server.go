@@ -0,0 +1,373 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ProtocolVersion is the latest MCP revision implemented by the server.
+const ProtocolVersion = "2025-03-26"
+
+// supportedVersions are the revisions accepted from clients, older clients
+// get their own version echoed back.
+var supportedVersions = map[string]bool{
+	"2024-11-05": true,
+	"2025-03-26": true,
+	"2025-06-18": true,
+}
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// TokenMetaKey is the initialize params _meta key carrying the GitHub token
+// of the session.
+const TokenMetaKey = "signalhound/githubToken"
+
+// Request is a JSON-RPC request or notification, notifications have no ID.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is a JSON-RPC response.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error object.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Tool is a tool exposed to the MCP clients.
+type Tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+
+	// Handler runs the tool with the raw arguments, errors are reported to the
+	// client as tool errors.
+	Handler func(ctx context.Context, session *Session, arguments json.RawMessage) (string, error) `json:"-"`
+}
+
+// Content is a text content block of a tool result.
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// ToolResult is the result of a tools/call request.
+type ToolResult struct {
+	Content []Content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
+
+// PromptArgument is an argument of a prompt template.
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// Prompt is a prompt template exposed to the MCP clients.
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+
+	// Handler renders the prompt text from the arguments, the required
+	// arguments are checked before.
+	Handler func(arguments map[string]string) string `json:"-"`
+}
+
+// PromptMessage is a message of a prompts/get result.
+type PromptMessage struct {
+	Role    string  `json:"role"`
+	Content Content `json:"content"`
+}
+
+// PromptResult is the result of a prompts/get request.
+type PromptResult struct {
+	Description string          `json:"description"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+// Resource is a text document exposed to the MCP clients.
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+
+	// Handler reads the resource text on each resources/read request.
+	Handler func() (string, error) `json:"-"`
+}
+
+// ResourceContents is the text of a resource in a resources/read result.
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text"`
+}
+
+// Server dispatches the MCP requests to the registered tools, prompts and
+// resources.
+type Server struct {
+	name    string
+	version string
+
+	// token is the GitHub token used by sessions without their own.
+	token string
+
+	// RequireSessionToken rejects sessions initialized without their own
+	// GitHub token instead of falling back to the server token.
+	RequireSessionToken bool
+
+	mu        sync.RWMutex
+	tools     map[string]*Tool
+	prompts   map[string]*Prompt
+	resources map[string]*Resource
+}
+
+// NewServer returns a server with no tools, token is the GitHub token shared
+// by the sessions not bringing their own.
+func NewServer(name, version, token string) *Server {
+	return &Server{name: name, version: version, token: token,
+		tools: map[string]*Tool{}, prompts: map[string]*Prompt{}, resources: map[string]*Resource{}}
+}
+
+// AddTool registers a tool, replacing any tool with the same name.
+func (s *Server) AddTool(tool *Tool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tools[tool.Name] = tool
+}
+
+// AddPrompt registers a prompt, replacing any prompt with the same name.
+func (s *Server) AddPrompt(prompt *Prompt) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prompts[prompt.Name] = prompt
+}
+
+// AddResource registers a resource, replacing any resource with the same URI.
+func (s *Server) AddResource(resource *Resource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resources[resource.URI] = resource
+}
+
+type initializeParams struct {
+	ProtocolVersion string                 `json:"protocolVersion"`
+	ClientInfo      map[string]interface{} `json:"clientInfo"`
+	Meta            map[string]interface{} `json:"_meta"`
+}
+
+type callParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+type getPromptParams struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments"`
+}
+
+type readResourceParams struct {
+	URI string `json:"uri"`
+}
+
+// Handle processes a single request of the session and returns the response,
+// or nil for notifications.
+func (s *Server) Handle(ctx context.Context, session *Session, request *Request) *Response {
+	result, rpcErr := s.dispatch(ctx, session, request)
+	if len(request.ID) == 0 {
+		return nil
+	}
+	response := &Response{JSONRPC: "2.0", ID: request.ID}
+	if rpcErr != nil {
+		response.Error = rpcErr
+	} else {
+		response.Result = result
+	}
+	return response
+}
+
+func (s *Server) dispatch(ctx context.Context, session *Session, request *Request) (interface{}, *Error) {
+	if request.JSONRPC != "2.0" || request.Method == "" {
+		return nil, &Error{Code: codeInvalidRequest, Message: "invalid JSON-RPC 2.0 request"}
+	}
+
+	switch request.Method {
+	case "initialize":
+		return s.initialize(session, request.Params)
+	case "ping":
+		return struct{}{}, nil
+	case "notifications/initialized", "notifications/cancelled":
+		return nil, nil
+	}
+
+	if !session.Initialized() {
+		return nil, &Error{Code: codeInvalidRequest, Message: "session not initialized"}
+	}
+	switch request.Method {
+	case "tools/list":
+		return map[string]interface{}{"tools": s.listTools()}, nil
+	case "tools/call":
+		var params callParams
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			return nil, &Error{Code: codeInvalidParams, Message: err.Error()}
+		}
+		return s.callTool(ctx, session, &params)
+	case "prompts/list":
+		return map[string]interface{}{"prompts": s.listPrompts()}, nil
+	case "prompts/get":
+		var params getPromptParams
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			return nil, &Error{Code: codeInvalidParams, Message: err.Error()}
+		}
+		return s.getPrompt(&params)
+	case "resources/list":
+		return map[string]interface{}{"resources": s.listResources()}, nil
+	case "resources/read":
+		var params readResourceParams
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			return nil, &Error{Code: codeInvalidParams, Message: err.Error()}
+		}
+		return s.readResource(&params)
+	}
+	return nil, &Error{Code: codeMethodNotFound, Message: fmt.Sprintf("method not found: %s", request.Method)}
+}
+
+// initialize negotiates the protocol version and sets the session GitHub
+// token, read from the _meta params when not already set by the transport.
+func (s *Server) initialize(session *Session, raw json.RawMessage) (interface{}, *Error) {
+	var params initializeParams
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return nil, &Error{Code: codeInvalidParams, Message: err.Error()}
+		}
+	}
+	if token, ok := params.Meta[TokenMetaKey].(string); ok && token != "" {
+		session.setToken(token)
+	}
+	if session.Token() == "" {
+		if s.RequireSessionToken || s.token == "" {
+			return nil, &Error{Code: codeInvalidParams, Message: "a GitHub token is required, set it in the Authorization header or in _meta." + TokenMetaKey}
+		}
+		session.setToken(s.token)
+	}
+
+	version := ProtocolVersion
+	if supportedVersions[params.ProtocolVersion] {
+		version = params.ProtocolVersion
+	}
+	session.initialize(params.ClientInfo)
+
+	return map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities": map[string]interface{}{
+			"tools":     map[string]bool{"listChanged": false},
+			"prompts":   map[string]bool{"listChanged": false},
+			"resources": map[string]bool{"listChanged": false},
+		},
+		"serverInfo": map[string]string{"name": s.name, "version": s.version},
+	}, nil
+}
+
+func (s *Server) listTools() []*Tool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	tools := make([]*Tool, 0, len(s.tools))
+	for _, tool := range s.tools {
+		tools = append(tools, tool)
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+func (s *Server) callTool(ctx context.Context, session *Session, params *callParams) (interface{}, *Error) {
+	s.mu.RLock()
+	tool, ok := s.tools[params.Name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, &Error{Code: codeInvalidParams, Message: fmt.Sprintf("unknown tool: %s", params.Name)}
+	}
+
+	text, err := tool.Handler(ctx, session, params.Arguments)
+	if err != nil {
+		return &ToolResult{Content: []Content{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+	return &ToolResult{Content: []Content{{Type: "text", Text: text}}}, nil
+}
+
+func (s *Server) listPrompts() []*Prompt {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	prompts := make([]*Prompt, 0, len(s.prompts))
+	for _, prompt := range s.prompts {
+		prompts = append(prompts, prompt)
+	}
+	sort.Slice(prompts, func(i, j int) bool { return prompts[i].Name < prompts[j].Name })
+	return prompts
+}
+
+func (s *Server) getPrompt(params *getPromptParams) (interface{}, *Error) {
+	s.mu.RLock()
+	prompt, ok := s.prompts[params.Name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, &Error{Code: codeInvalidParams, Message: fmt.Sprintf("unknown prompt: %s", params.Name)}
+	}
+	for _, argument := range prompt.Arguments {
+		if argument.Required && params.Arguments[argument.Name] == "" {
+			return nil, &Error{Code: codeInvalidParams, Message: fmt.Sprintf("missing required argument: %s", argument.Name)}
+		}
+	}
+	return &PromptResult{
+		Description: prompt.Description,
+		Messages:    []PromptMessage{{Role: "user", Content: Content{Type: "text", Text: prompt.Handler(params.Arguments)}}},
+	}, nil
+}
+
+func (s *Server) listResources() []*Resource {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	resources := make([]*Resource, 0, len(s.resources))
+	for _, resource := range s.resources {
+		resources = append(resources, resource)
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i].URI < resources[j].URI })
+	return resources
+}
+
+func (s *Server) readResource(params *readResourceParams) (interface{}, *Error) {
+	s.mu.RLock()
+	resource, ok := s.resources[params.URI]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, &Error{Code: codeInvalidParams, Message: fmt.Sprintf("unknown resource: %s", params.URI)}
+	}
+	text, err := resource.Handler()
+	if err != nil {
+		return nil, &Error{Code: codeInternalError, Message: err.Error()}
+	}
+	return map[string]interface{}{
+		"contents": []ResourceContents{{URI: resource.URI, MimeType: resource.MimeType, Text: text}},
+	}, nil
+}
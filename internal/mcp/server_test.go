@@ -0,0 +1,467 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/issue"
+	"sigs.k8s.io/signalhound/internal/state"
+	"sigs.k8s.io/signalhound/internal/store"
+	"sigs.k8s.io/signalhound/internal/testgrid"
+	"sigs.k8s.io/signalhound/internal/testing/fake"
+	"sigs.k8s.io/signalhound/internal/triage"
+)
+
+// existingIssues are the issues found by find_existing_issues.
+var existingIssues = map[string][]github.Issue{
+	"[sig-storage] CSI volumes should mount a volume": {
+		{Number: 129000, Title: "[Failing Test] CSI volumes should mount", URL: "https://github.com/kubernetes/kubernetes/issues/129000"},
+		{ID: "PVTI_1", Title: "[Flaky Test] CSI volumes", Draft: true,
+			TestName: "[sig-storage] CSI volumes should mount a volume", Board: "sig-release-master-blocking", Status: "Drafting"},
+	},
+}
+
+// setProjectManager replaces the session GitHub client by a fake per token
+// and returns the fakes.
+func setProjectManager(t *testing.T) map[string]*fake.ProjectManager {
+	managers := map[string]*fake.ProjectManager{}
+	previous := newProjectManager
+	newProjectManager = func(ctx context.Context, token string) github.ProjectManagerInterface {
+		if managers[token] == nil {
+			managers[token] = &fake.ProjectManager{Existing: existingIssues}
+		}
+		return managers[token]
+	}
+	t.Cleanup(func() { newProjectManager = previous })
+	return managers
+}
+
+// draftTitles returns the titles of the drafts created per token.
+func draftTitles(managers map[string]*fake.ProjectManager) map[string][]string {
+	titles := map[string][]string{}
+	for token, manager := range managers {
+		for _, draft := range manager.Drafts {
+			titles[token] = append(titles[token], draft.Title)
+		}
+	}
+	return titles
+}
+
+func newTestServer(token string) *Server {
+	server := NewServer("signalhound", "test", token)
+	RegisterTools(server)
+	RegisterPrompts(server)
+	RegisterResources(server)
+	return server
+}
+
+// post sends a JSON-RPC message and returns the response and session ID.
+func post(t *testing.T, url string, headers map[string]string, body string) (*http.Response, *Response) {
+	request, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	assert.NoError(t, err)
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	response, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	defer response.Body.Close() // nolint
+
+	var rpc Response
+	if response.Header.Get("Content-Type") == "application/json" {
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&rpc))
+	}
+	return response, &rpc
+}
+
+const (
+	initializeMessage = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"test"}}}`
+	callMessage       = `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"create_draft_issue","arguments":{"title":"%s","body":"body"}}}`
+)
+
+func TestHTTPSessionTokens(t *testing.T) {
+	managers := setProjectManager(t)
+	ts := httptest.NewServer(newTestServer("server-token").HTTPHandler())
+	defer ts.Close()
+
+	sessions := map[string]map[string]string{
+		"alice":  {"Authorization": "Bearer alice-token"},
+		"bob":    {TokenHeader: "bob-token"},
+		"shared": {},
+	}
+	for user, headers := range sessions {
+		response, rpc := post(t, ts.URL, headers, initializeMessage)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Nil(t, rpc.Error)
+		sessionID := response.Header.Get(SessionHeader)
+		assert.NotEmpty(t, sessionID)
+
+		response, rpc = post(t, ts.URL, map[string]string{SessionHeader: sessionID}, strings.Replace(callMessage, "%s", user, 1))
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Nil(t, rpc.Error)
+	}
+
+	assert.Equal(t, map[string][]string{
+		"alice-token":  {"alice"},
+		"bob-token":    {"bob"},
+		"server-token": {"shared"},
+	}, draftTitles(managers))
+}
+
+func TestHTTPUnknownSession(t *testing.T) {
+	ts := httptest.NewServer(newTestServer("server-token").HTTPHandler())
+	defer ts.Close()
+
+	response, _ := post(t, ts.URL, map[string]string{SessionHeader: "unknown"}, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	response, _ = post(t, ts.URL, map[string]string{}, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+func TestRequireSessionToken(t *testing.T) {
+	server := newTestServer("server-token")
+	server.RequireSessionToken = true
+	ts := httptest.NewServer(server.HTTPHandler())
+	defer ts.Close()
+
+	response, rpc := post(t, ts.URL, map[string]string{}, initializeMessage)
+	assert.Empty(t, response.Header.Get(SessionHeader))
+	if assert.NotNil(t, rpc.Error) {
+		assert.Equal(t, codeInvalidParams, rpc.Error.Code)
+	}
+
+	response, rpc = post(t, ts.URL, map[string]string{"Authorization": "Bearer alice-token"}, initializeMessage)
+	assert.NotEmpty(t, response.Header.Get(SessionHeader))
+	assert.Nil(t, rpc.Error)
+}
+
+func TestServeStdio(t *testing.T) {
+	managers := setProjectManager(t)
+	in := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"initialize","params":{"protocolVersion":"2024-11-05","_meta":{"signalhound/githubToken":"meta-token"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/list"}`,
+		strings.Replace(strings.Replace(callMessage, "%s", "stdio", 1), `"id":2`, `"id":4`, 1),
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"create_draft_issue","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"unknown"}`,
+		`not json`,
+	}, "\n")
+	var out bytes.Buffer
+	assert.NoError(t, newTestServer("").ServeStdio(context.Background(), strings.NewReader(in), &out))
+
+	var responses []map[string]interface{}
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var response map[string]interface{}
+		assert.NoError(t, decoder.Decode(&response))
+		responses = append(responses, response)
+	}
+	assert.Len(t, responses, 7)
+
+	// requests before initialize are rejected
+	assert.Contains(t, responses[0], "error")
+	// the client protocol version is echoed back
+	assert.Equal(t, "2024-11-05", responses[1]["result"].(map[string]interface{})["protocolVersion"])
+	tools := responses[2]["result"].(map[string]interface{})["tools"].([]interface{})
+	assert.Equal(t, "acknowledge_issue_link", tools[0].(map[string]interface{})["name"])
+	assert.Equal(t, "analyze_test_history", tools[1].(map[string]interface{})["name"])
+	assert.Equal(t, "create_draft_issue", tools[2].(map[string]interface{})["name"])
+	assert.NotContains(t, responses[3], "error")
+	assert.Equal(t, true, responses[4]["result"].(map[string]interface{})["isError"])
+	assert.Equal(t, float64(codeMethodNotFound), responses[5]["error"].(map[string]interface{})["code"])
+	assert.Equal(t, float64(codeParseError), responses[6]["error"].(map[string]interface{})["code"])
+
+	assert.Equal(t, map[string][]string{"meta-token": {"stdio"}}, draftTitles(managers))
+
+	// the session ends on shutdown while the client is idle
+	ctx, cancel := context.WithCancel(context.Background())
+	idle, _ := io.Pipe()
+	done := make(chan error)
+	go func() { done <- newTestServer("").ServeStdio(ctx, idle, &out) }()
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestPrompts(t *testing.T) {
+	server := newTestServer("server-token")
+	session := NewSession("")
+	initialize := &Request{JSONRPC: "2.0", ID: json.RawMessage("1"), Method: "initialize", Params: json.RawMessage(`{}`)}
+	assert.Nil(t, server.Handle(context.Background(), session, initialize).Error)
+
+	response := server.Handle(context.Background(), session, &Request{JSONRPC: "2.0", ID: json.RawMessage("2"), Method: "prompts/list"})
+	prompts := response.Result.(map[string]interface{})["prompts"].([]*Prompt)
+	if assert.Len(t, prompts, 4) {
+		assert.Equal(t, "analyze_board", prompts[0].Name)
+		assert.Equal(t, "analyze_failure", prompts[1].Name)
+		assert.Equal(t, "draft_issue", prompts[2].Name)
+		assert.Equal(t, "summarize_boards", prompts[3].Name)
+	}
+
+	response = server.Handle(context.Background(), session, &Request{JSONRPC: "2.0", ID: json.RawMessage("3"), Method: "prompts/get",
+		Params: json.RawMessage(`{"name":"draft_issue","arguments":{"board":"sig-release-master-blocking#kind-master","test":"[sig-node] Pods","state":"FLAKY"}}`)})
+	if assert.Nil(t, response.Error) {
+		text := response.Result.(*PromptResult).Messages[0].Content.Text
+		// the handbook conventions come before the test
+		assert.Contains(t, text, "## Reporting an issue\n\n- Title the issue `[Failing Test] <test name>` or `[Flaky Test] <test name>`")
+		assert.NotContains(t, text, "## Triaging failures and flakes")
+		assert.Contains(t, text, "Board: sig-release-master-blocking#kind-master\nTest: [sig-node] Pods\nState: FLAKY\n")
+		assert.NotContains(t, text, "Error:")
+	}
+
+	response = server.Handle(context.Background(), session, &Request{JSONRPC: "2.0", ID: json.RawMessage("4"), Method: "prompts/get",
+		Params: json.RawMessage(`{"name":"analyze_failure","arguments":{"board":"sig-release-master-blocking#kind-master"}}`)})
+	if assert.NotNil(t, response.Error) {
+		assert.Equal(t, "missing required argument: test", response.Error.Message)
+	}
+
+	response = server.Handle(context.Background(), session, &Request{JSONRPC: "2.0", ID: json.RawMessage("5"), Method: "prompts/get",
+		Params: json.RawMessage(`{"name":"analyze_board","arguments":{"board":"sig-release-master-blocking",` +
+			`"tests":"kind-master: [sig-node] Pods (FLAKY)\ngce-cos-master-default: [sig-network] DNS (FAILING)\n"}}`)})
+	if assert.Nil(t, response.Error) {
+		text := response.Result.(*PromptResult).Messages[0].Content.Text
+		assert.Contains(t, text, "## Triaging failures and flakes")
+		assert.Contains(t, text, "Board: sig-release-master-blocking\nTests:\nkind-master: [sig-node] Pods (FLAKY)\n"+
+			"gce-cos-master-default: [sig-network] DNS (FAILING)\n\nAnalyze the tests of this board only")
+	}
+
+	response = server.Handle(context.Background(), session, &Request{JSONRPC: "2.0", ID: json.RawMessage("6"), Method: "prompts/get",
+		Params: json.RawMessage(`{"name":"summarize_boards","arguments":{"analyses":"sig-release-master-blocking: DNS is failing"}}`)})
+	if assert.Nil(t, response.Error) {
+		text := response.Result.(*PromptResult).Messages[0].Content.Text
+		assert.Contains(t, text, "The boards were analyzed one by one:\n\nsig-release-master-blocking: DNS is failing\n\nCombine")
+	}
+
+	response = server.Handle(context.Background(), session, &Request{JSONRPC: "2.0", ID: json.RawMessage("7"), Method: "prompts/get",
+		Params: json.RawMessage(`{"name":"unknown"}`)})
+	assert.Equal(t, codeInvalidParams, response.Error.Code)
+}
+
+func TestFindExistingIssuesTool(t *testing.T) {
+	setProjectManager(t)
+	server := newTestServer("server-token")
+	session := NewSession("")
+	initialize := &Request{JSONRPC: "2.0", ID: json.RawMessage("1"), Method: "initialize", Params: json.RawMessage(`{}`)}
+	assert.Nil(t, server.Handle(context.Background(), session, initialize).Error)
+
+	call := func(test string) *ToolResult {
+		response := server.Handle(context.Background(), session, &Request{JSONRPC: "2.0", ID: json.RawMessage("2"), Method: "tools/call",
+			Params: json.RawMessage(`{"name":"find_existing_issues","arguments":{"test":"` + test + `"}}`)})
+		assert.Nil(t, response.Error)
+		return response.Result.(*ToolResult)
+	}
+	assert.Equal(t, "- #129000: [Failing Test] CSI volumes should mount (https://github.com/kubernetes/kubernetes/issues/129000)\n"+
+		"- draft: [Flaky Test] CSI volumes; test: [sig-storage] CSI volumes should mount a volume; board: sig-release-master-blocking; status: Drafting", call("[sig-storage] CSI volumes should mount a volume").Content[0].Text)
+	assert.Equal(t, "no existing issue found", call("[sig-node] Pods").Content[0].Text)
+	assert.True(t, call("").IsError)
+}
+
+func TestCreateIssueTool(t *testing.T) {
+	manager := &fake.ProjectManager{}
+	previous := newProjectManager
+	newProjectManager = func(ctx context.Context, token string) github.ProjectManagerInterface { return manager }
+	t.Cleanup(func() { newProjectManager = previous })
+	call := func(arguments string) (string, error) {
+		return createIssue(context.Background(), NewSession("token"), json.RawMessage(arguments))
+	}
+
+	url, err := call(`{"test":"[sig-node] Pods should run","board":"sig-release-1.32-blocking#kind","type":"failing","body":"body"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.com/kubernetes/kubernetes/issues/1", url)
+	assert.Equal(t, "[Failing Test] [sig-node] Pods should run", manager.Issues[0].Title)
+	assert.Equal(t, &github.IssueOptions{Owner: "kubernetes", Repo: "kubernetes",
+		Labels: []string{"kind/failing-test", "sig/node"}, Milestone: "v1.32"}, manager.Issues[0].Options)
+
+	url, err = call(`{"test":"ci-kubernetes-e2e-gci-gce.Up","board":"sig-release-master-blocking#gce","type":"flaky","body":"body","sig":"testing"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.com/kubernetes/test-infra/issues/2", url)
+	assert.Equal(t, "[Flaking Test] ci-kubernetes-e2e-gci-gce.Up", manager.Issues[1].Title)
+	assert.Equal(t, []string{"kind/flake", "sig/testing"}, manager.Issues[1].Options.Labels)
+
+	manager.Open = map[string]*github.Issue{"[sig-node] Pods should run": {Number: 1, URL: "https://github.com/kubernetes/kubernetes/issues/1"}}
+	result, err := call(`{"test":"[sig-node] Pods should run","board":"sig-release-master-blocking#kind","type":"failing","body":"body"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "open issue #1 already exists: https://github.com/kubernetes/kubernetes/issues/1", result)
+
+	_, err = call(`{"test":"[sig-node] Pods should run","board":"sig-release-master-blocking#kind","type":"broken","body":"body"}`)
+	assert.Error(t, err)
+	_, err = call(`{"test":"[sig-node] Pods should run","type":"failing"}`)
+	assert.Error(t, err)
+}
+
+func TestCreateDraftIssueDryRun(t *testing.T) {
+	managers := setProjectManager(t)
+	github.DryRun = true
+	t.Cleanup(func() { github.DryRun = false })
+
+	result, err := createDraftIssue(context.Background(), NewSession("token"), json.RawMessage(`{"title":"flake","body":"body"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `dry run, draft issue "flake" not created`, result)
+	assert.Len(t, managers["token"].Drafts, 1)
+}
+
+func TestAnalyzeTestHistoryTool(t *testing.T) {
+	triageServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"clustered": [{"id": "a1", "text": "timed out", "tests": [
+			{"name": "[sig-node] Pods should run", "jobs": [{"name": "ci-kubernetes-kind-e2e", "builds": [7, 8]}]}]}]}`))
+	}))
+	defer triageServer.Close()
+	previousHistory, previousTriage := History, Triage
+	History, Triage = store.NewStore(t.TempDir()+"/history.db"), triage.NewClient(triageServer.URL)
+	t.Cleanup(func() { History, Triage = previousHistory, previousTriage })
+
+	now := time.Now().Truncate(time.Millisecond)
+	runs := []v1alpha1.RunResult{
+		{Timestamp: now.Add(-time.Hour).UnixMilli(), Failed: true},
+		{Timestamp: now.Add(-2 * time.Hour).UnixMilli()},
+		{Timestamp: now.Add(-3 * time.Hour).UnixMilli(), Failed: true},
+	}
+	assert.NoError(t, History.Record([]*v1alpha1.DashboardTab{{
+		BoardHash: "sig-release-master-blocking#kind-master", TabState: v1alpha1.FLAKY_STATUS,
+		TestRuns: []v1alpha1.TestResult{{TestName: "[sig-node] Pods should run", Runs: runs}},
+	}}, now))
+
+	text, err := analyzeTestHistory(context.Background(), NewSession(""), json.RawMessage(`{"test":"[sig-node] Pods should run","days":1}`))
+	assert.NoError(t, err)
+	var result testHistory
+	assert.NoError(t, json.Unmarshal([]byte(text), &result))
+	assert.Equal(t, 2, result.Failures)
+	assert.Equal(t, 3, result.Runs)
+	assert.True(t, now.Add(-3*time.Hour).Equal(*result.FirstFailure))
+	assert.True(t, now.Add(-time.Hour).Equal(*result.LastFailure))
+	assert.Equal(t, []boardHistory{{Board: "sig-release-master-blocking#kind-master", Failures: 2, Runs: 3}}, result.Boards)
+	assert.Equal(t, []triage.Cluster{{ID: "a1", Text: "timed out", Failures: 2,
+		Jobs: []triage.Job{{Name: "ci-kubernetes-kind-e2e", Failures: 2}}}}, result.Clusters)
+	assert.Empty(t, result.Errors)
+
+	History = nil
+	text, err = analyzeTestHistory(context.Background(), NewSession(""), json.RawMessage(`{"test":"[sig-cli] Kubectl"}`))
+	assert.NoError(t, err)
+	assert.Contains(t, text, "the history is disabled on the server")
+	_, err = analyzeTestHistory(context.Background(), NewSession(""), json.RawMessage(`{}`))
+	assert.Error(t, err)
+}
+
+func TestResources(t *testing.T) {
+	server := newTestServer("server-token")
+	session := NewSession("")
+	initialize := &Request{JSONRPC: "2.0", ID: json.RawMessage("1"), Method: "initialize", Params: json.RawMessage(`{}`)}
+	assert.Nil(t, server.Handle(context.Background(), session, initialize).Error)
+
+	response := server.Handle(context.Background(), session, &Request{JSONRPC: "2.0", ID: json.RawMessage("2"), Method: "resources/list"})
+	resources := response.Result.(map[string]interface{})["resources"].([]*Resource)
+	assert.Len(t, resources, len(issue.TemplateFiles()))
+	assert.Equal(t, "signalhound://templates/branches.tmpl", resources[0].URI)
+
+	response = server.Handle(context.Background(), session, &Request{JSONRPC: "2.0", ID: json.RawMessage("3"), Method: "resources/read",
+		Params: json.RawMessage(`{"uri":"signalhound://templates/failure.tmpl"}`)})
+	if assert.Nil(t, response.Error) {
+		contents := response.Result.(map[string]interface{})["contents"].([]ResourceContents)
+		assert.Contains(t, contents[0].Text, "### Which jobs are failing?")
+	}
+	response = server.Handle(context.Background(), session, &Request{JSONRPC: "2.0", ID: json.RawMessage("4"), Method: "resources/read",
+		Params: json.RawMessage(`{"uri":"signalhound://templates/missing.tmpl"}`)})
+	assert.Equal(t, codeInvalidParams, response.Error.Code)
+}
+
+func TestRenderIssueTool(t *testing.T) {
+	call := func(arguments string) (*renderedIssue, error) {
+		text, err := renderIssue(context.Background(), NewSession(""), json.RawMessage(arguments))
+		if err != nil {
+			return nil, err
+		}
+		var rendered renderedIssue
+		assert.NoError(t, json.Unmarshal([]byte(text), &rendered))
+		return &rendered, nil
+	}
+
+	rendered, err := call(`{"board":"sig-release-master-blocking#kind-master","test":"[sig-node] Pods should run","state":"FAILING","error":"timed out"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "[Failing Test] [sig-node] Pods should run", rendered.Title)
+	assert.Contains(t, rendered.Body, "### Which jobs are failing?")
+	assert.Contains(t, rendered.Body, "timed out")
+
+	rendered, err = call(`{"board":"sig-release-master-blocking#kind-master","test":"[sig-node] Pods should run","state":"FLAKY",` +
+		`"template":"---\nlabels: [kind/flake, sig/{{ .Sig }}]\n---\n{{ .TestName }} flakes on {{ .TabName }}"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "[Flaking Test] [sig-node] Pods should run", rendered.Title)
+	assert.Equal(t, []string{"kind/flake", "sig/node"}, rendered.FrontMatter.Labels)
+	assert.Equal(t, "[sig-node] Pods should run flakes on kind-master", rendered.Body)
+
+	_, err = call(`{"board":"sig-release-master-blocking#kind-master","test":"[sig-node] Pods should run","state":"PASSING"}`)
+	assert.Error(t, err)
+	_, err = call(`{"board":"sig-release-master-blocking#kind-master","test":"[sig-node] Pods should run","state":"FLAKY","template":"{{ .Missing }}"}`)
+	assert.Error(t, err)
+}
+
+func TestGetFailingTestsTool(t *testing.T) {
+	grid := &fake.TestGrid{
+		Summaries: map[string][]v1alpha1.DashboardSummary{"sig-release-master-blocking": {
+			{DashboardName: "sig-release-master-blocking", OverallState: v1alpha1.FAILING_STATUS, DashboardTab: &v1alpha1.DashboardTab{TabName: "kind"}},
+			{DashboardName: "sig-release-master-blocking", OverallState: v1alpha1.PASSING_STATUS, DashboardTab: &v1alpha1.DashboardTab{TabName: "gce"}},
+			{DashboardName: "sig-release-master-blocking", OverallState: v1alpha1.FLAKY_STATUS, DashboardTab: &v1alpha1.DashboardTab{TabName: "arm64"}},
+		}},
+		Tabs: map[string]*v1alpha1.DashboardTab{"kind": {TabName: "kind", TabState: v1alpha1.FAILING_STATUS,
+			TestRuns: []v1alpha1.TestResult{{TestName: "[sig-node] Pods should run"}}}},
+	}
+	previous := newTestGrid
+	newTestGrid = func(url string) testgrid.TestGridInterface { return grid }
+	t.Cleanup(func() { newTestGrid = previous })
+
+	result, err := getFailingTests(context.Background(), NewSession(""), json.RawMessage(`{"dashboards":["sig-release-master-blocking"]}`))
+	assert.NoError(t, err)
+	var tests failingTests
+	assert.NoError(t, json.Unmarshal([]byte(result), &tests))
+	// the passing tab is filtered out and the flaky tab has no test
+	if assert.Len(t, tests.Tabs, 1) {
+		assert.Equal(t, "kind", tests.Tabs[0].TabName)
+		assert.Equal(t, "[sig-node] Pods should run", tests.Tabs[0].TestRuns[0].TestName)
+	}
+
+	_, err = getFailingTests(context.Background(), NewSession(""), json.RawMessage(`{"dashboards":["sig-release-unknown"]}`))
+	assert.ErrorContains(t, err, "sig-release-unknown")
+	_, err = getFailingTests(context.Background(), NewSession(""), json.RawMessage(`{"min_failure":-1}`))
+	assert.Error(t, err)
+}
+
+func TestAcknowledgeIssueLinkTool(t *testing.T) {
+	managers := setProjectManager(t)
+	previous := State
+	State = state.NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+	t.Cleanup(func() { State = previous })
+	session := NewSession("token")
+
+	result, err := acknowledgeIssueLink(context.Background(), session, json.RawMessage(
+		`{"test":"[sig-node] Pods should run","url":"https://github.com/kubernetes/kubernetes/issues/129500","note":"kubelet restarts","author":"alice"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "issue #129500 linked to [sig-node] Pods should run", result)
+	_, err = acknowledgeIssueLink(context.Background(), session, json.RawMessage(`{"test":"[sig-node] Pods should run","url":"https://example.com"}`))
+	assert.Error(t, err)
+
+	// the dedup checks find the linked issue
+	result, err = findExistingIssues(context.Background(), session, json.RawMessage(`{"test":"[sig-node] Pods should run"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "- #129500: kubelet restarts (https://github.com/kubernetes/kubernetes/issues/129500)", result)
+	result, err = createIssue(context.Background(), session, json.RawMessage(
+		`{"test":"[sig-node] Pods should run","board":"sig-release-master-blocking#kind","type":"failing","body":"body"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "issue #129500 is linked to the test: https://github.com/kubernetes/kubernetes/issues/129500", result)
+	assert.Empty(t, managers["token"].Issues)
+
+	State = nil
+	_, err = acknowledgeIssueLink(context.Background(), session, json.RawMessage(
+		`{"test":"[sig-node] Pods should run","url":"https://github.com/kubernetes/kubernetes/issues/129500"}`))
+	assert.ErrorContains(t, err, "disabled")
+}
@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+)
+
+// Session is the state of a single MCP client connection. Each session
+// carries its own GitHub token, so the GitHub mutations of a shared server
+// are attributed to the user behind the client.
+type Session struct {
+	ID string
+
+	mu          sync.RWMutex
+	token       string
+	clientInfo  map[string]interface{}
+	initialized bool
+}
+
+// NewSession returns a session with a random ID, token is the GitHub token
+// supplied by the transport, e.g. from an HTTP header, and may be empty.
+func NewSession(token string) *Session {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return &Session{ID: hex.EncodeToString(id), token: token}
+}
+
+// Token returns the GitHub token of the session.
+func (s *Session) Token() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.token
+}
+
+// ClientName returns the name reported by the client on initialize.
+func (s *Session) ClientName() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	name, _ := s.clientInfo["name"].(string)
+	return name
+}
+
+// Initialized returns true once the initialize handshake is done.
+func (s *Session) Initialized() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.initialized
+}
+
+func (s *Session) setToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+}
+
+func (s *Session) initialize(clientInfo map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clientInfo = clientInfo
+	s.initialized = true
+}
+
+// sessionStore keeps the sessions of the HTTP transport by ID.
+type sessionStore struct {
+	mu       sync.RWMutex
+	sessions map[string]*Session
+}
+
+func newSessionStore() *sessionStore {
+	return &sessionStore{sessions: map[string]*Session{}}
+}
+
+func (s *sessionStore) add(session *Session) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[session.ID] = session
+}
+
+func (s *sessionStore) get(id string) (*Session, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	session, ok := s.sessions[id]
+	return session, ok
+}
+
+func (s *sessionStore) delete(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+}
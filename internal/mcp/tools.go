@@ -0,0 +1,545 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/issue"
+	"sigs.k8s.io/signalhound/internal/release"
+	"sigs.k8s.io/signalhound/internal/sig"
+	"sigs.k8s.io/signalhound/internal/state"
+	"sigs.k8s.io/signalhound/internal/store"
+	"sigs.k8s.io/signalhound/internal/testgrid"
+	"sigs.k8s.io/signalhound/internal/triage"
+)
+
+// newProjectManager builds the GitHub client of a session, replaced in tests.
+var newProjectManager = github.NewProjectManager
+
+// newTestGrid builds the TestGrid client of get_failing_tests, replaced in
+// tests.
+var newTestGrid = func(url string) testgrid.TestGridInterface { return testgrid.NewTestGrid(url) }
+
+var (
+	// TestGridURL is the TestGrid endpoint of get_failing_tests.
+	TestGridURL = testgrid.URL
+
+	// Dashboards are the dashboards get_failing_tests checks when the call
+	// names none.
+	Dashboards = []string{"sig-release-master-blocking", "sig-release-master-informing"}
+
+	// History is the test results history analyze_test_history reads, nil
+	// when disabled.
+	History *store.Store
+
+	// Triage reads the failure clusters of analyze_test_history.
+	Triage = triage.NewClient(triage.URL)
+
+	// State keeps the issues linked by acknowledge_issue_link, nil when
+	// disabled.
+	State state.Store
+)
+
+// RegisterTools adds the signalhound tools to the server.
+func RegisterTools(s *Server) {
+	s.AddTool(&Tool{
+		Name:        "create_draft_issue",
+		Description: "Create a draft issue in the CI Signal project board, authored by the GitHub token of the session.",
+		InputSchema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "title": {"type": "string", "description": "issue title"},
+    "body": {"type": "string", "description": "issue body in Markdown"},
+    "board": {"type": "string", "description": "TestGrid board#tab the issue belongs to, used to pick the K8s release field"}
+  },
+  "required": ["title", "body"]
+}`),
+		Handler: createDraftIssue,
+	})
+	s.AddTool(&Tool{
+		Name:        "create_issue",
+		Description: "File the GitHub issue of a failing or flaky test in the repository of the test, labeled with its kind and SIG, and return the issue URL. An open issue of the test is returned instead of filing a duplicate.",
+		InputSchema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "test": {"type": "string", "description": "full test name as shown on TestGrid"},
+    "board": {"type": "string", "description": "TestGrid board#tab of the test, e.g. sig-release-master-blocking#gce-cos-master-default"},
+    "type": {"type": "string", "enum": ["failing", "flaky"], "description": "failure type of the test"},
+    "body": {"type": "string", "description": "issue body in Markdown"},
+    "sig": {"type": "string", "description": "SIG owning the test, e.g. node, read from the [sig-*] tag of the test name when empty"}
+  },
+  "required": ["test", "board", "type", "body"]
+}`),
+		Handler: createIssue,
+	})
+	s.AddTool(&Tool{
+		Name:        "find_existing_issues",
+		Description: "Find the open issues of kubernetes/kubernetes and the draft issues of the CI Signal project board similar to a test, to check before filing a new one. Each issue comes with the test and board read from its body and its board status when known.",
+		InputSchema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "test": {"type": "string", "description": "full test name"}
+  },
+  "required": ["test"]
+}`),
+		Handler: findExistingIssues,
+	})
+	s.AddTool(&Tool{
+		Name:        "acknowledge_issue_link",
+		Description: "Record an existing GitHub issue as tracking a test, e.g. an issue the searches miss, so find_existing_issues, create_issue and the issue audit treat the test as tracked.",
+		InputSchema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "test": {"type": "string", "description": "full test name as shown on TestGrid"},
+    "url": {"type": "string", "description": "URL of the issue, e.g. https://github.com/kubernetes/kubernetes/issues/129000"},
+    "note": {"type": "string", "description": "why the issue tracks the test, e.g. its title"},
+    "author": {"type": "string", "description": "who linked the issue"}
+  },
+  "required": ["test", "url"]
+}`),
+		Handler: acknowledgeIssueLink,
+	})
+	s.AddTool(&Tool{
+		Name:        "get_failing_tests",
+		Description: "Fetch the failing and flaky tabs of TestGrid dashboards and return their failing and flaky tests as JSON, with the error message, the Prow and triage links and the failure counts of each test.",
+		InputSchema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "dashboards": {"type": "array", "items": {"type": "string"}, "description": "TestGrid dashboards to check, e.g. sig-release-1.35-blocking, the server dashboards when empty"},
+    "min_failure": {"type": "integer", "minimum": 0, "description": "minimum number of failures of a test in a failing tab, 0 disables it"},
+    "min_flake": {"type": "integer", "minimum": 0, "description": "minimum number of flakes of a test in a flaky tab, 0 disables it"}
+  }
+}`),
+		Handler: getFailingTests,
+	})
+	s.AddTool(&Tool{
+		Name:        "analyze_test_history",
+		Description: "Return the failure history of a test as JSON: the first and last failures, the red runs per day and per board recorded by signalhound, and the Triage clusters of its failures with the jobs they hit, to give the issue body historical context.",
+		InputSchema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "test": {"type": "string", "description": "full test name as shown on TestGrid"},
+    "days": {"type": "integer", "minimum": 1, "description": "days of recorded history, 14 by default"}
+  },
+  "required": ["test"]
+}`),
+		Handler: analyzeTestHistory,
+	})
+	s.AddTool(&Tool{
+		Name:        "render_issue",
+		Description: "Render the GitHub issue of a failing or flaky test with the signalhound issue templates, or with a customized copy of a template resource, and return its title, front matter and body as JSON.",
+		InputSchema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "board": {"type": "string", "description": "TestGrid board#tab of the test, e.g. sig-release-master-blocking#gce-cos-master-default"},
+    "test": {"type": "string", "description": "full test name as shown on TestGrid"},
+    "state": {"type": "string", "enum": ["FAILING", "FLAKY"], "description": "TestGrid state of the tab, picks the failure or flake template"},
+    "error": {"type": "string", "description": "error message of the latest failure"},
+    "template": {"type": "string", "description": "template source replacing the failure or flake template, e.g. an edited signalhound://templates/flake.tmpl"}
+  },
+  "required": ["board", "test", "state"]
+}`),
+		Handler: renderIssue,
+	})
+}
+
+type createDraftIssueArgs struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	Board string `json:"board"`
+}
+
+// createDraftIssue creates the draft issue with the session GitHub client, so
+// the draft is attributed to the user behind the session.
+func createDraftIssue(ctx context.Context, session *Session, arguments json.RawMessage) (string, error) {
+	var args createDraftIssueArgs
+	if err := json.Unmarshal(arguments, &args); err != nil {
+		return "", fmt.Errorf("error parsing arguments: %v", err)
+	}
+	if args.Title == "" || args.Body == "" {
+		return "", errors.New("title and body are required")
+	}
+
+	manager := newProjectManager(ctx, session.Token())
+	if err := manager.CreateDraftIssue(args.Title, args.Body, args.Board); err != nil {
+		return "", fmt.Errorf("error creating draft issue: %v", err)
+	}
+	if github.DryRun {
+		return fmt.Sprintf("dry run, draft issue %q not created", args.Title), nil
+	}
+	return fmt.Sprintf("draft issue %q created", args.Title), nil
+}
+
+// issueKinds are the kind labels of the failure types of create_issue.
+var issueKinds = map[string]string{
+	"failing": "kind/failing-test",
+	"flaky":   "kind/flake",
+}
+
+type createIssueArgs struct {
+	Test  string `json:"test"`
+	Board string `json:"board"`
+	Type  string `json:"type"`
+	Body  string `json:"body"`
+	Sig   string `json:"sig"`
+}
+
+// createIssue files the issue of the test in its repository, see
+// issue.Repository, with the kind and SIG labels and the milestone of a
+// release branch board, using the session GitHub client.
+func createIssue(ctx context.Context, session *Session, arguments json.RawMessage) (string, error) {
+	var args createIssueArgs
+	if err := json.Unmarshal(arguments, &args); err != nil {
+		return "", fmt.Errorf("error parsing arguments: %v", err)
+	}
+	if args.Test == "" || args.Board == "" || args.Body == "" {
+		return "", errors.New("test, board and body are required")
+	}
+	kind, found := issueKinds[args.Type]
+	if !found {
+		return "", fmt.Errorf("unknown type %q, use failing or flaky", args.Type)
+	}
+	testSig := args.Sig
+	if testSig == "" {
+		testSig = sig.FromTestName(args.Test)
+	}
+
+	frontMatter := &issue.FrontMatter{Labels: []string{kind}, Milestone: release.Milestone(args.Board)}
+	options := frontMatter.WithRepository(issue.Repository(args.Board, args.Test)).WithSIGLabel(testSig).Options()
+	link, err := issueLink(args.Test)
+	if err != nil {
+		return "", err
+	}
+	if link != nil {
+		return fmt.Sprintf("issue #%d is linked to the test: %s", link.Number, link.URL), nil
+	}
+	manager := newProjectManager(ctx, session.Token())
+	open, err := manager.FindOpenIssue(options.Owner, options.Repo, args.Test)
+	if err != nil {
+		return "", fmt.Errorf("error finding open issue: %v", err)
+	}
+	if open != nil {
+		return fmt.Sprintf("open issue #%d already exists: %s", open.Number, open.URL), nil
+	}
+
+	state := v1alpha1.FLAKY_STATUS
+	if args.Type == "failing" {
+		state = v1alpha1.FAILING_STATUS
+	}
+	_, prefixTitle := issue.PickTemplate(state)
+	created, err := manager.CreateIssue(issue.Title(prefixTitle, args.Test), args.Body, args.Board, options)
+	if err != nil {
+		return "", fmt.Errorf("error creating issue: %v", err)
+	}
+	if created.DryRun {
+		return fmt.Sprintf("dry run, issue %q not created in %s/%s", created.Title, options.Owner, options.Repo), nil
+	}
+	return created.URL, nil
+}
+
+type findExistingIssuesArgs struct {
+	Test string `json:"test"`
+}
+
+// findExistingIssues lists the issues similar to the test, the most similar
+// first, one per line.
+func findExistingIssues(ctx context.Context, session *Session, arguments json.RawMessage) (string, error) {
+	var args findExistingIssuesArgs
+	if err := json.Unmarshal(arguments, &args); err != nil {
+		return "", fmt.Errorf("error parsing arguments: %v", err)
+	}
+	if args.Test == "" {
+		return "", errors.New("test is required")
+	}
+
+	issues, err := newProjectManager(ctx, session.Token()).FindExistingIssues(args.Test)
+	if err != nil {
+		return "", fmt.Errorf("error finding existing issues: %v", err)
+	}
+	link, err := issueLink(args.Test)
+	if err != nil {
+		return "", err
+	}
+	if link != nil {
+		// the linked issue tracks the test, it comes first
+		linked := link.Issue()
+		if linked.Title == "" {
+			linked.Title = "linked issue"
+		}
+		issues = append([]github.Issue{linked}, issues...)
+	}
+	if len(issues) == 0 {
+		return "no existing issue found", nil
+	}
+	lines := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		line := fmt.Sprintf("- #%d: %s (%s)", issue.Number, issue.Title, issue.URL)
+		if issue.Draft {
+			line = fmt.Sprintf("- draft: %s", issue.Title)
+		}
+		lines = append(lines, line+issueMetadata(issue))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// issueMetadata returns the test, board and status of the issue read by the
+// search, so the clients compare them with the failing tests instead of
+// parsing the issue, e.g. "; test: [sig-node] Pods; board:
+// sig-release-master-blocking; status: Observing", empty when unknown.
+func issueMetadata(issue github.Issue) string {
+	var metadata strings.Builder
+	for _, field := range []struct{ name, value string }{{"test", issue.TestName}, {"board", issue.Board}, {"status", issue.Status}} {
+		if field.value != "" {
+			fmt.Fprintf(&metadata, "; %s: %s", field.name, field.value)
+		}
+	}
+	return metadata.String()
+}
+
+type acknowledgeIssueLinkArgs struct {
+	Test   string `json:"test"`
+	URL    string `json:"url"`
+	Note   string `json:"note"`
+	Author string `json:"author"`
+}
+
+// acknowledgeIssueLink links the issue to the test in the state store.
+func acknowledgeIssueLink(ctx context.Context, session *Session, arguments json.RawMessage) (string, error) {
+	var args acknowledgeIssueLinkArgs
+	if err := json.Unmarshal(arguments, &args); err != nil {
+		return "", fmt.Errorf("error parsing arguments: %v", err)
+	}
+	if args.Test == "" || args.URL == "" {
+		return "", errors.New("test and url are required")
+	}
+	if State == nil {
+		return "", errors.New("the state store is disabled")
+	}
+	number, err := state.IssueNumber(args.URL)
+	if err != nil {
+		return "", err
+	}
+	if _, err := state.Update(State, func(current *state.State) {
+		_, _ = current.LinkIssue(args.Test, args.URL, args.Note, args.Author)
+	}); err != nil {
+		return "", fmt.Errorf("error saving the issue link: %v", err)
+	}
+	return fmt.Sprintf("issue #%d linked to %s", number, args.Test), nil
+}
+
+// issueLink returns the issue linked to the test, nil without link or state
+// store.
+func issueLink(testName string) (*state.IssueLink, error) {
+	if State == nil {
+		return nil, nil
+	}
+	current, err := State.Load()
+	if err != nil {
+		return nil, fmt.Errorf("error loading the state: %v", err)
+	}
+	if link, ok := current.IssueLink(testName); ok {
+		return &link, nil
+	}
+	return nil, nil
+}
+
+type getFailingTestsArgs struct {
+	Dashboards []string `json:"dashboards"`
+	MinFailure int      `json:"min_failure"`
+	MinFlake   int      `json:"min_flake"`
+}
+
+// failingTests is the result of get_failing_tests, Errors holds the tabs whose
+// tests couldn't be fetched.
+type failingTests struct {
+	Dashboards []string                 `json:"dashboards"`
+	Tabs       []*v1alpha1.DashboardTab `json:"tabs"`
+	Errors     []string                 `json:"errors,omitempty"`
+}
+
+// getFailingTests fetches the failing and flaky tabs of the dashboards from
+// TestGrid, so a client gets the current test list without the TUI.
+func getFailingTests(ctx context.Context, session *Session, arguments json.RawMessage) (string, error) {
+	var args getFailingTestsArgs
+	if len(arguments) > 0 {
+		if err := json.Unmarshal(arguments, &args); err != nil {
+			return "", fmt.Errorf("error parsing arguments: %v", err)
+		}
+	}
+	if args.MinFailure < 0 || args.MinFlake < 0 {
+		return "", errors.New("min_failure and min_flake must be positive")
+	}
+	if len(args.Dashboards) == 0 {
+		args.Dashboards = Dashboards
+	}
+
+	grid := newTestGrid(TestGridURL)
+	result := failingTests{Dashboards: args.Dashboards, Tabs: []*v1alpha1.DashboardTab{}}
+	for _, dashboard := range args.Dashboards {
+		summaries, err := grid.FetchTabSummary(dashboard, v1alpha1.ERROR_STATUSES)
+		if err != nil {
+			return "", fmt.Errorf("error fetching dashboard %s: %v", dashboard, err)
+		}
+		for _, summary := range summaries {
+			tab, err := grid.FetchTabTests(&summary, args.MinFailure, args.MinFlake)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s#%s: %v", dashboard, summary.DashboardTab.TabName, err))
+				continue
+			}
+			if len(tab.TestRuns) > 0 {
+				result.Tabs = append(result.Tabs, tab)
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error encoding failing tests: %v", err)
+	}
+	return string(data), nil
+}
+
+type analyzeTestHistoryArgs struct {
+	Test string `json:"test"`
+	Days int    `json:"days"`
+}
+
+// testHistory is the result of analyze_test_history. The recorded history and
+// the Triage clusters are read independently, the source failing is listed
+// in Errors.
+type testHistory struct {
+	Test         string           `json:"test"`
+	FirstFailure *time.Time       `json:"first_failure,omitempty"`
+	LastFailure  *time.Time       `json:"last_failure,omitempty"`
+	Failures     int              `json:"failures"`
+	Runs         int              `json:"runs"`
+	Boards       []boardHistory   `json:"boards"`
+	Days         []store.Day      `json:"days"`
+	Clusters     []triage.Cluster `json:"triage_clusters"`
+	Errors       []string         `json:"errors,omitempty"`
+}
+
+// boardHistory counts the red runs and the runs of a test on a board.
+type boardHistory struct {
+	Board    string `json:"board"`
+	Failures int    `json:"failures"`
+	Runs     int    `json:"runs"`
+}
+
+// analyzeTestHistory reads the history of the test recorded by signalhound
+// and its Triage clusters.
+func analyzeTestHistory(ctx context.Context, session *Session, arguments json.RawMessage) (string, error) {
+	var args analyzeTestHistoryArgs
+	if err := json.Unmarshal(arguments, &args); err != nil {
+		return "", fmt.Errorf("error parsing arguments: %v", err)
+	}
+	if args.Test == "" {
+		return "", errors.New("test is required")
+	}
+	if args.Days < 0 {
+		return "", errors.New("days must be positive")
+	}
+	window := store.ScoreWindow
+	if args.Days > 0 {
+		window = time.Duration(args.Days) * 24 * time.Hour
+	}
+
+	result := testHistory{Test: args.Test, Boards: []boardHistory{}, Days: []store.Day{}, Clusters: []triage.Cluster{}}
+	if History == nil {
+		result.Errors = append(result.Errors, "the history is disabled on the server")
+	} else if history, err := History.History(args.Test, time.Now().Add(-window)); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	} else {
+		result.Failures, result.Runs = history.FlakeRate()
+		result.Days = append(result.Days, history.Days()...)
+		boards := map[string]int{}
+		for _, run := range history.Runs {
+			index, found := boards[run.BoardHash]
+			if !found {
+				index = len(result.Boards)
+				boards[run.BoardHash] = index
+				result.Boards = append(result.Boards, boardHistory{Board: run.BoardHash})
+			}
+			result.Boards[index].Runs++
+			if !run.Failed {
+				continue
+			}
+			result.Boards[index].Failures++
+			timestamp := run.Timestamp
+			if result.FirstFailure == nil {
+				result.FirstFailure = &timestamp
+			}
+			result.LastFailure = &timestamp
+		}
+	}
+	if clusters, err := Triage.Clusters(args.Test); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	} else if clusters != nil {
+		result.Clusters = clusters
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error encoding test history: %v", err)
+	}
+	return string(data), nil
+}
+
+type renderIssueArgs struct {
+	Board    string `json:"board"`
+	Test     string `json:"test"`
+	State    string `json:"state"`
+	Error    string `json:"error"`
+	Template string `json:"template"`
+}
+
+// renderedIssue is the result of render_issue.
+type renderedIssue struct {
+	Title       string             `json:"title"`
+	FrontMatter *issue.FrontMatter `json:"front_matter,omitempty"`
+	Body        string             `json:"body"`
+}
+
+// renderIssue renders the issue of the test like the UI does, with the
+// template of its state or the template source of the call.
+func renderIssue(ctx context.Context, session *Session, arguments json.RawMessage) (string, error) {
+	var args renderIssueArgs
+	if err := json.Unmarshal(arguments, &args); err != nil {
+		return "", fmt.Errorf("error parsing arguments: %v", err)
+	}
+	if args.Board == "" || args.Test == "" {
+		return "", errors.New("board and test are required")
+	}
+	if args.State != v1alpha1.FAILING_STATUS && args.State != v1alpha1.FLAKY_STATUS {
+		return "", fmt.Errorf("unknown state %q, use FAILING or FLAKY", args.State)
+	}
+
+	tab := &v1alpha1.DashboardTab{BoardHash: args.Board, TabState: args.State}
+	issueTemplate := issue.NewTemplate(tab, &v1alpha1.TestResult{TestName: args.Test, ErrorMessage: args.Error})
+	templateFile, prefixTitle := issue.PickTemplate(args.State)
+	render := func() (bytes.Buffer, error) { return issue.RenderTemplate(issueTemplate, templateFile) }
+	if args.Template != "" {
+		render = func() (bytes.Buffer, error) { return issue.RenderSource(issueTemplate, args.Template) }
+	}
+	rendered, err := render()
+	if err != nil {
+		return "", fmt.Errorf("error rendering issue: %v", err)
+	}
+	frontMatter, body, err := issue.ParseFrontMatter(rendered.String())
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(renderedIssue{Title: issue.Title(prefixTitle, args.Test), FrontMatter: frontMatter, Body: body}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error encoding issue: %v", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,139 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	// SessionHeader carries the session ID of the Streamable HTTP transport.
+	SessionHeader = "Mcp-Session-Id"
+
+	// TokenHeader carries the GitHub token of the session, as an alternative
+	// to the Authorization bearer token.
+	TokenHeader = "X-GitHub-Token"
+
+	// maxRequestBytes limits the size of a single HTTP request body.
+	maxRequestBytes = 4 * 1024 * 1024
+)
+
+// ServeStdio runs a single session reading newline-delimited JSON-RPC
+// messages from in and writing the responses to out, until in is closed or
+// ctx is done. The request being handled is answered before returning.
+func (s *Server) ServeStdio(ctx context.Context, in io.Reader, out io.Writer) error {
+	session := NewSession("")
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRequestBytes)
+	encoder := json.NewEncoder(out)
+
+	// the reads block, they are left to the process exit on shutdown.
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	for {
+		var line string
+		select {
+		case <-ctx.Done():
+			return nil
+		case text, ok := <-lines:
+			if !ok {
+				return scanner.Err()
+			}
+			line = strings.TrimSpace(text)
+		}
+		if line == "" {
+			continue
+		}
+		var request Request
+		var response *Response
+		if err := json.Unmarshal([]byte(line), &request); err != nil {
+			response = &Response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &Error{Code: codeParseError, Message: err.Error()}}
+		} else {
+			response = s.Handle(ctx, session, &request)
+		}
+		if response == nil {
+			continue
+		}
+		if err := encoder.Encode(response); err != nil {
+			return err
+		}
+	}
+}
+
+// HTTPHandler returns the Streamable HTTP transport handler, each client
+// gets its own session on initialize. The session GitHub token is read from
+// the Authorization bearer token or the X-GitHub-Token header, falling back
+// to the initialize _meta params.
+func (s *Server) HTTPHandler() http.Handler {
+	sessions := newSessionStore()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+		case http.MethodDelete:
+			sessions.delete(r.Header.Get(SessionHeader))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		default:
+			w.Header().Set("Allow", "POST, DELETE")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var request Request
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestBytes)).Decode(&request); err != nil {
+			writeJSON(w, http.StatusBadRequest, &Response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &Error{Code: codeParseError, Message: err.Error()}})
+			return
+		}
+
+		var session *Session
+		if request.Method == "initialize" {
+			session = NewSession(headerToken(r))
+		} else {
+			var ok bool
+			if session, ok = sessions.get(r.Header.Get(SessionHeader)); !ok {
+				http.Error(w, "unknown or missing session", http.StatusNotFound)
+				return
+			}
+		}
+
+		response := s.Handle(r.Context(), session, &request)
+		if request.Method == "initialize" && response != nil && response.Error == nil {
+			sessions.add(session)
+			w.Header().Set(SessionHeader, session.ID)
+		}
+		if response == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		writeJSON(w, http.StatusOK, response)
+	})
+}
+
+// headerToken returns the GitHub token of the request headers.
+func headerToken(r *http.Request) string {
+	if token := r.Header.Get(TokenHeader); token != "" {
+		return token
+	}
+	if scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,182 @@
+package monitoring
+
+import "strings"
+
+// Kind is the OpenTelemetry instrument kind of a metric.
+type Kind string
+
+const (
+	Gauge     Kind = "gauge"
+	Counter   Kind = "counter"
+	Histogram Kind = "histogram"
+)
+
+// Definition describes a metric recorded by signalhound, it is the single
+// source for the instruments and the generated monitoring manifests.
+type Definition struct {
+	Name        string
+	Description string
+	Unit        string
+	Kind        Kind
+	Labels      []string
+
+	// GroupBy are the labels the Grafana panel series are summed by, all the
+	// labels besides dashboard when empty.
+	GroupBy []string
+
+	// Panel is the PromQL of the Grafana panel with %s standing for the
+	// Prometheus name, empty for the default query of the kind.
+	Panel string
+}
+
+var (
+	DashboardState = Definition{
+		Name:        "testgrid_dashboard_state",
+		Description: "Current state of testgrid dashboard (1 = active state)",
+		Unit:        "1",
+		Kind:        Gauge,
+		Labels:      []string{"dashboard", "tab", "overall_state", "state"},
+	}
+	TabState = Definition{
+		Name:        "testgrid_tab_state",
+		Description: "State of testgrid dashboard tab",
+		Unit:        "1",
+		Kind:        Gauge,
+		Labels:      []string{"dashboard", "tab", "state"},
+	}
+	LastRunTimestamp = Definition{
+		Name:        "testgrid_dashboard_last_run_timestamp",
+		Description: "Unix timestamp of the last test run for a dashboard tab",
+		Unit:        "s",
+		Kind:        Gauge,
+		Labels:      []string{"dashboard", "tab"},
+	}
+	LastUpdateTimestamp = Definition{
+		Name:        "testgrid_dashboard_last_update_timestamp",
+		Description: "Unix timestamp of the last update for a dashboard tab",
+		Unit:        "s",
+		Kind:        Gauge,
+		Labels:      []string{"dashboard", "tab"},
+	}
+	TotalTestFailures = Definition{
+		Name:        "testgrid_test_failures_total",
+		Description: "Total number of failing tests in a dashboard tab",
+		Unit:        "1",
+		Kind:        Gauge,
+		Labels:      []string{"dashboard", "tab"},
+	}
+	TotalTestFlakes = Definition{
+		Name:        "testgrid_test_flakes_total",
+		Description: "Total number of flaky tests in a dashboard tab",
+		Unit:        "1",
+		Kind:        Gauge,
+		Labels:      []string{"dashboard", "tab"},
+	}
+	TestFailures = Definition{
+		Name:        "testgrid_individual_test_failures_total",
+		Description: "Counter of failures for individual tests",
+		Unit:        "1",
+		Kind:        Counter,
+		Labels:      []string{"dashboard", "tab", "test_name", "tab_state"},
+		GroupBy:     []string{"tab", "test_name"},
+	}
+	TestGridRequestDuration = Definition{
+		Name:        "signalhound_testgrid_request_duration",
+		Description: "Latency of the TestGrid requests",
+		Unit:        "s",
+		Kind:        Histogram,
+		Labels:      []string{"dashboard", "endpoint"},
+	}
+	TestGridRequestErrors = Definition{
+		Name:        "signalhound_testgrid_request_errors",
+		Description: "Number of failed TestGrid requests",
+		Unit:        "1",
+		Kind:        Counter,
+		Labels:      []string{"dashboard", "endpoint"},
+	}
+	GitHubRequestDuration = Definition{
+		Name:        "signalhound_github_request_duration",
+		Description: "Latency of the GitHub API calls",
+		Unit:        "s",
+		Kind:        Histogram,
+		Labels:      []string{"operation"},
+	}
+	GitHubRequests = Definition{
+		Name:        "signalhound_github_requests",
+		Description: "Number of GitHub API calls",
+		Unit:        "1",
+		Kind:        Counter,
+		Labels:      []string{"operation", "result"},
+	}
+	CacheLookups = Definition{
+		Name:        "signalhound_cache_lookups",
+		Description: "Cache hit ratio",
+		Unit:        "1",
+		Kind:        Counter,
+		Labels:      []string{"cache", "result"},
+		GroupBy:     []string{"cache"},
+		Panel: `sum by (cache) (rate(%[1]s{result="hit"}[$__rate_interval]))` +
+			` / sum by (cache) (rate(%[1]s[$__rate_interval]))`,
+	}
+	ReleaseHealthScore = Definition{
+		Name:        "signalhound_release_health_score",
+		Description: "Signal health score of a release from 0 to 100, from its board states and flake rates",
+		Unit:        "{score}",
+		Kind:        Gauge,
+		Labels:      []string{"release"},
+	}
+	UserActions = Definition{
+		Name:        "signalhound_user_actions",
+		Description: "Number of actions taken in the abstract UI, recorded when telemetry.actions is enabled",
+		Unit:        "1",
+		Kind:        Counter,
+		Labels:      []string{"action"},
+	}
+)
+
+// Definitions lists every metric recorded by signalhound, the TestGrid
+// dashboard metrics of the controller and the fetch, GitHub and cache
+// metrics of the clients.
+var Definitions = []Definition{
+	DashboardState,
+	TabState,
+	LastRunTimestamp,
+	LastUpdateTimestamp,
+	TotalTestFailures,
+	TotalTestFlakes,
+	TestFailures,
+	ReleaseHealthScore,
+	TestGridRequestDuration,
+	TestGridRequestErrors,
+	GitHubRequestDuration,
+	GitHubRequests,
+	CacheLookups,
+	UserActions,
+}
+
+// unitSuffixes maps the OpenTelemetry units to the suffixes added by the
+// Prometheus exporter.
+var unitSuffixes = map[string]string{
+	"s":  "seconds",
+	"ms": "milliseconds",
+	"By": "bytes",
+}
+
+// PrometheusName returns the name exposed by the OpenTelemetry Prometheus
+// exporter, which appends the unit suffix, "ratio" for dimensionless gauges,
+// and "total" for counters. Histograms are exposed as the _bucket, _sum and
+// _count series of the name.
+func (d Definition) PrometheusName() string {
+	name := d.Name
+	suffix := unitSuffixes[d.Unit]
+	if d.Unit == "1" && d.Kind == Gauge {
+		suffix = "ratio"
+	}
+	if suffix != "" && !strings.HasSuffix(name, "_"+suffix) {
+		name += "_" + suffix
+	}
+	if d.Kind == Counter && !strings.HasSuffix(name, "_total") {
+		name += "_total"
+	}
+	return name
+}
@@ -0,0 +1,248 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ServiceMonitorFile and DashboardFile are the generated file names.
+	ServiceMonitorFile = "servicemonitor.yaml"
+	DashboardFile      = "grafana-dashboard.json"
+
+	// quantile is the latency quantile shown on the histogram panels.
+	quantile = "0.95"
+)
+
+// variableFilters restricts the panels to the dashboard variables, on the
+// metrics with the variable label.
+var variableFilters = []struct{ label, filter string }{
+	{"dashboard", `dashboard=~"$dashboard"`},
+	{"tab", `tab=~"$tab"`},
+}
+
+// Options are the deployment settings of the generated manifests, the
+// defaults match config/default.
+type Options struct {
+	Namespace string
+	Name      string
+	Port      string
+	Secure    bool
+}
+
+// DefaultOptions returns the options of the kustomize deployment.
+func DefaultOptions() Options {
+	return Options{
+		Namespace: "signalhound-system",
+		Name:      "signalhound-controller-manager-metrics-monitor",
+		Port:      "https",
+		Secure:    true,
+	}
+}
+
+var selectorLabels = map[string]string{
+	"control-plane":          "controller-manager",
+	"app.kubernetes.io/name": "signalhound",
+}
+
+// ServiceMonitor returns the Prometheus Operator ServiceMonitor scraping the
+// controller metrics service, keeping only the controller metrics and the
+// controller-runtime health metrics.
+func ServiceMonitor(opts Options) ([]byte, error) {
+	var names []string
+	for _, definition := range Definitions {
+		name := definition.PrometheusName()
+		if definition.Kind == Histogram {
+			name += "_(bucket|sum|count)"
+		}
+		names = append(names, name)
+	}
+
+	endpoint := map[string]interface{}{
+		"path":   "/metrics",
+		"port":   opts.Port,
+		"scheme": "http",
+		"metricRelabelings": []map[string]interface{}{{
+			"action":       "keep",
+			"sourceLabels": []string{"__name__"},
+			"regex":        fmt.Sprintf("%s|controller_runtime_.*|workqueue_.*", strings.Join(names, "|")),
+		}},
+	}
+	if opts.Secure {
+		endpoint["scheme"] = "https"
+		endpoint["bearerTokenFile"] = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+		endpoint["tlsConfig"] = map[string]interface{}{"insecureSkipVerify": true}
+	}
+
+	return yaml.Marshal(map[string]interface{}{
+		"apiVersion": "monitoring.coreos.com/v1",
+		"kind":       "ServiceMonitor",
+		"metadata": map[string]interface{}{
+			"name":      opts.Name,
+			"namespace": opts.Namespace,
+			"labels":    selectorLabels,
+		},
+		"spec": map[string]interface{}{
+			"endpoints": []interface{}{endpoint},
+			"selector":  map[string]interface{}{"matchLabels": selectorLabels},
+		},
+	})
+}
+
+// selector returns the series selector of the definition, filtered by the
+// dashboard variables of its labels.
+func selector(definition Definition, suffix string) string {
+	var filters []string
+	for _, variable := range variableFilters {
+		if slices.Contains(definition.Labels, variable.label) {
+			filters = append(filters, variable.filter)
+		}
+	}
+	if len(filters) == 0 {
+		return definition.PrometheusName() + suffix
+	}
+	return fmt.Sprintf("%s%s{%s}", definition.PrometheusName(), suffix, strings.Join(filters, ", "))
+}
+
+// panelQuery returns the PromQL of the definition panel, counters are shown as
+// their increase over the rate interval and histograms as their quantile.
+func panelQuery(definition Definition) string {
+	switch {
+	case definition.Panel != "":
+		return fmt.Sprintf(definition.Panel, definition.PrometheusName())
+	case definition.Kind == Counter:
+		return fmt.Sprintf("topk(20, sum by (%s) (increase(%s[$__rate_interval])))",
+			strings.Join(groupBy(definition), ", "), selector(definition, ""))
+	case definition.Kind == Histogram:
+		return fmt.Sprintf("histogram_quantile(%s, sum by (%s) (rate(%s[$__rate_interval])))",
+			quantile, strings.Join(append(groupBy(definition), "le"), ", "), selector(definition, "_bucket"))
+	case definition.Unit == "s":
+		return fmt.Sprintf("%s * 1000", selector(definition, ""))
+	}
+	return selector(definition, "")
+}
+
+// panelUnit returns the Grafana unit of the definition values, the custom
+// panels show ratios.
+func panelUnit(definition Definition) string {
+	switch {
+	case definition.Panel != "":
+		return "percentunit"
+	case definition.Kind == Histogram:
+		return "s"
+	case definition.Unit == "s":
+		return "dateTimeAsIso"
+	}
+	return "short"
+}
+
+// groupBy returns the labels the panel series are split by, the dashboard
+// label is left to the dashboard variable.
+func groupBy(definition Definition) (labels []string) {
+	if len(definition.GroupBy) > 0 {
+		return definition.GroupBy
+	}
+	for _, label := range definition.Labels {
+		if label != "dashboard" {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// legend returns the legend template of the panel series.
+func legend(definition Definition) string {
+	var parts []string
+	for _, label := range groupBy(definition) {
+		parts = append(parts, fmt.Sprintf("{{%s}}", label))
+	}
+	return strings.Join(parts, " ")
+}
+
+// GrafanaDashboard returns a Grafana dashboard with one time series panel per
+// metric definition, filtered by the dashboard and tab variables.
+func GrafanaDashboard() ([]byte, error) {
+	datasource := map[string]string{"type": "prometheus", "uid": "${datasource}"}
+	var panels []interface{}
+	for i, definition := range Definitions {
+		panels = append(panels, map[string]interface{}{
+			"id":          i + 1,
+			"type":        "timeseries",
+			"title":       definition.Description,
+			"description": fmt.Sprintf("%s (%s)", definition.PrometheusName(), definition.Kind),
+			"datasource":  datasource,
+			"gridPos":     map[string]int{"h": 9, "w": 12, "x": (i % 2) * 12, "y": (i / 2) * 9},
+			"fieldConfig": map[string]interface{}{
+				"defaults":  map[string]interface{}{"unit": panelUnit(definition)},
+				"overrides": []interface{}{},
+			},
+			"options": map[string]interface{}{
+				"legend":  map[string]interface{}{"displayMode": "table", "placement": "bottom", "showLegend": true},
+				"tooltip": map[string]interface{}{"mode": "multi", "sort": "desc"},
+			},
+			"targets": []interface{}{map[string]interface{}{
+				"datasource":   datasource,
+				"expr":         panelQuery(definition),
+				"legendFormat": legend(definition),
+				"refId":        "A",
+			}},
+		})
+	}
+
+	variable := func(name, label, selector string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":       name,
+			"label":      label,
+			"type":       "query",
+			"datasource": datasource,
+			"query":      fmt.Sprintf("label_values(%s%s, %s)", TabState.PrometheusName(), selector, name),
+			"refresh":    2,
+			"multi":      true,
+			"includeAll": true,
+			"current":    map[string]interface{}{"text": "All", "value": "$__all"},
+		}
+	}
+	dashboard := map[string]interface{}{
+		"title":         "SignalHound",
+		"uid":           "signalhound",
+		"editable":      true,
+		"graphTooltip":  1,
+		"schemaVersion": 39,
+		"refresh":       "1m",
+		"time":          map[string]string{"from": "now-24h", "to": "now"},
+		"panels":        panels,
+		"templating": map[string]interface{}{"list": []interface{}{
+			map[string]interface{}{"name": "datasource", "label": "Data source", "type": "datasource", "query": "prometheus"},
+			variable("dashboard", "Dashboard", ""),
+			variable("tab", "Tab", `{dashboard=~"$dashboard"}`),
+		}},
+	}
+	return json.MarshalIndent(dashboard, "", "  ")
+}
+
+// Write generates the ServiceMonitor and the Grafana dashboard into dir.
+func Write(dir string, opts Options) error {
+	serviceMonitor, err := ServiceMonitor(opts)
+	if err != nil {
+		return fmt.Errorf("error generating service monitor: %v", err)
+	}
+	dashboard, err := GrafanaDashboard()
+	if err != nil {
+		return fmt.Errorf("error generating grafana dashboard: %v", err)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	header := []byte("# Code generated by signalhound generate-monitoring. DO NOT EDIT.\n")
+	if err := os.WriteFile(filepath.Join(dir, ServiceMonitorFile), append(header, serviceMonitor...), 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, DashboardFile), append(dashboard, '\n'), 0o644)
+}
@@ -0,0 +1,110 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestPrometheusName(t *testing.T) {
+	assert.Equal(t, "testgrid_tab_state_ratio", TabState.PrometheusName())
+	assert.Equal(t, "testgrid_test_failures_total_ratio", TotalTestFailures.PrometheusName())
+	assert.Equal(t, "testgrid_dashboard_last_run_timestamp_seconds", LastRunTimestamp.PrometheusName())
+	assert.Equal(t, "testgrid_individual_test_failures_total", TestFailures.PrometheusName())
+	assert.Equal(t, "signalhound_release_health_score", ReleaseHealthScore.PrometheusName())
+	assert.Equal(t, "requests_total", Definition{Name: "requests", Kind: Counter}.PrometheusName())
+	assert.Equal(t, "signalhound_testgrid_request_duration_seconds", TestGridRequestDuration.PrometheusName())
+	assert.Equal(t, "signalhound_github_requests_total", GitHubRequests.PrometheusName())
+}
+
+func TestServiceMonitor(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Namespace = "monitoring"
+	output, err := ServiceMonitor(opts)
+	assert.NoError(t, err)
+	assert.Contains(t, string(output), "namespace: monitoring")
+	assert.Contains(t, string(output), "scheme: https")
+	for _, definition := range Definitions {
+		assert.Contains(t, string(output), definition.PrometheusName())
+	}
+
+	opts.Secure = false
+	output, err = ServiceMonitor(opts)
+	assert.NoError(t, err)
+	assert.Contains(t, string(output), "scheme: http\n")
+	assert.NotContains(t, string(output), "bearerTokenFile")
+}
+
+func TestGrafanaDashboard(t *testing.T) {
+	output, err := GrafanaDashboard()
+	assert.NoError(t, err)
+	for _, definition := range Definitions {
+		assert.Contains(t, string(output), definition.PrometheusName())
+	}
+	assert.Contains(t, string(output), `testgrid_tab_state_ratio{dashboard=~\"$dashboard\", tab=~\"$tab\"}`)
+	assert.Contains(t, string(output), "increase(testgrid_individual_test_failures_total")
+	assert.Contains(t, string(output), `histogram_quantile(0.95, sum by (endpoint, le) `+
+		`(rate(signalhound_testgrid_request_duration_seconds_bucket{dashboard=~\"$dashboard\"}[$__rate_interval])))`)
+	assert.Contains(t, string(output), `rate(signalhound_cache_lookups_total{result=\"hit\"}`)
+}
+
+// TestGeneratedFiles fails when config/monitoring is stale, run
+// make monitoring to regenerate it.
+func TestGeneratedFiles(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, Write(dir, DefaultOptions()))
+	for _, file := range []string{ServiceMonitorFile, DashboardFile} {
+		want, err := os.ReadFile(filepath.Join(dir, file))
+		assert.NoError(t, err)
+		got, err := os.ReadFile(filepath.Join("..", "..", "config", "monitoring", file))
+		assert.NoError(t, err)
+		assert.Equal(t, strings.TrimSpace(string(want)), strings.TrimSpace(string(got)), "%s is stale, run make monitoring", file)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	RecordTestGridRequest("sig-release-master-blocking", "summary", time.Now(), nil)
+	RecordTestGridRequest("sig-release-master-blocking", "table", time.Now(), errors.New("timeout"))
+	RecordGitHubRequest("createIssue", time.Now(), nil)
+	RecordCacheLookup("job_config", true)
+	RecordCacheLookup("job_config", false)
+	RecordUserAction("issue_created")
+
+	var data metricdata.ResourceMetrics
+	assert.NoError(t, reader.Collect(context.Background(), &data))
+	points := map[string]int{}
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			switch values := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, point := range values.DataPoints {
+					points[m.Name] += int(point.Value)
+				}
+			case metricdata.Histogram[float64]:
+				for _, point := range values.DataPoints {
+					points[m.Name] += int(point.Count)
+				}
+			}
+		}
+	}
+	assert.Equal(t, map[string]int{
+		TestGridRequestDuration.Name: 2,
+		TestGridRequestErrors.Name:   1,
+		GitHubRequestDuration.Name:   1,
+		GitHubRequests.Name:          1,
+		CacheLookups.Name:            2,
+		UserActions.Name:             1,
+	}, points)
+}
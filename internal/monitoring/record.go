@@ -0,0 +1,125 @@
+package monitoring
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// MeterName is the OpenTelemetry meter of the signalhound instruments.
+const MeterName = "signalhound"
+
+// latencyBuckets are the histogram bounds in seconds of the request
+// latencies, the OpenTelemetry defaults are meant for milliseconds.
+var latencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+var (
+	setupOnce sync.Once
+	setupErr  error
+)
+
+// Setup exports the OpenTelemetry metrics to the controller-runtime
+// Prometheus registry, served by the controller manager or by Handler.
+func Setup() error {
+	setupOnce.Do(func() {
+		exporter, err := prometheus.New(prometheus.WithRegisterer(metrics.Registry))
+		if err != nil {
+			setupErr = err
+			return
+		}
+		otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(exporter)))
+	})
+	return setupErr
+}
+
+// Handler serves the metrics exported by Setup, for the modes running
+// without the controller manager, e.g. mcp --http.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})
+}
+
+// instruments are the client metrics, created on first use from the global
+// meter provider. Without Setup they are no-ops.
+var instruments struct {
+	once             sync.Once
+	testGridDuration metric.Float64Histogram
+	testGridErrors   metric.Int64Counter
+	gitHubDuration   metric.Float64Histogram
+	gitHubRequests   metric.Int64Counter
+	cacheLookups     metric.Int64Counter
+	userActions      metric.Int64Counter
+}
+
+func initInstruments() {
+	instruments.once.Do(func() {
+		meter := otel.Meter(MeterName)
+		histogram := func(definition Definition) metric.Float64Histogram {
+			h, _ := meter.Float64Histogram(definition.Name, metric.WithDescription(definition.Description),
+				metric.WithUnit(definition.Unit), metric.WithExplicitBucketBoundaries(latencyBuckets...))
+			return h
+		}
+		counter := func(definition Definition) metric.Int64Counter {
+			c, _ := meter.Int64Counter(definition.Name, metric.WithDescription(definition.Description),
+				metric.WithUnit(definition.Unit))
+			return c
+		}
+		instruments.testGridDuration = histogram(TestGridRequestDuration)
+		instruments.testGridErrors = counter(TestGridRequestErrors)
+		instruments.gitHubDuration = histogram(GitHubRequestDuration)
+		instruments.gitHubRequests = counter(GitHubRequests)
+		instruments.cacheLookups = counter(CacheLookups)
+		instruments.userActions = counter(UserActions)
+	})
+}
+
+// RecordTestGridRequest records the latency of a request to the endpoint of
+// a TestGrid dashboard started at start, and counts it when err is not nil.
+func RecordTestGridRequest(dashboard, endpoint string, start time.Time, err error) {
+	initInstruments()
+	attributes := metric.WithAttributes(attribute.String("dashboard", dashboard), attribute.String("endpoint", endpoint))
+	instruments.testGridDuration.Record(context.Background(), time.Since(start).Seconds(), attributes)
+	if err != nil {
+		instruments.testGridErrors.Add(context.Background(), 1, attributes)
+	}
+}
+
+// RecordGitHubRequest records the latency and the result of a GitHub API
+// operation started at start.
+func RecordGitHubRequest(operation string, start time.Time, err error) {
+	initInstruments()
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	instruments.gitHubDuration.Record(context.Background(), time.Since(start).Seconds(),
+		metric.WithAttributes(attribute.String("operation", operation)))
+	instruments.gitHubRequests.Add(context.Background(), 1,
+		metric.WithAttributes(attribute.String("operation", operation), attribute.String("result", result)))
+}
+
+// RecordCacheLookup counts a lookup in the named cache as a hit or a miss.
+func RecordCacheLookup(cache string, hit bool) {
+	initInstruments()
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	instruments.cacheLookups.Add(context.Background(), 1,
+		metric.WithAttributes(attribute.String("cache", cache), attribute.String("result", result)))
+}
+
+// RecordUserAction counts an action taken in the abstract UI, e.g.
+// issue_created, without the test or the user it was taken for.
+func RecordUserAction(action string) {
+	initInstruments()
+	instruments.userActions.Add(context.Background(), 1, metric.WithAttributes(attribute.String("action", action)))
+}
@@ -0,0 +1,143 @@
+// Package network sets up the HTTP transport shared by the clients of
+// TestGrid, GitHub, Slack, Prow, Triage, Anthropic and MCP, for the
+// contributors behind a corporate proxy or a TLS inspecting firewall.
+package network
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// defaultTransport is the transport of the standard library, the settings
+// are applied to a clone of it.
+var defaultTransport = http.DefaultTransport.(*http.Transport)
+
+// Config is the proxy and the TLS settings of the HTTP clients. Without
+// proxy, the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables are
+// used.
+type Config struct {
+	// Proxy is the URL of the proxy of the HTTP and HTTPS requests, e.g.
+	// http://proxy.example.com:3128, overriding HTTPS_PROXY and HTTP_PROXY.
+	Proxy string `json:"proxy,omitempty"`
+
+	// NoProxy lists the hosts reached without proxy, comma-separated like
+	// NO_PROXY, overriding it.
+	NoProxy string `json:"noProxy,omitempty"`
+
+	// CABundle is a PEM file of the certificate authorities trusted on top of
+	// the system ones, e.g. the authority of a TLS inspecting firewall.
+	CABundle string `json:"caBundle,omitempty"`
+
+	// ClientCert and ClientKey are the PEM files of the client certificate
+	// presented to the servers asking for one.
+	ClientCert string `json:"clientCert,omitempty"`
+	ClientKey  string `json:"clientKey,omitempty"`
+}
+
+// Transport returns a clone of the default transport with the proxy and the
+// TLS settings.
+func (c *Config) Transport() (*http.Transport, error) {
+	transport := defaultTransport.Clone()
+	if c.Proxy != "" || c.NoProxy != "" {
+		proxy := httpproxy.FromEnvironment()
+		if c.Proxy != "" {
+			if _, err := url.Parse(c.Proxy); err != nil {
+				return nil, fmt.Errorf("invalid proxy %q: %v", c.Proxy, err)
+			}
+			proxy.HTTPProxy, proxy.HTTPSProxy = c.Proxy, c.Proxy
+		}
+		if c.NoProxy != "" {
+			proxy.NoProxy = c.NoProxy
+		}
+		proxyFunc := proxy.ProxyFunc()
+		transport.Proxy = func(request *http.Request) (*url.URL, error) {
+			return proxyFunc(request.URL)
+		}
+	}
+
+	if c.CABundle == "" && c.ClientCert == "" && c.ClientKey == "" {
+		return transport, nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.CABundle != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		bundle, err := os.ReadFile(c.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the CA bundle: %v", err)
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("error reading the CA bundle: no PEM certificate in %s", c.CABundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if c.ClientCert != "" || c.ClientKey != "" {
+		certificate, err := tls.LoadX509KeyPair(c.ClientCert, c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// Setup replaces the default transport of the standard library, used by
+// every HTTP client of signalhound, by the transport of the configuration.
+// The requests in flight are canceled once ctx is done, e.g. on shutdown.
+func Setup(ctx context.Context, c Config) error {
+	transport, err := c.Transport()
+	if err != nil {
+		return err
+	}
+	http.DefaultTransport = &cancelTransport{ctx: ctx, base: transport}
+	return nil
+}
+
+// cancelTransport cancels the requests of base once ctx is done, until their
+// response body is closed.
+type cancelTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (t *cancelTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if err := t.ctx.Err(); err != nil {
+		return nil, fmt.Errorf("request canceled on shutdown: %w", err)
+	}
+	requestCtx, cancel := context.WithCancel(request.Context())
+	stop := context.AfterFunc(t.ctx, cancel)
+	response, err := t.base.RoundTrip(request.WithContext(requestCtx))
+	if err != nil {
+		stop()
+		cancel()
+		return nil, err
+	}
+	response.Body = &cancelBody{ReadCloser: response.Body, done: func() {
+		stop()
+		cancel()
+	}}
+	return response, nil
+}
+
+// cancelBody releases the cancellation of its request once closed.
+type cancelBody struct {
+	io.ReadCloser
+	done func()
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.done()
+	return err
+}
@@ -0,0 +1,89 @@
+package network
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransportProxy(t *testing.T) {
+	config := Config{Proxy: "http://proxy.example.com:3128", NoProxy: "internal.example.com"}
+	transport, err := config.Transport()
+	assert.NoError(t, err)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://testgrid.k8s.io/sig-release-master-blocking/summary", nil)
+	proxy, err := transport.Proxy(request)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://proxy.example.com:3128", proxy.String())
+
+	request, _ = http.NewRequest(http.MethodGet, "https://internal.example.com/mcp", nil)
+	proxy, err = transport.Proxy(request)
+	assert.NoError(t, err)
+	assert.Nil(t, proxy)
+}
+
+func TestTransportCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	dir := t.TempDir()
+	bundle := filepath.Join(dir, "ca.pem")
+	assert.NoError(t, os.WriteFile(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+
+	// the test server certificate is not trusted by the system
+	_, err := (&http.Client{Transport: defaultTransport.Clone()}).Get(server.URL)
+	assert.ErrorContains(t, err, "certificate")
+
+	config := Config{CABundle: bundle}
+	transport, err := config.Transport()
+	assert.NoError(t, err)
+	response, err := (&http.Client{Transport: transport}).Get(server.URL)
+	if assert.NoError(t, err) {
+		response.Body.Close() // nolint
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+
+	previous := http.DefaultTransport
+	t.Cleanup(func() { http.DefaultTransport = previous })
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.NoError(t, Setup(ctx, config))
+	response, err = http.Get(server.URL)
+	if assert.NoError(t, err) {
+		response.Body.Close() // nolint
+	}
+	// the requests are canceled on shutdown
+	cancel()
+	_, err = http.Get(server.URL)
+	assert.ErrorContains(t, err, "canceled on shutdown")
+
+	assert.NoError(t, os.WriteFile(bundle, []byte("not a certificate"), 0o600))
+	_, err = config.Transport()
+	assert.ErrorContains(t, err, "no PEM certificate")
+	_, err = (&Config{ClientCert: filepath.Join(dir, "missing.pem")}).Transport()
+	assert.ErrorContains(t, err, "client certificate")
+}
+
+func TestCancelTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := &http.Client{Transport: &cancelTransport{ctx: ctx, base: &http.Transport{}}}
+	done := make(chan error)
+	go func() {
+		_, err := client.Get(server.URL)
+		done <- err
+	}()
+	// the request in flight is canceled
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
@@ -0,0 +1,141 @@
+// Package notify pushes the alerts of the controller to Slack incoming
+// webhooks, generic HTTP webhooks and email, for the operators not running
+// the UI.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/signalhound/internal/slack"
+)
+
+// Alert is a dashboard tab entering a state matched by an alert rule.
+type Alert struct {
+	Rule      string   `json:"rule"`
+	Dashboard string   `json:"dashboard"`
+	Tab       string   `json:"tab"`
+	BoardHash string   `json:"board_hash"`
+	TabURL    string   `json:"tab_url"`
+	State     string   `json:"state"`
+	Tests     []string `json:"tests,omitempty"`
+}
+
+// Message renders the alert as a markdown message, with up to five tests.
+func (a *Alert) Message() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%s is %s on [%s](%s)", a.Tab, a.State, a.BoardHash, a.TabURL) // nolint
+	for i, test := range a.Tests {
+		if i == 5 {
+			fmt.Fprintf(&builder, "\n* and %d more tests", len(a.Tests)-i) // nolint
+			break
+		}
+		fmt.Fprintf(&builder, "\n* `%s`", test) // nolint
+	}
+	return builder.String()
+}
+
+// Notifier sends an alert to a target.
+type Notifier interface {
+	Notify(ctx context.Context, alert *Alert) error
+}
+
+// client is the HTTP client of the webhooks.
+var client = &http.Client{Timeout: 15 * time.Second}
+
+// SlackWebhook posts the alerts to a Slack incoming webhook, the channel is
+// the one of the webhook.
+type SlackWebhook struct {
+	URL string
+}
+
+// Notify posts the alert message with its links in the Slack syntax.
+func (s *SlackWebhook) Notify(ctx context.Context, alert *Alert) error {
+	return post(ctx, s.URL, map[string]string{"text": slack.Mrkdwn(alert.Message())})
+}
+
+// Webhook posts the alerts as JSON to an HTTP endpoint, with the message in
+// the text field.
+type Webhook struct {
+	URL string
+}
+
+// Notify posts the alert fields and its message.
+func (w *Webhook) Notify(ctx context.Context, alert *Alert) error {
+	return post(ctx, w.URL, struct {
+		*Alert
+		Text string `json:"text"`
+	}{alert, alert.Message()})
+}
+
+// post sends the payload as JSON to the url, any status but 2xx is an error.
+func post(ctx context.Context, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error sending the alert: %v", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("error sending the alert: %v", err)
+	}
+	defer response.Body.Close() // nolint
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("error sending the alert: %s", response.Status)
+	}
+	return nil
+}
+
+// SMTP is the mail server the email alerts are sent through, without
+// authentication when Username is empty.
+type SMTP struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+// sendMail is smtp.SendMail, replaced in the tests.
+var sendMail = smtp.SendMail
+
+// Email sends the alerts by email to the recipients through the server.
+type Email struct {
+	Server *SMTP
+	To     []string
+}
+
+// Notify sends the alert message as a plain text email.
+func (e *Email) Notify(ctx context.Context, alert *Alert) error {
+	subject := fmt.Sprintf("%s is %s on %s", alert.Tab, alert.State, alert.Dashboard)
+	if err := e.Send(subject, alert.Message()); err != nil {
+		return fmt.Errorf("error sending the alert: %v", err)
+	}
+	return nil
+}
+
+// Send sends the plain text email to the recipients, the subject prefixed
+// with [signalhound].
+func (e *Email) Send(subject, body string) error {
+	if e.Server == nil || e.Server.Addr == "" {
+		return errors.New("no SMTP server, set --smtp-addr")
+	}
+	var auth smtp.Auth
+	if e.Server.Username != "" {
+		host, _, _ := strings.Cut(e.Server.Addr, ":")
+		auth = smtp.PlainAuth("", e.Server.Username, e.Server.Password, host)
+	}
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: [signalhound] %s\r\n\r\n%s\r\n",
+		e.Server.From, strings.Join(e.To, ", "), subject, body)
+	return sendMail(e.Server.Addr, auth, e.Server.From, e.To, []byte(message))
+}
@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var alert = &Alert{
+	Rule: "default/blocking", Dashboard: "sig-release-master-blocking", Tab: "gce-cos-master-default",
+	BoardHash: "sig-release-master-blocking#gce-cos-master-default", TabURL: "https://testgrid.k8s.io/sig-release-master-blocking#gce-cos-master-default",
+	State: "FAILING", Tests: []string{"[sig-node] Pods should run"},
+}
+
+func TestMessage(t *testing.T) {
+	assert.Equal(t, "gce-cos-master-default is FAILING on [sig-release-master-blocking#gce-cos-master-default]"+
+		"(https://testgrid.k8s.io/sig-release-master-blocking#gce-cos-master-default)\n* `[sig-node] Pods should run`", alert.Message())
+
+	many := *alert
+	many.Tests = []string{"a", "b", "c", "d", "e", "f", "g"}
+	assert.Contains(t, many.Message(), "* `e`\n* and 2 more tests")
+}
+
+func TestWebhooks(t *testing.T) {
+	var payloads []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var payload map[string]interface{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		payloads = append(payloads, payload)
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	assert.NoError(t, (&SlackWebhook{URL: server.URL + "/slack"}).Notify(ctx, alert))
+	assert.NoError(t, (&Webhook{URL: server.URL + "/hook"}).Notify(ctx, alert))
+	if assert.Len(t, payloads, 2) {
+		assert.Equal(t, map[string]interface{}{"text": "gce-cos-master-default is FAILING on <https://testgrid.k8s.io/sig-release-master-blocking#gce-cos-master-default|" +
+			"sig-release-master-blocking#gce-cos-master-default>\n* `[sig-node] Pods should run`"}, payloads[0])
+		assert.Equal(t, "FAILING", payloads[1]["state"])
+		assert.Equal(t, "default/blocking", payloads[1]["rule"])
+		assert.Equal(t, alert.Message(), payloads[1]["text"])
+	}
+	assert.EqualError(t, (&Webhook{URL: server.URL + "/gone"}).Notify(ctx, alert), "error sending the alert: 410 Gone")
+}
+
+func TestEmail(t *testing.T) {
+	var sent struct {
+		addr, from string
+		to         []string
+		message    string
+		auth       bool
+	}
+	previous := sendMail
+	t.Cleanup(func() { sendMail = previous })
+	sendMail = func(addr string, auth smtp.Auth, from string, to []string, message []byte) error {
+		sent.addr, sent.from, sent.to, sent.message, sent.auth = addr, from, to, string(message), auth != nil
+		return nil
+	}
+
+	server := &SMTP{Addr: "smtp.example.com:587", From: "signalhound@example.com", Username: "bot", Password: "secret"}
+	email := &Email{Server: server, To: []string{"release-team@example.com"}}
+	assert.NoError(t, email.Notify(context.Background(), alert))
+	assert.Equal(t, "smtp.example.com:587", sent.addr)
+	assert.Equal(t, []string{"release-team@example.com"}, sent.to)
+	assert.True(t, sent.auth)
+	assert.Contains(t, sent.message, "Subject: [signalhound] gce-cos-master-default is FAILING on sig-release-master-blocking\r\n")
+	assert.Contains(t, sent.message, "\r\n\r\n"+alert.Message())
+
+	sendMail = func(string, smtp.Auth, string, []string, []byte) error { return errors.New("connection refused") }
+	assert.EqualError(t, email.Notify(context.Background(), alert), "error sending the alert: connection refused")
+	assert.ErrorContains(t, (&Email{To: email.To}).Notify(context.Background(), alert), "no SMTP server")
+}
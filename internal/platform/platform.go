@@ -0,0 +1,133 @@
+package platform
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+)
+
+// Default is the variant of the tabs without platform token.
+const Default = "default"
+
+// Tokens are the architecture and OS tokens of the job and tab names, e.g.
+// kind-master-arm64 or gce-cos-master-default, naming the platform variant
+// of a suite.
+var Tokens = []string{"arm64", "ppc64le", "s390x", "windows", "cos", "ubuntu"}
+
+// Split returns the suite of a tab name, the name without its platform
+// tokens, and its variant, the tokens joined, e.g. kind-master and arm64 for
+// kind-master-arm64, or Default without token.
+func Split(tabName string) (suite, variant string) {
+	var words, tokens []string
+	for _, word := range strings.Split(tabName, "-") {
+		if slices.Contains(Tokens, strings.ToLower(word)) {
+			tokens = append(tokens, strings.ToLower(word))
+			continue
+		}
+		words = append(words, word)
+	}
+	if len(tokens) == 0 {
+		return tabName, Default
+	}
+	return strings.Join(words, "-"), strings.Join(tokens, "-")
+}
+
+// Occurrence is another platform variant of the suite of a test, Failing
+// when the test is red or flaky on it too.
+type Occurrence struct {
+	Variant   string
+	BoardHash string
+	TabURL    string
+	Failing   bool
+}
+
+// Index groups the tabs by suite across their platform variants.
+type Index map[string][]*v1alpha1.DashboardTab
+
+// NewIndex indexes the tabs by suite.
+func NewIndex(tabs []*v1alpha1.DashboardTab) Index {
+	index := Index{}
+	for _, tab := range tabs {
+		suite, _ := Split(tabName(tab))
+		index[suite] = append(index[suite], tab)
+	}
+	return index
+}
+
+// Variants returns the other platform variants of the suite of the tab among
+// the indexed tabs, sorted by variant, and whether the test fails on them.
+// The variants passing every test are not fetched, so a variant only counts
+// as green when its tab fails or flakes on other tests.
+func (i Index) Variants(tab *v1alpha1.DashboardTab, test *v1alpha1.TestResult) (occurrences []Occurrence) {
+	suite, variant := Split(tabName(tab))
+	seen := map[string]bool{}
+	for _, other := range i[suite] {
+		_, otherVariant := Split(tabName(other))
+		if otherVariant == variant || seen[other.BoardHash] {
+			continue
+		}
+		seen[other.BoardHash] = true
+		occurrences = append(occurrences, Occurrence{
+			Variant:   otherVariant,
+			BoardHash: other.BoardHash,
+			TabURL:    other.TabURL,
+			Failing:   hasTest(other, test.TestName),
+		})
+	}
+	sort.SliceStable(occurrences, func(a, b int) bool {
+		return occurrences[a].Variant < occurrences[b].Variant
+	})
+	return occurrences
+}
+
+// Hint summarizes the variants the test fails on, e.g. "fails only on
+// arm64, green on default", or an empty string without other variant.
+func Hint(variant string, occurrences []Occurrence) string {
+	if len(occurrences) == 0 {
+		return ""
+	}
+	failing, green := []string{variant}, []string{}
+	for _, occurrence := range occurrences {
+		if occurrence.Failing {
+			failing = append(failing, occurrence.Variant)
+		} else {
+			green = append(green, occurrence.Variant)
+		}
+	}
+	failing, green = unique(failing), unique(green)
+	if len(green) == 0 {
+		return "fails on every variant, " + strings.Join(failing, ", ") + ", not platform specific"
+	}
+	return "fails only on " + strings.Join(failing, ", ") + ", green on " + strings.Join(green, ", ")
+}
+
+// Variant returns the platform variant of the tab.
+func Variant(tab *v1alpha1.DashboardTab) string {
+	_, variant := Split(tabName(tab))
+	return variant
+}
+
+// tabName returns the tab name of the board hash, e.g. kind-master-arm64
+// for sig-release-master-informing#kind-master-arm64.
+func tabName(tab *v1alpha1.DashboardTab) string {
+	if _, name, found := strings.Cut(tab.BoardHash, "#"); found {
+		return name
+	}
+	return tab.TabName
+}
+
+func hasTest(tab *v1alpha1.DashboardTab, testName string) bool {
+	for _, test := range tab.TestRuns {
+		if test.TestName == testName {
+			return true
+		}
+	}
+	return false
+}
+
+func unique(values []string) []string {
+	slices.Sort(values)
+	return slices.Compact(values)
+}
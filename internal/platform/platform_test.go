@@ -0,0 +1,50 @@
+package platform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		tabName, suite, variant string
+	}{
+		{"kind-master-arm64", "kind-master", "arm64"},
+		{"kind-master", "kind-master", Default},
+		{"gce-cos-master-default", "gce-master-default", "cos"},
+		{"gce-ubuntu-master-default", "gce-master-default", "ubuntu"},
+		{"capz-windows-master-arm64", "capz-master", "windows-arm64"},
+	}
+	for _, tt := range tests {
+		suite, variant := Split(tt.tabName)
+		assert.Equal(t, tt.suite, suite, tt.tabName)
+		assert.Equal(t, tt.variant, variant, tt.tabName)
+	}
+}
+
+func TestVariants(t *testing.T) {
+	failure := v1alpha1.TestResult{TestName: "[sig-node] Pods"}
+	tabs := []*v1alpha1.DashboardTab{
+		{BoardHash: "sig-release-master-informing#kind-master-arm64", TestRuns: []v1alpha1.TestResult{failure}},
+		{BoardHash: "sig-release-master-blocking#kind-master", TestRuns: []v1alpha1.TestResult{{TestName: "other"}}},
+		{BoardHash: "sig-release-master-informing#kind-master-ppc64le", TestRuns: []v1alpha1.TestResult{failure}},
+		{BoardHash: "sig-release-master-blocking#gce-cos-master-default", TestRuns: []v1alpha1.TestResult{failure}},
+	}
+	index := NewIndex(tabs)
+
+	occurrences := index.Variants(tabs[0], &failure)
+	assert.Equal(t, []Occurrence{
+		{Variant: Default, BoardHash: "sig-release-master-blocking#kind-master"},
+		{Variant: "ppc64le", BoardHash: "sig-release-master-informing#kind-master-ppc64le", Failing: true},
+	}, occurrences)
+	assert.Equal(t, "fails only on arm64, ppc64le, green on default", Hint("arm64", occurrences))
+
+	occurrences = index.Variants(tabs[2], &failure)
+	assert.Equal(t, "fails on every variant, arm64, ppc64le, not platform specific", Hint("ppc64le", occurrences[:1]))
+
+	assert.Empty(t, index.Variants(tabs[3], &failure))
+	assert.Empty(t, Hint("cos", nil))
+}
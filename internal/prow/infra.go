@@ -0,0 +1,89 @@
+package prow
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// infraKeys are the metadata keys holding each infrastructure field, as
+// written by kubetest in finished.json and by kubetest2 in metadata.json.
+var infraKeys = struct {
+	zone, image, instanceType []string
+}{
+	zone:         []string{"zone", "gce-zone", "gcp-zone"},
+	image:        []string{"node_os_image", "node-os-image", "node_image", "node-image", "image"},
+	instanceType: []string{"instance-type", "instance_type", "machine-type", "machine_type", "node-size", "node_size"},
+}
+
+// Infrastructure is the infrastructure a job run ran on, the fields missing
+// from the run metadata are empty.
+type Infrastructure struct {
+	URL          string
+	Zone         string
+	Image        string
+	InstanceType string
+}
+
+// finishedJSON serializes the finished.json object of a job run.
+type finishedJSON struct {
+	Revision string                 `json:"revision"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+// GetInfrastructure reads the zone, node image and instance type of the job
+// run from the metadata of finished.json and artifacts/metadata.json, the
+// first one found wins. Missing objects are not an error.
+func (t *Prow) GetInfrastructure() (*Infrastructure, error) {
+	bucket, prefix, err := splitGCSPath(t.ProwURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var finished finishedJSON
+	if err := getJSONObject(fmt.Sprintf("%s/%s/%s/finished.json", GCSURL, bucket, prefix), &finished); err != nil {
+		return nil, err
+	}
+	var metadata map[string]interface{}
+	if err := getJSONObject(fmt.Sprintf("%s/%s/%s/artifacts/metadata.json", GCSURL, bucket, prefix), &metadata); err != nil {
+		return nil, err
+	}
+
+	lookup := func(keys []string) string {
+		for _, values := range []map[string]interface{}{finished.Metadata, metadata} {
+			for _, key := range keys {
+				if value, ok := values[key].(string); ok && value != "" {
+					return value
+				}
+			}
+		}
+		return ""
+	}
+	return &Infrastructure{
+		URL:          t.ProwURL,
+		Zone:         lookup(infraKeys.zone),
+		Image:        lookup(infraKeys.image),
+		InstanceType: lookup(infraKeys.instanceType),
+	}, nil
+}
+
+// getJSONObject decodes the JSON object at url into v, leaving v unchanged
+// when the object doesn't exist.
+func getJSONObject(url string, v interface{}) error {
+	response, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close() // nolint
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		if err := json.NewDecoder(response.Body).Decode(v); err != nil {
+			return fmt.Errorf("error unmarshaling %s: %v", url, err)
+		}
+		return nil
+	case http.StatusNotFound:
+		return nil
+	}
+	return fmt.Errorf("error fetching %s: %s", url, response.Status)
+}
@@ -0,0 +1,42 @@
+package prow
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetInfrastructure(t *testing.T) {
+	objects := map[string]string{
+		"/bucket/" + jobPath + "/finished.json":           `{"passed": false, "metadata": {"node_os_image": "cos-117-18613-0-79", "job-version": "v1.35.0", "zone": ""}}`,
+		"/bucket/" + jobPath + "/artifacts/metadata.json": `{"zone": "us-central1-b", "image": "ubuntu", "machine-type": "e2-standard-4", "nodes": 3}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		object, ok := objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, object) // nolint
+	}))
+	defer server.Close()
+	setGCSURL(t, server.URL)
+
+	jobURL := "https://prow.k8s.io/view/gs/bucket/" + jobPath
+	infra, err := NewProw(jobURL).GetInfrastructure()
+	assert.NoError(t, err)
+	// finished.json wins, empty values fall through to metadata.json
+	assert.Equal(t, &Infrastructure{URL: jobURL, Zone: "us-central1-b", Image: "cos-117-18613-0-79", InstanceType: "e2-standard-4"}, infra)
+
+	delete(objects, "/bucket/"+jobPath+"/artifacts/metadata.json")
+	infra, err = NewProw(jobURL).GetInfrastructure()
+	assert.NoError(t, err)
+	assert.Equal(t, &Infrastructure{URL: jobURL, Image: "cos-117-18613-0-79"}, infra)
+
+	objects["/bucket/"+jobPath+"/finished.json"] = "not json"
+	_, err = NewProw(jobURL).GetInfrastructure()
+	assert.Error(t, err)
+}
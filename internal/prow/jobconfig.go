@@ -0,0 +1,171 @@
+package prow
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+)
+
+// Job config annotations read by TestGrid for alerting and retention.
+const (
+	AnnotationNumFailuresToAlert = "testgrid-num-failures-to-alert"
+	AnnotationAlertEmail         = "testgrid-alert-email"
+	AnnotationDaysOfResults      = "testgrid-days-of-results"
+	AnnotationDashboards         = "testgrid-dashboards"
+)
+
+// minDaysOfResults is the shortest history that still allows spotting a
+// flake pattern across a release cycle milestone.
+const minDaysOfResults = 14
+
+// ProwJob serializes the prowjob.json stored in the artifacts of each run,
+// it carries the annotations of the job config at the time of the run.
+type ProwJob struct {
+	Metadata struct {
+		Annotations map[string]string `json:"annotations"`
+	} `json:"metadata"`
+	Spec struct {
+		Job     string `json:"job"`
+		Type    string `json:"type"`
+		PodSpec struct {
+			Containers []Container `json:"containers"`
+		} `json:"pod_spec"`
+	} `json:"spec"`
+}
+
+// Container is a container of the job pod, only the fields carrying the test
+// selection are kept.
+type Container struct {
+	Command []string `json:"command"`
+	Args    []string `json:"args"`
+	Env     []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"env"`
+}
+
+// LintIssue is a signal-quality problem found in a job config.
+type LintIssue struct {
+	Annotation string
+	Message    string
+}
+
+// GetProwJob returns the job config used by the run of the Prow URL.
+func (t *Prow) GetProwJob() (*ProwJob, error) {
+	bucket, prefix, err := splitGCSPath(t.ProwURL)
+	if err != nil {
+		return nil, err
+	}
+	response, err := http.Get(fmt.Sprintf("%s/%s/%s/prowjob.json", GCSURL, bucket, prefix))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close() // nolint
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching prowjob.json: %s", response.Status)
+	}
+
+	var job ProwJob
+	if err := json.NewDecoder(response.Body).Decode(&job); err != nil {
+		return nil, fmt.Errorf("error unmarshaling prowjob.json: %v", err)
+	}
+	return &job, nil
+}
+
+// Lint checks the job annotations for the common causes of missed signal.
+func (j *ProwJob) Lint() []LintIssue {
+	var issues []LintIssue
+	annotations := j.Metadata.Annotations
+
+	if value, ok := annotations[AnnotationNumFailuresToAlert]; !ok {
+		issues = append(issues, LintIssue{AnnotationNumFailuresToAlert, "not set, failures never alert"})
+	} else if num, err := strconv.Atoi(value); err != nil || num <= 0 {
+		issues = append(issues, LintIssue{AnnotationNumFailuresToAlert, fmt.Sprintf("invalid value %q", value)})
+	}
+
+	if annotations[AnnotationAlertEmail] == "" {
+		issues = append(issues, LintIssue{AnnotationAlertEmail, "not set, nobody is notified on failures"})
+	}
+
+	if value, ok := annotations[AnnotationDaysOfResults]; ok {
+		if days, err := strconv.Atoi(value); err != nil {
+			issues = append(issues, LintIssue{AnnotationDaysOfResults, fmt.Sprintf("invalid value %q", value)})
+		} else if days < minDaysOfResults {
+			issues = append(issues, LintIssue{AnnotationDaysOfResults, fmt.Sprintf("%d days of history is too short, use at least %d", days, minDaysOfResults)})
+		}
+	}
+	return issues
+}
+
+// filterFlagRegex matches the Ginkgo focus and skip flags of the job args,
+// also when they are nested in a --test_args value.
+var filterFlagRegex = regexp.MustCompile(`--(?:ginkgo\.)?(focus|skip)(?:-regex)?=(?:"([^"]*)"|'([^']*)'|(\S+))`)
+
+// filterEnvs are the environment variables of the e2e runners carrying the
+// Ginkgo focus and skip regexes.
+var filterEnvs = map[string]string{
+	"FOCUS":        "focus",
+	"GINKGO_FOCUS": "focus",
+	"FOCUS_REGEX":  "focus",
+	"SKIP":         "skip",
+	"GINKGO_SKIP":  "skip",
+	"SKIP_REGEX":   "skip",
+}
+
+// TestFilter is the Ginkgo test selection of a job config.
+type TestFilter struct {
+	Focus []*regexp.Regexp
+	Skip  []*regexp.Regexp
+}
+
+// TestFilter returns the focus and skip regexes set in the container args
+// and environment, invalid regexes are ignored.
+func (j *ProwJob) TestFilter() *TestFilter {
+	filter := &TestFilter{}
+	add := func(kind, expression string) {
+		re, err := regexp.Compile(expression)
+		if expression == "" || err != nil {
+			return
+		}
+		if kind == "focus" {
+			filter.Focus = append(filter.Focus, re)
+		} else {
+			filter.Skip = append(filter.Skip, re)
+		}
+	}
+
+	for _, container := range j.Spec.PodSpec.Containers {
+		for _, arg := range append(append([]string{}, container.Command...), container.Args...) {
+			for _, match := range filterFlagRegex.FindAllStringSubmatch(arg, -1) {
+				add(match[1], match[2]+match[3]+match[4])
+			}
+		}
+		for _, env := range container.Env {
+			if kind, ok := filterEnvs[env.Name]; ok {
+				add(kind, env.Value)
+			}
+		}
+	}
+	return filter
+}
+
+// Excludes returns true when the test is skipped, or not focused when the
+// job focuses on a subset of the tests.
+func (f *TestFilter) Excludes(testName string) bool {
+	for _, re := range f.Skip {
+		if re.MatchString(testName) {
+			return true
+		}
+	}
+	if len(f.Focus) == 0 {
+		return false
+	}
+	for _, re := range f.Focus {
+		if re.MatchString(testName) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package prow
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetProwJob(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/bucket/"+jobPath+"/prowjob.json", r.URL.Path)
+		fmt.Fprint(w, `{"metadata": {"annotations": {"testgrid-alert-email": "sig-node@kubernetes.io"}},
+			"spec": {"job": "ci-kubernetes-node-e2e-containerd", "type": "periodic"}}`) // nolint
+	}))
+	defer server.Close()
+	setGCSURL(t, server.URL)
+
+	job, err := NewProw("https://prow.k8s.io/view/gs/bucket/" + jobPath).GetProwJob()
+	assert.NoError(t, err)
+	assert.Equal(t, "ci-kubernetes-node-e2e-containerd", job.Spec.Job)
+	assert.Equal(t, "sig-node@kubernetes.io", job.Metadata.Annotations[AnnotationAlertEmail])
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		issues      []string
+	}{
+		{
+			name: "well configured job",
+			annotations: map[string]string{
+				AnnotationNumFailuresToAlert: "3",
+				AnnotationAlertEmail:         "release-team@kubernetes.io",
+				AnnotationDaysOfResults:      "30",
+			},
+		},
+		{
+			name:        "missing alerting",
+			annotations: map[string]string{},
+			issues:      []string{AnnotationNumFailuresToAlert, AnnotationAlertEmail},
+		},
+		{
+			name: "invalid threshold and short history",
+			annotations: map[string]string{
+				AnnotationNumFailuresToAlert: "0",
+				AnnotationAlertEmail:         "release-team@kubernetes.io",
+				AnnotationDaysOfResults:      "7",
+			},
+			issues: []string{AnnotationNumFailuresToAlert, AnnotationDaysOfResults},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &ProwJob{}
+			job.Metadata.Annotations = tt.annotations
+			var annotations []string
+			for _, issue := range job.Lint() {
+				annotations = append(annotations, issue.Annotation)
+			}
+			assert.Equal(t, tt.issues, annotations)
+		})
+	}
+}
+
+func TestTestFilter(t *testing.T) {
+	var job ProwJob
+	assert.NoError(t, json.Unmarshal([]byte(`{"spec": {"pod_spec": {"containers": [{
+		"command": ["runner.sh"],
+		"args": ["--test_args=--ginkgo.focus=\\[sig-node\\] --ginkgo.skip=\"\\[Serial\\]|\\[Flaky\\]\"", "--timeout=120m"],
+		"env": [{"name": "SKIP", "value": "Pods should be removed"}, {"name": "PATH", "value": "/bin"}]
+	}]}}}`), &job))
+
+	filter := job.TestFilter()
+	assert.Len(t, filter.Focus, 1)
+	assert.Len(t, filter.Skip, 2)
+
+	tests := map[string]bool{
+		"Kubernetes e2e suite.[It] [sig-node] Pods should run":                 false,
+		"Kubernetes e2e suite.[It] [sig-node] Pods should be removed":          true,
+		"Kubernetes e2e suite.[It] [sig-node] [Serial] Pods should be evicted": true,
+		"Kubernetes e2e suite.[It] [sig-network] DNS should resolve":           true,
+	}
+	for testName, excluded := range tests {
+		assert.Equal(t, excluded, filter.Excludes(testName), testName)
+	}
+	assert.False(t, (&TestFilter{}).Excludes("anything"))
+}
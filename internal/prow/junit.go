@@ -0,0 +1,174 @@
+package prow
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxJUnitBytes limits the size of a JUnit file read from the artifacts, the
+// e2e suites write a few megabytes.
+const maxJUnitBytes = 64 * 1024 * 1024
+
+// JUnitResult is the result of a test case in the JUnit files of a run.
+type JUnitResult struct {
+	// File is the JUnit file name in the artifacts, e.g. junit_01.xml.
+	File string
+	URL  string
+
+	Failed   bool
+	Skipped  bool
+	Duration time.Duration
+
+	// Message is the failure message attribute, Failure the failure body
+	// with the stack trace.
+	Message string
+	Failure string
+
+	// SystemOut is the tail of the output captured for the test.
+	SystemOut string
+}
+
+// junitTestCase serializes a testcase element of a JUnit file.
+type junitTestCase struct {
+	Name      string `xml:"name,attr"`
+	ClassName string `xml:"classname,attr"`
+	Time      string `xml:"time,attr"`
+	Failure   *struct {
+		Message string `xml:"message,attr"`
+		Text    string `xml:",chardata"`
+	} `xml:"failure"`
+	Error *struct {
+		Message string `xml:"message,attr"`
+		Text    string `xml:",chardata"`
+	} `xml:"error"`
+	Skipped   *struct{} `xml:"skipped"`
+	SystemOut string    `xml:"system-out"`
+}
+
+// GetJUnitResult lists the junit_*.xml files of the job artifacts and returns
+// the result of the test, a failed result first when the test ran several
+// times, e.g. in a retried suite. It returns nil when no file has the test.
+func (t *Prow) GetJUnitResult(testName string) (*JUnitResult, error) {
+	bucket, prefix, err := splitGCSPath(t.ProwURL)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := listGCSObjects(bucket, prefix+"/artifacts/")
+	if err != nil {
+		return nil, err
+	}
+
+	var found *JUnitResult
+	for _, object := range objects {
+		if !isJUnitFile(object) {
+			continue
+		}
+		url := fmt.Sprintf("%s/%s/%s", GCSURL, bucket, object)
+		result, err := getJUnitResult(url, testName)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			continue
+		}
+		result.File = strings.TrimPrefix(object, prefix+"/artifacts/")
+		if result.Failed {
+			return result, nil
+		}
+		if found == nil {
+			found = result
+		}
+	}
+	return found, nil
+}
+
+// isJUnitFile returns true for the JUnit files of the artifacts, e.g.
+// junit_01.xml or junit_runner.xml.
+func isJUnitFile(name string) bool {
+	base := strings.ToLower(path.Base(name))
+	return strings.HasPrefix(base, "junit") && strings.HasSuffix(base, ".xml")
+}
+
+// getJUnitResult streams the JUnit file and returns the result of the test,
+// a failed result first, nil when the file doesn't have the test.
+func getJUnitResult(url, testName string) (*JUnitResult, error) {
+	response, err := http.Get(url) // nolint
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close() // nolint
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching %s: %s", url, response.Status)
+	}
+	return parseJUnit(io.LimitReader(response.Body, maxJUnitBytes), url, testName)
+}
+
+// parseJUnit returns the result of the test in the JUnit document, the test
+// cases are matched wherever they are nested in the test suites.
+func parseJUnit(reader io.Reader, url, testName string) (*JUnitResult, error) {
+	var found *JUnitResult
+	decoder := xml.NewDecoder(reader)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return found, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %v", url, err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "testcase" {
+			continue
+		}
+		var testCase junitTestCase
+		if err := decoder.DecodeElement(&testCase, &start); err != nil {
+			return nil, fmt.Errorf("error parsing %s: %v", url, err)
+		}
+		if !testCase.matches(testName) {
+			continue
+		}
+		result := testCase.result(url)
+		if result.Failed {
+			return result, nil
+		}
+		if found == nil {
+			found = result
+		}
+	}
+}
+
+// matches returns true for the test case of the TestGrid test name, made
+// of the class name and the case name for some suites, e.g.
+// "Kubernetes e2e suite.[It] [sig-node] Pods" or "kubetest.Up".
+func (c *junitTestCase) matches(testName string) bool {
+	return c.Name == testName ||
+		(c.ClassName != "" && (c.ClassName+"."+c.Name == testName || c.ClassName+" "+c.Name == testName))
+}
+
+func (c *junitTestCase) result(url string) *JUnitResult {
+	result := &JUnitResult{URL: url, Skipped: c.Skipped != nil, SystemOut: tail(strings.TrimSpace(c.SystemOut), maxLogBytes)}
+	if seconds, err := strconv.ParseFloat(c.Time, 64); err == nil {
+		result.Duration = time.Duration(seconds * float64(time.Second))
+	}
+	switch {
+	case c.Failure != nil:
+		result.Failed, result.Message, result.Failure = true, c.Failure.Message, strings.TrimSpace(c.Failure.Text)
+	case c.Error != nil:
+		result.Failed, result.Message, result.Failure = true, c.Error.Message, strings.TrimSpace(c.Error.Text)
+	}
+	return result
+}
+
+// tail returns the last size bytes of the text.
+func tail(text string, size int) string {
+	if len(text) <= size {
+		return text
+	}
+	return text[len(text)-size:]
+}
@@ -0,0 +1,79 @@
+package prow
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testName = "Kubernetes e2e suite.[It] [sig-node] Pods should run"
+
+func TestGetJUnitResult(t *testing.T) {
+	objects := map[string]string{
+		"/bucket/" + jobPath + "/artifacts/junit_01.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites><testsuite name="Kubernetes e2e suite">
+  <testcase name="[It] [sig-node] Pods should run" classname="Kubernetes e2e suite" time="12.5">
+    <system-out>passed on the first attempt</system-out>
+  </testcase>
+  <testcase name="[It] [sig-network] DNS" classname="Kubernetes e2e suite" time="1"><skipped message="skipped"/></testcase>
+</testsuite></testsuites>`,
+		"/bucket/" + jobPath + "/artifacts/junit_02.xml": `<testsuite>
+  <testcase name="[It] [sig-node] Pods should run" classname="Kubernetes e2e suite" time="30.25">
+    <failure message="timed out waiting for the pod" type="failed">pods.go:42
+timed out waiting for the pod</failure>
+    <system-out>pod pending</system-out>
+  </testcase>
+</testsuite>`,
+		"/bucket/" + jobPath + "/artifacts/build-log.txt": "not a junit file",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/storage/v1/b/bucket/o") {
+			var items []string
+			for object := range objects {
+				items = append(items, fmt.Sprintf(`{"name": %q}`, strings.TrimPrefix(object, "/bucket/")))
+			}
+			fmt.Fprintf(w, `{"items": [%s]}`, strings.Join(items, ",")) // nolint
+			return
+		}
+		object, ok := objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, object) // nolint
+	}))
+	defer server.Close()
+	setGCSURL(t, server.URL)
+
+	jobURL := "https://prow.k8s.io/view/gs/bucket/" + jobPath
+	result, err := NewProw(jobURL).GetJUnitResult(testName)
+	assert.NoError(t, err)
+	// the failed attempt wins over the passed one
+	assert.Equal(t, &JUnitResult{
+		File:      "junit_02.xml",
+		URL:       server.URL + "/bucket/" + jobPath + "/artifacts/junit_02.xml",
+		Failed:    true,
+		Duration:  30250 * time.Millisecond,
+		Message:   "timed out waiting for the pod",
+		Failure:   "pods.go:42\ntimed out waiting for the pod",
+		SystemOut: "pod pending",
+	}, result)
+
+	result, err = NewProw(jobURL).GetJUnitResult("[It] [sig-network] DNS")
+	assert.NoError(t, err)
+	assert.True(t, result.Skipped)
+	assert.False(t, result.Failed)
+
+	result, err = NewProw(jobURL).GetJUnitResult("[sig-apps] Deployment")
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+
+	objects["/bucket/"+jobPath+"/artifacts/junit_03.xml"] = "<testsuite><testcase"
+	_, err = NewProw(jobURL).GetJUnitResult("[sig-apps] Deployment")
+	assert.Error(t, err)
+}
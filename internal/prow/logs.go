@@ -0,0 +1,214 @@
+package prow
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var GCSURL = "https://storage.googleapis.com"
+
+// maxLogBytes limits the amount of bytes fetched from the tail of each stream.
+const maxLogBytes = 64 * 1024
+
+// maxLogFetches limits the log objects fetched concurrently.
+const maxLogFetches = 4
+
+const (
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
+	StreamPodLog = "pod-log"
+)
+
+// LogStream holds a single log stream found in the job artifacts.
+type LogStream struct {
+	Name    string
+	Kind    string
+	URL     string
+	Content string
+
+	// Err is set when the stream was listed but its content couldn't be fetched.
+	Err error
+}
+
+// gcsObjectList serializes a page of the GCS JSON API object listing.
+type gcsObjectList struct {
+	Items []struct {
+		Name string `json:"name"`
+	} `json:"items"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// GetLogStreams lists the job artifacts in GCS and returns the stdout/stderr
+// streams and pod logs stored separately from the interleaved build log.
+func (t *Prow) GetLogStreams() ([]LogStream, error) {
+	bucket, prefix, err := splitGCSPath(t.ProwURL)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := listGCSObjects(bucket, prefix+"/artifacts/")
+	if err != nil {
+		return nil, err
+	}
+
+	var streams []LogStream
+	for _, object := range objects {
+		if kind := classifyStream(object); kind != "" {
+			streams = append(streams, LogStream{
+				Name: strings.TrimPrefix(object, prefix+"/artifacts/"),
+				Kind: kind,
+				URL:  fmt.Sprintf("%s/%s/%s", GCSURL, bucket, object),
+			})
+		}
+	}
+
+	// fetch the streams concurrently, a single unreadable object must not hide
+	// the other streams so the failure is kept in the stream for the caller.
+	var (
+		wg        sync.WaitGroup
+		semaphore = make(chan struct{}, maxLogFetches)
+	)
+	for i := range streams {
+		wg.Add(1)
+		go func(stream *LogStream) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			stream.Content, stream.Err = getLogTail(stream.URL)
+		}(&streams[i])
+	}
+	wg.Wait()
+
+	// stdout first, then stderr and pod logs, alphabetically inside each kind.
+	order := map[string]int{StreamStdout: 0, StreamStderr: 1, StreamPodLog: 2}
+	sort.SliceStable(streams, func(i, j int) bool {
+		if streams[i].Kind != streams[j].Kind {
+			return order[streams[i].Kind] < order[streams[j].Kind]
+		}
+		return streams[i].Name < streams[j].Name
+	})
+	return streams, nil
+}
+
+// listGCSObjects returns the names of all objects under prefix, following
+// the listing pages.
+func listGCSObjects(bucket, prefix string) ([]string, error) {
+	var (
+		names     []string
+		pageToken string
+	)
+	for {
+		query := url.Values{}
+		query.Set("prefix", prefix)
+		query.Set("fields", "items(name),nextPageToken")
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		listURL := fmt.Sprintf("%s/storage/v1/b/%s/o?%s", GCSURL, url.PathEscape(bucket), query.Encode())
+		body, err := getHTTPResponse(listURL)
+		if err != nil {
+			return nil, err
+		}
+
+		var objects gcsObjectList
+		if err := json.NewDecoder(body).Decode(&objects); err != nil {
+			return nil, fmt.Errorf("error unmarshaling artifacts list: %v", err)
+		}
+		for _, object := range objects.Items {
+			names = append(names, object.Name)
+		}
+		if objects.NextPageToken == "" {
+			return names, nil
+		}
+		pageToken = objects.NextPageToken
+	}
+}
+
+// splitGCSPath extracts the bucket and object prefix from a Prow job view URL,
+// e.g. https://prow.k8s.io/view/gs/<bucket>/logs/<job>/<build>.
+func splitGCSPath(prowURL string) (bucket, prefix string, err error) {
+	_, gcsPath, found := strings.Cut(prowURL, "/view/gs/")
+	if !found {
+		return "", "", fmt.Errorf("not a prow GCS job URL: %s", prowURL)
+	}
+	bucket, prefix, found = strings.Cut(strings.Trim(gcsPath, "/"), "/")
+	if !found || prefix == "" {
+		return "", "", fmt.Errorf("missing job path in prow URL: %s", prowURL)
+	}
+	return bucket, prefix, nil
+}
+
+// classifyStream returns the stream kind of an artifact, or an empty string
+// when the artifact is not a log stream.
+func classifyStream(name string) string {
+	base := strings.ToLower(path.Base(name))
+	switch {
+	case strings.Contains(base, "stdout"):
+		return StreamStdout
+	case strings.Contains(base, "stderr"):
+		return StreamStderr
+	case strings.HasSuffix(base, ".log") && (strings.Contains(name, "/pod-logs/") || strings.Contains(name, "/pods/")):
+		return StreamPodLog
+	}
+	return ""
+}
+
+// getLogTail returns the last maxLogBytes of a log object. The tail is
+// requested with a suffix range, servers ignoring the range answer with the
+// full object and only its tail is kept.
+func getLogTail(url string) (string, error) {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Range", fmt.Sprintf("bytes=-%d", maxLogBytes))
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close() // nolint
+
+	switch response.StatusCode {
+	case http.StatusPartialContent:
+		data, err := io.ReadAll(io.LimitReader(response.Body, maxLogBytes))
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	case http.StatusOK:
+		return readTail(response.Body, maxLogBytes)
+	case http.StatusRequestedRangeNotSatisfiable:
+		// a suffix range is only unsatisfiable on empty objects.
+		return "", nil
+	}
+	return "", fmt.Errorf("error fetching %s: %s", url, response.Status)
+}
+
+// readTail reads the whole reader and returns its last size bytes.
+func readTail(reader io.Reader, size int) (string, error) {
+	var (
+		tail []byte
+		buf  = make([]byte, 32*1024)
+	)
+	for {
+		n, err := reader.Read(buf)
+		tail = append(tail, buf[:n]...)
+		if len(tail) > size {
+			tail = append(tail[:0], tail[len(tail)-size:]...)
+		}
+		if err == io.EOF {
+			return string(tail), nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
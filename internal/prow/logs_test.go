@@ -0,0 +1,168 @@
+package prow
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const jobPath = "logs/ci-kubernetes-node-e2e-containerd/1855351637342687232"
+
+func TestGetLogStreams(t *testing.T) {
+	pages := map[string][]string{
+		"": {
+			jobPath + "/artifacts/junit_01.xml",
+			jobPath + "/artifacts/pod-logs/kube-apiserver.log",
+		},
+		"page-2": {
+			jobPath + "/artifacts/pod-logs/etcd.log",
+			jobPath + "/artifacts/test-stderr.txt",
+			jobPath + "/artifacts/test-stdout.txt",
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/storage/v1/b/bucket/o") {
+			assert.Equal(t, jobPath+"/artifacts/", r.URL.Query().Get("prefix"))
+			pageToken := r.URL.Query().Get("pageToken")
+			var items []string
+			for _, object := range pages[pageToken] {
+				items = append(items, fmt.Sprintf(`{"name": %q}`, object))
+			}
+			nextPageToken := ""
+			if pageToken == "" {
+				nextPageToken = "page-2"
+			}
+			fmt.Fprintf(w, `{"items": [%s], "nextPageToken": %q}`, strings.Join(items, ","), nextPageToken) // nolint
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "etcd.log") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprintf(w, "content of %s", r.URL.Path) // nolint
+	}))
+	defer server.Close()
+	setGCSURL(t, server.URL)
+
+	streams, err := NewProw("https://prow.k8s.io/view/gs/bucket/" + jobPath).GetLogStreams()
+	assert.NoError(t, err)
+	assert.Len(t, streams, 4)
+
+	assert.Equal(t, StreamStdout, streams[0].Kind)
+	assert.Equal(t, "test-stdout.txt", streams[0].Name)
+	assert.Equal(t, StreamStderr, streams[1].Kind)
+	assert.Equal(t, StreamPodLog, streams[2].Kind)
+	assert.Equal(t, "pod-logs/etcd.log", streams[2].Name)
+	assert.Error(t, streams[2].Err)
+	assert.Equal(t, "pod-logs/kube-apiserver.log", streams[3].Name)
+	assert.NoError(t, streams[3].Err)
+	assert.Contains(t, streams[3].Content, "kube-apiserver.log")
+}
+
+func TestGetLogTail(t *testing.T) {
+	content := strings.Repeat("a", maxLogBytes) + "tail"
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "partial content",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, fmt.Sprintf("bytes=-%d", maxLogBytes), r.Header.Get("Range"))
+				w.WriteHeader(http.StatusPartialContent)
+				fmt.Fprint(w, content[len(content)-maxLogBytes:]) // nolint
+			},
+			want: content[len(content)-maxLogBytes:],
+		},
+		{
+			name: "range ignored by the server",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, content) // nolint
+			},
+			want: content[len(content)-maxLogBytes:],
+		},
+		{
+			name: "empty object",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+				fmt.Fprint(w, "InvalidRange") // nolint
+			},
+			want: "",
+		},
+		{
+			name: "not found",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			got, err := getLogTail(server.URL)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// setGCSURL points the GCS endpoint to url for the duration of the test.
+func setGCSURL(t *testing.T, url string) {
+	previous := GCSURL
+	GCSURL = url
+	t.Cleanup(func() { GCSURL = previous })
+}
+
+func TestSplitGCSPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		bucket  string
+		prefix  string
+		wantErr bool
+	}{
+		{
+			name:   "prow view URL",
+			url:    "https://prow.k8s.io/view/gs/kubernetes-ci-logs/" + jobPath,
+			bucket: "kubernetes-ci-logs",
+			prefix: jobPath,
+		},
+		{
+			name:    "not a prow URL",
+			url:     "https://testgrid.k8s.io/sig-release-master-blocking",
+			wantErr: true,
+		},
+		{
+			name:    "bucket without path",
+			url:     "https://prow.k8s.io/view/gs/kubernetes-ci-logs/",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket, prefix, err := splitGCSPath(tt.url)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.bucket, bucket)
+			assert.Equal(t, tt.prefix, prefix)
+		})
+	}
+}
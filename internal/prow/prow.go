@@ -27,6 +27,11 @@ type BuildLog struct {
 
 type ProwInterface interface {
 	GetSpyGlassLens() (*BuildLog, error)
+	GetLogStreams() ([]LogStream, error)
+	GetProwJob() (*ProwJob, error)
+	GetInfrastructure() (*Infrastructure, error)
+	GetRevision() (string, error)
+	GetJUnitResult(testName string) (*JUnitResult, error)
 }
 
 func NewProw(prowUrl string) ProwInterface {
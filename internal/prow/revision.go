@@ -0,0 +1,51 @@
+package prow
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// revisionKeys are the metadata keys of finished.json holding the revision
+// of the tested code when the revision field is empty, e.g. in the runs of
+// the bootstrap jobs.
+var revisionKeys = []string{"repo-commit", "job-version", "revision"}
+
+var shaRegex = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// GetRevision returns the commit SHA of the code tested by the job run, read
+// from finished.json, or an empty string when the run has none.
+func (t *Prow) GetRevision() (string, error) {
+	bucket, prefix, err := splitGCSPath(t.ProwURL)
+	if err != nil {
+		return "", err
+	}
+	var finished finishedJSON
+	if err := getJSONObject(fmt.Sprintf("%s/%s/%s/finished.json", GCSURL, bucket, prefix), &finished); err != nil {
+		return "", err
+	}
+	if sha := CommitSHA(finished.Revision); sha != "" {
+		return sha, nil
+	}
+	for _, key := range revisionKeys {
+		if value, ok := finished.Metadata[key].(string); ok {
+			if sha := CommitSHA(value); sha != "" {
+				return sha, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// CommitSHA returns the commit SHA of a revision, either a SHA or a version
+// with the abbreviated SHA as build metadata, e.g.
+// v1.35.0-alpha.3.412+6f3c1b8e2a9d04, or an empty string.
+func CommitSHA(revision string) string {
+	if i := strings.LastIndex(revision, "+"); i >= 0 {
+		revision = revision[i+1:]
+	}
+	if shaRegex.MatchString(revision) {
+		return revision
+	}
+	return ""
+}
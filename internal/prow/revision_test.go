@@ -0,0 +1,44 @@
+package prow
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRevision(t *testing.T) {
+	finished := `{"passed": false, "revision": "6f3c1b8e2a9d04c7b1e0f5a3d2c4b6a8e9f0d1c2"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bucket/"+jobPath+"/finished.json" || finished == "" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, finished) // nolint
+	}))
+	defer server.Close()
+	setGCSURL(t, server.URL)
+
+	jobURL := "https://prow.k8s.io/view/gs/bucket/" + jobPath
+	revision, err := NewProw(jobURL).GetRevision()
+	assert.NoError(t, err)
+	assert.Equal(t, "6f3c1b8e2a9d04c7b1e0f5a3d2c4b6a8e9f0d1c2", revision)
+
+	// the bootstrap jobs only have the version of the build
+	finished = `{"passed": false, "metadata": {"job-version": "v1.35.0-alpha.3.412+6f3c1b8e2a9d04"}}`
+	revision, err = NewProw(jobURL).GetRevision()
+	assert.NoError(t, err)
+	assert.Equal(t, "6f3c1b8e2a9d04", revision)
+
+	finished = `{"passed": false, "metadata": {"job-version": "v1.35.0"}}`
+	revision, err = NewProw(jobURL).GetRevision()
+	assert.NoError(t, err)
+	assert.Empty(t, revision)
+
+	finished = ""
+	revision, err = NewProw(jobURL).GetRevision()
+	assert.NoError(t, err)
+	assert.Empty(t, revision)
+}
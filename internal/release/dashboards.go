@@ -0,0 +1,39 @@
+package release
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/signalhound/internal/fingerprint"
+)
+
+// versionRegex matches the minor version of a release, e.g. 1.32 or v1.32.
+var versionRegex = regexp.MustCompile(`^v?(\d+\.\d+)$`)
+
+// Dashboards returns the blocking and informing SIG Release dashboards of a
+// release branch, e.g. sig-release-1.32-blocking and
+// sig-release-1.32-informing for 1.32 or v1.32, master is accepted too.
+func Dashboards(version string) ([]string, error) {
+	branch := strings.TrimSpace(version)
+	if match := versionRegex.FindStringSubmatch(branch); match != nil {
+		branch = match[1]
+	} else if branch != fingerprint.Master {
+		return nil, fmt.Errorf("error parsing the release %q: expected a minor version like 1.32 or master", version)
+	}
+	return []string{
+		fmt.Sprintf("sig-release-%s-blocking", branch),
+		fmt.Sprintf("sig-release-%s-informing", branch),
+	}, nil
+}
+
+// Milestone returns the milestone of the release branch of a board hash,
+// e.g. v1.32 for sig-release-1.32-blocking, or an empty string for the
+// master and the non SIG Release dashboards.
+func Milestone(boardHash string) string {
+	branch := fingerprint.Branch(boardHash)
+	if branch == "" || branch == fingerprint.Master {
+		return ""
+	}
+	return "v" + branch
+}
@@ -0,0 +1,28 @@
+package release
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDashboards(t *testing.T) {
+	for _, version := range []string{"1.32", "v1.32"} {
+		dashboards, err := Dashboards(version)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"sig-release-1.32-blocking", "sig-release-1.32-informing"}, dashboards)
+	}
+
+	dashboards, err := Dashboards("master")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sig-release-master-blocking", "sig-release-master-informing"}, dashboards)
+
+	_, err = Dashboards("1.32.1")
+	assert.Error(t, err)
+}
+
+func TestMilestone(t *testing.T) {
+	assert.Equal(t, "v1.32", Milestone("sig-release-1.32-blocking#gce-cos-k8sbeta-default"))
+	assert.Empty(t, Milestone("sig-release-master-blocking#gce-cos-master-default"))
+	assert.Empty(t, Milestone("sig-node-release-blocking#node-kubelet"))
+}
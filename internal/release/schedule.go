@@ -0,0 +1,182 @@
+package release
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// ScheduleURL is the release schedule maintained by SIG Release.
+var ScheduleURL = "https://raw.githubusercontent.com/kubernetes/sig-release/master/releases/schedule.yaml"
+
+const (
+	PhaseNormal     = "Normal"
+	PhaseBurndown   = "Burndown"
+	PhaseCodeFreeze = "Code Freeze"
+)
+
+const (
+	// offsets from the release date of the freeze periods, the schedule file
+	// only carries the dates of releases already shipped.
+	burndownOffset   = 6 * 7 * 24 * time.Hour
+	codeFreezeOffset = 5 * 7 * 24 * time.Hour
+
+	// defaultCycleLength is used when the schedule has a single shipped release
+	// to estimate the cadence from.
+	defaultCycleLength = 16 * 7 * 24 * time.Hour
+
+	// fetchTimeout bounds the schedule download, it runs before the TUI starts.
+	fetchTimeout = 30 * time.Second
+)
+
+// Schedule serializes the patch release schedule file, releases/schedule.yaml.
+type Schedule struct {
+	Schedules        []Cycle        `json:"schedules"`
+	UpcomingReleases []PatchRelease `json:"upcoming_releases"`
+}
+
+// Cycle holds the dates of a shipped minor release and its patch releases.
+type Cycle struct {
+	Release                  string         `json:"release"`
+	ReleaseDate              string         `json:"releaseDate"`
+	MaintenanceModeStartDate string         `json:"maintenanceModeStartDate,omitempty"`
+	EndOfLifeDate            string         `json:"endOfLifeDate,omitempty"`
+	Next                     *PatchRelease  `json:"next,omitempty"`
+	PreviousPatches          []PatchRelease `json:"previousPatches,omitempty"`
+}
+
+// PatchRelease holds the dates of a single patch release.
+type PatchRelease struct {
+	Release            string `json:"release,omitempty"`
+	CherryPickDeadline string `json:"cherryPickDeadline,omitempty"`
+	TargetDate         string `json:"targetDate"`
+}
+
+// Phase is the release cycle phase at a given moment.
+type Phase struct {
+	Release     string
+	Name        string
+	ReleaseDate time.Time
+}
+
+// InFreeze returns true while the release is in burndown or code freeze.
+func (p *Phase) InFreeze() bool {
+	return p != nil && (p.Name == PhaseBurndown || p.Name == PhaseCodeFreeze)
+}
+
+// String renders the phase as "v1.35 Code Freeze".
+func (p *Phase) String() string {
+	if p == nil {
+		return ""
+	}
+	return fmt.Sprintf("v%s %s", p.Release, p.Name)
+}
+
+// FetchSchedule reads the release schedule from an URL or a local file path.
+func FetchSchedule(location string) (*Schedule, error) {
+	var data []byte
+	var err error
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		client := &http.Client{Timeout: fetchTimeout}
+		var response *http.Response
+		if response, err = client.Get(location); err != nil {
+			return nil, fmt.Errorf("error fetching release schedule: %v", err)
+		}
+		defer response.Body.Close() // nolint
+		if response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("error fetching release schedule: %s", response.Status)
+		}
+		if data, err = io.ReadAll(response.Body); err != nil {
+			return nil, fmt.Errorf("error parsing body response: %v", err)
+		}
+	} else if data, err = os.ReadFile(location); err != nil {
+		return nil, fmt.Errorf("error reading release schedule: %v", err)
+	}
+
+	var schedule Schedule
+	if err = yaml.Unmarshal(data, &schedule); err != nil {
+		return nil, fmt.Errorf("error unmarshaling release schedule: %v", err)
+	}
+	return &schedule, nil
+}
+
+// PhaseAt returns the phase of the minor release cycle in progress at the
+// given moment. The schedule only lists shipped releases, so the cycle in
+// progress is the minor after the latest shipped one, and its release date is
+// estimated from the cadence of the previous releases. Past the estimated
+// date the cycle is kept in code freeze until the release shows up.
+func (s *Schedule) PhaseAt(now time.Time) *Phase {
+	type shipped struct {
+		major, minor int
+		date         time.Time
+	}
+	var releases []shipped
+	for _, cycle := range s.Schedules {
+		releaseDate, err := parseDate(cycle.ReleaseDate)
+		if err != nil || releaseDate.After(now) {
+			continue
+		}
+		var major, minor int
+		if _, err := fmt.Sscanf(strings.TrimPrefix(cycle.Release, "v"), "%d.%d", &major, &minor); err != nil {
+			continue
+		}
+		releases = append(releases, shipped{major: major, minor: minor, date: releaseDate})
+	}
+	if len(releases) == 0 {
+		return nil
+	}
+	sort.Slice(releases, func(i, j int) bool { return releases[i].date.Before(releases[j].date) })
+
+	// average the length of the last three cycles.
+	recent := releases[max(0, len(releases)-4):]
+	latest := recent[len(recent)-1]
+	cycleLength := defaultCycleLength
+	if len(recent) > 1 {
+		cycleLength = latest.date.Sub(recent[0].date) / time.Duration(len(recent)-1)
+	}
+	releaseDate := latest.date.Add(cycleLength)
+
+	phase := PhaseNormal
+	switch {
+	case !now.Before(releaseDate.Add(-codeFreezeOffset)):
+		phase = PhaseCodeFreeze
+	case !now.Before(releaseDate.Add(-burndownOffset)):
+		phase = PhaseBurndown
+	}
+	return &Phase{
+		Release:     fmt.Sprintf("%d.%d", latest.major, latest.minor+1),
+		Name:        phase,
+		ReleaseDate: releaseDate,
+	}
+}
+
+// StrictThreshold lowers a minimum failure/flake threshold during freeze
+// phases, so every occurrence is surfaced. A disabled threshold (0) is kept.
+func (p *Phase) StrictThreshold(threshold int) int {
+	if p.InFreeze() && threshold > 1 {
+		return 1
+	}
+	return threshold
+}
+
+// Priority returns the issue priority label raised during freeze phases, or
+// an empty string outside freeze so the issue body is left unchanged.
+func (p *Phase) Priority(failing bool) string {
+	switch {
+	case !p.InFreeze():
+		return ""
+	case failing:
+		return "critical-urgent"
+	}
+	return "important-soon"
+}
+
+func parseDate(date string) (time.Time, error) {
+	return time.Parse(time.DateOnly, strings.TrimSpace(date))
+}
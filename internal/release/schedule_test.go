@@ -0,0 +1,78 @@
+package release
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchSchedule(t *testing.T) {
+	data, err := os.ReadFile("testdata/schedule.yaml")
+	assert.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	schedule, err := FetchSchedule(server.URL)
+	assert.NoError(t, err)
+	assert.Len(t, schedule.Schedules, 4)
+	assert.Equal(t, "1.34", schedule.Schedules[0].Release)
+	assert.Equal(t, "1.34.2", schedule.Schedules[0].Next.Release)
+	assert.Len(t, schedule.UpcomingReleases, 3)
+	assert.Equal(t, "2025-10-14", schedule.UpcomingReleases[0].TargetDate)
+}
+
+func TestPhaseAt(t *testing.T) {
+	schedule, err := FetchSchedule("testdata/schedule.yaml")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		now      string
+		release  string
+		phase    string
+		inFreeze bool
+	}{
+		{name: "early cycle", now: "2025-09-15", release: "1.35", phase: PhaseNormal},
+		{name: "burndown", now: "2025-11-20", release: "1.35", phase: PhaseBurndown, inFreeze: true},
+		{name: "code freeze", now: "2025-11-27", release: "1.35", phase: PhaseCodeFreeze, inFreeze: true},
+		{name: "overdue release stays in code freeze", now: "2026-01-15", release: "1.35", phase: PhaseCodeFreeze, inFreeze: true},
+		{name: "releases after now are ignored", now: "2025-05-01", release: "1.34", phase: PhaseNormal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now, _ := time.Parse(time.DateOnly, tt.now)
+			phase := schedule.PhaseAt(now)
+			assert.NotNil(t, phase)
+			assert.Equal(t, tt.release, phase.Release)
+			assert.Equal(t, tt.phase, phase.Name)
+			assert.Equal(t, tt.inFreeze, phase.InFreeze())
+		})
+	}
+
+	now, _ := time.Parse(time.DateOnly, "2024-01-01")
+	assert.Nil(t, schedule.PhaseAt(now))
+}
+
+func TestStrictThreshold(t *testing.T) {
+	freeze := &Phase{Name: PhaseCodeFreeze}
+	assert.Equal(t, 1, freeze.StrictThreshold(3))
+	assert.Equal(t, 0, freeze.StrictThreshold(0))
+
+	var unknown *Phase
+	assert.Equal(t, 3, unknown.StrictThreshold(3))
+}
+
+func TestPriority(t *testing.T) {
+	var unknown *Phase
+	assert.Empty(t, unknown.Priority(true))
+	assert.Empty(t, (&Phase{Name: PhaseNormal}).Priority(true))
+	assert.Equal(t, "critical-urgent", (&Phase{Name: PhaseCodeFreeze}).Priority(true))
+	assert.Equal(t, "important-soon", (&Phase{Name: PhaseBurndown}).Priority(false))
+}
@@ -0,0 +1,116 @@
+// Package sig infers the Special Interest Group owning a test, from the
+// [sig-*] tag of e2e test names, from the OWNERS files of the code a unit
+// test or a verify check covers, from a local override map or from the
+// owner of its Triage clusters, see Source.
+package sig
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/signalhound/internal/monitoring"
+)
+
+// OwnersURL is the raw content root of the kubernetes/kubernetes repository
+// the OWNERS files are read from.
+var OwnersURL = "https://raw.githubusercontent.com/kubernetes/kubernetes/master"
+
+// Names are the Kubernetes SIGs, without their sig- prefix, e.g. suggested
+// when a SIG is typed in the UI.
+var Names = []string{
+	"api-machinery", "apps", "architecture", "auth", "autoscaling", "cli", "cloud-provider",
+	"cluster-lifecycle", "contributor-experience", "docs", "etcd", "instrumentation", "k8s-infra",
+	"multicluster", "network", "node", "release", "scalability", "scheduling", "security", "storage",
+	"testing", "windows",
+}
+
+var (
+	tagRegex    = regexp.MustCompile(`\[sig-([a-z-]+)\]`)
+	verifyRegex = regexp.MustCompile(`(?i)^verify[ .:_-]+(?:verify-)?([\w-]+?)(?:\.sh)?$`)
+)
+
+// FromTestName returns the SIG of the [sig-*] tag of the test name, or an
+// empty string.
+func FromTestName(testName string) string {
+	if match := tagRegex.FindStringSubmatch(strings.ToLower(testName)); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// Path returns the kubernetes/kubernetes path covered by a test without
+// tag: the package of a unit test, e.g. k8s.io/kubernetes/pkg/kubelet/cm.TestX
+// maps to pkg/kubelet/cm and k8s.io/apiserver/pkg/server.TestX to its staging
+// directory, and the script of a verify check, e.g. verify.gofmt maps to
+// hack/verify-gofmt.sh. It returns an empty string for any other test.
+func Path(testName string) string {
+	if match := verifyRegex.FindStringSubmatch(strings.TrimSpace(testName)); match != nil {
+		return fmt.Sprintf("hack/verify-%s.sh", match[1])
+	}
+	fields := strings.Fields(testName)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "k8s.io/") {
+		return ""
+	}
+	pkg := strings.TrimSuffix(fields[0], ":")
+	// the test function follows the last dot of the last path element.
+	if dot := strings.LastIndex(pkg, "."); dot > strings.LastIndex(pkg, "/") {
+		pkg = pkg[:dot]
+	}
+	if trimmed, found := strings.CutPrefix(pkg, "k8s.io/kubernetes/"); found {
+		return trimmed
+	}
+	return "staging/src/" + pkg
+}
+
+// Resolver infers the SIG of the tests from its sources, tried in priority
+// order, and caches the SIG of each test so the refreshes don't query the
+// sources again.
+type Resolver struct {
+	Sources []Source
+
+	mu    sync.Mutex
+	cache map[string]string
+}
+
+// NewResolver returns a resolver of the sources, the first one knowing the
+// SIG of a test wins.
+func NewResolver(sources ...Source) *Resolver {
+	return &Resolver{Sources: sources, cache: map[string]string{}}
+}
+
+// Infer returns the SIG of the test from the first source knowing it, or an
+// empty string when none does. A failing source is skipped, its error is
+// returned when no other source knows the SIG and the test is looked up
+// again on the next call, e.g. once back online.
+func (r *Resolver) Infer(testName string) (string, error) {
+	r.mu.Lock()
+	sig, found := r.cache[testName]
+	r.mu.Unlock()
+	monitoring.RecordCacheLookup("sig", found)
+	if found {
+		return sig, nil
+	}
+
+	var errs []error
+	for _, source := range r.Sources {
+		inferred, err := source.SIG(testName)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if inferred != "" {
+			sig = inferred
+			break
+		}
+	}
+	if sig == "" && len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cache[testName] = sig
+	return sig, nil
+}
@@ -0,0 +1,132 @@
+package sig
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/signalhound/internal/triage"
+)
+
+func TestFromTestName(t *testing.T) {
+	assert.Equal(t, "node", FromTestName("Kubernetes e2e suite.[It] [sig-node] Pods should run"))
+	assert.Equal(t, "api-machinery", FromTestName("[SIG-API-Machinery] Watchers"))
+	assert.Empty(t, FromTestName("ci-kubernetes-e2e.Overall"))
+}
+
+func TestPath(t *testing.T) {
+	tests := map[string]string{
+		"k8s.io/kubernetes/pkg/kubelet/cm.TestCgroupName":   "pkg/kubelet/cm",
+		"k8s.io/kubernetes/pkg/kubelet/cm":                  "pkg/kubelet/cm",
+		"k8s.io/apiserver/pkg/server.TestGracefulShutdown":  "staging/src/k8s.io/apiserver/pkg/server",
+		"k8s.io/kubernetes/test/integration/apiserver: run": "test/integration/apiserver",
+		"verify.gofmt":                    "hack/verify-gofmt.sh",
+		"verify: verify-golangci-lint.sh": "hack/verify-golangci-lint.sh",
+		"[sig-node] Pods should run":      "",
+		"ci-kubernetes-e2e.Up":            "",
+	}
+	for testName, expected := range tests {
+		assert.Equal(t, expected, Path(testName), testName)
+	}
+}
+
+func TestResolver(t *testing.T) {
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/pkg/kubelet/OWNERS":
+			fmt.Fprint(w, "approvers:\n- sig-node-approvers\nlabels:\n- area/kubelet\n- sig/node\n") // nolint
+		case "/pkg/kubelet/cm/OWNERS":
+			fmt.Fprint(w, "approvers:\n- sig-node-cm-approvers\n") // nolint
+		case "/pkg/proxy/OWNERS":
+			fmt.Fprint(w, "labels:\n- sig/network\n") // nolint
+		case "/hack/OWNERS":
+			fmt.Fprint(w, "labels:\n- sig/testing\n") // nolint
+		case "/pkg/broken/OWNERS":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	resolver := NewResolver(TagSource{}, NewOwnersSource(server.URL))
+	tests := map[string]string{
+		"[sig-network] DNS should resolve":                 "network",
+		"k8s.io/kubernetes/pkg/kubelet/cm.TestCgroupName":  "node",
+		"k8s.io/kubernetes/pkg/kubelet/cm.TestCgroupName2": "node",
+		"verify.gofmt":                            "testing",
+		"k8s.io/kubernetes/pkg/proxy.TestSync":    "network",
+		"k8s.io/kubernetes/cmd/kubeadm/app.TestX": "",
+		"ci-kubernetes-e2e.Up":                    "",
+	}
+	for testName, expected := range tests {
+		sig, err := resolver.Infer(testName)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, sig, testName)
+	}
+	assert.Equal(t, 1, requests["/pkg/kubelet/OWNERS"], "OWNERS files are cached")
+
+	_, err := resolver.Infer("k8s.io/kubernetes/pkg/broken/part.TestX")
+	assert.Error(t, err)
+}
+
+// fakeClusters returns the Triage clusters of the tests, or err.
+type fakeClusters struct {
+	clusters map[string][]triage.Cluster
+	err      error
+	reads    int
+}
+
+func (f *fakeClusters) Clusters(testName string) ([]triage.Cluster, error) {
+	f.reads++
+	return f.clusters[testName], f.err
+}
+
+func TestSources(t *testing.T) {
+	overrides, err := NewOverrideSource(map[string]string{
+		`^k8s.io/kubernetes/pkg/volume`: "sig-storage",
+		`\[sig-node\] Pods should run`:  "apps",
+	})
+	assert.NoError(t, err)
+	_, err = NewOverrideSource(map[string]string{"[": "node"})
+	assert.Error(t, err)
+
+	clusters := &fakeClusters{clusters: map[string][]triage.Cluster{
+		"k8s.io/kubernetes/pkg/volume/csi.TestAttach": {{Owner: "sig-network"}},
+		"ci-kubernetes-e2e.Up":                        {{Owner: "sig-cluster-lifecycle"}, {Owner: "sig-testing"}},
+	}}
+	resolver := NewResolver(overrides, TagSource{}, TriageSource{Triage: clusters})
+	tests := map[string]string{
+		"k8s.io/kubernetes/pkg/volume/csi.TestAttach": "storage",
+		"[sig-node] Pods should run":                  "apps",
+		"[sig-node] Pods should stop":                 "node",
+		"ci-kubernetes-e2e.Up":                        "cluster-lifecycle",
+		"k8s.io/kubernetes/pkg/proxy.TestSync":        "",
+	}
+	for testName, expected := range tests {
+		sig, err := resolver.Infer(testName)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, sig, testName)
+	}
+	assert.Equal(t, 2, clusters.reads, "the sources of higher priority win")
+	_, err = resolver.Infer("ci-kubernetes-e2e.Up")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, clusters.reads, "the SIGs are cached")
+
+	offline := &fakeClusters{err: errors.New("offline")}
+	resolver = NewResolver(TriageSource{Triage: offline}, TagSource{})
+	sig, err := resolver.Infer("[sig-node] Pods should run")
+	assert.NoError(t, err, "a failing source falls through")
+	assert.Equal(t, "node", sig)
+	_, err = resolver.Infer("k8s.io/kubernetes/pkg/proxy.TestSync")
+	assert.Error(t, err)
+	_, err = resolver.Infer("k8s.io/kubernetes/pkg/proxy.TestSync")
+	assert.Error(t, err)
+	assert.Equal(t, 3, offline.reads, "the errors aren't cached")
+}
@@ -0,0 +1,249 @@
+package sig
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/signalhound/internal/triage"
+	"sigs.k8s.io/yaml"
+)
+
+// Names of the sources of the configuration, see Config.Sources.
+const (
+	SourceOverrides = "overrides"
+	SourceTag       = "tag"
+	SourceOwners    = "owners"
+	SourceTriage    = "triage"
+)
+
+// DefaultSources are the sources used when the configuration sets none, the
+// Triage source is left out as its data weighs hundreds of megabytes.
+var DefaultSources = []string{SourceOverrides, SourceTag, SourceOwners}
+
+// Source infers the SIG of a test, returning an empty string when it doesn't
+// know the test.
+type Source interface {
+	SIG(testName string) (string, error)
+}
+
+// Config selects the sources of the SIG inference and their priority.
+type Config struct {
+	// Sources are the names of the sources in priority order, overrides,
+	// tag, owners and triage, DefaultSources when empty.
+	Sources []string `json:"sources,omitempty"`
+
+	// Overrides maps a regular expression of the test names to their SIG,
+	// e.g. ^k8s.io/kubernetes/pkg/volume: storage.
+	Overrides map[string]string `json:"overrides,omitempty"`
+}
+
+// Validate checks the source names and the override expressions.
+func (c Config) Validate() error {
+	seen := map[string]bool{}
+	for _, name := range c.Sources {
+		switch name {
+		case SourceOverrides, SourceTag, SourceOwners, SourceTriage:
+		default:
+			return fmt.Errorf("invalid sig source %q, expected %s, %s, %s or %s",
+				name, SourceOverrides, SourceTag, SourceOwners, SourceTriage)
+		}
+		if seen[name] {
+			return fmt.Errorf("duplicate sig source %q", name)
+		}
+		seen[name] = true
+	}
+	_, err := NewOverrideSource(c.Overrides)
+	return err
+}
+
+// Resolver returns the resolver of the configured sources, reading the
+// OWNERS files under OwnersURL and the Triage data under triage.URL.
+func (c Config) Resolver() (*Resolver, error) {
+	names := c.Sources
+	if len(names) == 0 {
+		names = DefaultSources
+	}
+	var sources []Source
+	for _, name := range names {
+		switch name {
+		case SourceOverrides:
+			overrides, err := NewOverrideSource(c.Overrides)
+			if err != nil {
+				return nil, err
+			}
+			sources = append(sources, overrides)
+		case SourceTag:
+			sources = append(sources, TagSource{})
+		case SourceOwners:
+			sources = append(sources, NewOwnersSource(OwnersURL))
+		case SourceTriage:
+			sources = append(sources, TriageSource{Triage: triage.NewClient(triage.URL)})
+		default:
+			return nil, fmt.Errorf("invalid sig source %q", name)
+		}
+	}
+	return NewResolver(sources...), nil
+}
+
+// TagSource infers the SIG from the [sig-*] tag of the test name.
+type TagSource struct{}
+
+// SIG returns the SIG of the tag, see FromTestName.
+func (TagSource) SIG(testName string) (string, error) {
+	return FromTestName(testName), nil
+}
+
+// override is a test name expression of OverrideSource.
+type override struct {
+	regex *regexp.Regexp
+	sig   string
+}
+
+// OverrideSource infers the SIG from a local map of test name expressions,
+// for the tests the other sources get wrong or don't know.
+type OverrideSource struct {
+	overrides []override
+}
+
+// NewOverrideSource compiles the expressions of the overrides, tried in the
+// order of the expressions.
+func NewOverrideSource(overrides map[string]string) (*OverrideSource, error) {
+	expressions := make([]string, 0, len(overrides))
+	for expression := range overrides {
+		expressions = append(expressions, expression)
+	}
+	sort.Strings(expressions)
+	source := &OverrideSource{}
+	for _, expression := range expressions {
+		regex, err := regexp.Compile(expression)
+		if err != nil {
+			return nil, fmt.Errorf("error compiling sig override %q: %v", expression, err)
+		}
+		source.overrides = append(source.overrides, override{regex: regex, sig: strings.TrimPrefix(overrides[expression], "sig-")})
+	}
+	return source, nil
+}
+
+// SIG returns the SIG of the first expression matching the test name.
+func (s *OverrideSource) SIG(testName string) (string, error) {
+	for _, override := range s.overrides {
+		if override.regex.MatchString(testName) {
+			return override.sig, nil
+		}
+	}
+	return "", nil
+}
+
+// OwnersSource infers the SIG of the tests without tag from the OWNERS files
+// of the path they cover. OWNERS files are cached by directory.
+type OwnersSource struct {
+	URL string
+
+	client *http.Client
+	mu     sync.Mutex
+	owners map[string]string
+}
+
+// NewOwnersSource returns a source reading the OWNERS files under url.
+func NewOwnersSource(url string) *OwnersSource {
+	return &OwnersSource{URL: url, client: &http.Client{Timeout: 15 * time.Second}, owners: map[string]string{}}
+}
+
+// SIG returns the first sig/ label of the closest OWNERS file of the path
+// the test covers, see Path.
+func (s *OwnersSource) SIG(testName string) (string, error) {
+	covered := Path(testName)
+	if covered == "" {
+		return "", nil
+	}
+	dir := covered
+	if strings.HasSuffix(covered, ".sh") {
+		dir = path.Dir(covered)
+	}
+	// the root OWNERS file has no SIG, the walk stops below it.
+	for ; dir != "." && dir != "/"; dir = path.Dir(dir) {
+		sig, err := s.ownersSIG(dir)
+		if err != nil {
+			return "", err
+		}
+		if sig != "" {
+			return sig, nil
+		}
+	}
+	return "", nil
+}
+
+// ownersSIG returns the first sig/ label of the OWNERS file of the
+// directory, an empty string when the file is missing or has none.
+func (s *OwnersSource) ownersSIG(dir string) (string, error) {
+	s.mu.Lock()
+	sig, found := s.owners[dir]
+	s.mu.Unlock()
+	if found {
+		return sig, nil
+	}
+
+	response, err := s.client.Get(fmt.Sprintf("%s/%s/OWNERS", s.URL, dir))
+	if err != nil {
+		return "", fmt.Errorf("error fetching OWNERS of %s: %v", dir, err)
+	}
+	defer response.Body.Close() // nolint
+	switch response.StatusCode {
+	case http.StatusOK:
+		var owners struct {
+			Labels []string `json:"labels"`
+		}
+		data, err := io.ReadAll(response.Body)
+		if err != nil {
+			return "", fmt.Errorf("error reading OWNERS of %s: %v", dir, err)
+		}
+		if err := yaml.Unmarshal(data, &owners); err != nil {
+			return "", fmt.Errorf("error unmarshaling OWNERS of %s: %v", dir, err)
+		}
+		for _, label := range owners.Labels {
+			if trimmed, found := strings.CutPrefix(label, "sig/"); found {
+				sig = trimmed
+				break
+			}
+		}
+	case http.StatusNotFound:
+	default:
+		return "", fmt.Errorf("error fetching OWNERS of %s: %s", dir, response.Status)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.owners[dir] = sig
+	return sig, nil
+}
+
+// ClusterReader reads the Triage clusters of a test, see triage.Client.
+type ClusterReader interface {
+	Clusters(testName string) ([]triage.Cluster, error)
+}
+
+// TriageSource infers the SIG from the owner Triage hints on the cluster of
+// the most failures of the test.
+type TriageSource struct {
+	Triage ClusterReader
+}
+
+// SIG returns the owner of the top cluster of the test, without its sig-
+// prefix.
+func (s TriageSource) SIG(testName string) (string, error) {
+	clusters, err := s.Triage.Clusters(testName)
+	if err != nil {
+		return "", fmt.Errorf("error reading the Triage clusters of %s: %v", testName, err)
+	}
+	if len(clusters) == 0 {
+		return "", nil
+	}
+	return strings.TrimPrefix(clusters[0].Owner, "sig-"), nil
+}
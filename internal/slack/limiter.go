@@ -0,0 +1,73 @@
+package slack
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AnyChannel is the rate limit key applying to the channels without their
+// own limit.
+const AnyChannel = "*"
+
+// RateLimit caps the messages posted to a channel in a rolling window, e.g.
+// 5 messages per hour.
+type RateLimit struct {
+	Messages int             `json:"messages"`
+	Window   metav1.Duration `json:"window"`
+}
+
+// Validate checks the limit allows messages over a window.
+func (r RateLimit) Validate() error {
+	if r.Messages <= 0 || r.Window.Duration <= 0 {
+		return fmt.Errorf("invalid rate limit of %d messages per %s, expected positive values", r.Messages, r.Window.Duration)
+	}
+	return nil
+}
+
+// Limiter keeps the time of the messages posted by channel to enforce their
+// rate limits, the channels without limit are not capped.
+type Limiter struct {
+	limits map[string]RateLimit
+
+	mu     sync.Mutex
+	posted map[string][]time.Time
+}
+
+// NewLimiter returns a limiter of the rate limits by channel, AnyChannel
+// applies to the channels without their own limit.
+func NewLimiter(limits map[string]RateLimit) *Limiter {
+	return &Limiter{limits: limits, posted: map[string][]time.Time{}}
+}
+
+// Allow records a message posted to the channel at now, or returns an error
+// when the channel reached its rate limit.
+func (l *Limiter) Allow(channel string, now time.Time) error {
+	if l == nil {
+		return nil
+	}
+	limit, found := l.limits[channel]
+	if !found {
+		limit, found = l.limits[AnyChannel]
+	}
+	if !found {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var recent []time.Time
+	for _, at := range l.posted[channel] {
+		if now.Sub(at) < limit.Window.Duration {
+			recent = append(recent, at)
+		}
+	}
+	if len(recent) >= limit.Messages {
+		l.posted[channel] = recent
+		return fmt.Errorf("rate limit of %s reached, %d messages per %s", channel, limit.Messages, limit.Window.Duration)
+	}
+	l.posted[channel] = append(recent, now)
+	return nil
+}
@@ -0,0 +1,35 @@
+package slack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLimiter(t *testing.T) {
+	limiter := NewLimiter(map[string]RateLimit{
+		"#sig-node-ci": {Messages: 2, Window: metav1.Duration{Duration: time.Hour}},
+		AnyChannel:     {Messages: 1, Window: metav1.Duration{Duration: time.Minute}},
+	})
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, limiter.Allow("#sig-node-ci", now))
+	assert.NoError(t, limiter.Allow("#sig-node-ci", now.Add(time.Minute)))
+	assert.EqualError(t, limiter.Allow("#sig-node-ci", now.Add(2*time.Minute)), "rate limit of #sig-node-ci reached, 2 messages per 1h0m0s")
+	// the first message leaves the window
+	assert.NoError(t, limiter.Allow("#sig-node-ci", now.Add(time.Hour)))
+
+	// the other channels get the default limit, each its own count
+	assert.NoError(t, limiter.Allow("#sig-network", now))
+	assert.Error(t, limiter.Allow("#sig-network", now.Add(time.Second)))
+	assert.NoError(t, limiter.Allow("#sig-storage", now))
+
+	assert.NoError(t, NewLimiter(nil).Allow("#sig-node-ci", now))
+	var disabled *Limiter
+	assert.NoError(t, disabled.Allow("#sig-node-ci", now))
+
+	assert.Error(t, RateLimit{Messages: 0, Window: metav1.Duration{Duration: time.Hour}}.Validate())
+	assert.NoError(t, RateLimit{Messages: 1, Window: metav1.Duration{Duration: time.Hour}}.Validate())
+}
@@ -0,0 +1,84 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// APIURL is the Slack Web API endpoint.
+var APIURL = "https://slack.com/api"
+
+// linkRegex matches the markdown links of the messages, [text](url).
+var linkRegex = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+
+// Client posts messages with a Slack bot token, the bot must be a member of
+// the channels it posts to.
+type Client struct {
+	URL string
+
+	token  string
+	client *http.Client
+}
+
+// NewClient returns a client of the Slack Web API authenticated with the bot
+// token, e.g. xoxb-...
+func NewClient(token string) *Client {
+	return &Client{URL: APIURL, token: token, client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+type postMessageRequest struct {
+	Channel  string `json:"channel"`
+	Text     string `json:"text"`
+	ThreadTS string `json:"thread_ts,omitempty"`
+}
+
+type postMessageResponse struct {
+	OK      bool   `json:"ok"`
+	Error   string `json:"error"`
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+}
+
+// PostMessage posts the markdown message to the channel, in the thread of
+// the threadTS message when set, and returns the timestamp identifying the
+// posted message.
+func (c *Client) PostMessage(channel, message, threadTS string) (string, error) {
+	payload, err := json.Marshal(postMessageRequest{Channel: channel, Text: Mrkdwn(message), ThreadTS: threadTS})
+	if err != nil {
+		return "", err
+	}
+	request, err := http.NewRequest(http.MethodPost, c.URL+"/chat.postMessage", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/json; charset=utf-8")
+	request.Header.Set("Authorization", "Bearer "+c.token)
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("error posting to Slack: %v", err)
+	}
+	defer response.Body.Close() // nolint
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error posting to Slack: %s", response.Status)
+	}
+	var result postMessageResponse
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("error parsing Slack response: %v", err)
+	}
+	if !result.OK {
+		// the Web API reports errors in the body of a 200, e.g. not_in_channel.
+		return "", fmt.Errorf("error posting to Slack: %s", result.Error)
+	}
+	return result.TS, nil
+}
+
+// Mrkdwn converts the markdown links of the message to the Slack link
+// syntax, <url|text>, the rest of the formatting is shared.
+func Mrkdwn(message string) string {
+	return linkRegex.ReplaceAllString(message, "<$2|$1>")
+}
@@ -0,0 +1,58 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMrkdwn(t *testing.T) {
+	assert.Equal(t, ":red_circle: Failing on <https://testgrid.k8s.io/a#b|a#b>: `test` <https://prow.k8s.io/1|Prow>",
+		Mrkdwn(":red_circle: Failing on [a#b](https://testgrid.k8s.io/a#b): `test` [Prow](https://prow.k8s.io/1)"))
+	assert.Equal(t, "[sig-node] Pods", Mrkdwn("[sig-node] Pods"))
+}
+
+func TestPostMessage(t *testing.T) {
+	var posted []postMessageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/chat.postMessage", r.URL.Path)
+		if r.Header.Get("Authorization") != "Bearer xoxb-token" {
+			fmt.Fprint(w, `{"ok": false, "error": "invalid_auth"}`) // nolint
+			return
+		}
+		var request postMessageRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		if request.Channel == "#private" {
+			fmt.Fprint(w, `{"ok": false, "error": "not_in_channel"}`) // nolint
+			return
+		}
+		posted = append(posted, request)
+		fmt.Fprintf(w, `{"ok": true, "channel": "C123", "ts": "1700000000.00010%d"}`, len(posted)) // nolint
+	}))
+	defer server.Close()
+
+	client := NewClient("xoxb-token")
+	client.URL = server.URL
+	ts, err := client.PostMessage("#release-ci-signal", "Failing on [a#b](https://testgrid.k8s.io/a#b)", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "1700000000.000101", ts)
+
+	_, err = client.PostMessage("#release-ci-signal", "again", ts)
+	assert.NoError(t, err)
+	assert.Equal(t, []postMessageRequest{
+		{Channel: "#release-ci-signal", Text: "Failing on <https://testgrid.k8s.io/a#b|a#b>"},
+		{Channel: "#release-ci-signal", Text: "again", ThreadTS: "1700000000.000101"},
+	}, posted)
+
+	_, err = client.PostMessage("#private", "message", "")
+	assert.EqualError(t, err, "error posting to Slack: not_in_channel")
+
+	client = NewClient("xoxb-wrong")
+	client.URL = server.URL
+	_, err = client.PostMessage("#release-ci-signal", "message", "")
+	assert.EqualError(t, err, "error posting to Slack: invalid_auth")
+}
@@ -0,0 +1,132 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+const (
+	// ConfigMapPrefix selects the ConfigMap store in a state location, e.g.
+	// configmap:signalhound-system/signalhound-state.
+	ConfigMapPrefix = "configmap:"
+
+	// configMapKey is the ConfigMap data key holding the JSON state.
+	configMapKey = "state.json"
+
+	// requestTimeout bounds each request to the API server.
+	requestTimeout = 30 * time.Second
+)
+
+// ConfigMapStore keeps the state in a Kubernetes ConfigMap, so the controller
+// and the TUI users of a cluster share it. Saves are rejected with
+// ErrConflict when the ConfigMap changed since the last Load.
+type ConfigMapStore struct {
+	Client    client.Client
+	Namespace string
+	Name      string
+
+	mu sync.Mutex
+	// resourceVersion is the ConfigMap version of the last Load, empty when
+	// the ConfigMap doesn't exist yet.
+	resourceVersion string
+}
+
+// NewConfigMapStore returns a Store backed by the namespace/name ConfigMap.
+func NewConfigMapStore(c client.Client, namespace, name string) *ConfigMapStore {
+	return &ConfigMapStore{Client: c, Namespace: namespace, Name: name}
+}
+
+// Load reads the ConfigMap, a missing ConfigMap returns an empty state.
+func (c *ConfigMapStore) Load() (*State, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	state := &State{}
+	configMap := &corev1.ConfigMap{}
+	err := c.Client.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: c.Name}, configMap)
+	if apierrors.IsNotFound(err) {
+		c.resourceVersion = ""
+		return state, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading state configmap: %v", err)
+	}
+	c.resourceVersion = configMap.ResourceVersion
+	if data := configMap.Data[configMapKey]; data != "" {
+		if err := json.Unmarshal([]byte(data), state); err != nil {
+			return nil, fmt.Errorf("error parsing state configmap: %v", err)
+		}
+	}
+	return state, nil
+}
+
+// Save writes the state to the ConfigMap, creating it when it was missing
+// on the last Load.
+func (c *ConfigMapStore) Save(state *State) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       c.Namespace,
+			Name:            c.Name,
+			ResourceVersion: c.resourceVersion,
+			Labels:          map[string]string{"app.kubernetes.io/name": "signalhound"},
+		},
+		Data: map[string]string{configMapKey: string(data)},
+	}
+	if c.resourceVersion == "" {
+		err = c.Client.Create(ctx, configMap)
+	} else {
+		err = c.Client.Update(ctx, configMap)
+	}
+	if apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("error writing state configmap: %w", ErrConflict)
+	} else if err != nil {
+		return fmt.Errorf("error writing state configmap: %v", err)
+	}
+	c.resourceVersion = configMap.ResourceVersion
+	return nil
+}
+
+// Open returns the store of the location: the namespace/name ConfigMap of
+// the current kubeconfig context for configmap:<namespace>/<name>, a local
+// file otherwise.
+func Open(location string) (Store, error) {
+	reference, found := strings.CutPrefix(location, ConfigMapPrefix)
+	if !found {
+		return NewFileStore(location), nil
+	}
+	namespace, name, ok := strings.Cut(reference, "/")
+	if !ok || namespace == "" || name == "" {
+		return nil, fmt.Errorf("error parsing state location %q, expected %s<namespace>/<name>", location, ConfigMapPrefix)
+	}
+	restConfig, err := config.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error loading kubeconfig: %v", err)
+	}
+	c, err := client.New(restConfig, client.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("error creating kubernetes client: %v", err)
+	}
+	return NewConfigMapStore(c, namespace, name), nil
+}
@@ -0,0 +1,77 @@
+package state
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"sigs.k8s.io/signalhound/internal/github"
+)
+
+var issueURLRegex = regexp.MustCompile(`^https://github\.com/([\w.-]+)/([\w.-]+)/issues/(\d+)$`)
+
+// IssueLink is an existing GitHub issue tracking a test, linked by hand or
+// by an agent when the search doesn't find it, e.g. an issue titled after the
+// failure rather than the test.
+type IssueLink struct {
+	URL    string `json:"url"`
+	Number int    `json:"number"`
+
+	// Note says why the issue tracks the test, used as its title.
+	Note string `json:"note,omitempty"`
+
+	Author    string    `json:"author,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Issue returns the linked issue.
+func (l IssueLink) Issue() github.Issue {
+	return github.Issue{Number: l.Number, Title: l.Note, URL: l.URL}
+}
+
+// IssueNumber returns the number of the GitHub issue of the URL.
+func IssueNumber(url string) (int, error) {
+	_, _, number, err := ParseIssueURL(url)
+	return number, err
+}
+
+// ParseIssueURL returns the repository owner, name and the number of the
+// GitHub issue of the URL.
+func ParseIssueURL(url string) (owner, repo string, number int, err error) {
+	match := issueURLRegex.FindStringSubmatch(url)
+	if match == nil {
+		return "", "", 0, fmt.Errorf("invalid issue URL %q, expected https://github.com/<owner>/<repo>/issues/<number>", url)
+	}
+	number, err = strconv.Atoi(match[3])
+	return match[1], match[2], number, err
+}
+
+// LinkIssue records the issue of the URL as tracking the test, replacing its
+// previous link.
+func (s *State) LinkIssue(testName, url, note, author string) (IssueLink, error) {
+	number, err := IssueNumber(url)
+	if err != nil {
+		return IssueLink{}, err
+	}
+	if s.IssueLinks == nil {
+		s.IssueLinks = map[string]IssueLink{}
+	}
+	link := IssueLink{URL: url, Number: number, Note: note, Author: author, CreatedAt: time.Now().UTC()}
+	s.IssueLinks[testName] = link
+	return link, nil
+}
+
+// UnlinkIssue removes the issue link of the test.
+func (s *State) UnlinkIssue(testName string) {
+	delete(s.IssueLinks, testName)
+}
+
+// IssueLink returns the issue linked to the test, false when it has none.
+func (s *State) IssueLink(testName string) (IssueLink, bool) {
+	if s == nil {
+		return IssueLink{}, false
+	}
+	link, ok := s.IssueLinks[testName]
+	return link, ok
+}
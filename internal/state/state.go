@@ -0,0 +1,200 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Store persists the signalhound state shared across runs, see FileStore
+// for a single machine and ConfigMapStore for a cluster.
+type Store interface {
+	Load() (*State, error)
+	Save(state *State) error
+}
+
+// State holds the data signalhound keeps about tests between runs, keyed by
+// test name so it follows a test across boards.
+type State struct {
+	// Annotations are the freeform notes attached to tests by humans.
+	Annotations map[string][]Annotation `json:"annotations,omitempty"`
+
+	// SlackThreads are the first Slack messages reporting the tests, the
+	// next reports of a test follow up in their thread.
+	SlackThreads map[string]SlackThread `json:"slackThreads,omitempty"`
+
+	// Suppressions are the snoozed and acknowledged tests, see Drifted for
+	// the ones to revisit.
+	Suppressions map[string]Suppression `json:"suppressions,omitempty"`
+
+	// IssueLinks are the existing issues tracking the tests, treated like
+	// the issues found by the searches.
+	IssueLinks map[string]IssueLink `json:"issueLinks,omitempty"`
+}
+
+// SlackThread is the Slack message starting the thread of a test.
+type SlackThread struct {
+	Channel  string    `json:"channel"`
+	TS       string    `json:"ts"`
+	PostedAt time.Time `json:"postedAt"`
+}
+
+// Annotation is a note attached to a test, e.g. "waiting on #12345".
+type Annotation struct {
+	Text      string    `json:"text"`
+	Author    string    `json:"author,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// String renders the annotation with its author and date.
+func (a Annotation) String() string {
+	if a.Author == "" {
+		return fmt.Sprintf("%s (%s)", a.Text, a.CreatedAt.Format(time.DateOnly))
+	}
+	return fmt.Sprintf("%s (%s, %s)", a.Text, a.Author, a.CreatedAt.Format(time.DateOnly))
+}
+
+// Annotate attaches a note to the test.
+func (s *State) Annotate(testName, text, author string) Annotation {
+	if s.Annotations == nil {
+		s.Annotations = map[string][]Annotation{}
+	}
+	annotation := Annotation{Text: text, Author: author, CreatedAt: time.Now().UTC()}
+	s.Annotations[testName] = append(s.Annotations[testName], annotation)
+	return annotation
+}
+
+// ClearAnnotations removes every note of the test.
+func (s *State) ClearAnnotations(testName string) {
+	delete(s.Annotations, testName)
+}
+
+// Notes returns the rendered notes of the test, oldest first.
+func (s *State) Notes(testName string) (notes []string) {
+	if s == nil {
+		return nil
+	}
+	for _, annotation := range s.Annotations[testName] {
+		notes = append(notes, annotation.String())
+	}
+	return notes
+}
+
+// SlackThread returns the timestamp of the message starting the thread of
+// the test in the channel, empty when the test wasn't reported there.
+func (s *State) SlackThread(testName, channel string) string {
+	if s == nil {
+		return ""
+	}
+	if thread, ok := s.SlackThreads[testName]; ok && thread.Channel == channel {
+		return thread.TS
+	}
+	return ""
+}
+
+// SetSlackThread records the message starting the thread of the test,
+// replacing the thread of another channel.
+func (s *State) SetSlackThread(testName, channel, ts string) {
+	if s.SlackThreads == nil {
+		s.SlackThreads = map[string]SlackThread{}
+	}
+	s.SlackThreads[testName] = SlackThread{Channel: channel, TS: ts, PostedAt: time.Now().UTC()}
+}
+
+// DefaultPath returns the state file location under the user configuration
+// directory.
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "signalhound", "state.json")
+}
+
+// FileStore keeps the state in a local JSON file.
+type FileStore struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// NewFileStore returns a Store backed by the JSON file at path.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{Path: path}
+}
+
+// Load reads the state file, a missing file returns an empty state.
+func (f *FileStore) Load() (*State, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	state := &State{}
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading state file: %v", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("error parsing state file: %v", err)
+	}
+	return state, nil
+}
+
+// Save writes the state file atomically, so a crash never leaves a partial
+// file behind.
+func (f *FileStore) Save(state *State) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0o700); err != nil {
+		return fmt.Errorf("error creating state directory: %v", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), ".state-*.json")
+	if err != nil {
+		return fmt.Errorf("error writing state file: %v", err)
+	}
+	defer os.Remove(tmp.Name()) // nolint
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("error writing state file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing state file: %v", err)
+	}
+	return os.Rename(tmp.Name(), f.Path)
+}
+
+// maxUpdateAttempts bounds the retries of Update when another writer saved
+// the state between the load and the save.
+const maxUpdateAttempts = 5
+
+// ErrConflict is returned by Store.Save when the state was saved by another
+// writer since it was loaded, stores without concurrency control never
+// return it.
+var ErrConflict = errors.New("the state was changed by another writer")
+
+// Update loads the state, applies the change and saves it back, starting
+// over from a fresh load when another writer saved the state in between.
+func Update(store Store, change func(state *State)) (*State, error) {
+	for attempt := 1; ; attempt++ {
+		state, err := store.Load()
+		if err != nil {
+			return nil, err
+		}
+		change(state)
+		err = store.Save(state)
+		if errors.Is(err, ErrConflict) && attempt < maxUpdateAttempts {
+			continue
+		}
+		return state, err
+	}
+}
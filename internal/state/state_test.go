@@ -0,0 +1,172 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+)
+
+func TestFileStore(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "signalhound", "state.json"))
+
+	state, err := store.Load()
+	assert.NoError(t, err)
+	assert.Empty(t, state.Annotations)
+
+	_, err = Update(store, func(state *State) {
+		state.Annotate("[sig-node] Pods", "waiting on #12345", "alice")
+		state.Annotate("[sig-node] Pods", "infra issue, see incident-42", "")
+		state.Annotate("[sig-network] DNS", "fixed by #12346", "bob")
+	})
+	assert.NoError(t, err)
+
+	state, err = store.Load()
+	assert.NoError(t, err)
+	notes := state.Notes("[sig-node] Pods")
+	today := time.Now().UTC().Format(time.DateOnly)
+	assert.Equal(t, []string{
+		"waiting on #12345 (alice, " + today + ")",
+		"infra issue, see incident-42 (" + today + ")",
+	}, notes)
+
+	_, err = Update(store, func(state *State) { state.ClearAnnotations("[sig-node] Pods") })
+	assert.NoError(t, err)
+	state, err = store.Load()
+	assert.NoError(t, err)
+	assert.Empty(t, state.Notes("[sig-node] Pods"))
+	assert.Len(t, state.Notes("[sig-network] DNS"), 1)
+
+	entries, err := os.ReadDir(filepath.Dir(store.Path))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary file is left behind")
+}
+
+func TestFileStoreInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	assert.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+	_, err := NewFileStore(path).Load()
+	assert.Error(t, err)
+}
+
+func TestNilStateNotes(t *testing.T) {
+	var state *State
+	assert.Nil(t, state.Notes("test"))
+}
+
+func TestSlackThreads(t *testing.T) {
+	var empty *State
+	assert.Empty(t, empty.SlackThread("[sig-node] Pods", "#release-ci-signal"))
+
+	state := &State{}
+	state.SetSlackThread("[sig-node] Pods", "#release-ci-signal", "1700000000.000100")
+	assert.Equal(t, "1700000000.000100", state.SlackThread("[sig-node] Pods", "#release-ci-signal"))
+	assert.Empty(t, state.SlackThread("[sig-node] Pods", "#sig-node"))
+	assert.Empty(t, state.SlackThread("[sig-network] DNS", "#release-ci-signal"))
+
+	// a report in another channel starts a new thread
+	state.SetSlackThread("[sig-node] Pods", "#sig-node", "1700000000.000200")
+	assert.Empty(t, state.SlackThread("[sig-node] Pods", "#release-ci-signal"))
+}
+
+func TestSuppressions(t *testing.T) {
+	now := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	state := &State{}
+	state.Snooze("[sig-node] Pods", v1alpha1.FLAKY_STATUS, now.Add(48*time.Hour), "alice")
+	state.Snooze("[sig-network] DNS", v1alpha1.FAILING_STATUS, now.Add(-time.Hour), "alice")
+	state.Acknowledge("[sig-storage] CSI", v1alpha1.FLAKY_STATUS, "bob")
+	state.Acknowledge("[sig-apps] Deployment", v1alpha1.FLAKY_STATUS, "bob")
+	state.Acknowledge("[sig-auth] RBAC", v1alpha1.FAILING_STATUS, "bob")
+
+	suppression, ok := state.Suppressed("[sig-node] Pods", now)
+	assert.True(t, ok)
+	assert.Equal(t, "snoozed as flaky until 2026-10-18", suppression.String())
+	_, ok = state.Suppressed("[sig-network] DNS", now)
+	assert.False(t, ok)
+
+	tabs := []*v1alpha1.DashboardTab{
+		{TabState: v1alpha1.FLAKY_STATUS, TestRuns: []v1alpha1.TestResult{{TestName: "[sig-node] Pods"}, {TestName: "[sig-network] DNS"}}},
+		{TabState: v1alpha1.FAILING_STATUS, TestRuns: []v1alpha1.TestResult{
+			{TestName: "[sig-storage] CSI"}, {TestName: "[sig-auth] RBAC", RecoveredTimestamp: 1000},
+		}},
+	}
+	drifts := state.Drifted(tabs, now)
+	assert.Equal(t, []string{"[sig-apps] Deployment", "[sig-auth] RBAC", "[sig-network] DNS", "[sig-storage] CSI"},
+		[]string{drifts[0].TestName, drifts[1].TestName, drifts[2].TestName, drifts[3].TestName})
+	assert.Equal(t, []string{ChangeRecovered, ChangeRecovered, ChangeExpired, ChangeEscalated},
+		[]string{drifts[0].Change, drifts[1].Change, drifts[2].Change, drifts[3].Change})
+	assert.Equal(t, "acknowledged as flaky, now escalated to failing", drifts[3].String())
+
+	state.Unsuppress("[sig-storage] CSI")
+	assert.Len(t, state.Drifted(tabs, now), 3)
+	var empty *State
+	assert.Empty(t, empty.Drifted(tabs, now))
+}
+
+func TestIssueLinks(t *testing.T) {
+	var empty *State
+	_, ok := empty.IssueLink("[sig-node] Pods")
+	assert.False(t, ok)
+
+	state := &State{}
+	link, err := state.LinkIssue("[sig-node] Pods", "https://github.com/kubernetes/kubernetes/issues/129000", "kubelet restarts", "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, 129000, link.Number)
+	found, ok := state.IssueLink("[sig-node] Pods")
+	assert.True(t, ok)
+	assert.Equal(t, "kubelet restarts", found.Issue().Title)
+
+	owner, repo, number, err := ParseIssueURL("https://github.com/kubernetes/sig-release/issues/2800")
+	assert.NoError(t, err)
+	assert.Equal(t, "kubernetes", owner)
+	assert.Equal(t, "sig-release", repo)
+	assert.Equal(t, 2800, number)
+
+	_, err = state.LinkIssue("[sig-node] Pods", "https://github.com/kubernetes/kubernetes/pull/129001", "", "alice")
+	assert.Error(t, err)
+	state.UnlinkIssue("[sig-node] Pods")
+	_, ok = state.IssueLink("[sig-node] Pods")
+	assert.False(t, ok)
+}
+
+func TestConfigMapStore(t *testing.T) {
+	c := fake.NewClientBuilder().Build()
+	alice := NewConfigMapStore(c, "signalhound-system", "signalhound-state")
+	bob := NewConfigMapStore(c, "signalhound-system", "signalhound-state")
+
+	state, err := alice.Load()
+	assert.NoError(t, err)
+	assert.Empty(t, state.Annotations)
+	_, err = Update(alice, func(state *State) { state.Annotate("[sig-node] Pods", "waiting on #12345", "alice") })
+	assert.NoError(t, err)
+
+	// bob saves between the load and the save of alice.
+	stale, err := alice.Load()
+	assert.NoError(t, err)
+	_, err = Update(bob, func(state *State) { state.Annotate("[sig-node] Pods", "fixed by #12346", "bob") })
+	assert.NoError(t, err)
+	assert.ErrorIs(t, alice.Save(stale), ErrConflict)
+
+	state, err = Update(alice, func(state *State) { state.Annotate("[sig-network] DNS", "infra issue", "alice") })
+	assert.NoError(t, err)
+	assert.Len(t, state.Notes("[sig-node] Pods"), 2)
+	assert.Len(t, state.Notes("[sig-network] DNS"), 1)
+
+	state, err = bob.Load()
+	assert.NoError(t, err)
+	assert.Len(t, state.Annotations, 2)
+}
+
+func TestOpen(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "state.json"))
+	assert.NoError(t, err)
+	assert.IsType(t, &FileStore{}, store)
+
+	_, err = Open(ConfigMapPrefix + "signalhound-state")
+	assert.ErrorContains(t, err, "expected configmap:<namespace>/<name>")
+}
@@ -0,0 +1,146 @@
+package state
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+)
+
+// Kinds of suppression of a test.
+const (
+	Snoozed      = "snoozed"
+	Acknowledged = "acknowledged"
+)
+
+// Suppression silences a known failure or flake of a test, until a date for
+// a snooze and until lifted for an acknowledgement.
+type Suppression struct {
+	Kind string `json:"kind"`
+
+	// State is the tab state of the test when it was suppressed, FLAKY or
+	// FAILING, a flake turning into a failure is no longer the known issue.
+	State string `json:"state"`
+
+	// Until ends a snooze, zero for an acknowledgement.
+	Until time.Time `json:"until,omitempty"`
+
+	Author    string    `json:"author,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// String renders the suppression, e.g. "snoozed as flaky until 2026-10-20".
+func (s Suppression) String() string {
+	text := fmt.Sprintf("%s as %s", s.Kind, stateName(s.State))
+	if s.Kind == Snoozed {
+		text += " until " + s.Until.Format(time.DateOnly)
+	}
+	return text
+}
+
+// Active returns true while the suppression silences the test.
+func (s Suppression) Active(now time.Time) bool {
+	return s.Kind != Snoozed || now.Before(s.Until)
+}
+
+// Snooze silences the test in the tab state until the date.
+func (s *State) Snooze(testName, tabState string, until time.Time, author string) {
+	s.suppress(testName, Suppression{Kind: Snoozed, State: tabState, Until: until.UTC(), Author: author})
+}
+
+// Acknowledge silences the test in the tab state until it is lifted.
+func (s *State) Acknowledge(testName, tabState, author string) {
+	s.suppress(testName, Suppression{Kind: Acknowledged, State: tabState, Author: author})
+}
+
+func (s *State) suppress(testName string, suppression Suppression) {
+	if s.Suppressions == nil {
+		s.Suppressions = map[string]Suppression{}
+	}
+	suppression.CreatedAt = time.Now().UTC()
+	s.Suppressions[testName] = suppression
+}
+
+// Unsuppress lifts the snooze or acknowledgement of the test.
+func (s *State) Unsuppress(testName string) {
+	delete(s.Suppressions, testName)
+}
+
+// Suppressed returns the suppression of the test, false when the test has
+// none or its snooze ended.
+func (s *State) Suppressed(testName string, now time.Time) (Suppression, bool) {
+	if s == nil {
+		return Suppression{}, false
+	}
+	suppression, ok := s.Suppressions[testName]
+	return suppression, ok && suppression.Active(now)
+}
+
+// Changes of the signal of a suppressed test.
+const (
+	ChangeRecovered = "recovered"
+	ChangeEscalated = "escalated to failing"
+	ChangeExpired   = "snooze expired"
+)
+
+// Drift is a suppressed test whose signal changed since it was suppressed,
+// the suppression no longer describes it.
+type Drift struct {
+	TestName    string
+	Suppression Suppression
+	Change      string
+}
+
+// String renders the drift, e.g. "snoozed as flaky until 2026-10-20, now recovered".
+func (d Drift) String() string {
+	return fmt.Sprintf("%s, now %s", d.Suppression, d.Change)
+}
+
+// Drifted returns the suppressed tests no longer listed on the boards, the
+// flakes now failing and the ended snoozes, sorted by test name. A test in a
+// failing tab green again after its failures counts as recovered.
+func (s *State) Drifted(tabs []*v1alpha1.DashboardTab, now time.Time) []Drift {
+	if s == nil {
+		return nil
+	}
+	current := map[string]string{}
+	for _, tab := range tabs {
+		for _, test := range tab.TestRuns {
+			switch {
+			case tab.TabState == v1alpha1.FAILING_STATUS && test.RecoveredTimestamp != 0:
+			case tab.TabState == v1alpha1.FAILING_STATUS || current[test.TestName] == "":
+				current[test.TestName] = tab.TabState
+			}
+		}
+	}
+
+	var drifts []Drift
+	for testName, suppression := range s.Suppressions {
+		change := ""
+		switch {
+		case current[testName] == "":
+			change = ChangeRecovered
+		case suppression.State != v1alpha1.FAILING_STATUS && current[testName] == v1alpha1.FAILING_STATUS:
+			change = ChangeEscalated
+		case !suppression.Active(now):
+			change = ChangeExpired
+		default:
+			continue
+		}
+		drifts = append(drifts, Drift{TestName: testName, Suppression: suppression, Change: change})
+	}
+	sort.Slice(drifts, func(i, j int) bool { return drifts[i].TestName < drifts[j].TestName })
+	return drifts
+}
+
+// stateName returns the lowercase tab state, e.g. flaky.
+func stateName(tabState string) string {
+	switch tabState {
+	case v1alpha1.FAILING_STATUS:
+		return "failing"
+	case v1alpha1.FLAKY_STATUS:
+		return "flaky"
+	}
+	return "unknown"
+}
@@ -0,0 +1,231 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+)
+
+// boardsBucket holds a bucket per dashboard, with the state of the dashboard
+// at the fetches changing it.
+var boardsBucket = []byte("boards")
+
+// severity orders the board states, a higher one is worse.
+var severity = map[string]int{
+	v1alpha1.PASSING_STATUS: 1,
+	v1alpha1.FLAKY_STATUS:   2,
+	v1alpha1.FAILING_STATUS: 3,
+}
+
+// BoardTransition is a change of the state of a dashboard between two
+// fetches, From is empty for the first fetch of the dashboard.
+type BoardTransition struct {
+	Board string    `json:"board"`
+	From  string    `json:"from"`
+	To    string    `json:"to"`
+	At    time.Time `json:"at"`
+}
+
+// Degraded is true when the board went from passing to flaky or failing, or
+// from flaky to failing.
+func (t BoardTransition) Degraded() bool {
+	return t.From != "" && severity[t.To] > severity[t.From]
+}
+
+// String returns the transition, e.g. "sig-release-master-blocking degraded
+// from flaky to failing".
+func (t BoardTransition) String() string {
+	switch {
+	case t.From == "":
+		return fmt.Sprintf("%s first seen %s", t.Board, strings.ToLower(t.To))
+	case t.Degraded():
+		return fmt.Sprintf("%s degraded from %s to %s", t.Board, strings.ToLower(t.From), strings.ToLower(t.To))
+	}
+	return fmt.Sprintf("%s improved from %s to %s", t.Board, strings.ToLower(t.From), strings.ToLower(t.To))
+}
+
+// RecordBoards saves the state of the dashboards fetched at fetchedAt when it
+// changed since the previous fetch and returns these transitions. The state
+// of a dashboard is the worst state of its tabs, PASSING without tab.
+func (s *Store) RecordBoards(dashboards []string, tabs []*v1alpha1.DashboardTab, fetchedAt time.Time) ([]BoardTransition, error) {
+	states := map[string]string{}
+	for _, dashboard := range dashboards {
+		states[dashboard] = v1alpha1.PASSING_STATUS
+	}
+	for _, tab := range tabs {
+		dashboard, _, _ := strings.Cut(tab.BoardHash, "#")
+		if severity[tab.TabState] > severity[states[dashboard]] {
+			states[dashboard] = tab.TabState
+		}
+	}
+
+	db, err := s.open(false)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close() // nolint
+
+	var transitions []BoardTransition
+	err = db.Update(func(tx *bolt.Tx) error {
+		boards, err := tx.CreateBucketIfNotExists(boardsBucket)
+		if err != nil {
+			return err
+		}
+		for dashboard, state := range states {
+			bucket, err := boards.CreateBucketIfNotExists([]byte(dashboard))
+			if err != nil {
+				return err
+			}
+			transition := BoardTransition{Board: dashboard, To: state, At: fetchedAt.UTC()}
+			if _, value := bucket.Cursor().Last(); value != nil {
+				var last BoardTransition
+				if err := json.Unmarshal(value, &last); err != nil {
+					return err
+				}
+				if last.To == state {
+					continue
+				}
+				transition.From = last.To
+			}
+			if err := put(bucket, key(fetchedAt, ""), transition); err != nil {
+				return err
+			}
+			transitions = append(transitions, transition)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(transitions, func(i, j int) bool { return transitions[i].Board < transitions[j].Board })
+	return transitions, nil
+}
+
+// BoardTrend is the health of a dashboard over the days of a period.
+type BoardTrend struct {
+	Board string
+
+	// States are the worst state of the board on each day, oldest first,
+	// empty before its first recorded fetch.
+	States []string
+
+	// Transitions are the state changes of the period.
+	Transitions []BoardTransition
+}
+
+// Sparkline renders the states of the days, ▁ for a passing day, ▄ for a
+// flaky one, █ for a failing one and · for a day without record.
+func (b *BoardTrend) Sparkline() string {
+	var line strings.Builder
+	for _, state := range b.States {
+		switch state {
+		case v1alpha1.PASSING_STATUS:
+			line.WriteRune('▁')
+		case v1alpha1.FLAKY_STATUS:
+			line.WriteRune('▄')
+		case v1alpha1.FAILING_STATUS:
+			line.WriteRune('█')
+		default:
+			line.WriteRune('·')
+		}
+	}
+	return line.String()
+}
+
+// Current returns the latest state of the board, empty without record.
+func (b *BoardTrend) Current() string {
+	if len(b.States) == 0 {
+		return ""
+	}
+	return b.States[len(b.States)-1]
+}
+
+// BoardTrends are the health of the recorded dashboards over a period.
+type BoardTrends struct {
+	Since, Until time.Time
+	Boards       []BoardTrend
+}
+
+// BoardTrends returns the daily health of every recorded dashboard over the
+// days ending at until, sorted by name. A missing history file returns no
+// board.
+func (s *Store) BoardTrends(until time.Time, days int) (*BoardTrends, error) {
+	since := until.Add(-time.Duration(days) * 24 * time.Hour)
+	result := &BoardTrends{Since: since.UTC(), Until: until.UTC()}
+	db, err := s.open(true)
+	if os.IsNotExist(err) {
+		return result, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer db.Close() // nolint
+
+	err = db.View(func(tx *bolt.Tx) error {
+		return forEachTest(tx, boardsBucket, func(board string, bucket *bolt.Bucket) error {
+			trend := BoardTrend{Board: board, States: make([]string, days)}
+			// state is the state of the board at the start of the current day.
+			state, day := "", 0
+			worst := func(state string) {
+				if severity[state] > severity[trend.States[day]] {
+					trend.States[day] = state
+				}
+			}
+			if err := bucket.ForEach(func(_, value []byte) error {
+				var transition BoardTransition
+				if err := json.Unmarshal(value, &transition); err != nil {
+					return err
+				}
+				if !transition.At.Before(until) {
+					return nil
+				}
+				if transition.At.Before(since) {
+					state = transition.To
+					return nil
+				}
+				for ; day < days && !transition.At.Before(since.Add(time.Duration(day+1)*24*time.Hour)); day++ {
+					worst(state)
+				}
+				worst(state)
+				worst(transition.To)
+				state = transition.To
+				trend.Transitions = append(trend.Transitions, transition)
+				return nil
+			}); err != nil {
+				return err
+			}
+			for ; day < days; day++ {
+				worst(state)
+			}
+			result.Boards = append(result.Boards, trend)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading history: %v", err)
+	}
+	return result, nil
+}
+
+// String renders the sparkline and the current state of every board, with
+// their transitions of the period.
+func (t *BoardTrends) String() string {
+	if len(t.Boards) == 0 {
+		return fmt.Sprintf("No board recorded since %s.\n", t.Since.Format(time.DateOnly))
+	}
+	var text strings.Builder
+	fmt.Fprintf(&text, "# Board health, %s to %s\n", t.Since.Format(time.DateOnly), t.Until.Format(time.DateOnly))
+	for _, board := range t.Boards {
+		fmt.Fprintf(&text, "\n%s %s %s\n", board.Sparkline(), board.Board, strings.ToLower(board.Current()))
+		for _, transition := range board.Transitions {
+			fmt.Fprintf(&text, "* %s: %s\n", transition.At.Format("2006-01-02 15:04"), transition)
+		}
+	}
+	return text.String()
+}
@@ -0,0 +1,54 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/signalhound/internal/fingerprint"
+)
+
+// Digest renders the new failures, the resolved failures and the board states
+// of the release branch since the summary start in Markdown, the nightly
+// comment of the umbrella tracking issue of the release. The trends are the
+// board states of the same period.
+func (s *Summary) Digest(branch string, trends *BoardTrends) string {
+	if s.LastFetch.IsZero() {
+		return fmt.Sprintf("No history recorded for %s since %s.\n", branch, s.Since.Format("2006-01-02 15:04"))
+	}
+	var text strings.Builder
+	fmt.Fprintf(&text, "### CI Signal digest of %s, %s to %s UTC\n", branch,
+		s.Since.Format("2006-01-02 15:04"), s.LastFetch.Format("2006-01-02 15:04"))
+
+	text.WriteString("\n#### Boards\n\n")
+	boards := 0
+	for _, board := range trends.Boards {
+		if fingerprint.Branch(board.Board) != branch {
+			continue
+		}
+		boards++
+		fmt.Fprintf(&text, "* %s: %s\n", board.Board, strings.ToLower(board.Current()))
+		for _, transition := range board.Transitions {
+			fmt.Fprintf(&text, "  * %s: %s\n", transition.At.Format("2006-01-02 15:04"), transition)
+		}
+	}
+	if boards == 0 {
+		text.WriteString("None.\n")
+	}
+
+	text.WriteString("\n#### New failures\n\n")
+	writeTests(&text, branchTests(s.NewFailures, branch), "failing since")
+	text.WriteString("\n#### Resolved failures\n\n")
+	writeTests(&text, branchTests(s.Resolved, branch), "last failing on")
+	return text.String()
+}
+
+// branchTests returns the tests failing on the boards of the release branch.
+func branchTests(tests []SummaryTest, branch string) []SummaryTest {
+	var filtered []SummaryTest
+	for _, test := range tests {
+		if fingerprint.Branch(test.BoardHash) == branch {
+			filtered = append(filtered, test)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,264 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// dailyBucket holds a bucket per test, with the runs of the test on a
+	// board per day once compacted, see Compact.
+	dailyBucket = []byte("daily")
+
+	// metaBucket holds the time the raw history is compacted until.
+	metaBucket = []byte("meta")
+
+	// compactedUntilKey is the key of the compaction time in metaBucket.
+	compactedUntilKey = []byte("compactedUntil")
+)
+
+// DefaultRetention keeps the raw history a month and the daily runs a year.
+var DefaultRetention = Retention{RawDays: 30, DailyMonths: 12}
+
+// Retention is the retention policy of the history: the raw fetches and runs
+// are kept RawDays, then compacted into the daily runs of each test kept
+// DailyMonths.
+type Retention struct {
+	RawDays     int `json:"rawDays,omitempty"`
+	DailyMonths int `json:"dailyMonths,omitempty"`
+}
+
+// WithDefaults returns the retention with the unset periods of
+// DefaultRetention.
+func (r Retention) WithDefaults() Retention {
+	if r.RawDays == 0 {
+		r.RawDays = DefaultRetention.RawDays
+	}
+	if r.DailyMonths == 0 {
+		r.DailyMonths = DefaultRetention.DailyMonths
+	}
+	return r
+}
+
+// Validate checks the raw history covers the flake score window and the
+// daily runs outlive it.
+func (r Retention) Validate() error {
+	scoreDays := int(ScoreWindow.Hours() / 24)
+	if r.RawDays != 0 && r.RawDays < scoreDays {
+		return fmt.Errorf("invalid rawDays %d, expected at least the %d days of the flake score", r.RawDays, scoreDays)
+	}
+	if r.DailyMonths < 0 {
+		return fmt.Errorf("invalid dailyMonths %d, expected a positive number of months", r.DailyMonths)
+	}
+	return nil
+}
+
+// DailyRuns are the runs of a test on a board on a UTC day, kept once the
+// raw runs are compacted.
+type DailyRuns struct {
+	BoardHash string `json:"boardHash"`
+	Date      string `json:"date"`
+	Failures  int    `json:"failures"`
+	Runs      int    `json:"runs"`
+}
+
+// Compaction counts the entries removed or written by Compact.
+type Compaction struct {
+	Observations int
+	Runs         int
+	Days         int
+	ExpiredDays  int
+	Transitions  int
+}
+
+// String renders the counts of the compaction.
+func (c *Compaction) String() string {
+	return fmt.Sprintf("compacted %d runs into %d daily runs, dropped %d fetches, %d expired daily runs and %d board transitions",
+		c.Runs, c.Days, c.Observations, c.ExpiredDays, c.Transitions)
+}
+
+// Compact applies the retention at now: the runs of the days older than
+// RawDays are added to the daily runs of their test and removed with the
+// fetches, and the daily runs and board transitions older than DailyMonths
+// are removed, the last transition of each board kept for its state. The
+// runs before the compaction are not recorded again by Record, so they are
+// counted once. A missing history file has nothing to compact.
+func (s *Store) Compact(now time.Time, retention Retention) (*Compaction, error) {
+	retention = retention.WithDefaults()
+	rawCutoff := now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -retention.RawDays)
+	dailyCutoff := now.UTC().Truncate(24*time.Hour).AddDate(0, -retention.DailyMonths, 0)
+	compaction := &Compaction{}
+	if _, err := os.Stat(s.Path); os.IsNotExist(err) {
+		return compaction, nil
+	}
+
+	db, err := s.open(false)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close() // nolint
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		daily, err := tx.CreateBucketIfNotExists(dailyBucket)
+		if err != nil {
+			return err
+		}
+		if err := compactTests(tx, runsBucket, func(testName string, bucket *bolt.Bucket) error {
+			days := map[string]*DailyRuns{}
+			var order []string
+			if err := deleteBefore(bucket, rawCutoff, func(value []byte) error {
+				var run Run
+				if err := json.Unmarshal(value, &run); err != nil {
+					return err
+				}
+				date := run.Timestamp.UTC().Format(time.DateOnly)
+				dayKey := date + "#" + run.BoardHash
+				if days[dayKey] == nil {
+					days[dayKey] = &DailyRuns{BoardHash: run.BoardHash, Date: date}
+					order = append(order, dayKey)
+				}
+				days[dayKey].Runs++
+				if run.Failed {
+					days[dayKey].Failures++
+				}
+				compaction.Runs++
+				return nil
+			}); err != nil {
+				return err
+			}
+			if len(days) == 0 {
+				return nil
+			}
+			testDaily, err := daily.CreateBucketIfNotExists([]byte(testName))
+			if err != nil {
+				return err
+			}
+			for _, dayKey := range order {
+				day := days[dayKey]
+				date, _ := time.Parse(time.DateOnly, day.Date)
+				k := key(date, day.BoardHash)
+				// the runs recorded late on an already compacted day add up.
+				if value := testDaily.Get(k); value != nil {
+					var previous DailyRuns
+					if err := json.Unmarshal(value, &previous); err != nil {
+						return err
+					}
+					day.Runs += previous.Runs
+					day.Failures += previous.Failures
+				}
+				if err := put(testDaily, k, day); err != nil {
+					return err
+				}
+				compaction.Days++
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		if err := compactTests(tx, observationsBucket, func(_ string, bucket *bolt.Bucket) error {
+			return deleteBefore(bucket, rawCutoff, func([]byte) error {
+				compaction.Observations++
+				return nil
+			})
+		}); err != nil {
+			return err
+		}
+		if err := compactTests(tx, dailyBucket, func(_ string, bucket *bolt.Bucket) error {
+			return deleteBefore(bucket, dailyCutoff, func([]byte) error {
+				compaction.ExpiredDays++
+				return nil
+			})
+		}); err != nil {
+			return err
+		}
+		if err := compactTests(tx, boardsBucket, func(_ string, bucket *bolt.Bucket) error {
+			// the last transition is the current state of the board.
+			lastKey, _ := bucket.Cursor().Last()
+			cutoff := dailyCutoff
+			if lastKey != nil && !keyTime(lastKey).After(dailyCutoff) {
+				cutoff = keyTime(lastKey)
+			}
+			return deleteBefore(bucket, cutoff, func([]byte) error {
+				compaction.Transitions++
+				return nil
+			})
+		}); err != nil {
+			return err
+		}
+		meta, err := tx.CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+		if until := compactedUntil(tx); rawCutoff.After(until) {
+			return put(meta, compactedUntilKey, rawCutoff)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error compacting history: %v", err)
+	}
+	return compaction, nil
+}
+
+// compactedUntil returns the time the raw runs are compacted until, zero
+// before the first compaction.
+func compactedUntil(tx *bolt.Tx) time.Time {
+	var until time.Time
+	if meta := tx.Bucket(metaBucket); meta != nil {
+		if value := meta.Get(compactedUntilKey); value != nil {
+			_ = json.Unmarshal(value, &until)
+		}
+	}
+	return until
+}
+
+// deleteBefore removes the entries of the bucket recorded before the time,
+// calling fn with each removed value.
+func deleteBefore(bucket *bolt.Bucket, before time.Time, fn func(value []byte) error) error {
+	cursor := bucket.Cursor()
+	limit := key(before, "")
+	for k, v := cursor.First(); k != nil && string(k) < string(limit); k, v = cursor.First() {
+		if err := fn(v); err != nil {
+			return err
+		}
+		if err := cursor.Delete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compactTests calls fn with the bucket of each test, or board, of the named
+// bucket and removes the buckets fn left empty. The names are listed first as
+// a bucket can't change while it's iterated.
+func compactTests(tx *bolt.Tx, name []byte, fn func(testName string, bucket *bolt.Bucket) error) error {
+	var testNames []string
+	if err := forEachTest(tx, name, func(testName string, _ *bolt.Bucket) error {
+		testNames = append(testNames, testName)
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, testName := range testNames {
+		parent := tx.Bucket(name)
+		if err := fn(testName, parent.Bucket([]byte(testName))); err != nil {
+			return err
+		}
+		if k, _ := parent.Bucket([]byte(testName)).Cursor().First(); k == nil {
+			if err := parent.DeleteBucket([]byte(testName)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// keyTime returns the time of an entry key, see key.
+func keyTime(k []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(k[:8]))).UTC()
+}
@@ -0,0 +1,367 @@
+// Package store keeps the history of the fetched test results in a local
+// BoltDB file, so the flake rate of a test is computed over days or weeks
+// instead of the TestGrid window of the current fetch.
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+)
+
+// ScoreWindow is the history the flake score of a test is computed on.
+const ScoreWindow = 14 * 24 * time.Hour
+
+// openTimeout bounds the wait for the file lock held by another signalhound
+// recording or querying the history.
+const openTimeout = 5 * time.Second
+
+var (
+	// observationsBucket holds a bucket per test, with the result of the
+	// test on a board at each fetch.
+	observationsBucket = []byte("observations")
+
+	// runsBucket holds a bucket per test, with the result of the test in
+	// each run of a board, recorded once whatever the number of fetches.
+	runsBucket = []byte("runs")
+)
+
+// Observation is the result of a test on a board at a fetch.
+type Observation struct {
+	BoardHash string              `json:"boardHash"`
+	TabState  string              `json:"tabState"`
+	FetchedAt time.Time           `json:"fetchedAt"`
+	Result    v1alpha1.TestResult `json:"result"`
+}
+
+// Run is the result of a test in a run of a board.
+type Run struct {
+	BoardHash string    `json:"boardHash"`
+	Timestamp time.Time `json:"timestamp"`
+	Failed    bool      `json:"failed"`
+}
+
+// History is the recorded history of a test, oldest first. Daily are the
+// runs per day of the compacted history, older than Runs, see Store.Compact.
+type History struct {
+	TestName     string
+	Observations []Observation
+	Runs         []Run
+	Daily        []DailyRuns
+}
+
+// FlakeRate returns the number of red runs and of runs of the history, a
+// flaky run counts as red.
+func (h *History) FlakeRate() (failures, runs int) {
+	for _, day := range h.Daily {
+		failures, runs = failures+day.Failures, runs+day.Runs
+	}
+	for _, run := range h.Runs {
+		if run.Failed {
+			failures++
+		}
+	}
+	return failures, runs + len(h.Runs)
+}
+
+// Day is the number of red runs and of runs of a test on a day.
+type Day struct {
+	Date     string
+	Failures int
+	Runs     int
+}
+
+// Days returns the runs of the history per UTC day, oldest first.
+func (h *History) Days() []Day {
+	var days []Day
+	// the boards of a compacted day are merged.
+	for _, daily := range h.Daily {
+		if len(days) == 0 || days[len(days)-1].Date != daily.Date {
+			days = append(days, Day{Date: daily.Date})
+		}
+		day := &days[len(days)-1]
+		day.Failures, day.Runs = day.Failures+daily.Failures, day.Runs+daily.Runs
+	}
+	for _, run := range h.Runs {
+		date := run.Timestamp.UTC().Format(time.DateOnly)
+		if len(days) == 0 || days[len(days)-1].Date != date {
+			days = append(days, Day{Date: date})
+		}
+		day := &days[len(days)-1]
+		day.Runs++
+		if run.Failed {
+			day.Failures++
+		}
+	}
+	return days
+}
+
+// String renders the flake rate of the history, its runs per day and the
+// boards and states the test was seen in, the fetches repeating the previous
+// one of the board are left out.
+func (h *History) String() string {
+	failures, runs := h.FlakeRate()
+	if runs == 0 && len(h.Observations) == 0 {
+		return fmt.Sprintf("No history recorded for %s.\n", h.TestName)
+	}
+	var text strings.Builder
+	fmt.Fprintf(&text, "%s\n", h.TestName)
+	if runs > 0 {
+		fmt.Fprintf(&text, "%d of %d runs red, %.1f%% flake rate\n", failures, runs, 100*float64(failures)/float64(runs))
+	}
+	text.WriteString("\nRuns per day:\n")
+	for _, day := range h.Days() {
+		fmt.Fprintf(&text, "* %s: %d of %d red\n", day.Date, day.Failures, day.Runs)
+	}
+	text.WriteString("\nFetches:\n")
+	previous := map[string]string{}
+	for _, observation := range h.Observations {
+		seen := fmt.Sprintf("%s/%d/%d", observation.TabState, observation.Result.FailureCount, observation.Result.RunCount)
+		if previous[observation.BoardHash] == seen {
+			continue
+		}
+		previous[observation.BoardHash] = seen
+		fmt.Fprintf(&text, "* %s: %s %s, %d of %d runs red\n", observation.FetchedAt.Format("2006-01-02 15:04"),
+			observation.BoardHash, strings.ToLower(observation.TabState), observation.Result.FailureCount, observation.Result.RunCount)
+	}
+	return text.String()
+}
+
+// Store records the test results in the BoltDB file at Path. The file is
+// opened for each operation, so several signalhound processes share it.
+type Store struct {
+	Path string
+}
+
+// DefaultPath returns the history file location under the user
+// configuration directory, next to the state file.
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "signalhound", "history.db")
+}
+
+// NewStore returns a Store backed by the BoltDB file at path.
+func NewStore(path string) *Store {
+	return &Store{Path: path}
+}
+
+func (s *Store) open(readOnly bool) (*bolt.DB, error) {
+	if !readOnly {
+		if err := os.MkdirAll(filepath.Dir(s.Path), 0o700); err != nil {
+			return nil, fmt.Errorf("error creating history directory: %v", err)
+		}
+	} else if _, err := os.Stat(s.Path); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(s.Path, 0o600, &bolt.Options{Timeout: openTimeout, ReadOnly: readOnly})
+	if err != nil {
+		return nil, fmt.Errorf("error opening history %s: %v", s.Path, err)
+	}
+	return db, nil
+}
+
+// Record saves the results of the tests of the tabs fetched at fetchedAt.
+// The runs already recorded by a previous fetch are overwritten, not
+// counted twice, the runs already compacted are skipped.
+func (s *Store) Record(tabs []*v1alpha1.DashboardTab, fetchedAt time.Time) error {
+	db, err := s.open(false)
+	if err != nil {
+		return err
+	}
+	defer db.Close() // nolint
+
+	return db.Update(func(tx *bolt.Tx) error {
+		observations, err := tx.CreateBucketIfNotExists(observationsBucket)
+		if err != nil {
+			return err
+		}
+		runs, err := tx.CreateBucketIfNotExists(runsBucket)
+		if err != nil {
+			return err
+		}
+		until := compactedUntil(tx)
+		for _, tab := range tabs {
+			for _, test := range tab.TestRuns {
+				testObservations, err := observations.CreateBucketIfNotExists([]byte(test.TestName))
+				if err != nil {
+					return err
+				}
+				observation := Observation{BoardHash: tab.BoardHash, TabState: tab.TabState, FetchedAt: fetchedAt.UTC(), Result: test}
+				if err := put(testObservations, key(fetchedAt, tab.BoardHash), observation); err != nil {
+					return err
+				}
+
+				testRuns, err := runs.CreateBucketIfNotExists([]byte(test.TestName))
+				if err != nil {
+					return err
+				}
+				for _, result := range test.Runs {
+					timestamp := time.UnixMilli(result.Timestamp)
+					if timestamp.Before(until) {
+						continue
+					}
+					run := Run{BoardHash: tab.BoardHash, Timestamp: timestamp.UTC(), Failed: result.Failed}
+					if err := put(testRuns, key(timestamp, tab.BoardHash), run); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// History returns the observations, runs and compacted days of the test
+// since the time, oldest first. A missing history file is an empty history.
+func (s *Store) History(testName string, since time.Time) (*History, error) {
+	history := &History{TestName: testName}
+	db, err := s.open(true)
+	if os.IsNotExist(err) {
+		return history, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer db.Close() // nolint
+
+	err = db.View(func(tx *bolt.Tx) error {
+		if err := scan(tx, observationsBucket, testName, since, func(value []byte) error {
+			var observation Observation
+			if err := json.Unmarshal(value, &observation); err != nil {
+				return err
+			}
+			history.Observations = append(history.Observations, observation)
+			return nil
+		}); err != nil {
+			return err
+		}
+		// the day of the time is kept whole.
+		if err := scan(tx, dailyBucket, testName, since.UTC().Truncate(24*time.Hour), func(value []byte) error {
+			var daily DailyRuns
+			if err := json.Unmarshal(value, &daily); err != nil {
+				return err
+			}
+			history.Daily = append(history.Daily, daily)
+			return nil
+		}); err != nil {
+			return err
+		}
+		return scan(tx, runsBucket, testName, since, func(value []byte) error {
+			var run Run
+			if err := json.Unmarshal(value, &run); err != nil {
+				return err
+			}
+			history.Runs = append(history.Runs, run)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading history: %v", err)
+	}
+	// the keys sort by time then board, the boards interleave.
+	sort.SliceStable(history.Runs, func(i, j int) bool { return history.Runs[i].Timestamp.Before(history.Runs[j].Timestamp) })
+	return history, nil
+}
+
+// Score sets the flake score of the tests of the tabs from their runs on
+// every board recorded since the time, the tests without a recorded run keep
+// the score of their grid.
+func (s *Store) Score(tabs []*v1alpha1.DashboardTab, since time.Time) error {
+	db, err := s.open(true)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer db.Close() // nolint
+
+	type score struct{ failures, runs int }
+	scores := map[string]*score{}
+	err = db.View(func(tx *bolt.Tx) error {
+		for _, tab := range tabs {
+			for _, test := range tab.TestRuns {
+				if scores[test.TestName] != nil {
+					continue
+				}
+				testScore := &score{}
+				scores[test.TestName] = testScore
+				if err := scan(tx, runsBucket, test.TestName, since, func(value []byte) error {
+					var run Run
+					if err := json.Unmarshal(value, &run); err != nil {
+						return err
+					}
+					testScore.runs++
+					if run.Failed {
+						testScore.failures++
+					}
+					return nil
+				}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error reading history: %v", err)
+	}
+	for _, tab := range tabs {
+		for i := range tab.TestRuns {
+			test := &tab.TestRuns[i]
+			if testScore := scores[test.TestName]; testScore.runs > 0 {
+				test.FlakeScore, test.ScoreRuns = 100*testScore.failures/testScore.runs, testScore.runs
+			}
+		}
+	}
+	return nil
+}
+
+// key orders the entries by time, the board tells apart the boards running
+// the test at the same time.
+func key(at time.Time, boardHash string) []byte {
+	k := make([]byte, 8, 8+len(boardHash))
+	binary.BigEndian.PutUint64(k, uint64(at.UnixNano()))
+	return append(k, boardHash...)
+}
+
+func put(bucket *bolt.Bucket, k []byte, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(k, data)
+}
+
+// scan calls fn with the values of the test bucket recorded since the time.
+func scan(tx *bolt.Tx, name []byte, testName string, since time.Time, fn func(value []byte) error) error {
+	parent := tx.Bucket(name)
+	if parent == nil {
+		return nil
+	}
+	bucket := parent.Bucket([]byte(testName))
+	if bucket == nil {
+		return nil
+	}
+	cursor := bucket.Cursor()
+	k, v := cursor.First()
+	if since.After(time.Unix(0, 0)) {
+		k, v = cursor.Seek(key(since, ""))
+	}
+	for ; k != nil; k, v = cursor.Next() {
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
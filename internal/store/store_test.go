@@ -0,0 +1,415 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+)
+
+func TestStore(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "signalhound", "history.db"))
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	at := func(days, hours int) int64 { return day.Add(time.Duration(days*24+hours) * time.Hour).UnixMilli() }
+
+	// a missing file is an empty history
+	history, err := store.History("[sig-node] Pods", time.Time{})
+	assert.NoError(t, err)
+	assert.Empty(t, history.Runs)
+
+	tab := func(state string, runs ...v1alpha1.RunResult) *v1alpha1.DashboardTab {
+		return &v1alpha1.DashboardTab{BoardHash: "sig-release-master-blocking#gce", TabState: state,
+			TestRuns: []v1alpha1.TestResult{{TestName: "[sig-node] Pods", FailureCount: 1, RunCount: 2, Runs: runs}}}
+	}
+	// the second fetch sees again the latest run of the first one
+	assert.NoError(t, store.Record([]*v1alpha1.DashboardTab{tab(v1alpha1.FLAKY_STATUS,
+		v1alpha1.RunResult{Timestamp: at(0, 6), Failed: true}, v1alpha1.RunResult{Timestamp: at(0, 0)})}, day.Add(7*time.Hour)))
+	assert.NoError(t, store.Record([]*v1alpha1.DashboardTab{tab(v1alpha1.FAILING_STATUS,
+		v1alpha1.RunResult{Timestamp: at(1, 0), Failed: true}, v1alpha1.RunResult{Timestamp: at(0, 6), Failed: true})}, day.Add(25*time.Hour)))
+
+	history, err = store.History("[sig-node] Pods", time.Time{})
+	assert.NoError(t, err)
+	if assert.Len(t, history.Observations, 2) {
+		assert.Equal(t, v1alpha1.FLAKY_STATUS, history.Observations[0].TabState)
+		assert.Equal(t, 1, history.Observations[1].Result.FailureCount)
+	}
+	failures, runs := history.FlakeRate()
+	assert.Equal(t, 2, failures)
+	assert.Equal(t, 3, runs)
+	assert.Equal(t, []Day{{Date: "2026-10-01", Failures: 1, Runs: 2}, {Date: "2026-10-02", Failures: 1, Runs: 1}}, history.Days())
+
+	assert.Equal(t, `[sig-node] Pods
+2 of 3 runs red, 66.7% flake rate
+
+Runs per day:
+* 2026-10-01: 1 of 2 red
+* 2026-10-02: 1 of 1 red
+
+Fetches:
+* 2026-10-01 07:00: sig-release-master-blocking#gce flaky, 1 of 2 runs red
+* 2026-10-02 01:00: sig-release-master-blocking#gce failing, 1 of 2 runs red
+`, history.String())
+
+	history, err = store.History("[sig-node] Pods", day.Add(12*time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, history.Observations, 1)
+	assert.Len(t, history.Runs, 1)
+
+	history, err = store.History("[sig-network] DNS", time.Time{})
+	assert.NoError(t, err)
+	assert.Empty(t, history.Observations)
+	assert.Equal(t, "No history recorded for [sig-network] DNS.\n", history.String())
+}
+
+func TestScore(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "history.db"))
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	run := func(hours int, failed bool) v1alpha1.RunResult {
+		return v1alpha1.RunResult{Timestamp: day.Add(time.Duration(hours) * time.Hour).UnixMilli(), Failed: failed}
+	}
+	tabs := func() []*v1alpha1.DashboardTab {
+		return []*v1alpha1.DashboardTab{
+			{BoardHash: "sig-release-master-blocking#gce", TestRuns: []v1alpha1.TestResult{
+				{TestName: "[sig-node] Pods", FlakeScore: 50, ScoreRuns: 2, Runs: []v1alpha1.RunResult{run(6, true), run(0, false)}},
+				{TestName: "[sig-network] DNS", FlakeScore: 100, ScoreRuns: 1},
+			}},
+			{BoardHash: "sig-release-master-informing#ec2", TestRuns: []v1alpha1.TestResult{
+				{TestName: "[sig-node] Pods", FlakeScore: 0, ScoreRuns: 2, Runs: []v1alpha1.RunResult{run(4, false), run(2, false)}},
+			}},
+		}
+	}
+
+	// without a history the grid scores are kept
+	scored := tabs()
+	assert.NoError(t, store.Score(scored, time.Time{}))
+	assert.Equal(t, 50, scored[0].TestRuns[0].FlakeScore)
+
+	assert.NoError(t, store.Record(tabs(), day.Add(7*time.Hour)))
+	scored = tabs()
+	assert.NoError(t, store.Score(scored, time.Time{}))
+	// the runs of both boards are scored together
+	assert.Equal(t, 25, scored[0].TestRuns[0].FlakeScore)
+	assert.Equal(t, 4, scored[0].TestRuns[0].ScoreRuns)
+	assert.Equal(t, 25, scored[1].TestRuns[0].FlakeScore)
+	// a test without a recorded run keeps its grid score
+	assert.Equal(t, 100, scored[0].TestRuns[1].FlakeScore)
+
+	scored = tabs()
+	assert.NoError(t, store.Score(scored, day.Add(3*time.Hour)))
+	assert.Equal(t, 50, scored[0].TestRuns[0].FlakeScore)
+	assert.Equal(t, 2, scored[0].TestRuns[0].ScoreRuns)
+}
+
+func TestSummary(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "history.db"))
+	day := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	summary, err := store.Summary(day)
+	assert.NoError(t, err)
+	assert.Equal(t, "No history recorded since 2026-10-01.\n", summary.String())
+
+	tab := func(boardHash, state string, tests ...v1alpha1.TestResult) *v1alpha1.DashboardTab {
+		return &v1alpha1.DashboardTab{BoardHash: boardHash, TabState: state, TestRuns: tests}
+	}
+	flaky := v1alpha1.TestResult{TestName: "[sig-network] DNS", Runs: []v1alpha1.RunResult{
+		{Timestamp: day.Add(24 * time.Hour).UnixMilli(), Failed: true}, {Timestamp: day.Add(25 * time.Hour).UnixMilli()}}}
+	// the test failing before the summary start is not new
+	assert.NoError(t, store.Record([]*v1alpha1.DashboardTab{
+		tab("sig-release-master-blocking#gce", v1alpha1.FAILING_STATUS, v1alpha1.TestResult{TestName: "[sig-node] Pods"}),
+	}, day.Add(-24*time.Hour)))
+	assert.NoError(t, store.Record([]*v1alpha1.DashboardTab{
+		tab("sig-release-master-blocking#gce", v1alpha1.FAILING_STATUS, v1alpha1.TestResult{TestName: "[sig-node] Pods"},
+			v1alpha1.TestResult{TestName: "[sig-apps] Deployment"}),
+		tab("sig-release-master-informing#kind", v1alpha1.FLAKY_STATUS, flaky),
+	}, day.Add(26*time.Hour)))
+	// the Pods test recovered, the Deployment one still fails
+	assert.NoError(t, store.Record([]*v1alpha1.DashboardTab{
+		tab("sig-release-master-blocking#gce", v1alpha1.FAILING_STATUS, v1alpha1.TestResult{TestName: "[sig-apps] Deployment"}),
+	}, day.Add(50*time.Hour)))
+
+	summary, err = store.Summary(day)
+	assert.NoError(t, err)
+	assert.Equal(t, []SummaryTest{{"[sig-apps] Deployment", "sig-release-master-blocking#gce", day.Add(26 * time.Hour)}}, summary.NewFailures)
+	assert.Equal(t, []SummaryTest{{"[sig-node] Pods", "sig-release-master-blocking#gce", day.Add(26 * time.Hour)}}, summary.Resolved)
+	assert.Equal(t, []Flake{{TestName: "[sig-network] DNS", Failures: 1, Runs: 2}}, summary.TopFlakes)
+	assert.Equal(t, `# CI Signal summary, 2026-10-01 to 2026-10-03
+
+## New failures
+
+* `+"`[sig-apps] Deployment`"+` on sig-release-master-blocking#gce, failing since 2026-10-02 14:00
+
+## Resolved failures
+
+* `+"`[sig-node] Pods`"+` on sig-release-master-blocking#gce, last failing on 2026-10-02 14:00
+
+## Top flakes
+
+* `+"`[sig-network] DNS`"+`: 1 of 2 runs red (50.0%)
+
+## Board health
+
+Failing / flaky tabs per day.
+
+| Day | sig-release-master-blocking | sig-release-master-informing |
+|-----|-----|-----|
+| 2026-10-02 | 1 / 0 | 0 / 1 |
+| 2026-10-03 | 1 / 0 | 0 / 0 |
+`, summary.String())
+}
+
+func TestFailures(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "history.db"))
+	day := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	failures, err := store.Failures(day)
+	assert.NoError(t, err)
+	assert.Empty(t, failures)
+
+	pods := v1alpha1.TestResult{TestName: "[sig-node] Pods"}
+	dns := v1alpha1.TestResult{TestName: "[sig-network] DNS"}
+	assert.NoError(t, store.Record([]*v1alpha1.DashboardTab{
+		{BoardHash: "sig-release-master-blocking#gce", TabState: v1alpha1.FAILING_STATUS, TestRuns: []v1alpha1.TestResult{pods}},
+	}, day.Add(-24*time.Hour)))
+	assert.NoError(t, store.Record([]*v1alpha1.DashboardTab{
+		{BoardHash: "sig-release-master-informing#kind", TabState: v1alpha1.FLAKY_STATUS, TestRuns: []v1alpha1.TestResult{dns}},
+	}, day.Add(time.Hour)))
+	assert.NoError(t, store.Record([]*v1alpha1.DashboardTab{
+		{BoardHash: "sig-release-master-informing#kind", TabState: v1alpha1.FAILING_STATUS, TestRuns: []v1alpha1.TestResult{dns}},
+		{BoardHash: "sig-release-master-blocking#gce", TabState: v1alpha1.FAILING_STATUS, TestRuns: []v1alpha1.TestResult{pods}},
+	}, day.Add(2*time.Hour)))
+
+	failures, err = store.Failures(day)
+	assert.NoError(t, err)
+	assert.Equal(t, []Failure{
+		{TestName: "[sig-network] DNS", BoardHash: "sig-release-master-informing#kind", TabState: v1alpha1.FAILING_STATUS,
+			FirstSeen: day.Add(time.Hour), LastSeen: day.Add(2 * time.Hour)},
+		{TestName: "[sig-node] Pods", BoardHash: "sig-release-master-blocking#gce", TabState: v1alpha1.FAILING_STATUS,
+			FirstSeen: day.Add(2 * time.Hour), LastSeen: day.Add(2 * time.Hour)},
+	}, failures)
+}
+
+func TestFlakeTrends(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "history.db"))
+	until := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	week := 7 * 24 * time.Hour
+
+	trends, err := store.FlakeTrends(until, week)
+	assert.NoError(t, err)
+	assert.Empty(t, trends.Tests)
+
+	// runs returns a run a day from the time, the first failed ones red.
+	runs := func(from time.Time, count, failed int) []v1alpha1.RunResult {
+		var results []v1alpha1.RunResult
+		for i := 0; i < count; i++ {
+			results = append(results, v1alpha1.RunResult{Timestamp: from.Add(time.Duration(i) * 24 * time.Hour).UnixMilli(), Failed: i < failed})
+		}
+		return results
+	}
+	previous, current := until.Add(-2*week), until.Add(-week)
+	test := func(name, sigName string, previousFailed, failed int) v1alpha1.TestResult {
+		return v1alpha1.TestResult{TestName: name, Sig: sigName, Runs: append(runs(previous, 4, previousFailed), runs(current, 4, failed)...)}
+	}
+	assert.NoError(t, store.Record([]*v1alpha1.DashboardTab{{
+		BoardHash: "sig-release-master-blocking#gce", TabState: v1alpha1.FLAKY_STATUS,
+		TestRuns: []v1alpha1.TestResult{
+			test("[sig-node] Pods", "", 0, 1),
+			test("[sig-node] Probes", "", 1, 3),
+			test("[sig-node] Volumes", "", 1, 1),
+			test("Kubelet restarts", "node", 3, 1),
+			test("[sig-network] DNS", "", 2, 0),
+		},
+	}}, until))
+
+	trends, err = store.FlakeTrends(until, week)
+	assert.NoError(t, err)
+	assert.Equal(t, []FlakeTrend{
+		{TestName: "[sig-node] Probes", SIG: "node", Trend: WorseningFlake, Failures: 3, Runs: 4, PreviousFailures: 1, PreviousRuns: 4},
+		{TestName: "Kubelet restarts", SIG: "node", Trend: ImprovedFlake, Failures: 1, Runs: 4, PreviousFailures: 3, PreviousRuns: 4},
+		{TestName: "[sig-node] Pods", SIG: "node", Trend: NewFlake, Failures: 1, Runs: 4, PreviousRuns: 4},
+		{TestName: "[sig-network] DNS", SIG: "network", Trend: ResolvedFlake, Runs: 4, PreviousFailures: 2, PreviousRuns: 4},
+	}, trends.Tests)
+	assert.Equal(t, []string{"network", "node"}, trends.SIGs())
+	assert.Equal(t, `# sig-node flake trends, 2026-10-08 to 2026-10-15
+
+## New flakes
+
+* `+"`[sig-node] Pods`"+`: 1 of 4 runs red (25.0%), 0 of 4 the period before (0.0%)
+
+## Worsening flakes
+
+* `+"`[sig-node] Probes`"+`: 3 of 4 runs red (75.0%), 1 of 4 the period before (25.0%)
+
+## Improved flakes
+
+* `+"`Kubelet restarts`"+`: 1 of 4 runs red (25.0%), 3 of 4 the period before (75.0%)
+
+## Resolved flakes
+
+None.
+`, trends.Report("node"))
+}
+
+func TestBoardTrends(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "history.db"))
+	until := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	at := func(days, hours int) time.Time { return until.Add(time.Duration(days*24+hours) * time.Hour) }
+	master, informing := "sig-release-master-blocking", "sig-release-master-informing"
+	tab := func(board, state string) *v1alpha1.DashboardTab {
+		return &v1alpha1.DashboardTab{BoardHash: board + "#gce", TabState: state}
+	}
+
+	trends, err := store.BoardTrends(until, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, "No board recorded since 2026-10-08.\n", trends.String())
+
+	record := func(fetchedAt time.Time, tabs ...*v1alpha1.DashboardTab) []BoardTransition {
+		transitions, err := store.RecordBoards([]string{master, informing}, tabs, fetchedAt)
+		assert.NoError(t, err)
+		return transitions
+	}
+	assert.Equal(t, []BoardTransition{
+		{Board: master, To: v1alpha1.PASSING_STATUS, At: at(-9, 0)},
+		{Board: informing, To: v1alpha1.FLAKY_STATUS, At: at(-9, 0)},
+	}, record(at(-9, 0), tab(informing, v1alpha1.FLAKY_STATUS)))
+	// an unchanged state is not recorded again
+	assert.Empty(t, record(at(-8, 0), tab(informing, v1alpha1.FLAKY_STATUS)))
+	transitions := record(at(-4, 6), tab(master, v1alpha1.FLAKY_STATUS), tab(master, v1alpha1.FAILING_STATUS))
+	if assert.Len(t, transitions, 2) {
+		assert.True(t, transitions[0].Degraded())
+		assert.Equal(t, "sig-release-master-blocking degraded from passing to failing", transitions[0].String())
+		assert.False(t, transitions[1].Degraded())
+		assert.Equal(t, "sig-release-master-informing improved from flaky to passing", transitions[1].String())
+	}
+	record(at(-2, 12), tab(master, v1alpha1.FLAKY_STATUS))
+	record(at(-2, 18))
+	// the fetches after the period are left out
+	record(at(0, 1), tab(master, v1alpha1.FAILING_STATUS))
+
+	trends, err = store.BoardTrends(until, 7)
+	assert.NoError(t, err)
+	if assert.Len(t, trends.Boards, 2) {
+		assert.Equal(t, "▁▁▁███▁", trends.Boards[0].Sparkline())
+		assert.Equal(t, "▄▄▄▄▁▁▁", trends.Boards[1].Sparkline())
+	}
+	assert.Equal(t, `# Board health, 2026-10-08 to 2026-10-15
+
+▁▁▁███▁ sig-release-master-blocking passing
+* 2026-10-11 06:00: sig-release-master-blocking degraded from passing to failing
+* 2026-10-13 12:00: sig-release-master-blocking improved from failing to flaky
+* 2026-10-13 18:00: sig-release-master-blocking improved from flaky to passing
+
+▄▄▄▄▁▁▁ sig-release-master-informing passing
+* 2026-10-11 06:00: sig-release-master-informing improved from flaky to passing
+`, trends.String())
+}
+
+func TestDigest(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "history.db"))
+	since := time.Date(2026, 10, 15, 6, 0, 0, 0, time.UTC)
+	until := since.Add(24 * time.Hour)
+	branch, master := "sig-release-1.35-blocking", "sig-release-master-blocking"
+	tab := func(boardHash, state string, tests ...string) *v1alpha1.DashboardTab {
+		tab := &v1alpha1.DashboardTab{BoardHash: boardHash, TabState: state}
+		for _, test := range tests {
+			tab.TestRuns = append(tab.TestRuns, v1alpha1.TestResult{TestName: test})
+		}
+		return tab
+	}
+	record := func(fetchedAt time.Time, tabs ...*v1alpha1.DashboardTab) {
+		assert.NoError(t, store.Record(tabs, fetchedAt))
+		_, err := store.RecordBoards([]string{branch, master}, tabs, fetchedAt)
+		assert.NoError(t, err)
+	}
+
+	summary, err := store.Summary(since)
+	assert.NoError(t, err)
+	trends, err := store.BoardTrends(until, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "No history recorded for 1.35 since 2026-10-15 06:00.\n", summary.Digest("1.35", trends))
+
+	record(since.Add(-time.Hour), tab(branch+"#gce", v1alpha1.PASSING_STATUS))
+	record(since.Add(time.Hour), tab(branch+"#gce", v1alpha1.FAILING_STATUS, "[sig-node] Pods"))
+	// the failures of master are left out of the digest of the branch
+	record(since.Add(20*time.Hour), tab(branch+"#gce", v1alpha1.FAILING_STATUS, "[sig-apps] Deployment"),
+		tab(master+"#gce", v1alpha1.FAILING_STATUS, "[sig-network] DNS"))
+
+	summary, err = store.Summary(since)
+	assert.NoError(t, err)
+	trends, err = store.BoardTrends(until, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, `### CI Signal digest of 1.35, 2026-10-15 06:00 to 2026-10-16 02:00 UTC
+
+#### Boards
+
+* sig-release-1.35-blocking: failing
+  * 2026-10-15 07:00: sig-release-1.35-blocking degraded from passing to failing
+
+#### New failures
+
+* `+"`[sig-node] Pods`"+` on sig-release-1.35-blocking#gce, failing since 2026-10-15 07:00
+* `+"`[sig-apps] Deployment`"+` on sig-release-1.35-blocking#gce, failing since 2026-10-16 02:00
+
+#### Resolved failures
+
+* `+"`[sig-node] Pods`"+` on sig-release-1.35-blocking#gce, last failing on 2026-10-15 07:00
+`, summary.Digest("1.35", trends))
+}
+
+func TestCompact(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "history.db"))
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	at := func(days, hours int) time.Time { return now.Add(time.Duration(days*24+hours) * time.Hour) }
+	run := func(days, hours int, failed bool) v1alpha1.RunResult {
+		return v1alpha1.RunResult{Timestamp: at(days, hours).UnixMilli(), Failed: failed}
+	}
+	tabs := func(runs ...v1alpha1.RunResult) []*v1alpha1.DashboardTab {
+		return []*v1alpha1.DashboardTab{{BoardHash: "sig-release-master-blocking#gce", TabState: v1alpha1.FLAKY_STATUS,
+			TestRuns: []v1alpha1.TestResult{{TestName: "[sig-node] Pods", Runs: runs}}}}
+	}
+	retention := Retention{RawDays: 14, DailyMonths: 1}
+	assert.Error(t, Retention{RawDays: 7}.Validate())
+	assert.NoError(t, retention.Validate())
+
+	// a missing file has nothing to compact
+	compaction, err := store.Compact(now, retention)
+	assert.NoError(t, err)
+	assert.Equal(t, &Compaction{}, compaction)
+
+	assert.NoError(t, store.Record(tabs(run(-50, 0, true)), at(-50, 1)))
+	assert.NoError(t, store.Record(tabs(run(-20, 6, true), run(-20, 0, false)), at(-20, 7)))
+	assert.NoError(t, store.Record(tabs(run(-1, 0, false)), at(-1, 1)))
+	_, err = store.RecordBoards([]string{"sig-release-master-blocking"}, tabs(), at(-50, 1))
+	assert.NoError(t, err)
+	_, err = store.RecordBoards([]string{"sig-release-master-blocking"}, nil, at(-40, 1))
+	assert.NoError(t, err)
+
+	compaction, err = store.Compact(now, retention)
+	assert.NoError(t, err)
+	assert.Equal(t, &Compaction{Observations: 2, Runs: 3, Days: 2, ExpiredDays: 1, Transitions: 1}, compaction)
+	assert.Equal(t, "compacted 3 runs into 2 daily runs, dropped 2 fetches, 1 expired daily runs and 1 board transitions", compaction.String())
+
+	history, err := store.History("[sig-node] Pods", time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, history.Observations, 1)
+	assert.Len(t, history.Runs, 1)
+	assert.Equal(t, []DailyRuns{{BoardHash: "sig-release-master-blocking#gce", Date: "2026-09-25", Failures: 1, Runs: 2}}, history.Daily)
+	failures, runs := history.FlakeRate()
+	assert.Equal(t, 1, failures)
+	assert.Equal(t, 3, runs)
+	assert.Equal(t, []Day{{Date: "2026-09-25", Failures: 1, Runs: 2}, {Date: "2026-10-14", Runs: 1}}, history.Days())
+
+	// the compacted runs fetched again are not counted twice
+	assert.NoError(t, store.Record(tabs(run(-20, 6, true), run(-1, 0, false)), now))
+	history, err = store.History("[sig-node] Pods", time.Time{})
+	assert.NoError(t, err)
+	_, runs = history.FlakeRate()
+	assert.Equal(t, 3, runs)
+
+	// the last board transition is kept
+	trends, err := store.BoardTrends(now, 7)
+	assert.NoError(t, err)
+	assert.Len(t, trends.Boards, 1)
+}
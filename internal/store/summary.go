@@ -0,0 +1,349 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+)
+
+// topFlakes is the number of flaky tests listed in a summary.
+const topFlakes = 10
+
+// SummaryTest is a test failing on a board in a summary, At is the first
+// failing fetch of a new failure or the last one of a resolved failure.
+type SummaryTest struct {
+	TestName  string
+	BoardHash string
+	At        time.Time
+}
+
+// Flake is a flaky test of a summary with its runs since the summary start.
+type Flake struct {
+	TestName string
+	Failures int
+	Runs     int
+}
+
+// BoardDay is the number of failing and flaky tabs of a board on a day.
+type BoardDay struct {
+	Failing int
+	Flaky   int
+}
+
+// Summary aggregates the history of every test since a time in the weekly
+// CI Signal report format.
+type Summary struct {
+	Since time.Time
+
+	// LastFetch is the latest recorded fetch, zero without history.
+	LastFetch time.Time
+
+	// NewFailures are the tests failing on a board for the first time since
+	// the summary start.
+	NewFailures []SummaryTest
+
+	// Resolved are the tests failing on a board since the summary start and
+	// missing from the latest fetch of the board.
+	Resolved []SummaryTest
+
+	// TopFlakes are the tests seen flaky since the summary start with the
+	// most red runs.
+	TopFlakes []Flake
+
+	// Days are the UTC days with a fetch, oldest first, and Boards the
+	// failing and flaky tabs of the boards on each of them.
+	Days   []string
+	Boards map[string]map[string]BoardDay
+}
+
+// Summary returns the summary of the history recorded since the time. A
+// missing history file is an empty summary.
+func (s *Store) Summary(since time.Time) (*Summary, error) {
+	summary := &Summary{Since: since.UTC(), Boards: map[string]map[string]BoardDay{}}
+	db, err := s.open(true)
+	if os.IsNotExist(err) {
+		return summary, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer db.Close() // nolint
+
+	type testBoard struct{ testName, boardHash string }
+	type seen struct{ firstFailing, lastFailing, last time.Time }
+	var (
+		tests  = map[testBoard]*seen{}
+		flaky  = map[string]bool{}
+		states = map[string]map[string]string{} // day, then tab, to its worst state
+	)
+	err = db.View(func(tx *bolt.Tx) error {
+		if err := forEachTest(tx, observationsBucket, func(testName string, bucket *bolt.Bucket) error {
+			return bucket.ForEach(func(_, value []byte) error {
+				var observation Observation
+				if err := json.Unmarshal(value, &observation); err != nil {
+					return err
+				}
+				at := observation.FetchedAt
+				if at.After(summary.LastFetch) {
+					summary.LastFetch = at
+				}
+				key := testBoard{testName, observation.BoardHash}
+				if tests[key] == nil {
+					tests[key] = &seen{}
+				}
+				test := tests[key]
+				if at.After(test.last) {
+					test.last = at
+				}
+				if observation.TabState == v1alpha1.FAILING_STATUS {
+					if test.firstFailing.IsZero() || at.Before(test.firstFailing) {
+						test.firstFailing = at
+					}
+					if at.After(test.lastFailing) {
+						test.lastFailing = at
+					}
+				}
+				if at.Before(since) {
+					return nil
+				}
+				if observation.TabState == v1alpha1.FLAKY_STATUS {
+					flaky[testName] = true
+				}
+				day := at.UTC().Format(time.DateOnly)
+				if states[day] == nil {
+					states[day] = map[string]string{}
+				}
+				if states[day][observation.BoardHash] != v1alpha1.FAILING_STATUS {
+					states[day][observation.BoardHash] = observation.TabState
+				}
+				return nil
+			})
+		}); err != nil {
+			return err
+		}
+
+		for testName := range flaky {
+			flake := Flake{TestName: testName}
+			if err := scan(tx, runsBucket, testName, since, func(value []byte) error {
+				var run Run
+				if err := json.Unmarshal(value, &run); err != nil {
+					return err
+				}
+				flake.Runs++
+				if run.Failed {
+					flake.Failures++
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			summary.TopFlakes = append(summary.TopFlakes, flake)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading history: %v", err)
+	}
+
+	for key, test := range tests {
+		if !test.firstFailing.Before(since) {
+			summary.NewFailures = append(summary.NewFailures, SummaryTest{key.testName, key.boardHash, test.firstFailing})
+		}
+		if !test.lastFailing.Before(since) && test.last.Before(summary.LastFetch) {
+			summary.Resolved = append(summary.Resolved, SummaryTest{key.testName, key.boardHash, test.lastFailing})
+		}
+	}
+	sortTests(summary.NewFailures)
+	sortTests(summary.Resolved)
+	sort.Slice(summary.TopFlakes, func(i, j int) bool {
+		a, b := summary.TopFlakes[i], summary.TopFlakes[j]
+		if a.Failures != b.Failures {
+			return a.Failures > b.Failures
+		}
+		return a.TestName < b.TestName
+	})
+	summary.TopFlakes = summary.TopFlakes[:min(len(summary.TopFlakes), topFlakes)]
+
+	for day, tabs := range states {
+		summary.Days = append(summary.Days, day)
+		for boardHash, state := range tabs {
+			board, _, _ := strings.Cut(boardHash, "#")
+			if summary.Boards[board] == nil {
+				summary.Boards[board] = map[string]BoardDay{}
+			}
+			boardDay := summary.Boards[board][day]
+			if state == v1alpha1.FAILING_STATUS {
+				boardDay.Failing++
+			} else {
+				boardDay.Flaky++
+			}
+			summary.Boards[board][day] = boardDay
+		}
+	}
+	sort.Strings(summary.Days)
+	return summary, nil
+}
+
+// String renders the summary in Markdown, ready to paste in the release team
+// meeting notes.
+func (s *Summary) String() string {
+	if s.LastFetch.IsZero() {
+		return fmt.Sprintf("No history recorded since %s.\n", s.Since.Format(time.DateOnly))
+	}
+	var text strings.Builder
+	fmt.Fprintf(&text, "# CI Signal summary, %s to %s\n", s.Since.Format(time.DateOnly), s.LastFetch.Format(time.DateOnly))
+
+	text.WriteString("\n## New failures\n\n")
+	writeTests(&text, s.NewFailures, "failing since")
+	text.WriteString("\n## Resolved failures\n\n")
+	writeTests(&text, s.Resolved, "last failing on")
+
+	text.WriteString("\n## Top flakes\n\n")
+	if len(s.TopFlakes) == 0 {
+		text.WriteString("None.\n")
+	}
+	for _, flake := range s.TopFlakes {
+		fmt.Fprintf(&text, "* `%s`: %d of %d runs red", flake.TestName, flake.Failures, flake.Runs)
+		if flake.Runs > 0 {
+			fmt.Fprintf(&text, " (%.1f%%)", 100*float64(flake.Failures)/float64(flake.Runs))
+		}
+		text.WriteString("\n")
+	}
+
+	text.WriteString("\n## Board health\n\nFailing / flaky tabs per day.\n\n")
+	var boards []string
+	for board := range s.Boards {
+		boards = append(boards, board)
+	}
+	sort.Strings(boards)
+	fmt.Fprintf(&text, "| Day | %s |\n|-----|%s\n", strings.Join(boards, " | "), strings.Repeat("-----|", len(boards)))
+	for _, day := range s.Days {
+		fmt.Fprintf(&text, "| %s |", day)
+		for _, board := range boards {
+			boardDay := s.Boards[board][day]
+			fmt.Fprintf(&text, " %d / %d |", boardDay.Failing, boardDay.Flaky)
+		}
+		text.WriteString("\n")
+	}
+	return text.String()
+}
+
+// writeTests writes a line per test, or None. without tests.
+func writeTests(text *strings.Builder, tests []SummaryTest, label string) {
+	if len(tests) == 0 {
+		text.WriteString("None.\n")
+	}
+	for _, test := range tests {
+		fmt.Fprintf(text, "* `%s` on %s, %s %s\n", test.TestName, test.BoardHash, label, test.At.Format("2006-01-02 15:04"))
+	}
+}
+
+// sortTests orders the tests by time, then name and board.
+func sortTests(tests []SummaryTest) {
+	sort.Slice(tests, func(i, j int) bool {
+		if !tests[i].At.Equal(tests[j].At) {
+			return tests[i].At.Before(tests[j].At)
+		}
+		if tests[i].TestName != tests[j].TestName {
+			return tests[i].TestName < tests[j].TestName
+		}
+		return tests[i].BoardHash < tests[j].BoardHash
+	})
+}
+
+// forEachTest calls fn with the name and the bucket of every test of the
+// bucket.
+func forEachTest(tx *bolt.Tx, name []byte, fn func(testName string, bucket *bolt.Bucket) error) error {
+	parent := tx.Bucket(name)
+	if parent == nil {
+		return nil
+	}
+	return parent.ForEach(func(k, v []byte) error {
+		// the nested buckets have no value.
+		if v != nil {
+			return nil
+		}
+		return fn(string(k), parent.Bucket(k))
+	})
+}
+
+// Failure is a test failing or flaky on a board over a period, seen from the
+// first to the last fetch of the period.
+type Failure struct {
+	TestName  string
+	BoardHash string
+	TabState  string
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+// Failures returns the tests recorded failing or flaky on a board since the
+// time, the oldest first, failing wins over flaky on a board. A missing
+// history file returns no failure.
+func (s *Store) Failures(since time.Time) ([]Failure, error) {
+	db, err := s.open(true)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer db.Close() // nolint
+
+	type testBoard struct{ testName, boardHash string }
+	seen := map[testBoard]*Failure{}
+	err = db.View(func(tx *bolt.Tx) error {
+		return forEachTest(tx, observationsBucket, func(testName string, bucket *bolt.Bucket) error {
+			return bucket.ForEach(func(_, value []byte) error {
+				var observation Observation
+				if err := json.Unmarshal(value, &observation); err != nil {
+					return err
+				}
+				at := observation.FetchedAt
+				if at.Before(since) {
+					return nil
+				}
+				key := testBoard{testName, observation.BoardHash}
+				failure := seen[key]
+				if failure == nil {
+					failure = &Failure{TestName: testName, BoardHash: observation.BoardHash, TabState: observation.TabState, FirstSeen: at, LastSeen: at}
+					seen[key] = failure
+				}
+				if at.Before(failure.FirstSeen) {
+					failure.FirstSeen = at
+				}
+				if at.After(failure.LastSeen) {
+					failure.LastSeen = at
+				}
+				if observation.TabState == v1alpha1.FAILING_STATUS {
+					failure.TabState = v1alpha1.FAILING_STATUS
+				}
+				return nil
+			})
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading history: %v", err)
+	}
+
+	failures := make([]Failure, 0, len(seen))
+	for _, failure := range seen {
+		failures = append(failures, *failure)
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		a, b := failures[i], failures[j]
+		if !a.FirstSeen.Equal(b.FirstSeen) {
+			return a.FirstSeen.Before(b.FirstSeen)
+		}
+		if a.TestName != b.TestName {
+			return a.TestName < b.TestName
+		}
+		return a.BoardHash < b.BoardHash
+	})
+	return failures, nil
+}
@@ -0,0 +1,203 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"sigs.k8s.io/signalhound/internal/sig"
+)
+
+// trendDelta is the change of flake rate between two periods a flaky test
+// needs to be worsening or improved.
+const trendDelta = 0.05
+
+// Trend is the change of the flake rate of a test from a period to the next.
+type Trend string
+
+const (
+	// NewFlake has red runs in the period and none in the previous one.
+	NewFlake Trend = "new"
+	// WorseningFlake has a flake rate up by more than 5 points.
+	WorseningFlake Trend = "worsening"
+	// ImprovedFlake has a flake rate down by more than 5 points, but still
+	// red runs.
+	ImprovedFlake Trend = "improved"
+	// ResolvedFlake has red runs in the previous period and only green runs
+	// in the period.
+	ResolvedFlake Trend = "resolved"
+)
+
+// trends are the trends in the order of the reports.
+var trends = []Trend{NewFlake, WorseningFlake, ImprovedFlake, ResolvedFlake}
+
+// FlakeTrend is the flake rate of a test over a period and the previous one.
+type FlakeTrend struct {
+	TestName string
+	SIG      string
+	Trend    Trend
+
+	Failures, Runs                 int
+	PreviousFailures, PreviousRuns int
+}
+
+// FlakeTrends are the tests whose flake rate changed from a period to the
+// next, the steady ones are left out.
+type FlakeTrends struct {
+	Since, Until time.Time
+	Tests        []FlakeTrend
+}
+
+// FlakeTrends compares the runs of every test over the period ending at
+// until with the period before it. The SIG of a test is the one recorded by
+// the latest fetch, or the [sig-*] tag of its name. A missing history file
+// returns no trend.
+func (s *Store) FlakeTrends(until time.Time, period time.Duration) (*FlakeTrends, error) {
+	since := until.Add(-period)
+	result := &FlakeTrends{Since: since.UTC(), Until: until.UTC()}
+	db, err := s.open(true)
+	if os.IsNotExist(err) {
+		return result, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer db.Close() // nolint
+
+	err = db.View(func(tx *bolt.Tx) error {
+		return forEachTest(tx, runsBucket, func(testName string, _ *bolt.Bucket) error {
+			test := FlakeTrend{TestName: testName}
+			if err := scan(tx, runsBucket, testName, since.Add(-period), func(value []byte) error {
+				var run Run
+				if err := json.Unmarshal(value, &run); err != nil {
+					return err
+				}
+				switch {
+				case !run.Timestamp.Before(until):
+				case run.Timestamp.Before(since):
+					test.PreviousRuns++
+					if run.Failed {
+						test.PreviousFailures++
+					}
+				default:
+					test.Runs++
+					if run.Failed {
+						test.Failures++
+					}
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			if test.Trend = trendOf(&test); test.Trend == "" {
+				return nil
+			}
+
+			if err := scan(tx, observationsBucket, testName, since.Add(-period), func(value []byte) error {
+				var observation Observation
+				if err := json.Unmarshal(value, &observation); err != nil {
+					return err
+				}
+				if observation.Result.Sig != "" {
+					test.SIG = observation.Result.Sig
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			if test.SIG == "" {
+				test.SIG = sig.FromTestName(testName)
+			}
+			result.Tests = append(result.Tests, test)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading history: %v", err)
+	}
+
+	sort.Slice(result.Tests, func(i, j int) bool {
+		a, b := result.Tests[i], result.Tests[j]
+		if a.Failures != b.Failures {
+			return a.Failures > b.Failures
+		}
+		return a.TestName < b.TestName
+	})
+	return result, nil
+}
+
+// trendOf returns the trend of the test runs, empty without run in the period
+// or when the flake rate is steady.
+func trendOf(test *FlakeTrend) Trend {
+	if test.Runs == 0 || test.Failures == 0 && test.PreviousFailures == 0 {
+		return ""
+	}
+	if test.PreviousFailures == 0 {
+		return NewFlake
+	}
+	if test.Failures == 0 {
+		return ResolvedFlake
+	}
+	rate := float64(test.Failures) / float64(test.Runs)
+	previous := float64(test.PreviousFailures) / float64(test.PreviousRuns)
+	switch {
+	case rate > previous+trendDelta:
+		return WorseningFlake
+	case rate < previous-trendDelta:
+		return ImprovedFlake
+	}
+	return ""
+}
+
+// SIGs returns the SIGs with a trend, sorted, the tests without SIG are left
+// out.
+func (t *FlakeTrends) SIGs() []string {
+	seen := map[string]bool{}
+	var sigs []string
+	for _, test := range t.Tests {
+		if test.SIG != "" && !seen[test.SIG] {
+			seen[test.SIG] = true
+			sigs = append(sigs, test.SIG)
+		}
+	}
+	sort.Strings(sigs)
+	return sigs
+}
+
+// Report renders the trends of the tests of the SIG in Markdown.
+func (t *FlakeTrends) Report(sigName string) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "# sig-%s flake trends, %s to %s\n", sigName, t.Since.Format(time.DateOnly), t.Until.Format(time.DateOnly))
+	for _, trend := range trends {
+		fmt.Fprintf(&text, "\n## %s%s flakes\n\n", strings.ToUpper(string(trend[:1])), trend[1:])
+		count := 0
+		for _, test := range t.Tests {
+			if test.SIG != sigName || test.Trend != trend {
+				continue
+			}
+			count++
+			fmt.Fprintf(&text, "* `%s`: %d of %d runs red (%.1f%%)", test.TestName, test.Failures, test.Runs, percent(test.Failures, test.Runs))
+			if test.PreviousRuns > 0 {
+				fmt.Fprintf(&text, ", %d of %d the period before (%.1f%%)", test.PreviousFailures, test.PreviousRuns,
+					percent(test.PreviousFailures, test.PreviousRuns))
+			}
+			text.WriteString("\n")
+		}
+		if count == 0 {
+			text.WriteString("None.\n")
+		}
+	}
+	return text.String()
+}
+
+// percent returns the failures in percent of the runs.
+func percent(failures, runs int) float64 {
+	if runs == 0 {
+		return 0
+	}
+	return 100 * float64(failures) / float64(runs)
+}
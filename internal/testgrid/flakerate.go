@@ -0,0 +1,83 @@
+package testgrid
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultMinFlakeRuns is the minimum number of runs in the window of a
+// threshold without MinRuns, a single red run out of two is not a trend.
+const defaultMinFlakeRuns = 5
+
+// FlakeRateThreshold is a rolling flake rate over a time window, e.g. more
+// than 30% of the runs of the last 24h.
+type FlakeRateThreshold struct {
+	// Rate is the ratio of red runs between 0 and 1, exceeded when higher.
+	Rate float64 `json:"rate"`
+
+	// Window is the time window of the runs, ending at the latest run.
+	Window metav1.Duration `json:"window"`
+
+	// MinRuns is the minimum number of runs with a result in the window,
+	// defaults to 5.
+	MinRuns int `json:"minRuns,omitempty"`
+}
+
+// ParseFlakeRateThreshold parses a rate and a window separated by a slash,
+// e.g. 30%/24h or 0.3/24h.
+func ParseFlakeRateThreshold(value string) (FlakeRateThreshold, error) {
+	rate, window, found := strings.Cut(value, "/")
+	if !found {
+		return FlakeRateThreshold{}, fmt.Errorf("error parsing flake rate threshold %q, expected <rate>/<window>, e.g. 30%%/24h", value)
+	}
+	var threshold FlakeRateThreshold
+	ratio, err := strconv.ParseFloat(strings.TrimSuffix(rate, "%"), 64)
+	if err != nil {
+		return threshold, fmt.Errorf("error parsing flake rate %q: %v", rate, err)
+	}
+	if strings.HasSuffix(rate, "%") {
+		ratio /= 100
+	}
+	duration, err := time.ParseDuration(window)
+	if err != nil {
+		return threshold, fmt.Errorf("error parsing flake rate window %q: %v", window, err)
+	}
+	threshold = FlakeRateThreshold{Rate: ratio, Window: metav1.Duration{Duration: duration}}
+	return threshold, threshold.Validate()
+}
+
+// Validate checks the rate is a ratio and the window is positive.
+func (f FlakeRateThreshold) Validate() error {
+	if f.Rate <= 0 || f.Rate >= 1 {
+		return fmt.Errorf("error in flake rate threshold %s, the rate must be between 0 and 100%%", f)
+	}
+	if f.Window.Duration <= 0 {
+		return fmt.Errorf("error in flake rate threshold %s, the window must be positive", f)
+	}
+	if f.MinRuns < 0 {
+		return fmt.Errorf("error in flake rate threshold %s, minRuns can't be negative", f)
+	}
+	return nil
+}
+
+// Exceeds returns true when the red runs are over the rate of the runs. A
+// test without any green run is failing, not flaking, and never exceeds it.
+func (f FlakeRateThreshold) Exceeds(failures, runs int) bool {
+	minRuns := f.MinRuns
+	if minRuns == 0 {
+		minRuns = defaultMinFlakeRuns
+	}
+	if runs < minRuns || failures == 0 || failures == runs {
+		return false
+	}
+	return float64(failures)/float64(runs) > f.Rate
+}
+
+// String returns the threshold as a rate over a window, e.g. 30% over 24h0m0s.
+func (f FlakeRateThreshold) String() string {
+	return fmt.Sprintf("%s%% over %s", strconv.FormatFloat(f.Rate*100, 'f', -1, 64), f.Window.Duration)
+}
@@ -0,0 +1,174 @@
+package testgrid
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/monitoring"
+)
+
+// failingStreak is the number of latest red runs making a test consistently
+// failing rather than flaky.
+const failingStreak = 2
+
+// gridURL is the table endpoint of a tab with every test, the passing ones
+// included, unlike tabURL.
+const gridURL = "%s/%s/table?tab=%s&dashboard=%s"
+
+// Grid is the result matrix of the tests of a tab, a cell per test and run,
+// the most recent run first.
+type Grid struct {
+	// Timestamps and Changelists are the start time in milliseconds and the
+	// build of each run column.
+	Timestamps  []int64
+	Changelists []string
+
+	Tests []GridTest
+}
+
+// GridTest is the row of a test in the grid.
+type GridTest struct {
+	Name string
+
+	// Results are the TestGrid status of the test in each run column, e.g.
+	// StatusPass, StatusNoResult when the run has no result for the test.
+	Results []int
+
+	// Messages are the failure messages of each run column, empty for the
+	// runs without failure.
+	Messages []string
+}
+
+// GridFetcher fetches the result matrix of the TestGrid tabs, the clients
+// without it only know the failing tests of a tab.
+type GridFetcher interface {
+	FetchGrid(dashboard, tab string) (*Grid, error)
+}
+
+var _ GridFetcher = &TestGrid{}
+
+// FetchGrid returns the result of every test of the tab of the dashboard in
+// every run of the TestGrid table.
+func (t *TestGrid) FetchGrid(dashboard, tab string) (grid *Grid, err error) {
+	defer func(start time.Time) { monitoring.RecordTestGridRequest(dashboard, "grid", start, err) }(time.Now())
+
+	endpoint := fmt.Sprintf(gridURL, t.URL, url.PathEscape(dashboard), url.QueryEscape(tab), url.QueryEscape(dashboard))
+	response, err := http.Get(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching testgrid table endpoint: %v", err)
+	}
+	defer response.Body.Close() // nolint
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching testgrid table of %s#%s: %s", dashboard, tab, response.Status)
+	}
+
+	var testGroup TestGroup
+	if err = json.NewDecoder(response.Body).Decode(&testGroup); err != nil {
+		return nil, fmt.Errorf("error unmarshaling body response: %v", err)
+	}
+	return testGroup.Grid(), nil
+}
+
+// Grid expands the run-length encoded statuses of the tests of the group
+// into a cell per run. The synthetic rows are kept, see IsSynthetic.
+func (tg *TestGroup) Grid() *Grid {
+	grid := &Grid{Timestamps: tg.Timestamps, Changelists: tg.Changelists}
+	for _, test := range tg.Tests {
+		row := GridTest{Name: test.Name, Results: make([]int, 0, len(tg.Timestamps)), Messages: test.Messages}
+		for _, status := range test.Statuses {
+			for i := 0; i < status.Count && len(row.Results) < len(tg.Timestamps); i++ {
+				row.Results = append(row.Results, status.Value)
+			}
+		}
+		for len(row.Results) < len(tg.Timestamps) {
+			row.Results = append(row.Results, StatusNoResult)
+		}
+		grid.Tests = append(grid.Tests, row)
+	}
+	return grid
+}
+
+// Test returns the row of the test, nil when the grid has no such test.
+func (g *Grid) Test(name string) *GridTest {
+	for i := range g.Tests {
+		if g.Tests[i].Name == name {
+			return &g.Tests[i]
+		}
+	}
+	return nil
+}
+
+// ConsecutiveFailures returns the number of red runs of the current failure
+// streak, skipping the runs FailureWindow skips.
+func (gt *GridTest) ConsecutiveFailures() int {
+	failures := 0
+	for _, result := range gt.Results {
+		switch result {
+		case StatusPass, StatusPassWithErrors, StatusPassWithSkips, StatusFlaky:
+			return failures
+		case StatusCategorizedAbort, StatusTimedOut, StatusCategorizedFail, StatusBuildFail, StatusFail, StatusToolFail:
+			failures++
+		}
+	}
+	return failures
+}
+
+// Classify returns v1alpha1.CONSISTENT_FAILURE for a test red in its latest
+// runs, at least failingStreak of them, with the column of the first red run
+// of the streak, -1 when the streak is older than the grid. The other tests
+// with red or flaky runs are v1alpha1.INTERMITTENT_FLAKE, the tests without
+// any an empty classification. The runs FailureWindow skips are skipped.
+func (gt *GridTest) Classify() (classification string, since int) {
+	streak, since, red := 0, -1, false
+	for column, result := range gt.Results {
+		switch result {
+		case StatusPass, StatusPassWithErrors, StatusPassWithSkips:
+			if streak >= failingStreak {
+				return v1alpha1.CONSISTENT_FAILURE, since
+			}
+			streak = -1
+		case StatusFlaky:
+			if streak >= failingStreak {
+				return v1alpha1.CONSISTENT_FAILURE, since
+			}
+			streak, red = -1, true
+		case StatusCategorizedAbort, StatusTimedOut, StatusCategorizedFail, StatusBuildFail, StatusFail, StatusToolFail:
+			red = true
+			if streak >= 0 {
+				streak, since = streak+1, column
+			}
+		}
+	}
+	if streak >= failingStreak {
+		return v1alpha1.CONSISTENT_FAILURE, -1
+	}
+	if red {
+		return v1alpha1.INTERMITTENT_FLAKE, -1
+	}
+	return "", -1
+}
+
+// Sparkline renders the results of the latest runs, up to width, the oldest
+// on the left: ▁ for a green run, ▄ for a flaky one, █ for a red one and ·
+// for a run without result.
+func (gt *GridTest) Sparkline(width int) string {
+	var line strings.Builder
+	for column := min(width, len(gt.Results)) - 1; column >= 0; column-- {
+		switch gt.Results[column] {
+		case StatusPass, StatusPassWithErrors, StatusPassWithSkips:
+			line.WriteRune('▁')
+		case StatusFlaky:
+			line.WriteRune('▄')
+		case StatusCategorizedAbort, StatusTimedOut, StatusCategorizedFail, StatusBuildFail, StatusFail, StatusToolFail:
+			line.WriteRune('█')
+		default:
+			line.WriteRune('·')
+		}
+	}
+	return line.String()
+}
@@ -2,14 +2,20 @@ package testgrid
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"regexp"
 	"strings"
 	"time"
 
 	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/fingerprint"
+	"sigs.k8s.io/signalhound/internal/monitoring"
 	"sigs.k8s.io/signalhound/internal/prow"
+	"sigs.k8s.io/signalhound/internal/sig"
 )
 
 var (
@@ -17,8 +23,13 @@ var (
 	e2eSuitePrefix = `Kubernetes e2e suite.`
 	kubetestPrefix = `kubetest`
 	testRegex      = e2eSuitePrefix + `\[It\] \[(\w.*)\] (?<TEST>\w.*)`
+	syntheticRegex = regexp.MustCompile(`(^|\.)(Overall|Serial)$`)
 )
 
+// maxRunURLs limits the red and green run URLs kept per test, the runs whose
+// infrastructure is compared.
+const maxRunURLs = 5
+
 const tabURL = "%s/%s/table?tab=%s&exclude-non-failed-tests=&dashboard=%s"
 
 // TestGroup serializes the content from testgrid tab endpoint
@@ -54,6 +65,180 @@ type Statuses struct {
 	Value int `json:"value"`
 }
 
+// TestGrid cell result values, as defined by the TestGrid TestStatus proto.
+const (
+	StatusNoResult         = 0
+	StatusPass             = 1
+	StatusPassWithErrors   = 2
+	StatusPassWithSkips    = 3
+	StatusCategorizedAbort = 5
+	StatusTimedOut         = 9
+	StatusCategorizedFail  = 10
+	StatusBuildFail        = 11
+	StatusFail             = 12
+	StatusFlaky            = 13
+	StatusToolFail         = 14
+)
+
+// FailureWindow returns the timestamps of the last green run before the
+// current failure streak and of the first red run of the streak. Runs still
+// in progress, aborted or without result are skipped, a flaky run counts as
+// green. Zero values are returned when the latest run is green, and a zero
+// lastPass when the streak is longer than the grid.
+func (te *Test) FailureWindow(timestamps []int64) (lastPass, firstFail int64) {
+	lastPassColumn, firstFailColumn := te.FailureWindowColumns(len(timestamps))
+	if lastPassColumn >= 0 {
+		lastPass = timestamps[lastPassColumn]
+	}
+	if firstFailColumn >= 0 {
+		firstFail = timestamps[firstFailColumn]
+	}
+	return lastPass, firstFail
+}
+
+// FailureWindowColumns returns the columns of the runs of FailureWindow, -1
+// for the runs not found.
+func (te *Test) FailureWindowColumns(columns int) (lastPass, firstFail int) {
+	column, lastPass, firstFail := 0, -1, -1
+	for _, status := range te.Statuses {
+		for i := 0; i < status.Count && column < columns; i++ {
+			switch status.Value {
+			case StatusPass, StatusPassWithErrors, StatusPassWithSkips, StatusFlaky:
+				if firstFail < 0 {
+					return -1, -1
+				}
+				return column, firstFail
+			case StatusCategorizedAbort, StatusTimedOut, StatusCategorizedFail, StatusBuildFail, StatusFail, StatusToolFail:
+				firstFail = column
+			}
+			column++
+		}
+	}
+	return -1, firstFail
+}
+
+// RecoveryWindow returns the timestamps of the first red run of the latest
+// failure streak and of the first green run after it, skipping the runs
+// FailureWindow skips. Zero values are returned while the latest run is red
+// and when the grid has no failure.
+func (te *Test) RecoveryWindow(timestamps []int64) (firstFail, recovered int64) {
+	column := 0
+	for _, status := range te.Statuses {
+		for i := 0; i < status.Count && column < len(timestamps); i++ {
+			switch status.Value {
+			case StatusPass, StatusPassWithErrors, StatusPassWithSkips, StatusFlaky:
+				if firstFail != 0 {
+					return firstFail, recovered
+				}
+				recovered = timestamps[column]
+			case StatusCategorizedAbort, StatusTimedOut, StatusCategorizedFail, StatusBuildFail, StatusFail, StatusToolFail:
+				if recovered == 0 {
+					return 0, 0
+				}
+				firstFail = timestamps[column]
+			}
+			column++
+		}
+	}
+	if firstFail == 0 {
+		return 0, 0
+	}
+	return firstFail, recovered
+}
+
+// ConsecutiveFailures returns the number of red runs of the current failure
+// streak, skipping the runs FailureWindow skips.
+func (te *Test) ConsecutiveFailures() int {
+	failures := 0
+	for _, status := range te.Statuses {
+		switch status.Value {
+		case StatusPass, StatusPassWithErrors, StatusPassWithSkips, StatusFlaky:
+			return failures
+		case StatusCategorizedAbort, StatusTimedOut, StatusCategorizedFail, StatusBuildFail, StatusFail, StatusToolFail:
+			failures += status.Count
+		}
+	}
+	return failures
+}
+
+// MissingRuns returns the number of the latest runs without a result for
+// the test, e.g. after it was skipped or removed from the job.
+func (te *Test) MissingRuns() int {
+	missing := 0
+	for _, status := range te.Statuses {
+		if status.Value != StatusNoResult {
+			break
+		}
+		missing += status.Count
+	}
+	return missing
+}
+
+// FlakeRate returns the number of red runs and of runs with a result since
+// the timestamp in milliseconds, skipping the runs FailureWindow skips. A
+// flaky run counts as red, it is the flake the rate measures.
+func (te *Test) FlakeRate(timestamps []int64, since int64) (failures, runs int) {
+	column := 0
+	for _, status := range te.Statuses {
+		for i := 0; i < status.Count && column < len(timestamps); i++ {
+			if timestamps[column] < since {
+				return failures, runs
+			}
+			switch status.Value {
+			case StatusPass, StatusPassWithErrors, StatusPassWithSkips:
+				runs++
+			case StatusFlaky, StatusCategorizedAbort, StatusTimedOut, StatusCategorizedFail, StatusBuildFail, StatusFail, StatusToolFail:
+				failures++
+				runs++
+			}
+			column++
+		}
+	}
+	return failures, runs
+}
+
+// RecentRuns returns the columns of the latest red and green runs of the
+// test, up to limit of each and the most recent first, skipping the runs
+// FailureWindow skips. A flaky run counts as red.
+func (te *Test) RecentRuns(columns, limit int) (failed, passed []int) {
+	column := 0
+	for _, status := range te.Statuses {
+		for i := 0; i < status.Count && column < columns; i++ {
+			switch status.Value {
+			case StatusPass, StatusPassWithErrors, StatusPassWithSkips:
+				if len(passed) < limit {
+					passed = append(passed, column)
+				}
+			case StatusFlaky, StatusCategorizedAbort, StatusTimedOut, StatusCategorizedFail, StatusBuildFail, StatusFail, StatusToolFail:
+				if len(failed) < limit {
+					failed = append(failed, column)
+				}
+			}
+			column++
+		}
+	}
+	return failed, passed
+}
+
+// Runs returns the result of the test in every run of the grid, the most
+// recent first, skipping the runs FailureWindow skips. A flaky run counts as
+// red.
+func (te *Test) Runs(timestamps []int64) (runs []v1alpha1.RunResult) {
+	column := 0
+	for _, status := range te.Statuses {
+		for i := 0; i < status.Count && column < len(timestamps); i++ {
+			switch status.Value {
+			case StatusPass, StatusPassWithErrors, StatusPassWithSkips:
+				runs = append(runs, v1alpha1.RunResult{Timestamp: timestamps[column]})
+			case StatusFlaky, StatusCategorizedAbort, StatusTimedOut, StatusCategorizedFail, StatusBuildFail, StatusFail, StatusToolFail:
+				runs = append(runs, v1alpha1.RunResult{Timestamp: timestamps[column], Failed: true})
+			}
+			column++
+		}
+	}
+	return runs
+}
+
 // RenderStatuses renders the statuses of a test into a string.
 func (te *Test) RenderStatuses(timestamps []int64) (string, int, int) {
 	var firstFailureIndex = -1
@@ -77,8 +262,30 @@ func (te *Test) RenderStatuses(timestamps []int64) (string, int, int) {
 	return output.String(), failureCount, firstFailureIndex
 }
 
+// TestGridInterface fetches the dashboards of a TestGrid instance, see
+// TestGrid, faked in the unit tests.
+type TestGridInterface interface {
+	FetchTabSummary(dashboard string, filterStatus []string) ([]v1alpha1.DashboardSummary, error)
+	FetchTestGroup(summary *v1alpha1.DashboardSummary) (*TestGroup, error)
+	FetchTabTests(summary *v1alpha1.DashboardSummary, minFailure, minFlake int) (*v1alpha1.DashboardTab, error)
+}
+
+var _ TestGridInterface = &TestGrid{}
+
 type TestGrid struct {
 	URL string
+
+	// IncludeSynthetic keeps the synthetic rows in the tab tests, see
+	// IsSynthetic.
+	IncludeSynthetic bool
+}
+
+// IsSynthetic returns true for the rows TestGrid adds to the tests of a job:
+// the Overall row of the run, e.g. ci-kubernetes-e2e.Overall, and the
+// aggregate rows of a suite, e.g. Kubernetes e2e suite.Serial. They fail
+// with any test and are not tests an issue can be filed for.
+func IsSynthetic(testName string) bool {
+	return syntheticRegex.MatchString(testName)
 }
 
 func NewTestGrid(url string) *TestGrid {
@@ -89,6 +296,8 @@ type DashboardMapper map[string]*v1alpha1.DashboardSummary
 
 // FetchTabSummary retrieves the summary data for a given dashboard from the TestGrid
 func (t *TestGrid) FetchTabSummary(dashboard string, filterStatus []string) (summary []v1alpha1.DashboardSummary, err error) {
+	defer func(start time.Time) { monitoring.RecordTestGridRequest(dashboard, "summary", start, err) }(time.Now())
+
 	var response *http.Response
 	url := fmt.Sprintf("%s/%s/summary", t.URL, cleanHTMLCharacters(dashboard))
 
@@ -130,21 +339,82 @@ func filterDashboards(dashboardList DashboardMapper, url string, filterStatus []
 	return summary
 }
 
-// FetchTabTests returns the test group related to the tab of a dashboard
-func (t *TestGrid) FetchTabTests(summary *v1alpha1.DashboardSummary, minFailure, minFlake int) (tab *v1alpha1.DashboardTab, err error) {
-	var response *http.Response
-	if response, err = http.Get(summary.DashboardTab.TabURL); err != nil {
-		return tab, err
+// ErrNotDashboardGroup is returned by FetchDashboardGroup for a name that is
+// not a dashboard group, e.g. a dashboard.
+var ErrNotDashboardGroup = errors.New("not a dashboard group")
+
+// DashboardGroupFetcher lists the dashboards of the TestGrid dashboard
+// groups, the clients without it only accept dashboard names.
+type DashboardGroupFetcher interface {
+	FetchDashboardGroup(group string) ([]string, error)
+}
+
+var _ DashboardGroupFetcher = &TestGrid{}
+
+// FetchDashboardGroup returns the dashboards of a dashboard group from the
+// TestGrid API, e.g. sig-release-master-blocking for sig-release, or
+// ErrNotDashboardGroup when TestGrid has no such group.
+func (t *TestGrid) FetchDashboardGroup(group string) (dashboards []string, err error) {
+	defer func(start time.Time) { monitoring.RecordTestGridRequest(group, "dashboard-group", start, err) }(time.Now())
+
+	response, err := http.Get(fmt.Sprintf("%s/api/v1/dashboard-groups/%s", t.URL, url.PathEscape(group)))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching testgrid dashboard group endpoint: %v", err)
+	}
+	defer response.Body.Close() // nolint
+	if response.StatusCode == http.StatusNotFound {
+		return nil, ErrNotDashboardGroup
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching testgrid dashboard group %s: %s", group, response.Status)
 	}
 
-	var data []byte
-	if data, err = io.ReadAll(response.Body); err != nil {
-		return tab, err
+	var data struct {
+		Dashboards []struct {
+			Name string `json:"name"`
+		} `json:"dashboards"`
+	}
+	if err = json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("error unmarshaling body response: %v", err)
+	}
+	if len(data.Dashboards) == 0 {
+		return nil, ErrNotDashboardGroup
+	}
+	for _, dashboard := range data.Dashboards {
+		dashboards = append(dashboards, dashboard.Name)
+	}
+	return dashboards, nil
+}
+
+// FetchTestGroup returns the raw test group table of the tab of a dashboard.
+func (t *TestGrid) FetchTestGroup(summary *v1alpha1.DashboardSummary) (testGroup *TestGroup, err error) {
+	defer func(start time.Time) {
+		monitoring.RecordTestGridRequest(summary.DashboardName, "table", start, err)
+	}(time.Now())
+
+	response, err := http.Get(summary.DashboardTab.TabURL)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close() // nolint
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
 	}
 
-	// unmarshal test group and be converted into the internal dashboard format
-	var testGroup = &TestGroup{}
+	testGroup = &TestGroup{}
 	if err = json.Unmarshal(data, testGroup); err != nil {
+		return nil, err
+	}
+	return testGroup, nil
+}
+
+// FetchTabTests returns the test group related to the tab of a dashboard
+func (t *TestGrid) FetchTabTests(summary *v1alpha1.DashboardSummary, minFailure, minFlake int) (tab *v1alpha1.DashboardTab, err error) {
+	// fetch the test group to be converted into the internal dashboard format
+	testGroup, err := t.FetchTestGroup(summary)
+	if err != nil {
 		return tab, err
 	}
 
@@ -156,16 +426,60 @@ func (t *TestGrid) FetchTabTests(summary *v1alpha1.DashboardSummary, minFailure,
 
 	summary.DashboardTab.BoardHash = aggregation
 	summary.DashboardTab.TabURL = cleanHTMLCharacters(fmt.Sprintf("https://testgrid.k8s.io/%s&exclude-non-failed-tests=", aggregation))
-	summary.DashboardTab.TestRuns = filterTabTests(testGroup, summary.OverallState, minFailure, minFlake)
+	summary.DashboardTab.TestRuns = filterTabTests(testGroup, summary.OverallState, minFailure, minFlake, t.IncludeSynthetic)
 	summary.DashboardTab.TabState = summary.OverallState
 	summary.DashboardTab.StateIcon = icon
+	summary.DashboardTab.LatestBuildURL = testGroup.LatestBuildURL()
 
 	return summary.DashboardTab, nil
 }
 
-func filterTabTests(testGroup *TestGroup, state string, minFailure, minFlake int) (tests []v1alpha1.TestResult) {
+// LatestBuildURL returns the Prow view URL of the most recent run of the
+// test group, or an empty string when the grid has no run.
+func (tg *TestGroup) LatestBuildURL() string {
+	if len(tg.Changelists) == 0 || tg.Query == "" {
+		return ""
+	}
+	return cleanHTMLCharacters(fmt.Sprintf("%s/view/gs/%s/%s", prow.URL, tg.Query, tg.Changelists[0]))
+}
+
+// commit returns the commit of the run in the column from the Commit custom
+// column of the tab, or an empty string when the tab has no such column.
+func (tg *TestGroup) commit(column int) string {
+	for i, header := range tg.ColumnHeaderNames {
+		if strings.EqualFold(header, "commit") && column >= 0 && column < len(tg.CustomColumns) && i < len(tg.CustomColumns[column]) {
+			return tg.CustomColumns[column][i]
+		}
+	}
+	return ""
+}
+
+// runURLs returns the Prow view URLs of the runs in the columns.
+func (tg *TestGroup) runURLs(columns []int) (urls []string) {
+	for _, column := range columns {
+		if url := tg.runURL(column); url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// runURL returns the Prow view URL of the run in the column, or an empty
+// string when the column has no run.
+func (tg *TestGroup) runURL(column int) string {
+	if tg.Query == "" || column < 0 || column >= len(tg.Changelists) {
+		return ""
+	}
+	return cleanHTMLCharacters(fmt.Sprintf("%s/view/gs/%s/%s", prow.URL, tg.Query, tg.Changelists[column]))
+}
+
+func filterTabTests(testGroup *TestGroup, state string, minFailure, minFlake int, includeSynthetic bool) (tests []v1alpha1.TestResult) {
 	jobName := strings.Split(testGroup.Query, "/")
-	for _, test := range testGroup.Tests {
+	grid := testGroup.Grid()
+	for i, test := range testGroup.Tests {
+		if !includeSynthetic && IsSynthetic(test.Name) {
+			continue
+		}
 		errMessage, failures, firstFailure := test.RenderStatuses(testGroup.Timestamps)
 		if ((failures >= minFailure || minFailure == 0) && state == v1alpha1.FAILING_STATUS) ||
 			((failures >= minFlake || minFlake == 0) && state == v1alpha1.FLAKY_STATUS) {
@@ -181,6 +495,19 @@ func filterTabTests(testGroup *TestGroup, state string, minFailure, minFlake int
 			if firstFailure >= 0 && firstFailure < len(testGroup.Changelists) {
 				prowJobURL = cleanHTMLCharacters(fmt.Sprintf("https://prow.k8s.io/view/gs/%s/%s", testGroup.Query, testGroup.Changelists[firstFailure]))
 			}
+			lastPass, firstFail := test.FailureWindow(testGroup.Timestamps)
+			lastPassRun, firstFailRun := test.FailureWindowColumns(len(testGroup.Timestamps))
+			recoveredFrom, recovered := test.RecoveryWindow(testGroup.Timestamps)
+			var lastFailure int64
+			if firstFailure >= 0 && firstFailure < len(testGroup.Timestamps) {
+				lastFailure = testGroup.Timestamps[firstFailure]
+			}
+			failedRuns, passedRuns := test.RecentRuns(len(testGroup.Timestamps), maxRunURLs)
+			classification, failingSince := grid.Tests[i].Classify()
+			var message string
+			if firstFailure >= 0 && firstFailure < len(test.Messages) {
+				message = test.Messages[firstFailure]
+			}
 			tests = append(tests, v1alpha1.TestResult{
 				TestName:        test.Name,
 				LatestTimestamp: testGroup.Timestamps[0],
@@ -188,13 +515,42 @@ func filterTabTests(testGroup *TestGroup, state string, minFailure, minFlake int
 				ProwJobURL:      prowJobURL,
 				TriageURL:       cleanHTMLCharacters(fmt.Sprintf("https://storage.googleapis.com/k8s-triage/index.html?job=%s$&test=%s", cleanHTMLCharacters(jobName[len(jobName)-1]), cleanHTMLCharacters(testName))),
 				ErrorMessage:    errMessage,
+
+				LastPassTimestamp:    lastPass,
+				FirstFailTimestamp:   firstFail,
+				LastFailureTimestamp: lastFailure,
+				FailureCount:         failures,
+				RunCount:             len(testGroup.Timestamps),
+				ConsecutiveFailures:  test.ConsecutiveFailures(),
+				Classification:       classification,
+				FailingSince:         testGroup.commit(failingSince),
+				Fingerprint:          fingerprint.New(test.Name, message),
+				MissingRuns:          test.MissingRuns(),
+
+				RecoveredTimestamp:     recovered,
+				RecoveredFromTimestamp: recoveredFrom,
+				Sig:                    sig.FromTestName(test.Name),
+				FailedRunURLs:          testGroup.runURLs(failedRuns),
+				PassedRunURLs:          testGroup.runURLs(passedRuns),
+				LastPassRunURL:         testGroup.runURL(lastPassRun),
+				FirstFailRunURL:        testGroup.runURL(firstFailRun),
+				LastPassCommit:         prow.CommitSHA(testGroup.commit(lastPassRun)),
+				FirstFailCommit:        prow.CommitSHA(testGroup.commit(firstFailRun)),
+				Runs:                   test.Runs(testGroup.Timestamps),
+				FlakeScore:             100 * failures / len(testGroup.Timestamps),
+				ScoreRuns:              len(testGroup.Timestamps),
 			})
 		}
 	}
 	return tests
 }
 
+// hasStatus returns true when the board status is in statuses, a nil list
+// matches every status.
 func hasStatus(boardStatus string, statuses []string) bool {
+	if statuses == nil {
+		return true
+	}
 	for _, status := range statuses {
 		if boardStatus == status {
 			return true
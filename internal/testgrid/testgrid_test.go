@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"sigs.k8s.io/signalhound/api/v1alpha1"
@@ -91,6 +92,7 @@ func Test_FetchTable(t *testing.T) {
 				Changelists:   []string{"1972011571991285760"},
 				Tests: []Test{
 					{Name: "ci-kubernetes-build.Overall", ShortTexts: []string{"F"}, Messages: []string{"F"}},
+					{Name: "ci-kubernetes-build.Build", ShortTexts: []string{"F"}, Messages: []string{"F"}},
 				},
 			},
 		},
@@ -101,30 +103,52 @@ func Test_FetchTable(t *testing.T) {
 			server := startServer(tt.response)
 			defer server.Close()
 
-			summary := &v1alpha1.DashboardSummary{
-				OverallState:  v1alpha1.FLAKY_STATUS,
-				DashboardName: dashboard,
-				DashboardTab: &v1alpha1.DashboardTab{
-					TabName: "cikubernetesbuild",
-					TabURL:  server.URL,
-				},
+			summary := func() *v1alpha1.DashboardSummary {
+				return &v1alpha1.DashboardSummary{
+					OverallState:  v1alpha1.FLAKY_STATUS,
+					DashboardName: dashboard,
+					DashboardTab: &v1alpha1.DashboardTab{
+						TabName: "cikubernetesbuild",
+						TabURL:  server.URL,
+					},
+				}
 			}
 
 			tg := NewTestGrid(server.URL)
-			tabTest, err := tg.FetchTabTests(summary, 1, 1)
+			tabTest, err := tg.FetchTabTests(summary(), 1, 1)
 			assert.NoError(t, err)
 
 			assert.NotEmpty(t, tabTest.StateIcon)
 			assert.Equal(t, v1alpha1.FLAKY_STATUS, tabTest.TabState)
 			assert.Len(t, tabTest.TestRuns, 1)
 			for _, test := range tabTest.TestRuns {
-				assert.Contains(t, test.TestName, "Overall")
+				assert.Equal(t, "ci-kubernetes-build.Build", test.TestName)
 				assert.Contains(t, test.ErrorMessage, "F")
 			}
+
+			// the synthetic rows are only kept on demand
+			tg.IncludeSynthetic = true
+			tabTest, err = tg.FetchTabTests(summary(), 1, 1)
+			assert.NoError(t, err)
+			assert.Len(t, tabTest.TestRuns, 2)
+			assert.Contains(t, tabTest.TestRuns[0].TestName, "Overall")
 		})
 	}
 }
 
+func TestIsSynthetic(t *testing.T) {
+	for name, synthetic := range map[string]bool{
+		"Overall":                     true,
+		"ci-kubernetes-e2e.Overall":   true,
+		"Kubernetes e2e suite.Serial": true,
+		"Kubernetes e2e suite.[It] [sig-node] Pods should run [Serial]": false,
+		"kubetest.Up":                      false,
+		"ci-kubernetes-e2e.OverallTimeout": false,
+	} {
+		assert.Equal(t, synthetic, IsSynthetic(name), name)
+	}
+}
+
 func TestRenderStatuses(t *testing.T) {
 	message := "kubetest --timeout triggered"
 	tests := []struct {
@@ -169,6 +193,31 @@ func TestRenderStatuses(t *testing.T) {
 	}
 }
 
+func Test_FetchDashboardGroup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/dashboard-groups/sig-release":
+			w.Write([]byte(`{"dashboards":[{"name":"sig-release-master-blocking","link":"/sig-release-master-blocking"},` + // nolint
+				`{"name":"sig-release-master-informing","link":"/sig-release-master-informing"}]}`))
+		case "/api/v1/dashboard-groups/sig-release-broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tg := NewTestGrid(server.URL)
+	dashboards, err := tg.FetchDashboardGroup("sig-release")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sig-release-master-blocking", "sig-release-master-informing"}, dashboards)
+
+	_, err = tg.FetchDashboardGroup("sig-release-master-blocking")
+	assert.ErrorIs(t, err, ErrNotDashboardGroup)
+	_, err = tg.FetchDashboardGroup("sig-release-broken")
+	assert.ErrorContains(t, err, "500 Internal Server Error")
+}
+
 func startServer(response interface{}) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -176,3 +225,254 @@ func startServer(response interface{}) *httptest.Server {
 		w.Write(jsonData) // nolint
 	}))
 }
+
+func TestFailureWindow(t *testing.T) {
+	timestamps := []int64{5000, 4000, 3000, 2000, 1000}
+	tests := []struct {
+		name      string
+		statuses  []Statuses
+		lastPass  int64
+		firstFail int64
+		// columns of the runs
+		lastPassRun  int
+		firstFailRun int
+	}{
+		{
+			name:         "failing since the third run",
+			statuses:     []Statuses{{Count: 1, Value: StatusFail}, {Count: 1, Value: StatusNoResult}, {Count: 1, Value: StatusFail}, {Count: 2, Value: StatusPass}},
+			lastPass:     2000,
+			firstFail:    3000,
+			lastPassRun:  3,
+			firstFailRun: 2,
+		},
+		{
+			name:         "latest run is green",
+			statuses:     []Statuses{{Count: 1, Value: StatusPass}, {Count: 4, Value: StatusFail}},
+			lastPassRun:  -1,
+			firstFailRun: -1,
+		},
+		{
+			name:         "flaky run counts as green",
+			statuses:     []Statuses{{Count: 2, Value: StatusFail}, {Count: 3, Value: StatusFlaky}},
+			lastPass:     3000,
+			firstFail:    4000,
+			lastPassRun:  2,
+			firstFailRun: 1,
+		},
+		{
+			name:         "streak longer than the grid",
+			statuses:     []Statuses{{Count: 5, Value: StatusBuildFail}},
+			firstFail:    1000,
+			lastPassRun:  -1,
+			firstFailRun: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := Test{Statuses: tt.statuses}
+			lastPass, firstFail := test.FailureWindow(timestamps)
+			assert.Equal(t, tt.lastPass, lastPass)
+			assert.Equal(t, tt.firstFail, firstFail)
+			lastPassRun, firstFailRun := test.FailureWindowColumns(len(timestamps))
+			assert.Equal(t, tt.lastPassRun, lastPassRun)
+			assert.Equal(t, tt.firstFailRun, firstFailRun)
+		})
+	}
+}
+
+func TestRecoveryWindow(t *testing.T) {
+	timestamps := []int64{5000, 4000, 3000, 2000, 1000}
+	tests := []struct {
+		name          string
+		statuses      []Statuses
+		recoveredFrom int64
+		recovered     int64
+	}{
+		{
+			name:     "still failing",
+			statuses: []Statuses{{Count: 1, Value: StatusFail}, {Count: 4, Value: StatusPass}},
+		},
+		{
+			name:          "green since the third run",
+			statuses:      []Statuses{{Count: 2, Value: StatusPass}, {Count: 1, Value: StatusNoResult}, {Count: 1, Value: StatusPass}, {Count: 1, Value: StatusFail}},
+			recoveredFrom: 1000,
+			recovered:     2000,
+		},
+		{
+			name:          "only the latest streak",
+			statuses:      []Statuses{{Count: 1, Value: StatusFlaky}, {Count: 2, Value: StatusTimedOut}, {Count: 1, Value: StatusPass}, {Count: 1, Value: StatusFail}},
+			recoveredFrom: 3000,
+			recovered:     5000,
+		},
+		{
+			name:     "never failed",
+			statuses: []Statuses{{Count: 5, Value: StatusPass}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := Test{Statuses: tt.statuses}
+			recoveredFrom, recovered := test.RecoveryWindow(timestamps)
+			assert.Equal(t, tt.recoveredFrom, recoveredFrom)
+			assert.Equal(t, tt.recovered, recovered)
+		})
+	}
+}
+
+func TestConsecutiveFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []Statuses
+		want     int
+	}{
+		{name: "green", statuses: []Statuses{{Count: 1, Value: StatusPass}, {Count: 4, Value: StatusFail}}},
+		{name: "skips runs without result", statuses: []Statuses{{Count: 2, Value: StatusFail}, {Count: 1, Value: StatusNoResult}, {Count: 1, Value: StatusTimedOut}, {Count: 1, Value: StatusFlaky}}, want: 3},
+		{name: "whole grid", statuses: []Statuses{{Count: 5, Value: StatusBuildFail}}, want: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := Test{Statuses: tt.statuses}
+			assert.Equal(t, tt.want, test.ConsecutiveFailures())
+		})
+	}
+}
+
+func TestMissingRuns(t *testing.T) {
+	test := Test{Statuses: []Statuses{{Count: 3, Value: StatusNoResult}, {Count: 2, Value: StatusFail}, {Count: 1, Value: StatusNoResult}}}
+	assert.Equal(t, 3, test.MissingRuns())
+	test = Test{Statuses: []Statuses{{Count: 2, Value: StatusFail}, {Count: 1, Value: StatusNoResult}}}
+	assert.Equal(t, 0, test.MissingRuns())
+}
+
+func TestFlakeRate(t *testing.T) {
+	timestamps := []int64{5000, 4000, 3000, 2000, 1000}
+	test := Test{Statuses: []Statuses{{Count: 1, Value: StatusPass}, {Count: 1, Value: StatusFlaky}, {Count: 1, Value: StatusNoResult}, {Count: 2, Value: StatusFail}}}
+
+	failures, runs := test.FlakeRate(timestamps, 0)
+	assert.Equal(t, 3, failures)
+	assert.Equal(t, 4, runs)
+
+	failures, runs = test.FlakeRate(timestamps, 3000)
+	assert.Equal(t, 1, failures)
+	assert.Equal(t, 2, runs)
+}
+
+func TestRecentRuns(t *testing.T) {
+	test := Test{Statuses: []Statuses{{Count: 2, Value: StatusFail}, {Count: 1, Value: StatusNoResult}, {Count: 1, Value: StatusPass}, {Count: 1, Value: StatusFlaky}, {Count: 3, Value: StatusPass}}}
+	failed, passed := test.RecentRuns(8, 2)
+	assert.Equal(t, []int{0, 1}, failed)
+	assert.Equal(t, []int{3, 5}, passed)
+
+	// the statuses past the grid columns are ignored
+	failed, passed = test.RecentRuns(3, 5)
+	assert.Equal(t, []int{0, 1}, failed)
+	assert.Nil(t, passed)
+
+	assert.Equal(t, []v1alpha1.RunResult{{Timestamp: 8, Failed: true}, {Timestamp: 7, Failed: true}, {Timestamp: 5}},
+		test.Runs([]int64{8, 7, 6, 5}))
+
+	group := TestGroup{Query: "kubernetes-ci-logs/logs/ci-kubernetes-e2e-gci-gce", Changelists: []string{"3", "2", "1"}}
+	assert.Equal(t, []string{"https://prow.k8s.io/view/gs/kubernetes-ci-logs/logs/ci-kubernetes-e2e-gci-gce/1"}, group.runURLs([]int{2, 3}))
+	assert.Empty(t, group.runURL(-1))
+}
+
+func TestFlakeRateThreshold(t *testing.T) {
+	threshold, err := ParseFlakeRateThreshold("30%/24h")
+	assert.NoError(t, err)
+	assert.Equal(t, 0.3, threshold.Rate)
+	assert.Equal(t, 24*time.Hour, threshold.Window.Duration)
+	assert.Equal(t, "30% over 24h0m0s", threshold.String())
+
+	threshold, err = ParseFlakeRateThreshold("0.5/6h")
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, threshold.Rate)
+
+	for _, value := range []string{"30%", "130%/24h", "30%/forever", "0.3/-1h"} {
+		_, err = ParseFlakeRateThreshold(value)
+		assert.Error(t, err, value)
+	}
+
+	assert.True(t, threshold.Exceeds(4, 6))
+	assert.False(t, threshold.Exceeds(3, 6), "the rate must be exceeded")
+	assert.False(t, threshold.Exceeds(2, 3), "too few runs")
+	assert.False(t, threshold.Exceeds(6, 6), "failing, not flaking")
+	threshold.MinRuns = 2
+	assert.True(t, threshold.Exceeds(2, 3))
+}
+
+func Test_FetchGrid(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/"+dashboard+"/table" || r.URL.Query().Get("tab") != tabName {
+			http.NotFound(w, r)
+			return
+		}
+		// exclude-non-failed-tests would leave out the passing tests.
+		assert.False(t, r.URL.Query().Has("exclude-non-failed-tests"))
+		w.Write([]byte(`{"timestamps":[4000,3000,2000,1000],"changelists":["104","103","102","101"],"tests":[` + // nolint
+			`{"name":"[sig-node] Pods","statuses":[{"count":2,"value":12},{"count":1,"value":13},{"count":1,"value":1}]},` +
+			`{"name":"[sig-apps] Deployment","statuses":[{"count":1,"value":1},{"count":1,"value":0}]}]}`))
+	}))
+	defer server.Close()
+
+	tg := NewTestGrid(server.URL)
+	grid, err := tg.FetchGrid(dashboard, tabName)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{4000, 3000, 2000, 1000}, grid.Timestamps)
+	assert.Equal(t, []GridTest{
+		{Name: "[sig-node] Pods", Results: []int{StatusFail, StatusFail, StatusFlaky, StatusPass}},
+		{Name: "[sig-apps] Deployment", Results: []int{StatusPass, StatusNoResult, StatusNoResult, StatusNoResult}},
+	}, grid.Tests)
+
+	_, err = tg.FetchGrid(dashboard, "missing")
+	assert.ErrorContains(t, err, "404 Not Found")
+}
+
+func TestGridTest(t *testing.T) {
+	test := GridTest{Results: []int{StatusFail, StatusNoResult, StatusTimedOut, StatusFlaky, StatusPass, StatusFail}}
+	assert.Equal(t, 2, test.ConsecutiveFailures())
+	assert.Equal(t, "█▁▄█·█", test.Sparkline(10))
+	assert.Equal(t, "█·█", test.Sparkline(3))
+
+	grid := &Grid{Tests: []GridTest{{Name: "[sig-node] Pods"}}}
+	assert.Equal(t, &grid.Tests[0], grid.Test("[sig-node] Pods"))
+	assert.Nil(t, grid.Test("[sig-apps] Deployment"))
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name           string
+		results        []int
+		classification string
+		since          int
+	}{
+		{"passing", []int{StatusPass, StatusNoResult, StatusPass}, "", -1},
+		{"failing since a commit", []int{StatusFail, StatusNoResult, StatusFail, StatusPass, StatusFail}, v1alpha1.CONSISTENT_FAILURE, 2},
+		{"failing over the grid", []int{StatusFail, StatusTimedOut}, v1alpha1.CONSISTENT_FAILURE, -1},
+		{"single red run", []int{StatusFail, StatusPass, StatusPass}, v1alpha1.INTERMITTENT_FLAKE, -1},
+		{"red runs between green ones", []int{StatusPass, StatusFail, StatusFail, StatusPass}, v1alpha1.INTERMITTENT_FLAKE, -1},
+		{"flaky run", []int{StatusFlaky, StatusFail, StatusFail}, v1alpha1.INTERMITTENT_FLAKE, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			classification, since := (&GridTest{Results: tt.results}).Classify()
+			assert.Equal(t, tt.classification, classification)
+			assert.Equal(t, tt.since, since)
+		})
+	}
+
+	testGroup := &TestGroup{
+		Timestamps:        []int64{3000, 2000, 1000},
+		ColumnHeaderNames: []string{"infra-commit", "Commit"},
+		CustomColumns:     [][]string{{"a1", "c3"}, {"a1", "v1.35.0-alpha.3.412+a1b2c3d4e5f607"}, {"a1", "6f3c1b8e2a9d04"}},
+		Tests:             []Test{{Name: "[sig-node] Pods", Statuses: []Statuses{{Count: 2, Value: StatusFail}, {Count: 1, Value: StatusPass}}}},
+	}
+	results := filterTabTests(testGroup, v1alpha1.FLAKY_STATUS, 0, 0, false)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, v1alpha1.CONSISTENT_FAILURE, results[0].Classification)
+		assert.Equal(t, "v1.35.0-alpha.3.412+a1b2c3d4e5f607", results[0].FailingSince)
+		assert.Equal(t, "6f3c1b8e2a9d04", results[0].LastPassCommit)
+		assert.Equal(t, "a1b2c3d4e5f607", results[0].FirstFailCommit)
+	}
+}
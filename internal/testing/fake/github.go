@@ -0,0 +1,180 @@
+package fake
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/signalhound/internal/github"
+)
+
+// Draft is a draft issue created in the project board.
+type Draft struct {
+	Title string
+	Body  string
+	Board string
+}
+
+// Issue is an issue filed in a repository, numbered from 1.
+type Issue struct {
+	github.Issue
+	Body    string
+	Board   string
+	Options *github.IssueOptions
+}
+
+// Comment is a comment posted on an issue, Action is comment, reopen or
+// close.
+type Comment struct {
+	Issue   *github.Issue
+	Comment string
+	Action  string
+}
+
+// ProjectManager is a GitHub client returning the scripted fields, pull
+// requests and issues, or Err when set, and recording the drafts, issues
+// and comments.
+type ProjectManager struct {
+	Fields       []github.ProjectFieldInfo
+	Pulls        []github.PullRequest
+	BoardChanges []github.BoardChange
+
+	// Existing, Open and Closed are the issues of each test name, the closed
+	// issue is found when closed after closedSince.
+	Existing map[string][]github.Issue
+	Open     map[string]*github.Issue
+	Closed   map[string]*github.Issue
+
+	// OpenCounts are the numbers of open issues of each milestone.
+	OpenCounts map[string]int
+
+	// BoardIssues are the draft and open issues of the project board.
+	BoardIssues []github.BoardIssue
+
+	// Limit is the rate limit left to the token, unlimited when nil.
+	Limit *github.RateLimit
+
+	Err error
+
+	mu       sync.Mutex
+	Drafts   []Draft
+	Issues   []Issue
+	Comments []Comment
+}
+
+var _ github.ProjectManagerInterface = &ProjectManager{}
+
+func (f *ProjectManager) GetProjectFields() ([]github.ProjectFieldInfo, error) {
+	return f.Fields, f.Err
+}
+
+func (f *ProjectManager) CreateDraftIssue(title, body, board string) error {
+	if f.Err != nil {
+		return f.Err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Drafts = append(f.Drafts, Draft{Title: title, Body: body, Board: board})
+	return nil
+}
+
+func (f *ProjectManager) CreateIssue(title, body, board string, options *github.IssueOptions) (*github.Issue, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	number := len(f.Issues) + 1
+	issue := github.Issue{
+		ID:     fmt.Sprintf("I_%d", number),
+		Number: number,
+		Title:  title,
+		URL:    fmt.Sprintf("https://github.com/%s/%s/issues/%d", options.Owner, options.Repo, number),
+	}
+	f.Issues = append(f.Issues, Issue{Issue: issue, Body: body, Board: board, Options: options})
+	return &issue, nil
+}
+
+func (f *ProjectManager) ListMergedPullRequests(owner, repo string, from, to time.Time) ([]github.PullRequest, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	var pulls []github.PullRequest
+	for _, pull := range f.Pulls {
+		if !pull.MergedAt.Before(from) && !pull.MergedAt.After(to) {
+			pulls = append(pulls, pull)
+		}
+	}
+	return pulls, nil
+}
+
+func (f *ProjectManager) FindClosedIssue(owner, repo, testName string, closedSince time.Time) (*github.Issue, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if issue := f.Closed[testName]; issue != nil && !issue.ClosedAt.Before(closedSince) {
+		return issue, nil
+	}
+	return nil, nil
+}
+
+func (f *ProjectManager) ReopenIssue(issue *github.Issue, comment string) error {
+	return f.comment(issue, comment, "reopen")
+}
+
+func (f *ProjectManager) FindOpenIssue(owner, repo, testName string) (*github.Issue, error) {
+	return f.Open[testName], f.Err
+}
+
+func (f *ProjectManager) CountOpenIssues(owner, repo string, labels []string, milestone string) (int, error) {
+	return f.OpenCounts[milestone], f.Err
+}
+
+func (f *ProjectManager) GetIssue(owner, repo string, number int) (*github.Issue, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return &github.Issue{
+		ID:     fmt.Sprintf("%s/%s#%d", owner, repo, number),
+		Number: number,
+		URL:    fmt.Sprintf("https://github.com/%s/%s/issues/%d", owner, repo, number),
+	}, nil
+}
+
+func (f *ProjectManager) CommentIssue(issue *github.Issue, comment string) error {
+	return f.comment(issue, comment, "comment")
+}
+
+func (f *ProjectManager) CloseIssue(issue *github.Issue, comment string) error {
+	return f.comment(issue, comment, "close")
+}
+
+func (f *ProjectManager) FindExistingIssues(testName string) ([]github.Issue, error) {
+	return f.Existing[testName], f.Err
+}
+
+func (f *ProjectManager) ListBoardIssues() ([]github.BoardIssue, error) {
+	return f.BoardIssues, f.Err
+}
+
+func (f *ProjectManager) BootstrapBoard(release string, dryRun bool) ([]github.BoardChange, error) {
+	return f.BoardChanges, f.Err
+}
+
+func (f *ProjectManager) RateLimit() (*github.RateLimit, error) {
+	if f.Limit == nil {
+		return &github.RateLimit{Limit: 5000, Remaining: 5000}, f.Err
+	}
+	return f.Limit, f.Err
+}
+
+// comment records the comment of the action on the issue.
+func (f *ProjectManager) comment(issue *github.Issue, comment, action string) error {
+	if f.Err != nil {
+		return f.Err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Comments = append(f.Comments, Comment{Issue: issue, Comment: comment, Action: action})
+	return nil
+}
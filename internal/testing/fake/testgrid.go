@@ -0,0 +1,106 @@
+// Package fake has in-memory implementations of the TestGrid and GitHub
+// clients with scripted responses, for the unit tests of the reconciler,
+// the pipeline and the MCP handlers without network, see the servers of the
+// testing package for the end-to-end tests.
+package fake
+
+import (
+	"fmt"
+	"slices"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/testgrid"
+)
+
+// TestGrid is a TestGrid client returning the scripted summaries, tabs and
+// test groups, or Err when set.
+type TestGrid struct {
+	// Summaries are the tab summaries of each dashboard.
+	Summaries map[string][]v1alpha1.DashboardSummary
+
+	// Tabs are the failing and flaky tests of each tab, by tab name. They are
+	// returned as is, the minimum failures and flakes are not applied.
+	Tabs map[string]*v1alpha1.DashboardTab
+
+	// TestGroups are the test groups of each tab, by tab name.
+	TestGroups map[string]*testgrid.TestGroup
+
+	// Groups are the dashboards of each dashboard group.
+	Groups map[string][]string
+
+	Err error
+}
+
+var _ testgrid.TestGridInterface = &TestGrid{}
+
+var _ testgrid.DashboardGroupFetcher = &TestGrid{}
+
+var _ testgrid.GridFetcher = &TestGrid{}
+
+// FetchDashboardGroup returns the dashboards of the group, or
+// testgrid.ErrNotDashboardGroup when not scripted.
+func (g *TestGrid) FetchDashboardGroup(group string) ([]string, error) {
+	if g.Err != nil {
+		return nil, g.Err
+	}
+	dashboards, found := g.Groups[group]
+	if !found {
+		return nil, testgrid.ErrNotDashboardGroup
+	}
+	return dashboards, nil
+}
+
+// FetchGrid returns the grid of the test group of the tab.
+func (g *TestGrid) FetchGrid(dashboard, tab string) (*testgrid.Grid, error) {
+	if g.Err != nil {
+		return nil, g.Err
+	}
+	group, found := g.TestGroups[tab]
+	if !found {
+		return nil, fmt.Errorf("error fetching grid: tab %s not found", tab)
+	}
+	return group.Grid(), nil
+}
+
+// FetchTabSummary returns the summaries of the dashboard with one of the
+// statuses, all of them without statuses.
+func (g *TestGrid) FetchTabSummary(dashboard string, filterStatus []string) ([]v1alpha1.DashboardSummary, error) {
+	if g.Err != nil {
+		return nil, g.Err
+	}
+	summaries, found := g.Summaries[dashboard]
+	if !found {
+		return nil, fmt.Errorf("error fetching summary: dashboard %s not found", dashboard)
+	}
+	var filtered []v1alpha1.DashboardSummary
+	for _, summary := range summaries {
+		if len(filterStatus) == 0 || slices.Contains(filterStatus, summary.OverallState) {
+			filtered = append(filtered, summary)
+		}
+	}
+	return filtered, nil
+}
+
+// FetchTestGroup returns the test group of the summary tab.
+func (g *TestGrid) FetchTestGroup(summary *v1alpha1.DashboardSummary) (*testgrid.TestGroup, error) {
+	if g.Err != nil {
+		return nil, g.Err
+	}
+	group, found := g.TestGroups[summary.DashboardTab.TabName]
+	if !found {
+		return nil, fmt.Errorf("error fetching test group: tab %s not found", summary.DashboardTab.TabName)
+	}
+	return group, nil
+}
+
+// FetchTabTests returns the tab of the summary, an empty tab of the summary
+// when not scripted.
+func (g *TestGrid) FetchTabTests(summary *v1alpha1.DashboardSummary, minFailure, minFlake int) (*v1alpha1.DashboardTab, error) {
+	if g.Err != nil {
+		return nil, g.Err
+	}
+	if tab, found := g.Tabs[summary.DashboardTab.TabName]; found {
+		return tab, nil
+	}
+	return summary.DashboardTab, nil
+}
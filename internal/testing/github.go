@@ -0,0 +1,396 @@
+package testing
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	mutationRegex = regexp.MustCompile(`^mutation\([^)]*\)\{(\w+)\(`)
+	titleRegex    = regexp.MustCompile(`in:title "([^"]*)"`)
+	existingRegex = regexp.MustCompile(`"([^"]*)" in:title,body`)
+	mergedRegex   = regexp.MustCompile(`merged:(\S+)\.\.(\S+)`)
+)
+
+// Draft is a draft issue created in the fake project board.
+type Draft struct {
+	ItemID string
+	Title  string
+	Body   string
+}
+
+// Issue is an issue of the fake repository.
+type Issue struct {
+	ID       string     `json:"id"`
+	Number   int        `json:"number"`
+	Title    string     `json:"title"`
+	URL      string     `json:"url"`
+	State    string     `json:"state"`
+	ClosedAt *time.Time `json:"closedAt,omitempty"`
+	Body     string     `json:"body,omitempty"`
+	Comments []string   `json:"comments,omitempty"`
+}
+
+// GitHubServer is a fake GitHub GraphQL API of a single project board and
+// repository, recording the drafts, issues and project item fields.
+type GitHubServer struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	fields       []map[string]interface{}
+	pullRequests []json.RawMessage
+	issues       []*Issue
+	drafts       []Draft
+	itemFields   map[string]map[string]string
+	items        int
+}
+
+// NewGitHubServer starts the fake GitHub server with the fixture project
+// fields, merged pull requests and open issues.
+func NewGitHubServer() (*GitHubServer, error) {
+	data, err := fixtures.ReadFile("fixtures/github.json")
+	if err != nil {
+		return nil, err
+	}
+	var fixture struct {
+		Fields       []map[string]interface{} `json:"fields"`
+		PullRequests []json.RawMessage        `json:"pullRequests"`
+		Issues       []*Issue                 `json:"issues"`
+	}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("error parsing github fixture: %v", err)
+	}
+	s := &GitHubServer{
+		fields:       fixture.Fields,
+		pullRequests: fixture.PullRequests,
+		issues:       fixture.Issues,
+		itemFields:   map[string]map[string]string{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /user", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("X-OAuth-Scopes", "project, repo")
+		writeJSON(w, map[string]string{"login": "signalhound-selftest"})
+	})
+	mux.HandleFunc("POST /graphql", s.serveGraphQL)
+	s.Server = httptest.NewServer(mux)
+	return s, nil
+}
+
+// Drafts returns the draft issues created in the project board.
+func (s *GitHubServer) Drafts() []Draft {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Draft{}, s.drafts...)
+}
+
+// Issue returns a copy of the issue with the number, nil when missing.
+func (s *GitHubServer) Issue(number int) *Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, issue := range s.issues {
+		if issue.Number == number {
+			found := *issue
+			found.Comments = append([]string{}, issue.Comments...)
+			return &found
+		}
+	}
+	return nil
+}
+
+// FieldOptions returns the option IDs of the single select field, by name.
+func (s *GitHubServer) FieldOptions(fieldID string) map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	options := map[string]string{}
+	if field := s.field(fieldID); field != nil {
+		values, _ := field["options"].([]interface{})
+		for _, value := range values {
+			option, _ := value.(map[string]interface{})
+			options[fmt.Sprint(option["name"])] = fmt.Sprint(option["id"])
+		}
+	}
+	return options
+}
+
+// FieldNames returns the names of the project fields, in the board order.
+func (s *GitHubServer) FieldNames() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var names []string
+	for _, field := range s.fields {
+		names = append(names, fmt.Sprint(field["name"]))
+	}
+	return names
+}
+
+// ItemFields returns the single select option IDs set on the project item,
+// by field ID.
+func (s *GitHubServer) ItemFields(itemID string) map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fields := map[string]string{}
+	for field, option := range s.itemFields[itemID] {
+		fields[field] = option
+	}
+	return fields
+}
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+func (s *GitHubServer) serveGraphQL(w http.ResponseWriter, r *http.Request) {
+	if !authorized(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var request graphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	data, err := s.resolve(&request)
+	s.mu.Unlock()
+	if err != nil {
+		writeJSON(w, map[string]interface{}{"errors": []map[string]string{{"message": err.Error()}}})
+		return
+	}
+	writeJSON(w, map[string]interface{}{"data": data})
+}
+
+// resolve returns the data of the query or mutation, the operation is
+// recognized by its root field.
+func (s *GitHubServer) resolve(request *graphQLRequest) (interface{}, error) {
+	if match := mutationRegex.FindStringSubmatch(request.Query); match != nil {
+		input, _ := request.Variables["input"].(map[string]interface{})
+		return s.mutate(match[1], input)
+	}
+
+	switch {
+	case strings.Contains(request.Query, "items(last: $items)"):
+		nodes := []interface{}{}
+		for _, draft := range s.drafts {
+			nodes = append(nodes, map[string]interface{}{
+				"id": draft.ItemID, "content": map[string]string{"__typename": "DraftIssue", "title": draft.Title, "body": draft.Body},
+			})
+		}
+		return map[string]interface{}{"node": map[string]interface{}{"items": map[string]interface{}{"nodes": nodes}}}, nil
+	case strings.Contains(request.Query, "fields(first: 50)"):
+		return map[string]interface{}{"node": map[string]interface{}{"fields": map[string]interface{}{"nodes": s.fields}}}, nil
+	case strings.Contains(request.Query, "node(id: $fieldID)"):
+		field := s.field(request.Variables["fieldID"])
+		if field == nil {
+			return nil, fmt.Errorf("project field %v not found", request.Variables["fieldID"])
+		}
+		return map[string]interface{}{"node": map[string]interface{}{"options": field["options"]}}, nil
+	case strings.Contains(request.Query, "search("):
+		query, _ := request.Variables["query"].(string)
+		return s.search(query)
+	case strings.Contains(request.Query, "label("):
+		return map[string]interface{}{"repository": map[string]interface{}{
+			"label": map[string]string{"id": fmt.Sprintf("LA_%v", request.Variables["label"])},
+		}}, nil
+	case strings.Contains(request.Query, "user("):
+		return map[string]interface{}{"user": map[string]string{"id": fmt.Sprintf("U_%v", request.Variables["login"])}}, nil
+	case strings.Contains(request.Query, "repository("):
+		return map[string]interface{}{"repository": map[string]interface{}{
+			"id": "R_kubernetes", "milestones": map[string]interface{}{"nodes": []interface{}{}},
+		}}, nil
+	}
+	return nil, fmt.Errorf("unsupported query: %s", request.Query)
+}
+
+// search returns the merged pull requests in the merge window of a pull
+// request search, the open issues with the term in their title or body of an
+// existing issues search, or the issues with the title term in the searched
+// state.
+func (s *GitHubServer) search(query string) (interface{}, error) {
+	nodes := []interface{}{}
+	if strings.Contains(query, "is:pr") {
+		match := mergedRegex.FindStringSubmatch(query)
+		if match == nil {
+			return nil, fmt.Errorf("unsupported pull request search: %s", query)
+		}
+		from, _ := time.Parse(time.RFC3339, match[1])
+		to, _ := time.Parse(time.RFC3339, match[2])
+		for _, pull := range s.pullRequests {
+			var merged struct {
+				MergedAt time.Time `json:"mergedAt"`
+			}
+			if err := json.Unmarshal(pull, &merged); err == nil && !merged.MergedAt.Before(from) && !merged.MergedAt.After(to) {
+				nodes = append(nodes, pull)
+			}
+		}
+		return map[string]interface{}{"search": map[string]interface{}{
+			"pageInfo": map[string]interface{}{"endCursor": "", "hasNextPage": false},
+			"nodes":    nodes,
+		}}, nil
+	}
+
+	if match := existingRegex.FindStringSubmatch(query); match != nil {
+		for _, issue := range s.issues {
+			if issue.State == "OPEN" && (strings.Contains(issue.Title, match[1]) || strings.Contains(issue.Body, match[1])) {
+				nodes = append(nodes, map[string]interface{}{
+					"id": issue.ID, "number": issue.Number, "title": issue.Title, "url": issue.URL, "body": issue.Body,
+				})
+			}
+		}
+		return map[string]interface{}{"search": map[string]interface{}{"nodes": nodes}}, nil
+	}
+
+	term := ""
+	if match := titleRegex.FindStringSubmatch(query); match != nil {
+		term = match[1]
+	}
+	for _, issue := range s.issues {
+		open := issue.State == "OPEN"
+		if strings.Contains(issue.Title, term) && ((open && strings.Contains(query, "is:open")) || (!open && strings.Contains(query, "is:closed"))) {
+			nodes = append(nodes, map[string]interface{}{
+				"id": issue.ID, "number": issue.Number, "title": issue.Title, "url": issue.URL, "closedAt": issue.ClosedAt,
+			})
+		}
+	}
+	return map[string]interface{}{"search": map[string]interface{}{"nodes": nodes}}, nil
+}
+
+// mutate applies the mutation with its input.
+func (s *GitHubServer) mutate(name string, input map[string]interface{}) (interface{}, error) {
+	str := func(key string) string {
+		value, _ := input[key].(string)
+		return value
+	}
+
+	switch name {
+	case "addProjectV2DraftIssue":
+		itemID := s.newItem()
+		s.drafts = append(s.drafts, Draft{ItemID: itemID, Title: str("title"), Body: str("body")})
+		return map[string]interface{}{name: map[string]interface{}{"projectItem": map[string]string{"id": itemID}}}, nil
+	case "addProjectV2ItemById":
+		return map[string]interface{}{name: map[string]interface{}{"item": map[string]string{"id": s.newItem()}}}, nil
+	case "updateProjectV2ItemFieldValue":
+		itemFields, found := s.itemFields[str("itemId")]
+		if !found {
+			return nil, fmt.Errorf("project item %q not found", str("itemId"))
+		}
+		value, _ := input["value"].(map[string]interface{})
+		itemFields[str("fieldId")], _ = value["singleSelectOptionId"].(string)
+	case "updateProjectV2Field":
+		field := s.field(input["fieldId"])
+		if field == nil {
+			return nil, fmt.Errorf("project field %v not found", input["fieldId"])
+		}
+		options, _ := input["singleSelectOptions"].([]interface{})
+		updated := []interface{}{}
+		for i, value := range options {
+			option, _ := value.(map[string]interface{})
+			if _, found := option["id"]; !found {
+				option["id"] = fmt.Sprintf("OPT_new_%d", i)
+			}
+			updated = append(updated, map[string]interface{}{"id": option["id"], "name": option["name"]})
+		}
+		field["options"] = updated
+		if fieldName, found := input["name"]; found {
+			field["name"] = fieldName
+		}
+		return map[string]interface{}{name: map[string]interface{}{"projectV2Field": map[string]interface{}{"options": updated}}}, nil
+	case "createProjectV2Field":
+		options, _ := input["singleSelectOptions"].([]interface{})
+		created := []interface{}{}
+		for i, value := range options {
+			option, _ := value.(map[string]interface{})
+			created = append(created, map[string]interface{}{"id": fmt.Sprintf("OPT_%d_%d", len(s.fields), i), "name": option["name"]})
+		}
+		s.fields = append(s.fields, map[string]interface{}{
+			"__typename": "ProjectV2SingleSelectField", "id": fmt.Sprintf("PVTSSF_%d", len(s.fields)), "name": str("name"), "options": created,
+		})
+	case "createIssue":
+		number := 0
+		for _, issue := range s.issues {
+			number = max(number, issue.Number)
+		}
+		number++
+		issue := &Issue{
+			ID:     fmt.Sprintf("I_%d", number),
+			Number: number,
+			Title:  str("title"),
+			URL:    fmt.Sprintf("https://github.com/kubernetes/kubernetes/issues/%d", number),
+			State:  "OPEN",
+			Body:   str("body"),
+		}
+		s.issues = append(s.issues, issue)
+		return map[string]interface{}{name: map[string]interface{}{"issue": map[string]interface{}{
+			"id": issue.ID, "number": issue.Number, "url": issue.URL,
+		}}}, nil
+	case "addComment", "closeIssue", "reopenIssue":
+		id := str("issueId")
+		if name == "addComment" {
+			id = str("subjectId")
+		}
+		issue := s.findIssue(id)
+		if issue == nil {
+			return nil, fmt.Errorf("issue %q not found", id)
+		}
+		switch name {
+		case "addComment":
+			issue.Comments = append(issue.Comments, str("body"))
+		case "closeIssue":
+			now := time.Now().UTC()
+			issue.State, issue.ClosedAt = "CLOSED", &now
+		case "reopenIssue":
+			issue.State, issue.ClosedAt = "OPEN", nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported mutation: %s", name)
+	}
+	return map[string]interface{}{name: map[string]string{"clientMutationId": ""}}, nil
+}
+
+// field returns the project field with the ID, nil when missing.
+func (s *GitHubServer) field(id interface{}) map[string]interface{} {
+	for _, field := range s.fields {
+		if field["id"] == id {
+			return field
+		}
+	}
+	return nil
+}
+
+func (s *GitHubServer) newItem() string {
+	s.items++
+	itemID := fmt.Sprintf("PVTI_%d", s.items)
+	s.itemFields[itemID] = map[string]string{}
+	return itemID
+}
+
+func (s *GitHubServer) findIssue(id string) *Issue {
+	for _, issue := range s.issues {
+		if issue.ID == id {
+			return issue
+		}
+	}
+	return nil
+}
+
+// authorized returns true when the request carries the fake token.
+func authorized(r *http.Request) bool {
+	return r.Header.Get("Authorization") == "Bearer "+Token
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
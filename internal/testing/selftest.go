@@ -0,0 +1,229 @@
+package testing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"sigs.k8s.io/signalhound/internal/mcp"
+	"sigs.k8s.io/signalhound/pkg/pipeline"
+)
+
+const (
+	// culpritPull and fixPull are the fixture pull requests expected as the
+	// culprit of the failing test and the fix of the recovered one.
+	culpritPull = 130001
+	fixPull     = 130002
+
+	// resolvedIssue is the fixture issue of the recovered test.
+	resolvedIssue = 129000
+)
+
+// Step is the outcome of a step of the self test.
+type Step struct {
+	Name   string
+	Detail string
+	Err    error
+}
+
+// SelfTest runs the fetch, analysis, report, culprit search, issue creation,
+// resolution and MCP stages against the environment. It stops at the first
+// failing step, the failure is the Err of the last step.
+func SelfTest(ctx context.Context, env *Environment) (steps []Step) {
+	var (
+		failing, recovered *pipeline.Finding
+		report             *pipeline.Report
+	)
+	stages := []struct {
+		name string
+		run  func() (string, error)
+	}{
+		{"fetch TestGrid", func() (string, error) {
+			fetched, err := pipeline.NewFetcher(env.TestGrid.URL, 2, 3).Fetch([]string{Dashboard})
+			if err != nil {
+				return "", err
+			}
+			if len(fetched) != 2 {
+				return "", fmt.Errorf("expected a failing and a flaky tab, got %d tabs", len(fetched))
+			}
+			findings := pipeline.NewAnalyzer().Analyze(fetched)
+			for _, finding := range findings {
+				switch {
+				case finding.Recovered():
+					recovered = finding
+				case finding.Failing() && failing == nil:
+					failing = finding
+				}
+			}
+			if failing == nil || recovered == nil {
+				return "", errors.New("expected a failing and a recovered test")
+			}
+			return fmt.Sprintf("%d tabs, %d findings", len(fetched), len(findings)), nil
+		}},
+		{"find culprits", func() (string, error) {
+			culprits, err := pipeline.FindCulprits(ctx, Token, failing, 3)
+			if err != nil {
+				return "", err
+			}
+			if len(culprits) == 0 || culprits[0].Number != culpritPull {
+				return "", fmt.Errorf("expected #%d as the first culprit, got %v", culpritPull, culprits)
+			}
+			failing.Culprits = culprits
+			return fmt.Sprintf("#%d %s", culprits[0].Number, culprits[0].Title), nil
+		}},
+		{"render reports", func() (string, error) {
+			var err error
+			if report, err = pipeline.NewIssueReporter().Report(failing); err != nil {
+				return "", err
+			}
+			if !strings.Contains(report.Body, fmt.Sprintf("#%d", culpritPull)) {
+				return "", errors.New("the issue body misses the culprit")
+			}
+			slack, err := pipeline.NewSlackReporter().Report(failing)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%q and a %d bytes Slack message", report.Title, len(slack.Body)), nil
+		}},
+		{"create draft issue", func() (string, error) {
+			if err := pipeline.NewProjectCreator(ctx, Token).Create(failing, report); err != nil {
+				return "", err
+			}
+			drafts := env.GitHub.Drafts()
+			if len(drafts) != 1 || drafts[0].Title != report.Title {
+				return "", fmt.Errorf("expected the draft %q, got %d drafts", report.Title, len(drafts))
+			}
+			fields := env.GitHub.ItemFields(drafts[0].ItemID)
+			if len(fields) != 4 {
+				return "", fmt.Errorf("expected 4 project fields set on the draft, got %v", fields)
+			}
+			return fmt.Sprintf("%s with %d fields", drafts[0].ItemID, len(fields)), nil
+		}},
+		{"resolve recovered issue", func() (string, error) {
+			closed, err := pipeline.NewProjectResolver(ctx, Token).Resolve(recovered)
+			if err != nil {
+				return "", err
+			}
+			if closed == nil || closed.Number != resolvedIssue {
+				return "", fmt.Errorf("expected #%d to be closed", resolvedIssue)
+			}
+			resolved := env.GitHub.Issue(resolvedIssue)
+			if resolved.State != "CLOSED" || len(resolved.Comments) != 1 || !strings.Contains(resolved.Comments[0], fmt.Sprintf("#%d", fixPull)) {
+				return "", fmt.Errorf("expected #%d closed with #%d as likely fix", resolvedIssue, fixPull)
+			}
+			return fmt.Sprintf("#%d closed, likely fix #%d", resolvedIssue, fixPull), nil
+		}},
+		{"MCP create_draft_issue", func() (string, error) {
+			result, err := CallTool(ctx, env.MCP.URL, Token, "create_draft_issue", map[string]string{
+				"title": "[Flaking Test] selftest", "body": "created over MCP", "board": Dashboard + "#kind-master",
+			})
+			if err != nil {
+				return "", err
+			}
+			if result.IsError || len(env.GitHub.Drafts()) != 2 {
+				return "", fmt.Errorf("expected a second draft, got %v", result.Content)
+			}
+			return result.Content[0].Text, nil
+		}},
+		{"MCP get_failing_tests", func() (string, error) {
+			result, err := CallTool(ctx, env.MCP.URL, Token, "get_failing_tests", map[string][]string{
+				"dashboards": {Dashboard},
+			})
+			if err != nil {
+				return "", err
+			}
+			if result.IsError || len(result.Content) == 0 {
+				return "", fmt.Errorf("expected the failing tests, got %v", result.Content)
+			}
+			var tests struct {
+				Tabs []json.RawMessage `json:"tabs"`
+			}
+			if err := json.Unmarshal([]byte(result.Content[0].Text), &tests); err != nil {
+				return "", err
+			}
+			if len(tests.Tabs) != 2 {
+				return "", fmt.Errorf("expected a failing and a flaky tab, got %d tabs", len(tests.Tabs))
+			}
+			return fmt.Sprintf("%d tabs", len(tests.Tabs)), nil
+		}},
+	}
+
+	for _, stage := range stages {
+		detail, err := stage.run()
+		steps = append(steps, Step{Name: stage.name, Detail: detail, Err: err})
+		if err != nil {
+			break
+		}
+	}
+	return steps
+}
+
+// CallTool initializes an MCP session on the Streamable HTTP server at url,
+// calls the tool with the arguments and closes the session.
+func CallTool(ctx context.Context, url, token, tool string, arguments interface{}) (*mcp.ToolResult, error) {
+	var (
+		session string
+		result  mcp.ToolResult
+	)
+	call := func(id int, method string, params interface{}, result interface{}) error {
+		body, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": id, "method": method, "params": params})
+		if err != nil {
+			return err
+		}
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Authorization", "Bearer "+token)
+		if session != "" {
+			request.Header.Set(mcp.SessionHeader, session)
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			return err
+		}
+		defer response.Body.Close() // nolint
+		if response.StatusCode != http.StatusOK {
+			return fmt.Errorf("%s: unexpected response: %s", method, response.Status)
+		}
+		if id := response.Header.Get(mcp.SessionHeader); id != "" {
+			session = id
+		}
+		var rpc struct {
+			Result json.RawMessage `json:"result"`
+			Error  *mcp.Error      `json:"error"`
+		}
+		if err := json.NewDecoder(response.Body).Decode(&rpc); err != nil {
+			return err
+		}
+		if rpc.Error != nil {
+			return fmt.Errorf("%s: %s", method, rpc.Error.Message)
+		}
+		return json.Unmarshal(rpc.Result, result)
+	}
+
+	var initialize map[string]interface{}
+	if err := call(1, "initialize", map[string]interface{}{
+		"protocolVersion": mcp.ProtocolVersion,
+		"clientInfo":      map[string]string{"name": "signalhound-selftest", "version": "dev"},
+	}, &initialize); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if request, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil); err == nil {
+			request.Header.Set(mcp.SessionHeader, session)
+			if response, err := http.DefaultClient.Do(request); err == nil {
+				response.Body.Close() // nolint
+			}
+		}
+	}()
+	if err := call(2, "tools/call", map[string]interface{}{"name": tool, "arguments": arguments}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,104 @@
+// Package testing provides fake TestGrid, GitHub GraphQL and MCP servers
+// serving canned fixtures, to run the signalhound pipeline end to end without
+// network access in integration tests, local demos and signalhound selftest.
+package testing
+
+import (
+	"embed"
+	"net/http"
+	"net/http/httptest"
+
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/mcp"
+)
+
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+const (
+	// Dashboard is the dashboard served by the fake TestGrid, with a failing
+	// and a flaky tab.
+	Dashboard = "sig-release-master-blocking"
+
+	// Token is the only GitHub token accepted by the fake GitHub server.
+	Token = "signalhound-selftest"
+)
+
+// Environment is a fake TestGrid, GitHub and MCP server wired together.
+type Environment struct {
+	TestGrid *httptest.Server
+	GitHub   *GitHubServer
+	MCP      *httptest.Server
+
+	previousGraphQLURL  string
+	previousTestGridURL string
+}
+
+// NewEnvironment starts the fake servers and points the GitHub clients and
+// the MCP tools to the fake GitHub and TestGrid servers until Close.
+func NewEnvironment() (*Environment, error) {
+	gitHub, err := NewGitHubServer()
+	if err != nil {
+		return nil, err
+	}
+	env := &Environment{
+		TestGrid:            NewTestGridServer(),
+		GitHub:              gitHub,
+		previousGraphQLURL:  github.GraphQLURL,
+		previousTestGridURL: mcp.TestGridURL,
+	}
+	github.GraphQLURL = gitHub.URL + "/graphql"
+	mcp.TestGridURL = env.TestGrid.URL
+	env.MCP = NewMCPServer(Token)
+	return env, nil
+}
+
+// Close stops the fake servers and restores the GitHub and TestGrid
+// endpoints.
+func (e *Environment) Close() {
+	e.MCP.Close()
+	e.GitHub.Close()
+	e.TestGrid.Close()
+	github.GraphQLURL = e.previousGraphQLURL
+	mcp.TestGridURL = e.previousTestGridURL
+}
+
+// NewTestGridServer serves the summary and the tab tables of Dashboard, the
+// other dashboards have no tabs.
+func NewTestGridServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{dashboard}/summary", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("dashboard") != Dashboard {
+			writeJSON(w, map[string]interface{}{})
+			return
+		}
+		serveFixture(w, "summary.json")
+	})
+	mux.HandleFunc("GET /{dashboard}/table", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("dashboard") != Dashboard {
+			http.NotFound(w, r)
+			return
+		}
+		serveFixture(w, r.URL.Query().Get("tab")+".json")
+	})
+	return httptest.NewServer(mux)
+}
+
+// NewMCPServer serves the signalhound MCP tools over the Streamable HTTP
+// transport, token is the GitHub token of the sessions without their own.
+func NewMCPServer(token string) *httptest.Server {
+	server := mcp.NewServer("signalhound", "selftest", token)
+	mcp.RegisterTools(server)
+	return httptest.NewServer(server.HTTPHandler())
+}
+
+// serveFixture writes the fixture file, a missing one is not found.
+func serveFixture(w http.ResponseWriter, name string) {
+	data, err := fixtures.ReadFile("fixtures/" + name)
+	if err != nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,142 @@
+package testing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/signalhound/internal/github"
+)
+
+func TestSelfTest(t *testing.T) {
+	env, err := NewEnvironment()
+	assert.NoError(t, err)
+	defer env.Close()
+
+	steps := SelfTest(context.Background(), env)
+	assert.Len(t, steps, 7)
+	for _, step := range steps {
+		assert.NoError(t, step.Err, step.Name)
+	}
+
+	drafts := env.GitHub.Drafts()
+	assert.Equal(t, map[string]string{
+		"PVTSSF_release": "OPT_135", "PVTSSF_view": "OPT_tracking", "PVTSSF_status": "OPT_drafting", "PVTSSF_board": "OPT_blocking",
+	}, env.GitHub.ItemFields(drafts[0].ItemID))
+	assert.Contains(t, drafts[0].Body, "[sig-node] Pods should run a pod to completion")
+}
+
+func TestEnvironmentClose(t *testing.T) {
+	previous := github.GraphQLURL
+	env, err := NewEnvironment()
+	assert.NoError(t, err)
+	assert.NotEqual(t, previous, github.GraphQLURL)
+	env.Close()
+	assert.Equal(t, previous, github.GraphQLURL)
+}
+
+func TestGitHubServerUnauthorized(t *testing.T) {
+	server, err := NewGitHubServer()
+	assert.NoError(t, err)
+	defer server.Close()
+
+	response, err := http.Post(server.URL+"/graphql", "application/json", nil)
+	assert.NoError(t, err)
+	defer response.Body.Close() // nolint
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestCreateReleaseOption(t *testing.T) {
+	env, err := NewEnvironment()
+	assert.NoError(t, err)
+	defer env.Close()
+	t.Cleanup(func() { github.Release, github.CreateReleaseOption = "", false })
+
+	draftRelease := func() string {
+		manager := github.NewProjectManager(context.Background(), Token)
+		assert.NoError(t, manager.CreateDraftIssue("[Failing Test] DNS", "body", Dashboard))
+		drafts := env.GitHub.Drafts()
+		return env.GitHub.ItemFields(drafts[len(drafts)-1].ItemID)["PVTSSF_release"]
+	}
+
+	github.Release = "1.34"
+	assert.Equal(t, "OPT_134", draftRelease())
+
+	// a missing release falls back to the latest option unless opted in
+	github.Release = "1.36"
+	assert.Equal(t, "OPT_135", draftRelease())
+
+	github.CreateReleaseOption = true
+	option := draftRelease()
+	assert.Equal(t, map[string]string{"v1.34": "OPT_134", "v1.35": "OPT_135", "v1.36": option}, env.GitHub.FieldOptions("PVTSSF_release"))
+
+	// the option is created once, and never for a past release
+	assert.Equal(t, option, draftRelease())
+	github.Release = "1.33"
+	assert.Equal(t, option, draftRelease())
+	assert.Len(t, env.GitHub.FieldOptions("PVTSSF_release"), 3)
+}
+
+func TestBootstrapBoard(t *testing.T) {
+	env, err := NewEnvironment()
+	assert.NoError(t, err)
+	defer env.Close()
+
+	manager := github.NewProjectManager(context.Background(), Token)
+	_, err = manager.BootstrapBoard("next", false)
+	assert.Error(t, err)
+
+	changes, err := manager.BootstrapBoard("1.36", true)
+	assert.NoError(t, err)
+	var actions []string
+	for _, change := range changes {
+		actions = append(actions, change.String())
+	}
+	assert.Equal(t, []string{
+		"K8s Release: add option v1.36",
+		"Status: add option New",
+		"Status: add option Observing",
+		"Status: add option Resolved",
+		"Testgrid Board: add option 1.36-blocking",
+		"Testgrid Board: add option 1.36-informing",
+	}, actions)
+	assert.Len(t, env.GitHub.FieldOptions("PVTSSF_release"), 2, "a dry run changes nothing")
+
+	changes, err = manager.BootstrapBoard("v1.36", false)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 6)
+	options := env.GitHub.FieldOptions("PVTSSF_release")
+	assert.Equal(t, "OPT_134", options["v1.34"], "the existing options are kept")
+	assert.Contains(t, options, "v1.36")
+	assert.Len(t, env.GitHub.FieldOptions("PVTSSF_status"), 4)
+
+	// the board is already set up
+	changes, err = manager.BootstrapBoard("1.36", false)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestFindExistingIssues(t *testing.T) {
+	env, err := NewEnvironment()
+	assert.NoError(t, err)
+	defer env.Close()
+
+	manager := github.NewProjectManager(context.Background(), Token)
+	assert.NoError(t, manager.CreateDraftIssue("[Flaking Test] CSI volumes should mount a volume", "flakes on kind", Dashboard))
+	assert.NoError(t, manager.CreateDraftIssue("[Failing Test] DNS should resolve", "body", Dashboard))
+
+	// the fixture issue contains the test name, the draft only its words
+	issues, err := manager.FindExistingIssues("Kubernetes e2e suite.[It] [sig-storage] CSI volumes should mount a volume")
+	assert.NoError(t, err)
+	if assert.Len(t, issues, 2) {
+		assert.Equal(t, 129000, issues[0].Number)
+		assert.True(t, issues[1].Draft)
+		assert.Equal(t, "[Flaking Test] CSI volumes should mount a volume", issues[1].Title)
+	}
+
+	issues, err = manager.FindExistingIssues("Kubernetes e2e suite.[It] [sig-apps] Deployment should roll out")
+	assert.NoError(t, err)
+	assert.Empty(t, issues)
+}
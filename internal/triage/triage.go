@@ -0,0 +1,116 @@
+// Package triage reads the failure clusters of the Kubernetes Triage
+// dashboard, the failures of the CI jobs grouped by similar error text.
+package triage
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// URL is the bucket the Triage dashboard data is published to.
+var URL = "https://storage.googleapis.com/k8s-triage"
+
+// refreshInterval is how long the clusters are kept, Triage publishes its
+// data about every hour.
+const refreshInterval = time.Hour
+
+// Cluster is a cluster of similar failures of a test, Failures counts the
+// failed builds of the test in the cluster across the jobs.
+type Cluster struct {
+	ID       string `json:"id"`
+	Text     string `json:"text"`
+	Owner    string `json:"owner,omitempty"`
+	Failures int    `json:"failures"`
+	Jobs     []Job  `json:"jobs"`
+}
+
+// Job is a job failing the test in a cluster.
+type Job struct {
+	Name     string `json:"name"`
+	Failures int    `json:"failures"`
+}
+
+// failureData serializes the clusters of failure_data.json, the builds of a
+// job are only counted.
+type failureData struct {
+	Clustered []struct {
+		ID    string `json:"id"`
+		Text  string `json:"text"`
+		Owner string `json:"owner"`
+		Tests []struct {
+			Name string `json:"name"`
+			Jobs []struct {
+				Name   string            `json:"name"`
+				Builds []json.RawMessage `json:"builds"`
+			} `json:"jobs"`
+		} `json:"tests"`
+	} `json:"clustered"`
+}
+
+// Client reads the clusters of the Triage data under URL. The clusters are
+// indexed by test and kept for refreshInterval.
+type Client struct {
+	URL string
+
+	client    *http.Client
+	mu        sync.Mutex
+	clusters  map[string][]Cluster
+	fetchedAt time.Time
+}
+
+// NewClient returns a client of the Triage data under url.
+func NewClient(url string) *Client {
+	// failure_data.json weighs hundreds of megabytes.
+	return &Client{URL: url, client: &http.Client{Timeout: 5 * time.Minute}}
+}
+
+// Clusters returns the clusters of the test, the most failures first.
+func (c *Client) Clusters(testName string) ([]Cluster, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.clusters == nil || time.Since(c.fetchedAt) > refreshInterval {
+		clusters, err := c.fetch()
+		if err != nil {
+			return nil, err
+		}
+		c.clusters, c.fetchedAt = clusters, time.Now()
+	}
+	return c.clusters[testName], nil
+}
+
+// fetch reads failure_data.json and indexes its clusters by test.
+func (c *Client) fetch() (map[string][]Cluster, error) {
+	response, err := c.client.Get(c.URL + "/failure_data.json")
+	if err != nil {
+		return nil, fmt.Errorf("error fetching triage data: %v", err)
+	}
+	defer response.Body.Close() // nolint
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching triage data: %s", response.Status)
+	}
+	var data failureData
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("error parsing triage data: %v", err)
+	}
+
+	clusters := map[string][]Cluster{}
+	for _, clustered := range data.Clustered {
+		for _, test := range clustered.Tests {
+			cluster := Cluster{ID: clustered.ID, Text: clustered.Text, Owner: clustered.Owner}
+			for _, job := range test.Jobs {
+				cluster.Jobs = append(cluster.Jobs, Job{Name: job.Name, Failures: len(job.Builds)})
+				cluster.Failures += len(job.Builds)
+			}
+			sort.SliceStable(cluster.Jobs, func(i, j int) bool { return cluster.Jobs[i].Failures > cluster.Jobs[j].Failures })
+			clusters[test.Name] = append(clusters[test.Name], cluster)
+		}
+	}
+	for _, testClusters := range clusters {
+		sort.SliceStable(testClusters, func(i, j int) bool { return testClusters[i].Failures > testClusters[j].Failures })
+	}
+	return clusters, nil
+}
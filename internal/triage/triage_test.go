@@ -0,0 +1,55 @@
+package triage
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusters(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/failure_data.json", r.URL.Path)
+		fmt.Fprint(w, `{"builds": {}, "clustered": [
+			{"id": "a1", "key": "timeout", "text": "timed out waiting for the condition", "owner": "sig-node", "tests": [
+				{"name": "[sig-node] Pods should run", "jobs": [
+					{"name": "ci-kubernetes-e2e-gci-gce", "builds": [101, 102]},
+					{"name": "ci-kubernetes-kind-e2e", "builds": [7, 8, 9]}
+				]}
+			]},
+			{"id": "b2", "key": "oom", "text": "container killed OOM", "tests": [
+				{"name": "[sig-node] Pods should run", "jobs": [{"name": "ci-kubernetes-kind-e2e", "builds": [10]}]},
+				{"name": "[sig-apps] Deployment", "jobs": [{"name": "ci-kubernetes-kind-e2e", "builds": [10]}]}
+			]}
+		]}`) // nolint
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	clusters, err := client.Clusters("[sig-node] Pods should run")
+	assert.NoError(t, err)
+	assert.Equal(t, []Cluster{
+		{ID: "a1", Text: "timed out waiting for the condition", Owner: "sig-node", Failures: 5, Jobs: []Job{
+			{Name: "ci-kubernetes-kind-e2e", Failures: 3}, {Name: "ci-kubernetes-e2e-gci-gce", Failures: 2},
+		}},
+		{ID: "b2", Text: "container killed OOM", Failures: 1, Jobs: []Job{{Name: "ci-kubernetes-kind-e2e", Failures: 1}}},
+	}, clusters)
+
+	// the data is fetched once for every test
+	clusters, err = client.Clusters("[sig-cli] Kubectl")
+	assert.NoError(t, err)
+	assert.Empty(t, clusters)
+	assert.Equal(t, 1, requests)
+}
+
+func TestClustersError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := NewClient(server.URL).Clusters("[sig-node] Pods should run")
+	assert.ErrorContains(t, err, "404 Not Found")
+}
@@ -0,0 +1,286 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/culprit"
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/issue"
+)
+
+const (
+	batchPageName = "Batch"
+
+	// maxBatchListed is the number of tests listed in the confirmation of a
+	// batch, the others are counted.
+	maxBatchListed = 10
+)
+
+var markedTests map[string]bool // Tests of the tests panel marked with space for a batch Ctrl-B, cleared on another tab
+
+// toggleMark marks the selected test for the batch issue creation, or
+// unmarks it, and moves to the next test.
+func toggleMark() {
+	index := brokenPanel.GetCurrentItem()
+	if currentTab == nil || index < 0 || index >= len(listedTests) {
+		return
+	}
+	testName := listedTests[index].TestName
+	if markedTests[testName] {
+		delete(markedTests, testName)
+	} else {
+		if markedTests == nil {
+			markedTests = map[string]bool{}
+		}
+		markedTests[testName] = true
+	}
+	brokenPanel.SetItemText(index, testItemText(listedTests[index], loadState()), "")
+	brokenPanel.SetTitle(testsTitle())
+	if index+1 < brokenPanel.GetItemCount() {
+		brokenPanel.SetCurrentItem(index + 1)
+	}
+}
+
+// markedBatch returns the marked tests of the tab, in the tab order.
+func markedBatch(tab *v1alpha1.DashboardTab, marked map[string]bool) []v1alpha1.TestResult {
+	var batch []v1alpha1.TestResult
+	for _, test := range tab.TestRuns {
+		if marked[test.TestName] {
+			batch = append(batch, test)
+		}
+	}
+	return batch
+}
+
+// brokenJob returns true when the batch has every test the job of a failing
+// tab still runs, more than one, so a single issue of the job can replace
+// an issue per test.
+func brokenJob(tab *v1alpha1.DashboardTab, batch []v1alpha1.TestResult) bool {
+	if tab.TabState != v1alpha1.FAILING_STATUS || len(batch) < 2 {
+		return false
+	}
+	marked := map[string]bool{}
+	for _, test := range batch {
+		marked[test.TestName] = true
+	}
+	for _, test := range tab.TestRuns {
+		if !test.Skipped && !marked[test.TestName] {
+			return false
+		}
+	}
+	return true
+}
+
+// batchSummary returns the text of the confirmation of a batch, the titles
+// of the issues to create and their destination.
+func batchSummary(tab *v1alpha1.DashboardTab, batch []v1alpha1.TestResult) string {
+	destination := "draft issues on the GitHub Project"
+	if repositoryIssues {
+		destination = "issues in the repository of each test"
+	}
+	var text strings.Builder
+	fmt.Fprintf(&text, "Create %d %s for %s?\n\n", len(batch), destination, tab.BoardHash)
+	for i, test := range batch {
+		if i == maxBatchListed {
+			fmt.Fprintf(&text, "... and %d more\n", len(batch)-maxBatchListed)
+			break
+		}
+		_, prefixTitle := issue.PickTemplate(issue.TestState(tab, &test))
+		fmt.Fprintf(&text, "%s\n", issue.Title(prefixTitle, test.TestName))
+	}
+	if brokenJob(tab, batch) {
+		fmt.Fprintf(&text, "\nEvery test of the job fails, a single %s can track the broken job instead.", issue.JobTitle(tab))
+	}
+	return text.String()
+}
+
+// showBatchConfirm asks to confirm the creation of an issue per marked test,
+// or of a single issue when the marked tests break the whole job.
+func showBatchConfirm() {
+	if currentTab == nil || creatingIssue {
+		return
+	}
+	tab := currentTab
+	batch := markedBatch(tab, markedTests)
+	if len(batch) == 0 {
+		position.SetText("[blue]Press [yellow]space [blue]to mark the tests, then [yellow]Ctrl-B [blue]to create their issues")
+		return
+	}
+	if readOnly {
+		position.SetText(fmt.Sprintf("[red]error: %v", errReadOnly))
+		return
+	}
+
+	createEach := fmt.Sprintf("Create %d issues", len(batch))
+	createJob := "Create one job issue"
+	buttons := []string{createEach}
+	if brokenJob(tab, batch) {
+		buttons = append(buttons, createJob)
+	}
+	buttons = append(buttons, "Cancel")
+	modal := tview.NewModal().SetText(batchSummary(tab, batch)).AddButtons(buttons).
+		SetDoneFunc(func(_ int, label string) {
+			pages.RemovePage(batchPageName)
+			app.SetFocus(brokenPanel)
+			switch label {
+			case createEach:
+				createBatchIssues(tab, batch)
+			case createJob:
+				createJobIssue(tab, batch)
+			}
+		})
+	pages.AddPage(batchPageName, modal, true, true)
+	app.SetFocus(modal)
+}
+
+// createBatchIssues renders and files the issue of every test of the batch
+// in background, one after the other. The filed tests are unmarked, the
+// failed ones stay marked for another Ctrl-B.
+func createBatchIssues(tab *v1alpha1.DashboardTab, batch []v1alpha1.TestResult) {
+	// the UI state is read here, the rendering and filing run in background.
+	tabs := currentTabs
+	templates := make([]*issue.Template, len(batch))
+	for i := range batch {
+		templates[i] = issue.NewTemplate(tab, &batch[i])
+		templates[i].Logs = includedLogs(&batch[i])
+		templates[i].Details = fetchedDetails(&batch[i])
+		templates[i].Priority = releasePhase.Priority(issue.TestState(tab, &batch[i]) == v1alpha1.FAILING_STATUS)
+	}
+	creatingIssue = true
+	stopLoading := startLoading(fmt.Sprintf("Creating %d issues on GitHub", len(batch)))
+	done := trackAction()
+	go func() {
+		defer recoverCrash()
+		filed := map[string]*github.Issue{}
+		var errs []string
+		for i := range batch {
+			test := &batch[i]
+			filedIssue, err := renderAndFileIssue(templates[i], tabs, tab, test)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", test.TestName, err))
+				continue
+			}
+			filed[test.TestName] = filedIssue
+		}
+		done()
+		app.QueueUpdateDraw(func() {
+			stopLoading()
+			creatingIssue = false
+			for testName, filedIssue := range filed {
+				delete(markedTests, testName)
+				linkCreatedIssue(testName, filedIssue)
+			}
+			if currentTab != nil && currentTab.BoardHash == tab.BoardHash {
+				selected := brokenPanel.GetCurrentItem()
+				renderTestsList(currentTab)
+				brokenPanel.SetCurrentItem(selected)
+			}
+			if len(errs) > 0 {
+				position.SetText(fmt.Sprintf("[red]Created %d of %d issues, error: %s", len(filed), len(batch), tview.Escape(strings.Join(errs, "; "))))
+				return
+			}
+			if github.DryRun {
+				position.SetText(fmt.Sprintf("[yellow]DRY RUN [blue]the %d issues were not sent to GitHub", len(filed)))
+				return
+			}
+			position.SetText(fmt.Sprintf("[blue]Created [yellow]%d ISSUES [blue]for the marked tests", len(filed)))
+		})
+	}()
+}
+
+// renderAndFileIssue renders the issue of the test like the GitHub panel and
+// files it like Ctrl-B on the panel, returning the filed issue, nil for a
+// draft.
+func renderAndFileIssue(issueTemplate *issue.Template, tabs []*v1alpha1.DashboardTab, tab *v1alpha1.DashboardTab,
+	currentTest *v1alpha1.TestResult) (*github.Issue, error) {
+	templateFile, prefixTitle := issue.PickTemplate(issue.TestState(tab, currentTest))
+	rendered, err := renderIssue(issueTemplate, templateFile, tabs, tab, currentTest)
+	if err != nil {
+		return nil, err
+	}
+	frontMatter, body, err := issue.ParseFrontMatter(rendered.body)
+	if err != nil {
+		return nil, err
+	}
+	filed, _, err := fileIssue(githubToken, tab, currentTest, issue.Title(prefixTitle, currentTest.TestName),
+		issueFrontMatter(frontMatter, tab, currentTest), body)
+	return filed, err
+}
+
+// createJobIssue files the single issue of the broken job in background,
+// the tests are unmarked once it is filed.
+func createJobIssue(tab *v1alpha1.DashboardTab, batch []v1alpha1.TestResult) {
+	creatingIssue = true
+	stopLoading := startLoading("Creating the job issue on GitHub")
+	done := trackAction()
+	go func() {
+		defer recoverCrash()
+		filed, err := fileJobIssue(githubToken, tab, batch)
+		done()
+		app.QueueUpdateDraw(func() {
+			stopLoading()
+			creatingIssue = false
+			if err != nil {
+				position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
+				return
+			}
+			markedTests = nil
+			// the job issue tracks every test of the batch.
+			for _, test := range batch {
+				linkCreatedIssue(test.TestName, filed)
+			}
+			if currentTab != nil && currentTab.BoardHash == tab.BoardHash {
+				selected := brokenPanel.GetCurrentItem()
+				renderTestsList(currentTab)
+				brokenPanel.SetCurrentItem(selected)
+			}
+			switch {
+			case github.DryRun:
+				position.SetText("[yellow]DRY RUN [blue]the job issue was not sent to GitHub")
+			case filed != nil && !filed.ClosedAt.IsZero():
+				position.SetText(fmt.Sprintf("[blue]Reopened [yellow]JOB ISSUE #%d [blue]closed on %s", filed.Number, filed.ClosedAt.Format(time.DateOnly)))
+			case filed != nil:
+				position.SetText(fmt.Sprintf("[blue]Created [yellow]JOB ISSUE #%d", filed.Number))
+			default:
+				position.SetText("[blue]Created [yellow]DRAFT JOB ISSUE [blue]on GitHub Project!")
+			}
+		})
+	}()
+}
+
+// fileJobIssue creates the issue of the broken job on GitHub, in the
+// repository of its first test when repositoryIssues is set, reopening the
+// issue of the job closed within the reopen window.
+func fileJobIssue(token string, tab *v1alpha1.DashboardTab, batch []v1alpha1.TestResult) (*github.Issue, error) {
+	if readOnly {
+		return nil, errReadOnly
+	}
+	body, err := issue.RenderJob(issue.NewJob(tab, batch))
+	if err != nil {
+		return nil, err
+	}
+	var options *github.IssueOptions
+	if repositoryIssues {
+		options = (*issue.FrontMatter)(nil).WithRepository(issue.Repository(tab.BoardHash, batch[0].TestName)).Options()
+	}
+	gh := github.NewProjectManager(context.Background(), token)
+	// the job issue title ends with the board hash, found like a test name.
+	filed, err := github.ReopenOrCreateDraft(gh, culprit.DefaultOwner, culprit.DefaultRepository,
+		tab.BoardHash, issue.JobTitle(tab), body, tab.BoardHash, options, reopenWindow)
+	switch {
+	case err != nil, github.DryRun:
+	case filed != nil && !filed.ClosedAt.IsZero():
+		recordAction(actionIssueReopened)
+	case filed != nil:
+		recordAction(actionIssueCreated)
+	default:
+		recordAction(actionDraftCreated)
+	}
+	return filed, err
+}
@@ -0,0 +1,37 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+)
+
+func TestMarkedBatch(t *testing.T) {
+	defer func() { markedTests = nil }()
+	tab := &v1alpha1.DashboardTab{BoardHash: "sig-release-master-blocking#gce", TabState: v1alpha1.FAILING_STATUS, TestRuns: []v1alpha1.TestResult{
+		{TestName: "[sig-node] Pods should run"},
+		{TestName: "[sig-network] DNS should resolve"},
+		{TestName: "[sig-apps] Deployment should roll", Skipped: true},
+	}}
+
+	markedTests = map[string]bool{"[sig-network] DNS should resolve": true}
+	batch := markedBatch(tab, markedTests)
+	assert.Equal(t, []v1alpha1.TestResult{tab.TestRuns[1]}, batch)
+	assert.False(t, brokenJob(tab, batch))
+	assert.Equal(t, "[blue]●[-] [sig-network[] DNS should resolve", testItemText(tab.TestRuns[1], nil))
+	assert.Equal(t, formatTitle("Tests - sort: [yellow]last failure[-] - [blue]1 marked[-], Ctrl-B to create their issues"), testsTitle())
+	assert.Equal(t, "Create 1 draft issues on the GitHub Project for sig-release-master-blocking#gce?\n\n"+
+		"[Failing Test] [sig-network] DNS should resolve\n", batchSummary(tab, batch))
+
+	// the job config no longer runs the skipped test, the job is broken
+	markedTests["[sig-node] Pods should run"] = true
+	batch = markedBatch(tab, markedTests)
+	assert.Len(t, batch, 2)
+	assert.True(t, brokenJob(tab, batch))
+	assert.Contains(t, batchSummary(tab, batch), "a single [Failing Job] sig-release-master-blocking#gce can track the broken job instead.")
+
+	tab.TabState = v1alpha1.FLAKY_STATUS
+	assert.False(t, brokenJob(tab, batch))
+}
@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/rivo/tview"
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/culprit"
+	"sigs.k8s.io/signalhound/internal/prow"
+)
+
+// bisectRange is the commits of the last green and the first red run of the
+// failure streak of a test, the boundaries of a bisect.
+type bisectRange struct {
+	lastPass, firstFail       string
+	lastPassURL, firstFailURL string
+}
+
+// fetchBisectRange returns the revisions of the runs bounding the failure
+// streak, the commits of the TestGrid Commit column or else the revisions of
+// their finished.json. It is best effort, nil is returned when the grid has
+// no green run before the failure or a revision is unknown.
+func fetchBisectRange(currentTest *v1alpha1.TestResult) *bisectRange {
+	if currentTest.LastPassRunURL == "" || currentTest.FirstFailRunURL == "" {
+		return nil
+	}
+	bisect := &bisectRange{lastPassURL: currentTest.LastPassRunURL, firstFailURL: currentTest.FirstFailRunURL}
+	if currentTest.LastPassCommit != "" && currentTest.FirstFailCommit != "" {
+		bisect.lastPass, bisect.firstFail = currentTest.LastPassCommit, currentTest.FirstFailCommit
+		return bisect
+	}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer recoverCrash()
+		defer wg.Done()
+		bisect.lastPass, _ = prow.NewProw(bisect.lastPassURL).GetRevision()
+	}()
+	go func() {
+		defer recoverCrash()
+		defer wg.Done()
+		bisect.firstFail, _ = prow.NewProw(bisect.firstFailURL).GetRevision()
+	}()
+	wg.Wait()
+	if bisect.lastPass == "" || bisect.firstFail == "" {
+		return nil
+	}
+	return bisect
+}
+
+// command returns the git command listing the commits of the range.
+func (b *bisectRange) command() string {
+	return fmt.Sprintf("git log %s..%s", b.lastPass, b.firstFail)
+}
+
+// bisectText returns the bisect section of the details page.
+func bisectText(bisect *bisectRange) string {
+	if bisect == nil {
+		return "[blue]Bisect:[-] no revision of a green run before the failure\n"
+	}
+	return fmt.Sprintf("[blue]Last green run:[-] %s %s\n[blue]First red run:[-] %s %s\n[blue]Bisect:[-] %s\n[blue]Suspected commits:[-] %s\n",
+		bisect.lastPass, tview.Escape(bisect.lastPassURL), bisect.firstFail, tview.Escape(bisect.firstFailURL), bisect.command(),
+		tview.Escape(culprit.CompareURL(bisect.lastPass, bisect.firstFail)))
+}
@@ -0,0 +1,34 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/fingerprint"
+	"sigs.k8s.io/signalhound/internal/issue"
+)
+
+// crossBranch returns the boards of the other branches failing with the
+// fingerprint of the test among the loaded tabs, and the cherry-pick hint.
+func crossBranch(tabs []*v1alpha1.DashboardTab, tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestResult) (boards []issue.CrossBranch, hint string) {
+	occurrences := fingerprint.NewIndex(tabs).CrossBranch(tab, currentTest)
+	for _, occurrence := range occurrences {
+		boards = append(boards, issue.CrossBranch{Branch: occurrence.Branch, BoardHash: occurrence.BoardHash, URL: occurrence.TabURL})
+	}
+	return boards, fingerprint.Hint(fingerprint.Branch(tab.BoardHash), fingerprint.Branches(occurrences))
+}
+
+// crossBranchText returns the position bar text flagging the cross-branch
+// correlation, or an empty string.
+func crossBranchText(boards []issue.CrossBranch, hint string) string {
+	if len(boards) == 0 {
+		return ""
+	}
+	var names []string
+	for _, board := range boards {
+		names = append(names, board.BoardHash)
+	}
+	return fmt.Sprintf("[yellow]Same failure on %s, %s", tview.Escape(strings.Join(names, ", ")), hint)
+}
@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/rivo/tview"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+)
+
+// errNoBrowser is returned over SSH, where the browser of the remote host
+// can't reach the user.
+var errNoBrowser = errors.New("no browser over SSH, set SIGNALHOUND_BROWSER to open the links")
+
+// BrowserCommand returns the command opening the URL in the system browser,
+// xdg-open, open or start. SIGNALHOUND_BROWSER overrides it, e.g. with a
+// script forwarding the URL from a bastion.
+func BrowserCommand(url string) ([]string, error) {
+	if browser := os.Getenv("SIGNALHOUND_BROWSER"); browser != "" {
+		return []string{browser, url}, nil
+	}
+	if isSSH() {
+		return nil, errNoBrowser
+	}
+	switch runtime.GOOS {
+	case "windows":
+		// start through cmd would split the URL on its & separators.
+		return []string{"rundll32", "url.dll,FileProtocolHandler", url}, nil
+	case "darwin":
+		return []string{"open", url}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if isWSL() {
+			return []string{"explorer.exe", url}, nil
+		}
+		return []string{"xdg-open", url}, nil
+	}
+	return nil, fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
+}
+
+// OpenURL opens the URL in the system browser without waiting for it, see
+// BrowserCommand.
+func OpenURL(url string) error {
+	command, err := BrowserCommand(url)
+	if err != nil {
+		return err
+	}
+	// the output of the browser would draw over the UI, it is discarded.
+	cmd := exec.Command(command[0], command[1:]...)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("error opening %s: %v", url, err)
+	}
+	go cmd.Wait() // nolint
+	return nil
+}
+
+// openTestLink opens the TestGrid tab (t), the Prow job (p) or the Triage
+// page (r) of the selected test. The link is copied to the clipboard when
+// no browser can be opened, e.g. over SSH.
+func openTestLink(key rune) {
+	index := brokenPanel.GetCurrentItem()
+	if currentTab == nil || index < 0 || index >= len(listedTests) {
+		return
+	}
+	name, url := testLink(currentTab.TabURL, listedTests[index], key)
+	if url == "" {
+		position.SetText(fmt.Sprintf("[red]The test has no %s link", name))
+		return
+	}
+	err := OpenURL(url)
+	if err == nil {
+		recordAction(actionLinkOpened)
+		position.SetText(fmt.Sprintf("[blue]Opened the [yellow]%s [blue]link in the browser", name))
+		return
+	}
+	if copyErr := CopyToClipboard(url); copyErr != nil {
+		position.SetText(fmt.Sprintf("[red]error: %v", tview.Escape(err.Error())))
+		return
+	}
+	position.SetText(fmt.Sprintf("[blue]COPIED the [yellow]%s [blue]link, %s", name, tview.Escape(err.Error())))
+}
+
+// testLink returns the name and the URL of the link of the key, t for the
+// TestGrid tab, p for the Prow job and r for the Triage page of the test.
+func testLink(tabURL string, test v1alpha1.TestResult, key rune) (string, string) {
+	switch key {
+	case 't':
+		return "TestGrid", tabURL
+	case 'p':
+		return "Prow", test.ProwJobURL
+	}
+	return "Triage", test.TriageURL
+}
@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// clipboardCommands are the clipboard commands tried by CopyToClipboard when
+// the detected one fails, with their arguments reading the text from the
+// standard input.
+var clipboardCommands = map[string][]string{
+	"wl-copy":  nil,
+	"xclip":    {"-selection", "clipboard"},
+	"xsel":     {"--clipboard", "--input"},
+	"pbcopy":   nil,
+	"clip.exe": nil,
+}
+
+// fallbackOrder is the order the clipboard commands are tried in.
+var fallbackOrder = []string{"wl-copy", "xclip", "xsel", "pbcopy", "clip.exe"}
+
+var clipboardTool string // Clipboard command of the configuration file, detected when empty
+
+// SetClipboardTool sets the clipboard command of the yy copies, e.g. osc52
+// or xsel, the SIGNALHOUND_CLIPBOARD environment variable wins. An empty
+// tool is detected, see ClipboardTool.
+func SetClipboardTool(tool string) {
+	clipboardTool = tool
+}
+
+// fallbackClipboardTools returns the clipboard commands found by lookPath
+// besides the failed one, then osc52 which needs no command.
+func fallbackClipboardTools(failed string, lookPath func(string) (string, error)) []string {
+	var tools []string
+	for _, tool := range fallbackOrder {
+		if tool == failed {
+			continue
+		}
+		if _, err := lookPath(tool); err == nil {
+			tools = append(tools, tool)
+		}
+	}
+	if failed != osc52Tool {
+		tools = append(tools, osc52Tool)
+	}
+	return tools
+}
+
+// copyWith copies the text with the clipboard command.
+func copyWith(tool, text string) error {
+	var cmd *exec.Cmd
+	switch tool {
+	case osc52Tool:
+		return copyOSC52(text)
+	case "clip":
+		// Native Windows
+		cmd = exec.Command("cmd", "/c", "echo "+text+" | clip")
+		// Alternative: cmd = exec.Command("powershell", "-command", "Set-Clipboard", "-Value", text)
+		return cmd.Run()
+	default:
+		cmd = exec.Command(tool, clipboardCommands[tool]...)
+	}
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
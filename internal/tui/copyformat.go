@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"regexp"
+
+	"sigs.k8s.io/signalhound/internal/slack"
+)
+
+// copyFormat is the format of the text copied by yy from the Slack and
+// GitHub panels, cycled with f on each panel.
+type copyFormat int
+
+const (
+	// markdownFormat copies the panel text as is, GitHub Markdown.
+	markdownFormat copyFormat = iota
+	// mrkdwnFormat converts the links to the Slack syntax.
+	mrkdwnFormat
+	// plainFormat strips the markup, the links keep their URL.
+	plainFormat
+)
+
+var (
+	slackCopyFormat  = markdownFormat // Format of the "yy" copies of the Slack panel
+	githubCopyFormat = markdownFormat // Format of the "yy" copies of the GitHub panel
+)
+
+var (
+	plainLinkRegex     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	plainEmphasisRegex = regexp.MustCompile(`\*\*?([^*\s](?:[^*\n]*[^*\s])?)\*\*?`)
+	plainCodeRegex     = regexp.MustCompile("`([^`\n]+)`")
+	plainFenceRegex    = regexp.MustCompile("(?m)^```.*\n?")
+	plainHeadingRegex  = regexp.MustCompile(`(?m)^#{1,6} +`)
+	plainCommentRegex  = regexp.MustCompile(`(?s)<!--.*?-->\n?`)
+)
+
+// String returns the name of the format shown on the position bar.
+func (f copyFormat) String() string {
+	switch f {
+	case mrkdwnFormat:
+		return "SLACK MRKDWN"
+	case plainFormat:
+		return "PLAIN TEXT"
+	}
+	return "MARKDOWN"
+}
+
+// next returns the format following f, back to markdown after plain text.
+func (f copyFormat) next() copyFormat {
+	return (f + 1) % (plainFormat + 1)
+}
+
+// apply converts the markdown text of a panel to the format.
+func (f copyFormat) apply(text string) string {
+	switch f {
+	case mrkdwnFormat:
+		return slack.Mrkdwn(text)
+	case plainFormat:
+		text = plainCommentRegex.ReplaceAllString(text, "")
+		text = plainFenceRegex.ReplaceAllString(text, "")
+		text = plainHeadingRegex.ReplaceAllString(text, "")
+		text = plainLinkRegex.ReplaceAllString(text, "$1 ($2)")
+		text = plainCodeRegex.ReplaceAllString(text, "$1")
+		return plainEmphasisRegex.ReplaceAllString(text, "$1")
+	}
+	return text
+}
@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+var (
+	screen   tcell.Screen        // Screen of the application, finalized on a crash
+	crashLog = DefaultCrashLog() // File the stack trace of a crash is appended to
+	exit     = os.Exit           // Exits after a crash, replaced in tests
+)
+
+// SetCrashLog sets the file the stack trace of a panic of the UI is appended
+// to, an empty path prints it on the standard error.
+func SetCrashLog(path string) {
+	crashLog = path
+}
+
+// DefaultCrashLog returns signalhound/crash.log under the user cache
+// directory.
+func DefaultCrashLog() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "signalhound", "crash.log")
+}
+
+// dryRunLog returns dry-run.log next to the crash log, the skipped GitHub
+// mutations of the UI are appended to it instead of garbling the screen.
+func dryRunLog() string {
+	if crashLog == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(crashLog), "dry-run.log")
+}
+
+// recoverCrash restores the terminal after a panic of the UI loop or of the
+// goroutine of a callback, writes the stack trace to the crash log and exits
+// with a pointer to it. It is deferred by every goroutine of the UI, a panic
+// left to the runtime would keep the terminal in raw mode.
+func recoverCrash() {
+	p := recover()
+	if p == nil {
+		return
+	}
+	// Fini is idempotent, tview already finalized the screen on a panic of
+	// the UI loop.
+	if screen != nil {
+		screen.Fini()
+	}
+	stack := debug.Stack()
+	if err := writeCrash(crashLog, p, stack, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "signalhound crashed: %v\n\n%s\n", p, stack) // nolint
+	} else {
+		fmt.Fprintf(os.Stderr, "signalhound crashed: %v\nThe stack trace is in %s, please attach it to a bug report.\n", p, crashLog) // nolint
+	}
+	exit(1)
+}
+
+// writeCrash appends the panic value and the stack trace to the file.
+func writeCrash(path string, p interface{}, stack []byte, at time.Time) error {
+	if path == "" {
+		return errors.New("no crash log")
+	}
+	file, err := appendLog(path)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(file, "%s panic: %v\n\n%s\n", at.Format(time.RFC3339), p, stack); err != nil {
+		file.Close() // nolint
+		return err
+	}
+	return file.Close()
+}
+
+// appendLog opens the file for appending, creating it and its directory.
+func appendLog(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+}
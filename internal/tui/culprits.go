@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/culprit"
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/issue"
+)
+
+const (
+	// maxCulprits limits the candidates listed in the issue body.
+	maxCulprits    = 5
+	culpritsHeader = "### Possible culprits\n"
+)
+
+// Possible culprits block rendered in the GitHub panel, replaced once the
+// merged pull requests are fetched.
+var githubCulpritsBlock string
+
+// showCulprits searches in background the pull requests merged between the
+// last green and the first red run of a failing test, and lists the best
+// ranked ones in the possible culprits section of the issue body.
+func showCulprits(tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestResult, token string) {
+	if tab.TabState != v1alpha1.FAILING_STATUS {
+		position.SetText("[yellow]Possible culprits are only searched for failing tests")
+		return
+	}
+	if currentTest.LastPassTimestamp == 0 || currentTest.FirstFailTimestamp == 0 {
+		position.SetText("[yellow]No green run found before the failure in the grid")
+		return
+	}
+
+	from := time.UnixMilli(currentTest.LastPassTimestamp)
+	to := time.UnixMilli(currentTest.FirstFailTimestamp)
+	position.SetText(fmt.Sprintf("[yellow]Searching pull requests merged between %s and %s...",
+		issue.TimeClean(currentTest.LastPassTimestamp), issue.TimeClean(currentTest.FirstFailTimestamp)))
+	go func() {
+		defer recoverCrash()
+		gh := github.NewProjectManager(context.Background(), token)
+		pulls, err := gh.ListMergedPullRequests(culprit.DefaultOwner, culprit.DefaultRepository, from, to)
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
+				return
+			}
+			var culprits []issue.Culprit
+			for _, candidate := range culprit.Rank(pulls, currentTest.TestName, maxCulprits) {
+				culprits = append(culprits, issue.Culprit{Number: candidate.Number, Title: candidate.Title, URL: candidate.URL})
+			}
+			culpritsBlock, err := issue.RenderCulprits(culprits)
+			if err != nil {
+				position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
+				return
+			}
+			if !replaceIssueBlock(culpritsHeader, &githubCulpritsBlock, culpritsBlock) {
+				position.SetText("[yellow]Possible culprits section was edited, the issue body is kept unchanged")
+				return
+			}
+			position.SetText(fmt.Sprintf("[blue]Found [yellow]%d [blue]possible culprits in %d merged pull requests", len(culprits), len(pulls)))
+		})
+	}()
+}
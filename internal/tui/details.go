@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/issue"
+	"sigs.k8s.io/signalhound/internal/prow"
+)
+
+const (
+	detailsPageName = "Test Details"
+	detailsHeader   = "### Test details\n"
+)
+
+var (
+	testDetails     *issue.TestDetails // JUnit result of the last inspected test
+	detailsBisect   *bisectRange       // Revisions bounding the failure of the last inspected test
+	detailsProwURL  string             // Prow job URL the test details were fetched from
+	detailsTestName string             // Test the details were fetched for
+	// Test details block rendered in the GitHub panel, replaced once the
+	// JUnit result is fetched.
+	githubDetailsBlock string
+)
+
+// fetchedDetails returns the test details fetched for the given test run.
+func fetchedDetails(currentTest *v1alpha1.TestResult) *issue.TestDetails {
+	if currentTest.ProwJobURL == "" || currentTest.ProwJobURL != detailsProwURL || currentTest.TestName != detailsTestName {
+		return nil
+	}
+	return testDetails
+}
+
+// showDetailsPage reads in background the JUnit result of the test in the
+// last run, shows the failure message, duration and output in their own page
+// and fills the test details section of the issue body.
+func showDetailsPage(currentTest *v1alpha1.TestResult) {
+	if currentTest.ProwJobURL == "" {
+		position.SetText("[red]error: no prow job URL for this test")
+		return
+	}
+	if details := fetchedDetails(currentTest); details != nil {
+		renderDetailsPage(details, detailsBisect)
+		return
+	}
+
+	stopLoading := startLoading("Reading the JUnit results of the run")
+	go func() {
+		defer recoverCrash()
+		result, err := prow.NewProw(currentTest.ProwJobURL).GetJUnitResult(currentTest.TestName)
+		bisect := fetchBisectRange(currentTest)
+		app.QueueUpdateDraw(func() {
+			stopLoading()
+			if err != nil {
+				position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
+				return
+			}
+			if result == nil {
+				position.SetText("[yellow]No JUnit result for this test in the job artifacts")
+				return
+			}
+			details := &issue.TestDetails{
+				File:     result.File,
+				URL:      result.URL,
+				Duration: result.Duration,
+				Message:  result.Message,
+				Failure:  result.Failure,
+				Output:   result.SystemOut,
+			}
+			testDetails, detailsProwURL, detailsTestName = details, currentTest.ProwJobURL, currentTest.TestName
+			detailsBisect = bisect
+			detailsBlock, err := issue.RenderDetails(details)
+			if err != nil {
+				position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
+				return
+			}
+			renderDetailsPage(details, bisect)
+			if !replaceIssueBlock(detailsHeader, &githubDetailsBlock, detailsBlock) {
+				position.SetText("[yellow]Test details section was edited, the issue body is kept unchanged")
+			}
+		})
+	}()
+}
+
+// renderDetailsPage shows the test details in a scrollable page, b copies
+// the git log command of the bisect range and Esc returns.
+func renderDetailsPage(details *issue.TestDetails, bisect *bisectRange) {
+	var text strings.Builder
+	fmt.Fprintf(&text, "[blue]File:[-] %s\n[blue]Duration:[-] %s\n", tview.Escape(details.File), details.Duration)
+	text.WriteString(bisectText(bisect))
+	if details.Message != "" {
+		fmt.Fprintf(&text, "[blue]Message:[-] [red]%s[-]\n", tview.Escape(details.Message))
+	}
+	if details.Failure != "" {
+		fmt.Fprintf(&text, "\n[blue]Failure:[-]\n%s\n", tview.Escape(details.Failure))
+	}
+	if details.Output != "" {
+		fmt.Fprintf(&text, "\n[blue]System out:[-]\n%s\n", tview.Escape(details.Output))
+	}
+
+	view := tview.NewTextView().SetDynamicColors(true).SetScrollable(true).SetWrap(true).SetText(text.String())
+	setPanelDefaultStyle(view.Box)
+	view.SetTitle(formatTitle(detailsPageName))
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if vimTextViewKey(view, event) {
+			return nil
+		}
+		if event.Key() == tcell.KeyRune && event.Rune() == 'b' && bisect != nil {
+			if err := CopyToClipboard(bisect.command()); err != nil {
+				position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
+				return nil
+			}
+			position.SetText("[blue]COPIED [yellow]GIT LOG [blue]TO THE CLIPBOARD!")
+			return nil
+		}
+		if event.Key() == tcell.KeyEscape {
+			pages.RemovePage(detailsPageName)
+			pages.SwitchToPage(pagesName)
+			app.SetFocus(githubPanel)
+			position.SetText(defaultPositionText)
+			return nil
+		}
+		return event
+	})
+	if bisect != nil {
+		position.SetText("[green]Press [blue]b [green]to copy the git log of the bisect range, [blue]Esc [green]to return")
+	} else {
+		position.SetText("[green]Press [blue]Esc [green]to return, the test details are in the issue body")
+	}
+	pages.AddAndSwitchToPage(detailsPageName, view, true)
+	app.SetFocus(view)
+}
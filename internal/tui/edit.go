@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	issueTitlePageName   = "Issue title"
+	discardEditsPageName = "Discard edits"
+)
+
+var (
+	githubEditing  bool   // The GitHub panel is in edit mode, entered with i and left with Esc
+	githubEdited   bool   // The issue body or title was edited since its rendering
+	issueTitleEdit string // Issue title edited with t, the title of the template when empty
+)
+
+// resetIssueEdits leaves the edit mode and drops the edits of the issue,
+// done when another issue is rendered or the edits are discarded.
+func resetIssueEdits() {
+	githubEditing, githubEdited, issueTitleEdit = false, false, ""
+}
+
+// editedIssueTitle returns the title Ctrl-B creates the issue with, the
+// edited one or the title of the template.
+func editedIssueTitle(title string) string {
+	if issueTitleEdit != "" {
+		return issueTitleEdit
+	}
+	return title
+}
+
+// editsTitle returns the suffix of the GitHub panel title flagging the edit
+// mode and the edits.
+func editsTitle() string {
+	switch {
+	case githubEditing:
+		return " - [yellow]EDITING[-]"
+	case githubEdited:
+		return " - [yellow]edited[-]"
+	}
+	return ""
+}
+
+// showTitleEdit opens the input of the issue title over the position bar,
+// prefilled with the current title. Enter keeps the title, an empty one
+// restores the title of the template, Esc cancels. done is called once the
+// input is closed.
+func showTitleEdit(title string, done func()) {
+	input := tview.NewInputField().SetLabel("Title: ").SetText(editedIssueTitle(title))
+	setPanelDefaultStyle(input.Box)
+	input.SetTitle(formatTitle("Edit the issue title"))
+	input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			if edited := strings.TrimSpace(input.GetText()); edited != editedIssueTitle(title) {
+				issueTitleEdit = edited
+				if edited == title {
+					issueTitleEdit = ""
+				}
+				githubEdited = true
+			}
+		case tcell.KeyEscape:
+		default:
+			return
+		}
+		pages.RemovePage(issueTitlePageName)
+		app.SetFocus(githubPanel)
+		position.SetText(fmt.Sprintf("[blue]Ctrl-B creates [yellow]%s", tview.Escape(editedIssueTitle(title))))
+		done()
+	})
+
+	overlay := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(input, 3, 0, true)
+	position.SetText("[green]Type the issue title, [blue]Enter [green]to keep it, an empty title restores the template one, [blue]Esc [green]to cancel")
+	pages.AddPage(issueTitlePageName, overlay, true, true)
+	app.SetFocus(input)
+}
+
+// confirmDiscardEdits runs leave once the edits of the issue, sent by Ctrl-B
+// but lost when the panel is closed or another test selected, are discarded.
+// Without edits leave runs right away.
+func confirmDiscardEdits(leave func()) {
+	if !githubEdited {
+		leave()
+		return
+	}
+	modal := tview.NewModal().
+		SetText("The issue has unsaved edits, Ctrl-B on the GitHub panel creates the issue with them. Discard the edits?").
+		AddButtons([]string{"Discard", "Keep editing"}).
+		SetDoneFunc(func(_ int, label string) {
+			pages.RemovePage(discardEditsPageName)
+			if label == "Discard" {
+				resetIssueEdits()
+				leave()
+				return
+			}
+			app.SetFocus(githubPanel)
+		})
+	pages.AddPage(discardEditsPageName, modal, true, true)
+	app.SetFocus(modal)
+}
@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/github"
+)
+
+const similarIssuesPageName = "Similar issues"
+
+// Issues similar to the test of the GitHub panel, found in background once
+// the issue is rendered so a duplicate is spotted before Ctrl-B.
+var similarIssues []github.Issue
+
+// findSimilarIssues searches in background the existing issues of the test,
+// the result is dropped when another test was selected meanwhile.
+func findSimilarIssues(renderID int, tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestResult, token string) {
+	if token == "" {
+		return
+	}
+	go func() {
+		defer recoverCrash()
+		issues, err := github.NewProjectManager(context.Background(), token).FindExistingIssues(currentTest.TestName)
+		app.QueueUpdateDraw(func() {
+			if renderID != githubRenderID {
+				return
+			}
+			if err != nil {
+				position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
+				return
+			}
+			similarIssues = issues
+			githubPanel.SetTitle(githubTitle(tab, currentTest))
+			if len(issues) > 0 {
+				position.SetText(fmt.Sprintf("[yellow]%d similar issues [blue]already exist, press [yellow]Ctrl-E [blue]to list them before filing", len(issues)))
+			}
+		})
+	}()
+}
+
+// similarIssuesText returns the similar issues one per line, the most
+// similar first.
+func similarIssuesText(issues []github.Issue) string {
+	lines := make([]string, 0, len(issues))
+	for _, existing := range issues {
+		if existing.Draft {
+			lines = append(lines, fmt.Sprintf("* draft: %s", existing.Title))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("* #%d: %s\n  %s", existing.Number, existing.Title, existing.URL))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// showSimilarIssuesPage lists the similar issues of the test, Esc returns to
+// the main page.
+func showSimilarIssuesPage(currentTest *v1alpha1.TestResult) {
+	if len(similarIssues) == 0 {
+		position.SetText("[yellow]No similar issue found")
+		return
+	}
+	list := tview.NewTextView().SetDynamicColors(false).SetWrap(true).SetText(similarIssuesText(similarIssues))
+	setPanelDefaultStyle(list.Box)
+	list.SetTitle(formatTitle(tview.Escape("Similar issues: " + currentTest.TestName)))
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			pages.RemovePage(similarIssuesPageName)
+			pages.SwitchToPage(pagesName)
+			app.SetFocus(githubPanel)
+			position.SetText(defaultPositionText)
+			return nil
+		}
+		return event
+	})
+	position.SetText("[green]Press [blue]Esc [green]to return")
+	pages.AddAndSwitchToPage(similarIssuesPageName, list, true)
+	app.SetFocus(list)
+}
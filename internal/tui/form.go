@@ -0,0 +1,165 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/rivo/tview"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/issue"
+	"sigs.k8s.io/signalhound/internal/sig"
+)
+
+const (
+	issueFormPageName = "Issue form"
+
+	// draftDestination is the repository option of the issue form drafting
+	// the issue in the board.
+	draftDestination = "Draft on the board"
+
+	// noPriority is the priority option of the issue form leaving the
+	// /priority command out.
+	noPriority = "none"
+)
+
+// priorities are the priority options of the issue form.
+var priorities = []string{noPriority, "critical-urgent", "important-soon", "important-longterm", "backlog", "awaiting-more-evidence"}
+
+// issueForm are the fields of the form Ctrl-B opens on the GitHub panel
+// before creating the issue.
+type issueForm struct {
+	Repo     string // Repository of the issue, empty for a draft
+	Labels   []string
+	Commands issue.Commands
+}
+
+// newIssueForm returns the fields of the form of the rendered issue, created
+// in repo, the commands of its body and the SIG of the test when the body
+// has none.
+func newIssueForm(frontMatter *issue.FrontMatter, body, repo, testSig string) issueForm {
+	fields := issueForm{Repo: repo, Commands: issue.ParseCommands(body)}
+	if frontMatter != nil {
+		fields.Labels = frontMatter.Labels
+	}
+	if fields.Commands.Sig == "" {
+		fields.Commands.Sig = testSig
+	}
+	return fields
+}
+
+// apply returns the front matter and the body the issue is created with,
+// the repository issues are labeled with the sig/ label of the SIG.
+func (f issueForm) apply(frontMatter *issue.FrontMatter, body string) (*issue.FrontMatter, string) {
+	applied := issue.FrontMatter{}
+	if frontMatter != nil {
+		applied = *frontMatter
+	}
+	applied.Repo, applied.Labels = f.Repo, f.Labels
+	return applied.WithSIGLabel(f.Commands.Sig), f.Commands.Apply(body)
+}
+
+// knownSIGs returns the SIGs suggested by the issue form, the Kubernetes SIGs
+// and the SIGs of the tests of the tabs.
+func knownSIGs(tabs []*v1alpha1.DashboardTab) []string {
+	sigs := slices.Clone(sig.Names)
+	for _, tab := range tabs {
+		for _, test := range tab.TestRuns {
+			if test.Sig != "" && !slices.Contains(sigs, test.Sig) {
+				sigs = append(sigs, test.Sig)
+			}
+		}
+	}
+	slices.Sort(sigs)
+	return sigs
+}
+
+// matchingSIGs returns the SIGs starting with the typed text, none for an
+// empty text or an exact match so the suggestions close.
+func matchingSIGs(sigs []string, text string) []string {
+	text = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(text)), "sig-")
+	if text == "" || slices.Contains(sigs, text) {
+		return nil
+	}
+	var matching []string
+	for _, name := range sigs {
+		if strings.HasPrefix(name, text) {
+			matching = append(matching, name)
+		}
+	}
+	return matching
+}
+
+// optionIndex returns the index of the value in the options, appended to
+// them when missing.
+func optionIndex(options *[]string, value string) int {
+	if index := slices.Index(*options, value); index >= 0 {
+		return index
+	}
+	*options = append(*options, value)
+	return len(*options) - 1
+}
+
+// showIssueForm opens the form of the repository, labels, SIG, priority and
+// milestone of the issue over the panels, prefilled with fields. Create
+// calls create with the fields picked, Esc or Cancel closes the form.
+func showIssueForm(fields issueForm, sigs []string, create func(issueForm)) {
+	repos := append([]string{draftDestination}, issue.Repositories()...)
+	repoIndex := 0
+	if fields.Repo != "" {
+		repoIndex = optionIndex(&repos, fields.Repo)
+	}
+	priorityOptions := slices.Clone(priorities)
+	priorityIndex := 0
+	if fields.Commands.Priority != "" {
+		priorityIndex = optionIndex(&priorityOptions, fields.Commands.Priority)
+	}
+
+	form := tview.NewForm().
+		AddDropDown("Repository", repos, repoIndex, nil).
+		AddInputField("Labels", strings.Join(fields.Labels, ", "), 0, nil, nil).
+		AddInputField("SIG", fields.Commands.Sig, 0, nil, nil).
+		AddDropDown("Priority", priorityOptions, priorityIndex, nil).
+		AddInputField("Milestone", fields.Commands.Milestone, 0, nil, nil)
+	form.GetFormItemByLabel("SIG").(*tview.InputField).SetAutocompleteFunc(func(text string) []string {
+		return matchingSIGs(sigs, text)
+	})
+	setPanelDefaultStyle(form.Box)
+	form.SetTitle(formatTitle("Create the issue"))
+
+	closeForm := func() {
+		pages.RemovePage(issueFormPageName)
+		app.SetFocus(githubPanel)
+	}
+	form.AddButton("Create", func() {
+		picked := issueForm{}
+		if _, repo := form.GetFormItemByLabel("Repository").(*tview.DropDown).GetCurrentOption(); repo != draftDestination {
+			picked.Repo = repo
+		}
+		for _, label := range strings.Split(form.GetFormItemByLabel("Labels").(*tview.InputField).GetText(), ",") {
+			if label = strings.TrimSpace(label); label != "" {
+				picked.Labels = append(picked.Labels, label)
+			}
+		}
+		picked.Commands.Sig = strings.TrimPrefix(strings.TrimSpace(form.GetFormItemByLabel("SIG").(*tview.InputField).GetText()), "sig-")
+		if _, priority := form.GetFormItemByLabel("Priority").(*tview.DropDown).GetCurrentOption(); priority != noPriority {
+			picked.Commands.Priority = priority
+		}
+		picked.Commands.Milestone = strings.TrimSpace(form.GetFormItemByLabel("Milestone").(*tview.InputField).GetText())
+		closeForm()
+		create(picked)
+	})
+	form.AddButton("Cancel", closeForm)
+	form.SetCancelFunc(closeForm)
+
+	overlay := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(form, 15, 0, true).
+			AddItem(nil, 0, 1, false), 70, 0, true).
+		AddItem(nil, 0, 1, false)
+	position.SetText("[green]Pick the repository, labels, SIG, priority and milestone of the issue, [blue]Tab [green]to move, [blue]Esc [green]to cancel")
+	pages.AddPage(issueFormPageName, overlay, true, true)
+	app.SetFocus(form)
+}
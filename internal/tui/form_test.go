@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/issue"
+)
+
+func TestIssueForm(t *testing.T) {
+	body := "Failing.\n\n/sig \n/kind failing-test\n/milestone v1.35\n"
+	frontMatter := &issue.FrontMatter{Repo: "kubernetes/kubernetes", Labels: []string{"kind/failing-test"}, Assignees: []string{"@lead"}}
+	fields := newIssueForm(frontMatter, body, "kubernetes/kubernetes", "node")
+	assert.Equal(t, issueForm{Repo: "kubernetes/kubernetes", Labels: []string{"kind/failing-test"},
+		Commands: issue.Commands{Sig: "node", Milestone: "v1.35"}}, fields)
+
+	fields.Commands.Priority = "critical-urgent"
+	applied, appliedBody := fields.apply(frontMatter, body)
+	assert.Equal(t, &issue.FrontMatter{Repo: "kubernetes/kubernetes", Labels: []string{"kind/failing-test", "sig/node"},
+		Assignees: []string{"@lead"}}, applied)
+	assert.Equal(t, "Failing.\n\n/sig node\n/kind failing-test\n/priority critical-urgent\n/milestone v1.35\n", appliedBody)
+	assert.Equal(t, []string{"kind/failing-test"}, frontMatter.Labels)
+
+	// a draft has no repository nor labels
+	applied, _ = issueForm{Commands: issue.Commands{Sig: "node"}}.apply(frontMatter, body)
+	assert.Empty(t, applied.Repo)
+	assert.Nil(t, applied.Options())
+	applied, _ = issueForm{}.apply(nil, body)
+	assert.Nil(t, applied.Options())
+
+	sigs := knownSIGs([]*v1alpha1.DashboardTab{{TestRuns: []v1alpha1.TestResult{{Sig: "node"}, {Sig: "gpu"}}}})
+	assert.Contains(t, sigs, "gpu")
+	assert.Equal(t, []string{"scalability", "scheduling", "security", "storage"}, matchingSIGs(sigs, "S"))
+	assert.Equal(t, []string{"network"}, matchingSIGs(sigs, "sig-net"))
+	assert.Empty(t, matchingSIGs(sigs, "node"))
+	assert.Empty(t, matchingSIGs(sigs, ""))
+}
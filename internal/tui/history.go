@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/store"
+)
+
+const (
+	historyPageName = "History"
+
+	// historyWindow is the history shown on Ctrl-R, the one the flake score
+	// is computed on.
+	historyWindow = store.ScoreWindow
+
+	// boardTrendDays is the number of days of the board sparklines.
+	boardTrendDays = 7
+)
+
+var (
+	historyStore    *store.Store          // Store of the recorded test results, nil disables the history page
+	boardSparklines = map[string]string{} // Sparkline of the health of each board over the last week
+	boardAlert      string                // Boards whose latest state change degraded them, shown in the tabs panel title
+)
+
+// SetHistoryStore sets the store of the recorded test results.
+func SetHistoryStore(s *store.Store) {
+	historyStore = s
+}
+
+// showHistoryPage reads in background the recorded history of the test and
+// shows its flake rate over the history window, Esc returns.
+func showHistoryPage(currentTest *v1alpha1.TestResult) {
+	if historyStore == nil {
+		position.SetText("[red]error: the history is disabled, set --history")
+		return
+	}
+	stopLoading := startLoading("Reading the history")
+	go func() {
+		defer recoverCrash()
+		history, err := historyStore.History(currentTest.TestName, time.Now().Add(-historyWindow))
+		app.QueueUpdateDraw(func() {
+			stopLoading()
+			if err != nil {
+				position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
+				return
+			}
+			view := tview.NewTextView().SetDynamicColors(false).SetWrap(true).SetText(history.String())
+			setPanelDefaultStyle(view.Box)
+			view.SetTitle(formatTitle(fmt.Sprintf("History - last %d days", int(historyWindow.Hours()/24))))
+			view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+				if event.Key() == tcell.KeyEscape {
+					pages.RemovePage(historyPageName)
+					pages.SwitchToPage(pagesName)
+					app.SetFocus(githubPanel)
+					position.SetText(defaultPositionText)
+					return nil
+				}
+				return event
+			})
+			position.SetText("[green]Press [blue]Esc [green]to return")
+			pages.AddAndSwitchToPage(historyPageName, view, true)
+			app.SetFocus(view)
+		})
+	}()
+}
+
+// loadBoardTrends reads in background the health of the boards over the last
+// week, shows their sparkline before their tabs and flags the degraded boards
+// in the tabs panel title. The boards are left as is on errors.
+func loadBoardTrends() {
+	if historyStore == nil {
+		return
+	}
+	go func() {
+		defer recoverCrash()
+		trends, err := historyStore.BoardTrends(time.Now(), boardTrendDays)
+		if err != nil {
+			return
+		}
+		app.QueueUpdateDraw(func() {
+			for _, board := range trends.Boards {
+				boardSparklines[board.Board] = board.Sparkline()
+			}
+			boardAlert = degradedBoards(trends)
+			tabsPanel.SetTitle(boardsTitle())
+			updateTabsPanel(currentTabs)
+		})
+	}()
+}
+
+// degradedBoards returns the boards whose latest transition of the trends is
+// a degradation, e.g. "sig-release-master-blocking degraded from flaky to
+// failing", empty when none is.
+func degradedBoards(trends *store.BoardTrends) string {
+	var degraded []string
+	for _, board := range trends.Boards {
+		if len(board.Transitions) == 0 {
+			continue
+		}
+		if latest := board.Transitions[len(board.Transitions)-1]; latest.Degraded() {
+			degraded = append(degraded, latest.String())
+		}
+	}
+	return strings.Join(degraded, ", ")
+}
@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/infra"
+	"sigs.k8s.io/signalhound/internal/prow"
+)
+
+const infraPageName = "Infrastructure"
+
+// showInfraPage fetches in background the infrastructure of the latest red
+// and green runs of the test and lists it per red run, with the zones, node
+// images and instance types the failures cluster on.
+func showInfraPage(currentTest *v1alpha1.TestResult) {
+	if len(currentTest.FailedRunURLs) == 0 {
+		position.SetText("[red]error: no red run URL for this test")
+		return
+	}
+	stopLoading := startLoading("Fetching the metadata of the runs")
+	go func() {
+		defer recoverCrash()
+		failed, err := infra.Fetch(currentTest.FailedRunURLs)
+		passed, _ := infra.Fetch(currentTest.PassedRunURLs)
+		app.QueueUpdateDraw(func() {
+			stopLoading()
+			if len(failed) == 0 {
+				if err == nil {
+					position.SetText("[yellow]No run metadata found in the job artifacts")
+				} else {
+					position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
+				}
+				return
+			}
+			renderInfraPage(currentTest, failed, infra.Correlate(failed, passed), len(passed))
+		})
+	}()
+}
+
+// infraText returns the clusters of the failures and the infrastructure of
+// each red run.
+func infraText(failed []*prow.Infrastructure, clusters []infra.Cluster, passedRuns int) string {
+	var text strings.Builder
+	switch {
+	case len(clusters) > 0:
+		text.WriteString("Failures cluster on:\n")
+		for _, cluster := range clusters {
+			fmt.Fprintf(&text, "* %s\n", cluster)
+		}
+	case passedRuns == 0:
+		text.WriteString("No green run to compare the red runs with.\n")
+	default:
+		text.WriteString("Failures don't cluster on a zone, node image or instance type.\n")
+	}
+	text.WriteString("\nRed runs:\n")
+	for _, run := range failed {
+		fmt.Fprintf(&text, "* %s\n  zone %s, image %s, instance type %s\n", run.URL, orUnknown(run.Zone), orUnknown(run.Image), orUnknown(run.InstanceType))
+	}
+	return text.String()
+}
+
+func orUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
+
+// renderInfraPage shows the infrastructure page, Esc returns to the main page.
+func renderInfraPage(currentTest *v1alpha1.TestResult, failed []*prow.Infrastructure, clusters []infra.Cluster, passedRuns int) {
+	view := tview.NewTextView().SetDynamicColors(false).SetWrap(true).SetText(infraText(failed, clusters, passedRuns))
+	setPanelDefaultStyle(view.Box)
+	view.SetTitle(formatTitle(tview.Escape("Infrastructure: " + currentTest.TestName)))
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			pages.RemovePage(infraPageName)
+			pages.SwitchToPage(pagesName)
+			app.SetFocus(githubPanel)
+			position.SetText(defaultPositionText)
+			return nil
+		}
+		return event
+	})
+	if len(clusters) > 0 {
+		position.SetText(fmt.Sprintf("[red]Failures cluster on %s, [green]press [blue]Esc [green]to return", tview.Escape(clusters[0].String())))
+	} else {
+		position.SetText("[green]Press [blue]Esc [green]to return")
+	}
+	pages.AddAndSwitchToPage(infraPageName, view, true)
+	app.SetFocus(view)
+}
@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+var (
+	lastTabsGPress  time.Time // Track "gg" go-to-top shortcut in Board#Tabs panel
+	lastTestsGPress time.Time // Track "gg" go-to-top shortcut in Tests panel
+)
+
+// vimListCapture returns the input capture adding vim navigation to a list:
+// j/k move, h goes back, l selects, gg/G jump to the ends and Ctrl-d/Ctrl-u
+// move half a page. Arrow keys keep working as before.
+func vimListCapture(list *tview.List, lastGPress *time.Time) func(event *tcell.EventKey) *tcell.EventKey {
+	return func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyCtrlD:
+			moveListHalfPage(list, 1)
+			return nil
+		case tcell.KeyCtrlU:
+			moveListHalfPage(list, -1)
+			return nil
+		case tcell.KeyRune:
+		default:
+			return event
+		}
+		switch event.Rune() {
+		case 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, event.Modifiers())
+		case 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, event.Modifiers())
+		case 'h':
+			return tcell.NewEventKey(tcell.KeyEscape, 0, event.Modifiers())
+		case 'l':
+			return tcell.NewEventKey(tcell.KeyEnter, 0, event.Modifiers())
+		case 'G':
+			return tcell.NewEventKey(tcell.KeyEnd, 0, event.Modifiers())
+		case 'g':
+			if isGoTopShortcut(event, lastGPress) {
+				return tcell.NewEventKey(tcell.KeyHome, 0, event.Modifiers())
+			}
+			return nil
+		}
+		return event
+	}
+}
+
+// vimTextAreaKey translates the vim navigation keys of a read-only text area:
+// j/k/h/l move the cursor, gg/G jump to the ends and Ctrl-d/Ctrl-u scroll half
+// a page. It returns the translated event and true when the key was handled.
+func vimTextAreaKey(area *tview.TextArea, event *tcell.EventKey, lastGPress *time.Time) (*tcell.EventKey, bool) {
+	switch event.Key() {
+	case tcell.KeyCtrlD:
+		scrollTextAreaHalfPage(area, tcell.KeyDown)
+		return nil, true
+	case tcell.KeyCtrlU:
+		scrollTextAreaHalfPage(area, tcell.KeyUp)
+		return nil, true
+	case tcell.KeyRune:
+	default:
+		return event, false
+	}
+	switch event.Rune() {
+	case 'j':
+		return tcell.NewEventKey(tcell.KeyDown, 0, event.Modifiers()), true
+	case 'k':
+		return tcell.NewEventKey(tcell.KeyUp, 0, event.Modifiers()), true
+	case 'h':
+		return tcell.NewEventKey(tcell.KeyLeft, 0, event.Modifiers()), true
+	case 'l':
+		return tcell.NewEventKey(tcell.KeyRight, 0, event.Modifiers()), true
+	case 'G':
+		moveTextAreaToBottom(area)
+		return nil, true
+	case 'g':
+		if isGoTopShortcut(event, lastGPress) {
+			moveTextAreaToTop(area)
+		}
+		return nil, true
+	}
+	return event, false
+}
+
+// vimTextViewKey adds Ctrl-d/Ctrl-u half page scrolling to a text view, the
+// other vim keys are handled by the text view itself.
+func vimTextViewKey(view *tview.TextView, event *tcell.EventKey) bool {
+	_, _, _, height := view.GetInnerRect()
+	row, column := view.GetScrollOffset()
+	switch event.Key() {
+	case tcell.KeyCtrlD:
+		view.ScrollTo(row+max(1, height/2), column)
+		return true
+	case tcell.KeyCtrlU:
+		view.ScrollTo(max(0, row-max(1, height/2)), column)
+		return true
+	}
+	return false
+}
+
+func moveListHalfPage(list *tview.List, direction int) {
+	_, _, _, height := list.GetInnerRect()
+	item := list.GetCurrentItem() + direction*max(1, height/2)
+	list.SetCurrentItem(min(max(0, item), list.GetItemCount()-1))
+}
+
+func scrollTextAreaHalfPage(area *tview.TextArea, key tcell.Key) {
+	_, _, _, height := area.GetInnerRect()
+	handler := area.InputHandler()
+	for i := 0; i < max(1, height/2); i++ {
+		handler(tcell.NewEventKey(key, 0, tcell.ModNone), func(tview.Primitive) {})
+	}
+}
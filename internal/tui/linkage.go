@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/state"
+)
+
+var (
+	boardLinks   map[string]github.Issue // Open and draft issues of the project board by test, see loadIssueLinks
+	loadingLinks bool                    // The board issues are being listed
+)
+
+// linkedIssueText returns the marker of the tests list showing the issue
+// already tracking the test, linked in the state or found on the board, an
+// empty string when none is.
+func linkedIssueText(testName string, current *state.State) string {
+	if link, ok := current.IssueLink(testName); ok {
+		return fmt.Sprintf(" [green]✔ #%d[-]", link.Number)
+	}
+	linked, ok := boardLinks[testName]
+	switch {
+	case !ok:
+		return ""
+	case linked.Draft:
+		return " [green]✔ draft[-]"
+	}
+	return fmt.Sprintf(" [green]✔ #%d[-]", linked.Number)
+}
+
+// linkCreatedIssue marks the test as tracked by the issue Ctrl-B created, a
+// draft when filed is nil, until the board issues are listed again. Nothing
+// is created in a dry run.
+func linkCreatedIssue(testName string, filed *github.Issue) {
+	if github.DryRun {
+		return
+	}
+	linked := github.Issue{Draft: true}
+	if filed != nil {
+		linked = *filed
+	}
+	if boardLinks == nil {
+		boardLinks = map[string]github.Issue{}
+	}
+	boardLinks[testName] = linked
+}
+
+// loadIssueLinks lists in background the open and draft issues of the
+// project board and marks the tests they track in the tests list. The
+// marks are left as is on errors, e.g. offline or without token.
+func loadIssueLinks() {
+	if githubToken == "" || loadingLinks {
+		return
+	}
+	loadingLinks = true
+	go func() {
+		defer recoverCrash()
+		issues, err := github.NewProjectManager(context.Background(), githubToken).ListBoardIssues()
+		app.QueueUpdateDraw(func() {
+			loadingLinks = false
+			if err != nil {
+				return
+			}
+			boardLinks = github.IssuesByTest(issues)
+			refreshTestsList()
+		})
+	}()
+}
+
+// refreshTestsList renders the tests list of the current tab again, keeping
+// the selected test.
+func refreshTestsList() {
+	if currentTab == nil {
+		return
+	}
+	selected := brokenPanel.GetCurrentItem()
+	renderTestsList(currentTab)
+	brokenPanel.SetCurrentItem(selected)
+}
@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/state"
+)
+
+func TestIssueLinkage(t *testing.T) {
+	defer func() { boardLinks = nil }()
+	boardLinks = github.IssuesByTest([]github.BoardIssue{
+		{Issue: github.Issue{Number: 12, Title: "Pods are failing", TestName: "[sig-node] Pods should run"}},
+		{Issue: github.Issue{Title: "[Flaking Test] [sig-network] DNS should resolve", Draft: true}},
+		{Issue: github.Issue{Number: 13, Title: "[Failing Test] [sig-node] Pods should run"}},
+		{Issue: github.Issue{Number: 14, Title: "Bump the etcd version"}},
+	})
+	assert.Len(t, boardLinks, 2)
+	assert.Equal(t, 12, boardLinks["[sig-node] Pods should run"].Number, "the first issue of the board wins")
+
+	current := &state.State{}
+	_, err := current.LinkIssue("[sig-apps] Deployment should roll", "https://github.com/kubernetes/kubernetes/issues/99", "", "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "[sig-node[] Pods should run [green]✔ #12[-]", testItemText(v1alpha1.TestResult{TestName: "[sig-node] Pods should run"}, current))
+	assert.Equal(t, "[sig-network[] DNS should resolve [green]✔ draft[-]",
+		testItemText(v1alpha1.TestResult{TestName: "[sig-network] DNS should resolve"}, current))
+	assert.Equal(t, "[sig-apps[] Deployment should roll [green]✔ #99[-]",
+		testItemText(v1alpha1.TestResult{TestName: "[sig-apps] Deployment should roll"}, current))
+	assert.Equal(t, "[sig-storage[] CSI should mount", testItemText(v1alpha1.TestResult{TestName: "[sig-storage] CSI should mount"}, current))
+
+	linkCreatedIssue("[sig-storage] CSI should mount", &github.Issue{Number: 15})
+	assert.Equal(t, "[sig-storage[] CSI should mount [green]✔ #15[-]", testItemText(v1alpha1.TestResult{TestName: "[sig-storage] CSI should mount"}, current))
+}
@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+)
+
+const spinnerInterval = 100 * time.Millisecond
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// startLoading animates a spinner with the message in the position bar while
+// a background task runs, the returned stop function must be called from the
+// UI goroutine, e.g. inside QueueUpdateDraw, before showing the task result.
+func startLoading(message string) (stop func()) {
+	done := make(chan struct{})
+	stopped := false // only accessed from the UI goroutine
+	position.SetText(fmt.Sprintf("[yellow]%s %s...", spinnerFrames[0], message))
+
+	go func() {
+		defer recoverCrash()
+		ticker := time.NewTicker(spinnerInterval)
+		defer ticker.Stop()
+		for frame := 1; ; frame++ {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				text := fmt.Sprintf("[yellow]%s %s...", spinnerFrames[frame%len(spinnerFrames)], message)
+				app.QueueUpdateDraw(func() {
+					if !stopped {
+						position.SetText(text)
+					}
+				})
+			}
+		}
+	}()
+
+	return func() {
+		if !stopped {
+			stopped = true
+			close(done)
+		}
+	}
+}
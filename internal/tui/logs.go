@@ -0,0 +1,191 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/issue"
+	"sigs.k8s.io/signalhound/internal/prow"
+)
+
+const (
+	logsPageName = "Logs"
+	logsHeader   = "### Anything else we need to know?\n"
+)
+
+var (
+	logSections []*logSection // Separated log streams of the last inspected job
+	logsProwURL string        // Prow job URL the log sections were fetched from
+	// Logs block rendered in the GitHub panel, replaced on the issue body when
+	// the included streams change so the user edits are kept.
+	githubLogsBlock string
+)
+
+// logSection is a single log stream rendered in the logs page.
+type logSection struct {
+	stream   prow.LogStream
+	included bool
+	view     *tview.TextView
+}
+
+// includedLogs returns the log sections marked for inclusion for the given test.
+func includedLogs(currentTest *v1alpha1.TestResult) (logs []issue.Log) {
+	if currentTest.ProwJobURL == "" || currentTest.ProwJobURL != logsProwURL {
+		return nil
+	}
+	for _, section := range logSections {
+		if section.included {
+			logs = append(logs, issue.Log{
+				Name:    section.stream.Name,
+				URL:     section.stream.URL,
+				Content: section.stream.Content,
+			})
+		}
+	}
+	return logs
+}
+
+// showLogsPage fetches the separated stdout/stderr and pod log streams of the
+// test job in background and renders them in their own page.
+func showLogsPage(currentTest *v1alpha1.TestResult) {
+	if currentTest.ProwJobURL == "" {
+		position.SetText("[red]error: no prow job URL for this test")
+		return
+	}
+	if currentTest.ProwJobURL == logsProwURL && len(logSections) > 0 {
+		renderLogsPage(currentTest)
+		return
+	}
+
+	position.SetText("[yellow]Fetching log streams from the job artifacts...")
+	go func() {
+		defer recoverCrash()
+		streams, err := prow.NewProw(currentTest.ProwJobURL).GetLogStreams()
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
+				return
+			}
+			if len(streams) == 0 {
+				position.SetText("[yellow]No separated log streams found in the job artifacts")
+				return
+			}
+			logsProwURL = currentTest.ProwJobURL
+			logSections = make([]*logSection, 0, len(streams))
+			for _, stream := range streams {
+				logSections = append(logSections, &logSection{stream: stream})
+			}
+			renderLogsPage(currentTest)
+		})
+	}()
+}
+
+// renderLogsPage lays out each log stream as a scrollable section, Tab moves
+// across sections, Space toggles the inclusion in the issue body and Esc
+// returns to the main page with the issue logs updated.
+func renderLogsPage(currentTest *v1alpha1.TestResult) {
+	flex := tview.NewFlex().SetDirection(tview.FlexRow)
+	for i, section := range logSections {
+		section.view = tview.NewTextView().SetScrollable(true).SetWrap(true)
+		if section.stream.Err != nil {
+			section.view.SetText(fmt.Sprintf("[red]error: %v", tview.Escape(section.stream.Err.Error())))
+		} else {
+			section.view.SetText(tview.Escape(section.stream.Content))
+		}
+		section.view.ScrollToEnd()
+		setPanelDefaultStyle(section.view.Box)
+		setLogSectionTitle(section)
+
+		index := i
+		section.view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			if vimTextViewKey(section.view, event) {
+				return nil
+			}
+			switch event.Key() {
+			case tcell.KeyTab:
+				focusLogSection(index + 1)
+				return nil
+			case tcell.KeyBacktab:
+				focusLogSection(index - 1)
+				return nil
+			case tcell.KeyEscape:
+				closeLogsPage(currentTest)
+				return nil
+			case tcell.KeyRune:
+				if event.Rune() == ' ' && logSections[index].stream.Err == nil {
+					logSections[index].included = !logSections[index].included
+					setLogSectionTitle(logSections[index])
+					return nil
+				}
+			}
+			return event
+		})
+		flex.AddItem(section.view, 0, 1, i == 0)
+	}
+
+	position.SetText("[green]Press [blue]Tab [green]to switch streams, [blue]Space [green]to include in the issue, [blue]Esc [green]to return")
+	pages.AddAndSwitchToPage(logsPageName, flex, true)
+	focusLogSection(0)
+}
+
+func setLogSectionTitle(section *logSection) {
+	mark := " "
+	if section.included {
+		mark = "x"
+	}
+	section.view.SetTitle(formatTitle(tview.Escape(fmt.Sprintf("[%s] %s: %s", mark, section.stream.Kind, section.stream.Name))))
+}
+
+func focusLogSection(index int) {
+	if len(logSections) == 0 {
+		return
+	}
+	index = (index + len(logSections)) % len(logSections)
+	for i, section := range logSections {
+		if i == index {
+			section.view.SetBorderColor(tcell.ColorBlue)
+		} else {
+			section.view.SetBorderStyle(defaultBorderStyle())
+		}
+	}
+	app.SetFocus(logSections[index].view)
+}
+
+// closeLogsPage returns to the main page and swaps the logs block of the
+// issue body with the included streams, keeping the rest of the user edits.
+func closeLogsPage(currentTest *v1alpha1.TestResult) {
+	pages.RemovePage(logsPageName)
+	pages.SwitchToPage(pagesName)
+	app.SetFocus(githubPanel)
+	position.SetText(defaultPositionText)
+
+	logsBlock, err := issue.RenderLogs(includedLogs(currentTest))
+	if err != nil {
+		position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
+		return
+	}
+	if !replaceIssueBlock(logsHeader, &githubLogsBlock, logsBlock) {
+		position.SetText("[yellow]Logs section was edited, the issue body is kept unchanged")
+	}
+}
+
+// replaceIssueBlock swaps the rendered block following the section header of
+// the issue body with the new content, keeping the rest of the body. It
+// returns false when the block is no longer found under the header.
+func replaceIssueBlock(header string, block *string, content string) bool {
+	issueBody := githubPanel.GetText()
+	start := strings.Index(issueBody, header)
+	if start < 0 {
+		return false
+	}
+	start += len(header)
+	if !strings.HasPrefix(issueBody[start:], *block) {
+		return false
+	}
+	githubPanel.SetText(issueBody[:start]+content+issueBody[start+len(*block):], false)
+	*block = content
+	return true
+}
@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/issue"
+	"sigs.k8s.io/signalhound/internal/state"
+)
+
+const notesPageName = "Notes"
+
+var (
+	stateStore state.Store // Store of the human annotations, nil disables them
+	// Notes block rendered in the GitHub panel after the logs block, replaced
+	// when a note is added so the user edits are kept.
+	githubNotesBlock string
+)
+
+// SetStateStore sets the store keeping the test annotations.
+func SetStateStore(store state.Store) {
+	stateStore = store
+}
+
+// loadNotes returns the rendered annotations of the test, safe to call off
+// the UI goroutine.
+func loadNotes(testName string) ([]string, error) {
+	if stateStore == nil {
+		return nil, nil
+	}
+	current, err := stateStore.Load()
+	if err != nil {
+		return nil, err
+	}
+	return current.Notes(testName), nil
+}
+
+// testNotes returns the rendered annotations of the test, reporting the
+// errors on the position bar.
+func testNotes(testName string) []string {
+	notes, err := loadNotes(testName)
+	if err != nil {
+		position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
+	}
+	return notes
+}
+
+// annotationAuthor returns the local user name used as the note author.
+func annotationAuthor() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return current.Username
+	}
+	return os.Getenv("USER")
+}
+
+// showNotesPage lists the annotations of the test and reads a new one, Enter
+// saves it and Esc returns to the main page with the issue notes updated.
+func showNotesPage(currentTest *v1alpha1.TestResult) {
+	if stateStore == nil {
+		position.SetText("[red]error: no state store configured")
+		return
+	}
+
+	list := tview.NewTextView().SetDynamicColors(false).SetWrap(true)
+	setPanelDefaultStyle(list.Box)
+	list.SetTitle(formatTitle(tview.Escape("Notes: " + currentTest.TestName)))
+	renderNotesList := func() {
+		notes := testNotes(currentTest.TestName)
+		if len(notes) == 0 {
+			list.SetText("No notes yet.")
+			return
+		}
+		list.SetText("* " + strings.Join(notes, "\n* "))
+	}
+	renderNotesList()
+
+	input := tview.NewInputField().SetLabel("New note: ")
+	setPanelDefaultStyle(input.Box)
+	input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			text := strings.TrimSpace(input.GetText())
+			if text == "" {
+				return
+			}
+			if _, err := state.Update(stateStore, func(current *state.State) {
+				current.Annotate(currentTest.TestName, text, annotationAuthor())
+			}); err != nil {
+				position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
+				return
+			}
+			recordAction(actionAnnotated)
+			input.SetText("")
+			renderNotesList()
+		case tcell.KeyEscape:
+			closeNotesPage(currentTest)
+		}
+	})
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(list, 0, 1, false).
+		AddItem(input, 3, 0, true)
+	position.SetText("[green]Type a note and press [blue]Enter [green]to save it, [blue]Esc [green]to return")
+	pages.AddAndSwitchToPage(notesPageName, flex, true)
+	app.SetFocus(input)
+}
+
+// closeNotesPage returns to the main page and swaps the notes block of the
+// issue body, found right after the logs block.
+func closeNotesPage(currentTest *v1alpha1.TestResult) {
+	pages.RemovePage(notesPageName)
+	pages.SwitchToPage(pagesName)
+	app.SetFocus(githubPanel)
+	position.SetText(defaultPositionText)
+
+	notesBlock, err := issue.RenderNotes(testNotes(currentTest.TestName))
+	if err != nil {
+		position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
+		return
+	}
+	if !replaceIssueBlock(logsHeader+githubLogsBlock, &githubNotesBlock, notesBlock) {
+		position.SetText("[yellow]Notes section was edited, the issue body is kept unchanged")
+	}
+}
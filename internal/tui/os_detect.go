@@ -21,3 +21,15 @@ func isWSL() bool {
 	_, exists := os.LookupEnv("WSL_DISTRO_NAME")
 	return exists
 }
+
+// Helper function to detect SSH sessions
+// The clipboard commands of a remote host can't reach the clipboard of the
+// user, OSC 52 is used instead.
+func isSSH() bool {
+	return os.Getenv("SSH_CONNECTION") != "" || os.Getenv("SSH_CLIENT") != "" || os.Getenv("SSH_TTY") != ""
+}
+
+// Helper function to detect X11
+func isX11() bool {
+	return os.Getenv("DISPLAY") != ""
+}
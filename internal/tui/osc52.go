@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// osc52Tool is the ClipboardTool copying with the OSC 52 terminal escape
+	// sequence, the terminal of the user sets its own clipboard even when
+	// signalhound runs on a remote host.
+	osc52Tool = "osc52"
+
+	// maxOSC52Bytes keeps the encoded sequence within the 100000 bytes most
+	// terminals accept.
+	maxOSC52Bytes = 74994
+)
+
+// osc52Sequence returns the OSC 52 sequence setting the clipboard to text.
+// Under tmux and screen it is wrapped in a DCS passthrough, so the
+// multiplexer forwards it to the outer terminal.
+func osc52Sequence(text string) string {
+	sequence := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	switch {
+	case os.Getenv("TMUX") != "":
+		return "\x1bPtmux;" + strings.ReplaceAll(sequence, "\x1b", "\x1b\x1b") + "\x1b\\"
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		return "\x1bP" + sequence + "\x1b\\"
+	}
+	return sequence
+}
+
+// copyOSC52 writes the OSC 52 sequence of text to the controlling terminal.
+func copyOSC52(text string) error {
+	if len(text) > maxOSC52Bytes {
+		return fmt.Errorf("%d bytes are too large to copy with OSC 52, the maximum is %d", len(text), maxOSC52Bytes)
+	}
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("error opening the terminal: %v", err)
+	}
+	defer tty.Close() // nolint
+	_, err = tty.WriteString(osc52Sequence(text))
+	return err
+}
@@ -2,7 +2,10 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
 	"runtime"
 	"strings"
@@ -10,10 +13,13 @@ import (
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
 	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/culprit"
 	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/issue"
+	"sigs.k8s.io/signalhound/internal/links"
+	"sigs.k8s.io/signalhound/internal/release"
+	"sigs.k8s.io/signalhound/internal/state"
 )
 
 const (
@@ -31,6 +37,7 @@ var (
 	githubPanel       = tview.NewTextArea()
 	position          = tview.NewTextView()
 	currentTabs       []*v1alpha1.DashboardTab // Store current tabs for refresh
+	currentTab        *v1alpha1.DashboardTab   // Tab listed in the tests panel
 	githubToken       string                   // Store token for refresh
 	selectedBoardHash string                   // Store selected BoardHash for refresh preservation
 	selectedTestName  string                   // Store selected test name for refresh preservation
@@ -38,8 +45,83 @@ var (
 	lastGitHubYPress  time.Time                // Track "yy" clipboard shortcut in GitHub panel
 	lastSlackGPress   time.Time                // Track "gg" go-to-top shortcut in Slack panel
 	lastGitHubGPress  time.Time                // Track "gg" go-to-top shortcut in GitHub panel
+	releasePhase      *release.Phase           // Current release cycle phase, nil when unknown
+	reopenWindow      time.Duration            // Reopen issues closed within the window on Ctrl-B, 0 to disable
+	githubRenderID    int                      // Generation of the GitHub panel rendering, drops stale results
+	githubRendered    bool                     // The GitHub panel holds the rendered issue of the selected test
+	creatingIssue     bool                     // An issue creation is running in background
+	slackMentions     *issue.SlackMentions     // Mentions appended to the Slack messages of failing tests
+	repositoryIssues  bool                     // Ctrl-B creates issues in the repository of the test instead of drafts
+	linkChecker       *links.Checker           // Checks the links of the issues and Slack messages, nil disables it
+	readOnly          bool                     // Ctrl-B doesn't create issues nor post to Slack
 )
 
+// errReadOnly is returned when creating an issue or posting to Slack with a
+// read-only configuration.
+var errReadOnly = errors.New("read-only profile, the issue and the Slack message can only be copied")
+
+// SetReleasePhase sets the release cycle phase used to flag freeze periods
+// on panels, Slack messages and issue priorities.
+func SetReleasePhase(phase *release.Phase) {
+	releasePhase = phase
+}
+
+// SetSlackMentions sets the @-mentions appended to the Slack messages of
+// failing tests.
+func SetSlackMentions(mentions *issue.SlackMentions) {
+	slackMentions = mentions
+}
+
+// SetReopenWindow sets the window in which a closed issue of a regressed test
+// is reopened instead of creating a new draft issue.
+func SetReopenWindow(window time.Duration) {
+	reopenWindow = window
+}
+
+// SetRepositoryIssues sets whether Ctrl-B starts creating the issues in the
+// repository of the test instead of drafting them, toggled with Ctrl-T.
+func SetRepositoryIssues(enabled bool) {
+	repositoryIssues = enabled
+}
+
+// SetReadOnly sets whether the issue creation and the Slack posting are
+// disabled, leaving the copies.
+func SetReadOnly(enabled bool) {
+	readOnly = enabled
+}
+
+// SetLinkChecker sets the checker replacing the dead links of the issues and
+// the Slack messages, nil includes the links unchecked.
+func SetLinkChecker(checker *links.Checker) {
+	linkChecker = checker
+}
+
+// githubTitle returns the GitHub panel title with the Ctrl-B destination of
+// the issue of the test.
+func githubTitle(tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestResult) string {
+	title := "GitHub Issue - draft"
+	if repositoryIssues {
+		title = fmt.Sprintf("GitHub Issue - [yellow]%s[-]", issue.Repository(tab.BoardHash, currentTest.TestName))
+	}
+	if len(similarIssues) > 0 {
+		title += fmt.Sprintf(" - [red]%d similar[-]", len(similarIssues))
+	}
+	return formatTitle(title + editsTitle())
+}
+
+// boardsTitle returns the tabs panel title, flagging freeze periods and the
+// degraded boards.
+func boardsTitle() string {
+	title := "Board#Tabs"
+	if releasePhase.InFreeze() {
+		title += fmt.Sprintf(" - [red]%s[-]", releasePhase)
+	}
+	if boardAlert != "" {
+		title += fmt.Sprintf(" - [red]%s[-]", boardAlert)
+	}
+	return formatTitle(title)
+}
+
 func isDoubleRuneShortcut(event *tcell.EventKey, lastPress *time.Time, runes ...rune) bool {
 	if event.Key() != tcell.KeyRune {
 		*lastPress = time.Time{}
@@ -112,16 +194,21 @@ func moveTextAreaToBottom(area *tview.TextArea) {
 	}
 }
 
+// closeDetailPanels clears the Slack and GitHub panels and returns to the
+// tests list, once the edits of the issue are discarded.
 func closeDetailPanels() {
-	slackPanel.SetText("", false)
-	githubPanel.SetText("", false)
-	app.SetFocus(brokenPanel)
+	confirmDiscardEdits(func() {
+		slackPanel.SetText("", false)
+		githubPanel.SetText("", false)
+		app.SetFocus(brokenPanel)
+	})
 }
 
 func flashPanelCopyState(panel *tview.TextArea) {
 	setPanelFocusStyle(panel.Box)
 	panel.SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite))
 	go func() {
+		defer recoverCrash()
 		time.Sleep(1 * time.Second)
 		app.QueueUpdateDraw(func() {
 			app.SetFocus(brokenPanel)
@@ -192,6 +279,10 @@ func updateTabsPanel(tabs []*v1alpha1.DashboardTab) {
 			icon = "🔴"
 		}
 		tabText := fmt.Sprintf("[%s] %s", icon, strings.ReplaceAll(tab.BoardHash, "#", " - "))
+		board, _, _ := strings.Cut(tab.BoardHash, "#")
+		if sparkline := boardSparklines[board]; sparkline != "" {
+			tabText = fmt.Sprintf("[%s] %s %s", icon, sparkline, strings.ReplaceAll(tab.BoardHash, "#", " - "))
+		}
 
 		// Create selection callback for this tab
 		tabCallback := func(tab *v1alpha1.DashboardTab) func() {
@@ -200,28 +291,9 @@ func updateTabsPanel(tabs []*v1alpha1.DashboardTab) {
 				selectedBoardHash = tab.BoardHash
 				selectedTestName = "" // Clear test selection when tab changes
 
-				brokenPanel.Clear()
-				for _, test := range tab.TestRuns {
-					brokenPanel.AddItem(tview.Escape(test.TestName), "", 0, nil)
-				}
+				renderTestsList(tab)
 				app.SetFocus(brokenPanel)
 				brokenPanel.SetCurrentItem(0)
-				brokenPanel.SetChangedFunc(func(i int, testName string, secondaryText string, shortcut rune) {
-					position.SetText(defaultPositionText)
-					// Store the selected test name when user navigates tests
-					if i >= 0 && i < brokenPanel.GetItemCount() {
-						_, selectedTestName = brokenPanel.GetItemText(i)
-					}
-				})
-				// Broken panel rendering the function selection
-				brokenPanel.SetSelectedFunc(func(i int, testName string, secondaryText string, shortcut rune) {
-					// Store the selected test name
-					selectedTestName = testName
-					var currentTest = tab.TestRuns[i]
-					updateSlackPanel(tab, &currentTest)
-					updateGitHubPanel(tab, &currentTest, githubToken)
-					app.SetFocus(slackPanel)
-				})
 			}
 		}(tab)
 
@@ -260,12 +332,169 @@ func updateTabsPanel(tabs []*v1alpha1.DashboardTab) {
 	}
 }
 
+// renderTestsList fills the tests panel with the tests of the tab matching
+// the search in the active sort order.
+func renderTestsList(tab *v1alpha1.DashboardTab) {
+	if currentTab == nil || currentTab.BoardHash != tab.BoardHash {
+		markedTests = nil
+	}
+	currentTab = tab
+	tests := filterTests(sortTests(tab.TestRuns, testsSortMode), testsSearch)
+	listedTests = tests
+	current := loadState()
+	brokenPanel.SetTitle(testsTitle())
+	brokenPanel.Clear()
+	for _, test := range tests {
+		brokenPanel.AddItem(testItemText(test, current), "", 0, nil)
+	}
+	brokenPanel.SetChangedFunc(func(i int, testName string, secondaryText string, shortcut rune) {
+		position.SetText(defaultPositionText)
+		// Store the selected test name when user navigates tests
+		if i >= 0 && i < brokenPanel.GetItemCount() {
+			_, selectedTestName = brokenPanel.GetItemText(i)
+		}
+	})
+	// Broken panel rendering the function selection
+	brokenPanel.SetSelectedFunc(func(i int, testName string, secondaryText string, shortcut rune) {
+		// the issue of the previous test is rendered again, without its edits
+		confirmDiscardEdits(func() {
+			// Store the selected test name
+			selectedTestName = testName
+			var currentTest = tests[i]
+			updateSlackPanel(tab, &currentTest)
+			updateGitHubPanel(tab, &currentTest, githubToken)
+			app.SetFocus(slackPanel)
+		})
+	})
+}
+
+// testItemText returns the tests list entry of a test, dimming the tests
+// the job config no longer runs and the snoozed or acknowledged ones,
+// highlighting the search in the name and flagging the marked tests.
+func testItemText(test v1alpha1.TestResult, current *state.State) string {
+	if markedTests[test.TestName] {
+		return "[blue]●[-] " + unmarkedItemText(test, current)
+	}
+	return unmarkedItemText(test, current)
+}
+
+// unmarkedItemText returns the tests list entry of a test without its mark.
+func unmarkedItemText(test v1alpha1.TestResult, current *state.State) string {
+	if test.Skipped {
+		return "[gray]" + highlightSearch(test.TestName, "gray") + " (skipped in job config)[-]" + linkedIssueText(test.TestName, current)
+	}
+	if suppression, ok := current.Suppressed(test.TestName, time.Now()); ok {
+		return "[gray]" + highlightSearch(test.TestName, "gray") + tview.Escape(" ("+suppression.String()+")") + "[-]" +
+			linkedIssueText(test.TestName, current)
+	}
+	return highlightSearch(test.TestName, "-") + linkedIssueText(test.TestName, current)
+}
+
+// cycleTestsSort switches the tests panel to the next sort mode, keeping the
+// selected test.
+func cycleTestsSort() {
+	testsSortMode = testsSortMode.next()
+	if currentTab == nil {
+		brokenPanel.SetTitle(testsTitle())
+		return
+	}
+	var selected string
+	if brokenPanel.GetItemCount() > 0 {
+		selected, _ = brokenPanel.GetItemText(brokenPanel.GetCurrentItem())
+	}
+	renderTestsList(currentTab)
+	for i := 0; i < brokenPanel.GetItemCount(); i++ {
+		if testName, _ := brokenPanel.GetItemText(i); testName == selected {
+			brokenPanel.SetCurrentItem(i)
+			break
+		}
+	}
+}
+
+// renderedIssue is the issue body of a test with its replaceable blocks.
+type renderedIssue struct {
+	body            string
+	logsBlock       string
+	culpritsBlock   string
+	detailsBlock    string
+	notesBlock      string
+	crossBranchText string
+	variantsText    string
+
+	// links are the links of the test with the dead ones replaced.
+	links     links.Links
+	deadLinks []links.DeadLink
+}
+
+// renderIssue renders the issue body and blocks of the test, it reads the
+// state store and must not touch the UI, so it runs off the UI goroutine.
+func renderIssue(issueTemplate *issue.Template, templateFile string, tabs []*v1alpha1.DashboardTab,
+	tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestResult) (rendered renderedIssue, err error) {
+	if issueTemplate.Notes, err = loadNotes(currentTest.TestName); err != nil {
+		return rendered, err
+	}
+	issueTemplate.CrossBranch, issueTemplate.CrossBranchHint = crossBranch(tabs, tab, currentTest)
+	issueTemplate.Variants, issueTemplate.VariantsHint = platformVariants(tabs, tab, currentTest)
+	rendered.links = links.For(tab, currentTest)
+	if linkChecker != nil {
+		rendered.links, rendered.deadLinks = linkChecker.Resolve(rendered.links)
+		issueTemplate.TestGridURL, issueTemplate.ProwURL, issueTemplate.TriageURL =
+			rendered.links.TestGrid, rendered.links.Prow, rendered.links.Triage
+	}
+
+	template, err := issue.RenderTemplate(issueTemplate, templateFile)
+	if err != nil {
+		return rendered, err
+	}
+	rendered.body = strings.TrimRight(template.String(), "\r\n")
+	if rendered.logsBlock, err = issue.RenderLogs(issueTemplate.Logs); err != nil {
+		return rendered, err
+	}
+	if rendered.culpritsBlock, err = issue.RenderCulprits(nil); err != nil {
+		return rendered, err
+	}
+	if rendered.detailsBlock, err = issue.RenderDetails(issueTemplate.Details); err != nil {
+		return rendered, err
+	}
+	if rendered.notesBlock, err = issue.RenderNotes(issueTemplate.Notes); err != nil {
+		return rendered, err
+	}
+	rendered.crossBranchText = crossBranchText(issueTemplate.CrossBranch, issueTemplate.CrossBranchHint)
+	rendered.variantsText = variantsText(issueTemplate.Variants, issueTemplate.VariantsHint)
+	return rendered, nil
+}
+
+// deadLinksText joins the dead links found in the links of a test.
+func deadLinksText(dead []links.DeadLink) string {
+	texts := make([]string, 0, len(dead))
+	for _, link := range dead {
+		texts = append(texts, link.String())
+	}
+	return strings.Join(texts, "; ")
+}
+
 // RenderVisual loads the entire grid and componnents in the app.
 // this is a blocking functions.
-func RenderVisual(tabs []*v1alpha1.DashboardTab, token string, refreshInterval time.Duration, refreshFunc func() ([]*v1alpha1.DashboardTab, error)) error {
-	app = tview.NewApplication()
+func RenderVisual(ctx context.Context, tabs []*v1alpha1.DashboardTab, token string, refreshInterval time.Duration,
+	refreshFunc func() ([]*v1alpha1.DashboardTab, error)) error {
+	var err error
+	if screen, err = tcell.NewScreen(); err != nil {
+		return err
+	}
+	app = tview.NewApplication().SetScreen(screen)
+	defer recoverCrash()
 	githubToken = token
 	currentTabs = tabs
+	// the skipped mutations would garble the screen, they go to the dry run
+	// log while the position bar and the status bar report the dry run.
+	if github.DryRun {
+		github.DryRunLog = io.Discard
+		if logFile, err := appendLog(dryRunLog()); err == nil {
+			defer logFile.Close()                                                                     // nolint
+			defer fmt.Fprintf(os.Stderr, "The skipped GitHub mutations are in %s.\n", logFile.Name()) // nolint
+			github.DryRunLog = logFile
+		}
+	}
 
 	// Render tab in the first row
 	tabsPanel = tview.NewList().ShowSecondaryText(false)
@@ -273,19 +502,57 @@ func RenderVisual(tabs []*v1alpha1.DashboardTab, token string, refreshInterval t
 	tabsPanel.SetSelectedBackgroundColor(tcell.ColorBlue)
 	tabsPanel.SetHighlightFullLine(true)
 	tabsPanel.SetMainTextStyle(tcell.StyleDefault)
-	tabsPanel.SetTitle(formatTitle("Board#Tabs"))
+	tabsPanel.SetTitle(boardsTitle())
+	tabsCapture := vimListCapture(tabsPanel, &lastTabsGPress)
+	tabsPanel.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && event.Rune() == 'm' {
+			showMaintenancePage()
+			return nil
+		}
+		return tabsCapture(event)
+	})
 
 	// Broken tests in the tab
 	brokenPanel.ShowSecondaryText(false).SetDoneFunc(func() { app.SetFocus(tabsPanel) })
 	setPanelDefaultStyle(brokenPanel.Box)
-	brokenPanel.SetTitle(formatTitle("Tests"))
+	brokenPanel.SetTitle(testsTitle())
 	brokenPanel.SetSelectedBackgroundColor(tcell.ColorBlue)
 	brokenPanel.SetHighlightFullLine(true)
 	brokenPanel.SetMainTextStyle(tcell.StyleDefault)
+	brokenCapture := vimListCapture(brokenPanel, &lastTestsGPress)
+	brokenPanel.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune {
+			switch event.Rune() {
+			case 's':
+				cycleTestsSort()
+				return nil
+			case '/':
+				showSearch()
+				return nil
+			case ' ':
+				toggleMark()
+				return nil
+			case 'z', 'a', 'u':
+				suppressSelectedTest(event.Rune())
+				return nil
+			case 'm':
+				showMaintenancePage()
+				return nil
+			case 't', 'p', 'r':
+				openTestLink(event.Rune())
+				return nil
+			}
+		}
+		if event.Key() == tcell.KeyCtrlB {
+			showBatchConfirm()
+			return nil
+		}
+		return brokenCapture(event)
+	})
 
 	// Slack Final issue rendering
 	setPanelDefaultStyle(slackPanel.Box)
-	slackPanel.SetTitle(formatTitle("Slack Message"))
+	slackPanel.SetTitle(slackTitle())
 	slackPanel.SetWrap(true)
 	slackPanel.SetTextStyle(tcell.StyleDefault)
 
@@ -298,11 +565,15 @@ func RenderVisual(tabs []*v1alpha1.DashboardTab, token string, refreshInterval t
 	// Final position bottom panel for information
 	position.SetDynamicColors(true).SetTextAlign(tview.AlignCenter).SetText(defaultPositionText).SetTextStyle(tcell.StyleDefault)
 
+	// Status bar of the GitHub rate limit, the MCP server and the TestGrid fetch
+	statusBar.SetDynamicColors(true).SetTextStyle(tcell.StyleDefault)
+
 	// Create the grid layout
-	grid := tview.NewGrid().SetRows(10, 10, 0, 0, 1).
+	grid := tview.NewGrid().SetRows(10, 10, 0, 0, 1, 1).
 		AddItem(tabsPanel, 0, 0, 1, 2, 0, 0, true).
 		AddItem(brokenPanel, 1, 0, 1, 2, 0, 0, false).
-		AddItem(position, 4, 0, 1, 2, 0, 0, false)
+		AddItem(position, 4, 0, 1, 2, 0, 0, false).
+		AddItem(statusBar, 5, 0, 1, 2, 0, 0, false)
 
 	// Adding middle panel and split across rows and columns
 	grid.AddItem(slackPanel, 2, 0, 2, 1, 0, 0, false).
@@ -310,10 +581,14 @@ func RenderVisual(tabs []*v1alpha1.DashboardTab, token string, refreshInterval t
 
 	// Initial tabs setup
 	updateTabsPanel(tabs)
+	loadBoardTrends()
+	loadIssueLinks()
+	refreshStatus()
 
 	// Set up periodic refresh if interval is configured and refresh function is provided
 	if refreshInterval > 0 && refreshFunc != nil {
 		go func() {
+			defer recoverCrash()
 			ticker := time.NewTicker(refreshInterval)
 			defer ticker.Stop()
 			for range ticker.C {
@@ -321,14 +596,19 @@ func RenderVisual(tabs []*v1alpha1.DashboardTab, token string, refreshInterval t
 				if err != nil {
 					app.QueueUpdateDraw(func() {
 						position.SetText(fmt.Sprintf("[red]Refresh error: %v", err))
+						refreshStatus()
 					})
 					continue
 				}
 				app.QueueUpdateDraw(func() {
 					updateTabsPanel(newTabs)
+					loadBoardTrends()
+					loadIssueLinks()
+					refreshStatus()
 					position.SetText(fmt.Sprintf("[green]Refreshed at %s", time.Now().Format("15:04:05")))
 					// Clear refresh message after 1 seconds
 					go func() {
+						defer recoverCrash()
 						time.Sleep(1 * time.Second)
 						app.QueueUpdateDraw(func() {
 							position.SetText(defaultPositionText)
@@ -339,52 +619,90 @@ func RenderVisual(tabs []*v1alpha1.DashboardTab, token string, refreshInterval t
 		}()
 	}
 
+	// SIGTERM stops the UI like Ctrl-C.
+	stop := context.AfterFunc(ctx, app.Stop)
+	defer stop()
+
 	// Render the final page.
 	pages = tview.NewPages().AddPage(pagesName, grid, true, true)
-	return app.SetRoot(pages, true).EnableMouse(true).Run()
+	err = app.SetRoot(pages, true).EnableMouse(true).Run()
+	// the issues and Slack messages being sent are not dropped on exit.
+	waitPendingActions(os.Stderr, pendingTimeout)
+	return err
+}
+
+// slackMessage returns the Slack message of the test with its mentions,
+// flagging freeze periods.
+func slackMessage(tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestResult) string {
+	item := issue.WithMentions(issue.SlackMessage(tab, currentTest), slackMentions.For(tab, currentTest, time.Now()))
+	if releasePhase.InFreeze() {
+		item = fmt.Sprintf(":rotating_light: *%s* %s", releasePhase, item)
+	}
+	return item
 }
 
 // updateSlackPanel writes down to left panel (Slack) content.
 func updateSlackPanel(tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestResult) {
 	// set the item string with current test content
-	item := fmt.Sprintf("%s %s on [%s](%s): `%s` [Prow](%s), [Triage](%s), last failure on %s\n",
-		tab.StateIcon, cases.Title(language.English).String(tab.TabState), tab.BoardHash, tab.TabURL,
-		currentTest.TestName, currentTest.ProwJobURL, currentTest.TriageURL, timeClean(currentTest.LatestTimestamp),
-	)
-	item = strings.TrimRight(item, "\r\n")
+	item := slackMessage(tab, currentTest)
 
-	// set input capture, "yy" for clipboard copy, esc to cancel panel selection.
+	// set input capture, "yy" for clipboard copy, "f" for the copy format,
+	// Ctrl-B to post to Slack, esc to cancel panel selection.
 	slackPanel.SetText(item, false)
 	slackPanel.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if translated, ok := vimTextAreaKey(slackPanel, event, &lastSlackGPress); ok {
+			return translated
+		}
 		if event.Key() == tcell.KeyRune {
 			switch event.Rune() {
 			case 'y', 'Y':
 				if isYankShortcut(event, &lastSlackYPress) {
-					position.SetText("[blue]COPIED [yellow]SLACK [blue]TO THE CLIPBOARD!")
-					if err := CopyToClipboard(slackPanel.GetText()); err != nil {
+					position.SetText(fmt.Sprintf("[blue]COPIED [yellow]SLACK [blue]TO THE CLIPBOARD AS [yellow]%s[blue]!", slackCopyFormat))
+					if err := CopyToClipboard(slackCopyFormat.apply(slackPanel.GetText())); err != nil {
 						position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
 						return nil
 					}
+					recordAction(actionSlackCopied)
 					flashPanelCopyState(slackPanel)
 				}
 				return nil
-			case 'j':
-				return tcell.NewEventKey(tcell.KeyDown, 0, event.Modifiers())
-			case 'k':
-				return tcell.NewEventKey(tcell.KeyUp, 0, event.Modifiers())
-			case 'G':
-				moveTextAreaToBottom(slackPanel)
-				return nil
-			case 'g':
-				if isGoTopShortcut(event, &lastSlackGPress) {
-					moveTextAreaToTop(slackPanel)
-				}
+			case 'f', 'F':
+				slackCopyFormat = slackCopyFormat.next()
+				position.SetText(fmt.Sprintf("[blue]yy copies [yellow]SLACK [blue]as [yellow]%s", slackCopyFormat))
 				return nil
 			default:
 				// Read-only panel: ignore direct text edits.
 				return nil
 			}
 		}
+		if event.Key() == tcell.KeyCtrlB {
+			if postingSlack {
+				return nil
+			}
+			postingSlack = true
+			message := slackPanel.GetText()
+			stopLoading := startLoading("Posting the message to Slack")
+			done := trackAction()
+			go func() {
+				defer recoverCrash()
+				channels, followUp, err := postSlackMessages(tab, currentTest, message)
+				// the UI may be stopped, its updates are not waited for.
+				done()
+				app.QueueUpdateDraw(func() {
+					stopLoading()
+					postingSlack = false
+					switch {
+					case err != nil:
+						position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
+					case followUp:
+						position.SetText(fmt.Sprintf("[blue]Posted [yellow]SLACK [blue]follow-up in the thread of the test on %s", channels))
+					default:
+						position.SetText(fmt.Sprintf("[blue]Posted [yellow]SLACK [blue]message on %s", channels))
+					}
+				})
+			}()
+			return nil
+		}
 		if event.Key() == tcell.KeyEscape {
 			closeDetailPanels()
 			return nil
@@ -403,59 +721,115 @@ func updateSlackPanel(tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestResu
 
 // updateGitHubPanel writes down to the right panel (GitHub) content.
 func updateGitHubPanel(tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestResult, token string) {
-	// create the filled-out issue template object
-	splitBoard := strings.Split(tab.BoardHash, "#")
-	issue := &IssueTemplate{
-		BoardName:    splitBoard[0],
-		TabName:      splitBoard[1],
-		TestName:     currentTest.TestName,
-		TestGridURL:  tab.TabURL,
-		TriageURL:    currentTest.TriageURL,
-		ProwURL:      currentTest.ProwJobURL,
-		ErrMessage:   currentTest.ErrorMessage,
-		FirstFailure: timeClean(currentTest.FirstTimestamp),
-		LastFailure:  timeClean(currentTest.LatestTimestamp),
-	}
-
-	// pick the correct template by failure status
-	templateFile, prefixTitle := "template/flake.tmpl", "Flaking Test"
-	if tab.TabState == v1alpha1.FAILING_STATUS {
-		templateFile, prefixTitle = "template/failure.tmpl", "Failing Test"
-	}
-	template, err := renderTemplate(issue, templateFile)
-	if err != nil {
-		position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
-		return
-	}
-	issueBody := strings.TrimRight(template.String(), "\r\n")
-	issueTitle := fmt.Sprintf("[%v] %v", prefixTitle, currentTest.TestName)
-	githubPanel.SetText(issueBody, false)
+	// create the filled-out issue template object, the UI state is read here
+	// and the rendering runs in background.
+	issueTemplate := issue.NewTemplate(tab, currentTest)
+	issueTemplate.Logs = includedLogs(currentTest)
+	issueTemplate.Details = fetchedDetails(currentTest)
+	issueTemplate.Priority = releasePhase.Priority(issue.TestState(tab, currentTest) == v1alpha1.FAILING_STATUS)
+	tabs := currentTabs
+
+	// pick the correct template by the failure status of the test
+	templateFile, prefixTitle := issue.PickTemplate(issue.TestState(tab, currentTest))
+	issueTitle := issue.Title(prefixTitle, currentTest.TestName)
+
+	similarIssues = nil
+	resetIssueEdits()
+	githubPanel.SetTitle(githubTitle(tab, currentTest))
+	githubRenderID++
+	renderID := githubRenderID
+	githubRendered = false
+	githubPanel.SetText("", false)
+	stopLoading := startLoading("Rendering the issue")
+	go func() {
+		defer recoverCrash()
+		rendered, err := renderIssue(issueTemplate, templateFile, tabs, tab, currentTest)
+		app.QueueUpdateDraw(func() {
+			stopLoading()
+			if renderID != githubRenderID {
+				// another test was selected meanwhile
+				return
+			}
+			if err != nil {
+				position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
+				return
+			}
+			githubPanel.SetText(rendered.body, false)
+			githubLogsBlock, githubCulpritsBlock, githubNotesBlock = rendered.logsBlock, rendered.culpritsBlock, rendered.notesBlock
+			githubDetailsBlock = rendered.detailsBlock
+			githubRendered = true
+			position.SetText(defaultPositionText)
+			notes := rendered.crossBranchText
+			if rendered.variantsText != "" && notes != "" {
+				notes += " [white]| "
+			}
+			if notes += rendered.variantsText; notes != "" {
+				position.SetText(notes)
+			}
+			if len(rendered.deadLinks) > 0 {
+				// the Slack message gets the same links as the issue.
+				slackPanel.SetText(slackMessage(rendered.links.Apply(tab, currentTest)), false)
+				position.SetText(fmt.Sprintf("[yellow]%s", tview.Escape(deadLinksText(rendered.deadLinks))))
+			}
+			findSimilarIssues(renderID, tab, currentTest, token)
+		})
+	}()
 
-	// set input capture, "yy" for clipboard copy, ctrl-b for
-	// automatic GitHub draft issue creation.
+	// set input capture, "yy" for clipboard copy, "f" for the copy format, "i"
+	// to edit the body until esc, "t" to edit the title, ctrl-b for
+	// automatic GitHub draft issue creation with the edits, ctrl-t to create
+	// repository issues instead of drafts, ctrl-l for the job log streams,
+	// ctrl-p for the possible culprits, ctrl-n for the test notes,
+	// ctrl-e for the similar existing issues, ctrl-o for the infrastructure
+	// of the runs, ctrl-r for the recorded history, ctrl-g for the test
+	// details of the JUnit results.
 	githubPanel.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if githubEditing {
+			switch event.Key() {
+			case tcell.KeyEscape:
+				githubEditing = false
+				githubPanel.SetTitle(githubTitle(tab, currentTest))
+				position.SetText("[blue]Ctrl-B creates the issue with the edits, [yellow]i [blue]edits it again")
+				return nil
+			case tcell.KeyCtrlB:
+				// creates the issue with the edits below
+			default:
+				githubEdited = true
+				return event
+			}
+		}
+		if translated, ok := vimTextAreaKey(githubPanel, event, &lastGitHubGPress); ok {
+			return translated
+		}
 		if event.Key() == tcell.KeyRune {
 			switch event.Rune() {
 			case 'y', 'Y':
 				if isYankShortcut(event, &lastGitHubYPress) {
-					position.SetText("[blue]COPIED [yellow]ISSUE [blue]TO THE CLIPBOARD!")
-					if err := CopyToClipboard(githubPanel.GetText()); err != nil {
+					position.SetText(fmt.Sprintf("[blue]COPIED [yellow]ISSUE [blue]TO THE CLIPBOARD AS [yellow]%s[blue]!", githubCopyFormat))
+					// the front matter configures the issue creation and is not part of the body
+					_, body, _ := issue.ParseFrontMatter(githubPanel.GetText())
+					if err := CopyToClipboard(githubCopyFormat.apply(body)); err != nil {
 						position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
 						return nil
 					}
+					recordAction(actionIssueCopied)
 					flashPanelCopyState(githubPanel)
 				}
 				return nil
-			case 'j':
-				return tcell.NewEventKey(tcell.KeyDown, 0, event.Modifiers())
-			case 'k':
-				return tcell.NewEventKey(tcell.KeyUp, 0, event.Modifiers())
-			case 'G':
-				moveTextAreaToBottom(githubPanel)
+			case 'f', 'F':
+				githubCopyFormat = githubCopyFormat.next()
+				position.SetText(fmt.Sprintf("[blue]yy copies the [yellow]ISSUE [blue]as [yellow]%s", githubCopyFormat))
 				return nil
-			case 'g':
-				if isGoTopShortcut(event, &lastGitHubGPress) {
-					moveTextAreaToTop(githubPanel)
+			case 'i':
+				if githubRendered {
+					githubEditing = true
+					githubPanel.SetTitle(githubTitle(tab, currentTest))
+					position.SetText("[green]Editing the issue, [blue]Esc [green]to stop, [blue]Ctrl-B [green]to create it with the edits")
+				}
+				return nil
+			case 't':
+				if githubRendered {
+					showTitleEdit(issueTitle, func() { githubPanel.SetTitle(githubTitle(tab, currentTest)) })
 				}
 				return nil
 			default:
@@ -464,19 +838,71 @@ func updateGitHubPanel(tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestRes
 			}
 		}
 		if event.Key() == tcell.KeyCtrlB {
-			gh := github.NewProjectManager(context.Background(), token)
-			if err := gh.CreateDraftIssue(issueTitle, issueBody, tab.BoardHash); err != nil {
+			if !githubRendered || creatingIssue {
+				return nil
+			}
+			frontMatter, body, err := issue.ParseFrontMatter(githubPanel.GetText())
+			if err != nil {
 				position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
 				return nil
 			}
-			position.SetText("[blue]Created [yellow]DRAFT ISSUE [blue] on GitHub Project!")
-			setPanelFocusStyle(githubPanel.Box)
-			go func() {
-				app.QueueUpdateDraw(func() {
-					app.SetFocus(brokenPanel)
-					setPanelDefaultStyle(githubPanel.Box)
-				})
-			}()
+			if readOnly {
+				position.SetText(fmt.Sprintf("[red]error: %v", errReadOnly))
+				return nil
+			}
+			githubEditing = false
+			githubPanel.SetTitle(githubTitle(tab, currentTest))
+			repo := ""
+			if defaults := issueFrontMatter(frontMatter, tab, currentTest); defaults != nil {
+				repo = defaults.Repo
+			}
+			fields := newIssueForm(frontMatter, body, repo, currentTest.Sig)
+			showIssueForm(fields, knownSIGs(currentTabs), func(picked issueForm) {
+				createIssue(tab, currentTest, token, editedIssueTitle(issueTitle), frontMatter, body, picked)
+			})
+			return nil
+		}
+		if event.Key() == tcell.KeyCtrlT {
+			repositoryIssues = !repositoryIssues
+			githubPanel.SetTitle(githubTitle(tab, currentTest))
+			if repositoryIssues {
+				position.SetText(fmt.Sprintf("[blue]Ctrl-B creates [yellow]ISSUES [blue]in the repository of the test, e.g. %s", issue.Repository(tab.BoardHash, currentTest.TestName)))
+			} else {
+				position.SetText("[blue]Ctrl-B creates [yellow]DRAFT ISSUES [blue]on the GitHub Project")
+			}
+			return nil
+		}
+		if !githubRendered && (event.Key() == tcell.KeyCtrlL || event.Key() == tcell.KeyCtrlP ||
+			event.Key() == tcell.KeyCtrlN || event.Key() == tcell.KeyCtrlG) {
+			// the issue blocks are replaced once the body is rendered
+			return nil
+		}
+		if event.Key() == tcell.KeyCtrlL {
+			showLogsPage(currentTest)
+			return nil
+		}
+		if event.Key() == tcell.KeyCtrlP {
+			showCulprits(tab, currentTest, token)
+			return nil
+		}
+		if event.Key() == tcell.KeyCtrlN {
+			showNotesPage(currentTest)
+			return nil
+		}
+		if event.Key() == tcell.KeyCtrlG {
+			showDetailsPage(currentTest)
+			return nil
+		}
+		if event.Key() == tcell.KeyCtrlE {
+			showSimilarIssuesPage(currentTest)
+			return nil
+		}
+		if event.Key() == tcell.KeyCtrlO {
+			showInfraPage(currentTest)
+			return nil
+		}
+		if event.Key() == tcell.KeyCtrlR {
+			showHistoryPage(currentTest)
 			return nil
 		}
 		if event.Key() == tcell.KeyEscape {
@@ -499,45 +925,137 @@ func updateGitHubPanel(tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestRes
 	})
 }
 
-// timeClean returns the string representation of the timestamp.
-func timeClean(ts int64) string {
-	return time.Unix(ts/1000, 0).UTC().Format(time.RFC1123)
+// createIssue files the issue of the test with the fields picked in the
+// issue form in background, like Ctrl-B on the GitHub panel.
+func createIssue(tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestResult, token, title string,
+	frontMatter *issue.FrontMatter, body string, picked issueForm) {
+	if creatingIssue {
+		return
+	}
+	frontMatter, body = picked.apply(frontMatter, body)
+	creatingIssue = true
+	stopLoading := startLoading("Creating the issue on GitHub")
+	done := trackAction()
+	go func() {
+		defer recoverCrash()
+		filed, repo, err := fileIssue(token, tab, currentTest, title, frontMatter, body)
+		done()
+		app.QueueUpdateDraw(func() {
+			stopLoading()
+			creatingIssue = false
+			if err != nil {
+				position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
+				return
+			}
+			// the edits are sent, nothing left to discard
+			resetIssueEdits()
+			githubPanel.SetTitle(githubTitle(tab, currentTest))
+			linkCreatedIssue(currentTest.TestName, filed)
+			refreshTestsList()
+			if github.DryRun {
+				position.SetText("[yellow]DRY RUN [blue]the issue was not sent to GitHub")
+			} else if filed != nil && !filed.ClosedAt.IsZero() {
+				position.SetText(fmt.Sprintf("[blue]Reopened [yellow]ISSUE #%d [blue]closed on %s", filed.Number, filed.ClosedAt.Format(time.DateOnly)))
+			} else if filed != nil {
+				position.SetText(fmt.Sprintf("[blue]Created [yellow]ISSUE #%d [blue]in %s", filed.Number, repo))
+			} else {
+				position.SetText("[blue]Created [yellow]DRAFT ISSUE [blue] on GitHub Project!")
+			}
+			app.SetFocus(brokenPanel)
+			setPanelDefaultStyle(githubPanel.Box)
+		})
+	}()
+}
+
+// issueFrontMatter returns the front matter of the template creating the
+// issue in the repository of the test when repositoryIssues is set, labeled
+// with the SIG of the test.
+func issueFrontMatter(frontMatter *issue.FrontMatter, tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestResult) *issue.FrontMatter {
+	if repositoryIssues {
+		frontMatter = frontMatter.WithRepository(issue.Repository(tab.BoardHash, currentTest.TestName))
+	}
+	return frontMatter.WithSIGLabel(currentTest.Sig)
 }
 
-// CopyToClipboard pipes the panel content to clip.exe WSL.
-func CopyToClipboard(text string) error {
-	var cmd *exec.Cmd
-	// Detect the operating system and use appropriate clipboard command
+// fileIssue creates the issue of the test on GitHub, in the repository of
+// the front matter or as a draft, reopening the issue closed within the
+// reopen window. It returns the filed issue, nil for a draft, and the
+// repository of a created issue.
+func fileIssue(token string, tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestResult, title string,
+	frontMatter *issue.FrontMatter, body string) (*github.Issue, string, error) {
+	if readOnly {
+		return nil, "", errReadOnly
+	}
+	repo := ""
+	if frontMatter != nil {
+		repo = frontMatter.Repo
+	}
+	gh := github.NewProjectManager(context.Background(), token)
+	filed, err := github.ReopenOrCreateDraft(gh, culprit.DefaultOwner, culprit.DefaultRepository,
+		currentTest.TestName, title, body, tab.BoardHash, frontMatter.Options(), reopenWindow)
+	switch {
+	case err != nil, github.DryRun:
+	case filed != nil && !filed.ClosedAt.IsZero():
+		recordAction(actionIssueReopened)
+	case filed != nil:
+		recordAction(actionIssueCreated)
+	default:
+		recordAction(actionDraftCreated)
+	}
+	return filed, repo, err
+}
+
+// ClipboardTool returns the clipboard command used by CopyToClipboard on
+// this system, osc52 over SSH and on Linux without a display server, e.g.
+// in tmux on a bastion. SIGNALHOUND_CLIPBOARD overrides it, then the
+// clipboard of the configuration file, see SetClipboardTool.
+func ClipboardTool() (string, error) {
+	if tool := os.Getenv("SIGNALHOUND_CLIPBOARD"); tool != "" {
+		return tool, nil
+	}
+	if clipboardTool != "" {
+		return clipboardTool, nil
+	}
+	if isSSH() {
+		return osc52Tool, nil
+	}
 	switch runtime.GOOS {
 	case "windows":
-		// Native Windows
-		cmd = exec.Command("cmd", "/c", "echo "+text+" | clip")
-		// Alternative: cmd = exec.Command("powershell", "-command", "Set-Clipboard", "-Value", text)
+		return "clip", nil
 	case "darwin":
-		// macOS
-		cmd = exec.Command("pbcopy")
-		cmd.Stdin = strings.NewReader(text)
+		return "pbcopy", nil
 	case "linux":
-		// Linux - need to check for available clipboard manager
-		// Try different clipboard managers in order of preference
-
-		// Check if running under WSL
+		// Check if running under WSL, then Wayland, falling back to X11
 		if isWSL() {
-			// WSL environment - use clip.exe
-			cmd = exec.Command("clip.exe")
-			cmd.Stdin = strings.NewReader(text)
+			return "clip.exe", nil
 		} else if isWayland() {
-			// Wayland
-			cmd = exec.Command("wl-copy")
-			cmd.Stdin = strings.NewReader(text)
-		} else {
-			// X11
-			cmd = exec.Command("xclip", "-selection", "clipboard")
-			cmd.Stdin = strings.NewReader(text)
+			return "wl-copy", nil
+		} else if isX11() {
+			return "xclip", nil
 		}
+		return osc52Tool, nil
+	}
+	return "", fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
+}
 
-	default:
-		return fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
+// CopyToClipboard pipes the panel content to the clipboard command of the
+// system, see ClipboardTool. When the detected command fails, e.g. xclip is
+// not installed on a headless host, the other clipboard commands found in
+// the PATH are tried, then OSC 52. A command set by the user has no
+// fallback.
+func CopyToClipboard(text string) error {
+	tool, err := ClipboardTool()
+	if err != nil {
+		return err
+	}
+	err = copyWith(tool, text)
+	if err == nil || os.Getenv("SIGNALHOUND_CLIPBOARD") != "" || clipboardTool != "" {
+		return err
+	}
+	for _, fallback := range fallbackClipboardTools(tool, exec.LookPath) {
+		if copyWith(fallback, text) == nil {
+			return nil
+		}
 	}
-	return cmd.Run()
+	return err
 }
@@ -0,0 +1,310 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/infra"
+	"sigs.k8s.io/signalhound/internal/issue"
+	"sigs.k8s.io/signalhound/internal/monitoring"
+	"sigs.k8s.io/signalhound/internal/prow"
+	"sigs.k8s.io/signalhound/internal/state"
+	"sigs.k8s.io/signalhound/internal/store"
+)
+
+func TestRenderIssue(t *testing.T) {
+	store := state.NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+	_, err := state.Update(store, func(current *state.State) {
+		current.Annotate("[sig-node] Pods", "waiting on #12345", "alice")
+	})
+	assert.NoError(t, err)
+	SetStateStore(store)
+	t.Cleanup(func() { SetStateStore(nil) })
+
+	failure := v1alpha1.TestResult{TestName: "[sig-node] Pods", Fingerprint: "abc"}
+	master := &v1alpha1.DashboardTab{BoardHash: "sig-release-master-blocking#gce", TabState: v1alpha1.FAILING_STATUS, TestRuns: []v1alpha1.TestResult{failure}}
+	branch := &v1alpha1.DashboardTab{BoardHash: "sig-release-1.34-blocking#gce", TabState: v1alpha1.FAILING_STATUS, TestRuns: []v1alpha1.TestResult{failure}}
+
+	rendered, err := renderIssue(issue.NewTemplate(branch, &failure), issue.FailureTemplate,
+		[]*v1alpha1.DashboardTab{master, branch}, branch, &failure)
+	assert.NoError(t, err)
+	assert.Contains(t, rendered.body, logsHeader+rendered.logsBlock+rendered.notesBlock)
+	assert.Contains(t, rendered.notesBlock, "* waiting on #12345 (alice, ")
+	assert.True(t, strings.HasPrefix(rendered.crossBranchText, "[yellow]Same failure on sig-release-master-blocking#gce"))
+}
+
+func TestTestItemText(t *testing.T) {
+	assert.Equal(t, "Pods should [run[]", testItemText(v1alpha1.TestResult{TestName: "Pods should [run]"}, nil))
+	assert.Equal(t, "[gray]Pods should run (skipped in job config)[-]",
+		testItemText(v1alpha1.TestResult{TestName: "Pods should run", Skipped: true}, nil))
+
+	current := &state.State{}
+	current.Acknowledge("Pods should run", v1alpha1.FLAKY_STATUS, "alice")
+	assert.Equal(t, "[gray]Pods should run (acknowledged as flaky)[-]", testItemText(v1alpha1.TestResult{TestName: "Pods should run"}, current))
+	current.Snooze("Pods should run", v1alpha1.FLAKY_STATUS, time.Now().Add(-time.Hour), "alice")
+	assert.Equal(t, "Pods should run", testItemText(v1alpha1.TestResult{TestName: "Pods should run"}, current))
+}
+
+func TestClipboardTool(t *testing.T) {
+	for _, key := range []string{"SIGNALHOUND_CLIPBOARD", "SSH_CONNECTION", "SSH_CLIENT", "SSH_TTY"} {
+		t.Setenv(key, "")
+	}
+	t.Setenv("SSH_CONNECTION", "10.0.0.1 51234 10.0.0.2 22")
+	tool, err := ClipboardTool()
+	assert.NoError(t, err)
+	assert.Equal(t, osc52Tool, tool)
+
+	defer SetClipboardTool("")
+	SetClipboardTool("wl-copy")
+	tool, err = ClipboardTool()
+	assert.NoError(t, err)
+	assert.Equal(t, "wl-copy", tool)
+
+	t.Setenv("SIGNALHOUND_CLIPBOARD", "xsel")
+	tool, err = ClipboardTool()
+	assert.NoError(t, err)
+	assert.Equal(t, "xsel", tool)
+
+	installed := func(tools ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			if slices.Contains(tools, name) {
+				return "/usr/bin/" + name, nil
+			}
+			return "", exec.ErrNotFound
+		}
+	}
+	assert.Equal(t, []string{"xsel", osc52Tool}, fallbackClipboardTools("xclip", installed("xclip", "xsel")))
+	assert.Equal(t, []string{osc52Tool}, fallbackClipboardTools("xclip", installed()))
+	assert.Equal(t, []string{"wl-copy"}, fallbackClipboardTools(osc52Tool, installed("wl-copy")))
+}
+
+func TestBrowserCommand(t *testing.T) {
+	for _, key := range []string{"SIGNALHOUND_BROWSER", "SSH_CONNECTION", "SSH_CLIENT", "SSH_TTY"} {
+		t.Setenv(key, "")
+	}
+	t.Setenv("SSH_TTY", "/dev/pts/0")
+	_, err := BrowserCommand("https://prow.k8s.io")
+	assert.ErrorIs(t, err, errNoBrowser)
+
+	t.Setenv("SIGNALHOUND_BROWSER", "firefox")
+	command, err := BrowserCommand("https://prow.k8s.io")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"firefox", "https://prow.k8s.io"}, command)
+
+	test := v1alpha1.TestResult{ProwJobURL: "https://prow.k8s.io/view/gs/1", TriageURL: "https://go.k8s.io/triage?test=x"}
+	name, url := testLink("https://testgrid.k8s.io/sig-release-master-blocking#gce", test, 't')
+	assert.Equal(t, "TestGrid", name)
+	assert.Equal(t, "https://testgrid.k8s.io/sig-release-master-blocking#gce", url)
+	_, url = testLink("", test, 'p')
+	assert.Equal(t, test.ProwJobURL, url)
+	_, url = testLink("", test, 'r')
+	assert.Equal(t, test.TriageURL, url)
+}
+
+func TestOSC52Sequence(t *testing.T) {
+	t.Setenv("TERM", "xterm-256color")
+	t.Setenv("TMUX", "")
+	assert.Equal(t, "\x1b]52;c;aGVsbG8=\a", osc52Sequence("hello"))
+
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1234,0")
+	assert.Equal(t, "\x1bPtmux;\x1b\x1b]52;c;aGVsbG8=\a\x1b\\", osc52Sequence("hello"))
+
+	t.Setenv("TMUX", "")
+	t.Setenv("TERM", "screen-256color")
+	assert.Equal(t, "\x1bP\x1b]52;c;aGVsbG8=\a\x1b\\", osc52Sequence("hello"))
+
+	assert.ErrorContains(t, copyOSC52(strings.Repeat("a", maxOSC52Bytes+1)), "too large")
+}
+
+func TestSimilarIssues(t *testing.T) {
+	t.Cleanup(func() { similarIssues = nil })
+	tab := &v1alpha1.DashboardTab{BoardHash: "sig-release-master-blocking#gce"}
+	test := &v1alpha1.TestResult{TestName: "[sig-node] Pods"}
+	assert.Equal(t, formatTitle("GitHub Issue - draft"), githubTitle(tab, test))
+
+	similarIssues = []github.Issue{
+		{Number: 129000, Title: "[Failing Test] [sig-node] Pods", URL: "https://github.com/kubernetes/kubernetes/issues/129000"},
+		{ID: "PVTI_1", Title: "[Flaky Test] Pods", Draft: true},
+	}
+	assert.Equal(t, formatTitle("GitHub Issue - draft - [red]2 similar[-]"), githubTitle(tab, test))
+	assert.Equal(t, "* #129000: [Failing Test] [sig-node] Pods\n  https://github.com/kubernetes/kubernetes/issues/129000\n"+
+		"* draft: [Flaky Test] Pods", similarIssuesText(similarIssues))
+}
+
+func TestIssueEdits(t *testing.T) {
+	t.Cleanup(resetIssueEdits)
+	tab := &v1alpha1.DashboardTab{BoardHash: "sig-release-master-blocking#gce"}
+	test := &v1alpha1.TestResult{TestName: "[sig-node] Pods"}
+	assert.Equal(t, "[Failing Test] [sig-node] Pods", editedIssueTitle("[Failing Test] [sig-node] Pods"))
+
+	githubEditing = true
+	assert.Equal(t, formatTitle("GitHub Issue - draft - [yellow]EDITING[-]"), githubTitle(tab, test))
+	githubEditing, githubEdited, issueTitleEdit = false, true, "[Failing Test] Pods restart on cgroup v2"
+	assert.Equal(t, formatTitle("GitHub Issue - draft - [yellow]edited[-]"), githubTitle(tab, test))
+	assert.Equal(t, "[Failing Test] Pods restart on cgroup v2", editedIssueTitle("[Failing Test] [sig-node] Pods"))
+
+	// without edits there is nothing to discard
+	resetIssueEdits()
+	left := false
+	confirmDiscardEdits(func() { left = true })
+	assert.True(t, left)
+	assert.Equal(t, formatTitle("GitHub Issue - draft"), githubTitle(tab, test))
+}
+
+func TestInfraText(t *testing.T) {
+	failed := []*prow.Infrastructure{{URL: "https://prow.k8s.io/view/gs/bucket/logs/job/2", Zone: "us-central1-b"}}
+	clusters := []infra.Cluster{{Attribute: infra.Zone, Value: "us-central1-b", Failures: 3, FailedRuns: 3, PassedRuns: 2}}
+	assert.Equal(t, "Failures cluster on:\n* zone us-central1-b: 3 of 3 red runs, 0 of 2 green runs\n\nRed runs:\n"+
+		"* https://prow.k8s.io/view/gs/bucket/logs/job/2\n  zone us-central1-b, image unknown, instance type unknown\n", infraText(failed, clusters, 2))
+	assert.True(t, strings.HasPrefix(infraText(failed, nil, 0), "No green run to compare the red runs with.\n"))
+}
+
+func TestBisectText(t *testing.T) {
+	bisect := &bisectRange{lastPass: "6f3c1b8e2a9d04", firstFail: "a1b2c3d4e5f607",
+		lastPassURL: "https://prow.k8s.io/view/gs/bucket/logs/job/1", firstFailURL: "https://prow.k8s.io/view/gs/bucket/logs/job/2"}
+	assert.Equal(t, "git log 6f3c1b8e2a9d04..a1b2c3d4e5f607", bisect.command())
+	assert.Contains(t, bisectText(bisect), "[blue]First red run:[-] a1b2c3d4e5f607 https://prow.k8s.io/view/gs/bucket/logs/job/2\n")
+	assert.Contains(t, bisectText(nil), "no revision")
+	assert.Contains(t, bisectText(bisect), "[blue]Suspected commits:[-] https://github.com/kubernetes/kubernetes/compare/6f3c1b8e2a9d04...a1b2c3d4e5f607\n")
+	assert.Nil(t, fetchBisectRange(&v1alpha1.TestResult{FirstFailRunURL: "https://prow.k8s.io/view/gs/bucket/logs/job/2"}))
+
+	// the commits of the TestGrid Commit column spare the finished.json reads
+	assert.Equal(t, bisect, fetchBisectRange(&v1alpha1.TestResult{
+		LastPassRunURL: bisect.lastPassURL, FirstFailRunURL: bisect.firstFailURL, LastPassCommit: "6f3c1b8e2a9d04", FirstFailCommit: "a1b2c3d4e5f607",
+	}))
+}
+
+func TestDegradedBoards(t *testing.T) {
+	at := time.Date(2026, 10, 12, 6, 0, 0, 0, time.UTC)
+	trends := &store.BoardTrends{Boards: []store.BoardTrend{
+		{Board: "sig-release-master-blocking", Transitions: []store.BoardTransition{
+			{Board: "sig-release-master-blocking", From: v1alpha1.PASSING_STATUS, To: v1alpha1.FLAKY_STATUS, At: at},
+			{Board: "sig-release-master-blocking", From: v1alpha1.FLAKY_STATUS, To: v1alpha1.FAILING_STATUS, At: at.Add(time.Hour)},
+		}},
+		{Board: "sig-release-master-informing", Transitions: []store.BoardTransition{
+			{Board: "sig-release-master-informing", From: v1alpha1.FAILING_STATUS, To: v1alpha1.PASSING_STATUS, At: at},
+		}},
+		{Board: "sig-release-1.34-blocking"},
+	}}
+	assert.Equal(t, "sig-release-master-blocking degraded from flaky to failing", degradedBoards(trends))
+	assert.Empty(t, degradedBoards(&store.BoardTrends{}))
+}
+
+func TestDriftItemText(t *testing.T) {
+	drift := state.Drift{TestName: "[sig-node] Pods", Suppression: state.Suppression{Kind: state.Acknowledged, State: v1alpha1.FLAKY_STATUS}, Change: state.ChangeRecovered}
+	assert.Equal(t, "[ [] [sig-node[] Pods: acknowledged as flaky, now recovered", driftItemText(drift, false))
+	assert.Equal(t, "[x[] [sig-node[] Pods: acknowledged as flaky, now recovered", driftItemText(drift, true))
+}
+
+// actionReader collects the metrics of the global meter provider, set once
+// since the instruments are bound to the first one.
+var actionReader = sync.OnceValue(func() *sdkmetric.ManualReader {
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	return reader
+})
+
+// actionCounts returns the cumulative counts of the UI actions.
+func actionCounts(t *testing.T) map[string]int64 {
+	var data metricdata.ResourceMetrics
+	assert.NoError(t, actionReader().Collect(context.Background(), &data))
+	counts := map[string]int64{}
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok && m.Name == monitoring.UserActions.Name {
+				for _, point := range sum.DataPoints {
+					action, _ := point.Attributes.Value("action")
+					counts[action.AsString()] += point.Value
+				}
+			}
+		}
+	}
+	return counts
+}
+
+func TestRecordAction(t *testing.T) {
+	before := actionCounts(t)
+	t.Cleanup(func() { SetActionTelemetry(false) })
+
+	// the actions are only counted once opted in
+	recordAction(actionSnoozed)
+	SetActionTelemetry(true)
+	recordAction(actionSnoozed)
+	recordAction(actionIssueCreated)
+
+	after := actionCounts(t)
+	assert.Equal(t, int64(1), after[actionSnoozed]-before[actionSnoozed])
+	assert.Equal(t, int64(1), after[actionIssueCreated]-before[actionIssueCreated])
+}
+
+func TestCopyFormat(t *testing.T) {
+	message := ":red_circle: *Failing* [gce-cos-master-default](https://testgrid.k8s.io/sig-release-master-blocking#gce-cos-master-default)\n" +
+		"* `[sig-node] Pods should run`"
+	assert.Equal(t, message, markdownFormat.apply(message))
+	assert.Equal(t, ":red_circle: *Failing* <https://testgrid.k8s.io/sig-release-master-blocking#gce-cos-master-default|gce-cos-master-default>\n"+
+		"* `[sig-node] Pods should run`", mrkdwnFormat.apply(message))
+	assert.Equal(t, ":red_circle: Failing gce-cos-master-default (https://testgrid.k8s.io/sig-release-master-blocking#gce-cos-master-default)\n"+
+		"* [sig-node] Pods should run", plainFormat.apply(message))
+
+	body := "<!-- filled by signalhound -->\n### Which jobs are failing?\n\n**sig-release-master-blocking**\n```\nerror: timeout\n```\n"
+	assert.Equal(t, "Which jobs are failing?\n\nsig-release-master-blocking\nerror: timeout\n", plainFormat.apply(body))
+
+	assert.Equal(t, mrkdwnFormat, markdownFormat.next())
+	assert.Equal(t, markdownFormat, plainFormat.next())
+}
+
+func TestRecoverCrash(t *testing.T) {
+	previousLog, previousExit := crashLog, exit
+	t.Cleanup(func() { crashLog, exit = previousLog, previousExit })
+	crashLog = filepath.Join(t.TempDir(), "signalhound", "crash.log")
+	code := 0
+	exit = func(c int) { code = c }
+
+	func() {
+		defer recoverCrash()
+		panic("boom")
+	}()
+	assert.Equal(t, 1, code)
+	data, err := os.ReadFile(crashLog)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), " panic: boom\n")
+	assert.Contains(t, string(data), "TestRecoverCrash")
+
+	// without panic the goroutine returns normally
+	code = 0
+	func() { defer recoverCrash() }()
+	assert.Equal(t, 0, code)
+}
+
+func TestDryRunLog(t *testing.T) {
+	previousLog := crashLog
+	t.Cleanup(func() { crashLog = previousLog })
+	crashLog = filepath.Join(t.TempDir(), "signalhound", "crash.log")
+	assert.Equal(t, filepath.Join(filepath.Dir(crashLog), "dry-run.log"), dryRunLog())
+
+	file, err := appendLog(dryRunLog())
+	assert.NoError(t, err)
+	_, err = file.WriteString("dry run: skipped createIssue {}\n")
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+	data, err := os.ReadFile(dryRunLog())
+	assert.NoError(t, err)
+	assert.Equal(t, "dry run: skipped createIssue {}\n", string(data))
+
+	crashLog = ""
+	assert.Empty(t, dryRunLog())
+}
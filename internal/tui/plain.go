@@ -0,0 +1,247 @@
+package tui
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/issue"
+)
+
+// plainSession is the state of the plain mode, content is written as
+// labeled text blocks and every choice is a numbered menu read line by line.
+type plainSession struct {
+	in          *bufio.Scanner
+	out         io.Writer
+	tabs        []*v1alpha1.DashboardTab
+	token       string
+	refreshFunc func() ([]*v1alpha1.DashboardTab, error)
+}
+
+// RenderPlain runs the screen reader friendly mode on in and out instead of
+// the grid UI: no color, position or layout carries information, and the
+// menus are numbered the same way on every visit. It returns when the user
+// quits or in is closed. A nil refreshFunc disables the refresh entry.
+func RenderPlain(in io.Reader, out io.Writer, tabs []*v1alpha1.DashboardTab, token string,
+	refreshFunc func() ([]*v1alpha1.DashboardTab, error)) error {
+	session := &plainSession{in: bufio.NewScanner(in), out: out, tabs: tabs, token: token, refreshFunc: refreshFunc}
+	return session.boards()
+}
+
+// boards lists the boards until the user quits.
+func (s *plainSession) boards() error {
+	for {
+		s.printf("\n== Boards: %d failing or flaky ==\n", len(s.tabs))
+		if releasePhase.InFreeze() {
+			s.printf("Release phase: %s\n", releasePhase)
+		}
+		for i, tab := range s.tabs {
+			s.printf("%d. %s, %s, %d tests\n", i+1, tab.BoardHash, strings.ToLower(tab.TabState), len(tab.TestRuns))
+		}
+		prompt := "Enter a board number, or q to quit:"
+		if s.refreshFunc != nil {
+			prompt = "Enter a board number, r to refresh, or q to quit:"
+		}
+		choice, number, ok := s.choose(prompt, len(s.tabs))
+		switch {
+		case !ok || choice == "q":
+			return nil
+		case choice == "r" && s.refreshFunc != nil:
+			tabs, err := s.refreshFunc()
+			if err != nil {
+				s.printf("Error refreshing the boards: %v\n", err)
+				continue
+			}
+			s.tabs = tabs
+			s.printf("Refreshed at %s.\n", time.Now().Format(time.TimeOnly))
+		case number > 0:
+			if quit := s.tests(s.tabs[number-1]); quit {
+				return nil
+			}
+		default:
+			s.printf("Unknown choice %q.\n", choice)
+		}
+	}
+}
+
+// tests lists the tests of the tab until the user goes back, it returns
+// true when the user quits.
+func (s *plainSession) tests(tab *v1alpha1.DashboardTab) bool {
+	for {
+		s.printf("\n== Tests of %s: %d ==\n", tab.BoardHash, len(tab.TestRuns))
+		for i, test := range tab.TestRuns {
+			skipped := ""
+			if test.Skipped {
+				skipped = ", skipped in job config"
+			}
+			s.printf("%d. %s, %d of %d runs failed%s\n", i+1, test.TestName, test.FailureCount, test.RunCount, skipped)
+		}
+		choice, number, ok := s.choose("Enter a test number, b to go back, or q to quit:", len(tab.TestRuns))
+		switch {
+		case !ok || choice == "q":
+			return true
+		case choice == "b":
+			return false
+		case number > 0:
+			if quit := s.test(tab, &tab.TestRuns[number-1]); quit {
+				return true
+			}
+		default:
+			s.printf("Unknown choice %q.\n", choice)
+		}
+	}
+}
+
+// test prints the Slack message and the GitHub issue of the test and offers
+// the actions of the panels, it returns true when the user quits.
+func (s *plainSession) test(tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestResult) bool {
+	issueTemplate := issue.NewTemplate(tab, currentTest)
+	issueTemplate.Priority = releasePhase.Priority(issue.TestState(tab, currentTest) == v1alpha1.FAILING_STATUS)
+	templateFile, prefixTitle := issue.PickTemplate(issue.TestState(tab, currentTest))
+	title := issue.Title(prefixTitle, currentTest.TestName)
+
+	rendered, err := renderIssue(issueTemplate, templateFile, s.tabs, tab, currentTest)
+	if err != nil {
+		s.printf("Error rendering the issue: %v\n", err)
+		return false
+	}
+	message := slackMessage(rendered.links.Apply(tab, currentTest))
+	frontMatter, body, err := issue.ParseFrontMatter(rendered.body)
+	if err != nil {
+		s.printf("Error rendering the issue: %v\n", err)
+		return false
+	}
+
+	s.printf("\n== Test: %s ==\n", currentTest.TestName)
+	s.printf("Board: %s, %s\n", tab.BoardHash, strings.ToLower(tab.TabState))
+	for _, dead := range rendered.deadLinks {
+		s.printf("Warning: %s\n", dead)
+	}
+	s.printf("\n-- Slack message --\n%s\n-- End of Slack message --\n", message)
+	s.printf("\n-- GitHub issue: %s --\n%s\n-- End of GitHub issue --\n", title, body)
+	if s.token != "" {
+		existing, err := github.NewProjectManager(context.Background(), s.token).FindExistingIssues(currentTest.TestName)
+		switch {
+		case err != nil:
+			s.printf("Error searching the similar issues: %v\n", err)
+		case len(existing) > 0:
+			s.printf("\n-- Similar issues: %d --\n%s\n-- End of similar issues --\n", len(existing), similarIssuesText(existing))
+		}
+	}
+	for {
+		destination, other := "as a draft in the GitHub project", "repository issues"
+		if repositoryIssues {
+			destination = "in " + frontMatter.WithRepository(issue.Repository(tab.BoardHash, currentTest.TestName)).Repo
+			other = "drafts"
+		}
+		s.printf("\n1. Copy the Slack message\n2. Copy the GitHub issue\n3. Create the GitHub issue %s\n4. Switch the issue creation to %s\n",
+			destination, other)
+		actions := 4
+		if slackClient != nil {
+			// the entry is only listed when usable, the others keep their numbers.
+			s.printf("5. Post the Slack message to %s\n", strings.Join(slackChannels(tab, currentTest), " and "))
+			actions = 5
+		}
+		choice, number, ok := s.choose("Enter an action number, b to go back, or q to quit:", actions)
+		switch {
+		case !ok || choice == "q":
+			return true
+		case choice == "b":
+			return false
+		case number == 1:
+			err := CopyToClipboard(message)
+			if err == nil {
+				recordAction(actionSlackCopied)
+			}
+			s.report("Slack message copied to the clipboard.", err)
+		case number == 2:
+			err := CopyToClipboard(body)
+			if err == nil {
+				recordAction(actionIssueCopied)
+			}
+			s.report("GitHub issue copied to the clipboard.", err)
+		case number == 3 && readOnly:
+			// a read-only profile never creates the issue, no need to confirm.
+			s.report("", errReadOnly)
+		case number == 3:
+			if !s.confirm(fmt.Sprintf("Create the issue %q %s? Enter y to confirm:", title, destination)) {
+				s.printf("Issue not created.\n")
+				continue
+			}
+			s.printf("Creating the issue on GitHub...\n")
+			filed, repo, err := fileIssue(s.token, tab, currentTest, title, issueFrontMatter(frontMatter, tab, currentTest), body)
+			switch {
+			case err != nil:
+				s.report("", err)
+			case github.DryRun:
+				s.printf("Dry run, the issue was not sent to GitHub.\n")
+			case filed != nil && !filed.ClosedAt.IsZero():
+				s.printf("Reopened issue #%d, closed on %s.\n", filed.Number, filed.ClosedAt.Format(time.DateOnly))
+			case filed != nil:
+				s.printf("Created issue #%d in %s.\n", filed.Number, repo)
+			default:
+				s.printf("Created a draft issue in the GitHub project.\n")
+			}
+		case number == 4:
+			repositoryIssues = !repositoryIssues
+		case number == 5:
+			s.printf("Posting the message to Slack...\n")
+			channels, followUp, err := postSlackMessages(tab, currentTest, message)
+			switch {
+			case err != nil:
+				s.report("", err)
+			case followUp:
+				s.printf("Posted a follow-up in the thread of the test on %s.\n", channels)
+			default:
+				s.printf("Posted the message on %s.\n", channels)
+			}
+		default:
+			s.printf("Unknown choice %q.\n", choice)
+		}
+	}
+}
+
+// choose prints the prompt and reads a line, returning the lowercased choice
+// and its menu number, zero when it is not a number between 1 and count. It
+// returns false when the input is closed.
+func (s *plainSession) choose(prompt string, count int) (string, int, bool) {
+	s.printf("%s\n", prompt)
+	if !s.in.Scan() {
+		return "", 0, false
+	}
+	choice := strings.ToLower(strings.TrimSpace(s.in.Text()))
+	if number, err := strconv.Atoi(choice); err == nil && number >= 1 && number <= count {
+		return choice, number, true
+	}
+	return choice, 0, true
+}
+
+// confirm prints the prompt and reads a line, returning true when the user
+// answers y or yes.
+func (s *plainSession) confirm(prompt string) bool {
+	s.printf("%s\n", prompt)
+	if !s.in.Scan() {
+		return false
+	}
+	answer := strings.ToLower(strings.TrimSpace(s.in.Text()))
+	return answer == "y" || answer == "yes"
+}
+
+// report prints the message, or the error when the action failed.
+func (s *plainSession) report(message string, err error) {
+	if err != nil {
+		s.printf("Error: %v\n", err)
+		return
+	}
+	s.printf("%s\n", message)
+}
+
+func (s *plainSession) printf(format string, args ...interface{}) {
+	fmt.Fprintf(s.out, format, args...) // nolint
+}
@@ -0,0 +1,168 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/issue"
+	"sigs.k8s.io/signalhound/internal/slack"
+	"sigs.k8s.io/signalhound/internal/state"
+)
+
+func TestRenderPlain(t *testing.T) {
+	tabs := []*v1alpha1.DashboardTab{{
+		BoardHash: "sig-release-master-blocking#gce-cos-master-default", TabState: v1alpha1.FAILING_STATUS, StateIcon: ":large_red_square:",
+		TestRuns: []v1alpha1.TestResult{{TestName: "[sig-node] Pods should run", FailureCount: 3, RunCount: 10}},
+	}}
+	t.Cleanup(func() { repositoryIssues = false })
+
+	var out bytes.Buffer
+	refreshes := 0
+	input := strings.Join([]string{"7", "r", "1", "1", "4", "b", "b", "q", "unread"}, "\n")
+	err := RenderPlain(strings.NewReader(input), &out, tabs, "", func() ([]*v1alpha1.DashboardTab, error) {
+		refreshes++
+		return nil, errors.New("testgrid is down")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, refreshes)
+
+	output := out.String()
+	for _, expected := range []string{
+		"== Boards: 1 failing or flaky ==\n1. sig-release-master-blocking#gce-cos-master-default, failing, 1 tests\n",
+		"Unknown choice \"7\".",
+		"Error refreshing the boards: testgrid is down",
+		"1. [sig-node] Pods should run, 3 of 10 runs failed\n",
+		"-- Slack message --\n:large_red_square: Failing on [sig-release-master-blocking#gce-cos-master-default]",
+		"-- GitHub issue: [Failing Test] [sig-node] Pods should run --\n",
+		"3. Create the GitHub issue as a draft in the GitHub project\n4. Switch the issue creation to repository issues\n",
+		"3. Create the GitHub issue in kubernetes/kubernetes\n4. Switch the issue creation to drafts\n",
+	} {
+		assert.Contains(t, output, expected)
+	}
+	assert.True(t, repositoryIssues)
+
+	// a closed input quits
+	out.Reset()
+	assert.NoError(t, RenderPlain(strings.NewReader("1\n"), &out, tabs, "", nil))
+	assert.Contains(t, out.String(), "Enter a board number, or q to quit:")
+}
+
+func TestRenderPlainConfirmIssue(t *testing.T) {
+	tabs := []*v1alpha1.DashboardTab{{
+		BoardHash: "sig-release-master-blocking#kind-master", TabState: v1alpha1.FAILING_STATUS,
+		TestRuns: []v1alpha1.TestResult{{TestName: "[sig-node] Pods should run"}},
+	}}
+	var out bytes.Buffer
+	assert.NoError(t, RenderPlain(strings.NewReader("1\n1\n3\nn\nq\n"), &out, tabs, "", nil))
+	output := out.String()
+	assert.Contains(t, output, "Create the issue \"[Failing Test] [sig-node] Pods should run\" as a draft in the GitHub project? Enter y to confirm:\n")
+	assert.Contains(t, output, "Issue not created.")
+	assert.NotContains(t, output, "Creating the issue on GitHub")
+}
+
+func TestRenderPlainPostSlack(t *testing.T) {
+	var threads []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "#release-ci-signal", request["channel"])
+		assert.Contains(t, request["text"], "<https://testgrid.k8s.io/sig-release-master-blocking#kind-master|sig-release-master-blocking#kind-master>")
+		threads = append(threads, request["thread_ts"])
+		fmt.Fprint(w, `{"ok": true, "ts": "1700000000.000100"}`) // nolint
+	}))
+	defer server.Close()
+	SetSlackChannel("xoxb-token", "#release-ci-signal")
+	slackClient.URL = server.URL
+	SetStateStore(state.NewFileStore(filepath.Join(t.TempDir(), "state.json")))
+	t.Cleanup(func() {
+		SetSlackChannel("", "")
+		SetStateStore(nil)
+	})
+
+	tabs := []*v1alpha1.DashboardTab{{
+		BoardHash: "sig-release-master-blocking#kind-master", TabState: v1alpha1.FLAKY_STATUS,
+		TabURL:   "https://testgrid.k8s.io/sig-release-master-blocking#kind-master",
+		TestRuns: []v1alpha1.TestResult{{TestName: "[sig-node] Pods should run"}},
+	}}
+	var out bytes.Buffer
+	assert.NoError(t, RenderPlain(strings.NewReader("1\n1\n5\n5\nq\n"), &out, tabs, "", nil))
+	output := out.String()
+	assert.Contains(t, output, "5. Post the Slack message to #release-ci-signal\n")
+	assert.Contains(t, output, "Posted the message on #release-ci-signal.")
+	assert.Contains(t, output, "Posted a follow-up in the thread of the test on #release-ci-signal.")
+	// the second report of the test follows up in the thread of the first
+	assert.Equal(t, []string{"", "1700000000.000100"}, threads)
+}
+
+func TestRenderPlainPostSlackRouting(t *testing.T) {
+	var channels []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		channels = append(channels, request["channel"])
+		fmt.Fprint(w, `{"ok": true, "ts": "1700000000.000100"}`) // nolint
+	}))
+	defer server.Close()
+	SetSlackChannel("xoxb-token", "#release-ci-signal")
+	slackClient.URL = server.URL
+	SetSlackRouting(issue.SlackRoutes{"node": "#sig-node-ci"},
+		map[string]slack.RateLimit{"#sig-node-ci": {Messages: 1, Window: metav1.Duration{Duration: time.Hour}}})
+	t.Cleanup(func() {
+		SetSlackChannel("", "")
+		SetSlackRouting(nil, nil)
+	})
+
+	tabs := []*v1alpha1.DashboardTab{{
+		BoardHash: "sig-release-master-blocking#kind-master", TabState: v1alpha1.FLAKY_STATUS,
+		TestRuns: []v1alpha1.TestResult{{TestName: "[sig-node] Pods should run"}},
+	}}
+	var out bytes.Buffer
+	assert.NoError(t, RenderPlain(strings.NewReader("1\n1\n5\n5\nq\n"), &out, tabs, "", nil))
+	output := out.String()
+	assert.Contains(t, output, "5. Post the Slack message to #release-ci-signal and #sig-node-ci\n")
+	assert.Contains(t, output, "Posted the message on #release-ci-signal and #sig-node-ci.")
+	assert.Contains(t, output, "Error: posted on #release-ci-signal, but rate limit of #sig-node-ci reached, 1 messages per 1h0m0s")
+	assert.Equal(t, []string{"#release-ci-signal", "#sig-node-ci", "#release-ci-signal"}, channels)
+
+	// the failing tests stay on the Slack channel
+	tabs[0].TabState = v1alpha1.FAILING_STATUS
+	assert.Equal(t, []string{"#release-ci-signal"}, slackChannels(tabs[0], &tabs[0].TestRuns[0]))
+}
+
+func TestRenderPlainReadOnly(t *testing.T) {
+	posted := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted++
+		fmt.Fprint(w, `{"ok": true, "ts": "1700000000.000100"}`) // nolint
+	}))
+	defer server.Close()
+	SetSlackChannel("xoxb-token", "#release-ci-signal")
+	slackClient.URL = server.URL
+	SetReadOnly(true)
+	t.Cleanup(func() {
+		SetSlackChannel("", "")
+		SetReadOnly(false)
+	})
+
+	tabs := []*v1alpha1.DashboardTab{{
+		BoardHash: "sig-release-master-blocking#kind-master", TabState: v1alpha1.FLAKY_STATUS,
+		TestRuns: []v1alpha1.TestResult{{TestName: "[sig-node] Pods should run"}},
+	}}
+	var out bytes.Buffer
+	assert.NoError(t, RenderPlain(strings.NewReader("1\n1\n3\n5\nq\n"), &out, tabs, "", nil))
+	assert.Equal(t, 2, strings.Count(out.String(), "Error: "+errReadOnly.Error()))
+	assert.NotContains(t, out.String(), "Enter y to confirm")
+	assert.Zero(t, posted)
+}
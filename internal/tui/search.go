@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+)
+
+const searchPageName = "Search"
+
+var testsSearch string // Search filtering the tests list, set with / and cleared with Esc
+
+// searchRegexp compiles the search, a case-insensitive regular expression or
+// a substring when it isn't a valid one, nil without search.
+func searchRegexp(search string) *regexp.Regexp {
+	if search == "" {
+		return nil
+	}
+	re, err := regexp.Compile("(?i)" + search)
+	if err != nil {
+		re = regexp.MustCompile("(?i)" + regexp.QuoteMeta(search))
+	}
+	return re
+}
+
+// filterTests returns the tests whose name, error message or SIG match the
+// search, all of them without search.
+func filterTests(tests []v1alpha1.TestResult, search string) []v1alpha1.TestResult {
+	re := searchRegexp(search)
+	if re == nil {
+		return tests
+	}
+	var matching []v1alpha1.TestResult
+	for _, test := range tests {
+		if re.MatchString(test.TestName) || re.MatchString(test.ErrorMessage) || re.MatchString(testSIG(&test)) {
+			matching = append(matching, test)
+		}
+	}
+	return matching
+}
+
+// highlightSearch escapes the text for a tview list and highlights the parts
+// matching the search, the text after a match is back in color, e.g. gray
+// or - for the default one.
+func highlightSearch(text, color string) string {
+	re := searchRegexp(testsSearch)
+	if re == nil {
+		return tview.Escape(text)
+	}
+	var highlighted strings.Builder
+	last := 0
+	for _, match := range re.FindAllStringIndex(text, -1) {
+		if match[0] == match[1] {
+			continue
+		}
+		highlighted.WriteString(tview.Escape(text[last:match[0]]))
+		highlighted.WriteString("[yellow::b]" + tview.Escape(text[match[0]:match[1]]) + "[" + color + "::-]")
+		last = match[1]
+	}
+	highlighted.WriteString(tview.Escape(text[last:]))
+	return highlighted.String()
+}
+
+// showSearch opens the search input over the position bar, the tests list is
+// filtered while typing. Enter keeps the search and returns to the list, Esc
+// clears it.
+func showSearch() {
+	input := tview.NewInputField().SetLabel("/").SetText(testsSearch)
+	setPanelDefaultStyle(input.Box)
+	input.SetTitle(formatTitle("Search tests by name, error message or SIG"))
+	input.SetChangedFunc(func(text string) {
+		testsSearch = text
+		renderSearchedTests()
+	})
+	input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+		case tcell.KeyEscape:
+			testsSearch = ""
+			renderSearchedTests()
+		default:
+			return
+		}
+		pages.RemovePage(searchPageName)
+		app.SetFocus(brokenPanel)
+		position.SetText(defaultPositionText)
+	})
+
+	overlay := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(input, 3, 0, true)
+	position.SetText("[green]Type a name, an error or a SIG, a regular expression works, [blue]Enter [green]to keep the search, [blue]Esc [green]to clear it")
+	pages.AddPage(searchPageName, overlay, true, true)
+	app.SetFocus(input)
+}
+
+// renderSearchedTests renders the tests list of the current tab with the
+// search, the title shows it.
+func renderSearchedTests() {
+	if currentTab == nil {
+		brokenPanel.SetTitle(testsTitle())
+		return
+	}
+	renderTestsList(currentTab)
+	brokenPanel.SetCurrentItem(0)
+}
@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+)
+
+func TestFilterTests(t *testing.T) {
+	tests := []v1alpha1.TestResult{
+		{TestName: "[sig-node] Pods should run"},
+		{TestName: "kubetest.Up", ErrorMessage: "error creating cluster: quota exceeded"},
+		{TestName: "Kubelet restarts", Sig: "node"},
+	}
+	names := func(tests []v1alpha1.TestResult) (names []string) {
+		for _, test := range tests {
+			names = append(names, test.TestName)
+		}
+		return names
+	}
+	assert.Len(t, filterTests(tests, ""), 3)
+	assert.Equal(t, []string{"[sig-node] Pods should run", "Kubelet restarts"}, names(filterTests(tests, "NODE")))
+	assert.Equal(t, []string{"kubetest.Up"}, names(filterTests(tests, "quota")))
+	assert.Equal(t, []string{"[sig-node] Pods should run", "Kubelet restarts"}, names(filterTests(tests, "^(\\[sig-node\\]|kubelet)")))
+	// an invalid regular expression is a substring
+	assert.Equal(t, []string{"[sig-node] Pods should run"}, names(filterTests(tests, "[sig-node")))
+	assert.Empty(t, filterTests(tests, "storage"))
+}
+
+func TestHighlightSearch(t *testing.T) {
+	defer func() { testsSearch = "" }()
+	assert.Equal(t, "[sig-node[] Pods", highlightSearch("[sig-node] Pods", "-"))
+
+	testsSearch = "pods"
+	assert.Equal(t, "[sig-node[] [yellow::b]Pods[-::-] should run", highlightSearch("[sig-node] Pods should run", "-"))
+	assert.Equal(t, "[gray][yellow::b]Pods[gray::-] should run (skipped in job config)[-]",
+		testItemText(v1alpha1.TestResult{TestName: "Pods should run", Skipped: true}, nil))
+	assert.Equal(t, formatTitle("Tests - sort: [yellow]last failure[-] - search: [yellow]pods[-]"), testsTitle())
+}
@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// pendingTimeout bounds the wait for the pending actions once the UI exits.
+const pendingTimeout = 30 * time.Second
+
+var (
+	pendingActions sync.WaitGroup // Issues and Slack messages being sent in background
+	pendingCount   atomic.Int32   // Number of pendingActions, for the exit message
+)
+
+// trackAction counts an issue or a Slack message sent in background until
+// the returned func is called, so exiting the UI doesn't drop it. The func is
+// called before queueing the UI update, which blocks once the UI is stopped.
+func trackAction() func() {
+	pendingActions.Add(1)
+	pendingCount.Add(1)
+	return func() {
+		pendingCount.Add(-1)
+		pendingActions.Done()
+	}
+}
+
+// waitPendingActions waits up to the timeout for the actions still being
+// sent when the UI exits, printing their number to w. It returns false when
+// the wait timed out.
+func waitPendingActions(w io.Writer, timeout time.Duration) bool {
+	if count := pendingCount.Load(); count > 0 {
+		fmt.Fprintf(w, "waiting for %d pending actions to be sent\n", count) // nolint
+	}
+	done := make(chan struct{})
+	go func() {
+		pendingActions.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		fmt.Fprintf(w, "%d pending actions not sent\n", pendingCount.Load()) // nolint
+		return false
+	}
+}
@@ -0,0 +1,27 @@
+package tui
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitPendingActions(t *testing.T) {
+	var out bytes.Buffer
+	assert.True(t, waitPendingActions(&out, time.Second))
+	assert.Empty(t, out.String())
+
+	done := trackAction()
+	assert.False(t, waitPendingActions(&out, 10*time.Millisecond))
+	assert.Equal(t, "waiting for 1 pending actions to be sent\n1 pending actions not sent\n", out.String())
+
+	out.Reset()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		done()
+	}()
+	assert.True(t, waitPendingActions(&out, time.Second))
+	assert.Equal(t, "waiting for 1 pending actions to be sent\n", out.String())
+}
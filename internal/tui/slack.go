@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/issue"
+	"sigs.k8s.io/signalhound/internal/slack"
+	"sigs.k8s.io/signalhound/internal/state"
+)
+
+var (
+	slackClient  *slack.Client     // Posts the Slack messages on Ctrl-B, nil disables it
+	slackChannel string            // Channel the Slack messages are posted to
+	slackRoutes  issue.SlackRoutes // Channels of the SIGs the flaky test messages are posted to too
+	slackLimiter *slack.Limiter    // Rate limits of the channels, nil disables them
+	postingSlack bool              // A Slack post is running in background
+)
+
+// errSlackDisabled is returned when posting without a bot token or channel.
+var errSlackDisabled = errors.New("set slack.botToken and --slack-channel to post to Slack")
+
+// SetSlackChannel sets the channel the Slack messages are posted to with the
+// bot token, an empty token or channel disables the posting.
+func SetSlackChannel(token, channel string) {
+	slackClient, slackChannel = nil, channel
+	if token != "" && channel != "" {
+		slackClient = slack.NewClient(token)
+	}
+}
+
+// SetSlackRouting sets the channels of the SIGs the messages of the flaky
+// tests are posted to too, and the rate limits of the channels.
+func SetSlackRouting(routes issue.SlackRoutes, limits map[string]slack.RateLimit) {
+	slackRoutes, slackLimiter = routes, nil
+	if len(limits) > 0 {
+		slackLimiter = slack.NewLimiter(limits)
+	}
+}
+
+// slackChannels returns the channels the message of the test is posted to,
+// the Slack channel then the channel of the SIG owning a flaky test.
+func slackChannels(tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestResult) []string {
+	channels := []string{slackChannel}
+	if routed := slackRoutes.For(tab, currentTest); routed != "" && routed != slackChannel {
+		channels = append(channels, routed)
+	}
+	return channels
+}
+
+// slackTitle returns the Slack panel title with the Ctrl-B channel.
+func slackTitle() string {
+	if slackClient == nil {
+		return formatTitle("Slack Message")
+	}
+	return formatTitle(fmt.Sprintf("Slack Message - [yellow]%s[-]", slackChannel))
+}
+
+// postSlackMessages posts the message of the test to its Slack channels, see
+// slackChannels, stopping at the first error. It returns the channels posted
+// to, e.g. "#release-ci-signal and #sig-node-ci", and true when the message
+// followed up in the thread of the test on every channel.
+func postSlackMessages(tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestResult, message string) (string, bool, error) {
+	if readOnly {
+		return "", false, errReadOnly
+	}
+	var posted []string
+	followUp := true
+	for _, channel := range slackChannels(tab, currentTest) {
+		threaded, err := postSlackMessage(channel, currentTest.TestName, message)
+		if err != nil && len(posted) > 0 {
+			return "", false, fmt.Errorf("posted on %s, but %v", strings.Join(posted, " and "), err)
+		} else if err != nil {
+			return "", false, err
+		}
+		posted = append(posted, channel)
+		followUp = followUp && threaded
+		recordAction(actionSlackPosted)
+	}
+	return strings.Join(posted, " and "), followUp, nil
+}
+
+// postSlackMessage posts the message of the test to the channel, in the
+// thread of the previous report of the test kept in the state store. It
+// returns true when the message followed up in a thread.
+func postSlackMessage(channel, testName, message string) (bool, error) {
+	if slackClient == nil {
+		return false, errSlackDisabled
+	}
+	threadTS := ""
+	if stateStore != nil {
+		current, err := stateStore.Load()
+		if err != nil {
+			return false, err
+		}
+		threadTS = current.SlackThread(testName, channel)
+	}
+	if err := slackLimiter.Allow(channel, time.Now()); err != nil {
+		return false, err
+	}
+	ts, err := slackClient.PostMessage(channel, message, threadTS)
+	if err != nil || threadTS != "" || stateStore == nil {
+		return threadTS != "", err
+	}
+	if _, err = state.Update(stateStore, func(current *state.State) {
+		current.SetSlackThread(testName, channel, ts)
+	}); err != nil {
+		// the message is posted, only the next report starts a new thread.
+		return false, fmt.Errorf("posted, but error saving the Slack thread: %v", err)
+	}
+	return false, nil
+}
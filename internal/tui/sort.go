@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/rivo/tview"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/sig"
+)
+
+// sortMode is the order of the tests list, cycled with the s key.
+type sortMode int
+
+const (
+	sortByLastFailure sortMode = iota
+	sortByFlakeScore
+	sortByTestGrid
+	sortByName
+	sortByConsecutiveFailures
+	sortBySIG
+	sortModes
+)
+
+var testsSortMode = sortByLastFailure // Current order of the tests list, the most recent failure first
+
+// String returns the label shown in the tests panel title.
+func (m sortMode) String() string {
+	switch m {
+	case sortByFlakeScore:
+		return "flake score"
+	case sortByTestGrid:
+		return "testgrid"
+	case sortByName:
+		return "name"
+	case sortByConsecutiveFailures:
+		return "consecutive failures"
+	case sortBySIG:
+		return "SIG"
+	}
+	return "last failure"
+}
+
+// next returns the following sort mode, wrapping to the last failure.
+func (m sortMode) next() sortMode {
+	return (m + 1) % sortModes
+}
+
+// testsTitle returns the tests panel title with the active sort and search.
+func testsTitle() string {
+	title := fmt.Sprintf("Tests - sort: [yellow]%s[-]", testsSortMode)
+	if testsSearch != "" {
+		title += fmt.Sprintf(" - search: [yellow]%s[-]", tview.Escape(testsSearch))
+	}
+	if len(markedTests) > 0 {
+		title += fmt.Sprintf(" - [blue]%d marked[-], Ctrl-B to create their issues", len(markedTests))
+	}
+	return formatTitle(title)
+}
+
+// testSIG returns the SIG of the test, read from its name when it wasn't
+// inferred by the fetcher, or an empty string.
+func testSIG(test *v1alpha1.TestResult) string {
+	if test.Sig != "" {
+		return test.Sig
+	}
+	return sig.FromTestName(test.TestName)
+}
+
+// sortTests returns a copy of the tests in the sort mode order, scores, times
+// and streaks are sorted descending and ties keep the TestGrid order.
+func sortTests(tests []v1alpha1.TestResult, mode sortMode) []v1alpha1.TestResult {
+	sorted := append([]v1alpha1.TestResult(nil), tests...)
+	var less func(a, b *v1alpha1.TestResult) bool
+	switch mode {
+	case sortByName:
+		less = func(a, b *v1alpha1.TestResult) bool { return a.TestName < b.TestName }
+	case sortByFlakeScore:
+		less = func(a, b *v1alpha1.TestResult) bool { return a.FlakeScore > b.FlakeScore }
+	case sortByLastFailure:
+		less = func(a, b *v1alpha1.TestResult) bool { return a.LastFailureTimestamp > b.LastFailureTimestamp }
+	case sortByConsecutiveFailures:
+		less = func(a, b *v1alpha1.TestResult) bool { return a.ConsecutiveFailures > b.ConsecutiveFailures }
+	case sortBySIG:
+		// tests without SIG go last
+		less = func(a, b *v1alpha1.TestResult) bool {
+			sigA, sigB := testSIG(a), testSIG(b)
+			if sigA == "" || sigB == "" {
+				return sigB == "" && sigA != ""
+			}
+			return sigA < sigB
+		}
+	default:
+		return sorted
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return less(&sorted[i], &sorted[j]) })
+	return sorted
+}
@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+)
+
+func TestSortTests(t *testing.T) {
+	tests := []v1alpha1.TestResult{
+		{TestName: "[sig-storage] b", FlakeScore: 20, LastFailureTimestamp: 300, ConsecutiveFailures: 1},
+		{TestName: "kubetest.Up", FlakeScore: 50, LastFailureTimestamp: 100, ConsecutiveFailures: 5},
+		{TestName: "[sig-node] c", FlakeScore: 25, LastFailureTimestamp: 200},
+	}
+	cases := []struct {
+		mode sortMode
+		want []string
+	}{
+		{mode: sortByLastFailure, want: []string{"[sig-storage] b", "[sig-node] c", "kubetest.Up"}},
+		{mode: sortByFlakeScore, want: []string{"kubetest.Up", "[sig-node] c", "[sig-storage] b"}},
+		{mode: sortByTestGrid, want: []string{"[sig-storage] b", "kubetest.Up", "[sig-node] c"}},
+		{mode: sortByName, want: []string{"[sig-node] c", "[sig-storage] b", "kubetest.Up"}},
+		{mode: sortByConsecutiveFailures, want: []string{"kubetest.Up", "[sig-storage] b", "[sig-node] c"}},
+		{mode: sortBySIG, want: []string{"[sig-node] c", "[sig-storage] b", "kubetest.Up"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.mode.String(), func(t *testing.T) {
+			var names []string
+			for _, test := range sortTests(tests, tc.mode) {
+				names = append(names, test.TestName)
+			}
+			assert.Equal(t, tc.want, names)
+		})
+	}
+	assert.Equal(t, "[sig-storage] b", tests[0].TestName, "the input is not reordered")
+	assert.Equal(t, sortByLastFailure, sortBySIG.next())
+	assert.Equal(t, sortByLastFailure, testsSortMode, "the most recent failure comes first by default")
+	assert.Equal(t, formatTitle("Tests - sort: [yellow]last failure[-]"), testsTitle())
+}
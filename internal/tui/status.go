@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/rivo/tview"
+
+	"sigs.k8s.io/signalhound/internal/doctor"
+	"sigs.k8s.io/signalhound/internal/github"
+)
+
+// lowRateLimit is the share of the GitHub rate limit under which the status
+// bar warns, the board queries cost several points each.
+const lowRateLimit = 0.1
+
+var (
+	statusBar     = tview.NewTextView()
+	mcpURL        string       // MCP server checked by the status bar, empty to leave it out
+	fetchLatency  atomic.Int64 // Duration of the latest TestGrid fetch, see SetFetchLatency
+	loadingStatus atomic.Bool  // The status bar checks are running
+)
+
+// SetMCPURL sets the signalhound mcp --http server the status bar checks.
+func SetMCPURL(url string) {
+	mcpURL = url
+}
+
+// SetFetchLatency records the duration of the latest TestGrid fetch shown by
+// the status bar, safe to call from the refresh goroutine.
+func SetFetchLatency(latency time.Duration) {
+	fetchLatency.Store(int64(latency))
+}
+
+// health is the state of the services shown in the status bar.
+type health struct {
+	// DryRun is set when the GitHub mutations are skipped, see github.DryRun.
+	DryRun bool
+
+	// RateLimit is nil when GitHubErr is set or without token.
+	RateLimit *github.RateLimit
+	GitHubErr error
+	HasToken  bool
+
+	// MCP is the check of the MCP server, Skipped without URL.
+	MCP doctor.Result
+
+	FetchLatency time.Duration
+}
+
+// statusText renders the status bar, e.g. GitHub 4200/5000 │ MCP up │
+// TestGrid 1.2s, in red what makes the operations fail and in yellow what
+// makes them slow.
+func statusText(h health, now time.Time) string {
+	var items []string
+	if h.DryRun {
+		items = append(items, "[yellow]DRY RUN[-]")
+	}
+	switch {
+	case !h.HasToken:
+		items = append(items, "GitHub [yellow]no token[-]")
+	case h.GitHubErr != nil && strings.Contains(h.GitHubErr.Error(), "401"):
+		items = append(items, "GitHub [red]invalid token[-]")
+	case h.GitHubErr != nil:
+		items = append(items, "GitHub [red]unreachable[-]")
+	case h.RateLimit != nil:
+		color := "green"
+		if h.RateLimit.Remaining == 0 {
+			color = "red"
+		} else if float64(h.RateLimit.Remaining) < lowRateLimit*float64(h.RateLimit.Limit) {
+			color = "yellow"
+		}
+		item := fmt.Sprintf("GitHub [%s]%d/%d[-]", color, h.RateLimit.Remaining, h.RateLimit.Limit)
+		if color != "green" && h.RateLimit.ResetAt.After(now) {
+			item += fmt.Sprintf(" resets in %s", h.RateLimit.ResetAt.Sub(now).Round(time.Minute))
+		}
+		items = append(items, item)
+	}
+	switch h.MCP.Status {
+	case doctor.OK:
+		items = append(items, "MCP [green]up[-]")
+	case doctor.Failed, doctor.Warning:
+		items = append(items, "MCP [red]down[-]")
+	}
+	if h.FetchLatency > 0 {
+		color := "green"
+		if h.FetchLatency > 30*time.Second {
+			color = "yellow"
+		}
+		items = append(items, fmt.Sprintf("TestGrid [%s]%s[-]", color, h.FetchLatency.Round(100*time.Millisecond)))
+	}
+	return " " + strings.Join(items, " │ ")
+}
+
+// refreshStatus checks in background the GitHub rate limit and token and the
+// MCP server, and renders the status bar with the latest fetch latency.
+func refreshStatus() {
+	if !loadingStatus.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer recoverCrash()
+		defer loadingStatus.Store(false)
+		h := health{DryRun: github.DryRun, HasToken: githubToken != "", FetchLatency: time.Duration(fetchLatency.Load())}
+		if h.HasToken {
+			h.RateLimit, h.GitHubErr = github.NewProjectManager(context.Background(), githubToken).RateLimit()
+		}
+		h.MCP = doctor.Result{Status: doctor.Skipped}
+		if mcpURL != "" {
+			h.MCP = doctor.CheckMCP(context.Background(), mcpURL, githubToken)
+		}
+		app.QueueUpdateDraw(func() {
+			statusBar.SetText(statusText(h, time.Now()))
+		})
+	}()
+}
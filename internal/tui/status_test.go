@@ -0,0 +1,29 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/signalhound/internal/doctor"
+	"sigs.k8s.io/signalhound/internal/github"
+)
+
+func TestStatusText(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	skipped := doctor.Result{Status: doctor.Skipped}
+
+	assert.Equal(t, " GitHub [green]4200/5000[-] │ MCP [green]up[-] │ TestGrid [green]1.2s[-]", statusText(health{HasToken: true,
+		RateLimit: &github.RateLimit{Limit: 5000, Remaining: 4200}, MCP: doctor.Result{Status: doctor.OK}, FetchLatency: 1234 * time.Millisecond}, now))
+	assert.Equal(t, " GitHub [yellow]120/5000[-] resets in 25m0s │ MCP [red]down[-]", statusText(health{HasToken: true,
+		RateLimit: &github.RateLimit{Limit: 5000, Remaining: 120, ResetAt: now.Add(25 * time.Minute)}, MCP: doctor.Result{Status: doctor.Failed}}, now))
+	assert.Equal(t, " GitHub [red]0/5000[-] │ TestGrid [yellow]45s[-]", statusText(health{HasToken: true,
+		RateLimit: &github.RateLimit{Limit: 5000}, MCP: skipped, FetchLatency: 45 * time.Second}, now))
+	assert.Equal(t, " GitHub [red]invalid token[-]", statusText(health{HasToken: true,
+		GitHubErr: errors.New("non-200 OK status code: 401 Unauthorized"), MCP: skipped}, now))
+	assert.Equal(t, " GitHub [red]unreachable[-]", statusText(health{HasToken: true, GitHubErr: errors.New("dial tcp: timeout"), MCP: skipped}, now))
+	assert.Equal(t, " GitHub [yellow]no token[-]", statusText(health{MCP: skipped}, now))
+	assert.Equal(t, " [yellow]DRY RUN[-] │ GitHub [yellow]no token[-]", statusText(health{DryRun: true, MCP: skipped}, now))
+}
@@ -0,0 +1,191 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/state"
+)
+
+const (
+	maintenancePageName = "Maintenance"
+
+	// snoozeDuration is the time a test is snoozed for with the z key.
+	snoozeDuration = 7 * 24 * time.Hour
+)
+
+// listedTests are the tests of the tests panel, in the listed order.
+var listedTests []v1alpha1.TestResult
+
+// loadState returns the state of the store, nil without a store or on
+// errors, reported on the position bar.
+func loadState() *state.State {
+	if stateStore == nil {
+		return nil
+	}
+	current, err := stateStore.Load()
+	if err != nil {
+		position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
+		return nil
+	}
+	return current
+}
+
+// suppressSelectedTest snoozes, acknowledges or lifts the suppression of the
+// test selected in the tests panel, z, a and u keys.
+func suppressSelectedTest(key rune) {
+	if stateStore == nil {
+		position.SetText("[red]error: no state store configured")
+		return
+	}
+	index := brokenPanel.GetCurrentItem()
+	if currentTab == nil || index < 0 || index >= len(listedTests) {
+		return
+	}
+	test := listedTests[index]
+	var message, action string
+	if _, err := state.Update(stateStore, func(current *state.State) {
+		switch key {
+		case 'z':
+			until := time.Now().Add(snoozeDuration)
+			current.Snooze(test.TestName, currentTab.TabState, until, annotationAuthor())
+			message = fmt.Sprintf("[blue]Snoozed [yellow]%s [blue]until %s", tview.Escape(test.TestName), until.Format(time.DateOnly))
+			action = actionSnoozed
+		case 'a':
+			current.Acknowledge(test.TestName, currentTab.TabState, annotationAuthor())
+			message = fmt.Sprintf("[blue]Acknowledged [yellow]%s", tview.Escape(test.TestName))
+			action = actionAcknowledged
+		case 'u':
+			current.Unsuppress(test.TestName)
+			message = fmt.Sprintf("[blue]Lifted the suppression of [yellow]%s", tview.Escape(test.TestName))
+			action = actionUnsuppressed
+		}
+	}); err != nil {
+		position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
+		return
+	}
+	recordAction(action)
+	renderTestsList(currentTab)
+	brokenPanel.SetCurrentItem(index)
+	position.SetText(message)
+}
+
+// driftItemText returns the maintenance list entry of a drifted suppression.
+func driftItemText(drift state.Drift, selected bool) string {
+	box := "[ ]"
+	if selected {
+		box = "[x]"
+	}
+	return tview.Escape(fmt.Sprintf("%s %s: %s", box, drift.TestName, drift))
+}
+
+// showMaintenancePage lists the snoozed and acknowledged tests whose signal
+// changed since, Space selects a test, A selects them all, u lifts the
+// suppression of the selected tests and e escalates them: the suppression is
+// lifted and the change noted on the test. Esc returns to the main page.
+func showMaintenancePage() {
+	current := loadState()
+	if current == nil {
+		if stateStore == nil {
+			position.SetText("[red]error: no state store configured")
+		}
+		return
+	}
+	drifts := current.Drifted(currentTabs, time.Now())
+	if len(drifts) == 0 {
+		position.SetText("[green]Every snoozed and acknowledged test matches the current signal")
+		return
+	}
+
+	selected := make([]bool, len(drifts))
+	list := tview.NewList().ShowSecondaryText(false)
+	setPanelDefaultStyle(list.Box)
+	list.SetSelectedBackgroundColor(tcell.ColorBlue)
+	list.SetHighlightFullLine(true)
+	list.SetMainTextStyle(tcell.StyleDefault)
+	list.SetTitle(formatTitle(fmt.Sprintf("Maintenance - %d suppressions to revisit", len(drifts))))
+	for _, drift := range drifts {
+		list.AddItem(driftItemText(drift, false), "", 0, nil)
+	}
+	toggle := func(i int, value bool) {
+		selected[i] = value
+		list.SetItemText(i, driftItemText(drifts[i], value), "")
+	}
+	apply := func(escalate bool) {
+		var applied []state.Drift
+		for i, drift := range drifts {
+			if selected[i] {
+				applied = append(applied, drift)
+			}
+		}
+		if len(applied) == 0 {
+			position.SetText("[yellow]Select tests with [blue]Space [yellow]or [blue]A [yellow]first")
+			return
+		}
+		if _, err := state.Update(stateStore, func(current *state.State) {
+			for _, drift := range applied {
+				current.Unsuppress(drift.TestName)
+				if escalate {
+					current.Annotate(drift.TestName, "escalated: "+drift.String(), annotationAuthor())
+				}
+			}
+		}); err != nil {
+			position.SetText(fmt.Sprintf("[red]error: %v", err.Error()))
+			return
+		}
+		closeMaintenancePage()
+		action := "Unsnoozed"
+		if escalate {
+			action = "Escalated"
+		}
+		position.SetText(fmt.Sprintf("[blue]%s [yellow]%d [blue]tests", action, len(applied)))
+	}
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape:
+			closeMaintenancePage()
+			return nil
+		case event.Key() != tcell.KeyRune:
+			return event
+		}
+		switch event.Rune() {
+		case ' ':
+			i := list.GetCurrentItem()
+			toggle(i, !selected[i])
+		case 'A':
+			for i := range drifts {
+				toggle(i, true)
+			}
+		case 'u':
+			apply(false)
+		case 'e':
+			apply(true)
+		case 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, event.Modifiers())
+		case 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, event.Modifiers())
+		default:
+			return event
+		}
+		return nil
+	})
+
+	position.SetText("[blue]Space [green]selects, [blue]A [green]selects all, [blue]u [green]unsnoozes, [blue]e [green]escalates, [blue]Esc [green]returns")
+	pages.AddAndSwitchToPage(maintenancePageName, list, true)
+	app.SetFocus(list)
+}
+
+// closeMaintenancePage returns to the main page with the tests panel
+// showing the lifted suppressions.
+func closeMaintenancePage() {
+	pages.RemovePage(maintenancePageName)
+	pages.SwitchToPage(pagesName)
+	app.SetFocus(tabsPanel)
+	position.SetText(defaultPositionText)
+	if currentTab != nil {
+		renderTestsList(currentTab)
+	}
+}
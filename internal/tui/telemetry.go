@@ -0,0 +1,34 @@
+package tui
+
+import "sigs.k8s.io/signalhound/internal/monitoring"
+
+// Actions counted by the opt-in telemetry, the tests and the users they are
+// taken for are never recorded.
+const (
+	actionIssueCreated  = "issue_created"
+	actionIssueReopened = "issue_reopened"
+	actionDraftCreated  = "draft_created"
+	actionIssueCopied   = "issue_copied"
+	actionSlackCopied   = "slack_copied"
+	actionSlackPosted   = "slack_posted"
+	actionSnoozed       = "snoozed"
+	actionAcknowledged  = "acknowledged"
+	actionUnsuppressed  = "unsuppressed"
+	actionAnnotated     = "annotated"
+	actionLinkOpened    = "link_opened"
+)
+
+var actionTelemetry bool // Counts the actions taken in the UI, opt-in
+
+// SetActionTelemetry enables the counts of the UI actions, exported by the
+// monitoring metrics.
+func SetActionTelemetry(enabled bool) {
+	actionTelemetry = enabled
+}
+
+// recordAction counts the action when the telemetry is enabled.
+func recordAction(action string) {
+	if actionTelemetry {
+		monitoring.RecordUserAction(action)
+	}
+}
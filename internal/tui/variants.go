@@ -0,0 +1,29 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/rivo/tview"
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/issue"
+	"sigs.k8s.io/signalhound/internal/platform"
+)
+
+// platformVariants returns the other platform variants of the suite of the
+// test among the loaded tabs, and the variants the test fails on.
+func platformVariants(tabs []*v1alpha1.DashboardTab, tab *v1alpha1.DashboardTab, currentTest *v1alpha1.TestResult) (variants []issue.Variant, hint string) {
+	occurrences := platform.NewIndex(tabs).Variants(tab, currentTest)
+	for _, occurrence := range occurrences {
+		variants = append(variants, issue.Variant{Variant: occurrence.Variant, BoardHash: occurrence.BoardHash, URL: occurrence.TabURL, Failing: occurrence.Failing})
+	}
+	return variants, platform.Hint(platform.Variant(tab), occurrences)
+}
+
+// variantsText returns the position bar text naming the variants the test
+// fails on, or an empty string.
+func variantsText(variants []issue.Variant, hint string) string {
+	if len(variants) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("[yellow]Platform variants: %s", tview.Escape(hint))
+}
@@ -0,0 +1,30 @@
+package pipeline
+
+import (
+	"sort"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+)
+
+// TabAnalyzer flattens the tabs into findings, failing tabs first.
+type TabAnalyzer struct{}
+
+// NewAnalyzer returns the default Analyzer.
+func NewAnalyzer() *TabAnalyzer {
+	return &TabAnalyzer{}
+}
+
+// Analyze returns one finding per test, ordered by tab state and keeping the
+// TestGrid ordering inside each tab.
+func (a *TabAnalyzer) Analyze(tabs []*v1alpha1.DashboardTab) []*Finding {
+	var findings []*Finding
+	for _, tab := range tabs {
+		for i := range tab.TestRuns {
+			findings = append(findings, &Finding{Tab: tab, Test: &tab.TestRuns[i]})
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Failing() && !findings[j].Failing()
+	})
+	return findings
+}
@@ -0,0 +1,46 @@
+package pipeline
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/issue"
+	"sigs.k8s.io/signalhound/internal/state"
+)
+
+// ProjectAuditor looks up the issues filed for past failures, to find the
+// failures nobody filed an issue for.
+type ProjectAuditor struct {
+	manager github.ProjectManagerInterface
+
+	// State holds the issues linked to the tests by hand, nil skips them.
+	State *state.State
+}
+
+// NewProjectAuditor returns an auditor of the project board and the
+// repositories authenticated with the GitHub token.
+func NewProjectAuditor(ctx context.Context, token string) *ProjectAuditor {
+	return &ProjectAuditor{manager: github.NewProjectManager(ctx, token)}
+}
+
+// Filed returns the issue filed for the test of the board: an open or draft
+// issue similar to the test, or an issue of its repository, see
+// issue.Repository, closed since the time, or the issue linked to the test.
+// A miss returns nil.
+func (a *ProjectAuditor) Filed(testName, boardHash string, since time.Time) (*Issue, error) {
+	if link, ok := a.State.IssueLink(testName); ok {
+		linked := link.Issue()
+		return &linked, nil
+	}
+	existing, err := a.manager.FindExistingIssues(testName)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return &existing[0], nil
+	}
+	owner, repo, _ := strings.Cut(issue.Repository(boardHash, testName), "/")
+	return a.manager.FindClosedIssue(owner, repo, testName, since)
+}
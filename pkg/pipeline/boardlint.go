@@ -0,0 +1,127 @@
+package pipeline
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/signalhound/internal/github"
+)
+
+var (
+	// sigCommandRegex matches the /sig command of the issue templates.
+	sigCommandRegex = regexp.MustCompile(`(?m)^/sig \S+`)
+
+	// reasonRegex matches the error excerpt section of the issue templates,
+	// up to the next section.
+	reasonRegex = regexp.MustCompile(`(?s)### Reason for failure[^\n]*\n(.*?)(?:\n###|$)`)
+)
+
+// IssueLint is a board issue with the problems found by LintBoard.
+type IssueLint struct {
+	Issue    github.BoardIssue
+	Problems []string
+
+	// Score is the share of the checks the issue passes, in percent.
+	Score int
+}
+
+// BoardLint is the fix-list of the board issues, the worst first.
+type BoardLint struct {
+	Linted int
+	Issues []IssueLint
+}
+
+// lintCheck is a check of LintIssue, with the problem reported on failure.
+type lintCheck struct {
+	ok      bool
+	problem string
+}
+
+// LintBoard checks every board issue for the sections of the issue
+// templates, links, SIG and error excerpt, for its last activity within the
+// stale period, and the repository issues for their kind/ and sig/ labels.
+// The issues passing every check are left out of the fix-list.
+func LintBoard(issues []github.BoardIssue, now time.Time, stale time.Duration) *BoardLint {
+	lint := &BoardLint{Linted: len(issues)}
+	for _, boardIssue := range issues {
+		result := LintIssue(boardIssue, now, stale)
+		if len(result.Problems) > 0 {
+			lint.Issues = append(lint.Issues, result)
+		}
+	}
+	sort.SliceStable(lint.Issues, func(i, j int) bool {
+		if lint.Issues[i].Score != lint.Issues[j].Score {
+			return lint.Issues[i].Score < lint.Issues[j].Score
+		}
+		return lint.Issues[i].Issue.Title < lint.Issues[j].Issue.Title
+	})
+	return lint
+}
+
+// LintIssue checks a board issue, see LintBoard. Drafts have no labels nor
+// comments, their labels aren't checked and their activity is their last
+// edit.
+func LintIssue(boardIssue github.BoardIssue, now time.Time, stale time.Duration) IssueLint {
+	body := boardIssue.Body
+	hasLabel := func(prefix string) bool {
+		return slices.ContainsFunc(boardIssue.Labels, func(label string) bool { return strings.HasPrefix(label, prefix) })
+	}
+	checks := []lintCheck{
+		{strings.Contains(body, "testgrid.k8s.io"), "missing TestGrid link"},
+		{strings.Contains(body, "prow.k8s.io"), "missing Prow link"},
+		{sigCommandRegex.MatchString(body) || hasLabel("sig/"), "missing SIG"},
+		{hasErrorExcerpt(body), "missing error excerpt"},
+		{now.Sub(boardIssue.LastActivity) <= stale,
+			fmt.Sprintf("no activity for %d days", int(now.Sub(boardIssue.LastActivity).Hours()/24))},
+	}
+	if !boardIssue.Draft {
+		checks = append(checks,
+			lintCheck{hasLabel("kind/"), "missing kind/ label"},
+			lintCheck{hasLabel("sig/"), "missing sig/ label"})
+	}
+
+	result := IssueLint{Issue: boardIssue}
+	passed := 0
+	for _, check := range checks {
+		if check.ok {
+			passed++
+			continue
+		}
+		result.Problems = append(result.Problems, check.problem)
+	}
+	result.Score = 100 * passed / len(checks)
+	return result
+}
+
+// hasErrorExcerpt returns true when the reason for failure section of the
+// issue has text besides its code fences.
+func hasErrorExcerpt(body string) bool {
+	match := reasonRegex.FindStringSubmatch(body)
+	return match != nil && strings.Trim(match[1], "` \t\r\n") != ""
+}
+
+// Markdown renders the fix-list, one line per issue with its score and
+// problems.
+func (l *BoardLint) Markdown() string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "# Board lint, %d issues, %d to fix\n\n", l.Linted, len(l.Issues))
+	if len(l.Issues) == 0 {
+		text.WriteString("None.\n")
+	}
+	for _, result := range l.Issues {
+		boardIssue := result.Issue
+		name := fmt.Sprintf("draft %s", boardIssue.Title)
+		if !boardIssue.Draft {
+			name = fmt.Sprintf("[#%d %s](%s)", boardIssue.Number, boardIssue.Title, boardIssue.URL)
+		}
+		if boardIssue.Status != "" {
+			name += ", " + boardIssue.Status
+		}
+		fmt.Fprintf(&text, "* %d%% %s: %s\n", result.Score, name, strings.Join(result.Problems, "; "))
+	}
+	return text.String()
+}
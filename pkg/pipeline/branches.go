@@ -0,0 +1,104 @@
+package pipeline
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/fingerprint"
+)
+
+// BranchFailure is a failing or flaky test of the compared boards, with the
+// tabs it is red on for each side.
+type BranchFailure struct {
+	TestName string
+
+	MasterTabs []string
+	BranchTabs []string
+}
+
+// BranchDiff is the signal of the master boards compared to the boards of a
+// release branch. The tabs of the two sides run different jobs, the tests are
+// matched by name.
+type BranchDiff struct {
+	Branch string
+
+	// OnlyBranch are red on the release branch only, a fix of master missing
+	// a cherry-pick or a branch regression.
+	OnlyBranch []BranchFailure
+	// OnlyMaster are red on master only, a regression not branched yet or a
+	// fix to cherry-pick once landed.
+	OnlyMaster []BranchFailure
+	// Both are red on both sides.
+	Both []BranchFailure
+}
+
+// CompareBranches diffs the failing and flaky tests of the master tabs with
+// the ones of the tabs of the release branch, see fingerprint.Branch. The
+// tabs of other branches are ignored.
+func CompareBranches(branch string, tabs []*v1alpha1.DashboardTab) *BranchDiff {
+	failures := map[string]*BranchFailure{}
+	for _, tab := range tabs {
+		tabBranch := fingerprint.Branch(tab.BoardHash)
+		if tabBranch != fingerprint.Master && tabBranch != branch {
+			continue
+		}
+		for _, test := range tab.TestRuns {
+			failure, ok := failures[test.TestName]
+			if !ok {
+				failure = &BranchFailure{TestName: test.TestName}
+				failures[test.TestName] = failure
+			}
+			switch {
+			case tabBranch == fingerprint.Master && !slices.Contains(failure.MasterTabs, tab.BoardHash):
+				failure.MasterTabs = append(failure.MasterTabs, tab.BoardHash)
+			case tabBranch == branch && !slices.Contains(failure.BranchTabs, tab.BoardHash):
+				failure.BranchTabs = append(failure.BranchTabs, tab.BoardHash)
+			}
+		}
+	}
+
+	diff := &BranchDiff{Branch: branch}
+	for _, failure := range failures {
+		switch {
+		case len(failure.MasterTabs) == 0:
+			diff.OnlyBranch = append(diff.OnlyBranch, *failure)
+		case len(failure.BranchTabs) == 0:
+			diff.OnlyMaster = append(diff.OnlyMaster, *failure)
+		default:
+			diff.Both = append(diff.Both, *failure)
+		}
+	}
+	for _, failures := range [][]BranchFailure{diff.OnlyBranch, diff.OnlyMaster, diff.Both} {
+		sort.Slice(failures, func(i, j int) bool { return failures[i].TestName < failures[j].TestName })
+	}
+	return diff
+}
+
+// Markdown renders the diff with a section per side, the tabs of each test
+// in parentheses.
+func (d *BranchDiff) Markdown() string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "# master vs %s signal\n", d.Branch)
+	sections := []struct {
+		title    string
+		failures []BranchFailure
+	}{
+		{fmt.Sprintf("Only on %s (cherry-pick gaps)", d.Branch), d.OnlyBranch},
+		{"Only on master (not yet branched)", d.OnlyMaster},
+		{"On both", d.Both},
+	}
+	for _, section := range sections {
+		fmt.Fprintf(&text, "\n## %s\n\n", section.title)
+		if len(section.failures) == 0 {
+			text.WriteString("None.\n")
+		}
+		for _, failure := range section.failures {
+			tabs := append(append([]string{}, failure.BranchTabs...), failure.MasterTabs...)
+			fmt.Fprintf(&text, "* `%s` (%s)\n", failure.TestName, strings.Join(tabs, ", "))
+		}
+	}
+	return text.String()
+}
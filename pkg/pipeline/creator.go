@@ -0,0 +1,48 @@
+package pipeline
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/signalhound/internal/culprit"
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/issue"
+)
+
+// ProjectCreator files the issue reports as draft issues in the CI Signal
+// GitHub project board.
+type ProjectCreator struct {
+	manager github.ProjectManagerInterface
+
+	// ReopenWindow reopens the kubernetes/kubernetes issue of a test closed
+	// within the window instead of creating a new draft, zero disables it.
+	ReopenWindow time.Duration
+
+	// RepositoryIssues creates the issues in the repository of the test, see
+	// issue.Repository, instead of drafting them in the project board.
+	RepositoryIssues bool
+}
+
+// NewProjectCreator returns a Creator authenticated with the GitHub token.
+func NewProjectCreator(ctx context.Context, token string) *ProjectCreator {
+	return &ProjectCreator{manager: github.NewProjectManager(ctx, token)}
+}
+
+// Create adds the report as a draft issue, the board field is set from the
+// finding dashboard. A regression of a recently closed issue reopens it. A
+// report front matter with a repository, or RepositoryIssues, creates the
+// issue in a repository instead.
+func (c *ProjectCreator) Create(finding *Finding, report *Report) error {
+	testName, testSig := "", ""
+	if finding.Test != nil {
+		testName, testSig = finding.Test.TestName, finding.Test.Sig
+	}
+	frontMatter := report.FrontMatter
+	if c.RepositoryIssues {
+		frontMatter = frontMatter.WithRepository(issue.Repository(finding.Tab.BoardHash, testName))
+	}
+	frontMatter = frontMatter.WithSIGLabel(testSig)
+	_, err := github.ReopenOrCreateDraft(c.manager, culprit.DefaultOwner, culprit.DefaultRepository,
+		testName, report.Title, report.Body, finding.Tab.BoardHash, frontMatter.Options(), c.ReopenWindow)
+	return err
+}
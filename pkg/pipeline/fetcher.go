@@ -0,0 +1,216 @@
+package pipeline
+
+import (
+	"errors"
+	"slices"
+	"sync"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/monitoring"
+	"sigs.k8s.io/signalhound/internal/prow"
+	"sigs.k8s.io/signalhound/internal/sig"
+	"sigs.k8s.io/signalhound/internal/testgrid"
+)
+
+// TestGridURL is the default TestGrid endpoint.
+var TestGridURL = testgrid.URL
+
+// maxJobFilters bounds the job config filters kept between fetches.
+const maxJobFilters = 512
+
+// TestGridFetcher fetches dashboard tabs and their tests from TestGrid.
+type TestGridFetcher struct {
+	grid testgrid.TestGridInterface
+
+	// MinFailure is the minimum number of failures of a test in a failing tab, 0 disables it.
+	MinFailure int
+
+	// MinFlake is the minimum number of flakes of a test in a flaky tab, 0 disables it.
+	MinFlake int
+
+	// OnTabError is called when the tests of a tab can't be fetched, the tab is skipped.
+	OnTabError func(tabName string, err error)
+
+	// IncludeSynthetic keeps the Overall and aggregate rows TestGrid adds to
+	// the tests of a job, left out by default.
+	IncludeSynthetic bool
+
+	// Dashboards are the dashboards of the latest Fetch, the groups
+	// expanded, with or without failing tab.
+	Dashboards []string
+
+	// jobFilters caches the test filters of the job config by build URL, the
+	// config of a build doesn't change between refreshes.
+	jobFilters   map[string]*prow.TestFilter
+	jobFiltersMu sync.Mutex
+
+	// SIGs infers the SIG of the tests without [sig-*] tag, from the tag and
+	// the OWNERS files by default, nil disables it. A resolver shared by the
+	// fetchers of the refreshes keeps its cache.
+	SIGs *sig.Resolver
+
+	// notGroups are the names found not to be dashboard groups, a dashboard
+	// doesn't become a group between refreshes.
+	notGroups   map[string]bool
+	notGroupsMu sync.Mutex
+}
+
+// NewFetcher returns a Fetcher for the TestGrid instance at url.
+func NewFetcher(url string, minFailure, minFlake int) *TestGridFetcher {
+	return NewGridFetcher(testgrid.NewTestGrid(url), minFailure, minFlake)
+}
+
+// NewGridFetcher returns a Fetcher of the TestGrid client, e.g. a fake of
+// the unit tests.
+func NewGridFetcher(grid testgrid.TestGridInterface, minFailure, minFlake int) *TestGridFetcher {
+	return &TestGridFetcher{
+		grid:       grid,
+		MinFailure: minFailure,
+		MinFlake:   minFlake,
+		jobFilters: map[string]*prow.TestFilter{},
+		notGroups:  map[string]bool{},
+		SIGs:       sig.NewResolver(sig.TagSource{}, sig.NewOwnersSource(sig.OwnersURL)),
+	}
+}
+
+// Fetch returns the failing and flaky tabs of the dashboards with at least
+// one test over the thresholds. The dashboard groups, e.g. sig-release, are
+// expanded into their dashboards, see ExpandGroups.
+func (f *TestGridFetcher) Fetch(dashboards []string) ([]*v1alpha1.DashboardTab, error) {
+	var dashboardTabs []*v1alpha1.DashboardTab
+	if grid, ok := f.grid.(*testgrid.TestGrid); ok {
+		// the fakes of the unit tests have no synthetic rows.
+		grid.IncludeSynthetic = f.IncludeSynthetic
+	}
+	dashboards, err := f.ExpandGroups(dashboards)
+	if err != nil {
+		return nil, err
+	}
+	f.Dashboards = dashboards
+	for _, dashboard := range dashboards {
+		dashSummaries, err := f.grid.FetchTabSummary(dashboard, v1alpha1.ERROR_STATUSES)
+		if err != nil {
+			return nil, err
+		}
+		for _, dashSummary := range dashSummaries {
+			dashTab, err := f.grid.FetchTabTests(&dashSummary, f.MinFailure, f.MinFlake)
+			if err != nil {
+				if f.OnTabError != nil {
+					f.OnTabError(dashSummary.DashboardTab.TabName, err)
+				}
+				continue
+			}
+			f.markSkipped(dashTab)
+			f.inferSIGs(dashTab)
+			if len(dashTab.TestRuns) > 0 {
+				dashboardTabs = append(dashboardTabs, dashTab)
+			}
+		}
+	}
+	return dashboardTabs, nil
+}
+
+// ExpandGroups replaces the TestGrid dashboard groups of the names by their
+// dashboards, listed again on every call so the boards added to a group are
+// fetched. The duplicates are dropped, and the names are returned as is when
+// the TestGrid client has no dashboard groups.
+func (f *TestGridFetcher) ExpandGroups(names []string) ([]string, error) {
+	groups, ok := f.grid.(testgrid.DashboardGroupFetcher)
+	if !ok {
+		return names, nil
+	}
+	var dashboards []string
+	for _, name := range names {
+		expanded := []string{name}
+		f.notGroupsMu.Lock()
+		notGroup := f.notGroups[name]
+		f.notGroupsMu.Unlock()
+		if !notGroup {
+			members, err := groups.FetchDashboardGroup(name)
+			switch {
+			case errors.Is(err, testgrid.ErrNotDashboardGroup):
+				f.notGroupsMu.Lock()
+				f.notGroups[name] = true
+				f.notGroupsMu.Unlock()
+			case err != nil:
+				return nil, err
+			default:
+				expanded = members
+			}
+		}
+		for _, dashboard := range expanded {
+			if !slices.Contains(dashboards, dashboard) {
+				dashboards = append(dashboards, dashboard)
+			}
+		}
+	}
+	return dashboards, nil
+}
+
+// markSkipped flags the tests without recent results that the job config of
+// the latest run skips or doesn't focus, so a test disabled in the job is not
+// mistaken for a recovered one. The job config is best effort, the tests are
+// left untouched when it can't be fetched.
+func (f *TestGridFetcher) markSkipped(tab *v1alpha1.DashboardTab) {
+	if tab.LatestBuildURL == "" {
+		return
+	}
+	var filter *prow.TestFilter
+	for i := range tab.TestRuns {
+		test := &tab.TestRuns[i]
+		if test.MissingRuns == 0 {
+			continue
+		}
+		if filter == nil {
+			var err error
+			if filter, err = f.jobFilter(tab.LatestBuildURL); err != nil {
+				return
+			}
+		}
+		test.Skipped = filter.Excludes(test.TestName)
+	}
+}
+
+// inferSIGs sets the SIG of the tests of the tab without one from the
+// sources of the resolver, e.g. the OWNERS files of the code the unit tests
+// and verify checks cover. It is best effort, a test is left without SIG
+// when no source can be read.
+func (f *TestGridFetcher) inferSIGs(tab *v1alpha1.DashboardTab) {
+	if f.SIGs == nil {
+		return
+	}
+	for i := range tab.TestRuns {
+		test := &tab.TestRuns[i]
+		if test.Sig != "" {
+			continue
+		}
+		if inferred, err := f.SIGs.Infer(test.TestName); err == nil {
+			test.Sig = inferred
+		}
+	}
+}
+
+// jobFilter returns the test filter of the job config of the build.
+func (f *TestGridFetcher) jobFilter(buildURL string) (*prow.TestFilter, error) {
+	f.jobFiltersMu.Lock()
+	filter, found := f.jobFilters[buildURL]
+	f.jobFiltersMu.Unlock()
+	monitoring.RecordCacheLookup("job_config", found)
+	if found {
+		return filter, nil
+	}
+
+	job, err := prow.NewProw(buildURL).GetProwJob()
+	if err != nil {
+		return nil, err
+	}
+	filter = job.TestFilter()
+
+	f.jobFiltersMu.Lock()
+	defer f.jobFiltersMu.Unlock()
+	if f.jobFilters == nil || len(f.jobFilters) >= maxJobFilters {
+		f.jobFilters = map[string]*prow.TestFilter{}
+	}
+	f.jobFilters[buildURL] = filter
+	return filter, nil
+}
@@ -0,0 +1,148 @@
+package pipeline
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"sigs.k8s.io/signalhound/internal/testgrid"
+)
+
+// FlakeRateThreshold is a rolling flake rate over a time window.
+type FlakeRateThreshold = testgrid.FlakeRateThreshold
+
+// FlakeRateAlert is a test whose flake rate crossed a threshold.
+type FlakeRateAlert struct {
+	BoardHash string
+	TabURL    string
+
+	// TabState is the overall state of the tab on TestGrid, usually PASSING
+	// for the flakes masked by a recent green streak.
+	TabState string
+
+	TestName  string
+	Threshold FlakeRateThreshold
+
+	// Failures and Runs are the red runs and the runs with a result in the
+	// threshold window.
+	Failures int
+	Runs     int
+}
+
+// Rate returns the ratio of red runs in the threshold window.
+func (a *FlakeRateAlert) Rate() float64 {
+	if a.Runs == 0 {
+		return 0
+	}
+	return float64(a.Failures) / float64(a.Runs)
+}
+
+// Message renders the alert as a #release-ci-signal Slack message.
+func (a *FlakeRateAlert) Message() string {
+	return fmt.Sprintf(":warning: Flake rate %s%% (%d/%d runs) above %s on [%s](%s): `%s`, tab is %s on TestGrid",
+		strconv.FormatFloat(a.Rate()*100, 'f', 0, 64), a.Failures, a.Runs, a.Threshold, a.BoardHash, a.TabURL, a.TestName, a.TabState)
+}
+
+// FlakeRateMonitor alerts on the tests whose rolling flake rate crosses a
+// threshold, whatever the state TestGrid gives to their tab.
+type FlakeRateMonitor struct {
+	grid testgrid.TestGridInterface
+
+	Thresholds []FlakeRateThreshold
+
+	// IncludeSynthetic checks the Overall and aggregate rows TestGrid adds to
+	// the tests of a job, left out by default.
+	IncludeSynthetic bool
+
+	// OnTabError is called when the tests of a tab can't be fetched, the tab is skipped.
+	OnTabError func(tabName string, err error)
+
+	// exceeded are the tests over each threshold by board hash at the last
+	// check, only new crossings are alerted.
+	exceeded map[string]map[string]bool
+}
+
+// NewFlakeRateMonitor returns a monitor for the TestGrid instance at url.
+func NewFlakeRateMonitor(url string, thresholds []FlakeRateThreshold) *FlakeRateMonitor {
+	return NewGridFlakeRateMonitor(testgrid.NewTestGrid(url), thresholds)
+}
+
+// NewGridFlakeRateMonitor returns a monitor of the TestGrid client, e.g. a
+// fake of the unit tests.
+func NewGridFlakeRateMonitor(grid testgrid.TestGridInterface, thresholds []FlakeRateThreshold) *FlakeRateMonitor {
+	return &FlakeRateMonitor{
+		grid:       grid,
+		Thresholds: thresholds,
+		exceeded:   map[string]map[string]bool{},
+	}
+}
+
+// Check fetches every tab of the dashboards and returns the tests crossing a
+// threshold since the previous check, the first check returns all the tests
+// over a threshold. A test is alerted again after its rate went back under
+// the threshold.
+func (m *FlakeRateMonitor) Check(dashboards []string) ([]*FlakeRateAlert, error) {
+	var alerts []*FlakeRateAlert
+	exceeded := map[string]map[string]bool{}
+	for _, dashboard := range dashboards {
+		// the summary state is ignored, a masked flake lives on passing tabs.
+		summaries, err := m.grid.FetchTabSummary(dashboard, nil)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(summaries, func(i, j int) bool {
+			return summaries[i].DashboardTab.TabName < summaries[j].DashboardTab.TabName
+		})
+		for _, summary := range summaries {
+			boardHash := fmt.Sprintf("%s#%s", dashboard, summary.DashboardTab.TabName)
+			testGroup, err := m.grid.FetchTestGroup(&summary)
+			if err != nil {
+				if m.OnTabError != nil {
+					m.OnTabError(summary.DashboardTab.TabName, err)
+				}
+				// a tab failing to load keeps its crossings, not to alert them twice.
+				exceeded[boardHash] = m.exceeded[boardHash]
+				continue
+			}
+			exceeded[boardHash] = map[string]bool{}
+			for _, alert := range m.evaluate(boardHash, summary.OverallState, testGroup) {
+				key := alert.TestName + "\x00" + alert.Threshold.String()
+				exceeded[boardHash][key] = true
+				if !m.exceeded[boardHash][key] {
+					alerts = append(alerts, alert)
+				}
+			}
+		}
+	}
+	m.exceeded = exceeded
+	return alerts, nil
+}
+
+// evaluate returns the tests of the test group over a threshold.
+func (m *FlakeRateMonitor) evaluate(boardHash, state string, testGroup *testgrid.TestGroup) (alerts []*FlakeRateAlert) {
+	if len(testGroup.Timestamps) == 0 {
+		return nil
+	}
+	for _, test := range testGroup.Tests {
+		if !m.IncludeSynthetic && testgrid.IsSynthetic(test.Name) {
+			continue
+		}
+		for _, threshold := range m.Thresholds {
+			since := testGroup.Timestamps[0] - threshold.Window.Milliseconds()
+			failures, runs := test.FlakeRate(testGroup.Timestamps, since)
+			if !threshold.Exceeds(failures, runs) {
+				continue
+			}
+			alerts = append(alerts, &FlakeRateAlert{
+				BoardHash: boardHash,
+				TabURL:    fmt.Sprintf("https://testgrid.k8s.io/%s", boardHash),
+				TabState:  state,
+				TestName:  test.Name,
+				Threshold: threshold,
+				Failures:  failures,
+				Runs:      runs,
+			})
+		}
+	}
+	return alerts
+}
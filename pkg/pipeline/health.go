@@ -0,0 +1,129 @@
+package pipeline
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/fingerprint"
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/release"
+)
+
+// Penalties of the health score, the score of a release starts at 100 and
+// loses points for its failing and flaky tabs, its open critical issues and
+// the flake rate of its tests. Each source is capped, so the score keeps
+// telling a release with one broken blocking board from a red release.
+const (
+	failingBlockingPenalty  = 15
+	flakyBlockingPenalty    = 5
+	failingInformingPenalty = 5
+	flakyInformingPenalty   = 2
+	maxBoardPenalty         = 60
+
+	criticalIssuePenalty = 5
+	maxIssuePenalty      = 20
+
+	maxFlakePenalty = 20
+)
+
+// CriticalIssueLabels are the labels of the open issues counted as critical
+// by the health score, the failing tests raised during freeze phases.
+var CriticalIssueLabels = []string{"kind/failing-test", "priority/critical-urgent"}
+
+// HealthScore is the signal health of a release from 0 to 100, with the
+// figures it was computed from.
+type HealthScore struct {
+	Release        string `json:"release"`
+	Score          int    `json:"score"`
+	FailingTabs    int    `json:"failing_tabs"`
+	FlakyTabs      int    `json:"flaky_tabs"`
+	CriticalIssues int    `json:"critical_issues"`
+
+	// FlakeRate is the mean flake score in percent of the tests with one.
+	FlakeRate int `json:"flake_rate"`
+}
+
+// String returns the score with its figures, e.g. "master: 72/100 (1
+// failing and 2 flaky tabs, 1 critical issues, 12% mean flake rate)".
+func (h HealthScore) String() string {
+	return fmt.Sprintf("%s: %d/100 (%d failing and %d flaky tabs, %d critical issues, %d%% mean flake rate)",
+		h.Release, h.Score, h.FailingTabs, h.FlakyTabs, h.CriticalIssues, h.FlakeRate)
+}
+
+// Releases returns the release branches of the SIG Release dashboards, e.g.
+// master or 1.32, in the order of the dashboards.
+func Releases(dashboards []string) []string {
+	var releases []string
+	for _, dashboard := range dashboards {
+		branch := fingerprint.Branch(dashboard)
+		if branch != "" && !slices.Contains(releases, branch) {
+			releases = append(releases, branch)
+		}
+	}
+	return releases
+}
+
+// HealthScores returns the health score of every release of the dashboards,
+// see Releases, from the fetched tabs and the open critical issues of each
+// release, nil when they were not counted.
+func HealthScores(dashboards []string, tabs []*v1alpha1.DashboardTab, criticalIssues map[string]int) []HealthScore {
+	var scores []HealthScore
+	for _, branch := range Releases(dashboards) {
+		score := HealthScore{Release: branch, CriticalIssues: criticalIssues[branch]}
+		var boardPenalty, flakeScores, flakeTests int
+		for _, tab := range tabs {
+			dashboard, _, _ := strings.Cut(tab.BoardHash, "#")
+			if fingerprint.Branch(dashboard) != branch {
+				continue
+			}
+			blocking := strings.HasSuffix(dashboard, "-blocking")
+			switch {
+			case tab.TabState == v1alpha1.FAILING_STATUS && blocking:
+				score.FailingTabs++
+				boardPenalty += failingBlockingPenalty
+			case tab.TabState == v1alpha1.FAILING_STATUS:
+				score.FailingTabs++
+				boardPenalty += failingInformingPenalty
+			case tab.TabState == v1alpha1.FLAKY_STATUS && blocking:
+				score.FlakyTabs++
+				boardPenalty += flakyBlockingPenalty
+			case tab.TabState == v1alpha1.FLAKY_STATUS:
+				score.FlakyTabs++
+				boardPenalty += flakyInformingPenalty
+			}
+			for _, test := range tab.TestRuns {
+				if test.ScoreRuns > 0 {
+					flakeScores += test.FlakeScore
+					flakeTests++
+				}
+			}
+		}
+		if flakeTests > 0 {
+			score.FlakeRate = int(math.Round(float64(flakeScores) / float64(flakeTests)))
+		}
+		penalty := float64(min(boardPenalty, maxBoardPenalty)) +
+			float64(min(score.CriticalIssues*criticalIssuePenalty, maxIssuePenalty)) +
+			float64(min(score.FlakeRate, 100)*maxFlakePenalty)/100
+		score.Score = max(0, 100-int(math.Round(penalty)))
+		scores = append(scores, score)
+	}
+	return scores
+}
+
+// CountCriticalIssues returns the open critical issues of kubernetes/kubernetes
+// in the milestone of each release, the master ones without milestone.
+func CountCriticalIssues(manager github.ProjectManagerInterface, releases []string) (map[string]int, error) {
+	counts := map[string]int{}
+	for _, branch := range releases {
+		count, err := manager.CountOpenIssues(github.ORGANIZATION, "kubernetes", CriticalIssueLabels,
+			release.Milestone("sig-release-"+branch+"-blocking"))
+		if err != nil {
+			return nil, err
+		}
+		counts[branch] = count
+	}
+	return counts, nil
+}
@@ -0,0 +1,117 @@
+package pipeline
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/github"
+)
+
+// KnownIssue is a test still failing on the boards at release time, a known
+// issue of the release notes.
+type KnownIssue struct {
+	TestName string
+	Sig      string
+	Tabs     []string
+
+	// Issue is the open or draft issue of the project board tracking the
+	// test, nil when none does.
+	Issue *Issue
+}
+
+// KnownIssues lists the tests of the failing tabs not recovered yet, see
+// Finding.Recovered, with the board issue tracking each of them, see
+// github.IssuesByTest. The flaky tests are left out of the release notes.
+func KnownIssues(tabs []*v1alpha1.DashboardTab, issues []github.BoardIssue) []KnownIssue {
+	byTest := github.IssuesByTest(issues)
+	known := map[string]*KnownIssue{}
+	for _, tab := range tabs {
+		for i := range tab.TestRuns {
+			finding := &Finding{Tab: tab, Test: &tab.TestRuns[i]}
+			if !finding.Failing() || finding.Recovered() || finding.Test.Skipped {
+				continue
+			}
+			knownIssue, ok := known[finding.Test.TestName]
+			if !ok {
+				knownIssue = &KnownIssue{TestName: finding.Test.TestName, Sig: finding.Test.Sig}
+				if linked, found := byTest[finding.Test.TestName]; found {
+					knownIssue.Issue = &linked
+				}
+				known[finding.Test.TestName] = knownIssue
+			}
+			if !slices.Contains(knownIssue.Tabs, tab.BoardHash) {
+				knownIssue.Tabs = append(knownIssue.Tabs, tab.BoardHash)
+			}
+		}
+	}
+
+	list := make([]KnownIssue, 0, len(known))
+	for _, knownIssue := range known {
+		list = append(list, *knownIssue)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Sig != list[j].Sig {
+			return list[i].Sig < list[j].Sig
+		}
+		return list[i].TestName < list[j].TestName
+	})
+	return list
+}
+
+// KnownIssuesMarkdown renders the draft of the Known Issues (CI) section of
+// the release notes, a note per tracked test with its issue and SIG. The
+// tests without issue are listed apart, to file before the release.
+func KnownIssuesMarkdown(release string, known []KnownIssue) string {
+	var text strings.Builder
+	title := "## Known Issues (CI)"
+	if release != "" {
+		title = fmt.Sprintf("## Known Issues (CI) in %s", release)
+	}
+	fmt.Fprintf(&text, "%s\n\n", title)
+
+	var untracked []KnownIssue
+	tracked := 0
+	for _, knownIssue := range known {
+		if knownIssue.Issue == nil {
+			untracked = append(untracked, knownIssue)
+			continue
+		}
+		tracked++
+		fmt.Fprintf(&text, "- The `%s` test fails on %s, tracked in %s.%s\n",
+			knownIssue.TestName, strings.Join(knownIssue.Tabs, ", "), issueReference(knownIssue.Issue), sigSuffix(knownIssue.Sig))
+	}
+	if tracked == 0 {
+		text.WriteString("None.\n")
+	}
+	if len(untracked) > 0 {
+		text.WriteString("\n### Failing tests without issue\n\n")
+		for _, knownIssue := range untracked {
+			fmt.Fprintf(&text, "- `%s` on %s%s\n", knownIssue.TestName, strings.Join(knownIssue.Tabs, ", "), sigSuffix(knownIssue.Sig))
+		}
+	}
+	return text.String()
+}
+
+// issueReference returns the Markdown link of the issue, the title of a
+// draft of the project board.
+func issueReference(linked *Issue) string {
+	if linked.Draft {
+		return fmt.Sprintf("the draft issue %q of the project board", linked.Title)
+	}
+	return fmt.Sprintf("[#%d](%s)", linked.Number, linked.URL)
+}
+
+// sigSuffix returns the [SIG Name] suffix of the release notes, e.g. [SIG
+// Cluster Lifecycle], an empty string without SIG.
+func sigSuffix(sig string) string {
+	if sig == "" {
+		return ""
+	}
+	return fmt.Sprintf(" [SIG %s]", cases.Title(language.English).String(strings.ReplaceAll(sig, "-", " ")))
+}
@@ -0,0 +1,314 @@
+// Package pipeline exposes the SignalHound pipelines for embedding in other
+// release-engineering tools without shelling out to the CLI.
+//
+// A pipeline is composed by five stages:
+//
+//   - The Fetcher retrieves the failing and flaky tabs of the dashboards.
+//   - The Analyzer turns the fetched tabs into findings, one per test.
+//   - The Reporter renders a finding as a GitHub issue or a Slack message.
+//   - The Creator files the rendered issue on GitHub.
+//   - The Resolver closes the issue of a recovered test with a summary.
+//
+// Example:
+//
+//	fetcher := pipeline.NewFetcher(pipeline.TestGridURL, 2, 3)
+//	tabs, err := fetcher.Fetch([]string{"sig-release-master-blocking"})
+//	if err != nil {
+//		return err
+//	}
+//	for _, finding := range pipeline.NewAnalyzer().Analyze(tabs) {
+//		report, err := pipeline.NewIssueReporter().Report(finding)
+//		...
+//		err = pipeline.NewProjectCreator(ctx, token).Create(finding, report)
+//		...
+//	}
+//
+// Issues rendered by the CLI also carry the release freeze priority and the
+// job log streams and possible culprits picked by the user. Set
+// IssueReporter.Phase, Finding.Logs, Finding.Culprits and Finding.Details to
+// get the same output. Correlate and GroupVariants find the other branches
+// and platforms failing the same way. Set the Links checker of the reporters
+// to replace the dead TestGrid, Prow and Triage links like the CLI does.
+// NewSnapshot wraps the fetched tabs for a JSON, YAML or Markdown output.
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/culprit"
+	"sigs.k8s.io/signalhound/internal/fingerprint"
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/infra"
+	"sigs.k8s.io/signalhound/internal/issue"
+	"sigs.k8s.io/signalhound/internal/links"
+	"sigs.k8s.io/signalhound/internal/platform"
+	"sigs.k8s.io/signalhound/internal/prow"
+	"sigs.k8s.io/signalhound/internal/release"
+)
+
+// ScheduleURL is the default SIG Release schedule.
+var ScheduleURL = release.ScheduleURL
+
+// Log is a job log stream included in the issue body.
+type Log = issue.Log
+
+// TestDetails is the JUnit result of a test in its failed run.
+type TestDetails = issue.TestDetails
+
+// Culprit is a pull request possibly causing a failure.
+type Culprit = issue.Culprit
+
+// CrossBranch is a board of another branch failing with the same fingerprint.
+type CrossBranch = issue.CrossBranch
+
+// Variant is a tab running the suite of the test on another platform.
+type Variant = issue.Variant
+
+// InfraCluster is a zone, node image or instance type the red runs of a test
+// concentrate on.
+type InfraCluster = infra.Cluster
+
+// FrontMatter is the repository, labels, assignees and milestone set by an
+// issue template.
+type FrontMatter = issue.FrontMatter
+
+// SlackMentions are the @-mentions of the Slack messages of failing tests.
+type SlackMentions = issue.SlackMentions
+
+// Resolution is the summary commented on the issue of a recovered test.
+type Resolution = issue.Resolution
+
+// Issue is a GitHub issue filed for a test.
+type Issue = github.Issue
+
+// LinkChecker replaces the dead links of the reports, see NewLinkChecker.
+type LinkChecker = links.Checker
+
+// DeadLink is a dead link of a report with the alternate it was replaced by.
+type DeadLink = links.DeadLink
+
+// NewLinkChecker returns a link checker caching the checks for 30 minutes.
+func NewLinkChecker() *LinkChecker {
+	return links.NewChecker()
+}
+
+// ReleasePhase is the release cycle phase, used to raise issue priorities
+// during burndown and code freeze.
+type ReleasePhase = release.Phase
+
+// Fetcher retrieves the dashboard tabs in failing or flaky state.
+type Fetcher interface {
+	Fetch(dashboards []string) ([]*v1alpha1.DashboardTab, error)
+}
+
+// Analyzer turns the fetched dashboard tabs into findings.
+type Analyzer interface {
+	Analyze(tabs []*v1alpha1.DashboardTab) []*Finding
+}
+
+// Reporter renders a finding into a report.
+type Reporter interface {
+	Report(finding *Finding) (*Report, error)
+}
+
+// Creator files the report of a finding.
+type Creator interface {
+	Create(finding *Finding, report *Report) error
+}
+
+// Resolver closes the issue of a recovered finding, returning the closed
+// issue or nil when there is none.
+type Resolver interface {
+	Resolve(finding *Finding) (*Issue, error)
+}
+
+// Finding is a single failing or flaky test in a dashboard tab.
+type Finding struct {
+	Tab  *v1alpha1.DashboardTab
+	Test *v1alpha1.TestResult
+
+	// Logs are the job log streams included in the issue body, empty by default.
+	Logs []Log
+
+	// Culprits are the possible culprits listed in the issue body of failing
+	// tests, empty by default.
+	Culprits []Culprit
+
+	// Details is the JUnit result of the test included in the issue body,
+	// nil by default, see FetchDetails.
+	Details *TestDetails
+
+	// Notes are the human annotations of the test, see state.Annotation.
+	Notes []string
+
+	// CrossBranch are the boards of other branches failing with the same
+	// fingerprint, see Correlate.
+	CrossBranch []CrossBranch
+
+	// Variants are the other platform variants of the suite of the test,
+	// see GroupVariants.
+	Variants []Variant
+}
+
+// Failing returns true when the finding comes from a failing tab.
+func (f *Finding) Failing() bool {
+	return f.Tab.TabState == v1alpha1.FAILING_STATUS
+}
+
+// Recovered returns true when the test of a failing tab is green again after
+// its latest failure streak, green runs between flakes are not recoveries. A
+// test skipped by the job config is not a recovery either.
+func (f *Finding) Recovered() bool {
+	return f.Failing() && f.Test.RecoveredTimestamp != 0 && !f.Test.Skipped
+}
+
+// GreenRuns returns the number of consecutive green runs of the test since
+// its latest red or flaky run, the most recent first.
+func (f *Finding) GreenRuns() int {
+	for i, run := range f.Test.Runs {
+		if run.Failed {
+			return i
+		}
+	}
+	return len(f.Test.Runs)
+}
+
+// Report is the rendered output of a finding.
+type Report struct {
+	Title string
+	Body  string
+
+	// FrontMatter is the issue configuration set by the template front
+	// matter, nil when the template has none.
+	FrontMatter *FrontMatter
+
+	// DeadLinks are the links of the test found dead by the Links checker of
+	// the reporter, replaced by their alternate when one works.
+	DeadLinks []DeadLink
+}
+
+// FetchLogs returns the stdout/stderr and pod log streams of the finding job,
+// streams that can't be fetched are skipped.
+func FetchLogs(finding *Finding) ([]Log, error) {
+	streams, err := prow.NewProw(finding.Test.ProwJobURL).GetLogStreams()
+	if err != nil {
+		return nil, err
+	}
+	var logs []Log
+	for _, stream := range streams {
+		if stream.Err == nil {
+			logs = append(logs, Log{Name: stream.Name, URL: stream.URL, Content: stream.Content})
+		}
+	}
+	return logs, nil
+}
+
+// FetchDetails returns the JUnit result of the test in the finding job, nil
+// when the JUnit files of the job don't have the test.
+func FetchDetails(finding *Finding) (*TestDetails, error) {
+	result, err := prow.NewProw(finding.Test.ProwJobURL).GetJUnitResult(finding.Test.TestName)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	return &TestDetails{
+		File: result.File, URL: result.URL, Duration: result.Duration,
+		Message: result.Message, Failure: result.Failure, Output: result.SystemOut,
+	}, nil
+}
+
+// CorrelateInfrastructure returns the infrastructure the red runs of the
+// finding cluster on compared to its green runs. Runs whose metadata can't
+// be fetched are left out of the comparison.
+func CorrelateInfrastructure(finding *Finding) ([]InfraCluster, error) {
+	failed, err := infra.Fetch(finding.Test.FailedRunURLs)
+	if len(failed) == 0 {
+		return nil, err
+	}
+	passed, _ := infra.Fetch(finding.Test.PassedRunURLs)
+	return infra.Correlate(failed, passed), nil
+}
+
+// FindCulprits returns up to limit pull requests merged between the last green
+// and the first red run of the finding, ranked by overlap with the test area.
+func FindCulprits(ctx context.Context, token string, finding *Finding, limit int) ([]Culprit, error) {
+	return findCulprits(github.NewProjectManager(ctx, token), finding, limit)
+}
+
+func findCulprits(manager github.ProjectManagerInterface, finding *Finding, limit int) ([]Culprit, error) {
+	if finding.Test.LastPassTimestamp == 0 || finding.Test.FirstFailTimestamp == 0 {
+		return nil, errors.New("no green run found before the failure")
+	}
+	return rankMergedPullRequests(manager, finding.Test.TestName, finding.Test.LastPassTimestamp, finding.Test.FirstFailTimestamp, limit)
+}
+
+// FindFixes returns up to limit pull requests merged between the last red and
+// the first green run of a recovered finding, ranked by overlap with the test
+// area.
+func FindFixes(ctx context.Context, token string, finding *Finding, limit int) ([]Culprit, error) {
+	return findFixes(github.NewProjectManager(ctx, token), finding, limit)
+}
+
+func findFixes(manager github.ProjectManagerInterface, finding *Finding, limit int) ([]Culprit, error) {
+	if finding.Test.LastFailureTimestamp == 0 || finding.Test.RecoveredTimestamp == 0 {
+		return nil, errors.New("no green run found after the failure")
+	}
+	return rankMergedPullRequests(manager, finding.Test.TestName, finding.Test.LastFailureTimestamp, finding.Test.RecoveredTimestamp, limit)
+}
+
+// rankMergedPullRequests ranks the pull requests merged between the from and
+// to timestamps in milliseconds by overlap with the test area.
+func rankMergedPullRequests(manager github.ProjectManagerInterface, testName string, from, to int64, limit int) ([]Culprit, error) {
+	pulls, err := manager.ListMergedPullRequests(culprit.DefaultOwner, culprit.DefaultRepository,
+		time.UnixMilli(from), time.UnixMilli(to))
+	if err != nil {
+		return nil, err
+	}
+	var culprits []Culprit
+	for _, candidate := range culprit.Rank(pulls, testName, limit) {
+		culprits = append(culprits, Culprit{Number: candidate.Number, Title: candidate.Title, URL: candidate.URL})
+	}
+	return culprits, nil
+}
+
+// FetchReleasePhase returns the release cycle phase at the given moment from
+// the schedule at location, an URL or a local file path.
+func FetchReleasePhase(location string, now time.Time) (*ReleasePhase, error) {
+	schedule, err := release.FetchSchedule(location)
+	if err != nil {
+		return nil, err
+	}
+	return schedule.PhaseAt(now), nil
+}
+
+// Correlate sets the CrossBranch boards of the findings from the failures
+// with the same fingerprint on the tabs of the other release branches.
+func Correlate(tabs []*v1alpha1.DashboardTab, findings []*Finding) {
+	index := fingerprint.NewIndex(tabs)
+	for _, finding := range findings {
+		finding.CrossBranch = crossBranch(index, finding.Tab, finding.Test)
+	}
+}
+
+func crossBranch(index fingerprint.Index, tab *v1alpha1.DashboardTab, test *v1alpha1.TestResult) (boards []CrossBranch) {
+	for _, occurrence := range index.CrossBranch(tab, test) {
+		boards = append(boards, CrossBranch{Branch: occurrence.Branch, BoardHash: occurrence.BoardHash, URL: occurrence.TabURL})
+	}
+	return boards
+}
+
+// GroupVariants sets the Variants of the findings from the tabs running the
+// same suite on other platforms, e.g. the arm64 and the default jobs.
+func GroupVariants(tabs []*v1alpha1.DashboardTab, findings []*Finding) {
+	index := platform.NewIndex(tabs)
+	for _, finding := range findings {
+		finding.Variants = nil
+		for _, occurrence := range index.Variants(finding.Tab, finding.Test) {
+			finding.Variants = append(finding.Variants, Variant{
+				Variant: occurrence.Variant, BoardHash: occurrence.BoardHash, URL: occurrence.TabURL, Failing: occurrence.Failing,
+			})
+		}
+	}
+}
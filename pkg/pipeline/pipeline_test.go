@@ -0,0 +1,783 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/issue"
+	"sigs.k8s.io/signalhound/internal/prow"
+	"sigs.k8s.io/signalhound/internal/release"
+	"sigs.k8s.io/signalhound/internal/sig"
+	"sigs.k8s.io/signalhound/internal/state"
+	"sigs.k8s.io/signalhound/internal/testing/fake"
+)
+
+const dashboard = "sig-release-master-blocking"
+
+func TestFetch(t *testing.T) {
+	summary := map[string]*v1alpha1.DashboardSummary{
+		"gce-cos-master-default": {OverallState: v1alpha1.FAILING_STATUS, DashboardName: dashboard},
+		"kind-master":            {OverallState: v1alpha1.FLAKY_STATUS, DashboardName: dashboard},
+		"broken-tab":             {OverallState: v1alpha1.FLAKY_STATUS, DashboardName: dashboard},
+		"passing-tab":            {OverallState: v1alpha1.PASSING_STATUS, DashboardName: dashboard},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/"+dashboard+"/summary" {
+			_ = json.NewEncoder(w).Encode(summary)
+			return
+		}
+		switch r.URL.Query().Get("tab") {
+		case "broken-tab":
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, "internal error") // nolint
+		default:
+			fmt.Fprint(w, `{"query": "kubernetes-ci-logs/logs/ci-kubernetes-e2e", "changelists": ["1"], "timestamps": [1758999193000],
+				"tests": [{"name": "ci-kubernetes-e2e.Overall", "short_texts": ["F"], "messages": ["timeout"]},
+					{"name": "kubetest.Up", "short_texts": ["F"], "messages": ["timeout"]}]}`) // nolint
+		}
+	}))
+	defer server.Close()
+
+	var tabErrors []string
+	fetcher := NewFetcher(server.URL, 1, 1)
+	fetcher.OnTabError = func(tabName string, err error) {
+		tabErrors = append(tabErrors, tabName)
+	}
+	tabs, err := fetcher.Fetch([]string{dashboard})
+	assert.NoError(t, err)
+	assert.Len(t, tabs, 2)
+	assert.Equal(t, []string{"broken-tab"}, tabErrors)
+	for _, tab := range tabs {
+		assert.Len(t, tab.TestRuns, 1)
+		assert.Equal(t, "kubetest.Up", tab.TestRuns[0].TestName)
+		assert.NotEqual(t, v1alpha1.PASSING_STATUS, tab.TabState)
+	}
+
+	fetcher.IncludeSynthetic = true
+	tabs, err = fetcher.Fetch([]string{dashboard})
+	assert.NoError(t, err)
+	for _, tab := range tabs {
+		assert.Len(t, tab.TestRuns, 2)
+	}
+}
+
+func TestFetchGrid(t *testing.T) {
+	grid := &fake.TestGrid{
+		Summaries: map[string][]v1alpha1.DashboardSummary{dashboard: {
+			{DashboardName: dashboard, OverallState: v1alpha1.FAILING_STATUS, DashboardTab: &v1alpha1.DashboardTab{TabName: "kind-master"}},
+			{DashboardName: dashboard, OverallState: v1alpha1.FLAKY_STATUS, DashboardTab: &v1alpha1.DashboardTab{TabName: "broken-tab"}},
+		}},
+		Tabs: map[string]*v1alpha1.DashboardTab{"kind-master": {TabName: "kind-master",
+			TestRuns: []v1alpha1.TestResult{{TestName: "[sig-node] Pods should run", Sig: "node"}}}},
+	}
+	fetcher := NewGridFetcher(grid, 1, 1)
+	tabs, err := fetcher.Fetch([]string{dashboard})
+	assert.NoError(t, err)
+	if assert.Len(t, tabs, 1) {
+		assert.Equal(t, "kind-master", tabs[0].TabName)
+	}
+
+	grid.Err = errors.New("testgrid unavailable")
+	_, err = fetcher.Fetch([]string{dashboard})
+	assert.ErrorContains(t, err, "testgrid unavailable")
+}
+
+func TestExpandGroups(t *testing.T) {
+	informing := "sig-release-master-informing"
+	grid := &fake.TestGrid{
+		Summaries: map[string][]v1alpha1.DashboardSummary{
+			dashboard: {{DashboardName: dashboard, OverallState: v1alpha1.FAILING_STATUS, DashboardTab: &v1alpha1.DashboardTab{TabName: "kind-master"}}},
+			informing: {{DashboardName: informing, OverallState: v1alpha1.FLAKY_STATUS, DashboardTab: &v1alpha1.DashboardTab{TabName: "gce-master"}}},
+		},
+		Tabs: map[string]*v1alpha1.DashboardTab{
+			"kind-master": {TabName: "kind-master", TestRuns: []v1alpha1.TestResult{{TestName: "[sig-node] Pods should run", Sig: "node"}}},
+			"gce-master":  {TabName: "gce-master", TestRuns: []v1alpha1.TestResult{{TestName: "[sig-network] DNS", Sig: "network"}}},
+		},
+		Groups: map[string][]string{"sig-release": {dashboard}},
+	}
+	fetcher := NewGridFetcher(grid, 1, 1)
+	dashboards, err := fetcher.ExpandGroups([]string{"sig-release", dashboard, informing})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{dashboard, informing}, dashboards)
+
+	// the boards added to the group are fetched on the next call
+	grid.Groups["sig-release"] = append(grid.Groups["sig-release"], informing)
+	tabs, err := fetcher.Fetch([]string{"sig-release"})
+	assert.NoError(t, err)
+	assert.Len(t, tabs, 2)
+	assert.Equal(t, []string{dashboard, informing}, fetcher.Dashboards)
+}
+
+func TestFlakeRateMonitor(t *testing.T) {
+	// an hourly job green in its latest runs, flaking 3 times in the last 6h.
+	flaky := `[{"count": 2, "value": 1}, {"count": 1, "value": 12}, {"count": 1, "value": 1}, {"count": 2, "value": 13}, {"count": 2, "value": 1}]`
+	statuses := flaky
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/"+dashboard+"/summary" {
+			fmt.Fprint(w, `{"kind-master": {"overall_status": "PASSING", "dashboard_name": "`+dashboard+`"}}`) // nolint
+			return
+		}
+		hour := int64(time.Hour / time.Millisecond)
+		fmt.Fprintf(w, `{"timestamps": [%d, %d, %d, %d, %d, %d, %d, %d],
+			"tests": [{"name": "[sig-network] DNS", "statuses": %s}, {"name": "[sig-node] Pods", "statuses": [{"count": 8, "value": 1}]}]}`, // nolint
+			8*hour, 7*hour, 6*hour, 5*hour, 4*hour, 3*hour, 2*hour, hour, statuses)
+	}))
+	defer server.Close()
+
+	monitor := NewFlakeRateMonitor(server.URL, []FlakeRateThreshold{{Rate: 0.3, Window: metav1.Duration{Duration: 6 * time.Hour}}})
+	alerts, err := monitor.Check([]string{dashboard})
+	assert.NoError(t, err)
+	if assert.Len(t, alerts, 1) {
+		assert.Equal(t, "[sig-network] DNS", alerts[0].TestName)
+		assert.Equal(t, v1alpha1.PASSING_STATUS, alerts[0].TabState)
+		assert.Equal(t, 3, alerts[0].Failures)
+		assert.Equal(t, 7, alerts[0].Runs)
+		assert.Contains(t, alerts[0].Message(), "Flake rate 43% (3/7 runs) above 30% over 6h0m0s on [sig-release-master-blocking#kind-master]")
+	}
+
+	// only the crossings are alerted
+	alerts, err = monitor.Check([]string{dashboard})
+	assert.NoError(t, err)
+	assert.Empty(t, alerts)
+
+	statuses = `[{"count": 8, "value": 1}]`
+	alerts, err = monitor.Check([]string{dashboard})
+	assert.NoError(t, err)
+	assert.Empty(t, alerts)
+
+	statuses = flaky
+	alerts, err = monitor.Check([]string{dashboard})
+	assert.NoError(t, err)
+	assert.Len(t, alerts, 1)
+}
+
+func TestMarkSkipped(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/bucket/logs/ci-kubernetes-e2e/1/prowjob.json", r.URL.Path)
+		fmt.Fprint(w, `{"spec": {"pod_spec": {"containers": [{"args": ["--ginkgo.skip=\\[Serial\\]"]}]}}}`) // nolint
+	}))
+	defer server.Close()
+	previous := prow.GCSURL
+	prow.GCSURL = server.URL
+	t.Cleanup(func() { prow.GCSURL = previous })
+
+	tab := &v1alpha1.DashboardTab{
+		LatestBuildURL: "https://prow.k8s.io/view/gs/bucket/logs/ci-kubernetes-e2e/1",
+		TestRuns: []v1alpha1.TestResult{
+			{TestName: "[Serial] Pods should be evicted", MissingRuns: 2},
+			{TestName: "Pods should run", MissingRuns: 2},
+			{TestName: "[Serial] Nodes should drain"},
+		},
+	}
+	fetcher := NewFetcher(server.URL, 1, 1)
+	fetcher.markSkipped(tab)
+	assert.True(t, tab.TestRuns[0].Skipped)
+	assert.False(t, tab.TestRuns[1].Skipped)
+	assert.False(t, tab.TestRuns[2].Skipped)
+
+	// the job config of the build is cached between fetches
+	tab.TestRuns[0].Skipped = false
+	fetcher.markSkipped(tab)
+	assert.True(t, tab.TestRuns[0].Skipped)
+	assert.Equal(t, 1, requests)
+}
+
+func TestInferSIGs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/pkg/kubelet/OWNERS" {
+			fmt.Fprint(w, "labels:\n- sig/node\n") // nolint
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	tab := &v1alpha1.DashboardTab{
+		TestRuns: []v1alpha1.TestResult{
+			{TestName: "k8s.io/kubernetes/pkg/kubelet/cm.TestCgroupName"},
+			{TestName: "[sig-network] DNS should resolve", Sig: "network"},
+			{TestName: "ci-kubernetes-unit.Overall"},
+		},
+	}
+	fetcher := NewFetcher(server.URL, 1, 1)
+	fetcher.SIGs = sig.NewResolver(sig.TagSource{}, sig.NewOwnersSource(server.URL))
+	fetcher.inferSIGs(tab)
+	assert.Equal(t, "node", tab.TestRuns[0].Sig)
+	assert.Equal(t, "network", tab.TestRuns[1].Sig)
+	assert.Empty(t, tab.TestRuns[2].Sig)
+}
+
+func TestAnalyze(t *testing.T) {
+	flaky := &v1alpha1.DashboardTab{TabState: v1alpha1.FLAKY_STATUS, TestRuns: []v1alpha1.TestResult{{TestName: "flake-1"}}}
+	failing := &v1alpha1.DashboardTab{TabState: v1alpha1.FAILING_STATUS, TestRuns: []v1alpha1.TestResult{{TestName: "fail-1"}, {TestName: "fail-2"}}}
+
+	var names []string
+	for _, finding := range NewAnalyzer().Analyze([]*v1alpha1.DashboardTab{flaky, failing}) {
+		names = append(names, finding.Test.TestName)
+	}
+	assert.Equal(t, []string{"fail-1", "fail-2", "flake-1"}, names)
+}
+
+func TestIssueReporter(t *testing.T) {
+	finding := &Finding{
+		Tab: &v1alpha1.DashboardTab{
+			BoardHash: dashboard + "#gce-cos-master-default",
+			TabState:  v1alpha1.FAILING_STATUS,
+		},
+		Test: &v1alpha1.TestResult{TestName: "[sig-node] Pods should run", ProwJobURL: "https://prow.k8s.io/view/gs/bucket/logs/job/1"},
+	}
+
+	report, err := NewIssueReporter().Report(finding)
+	assert.NoError(t, err)
+	assert.Equal(t, "[Failing Test] [sig-node] Pods should run", report.Title)
+	assert.Contains(t, report.Body, "* ["+dashboard+"#gce-cos-master-default]")
+	assert.NotContains(t, report.Body, "/priority")
+
+	finding.Logs = []Log{{Name: "test-stdout.txt", URL: "https://storage.googleapis.com/stdout", Content: "stdout content"}}
+	reporter := &IssueReporter{Phase: &ReleasePhase{Release: "1.35", Name: release.PhaseCodeFreeze}}
+	report, err = reporter.Report(finding)
+	assert.NoError(t, err)
+	assert.Contains(t, report.Body, "/priority critical-urgent")
+	assert.Contains(t, report.Body, "stdout content")
+
+	finding.Notes = []string{"waiting on #12345"}
+	report, err = reporter.Report(finding)
+	assert.NoError(t, err)
+	assert.Contains(t, report.Body, "* waiting on #12345\n")
+}
+
+func TestCorrelate(t *testing.T) {
+	failure := v1alpha1.TestResult{TestName: "[sig-node] Pods should run", Fingerprint: "abc"}
+	master := &v1alpha1.DashboardTab{BoardHash: "sig-release-master-blocking#gce", TabURL: "https://testgrid.k8s.io/master", TabState: v1alpha1.FAILING_STATUS, TestRuns: []v1alpha1.TestResult{failure}}
+	branch := &v1alpha1.DashboardTab{BoardHash: "sig-release-1.34-blocking#gce", TabState: v1alpha1.FAILING_STATUS, TestRuns: []v1alpha1.TestResult{failure}}
+
+	findings := NewAnalyzer().Analyze([]*v1alpha1.DashboardTab{branch})
+	Correlate([]*v1alpha1.DashboardTab{master, branch}, findings)
+	assert.Equal(t, []CrossBranch{{Branch: "master", BoardHash: "sig-release-master-blocking#gce", URL: "https://testgrid.k8s.io/master"}}, findings[0].CrossBranch)
+
+	report, err := NewIssueReporter().Report(findings[0])
+	assert.NoError(t, err)
+	assert.Contains(t, report.Body, "other branches, the failure also happens on master, fix it there and cherry-pick it:")
+}
+
+func TestGroupVariants(t *testing.T) {
+	failure := v1alpha1.TestResult{TestName: "[sig-node] Pods should run"}
+	arm64 := &v1alpha1.DashboardTab{BoardHash: "sig-release-master-informing#kind-master-arm64", TabState: v1alpha1.FAILING_STATUS, TestRuns: []v1alpha1.TestResult{failure}}
+	amd64 := &v1alpha1.DashboardTab{BoardHash: "sig-release-master-blocking#kind-master", TabURL: "https://testgrid.k8s.io/kind", TabState: v1alpha1.FLAKY_STATUS,
+		TestRuns: []v1alpha1.TestResult{{TestName: "[sig-network] DNS"}}}
+
+	findings := NewAnalyzer().Analyze([]*v1alpha1.DashboardTab{arm64})
+	GroupVariants([]*v1alpha1.DashboardTab{arm64, amd64}, findings)
+	assert.Equal(t, []Variant{{Variant: "default", BoardHash: "sig-release-master-blocking#kind-master", URL: "https://testgrid.k8s.io/kind"}}, findings[0].Variants)
+
+	report, err := NewIssueReporter().Report(findings[0])
+	assert.NoError(t, err)
+	assert.Contains(t, report.Body, "other platform variants, the test fails only on arm64, green on default:")
+}
+
+func TestSlackReporter(t *testing.T) {
+	finding := &Finding{
+		Tab:  &v1alpha1.DashboardTab{BoardHash: dashboard + "#kind-master", TabState: v1alpha1.FLAKY_STATUS, StateIcon: ":large_purple_square:"},
+		Test: &v1alpha1.TestResult{TestName: "[sig-network] DNS"},
+	}
+	report, err := NewSlackReporter().Report(finding)
+	assert.NoError(t, err)
+	assert.Empty(t, report.Title)
+	assert.Contains(t, report.Body, ":large_purple_square: Flaky on ["+dashboard+"#kind-master]")
+
+	reporter := &SlackReporter{Mentions: &SlackMentions{Boards: map[string][]string{dashboard: {"@release-ci-signal"}}}}
+	report, err = reporter.Report(finding)
+	assert.NoError(t, err)
+	assert.NotContains(t, report.Body, "@release-ci-signal")
+
+	finding.Tab.TabState = v1alpha1.FAILING_STATUS
+	report, err = reporter.Report(finding)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(report.Body, " @release-ci-signal"))
+}
+
+func TestReporterLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/ci-kubernetes-renamed/") {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	finding := &Finding{
+		Tab: &v1alpha1.DashboardTab{BoardHash: dashboard + "#kind-master", TabState: v1alpha1.FLAKY_STATUS, TabURL: server.URL + "/" + dashboard},
+		Test: &v1alpha1.TestResult{
+			TestName:   "[sig-network] DNS",
+			ProwJobURL: server.URL + "/view/gs/bucket/logs/ci-kubernetes-renamed/1",
+			TriageURL:  server.URL + "/index.html?job=ci-kubernetes-renamed$&test=DNS",
+		},
+	}
+	checker := NewLinkChecker()
+	report, err := (&IssueReporter{Links: checker}).Report(finding)
+	assert.NoError(t, err)
+	assert.Len(t, report.DeadLinks, 2)
+	assert.Contains(t, report.Body, "* ["+server.URL+"/index.html?test=DNS]")
+	assert.Equal(t, server.URL+"/view/gs/bucket/logs/ci-kubernetes-renamed/1", finding.Test.ProwJobURL)
+
+	report, err = (&SlackReporter{Links: checker}).Report(finding)
+	assert.NoError(t, err)
+	assert.Contains(t, report.Body, "[Triage]("+server.URL+"/index.html?test=DNS)")
+
+	report, err = NewSlackReporter().Report(finding)
+	assert.NoError(t, err)
+	assert.Empty(t, report.DeadLinks)
+	assert.Contains(t, report.Body, "[Triage]("+finding.Test.TriageURL+")")
+}
+
+type fakeProjectManager struct {
+	title, body, board string
+	pulls              []github.PullRequest
+	closed, open       *github.Issue
+	reopened, comment  string
+	closedID           string
+	commentedID        string
+	existing           []github.Issue
+	options            *github.IssueOptions
+}
+
+func (f *fakeProjectManager) FindClosedIssue(owner, repo, testName string, closedSince time.Time) (*github.Issue, error) {
+	if f.closed == nil || f.closed.ClosedAt.Before(closedSince) {
+		return nil, nil
+	}
+	return f.closed, nil
+}
+
+func (f *fakeProjectManager) ReopenIssue(issue *github.Issue, comment string) error {
+	f.reopened, f.comment = issue.ID, comment
+	return nil
+}
+
+func (f *fakeProjectManager) FindOpenIssue(owner, repo, testName string) (*github.Issue, error) {
+	return f.open, nil
+}
+
+func (f *fakeProjectManager) CountOpenIssues(owner, repo string, labels []string, milestone string) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeProjectManager) GetIssue(owner, repo string, number int) (*github.Issue, error) {
+	return nil, nil
+}
+
+func (f *fakeProjectManager) CommentIssue(issue *github.Issue, comment string) error {
+	f.commentedID, f.comment = issue.ID, comment
+	return nil
+}
+
+func (f *fakeProjectManager) CloseIssue(issue *github.Issue, comment string) error {
+	f.closedID, f.comment = issue.ID, comment
+	return nil
+}
+
+func (f *fakeProjectManager) FindExistingIssues(testName string) ([]github.Issue, error) {
+	return f.existing, nil
+}
+
+func (f *fakeProjectManager) ListBoardIssues() ([]github.BoardIssue, error) {
+	return nil, nil
+}
+
+func (f *fakeProjectManager) BootstrapBoard(release string, dryRun bool) ([]github.BoardChange, error) {
+	return nil, nil
+}
+
+func (f *fakeProjectManager) RateLimit() (*github.RateLimit, error) {
+	return nil, nil
+}
+
+func (f *fakeProjectManager) ListMergedPullRequests(owner, repo string, from, to time.Time) ([]github.PullRequest, error) {
+	return f.pulls, nil
+}
+
+func (f *fakeProjectManager) GetProjectFields() ([]github.ProjectFieldInfo, error) {
+	return nil, nil
+}
+
+func (f *fakeProjectManager) CreateDraftIssue(title, body, board string) error {
+	f.title, f.body, f.board = title, body, board
+	return nil
+}
+
+func (f *fakeProjectManager) CreateIssue(title, body, board string, options *github.IssueOptions) (*github.Issue, error) {
+	f.title, f.body, f.board, f.options = title, body, board, options
+	return &github.Issue{Number: 2, Title: title}, nil
+}
+
+func TestProjectCreator(t *testing.T) {
+	manager := &fakeProjectManager{}
+	finding := &Finding{Tab: &v1alpha1.DashboardTab{BoardHash: dashboard + "#kind-master"}}
+	err := (&ProjectCreator{manager: manager}).Create(finding, &Report{Title: "title", Body: "body"})
+	assert.NoError(t, err)
+	assert.Equal(t, "title", manager.title)
+	assert.Equal(t, "body", manager.body)
+	assert.Equal(t, dashboard+"#kind-master", manager.board)
+}
+
+func TestProjectCreatorFrontMatter(t *testing.T) {
+	manager := &fakeProjectManager{}
+	finding := &Finding{
+		Tab:  &v1alpha1.DashboardTab{BoardHash: dashboard + "#kind-master"},
+		Test: &v1alpha1.TestResult{TestName: "[sig-node] Pods should run", Sig: "node"},
+	}
+	report := &Report{Title: "title", Body: "body", FrontMatter: &FrontMatter{
+		Repo: "kubernetes/kubernetes", Labels: []string{"kind/flake", "sig/"}, Assignees: []string{"@octocat"},
+	}}
+	assert.NoError(t, (&ProjectCreator{manager: manager}).Create(finding, report))
+	assert.Equal(t, &github.IssueOptions{
+		Owner: "kubernetes", Repo: "kubernetes", Labels: []string{"kind/flake", "sig/node"}, Assignees: []string{"octocat"},
+	}, manager.options)
+	assert.Equal(t, "body", manager.body)
+}
+
+func TestProjectCreatorRepositoryIssues(t *testing.T) {
+	manager := &fakeProjectManager{}
+	finding := &Finding{
+		Tab:  &v1alpha1.DashboardTab{BoardHash: dashboard + "#gce-cos-master-default"},
+		Test: &v1alpha1.TestResult{TestName: "ci-kubernetes-e2e-gci-gce.Up"},
+	}
+	report := &Report{Title: "title", Body: "body", FrontMatter: &FrontMatter{Labels: []string{"kind/failing-test"}}}
+	creator := &ProjectCreator{manager: manager, RepositoryIssues: true}
+	assert.NoError(t, creator.Create(finding, report))
+	assert.Equal(t, &github.IssueOptions{Owner: "kubernetes", Repo: "test-infra", Labels: []string{"kind/failing-test"}}, manager.options)
+	assert.Empty(t, report.FrontMatter.Repo)
+}
+
+func TestProjectCreatorReopen(t *testing.T) {
+	finding := &Finding{
+		Tab:  &v1alpha1.DashboardTab{BoardHash: dashboard + "#kind-master"},
+		Test: &v1alpha1.TestResult{TestName: "[sig-node] Pods should run"},
+	}
+	closed := &github.Issue{ID: "I_1", Number: 1, ClosedAt: time.Now().Add(-48 * time.Hour)}
+
+	tests := []struct {
+		name         string
+		window       time.Duration
+		wantReopened string
+		wantDraft    string
+	}{
+		{name: "disabled", window: 0, wantDraft: "title"},
+		{name: "closed within the window", window: 7 * 24 * time.Hour, wantReopened: "I_1"},
+		{name: "closed before the window", window: 24 * time.Hour, wantDraft: "title"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := &fakeProjectManager{closed: closed}
+			creator := &ProjectCreator{manager: manager, ReopenWindow: tt.window}
+			assert.NoError(t, creator.Create(finding, &Report{Title: "title", Body: "body"}))
+			assert.Equal(t, tt.wantReopened, manager.reopened)
+			assert.Equal(t, tt.wantDraft, manager.title)
+			if tt.wantReopened != "" {
+				assert.Contains(t, manager.comment, "body")
+			}
+		})
+	}
+}
+
+func TestFindCulprits(t *testing.T) {
+	manager := &fakeProjectManager{pulls: []github.PullRequest{
+		{Number: 1, Title: "docs", Files: []string{"README.md"}},
+		{Number: 2, Title: "kubelet", URL: "https://github.com/kubernetes/kubernetes/pull/2", Labels: []string{"sig/node"}},
+	}}
+	finding := &Finding{Test: &v1alpha1.TestResult{TestName: "[sig-node] Pods should run"}}
+	_, err := findCulprits(manager, finding, 3)
+	assert.Error(t, err)
+
+	finding.Test.LastPassTimestamp, finding.Test.FirstFailTimestamp = 1000, 2000
+	culprits, err := findCulprits(manager, finding, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []Culprit{{Number: 2, Title: "kubelet", URL: "https://github.com/kubernetes/kubernetes/pull/2"}}, culprits)
+}
+
+func TestProjectResolver(t *testing.T) {
+	finding := &Finding{
+		Tab: &v1alpha1.DashboardTab{BoardHash: dashboard + "#kind-master", TabURL: "https://testgrid.k8s.io/kind-master", TabState: v1alpha1.FAILING_STATUS},
+		Test: &v1alpha1.TestResult{
+			TestName:               "[sig-node] Pods should run",
+			LastFailureTimestamp:   3000,
+			RecoveredFromTimestamp: 1000,
+			RecoveredTimestamp:     4000,
+		},
+	}
+	manager := &fakeProjectManager{
+		open:  &github.Issue{ID: "I_1", Number: 1},
+		pulls: []github.PullRequest{{Number: 2, Title: "kubelet", URL: "https://github.com/kubernetes/kubernetes/pull/2", Labels: []string{"sig/node"}}},
+	}
+	resolver := &ProjectResolver{manager: manager, FixLimit: 3}
+
+	closed, err := resolver.Resolve(finding)
+	assert.NoError(t, err)
+	assert.Equal(t, manager.open, closed)
+	assert.Equal(t, "I_1", manager.closedID)
+	assert.Contains(t, manager.comment, "is passing again on [sig-release-master-blocking#kind-master]")
+	assert.Contains(t, manager.comment, "* [#2](https://github.com/kubernetes/kubernetes/pull/2) kubelet\n")
+
+	manager.closedID = ""
+	finding.Test.Skipped = true
+	closed, err = resolver.Resolve(finding)
+	assert.NoError(t, err)
+	assert.Nil(t, closed)
+	assert.Empty(t, manager.closedID)
+
+	finding.Test.Skipped = false
+	manager.open = nil
+	closed, err = resolver.Resolve(finding)
+	assert.NoError(t, err)
+	assert.Nil(t, closed)
+}
+
+func TestProjectResolverGreenRuns(t *testing.T) {
+	finding := &Finding{
+		Tab: &v1alpha1.DashboardTab{BoardHash: dashboard + "#kind-master", TabState: v1alpha1.FAILING_STATUS},
+		Test: &v1alpha1.TestResult{
+			TestName:           "[sig-node] Pods should run",
+			RecoveredTimestamp: 4000,
+			Runs:               []v1alpha1.RunResult{{Timestamp: 5000}, {Timestamp: 4000}, {Timestamp: 3000, Failed: true}},
+		},
+	}
+	assert.Equal(t, 2, finding.GreenRuns())
+
+	manager := &fakeProjectManager{open: &github.Issue{ID: "I_1", Number: 1}}
+	resolver := &ProjectResolver{manager: manager, GreenRuns: 3, CommentOnly: true}
+	commented, err := resolver.Resolve(finding)
+	assert.NoError(t, err)
+	assert.Nil(t, commented)
+	assert.Empty(t, manager.commentedID)
+
+	finding.Test.Runs = append([]v1alpha1.RunResult{{Timestamp: 6000}}, finding.Test.Runs...)
+	commented, err = resolver.Resolve(finding)
+	assert.NoError(t, err)
+	assert.Equal(t, manager.open, commented)
+	assert.Equal(t, "I_1", manager.commentedID)
+	assert.Empty(t, manager.closedID)
+	assert.Contains(t, manager.comment, ", green for the last 3 runs, consider closing the issue.")
+
+	// the issue is commented once
+	manager.commentedID = ""
+	commented, err = resolver.Resolve(finding)
+	assert.NoError(t, err)
+	assert.Nil(t, commented)
+	assert.Empty(t, manager.commentedID)
+}
+
+func TestProjectAuditor(t *testing.T) {
+	since := time.Now().Add(-30 * 24 * time.Hour)
+	manager := &fakeProjectManager{}
+	auditor := &ProjectAuditor{manager: manager}
+
+	filed, err := auditor.Filed("[sig-node] Pods should run", dashboard+"#kind-master", since)
+	assert.NoError(t, err)
+	assert.Nil(t, filed)
+
+	manager.closed = &github.Issue{Number: 1, ClosedAt: time.Now().Add(-24 * time.Hour)}
+	filed, err = auditor.Filed("[sig-node] Pods should run", dashboard+"#kind-master", since)
+	assert.NoError(t, err)
+	assert.Equal(t, manager.closed, filed)
+
+	// an issue closed before the audit period is a miss
+	manager.closed.ClosedAt = since.Add(-time.Hour)
+	filed, err = auditor.Filed("[sig-node] Pods should run", dashboard+"#kind-master", since)
+	assert.NoError(t, err)
+	assert.Nil(t, filed)
+
+	manager.existing = []github.Issue{{ID: "PVTI_1", Title: "[Failing Test] Pods should run", Draft: true}}
+	filed, err = auditor.Filed("[sig-node] Pods should run", dashboard+"#kind-master", since)
+	assert.NoError(t, err)
+	assert.Equal(t, "PVTI_1", filed.ID)
+
+	// the issue linked to the test tracks it
+	auditor.State = &state.State{}
+	_, err = auditor.State.LinkIssue("[sig-node] Pods should run", "https://github.com/kubernetes/kubernetes/issues/129000", "", "alice")
+	assert.NoError(t, err)
+	filed, err = auditor.Filed("[sig-node] Pods should run", dashboard+"#kind-master", since)
+	assert.NoError(t, err)
+	assert.Equal(t, 129000, filed.Number)
+}
+
+func TestSnapshot(t *testing.T) {
+	tabs := []*v1alpha1.DashboardTab{
+		{BoardHash: dashboard + "#kind-master", TabURL: "https://testgrid.k8s.io/kind", TabState: v1alpha1.FLAKY_STATUS,
+			TestRuns: []v1alpha1.TestResult{{TestName: "[sig-network] DNS", Sig: "network", FlakeScore: 35, ScoreRuns: 140}}},
+		{BoardHash: dashboard + "#gce-cos-master-default", TabURL: "https://testgrid.k8s.io/gce", TabState: v1alpha1.FAILING_STATUS,
+			TestRuns: []v1alpha1.TestResult{{TestName: "[sig-node] Pods should run", ProwJobURL: "https://prow.k8s.io/1"}}},
+	}
+	generatedAt := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	snapshot := NewSnapshot([]string{dashboard}, tabs, generatedAt)
+	assert.Equal(t, 1, snapshot.FailingTabs)
+	assert.Equal(t, 1, snapshot.FlakyTabs)
+
+	var output strings.Builder
+	assert.NoError(t, snapshot.Write(&output, FormatJSON))
+	var decoded Snapshot
+	assert.NoError(t, json.Unmarshal([]byte(output.String()), &decoded))
+	assert.Equal(t, generatedAt, decoded.GeneratedAt)
+	assert.Equal(t, "[sig-network] DNS", decoded.Tabs[0].TestRuns[0].TestName)
+	assert.Equal(t, snapshot.Health, decoded.Health)
+
+	output.Reset()
+	assert.NoError(t, snapshot.Write(&output, FormatYAML))
+	assert.Contains(t, output.String(), "failing_tabs: 1\n")
+	assert.Contains(t, output.String(), "    test_name: '[sig-network] DNS'\n")
+
+	output.Reset()
+	assert.NoError(t, snapshot.Write(&output, FormatMarkdown))
+	markdown := output.String()
+	assert.Contains(t, markdown, "from "+dashboard+": 1 failing and 1 flaky tabs.\n")
+	assert.Contains(t, markdown, "## Health score\n\n* master: 73/100 (1 failing and 1 flaky tabs, 0 critical issues, 35% mean flake rate)\n")
+	assert.Less(t, strings.Index(markdown, "## Failing"), strings.Index(markdown, "## Flaky"))
+	assert.Contains(t, markdown, "### ["+dashboard+"#kind-master](https://testgrid.k8s.io/kind)\n\n"+
+		"* `[sig-network] DNS` [Prow](), [Triage](), last failure on "+issue.TimeClean(0)+", sig/network, flake score 35% of 140 runs\n")
+
+	assert.Error(t, snapshot.Write(&output, "csv"))
+
+	output.Reset()
+	assert.NoError(t, NewSnapshot([]string{dashboard}, nil, generatedAt).Write(&output, FormatJSON))
+	assert.Contains(t, output.String(), `"tabs": []`)
+
+	assert.NoError(t, snapshot.CountCriticalIssues(&fake.ProjectManager{OpenCounts: map[string]int{"": 3}}))
+	assert.Equal(t, 3, snapshot.Health[0].CriticalIssues)
+	assert.Equal(t, 58, snapshot.Health[0].Score)
+}
+
+func TestHealthScores(t *testing.T) {
+	informing := "sig-release-master-informing"
+	dashboards := []string{dashboard, informing, "sig-release-1.32-blocking", "google-gke"}
+	assert.Equal(t, []string{"master", "1.32"}, Releases(dashboards))
+
+	tabs := []*v1alpha1.DashboardTab{
+		{BoardHash: dashboard + "#gce-cos-master-default", TabState: v1alpha1.FAILING_STATUS,
+			TestRuns: []v1alpha1.TestResult{{TestName: "[sig-node] Pods should run"}}},
+		{BoardHash: dashboard + "#kind-master", TabState: v1alpha1.FLAKY_STATUS,
+			TestRuns: []v1alpha1.TestResult{{TestName: "[sig-network] DNS", FlakeScore: 30, ScoreRuns: 100}}},
+		{BoardHash: informing + "#kind-ipv6-master", TabState: v1alpha1.FAILING_STATUS,
+			TestRuns: []v1alpha1.TestResult{{TestName: "[sig-network] Services", FlakeScore: 10, ScoreRuns: 50}}},
+		{BoardHash: informing + "#capz-windows-master", TabState: v1alpha1.FLAKY_STATUS},
+	}
+	manager := &fake.ProjectManager{OpenCounts: map[string]int{"": 2, "v1.32": 1}}
+	criticalIssues, err := CountCriticalIssues(manager, Releases(dashboards))
+	assert.NoError(t, err)
+	scores := HealthScores(dashboards, tabs, criticalIssues)
+	// 100 - (15+5+5+2) for the boards - 2*5 for the critical issues - 20% of 20 for the flake rate
+	assert.Equal(t, []HealthScore{
+		{Release: "master", Score: 59, FailingTabs: 2, FlakyTabs: 2, CriticalIssues: 2, FlakeRate: 20},
+		{Release: "1.32", Score: 95, CriticalIssues: 1},
+	}, scores)
+	assert.Equal(t, "master: 59/100 (2 failing and 2 flaky tabs, 2 critical issues, 20% mean flake rate)", scores[0].String())
+
+	// every source is capped
+	tabs = nil
+	for i := 0; i < 5; i++ {
+		tabs = append(tabs, &v1alpha1.DashboardTab{BoardHash: fmt.Sprintf("%s#tab-%d", dashboard, i), TabState: v1alpha1.FAILING_STATUS,
+			TestRuns: []v1alpha1.TestResult{{TestName: "[sig-node] Pods should run", FlakeScore: 100, ScoreRuns: 10}}})
+	}
+	scores = HealthScores([]string{dashboard}, tabs, map[string]int{"master": 10})
+	assert.Equal(t, 0, scores[0].Score)
+	scores = HealthScores([]string{dashboard}, tabs, nil)
+	assert.Equal(t, 20, scores[0].Score)
+
+	manager.Err = errors.New("rate limited")
+	_, err = CountCriticalIssues(manager, []string{"master"})
+	assert.ErrorContains(t, err, "rate limited")
+}
+
+func TestCompareBranches(t *testing.T) {
+	tabs := []*v1alpha1.DashboardTab{
+		{BoardHash: dashboard + "#gce-cos-master-default", TestRuns: []v1alpha1.TestResult{
+			{TestName: "[sig-node] Pods should run"}, {TestName: "[sig-network] DNS"}}},
+		{BoardHash: dashboard + "#kind-master", TestRuns: []v1alpha1.TestResult{{TestName: "[sig-node] Pods should run"}}},
+		{BoardHash: "sig-release-1.35-blocking#gce-cos-k8sbeta-default", TestRuns: []v1alpha1.TestResult{
+			{TestName: "[sig-network] DNS"}, {TestName: "[sig-storage] CSI"}}},
+		{BoardHash: "sig-release-1.34-blocking#gce-cos-k8sstable1-default", TestRuns: []v1alpha1.TestResult{{TestName: "[sig-apps] Deployment"}}},
+	}
+	diff := CompareBranches("1.35", tabs)
+	assert.Equal(t, []BranchFailure{{TestName: "[sig-storage] CSI", BranchTabs: []string{"sig-release-1.35-blocking#gce-cos-k8sbeta-default"}}}, diff.OnlyBranch)
+	assert.Equal(t, []BranchFailure{{TestName: "[sig-node] Pods should run",
+		MasterTabs: []string{dashboard + "#gce-cos-master-default", dashboard + "#kind-master"}}}, diff.OnlyMaster)
+	assert.Equal(t, []BranchFailure{{TestName: "[sig-network] DNS", MasterTabs: []string{dashboard + "#gce-cos-master-default"},
+		BranchTabs: []string{"sig-release-1.35-blocking#gce-cos-k8sbeta-default"}}}, diff.Both)
+
+	markdown := diff.Markdown()
+	assert.Contains(t, markdown, "## Only on 1.35 (cherry-pick gaps)\n\n* `[sig-storage] CSI` (sig-release-1.35-blocking#gce-cos-k8sbeta-default)\n")
+	assert.Contains(t, markdown, "## On both\n\n* `[sig-network] DNS` (sig-release-1.35-blocking#gce-cos-k8sbeta-default, sig-release-master-blocking#gce-cos-master-default)\n")
+	assert.Equal(t, "# master vs 1.33 signal\n\n## Only on 1.33 (cherry-pick gaps)\n\nNone.\n\n## Only on master (not yet branched)\n\nNone.\n\n## On both\n\nNone.\n",
+		CompareBranches("1.33", nil).Markdown())
+}
+
+func TestLintBoard(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tab := &v1alpha1.DashboardTab{BoardHash: dashboard + "#gce", TabURL: "https://testgrid.k8s.io/" + dashboard + "#gce"}
+	test := &v1alpha1.TestResult{TestName: "[sig-node] Pods should run", ProwJobURL: "https://prow.k8s.io/view/gs/1", ErrorMessage: "timeout"}
+	rendered, err := issue.RenderTemplate(issue.NewTemplate(tab, test), issue.FailureTemplate)
+	assert.NoError(t, err)
+	_, body, err := issue.ParseFrontMatter(rendered.String())
+	assert.NoError(t, err)
+
+	issues := []github.BoardIssue{
+		// an issue filed with the template is clean
+		{Issue: github.Issue{Number: 1, Title: "[Failing Test] [sig-node] Pods should run", URL: "https://github.com/kubernetes/kubernetes/issues/1"},
+			Body: body, Labels: []string{"kind/failing-test", "sig/node"}, LastActivity: now.Add(-24 * time.Hour)},
+		{Issue: github.Issue{Number: 2, Title: "DNS is broken", URL: "https://github.com/kubernetes/kubernetes/issues/2", Status: "Observing"},
+			Body: "See https://testgrid.k8s.io/sig-network\n\n### Reason for failure\n\n```\n```\n", LastActivity: now.Add(-10 * 24 * time.Hour)},
+		{Issue: github.Issue{Title: "[Flaking Test] Probes", Draft: true}, Body: strings.Replace(body, "timeout", "", 1), LastActivity: now},
+	}
+	lint := LintBoard(issues, now, 7*24*time.Hour)
+	assert.Equal(t, 3, lint.Linted)
+	if assert.Len(t, lint.Issues, 2) {
+		assert.Equal(t, 14, lint.Issues[0].Score)
+		assert.Equal(t, []string{"missing Prow link", "missing SIG", "missing error excerpt", "no activity for 10 days",
+			"missing kind/ label", "missing sig/ label"}, lint.Issues[0].Problems)
+		assert.Equal(t, 80, lint.Issues[1].Score)
+	}
+	assert.Equal(t, `# Board lint, 3 issues, 2 to fix
+
+* 14% [#2 DNS is broken](https://github.com/kubernetes/kubernetes/issues/2), Observing: missing Prow link; missing SIG; missing error excerpt; no activity for 10 days; missing kind/ label; missing sig/ label
+* 80% draft [Flaking Test] Probes: missing error excerpt
+`, lint.Markdown())
+}
+
+func TestKnownIssues(t *testing.T) {
+	tabs := []*v1alpha1.DashboardTab{
+		{BoardHash: dashboard + "#gce", TabState: v1alpha1.FAILING_STATUS, TestRuns: []v1alpha1.TestResult{
+			{TestName: "[sig-node] Pods should run", Sig: "node"},
+			{TestName: "[sig-cluster-lifecycle] Upgrade", Sig: "cluster-lifecycle"},
+			{TestName: "[sig-apps] Deployment", Sig: "apps", RecoveredTimestamp: 1},
+			{TestName: "[sig-storage] CSI", Sig: "storage"}}},
+		{BoardHash: dashboard + "#kind", TabState: v1alpha1.FAILING_STATUS, TestRuns: []v1alpha1.TestResult{
+			{TestName: "[sig-node] Pods should run", Sig: "node"}}},
+		{BoardHash: dashboard + "#ec2", TabState: v1alpha1.FLAKY_STATUS, TestRuns: []v1alpha1.TestResult{
+			{TestName: "[sig-network] DNS", Sig: "network"}}},
+	}
+	issues := []github.BoardIssue{
+		{Issue: github.Issue{Number: 12, URL: "https://github.com/kubernetes/kubernetes/issues/12", TestName: "[sig-node] Pods should run"}},
+		{Issue: github.Issue{Title: "[Failing Test] [sig-cluster-lifecycle] Upgrade", Draft: true}},
+		{Issue: github.Issue{Number: 13, Title: "[Flaking Test] [sig-network] DNS"}},
+	}
+	known := KnownIssues(tabs, issues)
+	if assert.Len(t, known, 3) {
+		assert.Equal(t, "[sig-cluster-lifecycle] Upgrade", known[0].TestName)
+		assert.Equal(t, []string{dashboard + "#gce", dashboard + "#kind"}, known[1].Tabs)
+		assert.Nil(t, known[2].Issue)
+	}
+	assert.Equal(t, "## Known Issues (CI) in 1.35\n\n"+
+		"- The `[sig-cluster-lifecycle] Upgrade` test fails on sig-release-master-blocking#gce, tracked in the draft issue \"[Failing Test] [sig-cluster-lifecycle] Upgrade\" of the project board. [SIG Cluster Lifecycle]\n"+
+		"- The `[sig-node] Pods should run` test fails on sig-release-master-blocking#gce, sig-release-master-blocking#kind, tracked in [#12](https://github.com/kubernetes/kubernetes/issues/12). [SIG Node]\n"+
+		"\n### Failing tests without issue\n\n"+
+		"- `[sig-storage] CSI` on sig-release-master-blocking#gce [SIG Storage]\n", KnownIssuesMarkdown("1.35", known))
+	assert.Equal(t, "## Known Issues (CI)\n\nNone.\n", KnownIssuesMarkdown("", nil))
+}
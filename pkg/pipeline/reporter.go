@@ -0,0 +1,109 @@
+package pipeline
+
+import (
+	"strings"
+	"time"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/fingerprint"
+	"sigs.k8s.io/signalhound/internal/issue"
+	"sigs.k8s.io/signalhound/internal/links"
+	"sigs.k8s.io/signalhound/internal/platform"
+)
+
+// IssueReporter renders findings with the GitHub issue templates.
+type IssueReporter struct {
+	// Phase raises the issue priority during freeze, nil leaves it unset.
+	Phase *ReleasePhase
+
+	// Links replaces the dead links of the issue, nil leaves them unchecked.
+	Links *LinkChecker
+}
+
+// NewIssueReporter returns a Reporter rendering GitHub issues.
+func NewIssueReporter() *IssueReporter {
+	return &IssueReporter{}
+}
+
+// Report renders the failure or flake issue template, picked by the tab state,
+// the template front matter is moved from the body to Report.FrontMatter.
+func (r *IssueReporter) Report(finding *Finding) (*Report, error) {
+	tab, test, deadLinks := checkLinks(r.Links, finding)
+	issueTemplate := issue.NewTemplate(tab, test)
+	issueTemplate.Logs = finding.Logs
+	issueTemplate.Culprits = finding.Culprits
+	issueTemplate.Details = finding.Details
+	issueTemplate.Notes = finding.Notes
+	issueTemplate.CrossBranch = finding.CrossBranch
+	issueTemplate.CrossBranchHint = crossBranchHint(finding.Tab, finding.CrossBranch)
+	issueTemplate.Variants = finding.Variants
+	issueTemplate.VariantsHint = variantsHint(finding.Tab, finding.Variants)
+	state := issue.TestState(finding.Tab, finding.Test)
+	issueTemplate.Priority = r.Phase.Priority(state == v1alpha1.FAILING_STATUS)
+
+	templateFile, prefixTitle := issue.PickTemplate(state)
+	rendered, err := issue.RenderTemplate(issueTemplate, templateFile)
+	if err != nil {
+		return nil, err
+	}
+	frontMatter, body, err := issue.ParseFrontMatter(rendered.String())
+	if err != nil {
+		return nil, err
+	}
+	return &Report{
+		Title:       issue.Title(prefixTitle, finding.Test.TestName),
+		Body:        strings.TrimRight(body, "\r\n"),
+		FrontMatter: frontMatter,
+		DeadLinks:   deadLinks,
+	}, nil
+}
+
+// checkLinks returns the tab and the test of the finding with their dead
+// links replaced, unchanged without checker.
+func checkLinks(checker *LinkChecker, finding *Finding) (*v1alpha1.DashboardTab, *v1alpha1.TestResult, []DeadLink) {
+	if checker == nil {
+		return finding.Tab, finding.Test, nil
+	}
+	resolved, deadLinks := checker.Resolve(links.For(finding.Tab, finding.Test))
+	tab, test := resolved.Apply(finding.Tab, finding.Test)
+	return tab, test, deadLinks
+}
+
+// crossBranchHint explains the cherry-pick needs of the cross-branch boards.
+func crossBranchHint(tab *v1alpha1.DashboardTab, boards []CrossBranch) string {
+	var branches []string
+	for _, board := range boards {
+		branches = append(branches, board.Branch)
+	}
+	return fingerprint.Hint(fingerprint.Branch(tab.BoardHash), branches)
+}
+
+// variantsHint names the platform variants the test of the tab fails on.
+func variantsHint(tab *v1alpha1.DashboardTab, variants []Variant) string {
+	occurrences := make([]platform.Occurrence, 0, len(variants))
+	for _, variant := range variants {
+		occurrences = append(occurrences, platform.Occurrence{Variant: variant.Variant, Failing: variant.Failing})
+	}
+	return platform.Hint(platform.Variant(tab), occurrences)
+}
+
+// SlackReporter renders findings as #release-ci-signal Slack messages.
+type SlackReporter struct {
+	// Mentions are appended to the messages of failing tests, nil disables them.
+	Mentions *SlackMentions
+
+	// Links replaces the dead links of the message, nil leaves them unchecked.
+	Links *LinkChecker
+}
+
+// NewSlackReporter returns a Reporter rendering Slack messages.
+func NewSlackReporter() *SlackReporter {
+	return &SlackReporter{}
+}
+
+// Report renders the Slack message, the report has no title.
+func (r *SlackReporter) Report(finding *Finding) (*Report, error) {
+	tab, test, deadLinks := checkLinks(r.Links, finding)
+	message := issue.SlackMessage(tab, test)
+	return &Report{Body: issue.WithMentions(message, r.Mentions.For(finding.Tab, finding.Test, time.Now())), DeadLinks: deadLinks}, nil
+}
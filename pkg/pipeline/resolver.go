@@ -0,0 +1,102 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+
+	"sigs.k8s.io/signalhound/internal/culprit"
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/issue"
+)
+
+// defaultFixLimit is the number of likely fixing pull requests listed in the
+// resolution summary.
+const defaultFixLimit = 3
+
+// ProjectResolver closes the open issue of recovered findings with a comment
+// summarizing the failure, so the thread documents the incident end to end.
+// Draft issues of the project board have no thread and are left as they are.
+// With CommentOnly the issue is left open with a comment suggesting to close
+// it, posted once per issue by the resolver.
+type ProjectResolver struct {
+	manager github.ProjectManagerInterface
+
+	// Owner and Repo are the repository the issues are searched in.
+	Owner string
+	Repo  string
+
+	// FixLimit is the number of likely fixing pull requests listed in the
+	// summary, zero skips the search.
+	FixLimit int
+
+	// GreenRuns is the number of consecutive green runs a recovered test
+	// needs before its issue is resolved, zero resolves on the first one.
+	GreenRuns int
+
+	// CommentOnly comments on the issue instead of closing it.
+	CommentOnly bool
+
+	mu        sync.Mutex
+	commented map[string]bool // issue IDs already commented by CommentOnly
+}
+
+// NewProjectResolver returns a Resolver of the kubernetes/kubernetes issues
+// authenticated with the GitHub token.
+func NewProjectResolver(ctx context.Context, token string) *ProjectResolver {
+	return &ProjectResolver{
+		manager:  github.NewProjectManager(ctx, token),
+		Owner:    culprit.DefaultOwner,
+		Repo:     culprit.DefaultRepository,
+		FixLimit: defaultFixLimit,
+	}
+}
+
+// Resolve comments the resolution summary on the open issue of the finding
+// recovered for GreenRuns runs and closes it, unless CommentOnly. The likely
+// fixes are best effort, the summary is posted without them when the search
+// fails.
+func (r *ProjectResolver) Resolve(finding *Finding) (*Issue, error) {
+	if !finding.Recovered() {
+		return nil, nil
+	}
+	greenRuns := finding.GreenRuns()
+	if greenRuns < r.GreenRuns {
+		return nil, nil
+	}
+	open, err := r.manager.FindOpenIssue(r.Owner, r.Repo, finding.Test.TestName)
+	if err != nil || open == nil || r.wasCommented(open) {
+		return nil, err
+	}
+
+	resolution := issue.NewResolution(finding.Tab, finding.Test)
+	resolution.GreenRuns, resolution.KeepOpen = greenRuns, r.CommentOnly
+	if r.FixLimit > 0 {
+		if fixes, err := findFixes(r.manager, finding, r.FixLimit); err == nil {
+			resolution.Fixes = fixes
+		}
+	}
+	comment, err := issue.RenderResolution(resolution)
+	if err != nil {
+		return nil, err
+	}
+	if !r.CommentOnly {
+		return open, r.manager.CloseIssue(open, comment)
+	}
+	if err := r.manager.CommentIssue(open, comment); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.commented == nil {
+		r.commented = map[string]bool{}
+	}
+	r.commented[open.ID] = true
+	return open, nil
+}
+
+// wasCommented returns true when CommentOnly already commented on the issue.
+func (r *ProjectResolver) wasCommented(open *Issue) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.CommentOnly && r.commented[open.ID]
+}
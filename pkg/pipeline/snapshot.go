@@ -0,0 +1,140 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/signalhound/api/v1alpha1"
+	"sigs.k8s.io/signalhound/internal/github"
+	"sigs.k8s.io/signalhound/internal/issue"
+)
+
+// Output formats of a snapshot, see Snapshot.Write.
+const (
+	FormatJSON     = "json"
+	FormatYAML     = "yaml"
+	FormatMarkdown = "markdown"
+)
+
+// Formats lists the output formats of a snapshot.
+var Formats = []string{FormatJSON, FormatYAML, FormatMarkdown}
+
+// Snapshot is the result of a fetch of the dashboards, written by the report
+// command for cron jobs and CI pipelines.
+type Snapshot struct {
+	GeneratedAt time.Time                `json:"generated_at"`
+	Dashboards  []string                 `json:"dashboards"`
+	FailingTabs int                      `json:"failing_tabs"`
+	FlakyTabs   int                      `json:"flaky_tabs"`
+	Tabs        []*v1alpha1.DashboardTab `json:"tabs"`
+
+	// Health is the health score of every release of the dashboards, see
+	// HealthScores, without the critical issues until CountCriticalIssues.
+	Health []HealthScore `json:"health,omitempty"`
+}
+
+// NewSnapshot returns the snapshot of the tabs fetched from the dashboards.
+func NewSnapshot(dashboards []string, tabs []*v1alpha1.DashboardTab, generatedAt time.Time) *Snapshot {
+	snapshot := &Snapshot{GeneratedAt: generatedAt.UTC(), Dashboards: dashboards, Tabs: tabs}
+	if snapshot.Tabs == nil {
+		snapshot.Tabs = []*v1alpha1.DashboardTab{}
+	}
+	for _, tab := range tabs {
+		switch tab.TabState {
+		case v1alpha1.FAILING_STATUS:
+			snapshot.FailingTabs++
+		case v1alpha1.FLAKY_STATUS:
+			snapshot.FlakyTabs++
+		}
+	}
+	snapshot.Health = HealthScores(dashboards, tabs, nil)
+	return snapshot
+}
+
+// CountCriticalIssues adds the open critical issues of the releases to the
+// health scores.
+func (s *Snapshot) CountCriticalIssues(manager github.ProjectManagerInterface) error {
+	criticalIssues, err := CountCriticalIssues(manager, Releases(s.Dashboards))
+	if err != nil {
+		return err
+	}
+	s.Health = HealthScores(s.Dashboards, s.Tabs, criticalIssues)
+	return nil
+}
+
+// Write writes the snapshot in the format, one of Formats.
+func (s *Snapshot) Write(w io.Writer, format string) error {
+	var (
+		output []byte
+		err    error
+	)
+	switch format {
+	case FormatJSON:
+		output, err = json.MarshalIndent(s, "", "  ")
+		output = append(output, '\n')
+	case FormatYAML:
+		output, err = yaml.Marshal(s)
+	case FormatMarkdown:
+		output = []byte(s.markdown())
+	default:
+		return fmt.Errorf("unknown output format %q, use one of %s", format, strings.Join(Formats, ", "))
+	}
+	if err != nil {
+		return fmt.Errorf("error encoding the report: %v", err)
+	}
+	_, err = w.Write(output)
+	return err
+}
+
+// markdown returns the summary of the snapshot, the failing tabs first, with
+// a line per test in the format of the Slack messages.
+func (s *Snapshot) markdown() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "# CI Signal report\n\nGenerated on %s from %s: %d failing and %d flaky tabs.\n", // nolint
+		s.GeneratedAt.Format(time.RFC1123), strings.Join(s.Dashboards, ", "), s.FailingTabs, s.FlakyTabs)
+	if len(s.Health) > 0 {
+		builder.WriteString("\n## Health score\n\n")
+		for _, health := range s.Health {
+			builder.WriteString("* " + health.String() + "\n")
+		}
+	}
+	for _, section := range []struct {
+		title string
+		state string
+	}{
+		{"Failing", v1alpha1.FAILING_STATUS},
+		{"Flaky", v1alpha1.FLAKY_STATUS},
+	} {
+		var tabs []*v1alpha1.DashboardTab
+		for _, tab := range s.Tabs {
+			if tab.TabState == section.state {
+				tabs = append(tabs, tab)
+			}
+		}
+		if len(tabs) == 0 {
+			continue
+		}
+		fmt.Fprintf(&builder, "\n## %s\n", section.title) // nolint
+		for _, tab := range tabs {
+			fmt.Fprintf(&builder, "\n### [%s](%s)\n\n", tab.BoardHash, tab.TabURL) // nolint
+			for i := range tab.TestRuns {
+				test := &tab.TestRuns[i]
+				fmt.Fprintf(&builder, "* `%s` [Prow](%s), [Triage](%s), last failure on %s", // nolint
+					test.TestName, test.ProwJobURL, test.TriageURL, issue.TimeClean(test.LatestTimestamp))
+				if test.Sig != "" {
+					fmt.Fprintf(&builder, ", sig/%s", test.Sig) // nolint
+				}
+				if score := issue.FlakeScore(test); score != "" {
+					builder.WriteString(", flake score " + score)
+				}
+				builder.WriteString("\n")
+			}
+		}
+	}
+	return builder.String()
+}